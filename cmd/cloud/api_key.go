@@ -0,0 +1,205 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"os"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	apiKeyCmd.PersistentFlags().String("server", defaultLocalServerAPI, "The provisioning server whose API will be queried.")
+
+	apiKeyCreateCmd.Flags().String("name", "", "A human readable name of the API key.")
+	apiKeyCreateCmd.Flags().String("scope", model.APIKeyScopeReadOnly, "The scope of the API key. Supported values: admin, read-only, installations.")
+	apiKeyCreateCmd.Flags().String("owner", "", "When set, restricts the API key to installations of the given owner.")
+	apiKeyCreateCmd.MarkFlagRequired("name")
+
+	apiKeyGetCmd.Flags().String("apikey", "", "The id of the API key to be fetched.")
+	apiKeyGetCmd.MarkFlagRequired("apikey")
+
+	apiKeyListCmd.Flags().String("owner", "", "The owner by which to filter API keys.")
+	apiKeyListCmd.Flags().Bool("table", false, "Whether to display the returned API key list in a table or not")
+	registerPagingFlags(apiKeyListCmd)
+
+	apiKeyDeleteCmd.Flags().String("apikey", "", "The id of the API key to be deleted.")
+	apiKeyDeleteCmd.MarkFlagRequired("apikey")
+
+	apiKeyBootstrapCmd.Flags().String("name", "bootstrap", "A human readable name of the API key.")
+	apiKeyBootstrapCmd.Flags().String("database", "sqlite://cloud.db", "The database backing the provisioning server.")
+
+	apiKeyCmd.AddCommand(apiKeyCreateCmd)
+	apiKeyCmd.AddCommand(apiKeyGetCmd)
+	apiKeyCmd.AddCommand(apiKeyListCmd)
+	apiKeyCmd.AddCommand(apiKeyDeleteCmd)
+	apiKeyCmd.AddCommand(apiKeyBootstrapCmd)
+}
+
+var apiKeyCmd = &cobra.Command{
+	Use:   "apikey",
+	Short: "Manipulate API keys used to authenticate with the provisioning server.",
+}
+
+var apiKeyCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an API key.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		name, _ := command.Flags().GetString("name")
+		scope, _ := command.Flags().GetString("scope")
+		ownerID, _ := command.Flags().GetString("owner")
+
+		apiKey, err := client.CreateAPIKey(&model.CreateAPIKeyRequest{
+			Name:    name,
+			Scope:   scope,
+			OwnerID: ownerID,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to create API key")
+		}
+
+		err = printJSON(apiKey)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	},
+}
+
+var apiKeyGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Get a particular API key.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		apiKeyID, _ := command.Flags().GetString("apikey")
+		apiKey, err := client.GetAPIKey(apiKeyID)
+		if err != nil {
+			return errors.Wrap(err, "failed to query API key")
+		}
+		if apiKey == nil {
+			return nil
+		}
+
+		err = printJSON(apiKey)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	},
+}
+
+var apiKeyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List created API keys.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		owner, _ := command.Flags().GetString("owner")
+		paging := parsePagingFlags(command)
+		apiKeys, err := client.GetAPIKeys(&model.GetAPIKeysRequest{
+			OwnerID: owner,
+			Paging:  paging,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to query API keys")
+		}
+
+		outputToTable, _ := command.Flags().GetBool("table")
+		if outputToTable {
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetHeader([]string{"ID", "NAME", "SCOPE", "OWNER"})
+
+			for _, apiKey := range apiKeys {
+				table.Append([]string{apiKey.ID, apiKey.Name, apiKey.Scope, apiKey.OwnerID})
+			}
+			table.Render()
+
+			return nil
+		}
+
+		err = printJSON(apiKeys)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	},
+}
+
+var apiKeyDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Revoke an API key.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		apiKeyID, _ := command.Flags().GetString("apikey")
+
+		err := client.DeleteAPIKey(apiKeyID)
+		if err != nil {
+			return errors.Wrap(err, "failed to delete API key")
+		}
+
+		return nil
+	},
+}
+
+var apiKeyBootstrapCmd = &cobra.Command{
+	Use:   "bootstrap",
+	Short: "Create an admin API key directly in the database, allowing initial access to a server requiring authentication.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		sqlStore, err := sqlStore(command)
+		if err != nil {
+			return errors.Wrap(err, "failed to create datastore")
+		}
+
+		err = sqlStore.Migrate()
+		if err != nil {
+			return errors.Wrap(err, "failed to migrate the database")
+		}
+
+		key, err := model.NewAPIKeySecret()
+		if err != nil {
+			return err
+		}
+
+		name, _ := command.Flags().GetString("name")
+		apiKey := &model.APIKey{
+			Name:  name,
+			Scope: model.APIKeyScopeAdmin,
+			Key:   key,
+		}
+
+		err = sqlStore.CreateAPIKey(apiKey)
+		if err != nil {
+			return errors.Wrap(err, "failed to create API key")
+		}
+
+		err = printJSON(apiKey)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	},
+}
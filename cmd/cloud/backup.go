@@ -21,6 +21,7 @@ func init() {
 	backupCreateCmd.MarkFlagRequired("installation")
 
 	backupListCmd.Flags().String("installation", "", "The installation id for which the backups should be listed.")
+	backupListCmd.Flags().String("schedule", "", "The backup schedule id for which the backups should be listed.")
 	backupListCmd.Flags().String("state", "", "The state to filter backups by.")
 	registerPagingFlags(backupListCmd)
 	backupListCmd.Flags().Bool("table", false, "Whether to display the returned backup list in a table or not.")
@@ -35,6 +36,7 @@ func init() {
 	backupCmd.AddCommand(backupListCmd)
 	backupCmd.AddCommand(backupGetCmd)
 	backupCmd.AddCommand(backupDeleteCmd)
+	backupCmd.AddCommand(backupScheduleCmd)
 }
 
 var backupCmd = &cobra.Command{
@@ -48,8 +50,7 @@ var backupCreateCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		installationID, _ := command.Flags().GetString("installation")
 
@@ -68,17 +69,18 @@ var backupListCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		installationID, _ := command.Flags().GetString("installation")
 		clusterInstallationID, _ := command.Flags().GetString("cluster-installation")
+		scheduleID, _ := command.Flags().GetString("schedule")
 		state, _ := command.Flags().GetString("state")
 		paging := parsePagingFlags(command)
 
 		request := &model.GetInstallationBackupsRequest{
 			InstallationID:        installationID,
 			ClusterInstallationID: clusterInstallationID,
+			ScheduleID:            scheduleID,
 			State:                 state,
 			Paging:                paging,
 		}
@@ -123,8 +125,7 @@ var backupGetCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		backupID, _ := command.Flags().GetString("backup")
 
@@ -148,8 +149,7 @@ var backupDeleteCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		backupID, _ := command.Flags().GetString("backup")
 
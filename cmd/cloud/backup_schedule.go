@@ -0,0 +1,167 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	backupScheduleCreateCmd.Flags().String("installation", "", "The id of the installation to back up periodically.")
+	backupScheduleCreateCmd.Flags().String("group", "", "The id of the group whose installations should be backed up periodically.")
+	backupScheduleCreateCmd.Flags().Duration("interval", 24*time.Hour, "The time between two scheduled backups.")
+	backupScheduleCreateCmd.Flags().Int64("keep-last", 0, "The number of the most recent scheduled backups of each installation to keep.")
+	backupScheduleCreateCmd.Flags().Int64("keep-daily-days", 0, "The number of days for which the newest scheduled backup of each day is kept.")
+
+	backupScheduleListCmd.Flags().String("installation", "", "The installation id for which the backup schedules should be listed.")
+	backupScheduleListCmd.Flags().String("group", "", "The group id for which the backup schedules should be listed.")
+	registerPagingFlags(backupScheduleListCmd)
+	backupScheduleListCmd.Flags().Bool("table", false, "Whether to display the returned backup schedule list in a table or not.")
+
+	backupScheduleGetCmd.Flags().String("schedule", "", "The id of the backup schedule to get.")
+	backupScheduleGetCmd.MarkFlagRequired("schedule")
+
+	backupScheduleDeleteCmd.Flags().String("schedule", "", "The id of the backup schedule to delete.")
+	backupScheduleDeleteCmd.MarkFlagRequired("schedule")
+
+	backupScheduleCmd.AddCommand(backupScheduleCreateCmd)
+	backupScheduleCmd.AddCommand(backupScheduleListCmd)
+	backupScheduleCmd.AddCommand(backupScheduleGetCmd)
+	backupScheduleCmd.AddCommand(backupScheduleDeleteCmd)
+}
+
+var backupScheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Manipulate installation backup schedules managed by the provisioning server.",
+}
+
+var backupScheduleCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a backup schedule for an installation or a group. Only hibernated installations are backed up; other installations are skipped and reported in the LastError of the schedule.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		installationID, _ := command.Flags().GetString("installation")
+		groupID, _ := command.Flags().GetString("group")
+		interval, _ := command.Flags().GetDuration("interval")
+		keepLast, _ := command.Flags().GetInt64("keep-last")
+		keepDailyDays, _ := command.Flags().GetInt64("keep-daily-days")
+
+		request := &model.CreateInstallationBackupScheduleRequest{
+			InstallationID:         installationID,
+			GroupID:                groupID,
+			IntervalSeconds:        int64(interval.Seconds()),
+			RetentionKeepLast:      keepLast,
+			RetentionKeepDailyDays: keepDailyDays,
+		}
+
+		dryRun, _ := command.Flags().GetBool("dry-run")
+		if dryRun {
+			return runDryRun(request)
+		}
+
+		schedule, err := client.CreateInstallationBackupSchedule(request)
+		if err != nil {
+			return errors.Wrap(err, "failed to create backup schedule")
+		}
+
+		return printJSON(schedule)
+	},
+}
+
+var backupScheduleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List installation backup schedules.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		installationID, _ := command.Flags().GetString("installation")
+		groupID, _ := command.Flags().GetString("group")
+		paging := parsePagingFlags(command)
+
+		schedules, err := client.GetInstallationBackupSchedules(&model.GetInstallationBackupSchedulesRequest{
+			InstallationID: installationID,
+			GroupID:        groupID,
+			Paging:         paging,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to get backup schedules")
+		}
+
+		outputToTable, _ := command.Flags().GetBool("table")
+		if outputToTable {
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetHeader([]string{"ID", "INSTALLATION ID", "GROUP ID", "INTERVAL", "KEEP LAST", "KEEP DAILY DAYS", "NEXT BACKUP AT"})
+
+			for _, schedule := range schedules {
+				table.Append([]string{
+					schedule.ID,
+					schedule.InstallationID,
+					schedule.GroupID,
+					schedule.Interval().String(),
+					strconv.FormatInt(schedule.RetentionKeepLast, 10),
+					strconv.FormatInt(schedule.RetentionKeepDailyDays, 10),
+					utils.TimeFromMillis(schedule.NextBackupAt).Format("2006-01-02 15:04:05 -0700 MST"),
+				})
+			}
+			table.Render()
+
+			return nil
+		}
+
+		return printJSON(schedules)
+	},
+}
+
+var backupScheduleGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Get installation backup schedule.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		scheduleID, _ := command.Flags().GetString("schedule")
+
+		schedule, err := client.GetInstallationBackupSchedule(scheduleID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get backup schedule")
+		}
+
+		return printJSON(schedule)
+	},
+}
+
+var backupScheduleDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete installation backup schedule. Backups requested by the schedule are kept.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		scheduleID, _ := command.Flags().GetString("schedule")
+
+		err := client.DeleteInstallationBackupSchedule(scheduleID)
+		if err != nil {
+			return errors.Wrap(err, "failed to delete backup schedule")
+		}
+
+		return nil
+	},
+}
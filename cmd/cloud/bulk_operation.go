@@ -0,0 +1,236 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	bulkOperationCreateCmd.Flags().String("type", "", "The type of the operation. One of update, hibernate, wake-up, delete or add-annotations.")
+	bulkOperationCreateCmd.Flags().String("owner", "", "Only target installations of this owner.")
+	bulkOperationCreateCmd.Flags().String("group", "", "Only target installations of this group.")
+	bulkOperationCreateCmd.Flags().String("state", "", "Only target installations in this state.")
+	bulkOperationCreateCmd.Flags().StringArray("annotation", []string{}, "Only target installations with this annotation. Use the flag multiple times to require multiple annotations.")
+	bulkOperationCreateCmd.Flags().String("dns-pattern", "", "Only target installations with a DNS matching this pattern, where * matches any characters.")
+	bulkOperationCreateCmd.Flags().Int64("concurrency", model.BulkOperationDefaultConcurrency, "The maximum number of installations with a requested state change which did not settle yet.")
+	bulkOperationCreateCmd.Flags().String("image", "", "The Mattermost container image set by an update operation.")
+	bulkOperationCreateCmd.Flags().String("version", "", "The Mattermost version set by an update operation.")
+	bulkOperationCreateCmd.Flags().String("size", "", "The size of the installations set by an update operation.")
+	bulkOperationCreateCmd.Flags().String("license", "", "The Mattermost License set by an update operation.")
+	bulkOperationCreateCmd.Flags().StringArray("mattermost-env", []string{}, "Env vars set by an update operation. Accepts format: KEY_NAME=VALUE. Use the flag multiple times to set multiple env vars.")
+	bulkOperationCreateCmd.Flags().StringArray("add-annotation", []string{}, "Annotations added by an add-annotations operation. Use the flag multiple times to add multiple annotations.")
+	bulkOperationCreateCmd.MarkFlagRequired("type")
+
+	bulkOperationListCmd.Flags().String("state", "", "The state to filter bulk operations by.")
+	registerPagingFlags(bulkOperationListCmd)
+	bulkOperationListCmd.Flags().Bool("table", false, "Whether to display the returned bulk operation list in a table or not.")
+
+	bulkOperationGetCmd.Flags().String("bulk-operation", "", "The id of the bulk operation to get.")
+	bulkOperationGetCmd.MarkFlagRequired("bulk-operation")
+
+	bulkOperationItemsCmd.Flags().String("bulk-operation", "", "The id of the bulk operation to get the installation results of.")
+	bulkOperationItemsCmd.Flags().Bool("table", false, "Whether to display the returned installation results in a table or not.")
+	bulkOperationItemsCmd.MarkFlagRequired("bulk-operation")
+
+	bulkOperationCancelCmd.Flags().String("bulk-operation", "", "The id of the bulk operation to cancel.")
+	bulkOperationCancelCmd.MarkFlagRequired("bulk-operation")
+
+	bulkOperationCmd.AddCommand(bulkOperationCreateCmd)
+	bulkOperationCmd.AddCommand(bulkOperationListCmd)
+	bulkOperationCmd.AddCommand(bulkOperationGetCmd)
+	bulkOperationCmd.AddCommand(bulkOperationItemsCmd)
+	bulkOperationCmd.AddCommand(bulkOperationCancelCmd)
+}
+
+var bulkOperationCmd = &cobra.Command{
+	Use:   "bulk-operation",
+	Short: "Manipulate operations applied to many installations at once on the provisioning server.",
+}
+
+var bulkOperationCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Apply an operation to all installations matching the given filter.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		operationType, _ := command.Flags().GetString("type")
+		owner, _ := command.Flags().GetString("owner")
+		group, _ := command.Flags().GetString("group")
+		state, _ := command.Flags().GetString("state")
+		annotations, _ := command.Flags().GetStringArray("annotation")
+		dnsPattern, _ := command.Flags().GetString("dns-pattern")
+		concurrency, _ := command.Flags().GetInt64("concurrency")
+		mattermostEnv, _ := command.Flags().GetStringArray("mattermost-env")
+		addAnnotations, _ := command.Flags().GetStringArray("add-annotation")
+
+		request := &model.CreateBulkOperationRequest{
+			Type: model.BulkOperationType(operationType),
+			Filter: model.BulkOperationInstallationFilter{
+				OwnerID:     owner,
+				GroupID:     group,
+				State:       state,
+				Annotations: annotations,
+				DNSPattern:  dnsPattern,
+			},
+			Annotations: addAnnotations,
+			Concurrency: concurrency,
+		}
+
+		if request.Type == model.BulkOperationTypeUpdate {
+			envVarMap, err := parseEnvVarInput(mattermostEnv, false)
+			if err != nil {
+				return err
+			}
+
+			request.Patch = &model.PatchInstallationRequest{
+				Version:       getStringFlagPointer(command, "version"),
+				Image:         getStringFlagPointer(command, "image"),
+				Size:          getStringFlagPointer(command, "size"),
+				License:       getStringFlagPointer(command, "license"),
+				MattermostEnv: envVarMap,
+			}
+		}
+
+		dryRun, _ := command.Flags().GetBool("dry-run")
+		if dryRun {
+			return runDryRun(request)
+		}
+
+		operation, err := client.CreateBulkOperation(request)
+		if err != nil {
+			return errors.Wrap(err, "failed to create bulk operation")
+		}
+
+		return printJSON(operation)
+	},
+}
+
+var bulkOperationListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List bulk operations.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		state, _ := command.Flags().GetString("state")
+		paging := parsePagingFlags(command)
+
+		operations, err := client.GetBulkOperations(&model.GetBulkOperationsRequest{
+			State:  state,
+			Paging: paging,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to get bulk operations")
+		}
+
+		outputToTable, _ := command.Flags().GetBool("table")
+		if outputToTable {
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetHeader([]string{"ID", "TYPE", "STATE", "CONCURRENCY", "CREATED"})
+
+			for _, operation := range operations {
+				table.Append([]string{
+					operation.ID,
+					string(operation.Type),
+					string(operation.State),
+					strconv.FormatInt(operation.Concurrency, 10),
+					utils.TimeFromMillis(operation.CreateAt).Format("2006-01-02 15:04:05 -0700 MST"),
+				})
+			}
+			table.Render()
+
+			return nil
+		}
+
+		return printJSON(operations)
+	},
+}
+
+var bulkOperationGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Get bulk operation.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		operationID, _ := command.Flags().GetString("bulk-operation")
+
+		operation, err := client.GetBulkOperation(operationID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get bulk operation")
+		}
+
+		return printJSON(operation)
+	},
+}
+
+var bulkOperationItemsCmd = &cobra.Command{
+	Use:   "items",
+	Short: "Get the outcome of a bulk operation for each installation.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		operationID, _ := command.Flags().GetString("bulk-operation")
+
+		items, err := client.GetBulkOperationItems(operationID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get bulk operation items")
+		}
+
+		outputToTable, _ := command.Flags().GetBool("table")
+		if outputToTable {
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetHeader([]string{"INSTALLATION ID", "STATE", "ERROR"})
+
+			for _, item := range items {
+				table.Append([]string{
+					item.InstallationID,
+					string(item.State),
+					item.Error,
+				})
+			}
+			table.Render()
+
+			return nil
+		}
+
+		return printJSON(items)
+	},
+}
+
+var bulkOperationCancelCmd = &cobra.Command{
+	Use:   "cancel",
+	Short: "Cancel bulk operation. Installation state changes already requested are not reverted.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		operationID, _ := command.Flags().GetString("bulk-operation")
+
+		err := client.CancelBulkOperation(operationID)
+		if err != nil {
+			return errors.Wrap(err, "failed to cancel bulk operation")
+		}
+
+		return nil
+	},
+}
@@ -48,6 +48,8 @@ func init() {
 	clusterCreateCmd.Flags().String("pgbouncer-values", model.PgbouncerDefaultVersion.Values(), "The branch name of the desired chart value file's version for Pgbouncer")
 	clusterCreateCmd.Flags().String("networking", "amazon-vpc-routed-eni", "Networking mode to use, for example: weave, calico, canal, amazon-vpc-routed-eni")
 	clusterCreateCmd.Flags().String("vpc", "", "Set to use a shared VPC")
+	clusterCreateCmd.Flags().String("provisioner", model.ProvisionerKops, "The provisioner managing the cluster. Use 'local' to register an existing cluster, for example one created with kind.")
+	clusterCreateCmd.Flags().String("kubeconfig", "", "The path to the kubeconfig of a local cluster on the provisioning server host. Required with the local provisioner.")
 
 	clusterCreateCmd.Flags().StringArray("annotation", []string{}, "Additional annotations for the cluster. Accepts multiple values, for example: '... --annotation abc --annotation def'")
 
@@ -109,6 +111,7 @@ func init() {
 	clusterCmd.AddCommand(clusterUpdateCmd)
 	clusterCmd.AddCommand(clusterUpgradeCmd)
 	clusterCmd.AddCommand(clusterResizeCmd)
+	clusterCmd.AddCommand(clusterDrainCmd)
 	clusterCmd.AddCommand(clusterDeleteCmd)
 	clusterCmd.AddCommand(clusterGetCmd)
 	clusterCmd.AddCommand(clusterListCmd)
@@ -136,8 +139,7 @@ var clusterCreateCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		provider, _ := command.Flags().GetString("provider")
 		version, _ := command.Flags().GetString("version")
@@ -147,9 +149,13 @@ var clusterCreateCmd = &cobra.Command{
 		annotations, _ := command.Flags().GetStringArray("annotation")
 		networking, _ := command.Flags().GetString("networking")
 		vpc, _ := command.Flags().GetString("vpc")
+		provisioner, _ := command.Flags().GetString("provisioner")
+		kubeconfigPath, _ := command.Flags().GetString("kubeconfig")
 
 		request := &model.CreateClusterRequest{
 			Provider:               provider,
+			Provisioner:            provisioner,
+			KubeconfigPath:         kubeconfigPath,
 			Version:                version,
 			KopsAMI:                kopsAMI,
 			Zones:                  strings.Split(zones, ","),
@@ -215,8 +221,7 @@ var clusterProvisionCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 		clusterID, _ := command.Flags().GetString("cluster")
 
 		var request *model.ProvisionClusterRequest = nil
@@ -256,8 +261,7 @@ var clusterUpdateCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		clusterID, _ := command.Flags().GetString("cluster")
 		allowInstallations, _ := command.Flags().GetBool("allow-installations")
@@ -296,8 +300,7 @@ var clusterUpgradeCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		clusterID, _ := command.Flags().GetString("cluster")
 		useRotator, _ := command.Flags().GetBool("use-rotator")
@@ -352,8 +355,7 @@ var clusterResizeCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		clusterID, _ := command.Flags().GetString("cluster")
 
@@ -407,8 +409,7 @@ var clusterDeleteCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		clusterID, _ := command.Flags().GetString("cluster")
 
@@ -427,8 +428,7 @@ var clusterGetCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		clusterID, _ := command.Flags().GetString("cluster")
 		cluster, err := client.GetCluster(clusterID)
@@ -454,8 +454,7 @@ var clusterListCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		paging := parsePagingFlags(command)
 
@@ -473,6 +472,14 @@ var clusterListCmd = &cobra.Command{
 			table.SetHeader([]string{"ID", "STATE", "VERSION", "MASTER NODES", "WORKER NODES", "NETWORKING", "VPC"})
 
 			for _, cluster := range clusters {
+				if cluster.ProvisionerMetadataKops == nil {
+					version := ""
+					if cluster.ProvisionerMetadataLocal != nil {
+						version = cluster.ProvisionerMetadataLocal.Version
+					}
+					table.Append([]string{cluster.ID, cluster.State, version, "", "", "", ""})
+					continue
+				}
 				table.Append([]string{
 					cluster.ID,
 					cluster.State,
@@ -503,8 +510,7 @@ var clusterUtilitiesCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 		clusterID, err := command.Flags().GetString("cluster")
 		if err != nil {
 			return err
@@ -36,8 +36,7 @@ var clusterAnnotationAddCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		clusterID, _ := command.Flags().GetString("cluster")
 		annotations, _ := command.Flags().GetStringArray("annotation")
@@ -69,8 +68,7 @@ var clusterAnnotationDeleteCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		clusterID, _ := command.Flags().GetString("cluster")
 		annotation, _ := command.Flags().GetString("annotation")
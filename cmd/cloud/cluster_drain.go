@@ -0,0 +1,76 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	clusterDrainRequestCmd.Flags().String("cluster", "", "The id of the cluster to drain.")
+	clusterDrainRequestCmd.Flags().Int64("max-concurrent-migrations", model.ClusterDrainDefaultMaxConcurrentMigrations, "The maximum number of installations migrated off the cluster at the same time.")
+	clusterDrainRequestCmd.MarkFlagRequired("cluster")
+
+	clusterDrainStatusCmd.Flags().String("cluster", "", "The id of the drained cluster.")
+	clusterDrainStatusCmd.MarkFlagRequired("cluster")
+
+	clusterDrainCmd.AddCommand(clusterDrainRequestCmd)
+	clusterDrainCmd.AddCommand(clusterDrainStatusCmd)
+}
+
+var clusterDrainCmd = &cobra.Command{
+	Use:   "drain",
+	Short: "Manipulate cluster drain operations managed by the provisioning server.",
+}
+
+var clusterDrainRequestCmd = &cobra.Command{
+	Use:   "request",
+	Short: "Request cluster drain which disallows new installations and migrates all multitenant installations to other clusters.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		clusterID, _ := command.Flags().GetString("cluster")
+		maxConcurrentMigrations, _ := command.Flags().GetInt64("max-concurrent-migrations")
+
+		request := &model.ClusterDrainRequest{
+			MaxConcurrentMigrations: maxConcurrentMigrations,
+		}
+
+		dryRun, _ := command.Flags().GetBool("dry-run")
+		if dryRun {
+			return runDryRun(request)
+		}
+
+		drainOperation, err := client.DrainCluster(clusterID, request)
+		if err != nil {
+			return errors.Wrap(err, "failed to request cluster drain")
+		}
+
+		return printJSON(drainOperation)
+	},
+}
+
+var clusterDrainStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Get progress of the most recent drain of the cluster.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		clusterID, _ := command.Flags().GetString("cluster")
+
+		drainStatus, err := client.GetClusterDrainStatus(clusterID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get cluster drain status")
+		}
+
+		return printJSON(drainStatus)
+	},
+}
@@ -65,8 +65,7 @@ var clusterInstallationGetCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		clusterInstallationID, _ := command.Flags().GetString("cluster-installation")
 		clusterInstallation, err := client.GetClusterInstallation(clusterInstallationID)
@@ -92,8 +91,7 @@ var clusterInstallationListCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		cluster, _ := command.Flags().GetString("cluster")
 		installation, _ := command.Flags().GetString("installation")
@@ -142,8 +140,7 @@ var clusterInstallationConfigGetCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		clusterInstallationID, _ := command.Flags().GetString("cluster-installation")
 		clusterInstallationConfig, err := client.GetClusterInstallationConfig(clusterInstallationID)
@@ -169,8 +166,7 @@ var clusterInstallationConfigSetCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		clusterInstallationID, _ := command.Flags().GetString("cluster-installation")
 		key, _ := command.Flags().GetString("key")
@@ -204,8 +200,7 @@ var clusterInstallationMMCTL = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		clusterInstallationID, _ := command.Flags().GetString("cluster-installation")
 		subcommand, _ := command.Flags().GetString("command")
@@ -228,8 +223,7 @@ var clusterInstallationMattermostCLICmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		clusterInstallationID, _ := command.Flags().GetString("cluster-installation")
 		subcommand, _ := command.Flags().GetString("command")
@@ -30,8 +30,7 @@ var dashboardCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		refreshSeconds, _ := command.Flags().GetInt("refresh-seconds")
 		if refreshSeconds < 1 {
@@ -31,8 +31,7 @@ var databaseListCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		vpcID, _ := command.Flags().GetString("vpc-id")
 		databaseType, _ := command.Flags().GetString("database-type")
@@ -0,0 +1,97 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"os"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	eventCmd.PersistentFlags().String("server", defaultLocalServerAPI, "The provisioning server whose API will be queried.")
+
+	eventListCmd.Flags().String("resource-type", "", "The resource type by which to filter events, for example: installation, cluster.")
+	eventListCmd.Flags().String("resource", "", "The resource ID by which to filter events.")
+	eventListCmd.Flags().Duration("since", 0, "Only list events which happened within the given duration, for example: 24h.")
+	eventListCmd.Flags().Duration("until", 0, "Only list events which happened before the given duration ago, for example: 1h.")
+	eventListCmd.Flags().Bool("table", false, "Whether to display the returned event list in a table or not")
+	registerPagingFlags(eventListCmd)
+
+	eventCmd.AddCommand(eventListCmd)
+}
+
+var eventCmd = &cobra.Command{
+	Use:   "events",
+	Short: "View the state change history of resources managed by the provisioning server.",
+}
+
+var eventListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List resource state change events, the newest first.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		resourceType, _ := command.Flags().GetString("resource-type")
+		resourceID, _ := command.Flags().GetString("resource")
+		since, _ := command.Flags().GetDuration("since")
+		until, _ := command.Flags().GetDuration("until")
+		paging := parsePagingFlags(command)
+
+		request := &model.GetEventsRequest{
+			ResourceType: resourceType,
+			ResourceID:   resourceID,
+			Paging:       paging,
+		}
+		now := time.Now()
+		if since > 0 {
+			request.Since = utils.GetMillisAtTime(now.Add(-since))
+		}
+		if until > 0 {
+			request.Until = utils.GetMillisAtTime(now.Add(-until))
+		}
+
+		events, err := client.GetEvents(request)
+		if err != nil {
+			return errors.Wrap(err, "failed to query events")
+		}
+
+		outputToTable, _ := command.Flags().GetBool("table")
+		if outputToTable {
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetHeader([]string{"TIMESTAMP", "TYPE", "RESOURCE", "OLD STATE", "NEW STATE", "INSTANCE", "ERROR"})
+
+			for _, event := range events {
+				table.Append([]string{
+					utils.TimeFromMillis(event.Timestamp).Format("2006-01-02 15:04:05 -0700 MST"),
+					event.ResourceType,
+					event.ResourceID,
+					event.OldState,
+					event.NewState,
+					event.InstanceID,
+					event.Error,
+				})
+			}
+			table.Render()
+
+			return nil
+		}
+
+		err = printJSON(events)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	},
+}
@@ -24,6 +24,13 @@ func init() {
 	groupCreateCmd.Flags().String("image", "", "The Mattermost container image to use.")
 	groupCreateCmd.Flags().Int64("max-rolling", 1, "The maximum number of installations that can be updated at one time when a group is updated")
 	groupCreateCmd.Flags().StringArray("mattermost-env", []string{}, "Env vars to add to the Mattermost App. Accepts format: KEY_NAME=VALUE. Use the flag multiple times to set multiple env vars.")
+	groupCreateCmd.Flags().Int64("canary-size", 0, "The number of installations to update first when a group is updated. The rest are updated once all of them finished updating.")
+	groupCreateCmd.Flags().Int64("failure-threshold-percent", 0, "The share of updated installations failing to update at which the group rollout is paused. 0 disables the check.")
+	groupCreateCmd.Flags().String("size", "", "The size of the installations in this group. Changes are rolled out to the installations.")
+	groupCreateCmd.Flags().String("license", "", "The Mattermost License of the installations in this group. Changes are rolled out to the installations.")
+	groupCreateCmd.Flags().String("affinity", "", "The affinity of installations created in this group.")
+	groupCreateCmd.Flags().String("database", "", "The database backing installations created in this group.")
+	groupCreateCmd.Flags().String("filestore", "", "The filestore backing installations created in this group.")
 	groupCreateCmd.MarkFlagRequired("name")
 
 	groupUpdateCmd.Flags().String("group", "", "The id of the group to be updated.")
@@ -35,6 +42,13 @@ func init() {
 	groupUpdateCmd.Flags().StringArray("mattermost-env", []string{}, "Env vars to add to the Mattermost App. Accepts format: KEY_NAME=VALUE. Use the flag multiple times to set multiple env vars.")
 	groupUpdateCmd.Flags().Bool("mattermost-env-clear", false, "Clears all env var data.")
 	groupUpdateCmd.Flags().Bool("force-sequence-update", false, "Forces the group version sequence to be increased by 1 even when no updates are present")
+	groupUpdateCmd.Flags().Int64("canary-size", 0, "The number of installations to update first when a group is updated. The rest are updated once all of them finished updating.")
+	groupUpdateCmd.Flags().Int64("failure-threshold-percent", 0, "The share of updated installations failing to update at which the group rollout is paused. 0 disables the check.")
+	groupUpdateCmd.Flags().String("size", "", "The size of the installations in this group. Changes are rolled out to the installations.")
+	groupUpdateCmd.Flags().String("license", "", "The Mattermost License of the installations in this group. Changes are rolled out to the installations.")
+	groupUpdateCmd.Flags().String("affinity", "", "The affinity of installations created in this group.")
+	groupUpdateCmd.Flags().String("database", "", "The database backing installations created in this group.")
+	groupUpdateCmd.Flags().String("filestore", "", "The filestore backing installations created in this group.")
 	groupUpdateCmd.MarkFlagRequired("group")
 
 	groupDeleteCmd.Flags().String("group", "", "The id of the group to be deleted.")
@@ -58,6 +72,26 @@ func init() {
 	groupLeaveCmd.Flags().Bool("retain-config", true, "Whether to retain the group configuration values or not.")
 	groupLeaveCmd.MarkFlagRequired("installation")
 
+	groupHistoryCmd.Flags().String("group", "", "The id of the group whose configuration history should be fetched.")
+	groupHistoryCmd.Flags().Int64("from", 0, "The group sequence to compare from instead of showing every change.")
+	groupHistoryCmd.Flags().Int64("to", 0, "The group sequence to compare to instead of showing every change.")
+	groupHistoryCmd.Flags().Bool("table", false, "Whether to display the configuration changes in a table or not")
+	groupHistoryCmd.MarkFlagRequired("group")
+
+	groupRollbackCmd.Flags().String("group", "", "The id of the group to be rolled back.")
+	groupRollbackCmd.Flags().Int64("sequence", 0, "The group sequence whose configuration should be restored.")
+	groupRollbackCmd.MarkFlagRequired("group")
+	groupRollbackCmd.MarkFlagRequired("sequence")
+
+	groupRolloutResumeCmd.Flags().String("group", "", "The id of the group whose paused rollout should be resumed.")
+	groupRolloutResumeCmd.MarkFlagRequired("group")
+
+	groupRolloutAbortCmd.Flags().String("group", "", "The id of the group whose rollout should be aborted.")
+	groupRolloutAbortCmd.MarkFlagRequired("group")
+
+	groupRolloutCmd.AddCommand(groupRolloutResumeCmd)
+	groupRolloutCmd.AddCommand(groupRolloutAbortCmd)
+
 	groupCmd.AddCommand(groupCreateCmd)
 	groupCmd.AddCommand(groupUpdateCmd)
 	groupCmd.AddCommand(groupDeleteCmd)
@@ -67,6 +101,9 @@ func init() {
 	groupCmd.AddCommand(groupGetGroupsStatusCmd)
 	groupCmd.AddCommand(groupJoinCmd)
 	groupCmd.AddCommand(groupLeaveCmd)
+	groupCmd.AddCommand(groupRolloutCmd)
+	groupCmd.AddCommand(groupHistoryCmd)
+	groupCmd.AddCommand(groupRollbackCmd)
 }
 
 var groupCmd = &cobra.Command{
@@ -80,8 +117,7 @@ var groupCreateCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		name, _ := command.Flags().GetString("name")
 		image, _ := command.Flags().GetString("image")
@@ -89,6 +125,13 @@ var groupCreateCmd = &cobra.Command{
 		version, _ := command.Flags().GetString("version")
 		maxRolling, _ := command.Flags().GetInt64("max-rolling")
 		mattermostEnv, _ := command.Flags().GetStringArray("mattermost-env")
+		canarySize, _ := command.Flags().GetInt64("canary-size")
+		failureThresholdPercent, _ := command.Flags().GetInt64("failure-threshold-percent")
+		size, _ := command.Flags().GetString("size")
+		license, _ := command.Flags().GetString("license")
+		affinity, _ := command.Flags().GetString("affinity")
+		database, _ := command.Flags().GetString("database")
+		filestore, _ := command.Flags().GetString("filestore")
 
 		envVarMap, err := parseEnvVarInput(mattermostEnv, false)
 		if err != nil {
@@ -102,6 +145,15 @@ var groupCreateCmd = &cobra.Command{
 			Version:       version,
 			Image:         image,
 			MattermostEnv: envVarMap,
+
+			CanarySize:              canarySize,
+			FailureThresholdPercent: failureThresholdPercent,
+
+			Size:      size,
+			License:   license,
+			Affinity:  affinity,
+			Database:  database,
+			Filestore: filestore,
 		}
 
 		dryRun, _ := command.Flags().GetBool("dry-run")
@@ -129,8 +181,7 @@ var groupUpdateCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		groupID, _ := command.Flags().GetString("group")
 		mattermostEnv, _ := command.Flags().GetStringArray("mattermost-env")
@@ -151,6 +202,15 @@ var groupUpdateCmd = &cobra.Command{
 			MaxRolling:          getInt64FlagPointer(command, "max-rolling"),
 			MattermostEnv:       envVarMap,
 			ForceSequenceUpdate: forceSequenceUpdate,
+
+			CanarySize:              getInt64FlagPointer(command, "canary-size"),
+			FailureThresholdPercent: getInt64FlagPointer(command, "failure-threshold-percent"),
+
+			Size:      getStringFlagPointer(command, "size"),
+			License:   getStringFlagPointer(command, "license"),
+			Affinity:  getStringFlagPointer(command, "affinity"),
+			Database:  getStringFlagPointer(command, "database"),
+			Filestore: getStringFlagPointer(command, "filestore"),
 		}
 
 		dryRun, _ := command.Flags().GetBool("dry-run")
@@ -178,8 +238,7 @@ var groupDeleteCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		groupID, _ := command.Flags().GetString("group")
 
@@ -198,8 +257,7 @@ var groupGetCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		groupID, _ := command.Flags().GetString("group")
 		group, err := client.GetGroup(groupID)
@@ -225,8 +283,7 @@ var groupListCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		paging := parsePagingFlags(command)
 		groups, err := client.GetGroups(&model.GetGroupsRequest{
@@ -269,8 +326,7 @@ var groupGetStatusCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		groupID, _ := command.Flags().GetString("group")
 		groupStatus, err := client.GetGroupStatus(groupID)
@@ -296,8 +352,7 @@ var groupJoinCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		groupID, _ := command.Flags().GetString("group")
 		installationID, _ := command.Flags().GetString("installation")
@@ -317,9 +372,8 @@ var groupLeaveCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
 		retainConfig, _ := command.Flags().GetBool("retain-config")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		installationID, _ := command.Flags().GetString("installation")
 		request := &model.LeaveGroupRequest{RetainConfig: retainConfig}
@@ -339,8 +393,7 @@ var groupGetGroupsStatusCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		groupStatus, err := client.GetGroupsStatus()
 		if err != nil {
@@ -358,3 +411,163 @@ var groupGetGroupsStatusCmd = &cobra.Command{
 		return nil
 	},
 }
+
+// groupConfigDiff is the difference between the group configurations of two
+// sequences.
+type groupConfigDiff struct {
+	FromSequence int64
+	ToSequence   int64
+	CreateAt     int64
+	Changes      []model.GroupConfigChange
+}
+
+var groupHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the configuration changes between the sequences of a group.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		groupID, _ := command.Flags().GetString("group")
+		groupConfigs, err := client.GetGroupHistory(groupID)
+		if err != nil {
+			return errors.Wrap(err, "failed to query group history")
+		}
+		if groupConfigs == nil {
+			return nil
+		}
+
+		var diffs []groupConfigDiff
+		if command.Flags().Changed("from") || command.Flags().Changed("to") {
+			from, _ := command.Flags().GetInt64("from")
+			to, _ := command.Flags().GetInt64("to")
+
+			var fromConfig, toConfig *model.GroupConfig
+			for _, groupConfig := range groupConfigs {
+				if groupConfig.Sequence == from {
+					fromConfig = groupConfig
+				}
+				if groupConfig.Sequence == to {
+					toConfig = groupConfig
+				}
+			}
+			if fromConfig == nil || toConfig == nil {
+				return errors.Errorf("group has no configuration for sequence %d or %d", from, to)
+			}
+
+			diffs = append(diffs, groupConfigDiff{
+				FromSequence: from,
+				ToSequence:   to,
+				CreateAt:     toConfig.CreateAt,
+				Changes:      toConfig.Diff(fromConfig),
+			})
+		} else {
+			// The history is sorted by sequence, most recent first, so every
+			// configuration is compared to the next one in the list.
+			for i, groupConfig := range groupConfigs {
+				previous := &model.GroupConfig{Sequence: -1}
+				if i+1 < len(groupConfigs) {
+					previous = groupConfigs[i+1]
+				}
+				diffs = append(diffs, groupConfigDiff{
+					FromSequence: previous.Sequence,
+					ToSequence:   groupConfig.Sequence,
+					CreateAt:     groupConfig.CreateAt,
+					Changes:      groupConfig.Diff(previous),
+				})
+			}
+		}
+
+		outputToTable, _ := command.Flags().GetBool("table")
+		if outputToTable {
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetHeader([]string{"FROM", "TO", "FIELD", "OLD", "NEW"})
+
+			for _, diff := range diffs {
+				for _, change := range diff.Changes {
+					table.Append([]string{fmt.Sprintf("%d", diff.FromSequence), fmt.Sprintf("%d", diff.ToSequence), change.Field, change.Old, change.New})
+				}
+			}
+			table.Render()
+
+			return nil
+		}
+
+		return printJSON(diffs)
+	},
+}
+
+var groupRollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Restore the configuration of a previous group sequence. The group sequence is increased so that installations are updated.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		groupID, _ := command.Flags().GetString("group")
+		sequence, _ := command.Flags().GetInt64("sequence")
+
+		request := &model.RollbackGroupRequest{Sequence: sequence}
+
+		dryRun, _ := command.Flags().GetBool("dry-run")
+		if dryRun {
+			err := printJSON(request)
+			if err != nil {
+				return errors.Wrap(err, "failed to print API request")
+			}
+
+			return nil
+		}
+
+		group, err := client.RollbackGroup(groupID, request)
+		if err != nil {
+			return errors.Wrap(err, "failed to roll back group")
+		}
+
+		return printJSON(group)
+	},
+}
+
+var groupRolloutCmd = &cobra.Command{
+	Use:   "rollout",
+	Short: "Manage the rollout of group configuration changes.",
+}
+
+var groupRolloutResumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Resume a paused group rollout.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		groupID, _ := command.Flags().GetString("group")
+		group, err := client.ResumeGroupRollout(groupID)
+		if err != nil {
+			return errors.Wrap(err, "failed to resume group rollout")
+		}
+
+		return printJSON(group)
+	},
+}
+
+var groupRolloutAbortCmd = &cobra.Command{
+	Use:   "abort",
+	Short: "Abort a group rollout. The remaining installations are not updated until the group configuration changes again.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		groupID, _ := command.Flags().GetString("group")
+		group, err := client.AbortGroupRollout(groupID)
+		if err != nil {
+			return errors.Wrap(err, "failed to abort group rollout")
+		}
+
+		return printJSON(group)
+	},
+}
@@ -29,11 +29,12 @@ func init() {
 	installationCreateCmd.Flags().String("dns", "", "The URL at which the Mattermost server will be available.")
 	installationCreateCmd.Flags().String("size", model.InstallationDefaultSize, "The size of the installation. Accepts 100users, 1000users, 5000users, 10000users, 25000users, miniSingleton, or miniHA. Defaults to 100users.")
 	installationCreateCmd.Flags().String("affinity", model.InstallationAffinityIsolated, "How other installations may be co-located in the same cluster.")
+	installationCreateCmd.Flags().String("scheduling-policy", "", "The policy used to select the cluster for the installation. Accepts first-fit, bin-packing, spread, annotation-affinity, or owner-anti-affinity. Defaults to the server policy.")
 	installationCreateCmd.Flags().String("license", "", "The Mattermost License to use in the server.")
 	installationCreateCmd.Flags().String("database", model.InstallationDatabaseMysqlOperator, "The Mattermost server database type. Accepts mysql-operator, aws-rds, aws-rds-postgres, or aws-multitenant-rds")
 	installationCreateCmd.Flags().String("filestore", model.InstallationFilestoreMinioOperator, "The Mattermost server filestore type. Accepts minio-operator, aws-s3, bifrost, or aws-multitenant-s3")
 	installationCreateCmd.Flags().StringArray("mattermost-env", []string{}, "Env vars to add to the Mattermost App. Accepts format: KEY_NAME=VALUE. Use the flag multiple times to set multiple env vars.")
-	installationCreateCmd.Flags().StringArray("annotation", []string{}, "Additional annotations for the installation. Accepts multiple values, for example: '... --annotation abc --annotation def'")
+	installationCreateCmd.Flags().StringArray("annotation", []string{}, "Additional annotations for the installation. The installation is only scheduled on clusters with all of its annotations. Accepts multiple values, for example: '... --annotation abc --annotation def'")
 	installationCreateCmd.Flags().String("rds-primary-instance", "", "The machine instance type used for primary replica of database cluster. Works only with single tenant RDS databases.")
 	installationCreateCmd.Flags().String("rds-replica-instance", "", "The machine instance type used for reader replicas of database cluster. Works only with single tenant RDS databases.")
 	installationCreateCmd.Flags().Int("rds-replicas-count", 0, "The number of reader replicas of database cluster. Min: 0, Max: 15. Works only with single tenant RDS databases.")
@@ -60,6 +61,21 @@ func init() {
 	installationListCmd.Flags().String("group", "", "The group ID to filter installations.")
 	installationListCmd.Flags().String("state", "", "The state to filter installations by.")
 	installationListCmd.Flags().String("dns", "", "The dns name to filter installations by.")
+	installationListCmd.Flags().String("dns-contains", "", "Only list installations with a dns name containing this value.")
+	installationListCmd.Flags().String("version", "", "The Mattermost version to filter installations by.")
+	installationListCmd.Flags().String("image", "", "The Mattermost container image to filter installations by.")
+	installationListCmd.Flags().String("database", "", "The database type to filter installations by.")
+	installationListCmd.Flags().String("filestore", "", "The filestore type to filter installations by.")
+	installationListCmd.Flags().String("size", "", "The size to filter installations by.")
+	installationListCmd.Flags().String("affinity", "", "The affinity to filter installations by.")
+	installationListCmd.Flags().String("cluster", "", "Only list installations running on this cluster.")
+	installationListCmd.Flags().Int64("created-after", 0, "Only list installations created after this time in milliseconds since the epoch.")
+	installationListCmd.Flags().Int64("created-before", 0, "Only list installations created before this time in milliseconds since the epoch.")
+	installationListCmd.Flags().StringArray("annotation", []string{}, "Only list installations with this annotation. Use the flag multiple times to require multiple annotations.")
+	installationListCmd.Flags().StringArray("any-annotation", []string{}, "Only list installations with at least one of the annotations passed with this flag.")
+	installationListCmd.Flags().String("sort-by", "", "The field to sort installations by. One of create_at, dns, version, state or owner.")
+	installationListCmd.Flags().String("sort-order", "", "The sort order. One of asc or desc.")
+	installationListCmd.Flags().String("after", "", "Only list installations sorted after the installation with this ID, usually the last one of the previous page. Cannot be combined with a page other than 0.")
 	installationListCmd.Flags().Bool("include-group-config", true, "Whether to include group configuration in the installations or not.")
 	installationListCmd.Flags().Bool("include-group-config-overrides", true, "Whether to include a group configuration override summary in the installations or not.")
 	installationListCmd.Flags().Bool("hide-license", true, "Whether to hide the license value in the output or not.")
@@ -93,6 +109,13 @@ func init() {
 	installationCmd.AddCommand(installationsGetStatuses)
 	installationCmd.AddCommand(installationRecoveryCmd)
 	installationCmd.AddCommand(backupCmd)
+	installationCmd.AddCommand(installationDBMigrationOperationCmd)
+	installationCmd.AddCommand(installationDBRestorationOperationCmd)
+	installationCmd.AddCommand(installationCloneOperationCmd)
+	installationCmd.AddCommand(installationClusterMigrationOperationCmd)
+	installationCmd.AddCommand(installationSchedulingCmd)
+	installationCmd.AddCommand(scheduledOperationCmd)
+	installationCmd.AddCommand(bulkOperationCmd)
 }
 
 var installationCmd = &cobra.Command{
@@ -106,8 +129,7 @@ var installationCreateCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		ownerID, _ := command.Flags().GetString("owner")
 		groupID, _ := command.Flags().GetString("group")
@@ -116,6 +138,7 @@ var installationCreateCmd = &cobra.Command{
 		size, _ := command.Flags().GetString("size")
 		dns, _ := command.Flags().GetString("dns")
 		affinity, _ := command.Flags().GetString("affinity")
+		schedulingPolicy, _ := command.Flags().GetString("scheduling-policy")
 		license, _ := command.Flags().GetString("license")
 		database, _ := command.Flags().GetString("database")
 		filestore, _ := command.Flags().GetString("filestore")
@@ -128,18 +151,19 @@ var installationCreateCmd = &cobra.Command{
 		}
 
 		request := &model.CreateInstallationRequest{
-			OwnerID:       ownerID,
-			GroupID:       groupID,
-			Version:       version,
-			Image:         image,
-			Size:          size,
-			DNS:           dns,
-			License:       license,
-			Affinity:      affinity,
-			Database:      database,
-			Filestore:     filestore,
-			MattermostEnv: envVarMap,
-			Annotations:   annotations,
+			OwnerID:          ownerID,
+			GroupID:          groupID,
+			Version:          version,
+			Image:            image,
+			Size:             size,
+			DNS:              dns,
+			License:          license,
+			Affinity:         affinity,
+			SchedulingPolicy: schedulingPolicy,
+			Database:         database,
+			Filestore:        filestore,
+			MattermostEnv:    envVarMap,
+			Annotations:      annotations,
 		}
 
 		if model.IsSingleTenantRDS(database) {
@@ -181,8 +205,7 @@ var installationUpdateCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		installationID, _ := command.Flags().GetString("installation")
 		mattermostEnv, _ := command.Flags().GetStringArray("mattermost-env")
@@ -227,8 +250,7 @@ var installationDeleteCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		installationID, _ := command.Flags().GetString("installation")
 
@@ -247,8 +269,7 @@ var installationHibernateCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		installationID, _ := command.Flags().GetString("installation")
 
@@ -272,8 +293,7 @@ var installationWakeupCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		installationID, _ := command.Flags().GetString("installation")
 
@@ -297,8 +317,7 @@ var installationGetCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		installationID, _ := command.Flags().GetString("installation")
 		includeGroupConfig, _ := command.Flags().GetBool("include-group-config")
@@ -334,13 +353,27 @@ var installationListCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		owner, _ := command.Flags().GetString("owner")
 		group, _ := command.Flags().GetString("group")
 		state, _ := command.Flags().GetString("state")
 		dns, _ := command.Flags().GetString("dns")
+		dnsContains, _ := command.Flags().GetString("dns-contains")
+		version, _ := command.Flags().GetString("version")
+		image, _ := command.Flags().GetString("image")
+		database, _ := command.Flags().GetString("database")
+		filestore, _ := command.Flags().GetString("filestore")
+		size, _ := command.Flags().GetString("size")
+		affinity, _ := command.Flags().GetString("affinity")
+		cluster, _ := command.Flags().GetString("cluster")
+		createdAfter, _ := command.Flags().GetInt64("created-after")
+		createdBefore, _ := command.Flags().GetInt64("created-before")
+		annotations, _ := command.Flags().GetStringArray("annotation")
+		anyAnnotations, _ := command.Flags().GetStringArray("any-annotation")
+		sortBy, _ := command.Flags().GetString("sort-by")
+		sortOrder, _ := command.Flags().GetString("sort-order")
+		after, _ := command.Flags().GetString("after")
 		includeGroupConfig, _ := command.Flags().GetBool("include-group-config")
 		includeGroupConfigOverrides, _ := command.Flags().GetBool("include-group-config-overrides")
 		hideLicense, _ := command.Flags().GetBool("hide-license")
@@ -351,6 +384,21 @@ var installationListCmd = &cobra.Command{
 			GroupID:                     group,
 			State:                       state,
 			DNS:                         dns,
+			DNSContains:                 dnsContains,
+			Version:                     version,
+			Image:                       image,
+			Database:                    database,
+			Filestore:                   filestore,
+			Size:                        size,
+			Affinity:                    affinity,
+			ClusterID:                   cluster,
+			CreatedAfter:                createdAfter,
+			CreatedBefore:               createdBefore,
+			Annotations:                 annotations,
+			AnyAnnotations:              anyAnnotations,
+			SortBy:                      sortBy,
+			SortOrder:                   sortOrder,
+			After:                       after,
 			IncludeGroupConfig:          includeGroupConfig,
 			IncludeGroupConfigOverrides: includeGroupConfigOverrides,
 			Paging:                      paging,
@@ -396,8 +444,7 @@ var installationsGetStatuses = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		installationsStatus, err := client.GetInstallationsStatus()
 		if err != nil {
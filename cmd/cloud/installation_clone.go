@@ -0,0 +1,141 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"os"
+
+	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	installationCloneRequestCmd.Flags().String("installation", "", "The id of the installation to clone. The installation must be hibernated.")
+	installationCloneRequestCmd.Flags().String("dns", "", "The URL at which the clone installation can be reached from the internet.")
+	installationCloneRequestCmd.Flags().String("owner", "", "An opaque identifier describing the owner of the clone installation. Defaults to the owner of the source installation.")
+	installationCloneRequestCmd.MarkFlagRequired("installation")
+	installationCloneRequestCmd.MarkFlagRequired("dns")
+
+	installationClonesListCmd.Flags().String("installation", "", "The id of the source installation to query operations.")
+	installationClonesListCmd.Flags().String("clone-installation", "", "The id of the clone installation to query operations.")
+	installationClonesListCmd.Flags().String("state", "", "The state to filter operations by.")
+	registerPagingFlags(installationClonesListCmd)
+	installationClonesListCmd.Flags().Bool("table", false, "Whether to display the returned clone operations list in a table or not.")
+
+	installationCloneGetCmd.Flags().String("clone", "", "The id of the clone operation.")
+	installationCloneGetCmd.MarkFlagRequired("clone")
+
+	installationCloneOperationCmd.AddCommand(installationCloneRequestCmd)
+	installationCloneOperationCmd.AddCommand(installationClonesListCmd)
+	installationCloneOperationCmd.AddCommand(installationCloneGetCmd)
+}
+
+var installationCloneOperationCmd = &cobra.Command{
+	Use:   "clone",
+	Short: "Manipulate installation clone operations managed by the provisioning server.",
+}
+
+var installationCloneRequestCmd = &cobra.Command{
+	Use:   "request",
+	Short: "Request creation of a new installation with the configuration and data of an existing hibernated one.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		installationID, _ := command.Flags().GetString("installation")
+		dns, _ := command.Flags().GetString("dns")
+		ownerID, _ := command.Flags().GetString("owner")
+
+		request := &model.InstallationCloneRequest{
+			DNS:     dns,
+			OwnerID: ownerID,
+		}
+
+		dryRun, _ := command.Flags().GetBool("dry-run")
+		if dryRun {
+			return runDryRun(request)
+		}
+
+		cloneOperation, err := client.CloneInstallation(installationID, request)
+		if err != nil {
+			return errors.Wrap(err, "failed to request installation clone")
+		}
+
+		return printJSON(cloneOperation)
+	},
+}
+
+var installationClonesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List installation clone operations.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		installationID, _ := command.Flags().GetString("installation")
+		cloneInstallationID, _ := command.Flags().GetString("clone-installation")
+		state, _ := command.Flags().GetString("state")
+		paging := parsePagingFlags(command)
+
+		request := &model.GetInstallationCloneOperationsRequest{
+			Paging:              paging,
+			InstallationID:      installationID,
+			CloneInstallationID: cloneInstallationID,
+			State:               state,
+		}
+
+		cloneOperations, err := client.GetInstallationCloneOperations(request)
+		if err != nil {
+			return errors.Wrap(err, "failed to list installation clone operations")
+		}
+
+		outputToTable, _ := command.Flags().GetBool("table")
+		if outputToTable {
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetHeader([]string{"ID", "INSTALLATION ID", "CLONE INSTALLATION ID", "BACKUP ID", "STATE", "REQUEST AT"})
+
+			for _, cloneOp := range cloneOperations {
+				table.Append([]string{
+					cloneOp.ID,
+					cloneOp.InstallationID,
+					cloneOp.CloneInstallationID,
+					cloneOp.BackupID,
+					string(cloneOp.State),
+					utils.TimeFromMillis(cloneOp.RequestAt).Format("2006-01-02 15:04:05 -0700 MST"),
+				})
+			}
+			table.Render()
+
+			return nil
+		}
+
+		return printJSON(cloneOperations)
+	},
+}
+
+var installationCloneGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Get installation clone operation.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		cloneID, _ := command.Flags().GetString("clone")
+
+		cloneOperation, err := client.GetInstallationCloneOperation(cloneID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get installation clone operation")
+		}
+
+		return printJSON(cloneOperation)
+	},
+}
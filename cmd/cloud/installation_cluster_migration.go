@@ -0,0 +1,142 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"os"
+
+	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	installationClusterMigrationRequestCmd.Flags().String("installation", "", "The id of the installation to migrate.")
+	installationClusterMigrationRequestCmd.Flags().String("target-cluster", "", "The id of the cluster to which the installation is migrated.")
+	installationClusterMigrationRequestCmd.MarkFlagRequired("installation")
+	installationClusterMigrationRequestCmd.MarkFlagRequired("target-cluster")
+
+	installationClusterMigrationsListCmd.Flags().String("installation", "", "The id of the installation to query operations.")
+	installationClusterMigrationsListCmd.Flags().String("source-cluster", "", "The id of the source cluster to query operations.")
+	installationClusterMigrationsListCmd.Flags().String("target-cluster", "", "The id of the target cluster to query operations.")
+	installationClusterMigrationsListCmd.Flags().String("state", "", "The state to filter operations by.")
+	registerPagingFlags(installationClusterMigrationsListCmd)
+	installationClusterMigrationsListCmd.Flags().Bool("table", false, "Whether to display the returned migration operations list in a table or not.")
+
+	installationClusterMigrationGetCmd.Flags().String("cluster-migration", "", "The id of the cluster migration operation.")
+	installationClusterMigrationGetCmd.MarkFlagRequired("cluster-migration")
+
+	installationClusterMigrationOperationCmd.AddCommand(installationClusterMigrationRequestCmd)
+	installationClusterMigrationOperationCmd.AddCommand(installationClusterMigrationsListCmd)
+	installationClusterMigrationOperationCmd.AddCommand(installationClusterMigrationGetCmd)
+}
+
+var installationClusterMigrationOperationCmd = &cobra.Command{
+	Use:   "cluster-migration",
+	Short: "Manipulate installation cluster migration operations managed by the provisioning server.",
+}
+
+var installationClusterMigrationRequestCmd = &cobra.Command{
+	Use:   "request",
+	Short: "Request installation migration to a different cluster.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		installationID, _ := command.Flags().GetString("installation")
+		targetClusterID, _ := command.Flags().GetString("target-cluster")
+
+		request := &model.InstallationClusterMigrationRequest{
+			InstallationID:  installationID,
+			TargetClusterID: targetClusterID,
+		}
+
+		dryRun, _ := command.Flags().GetBool("dry-run")
+		if dryRun {
+			return runDryRun(request)
+		}
+
+		migrationOperation, err := client.MigrateInstallationCluster(request)
+		if err != nil {
+			return errors.Wrap(err, "failed to request installation cluster migration")
+		}
+
+		return printJSON(migrationOperation)
+	},
+}
+
+var installationClusterMigrationsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List installation cluster migration operations.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		installationID, _ := command.Flags().GetString("installation")
+		sourceClusterID, _ := command.Flags().GetString("source-cluster")
+		targetClusterID, _ := command.Flags().GetString("target-cluster")
+		state, _ := command.Flags().GetString("state")
+		paging := parsePagingFlags(command)
+
+		request := &model.GetInstallationClusterMigrationOperationsRequest{
+			Paging:          paging,
+			InstallationID:  installationID,
+			SourceClusterID: sourceClusterID,
+			TargetClusterID: targetClusterID,
+			State:           state,
+		}
+
+		migrationOperations, err := client.GetInstallationClusterMigrationOperations(request)
+		if err != nil {
+			return errors.Wrap(err, "failed to list installation cluster migration operations")
+		}
+
+		outputToTable, _ := command.Flags().GetBool("table")
+		if outputToTable {
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetHeader([]string{"ID", "INSTALLATION ID", "SOURCE CLUSTER ID", "TARGET CLUSTER ID", "STATE", "REQUEST AT"})
+
+			for _, migration := range migrationOperations {
+				table.Append([]string{
+					migration.ID,
+					migration.InstallationID,
+					migration.SourceClusterID,
+					migration.TargetClusterID,
+					string(migration.State),
+					utils.TimeFromMillis(migration.RequestAt).Format("2006-01-02 15:04:05 -0700 MST"),
+				})
+			}
+			table.Render()
+
+			return nil
+		}
+
+		return printJSON(migrationOperations)
+	},
+}
+
+var installationClusterMigrationGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Get installation cluster migration operation.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		migrationID, _ := command.Flags().GetString("cluster-migration")
+
+		migrationOperation, err := client.GetInstallationClusterMigrationOperation(migrationID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get installation cluster migration operation")
+		}
+
+		return printJSON(migrationOperation)
+	},
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"os"
+
+	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	installationDBMigrationRequestCmd.Flags().String("installation", "", "The id of the installation to migrate.")
+	installationDBMigrationRequestCmd.Flags().String("destination-db", model.InstallationDatabaseMultiTenantRDSPostgres, "The destination database type.")
+	installationDBMigrationRequestCmd.Flags().String("multi-tenant-db", "", "The id of the destination multi-tenant database.")
+	installationDBMigrationRequestCmd.MarkFlagRequired("installation")
+	installationDBMigrationRequestCmd.MarkFlagRequired("multi-tenant-db")
+
+	installationDBMigrationsListCmd.Flags().String("installation", "", "The id of the installation to query operations.")
+	installationDBMigrationsListCmd.Flags().String("state", "", "The state to filter operations by.")
+	registerPagingFlags(installationDBMigrationsListCmd)
+	installationDBMigrationsListCmd.Flags().Bool("table", false, "Whether to display the returned migration operations list in a table or not.")
+
+	installationDBMigrationGetCmd.Flags().String("db-migration", "", "The id of the db migration operation.")
+	installationDBMigrationGetCmd.MarkFlagRequired("db-migration")
+
+	installationDBMigrationCancelCmd.Flags().String("db-migration", "", "The id of the db migration operation to cancel.")
+	installationDBMigrationCancelCmd.MarkFlagRequired("db-migration")
+
+	installationDBMigrationOperationCmd.AddCommand(installationDBMigrationRequestCmd)
+	installationDBMigrationOperationCmd.AddCommand(installationDBMigrationsListCmd)
+	installationDBMigrationOperationCmd.AddCommand(installationDBMigrationGetCmd)
+	installationDBMigrationOperationCmd.AddCommand(installationDBMigrationCancelCmd)
+}
+
+var installationDBMigrationOperationCmd = &cobra.Command{
+	Use:   "db-migration",
+	Short: "Manipulate installation db migration operations managed by the provisioning server.",
+}
+
+var installationDBMigrationRequestCmd = &cobra.Command{
+	Use:   "request",
+	Short: "Request database migration to different DB.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		installationID, _ := command.Flags().GetString("installation")
+		destinationDB, _ := command.Flags().GetString("destination-db")
+		multiTenantDBID, _ := command.Flags().GetString("multi-tenant-db")
+
+		request := &model.InstallationDBMigrationRequest{
+			InstallationID:         installationID,
+			DestinationDatabase:    destinationDB,
+			DestinationMultiTenant: &model.MultiTenantDBMigrationData{DatabaseID: multiTenantDBID},
+		}
+
+		dryRun, _ := command.Flags().GetBool("dry-run")
+		if dryRun {
+			return runDryRun(request)
+		}
+
+		migrationOperation, err := client.MigrateInstallationDatabase(request)
+		if err != nil {
+			return errors.Wrap(err, "failed to request installation database migration")
+		}
+
+		return printJSON(migrationOperation)
+	},
+}
+
+var installationDBMigrationsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List installation database migration operations.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		installationID, _ := command.Flags().GetString("installation")
+		state, _ := command.Flags().GetString("state")
+		paging := parsePagingFlags(command)
+
+		request := &model.GetInstallationDBMigrationOperationsRequest{
+			Paging:         paging,
+			InstallationID: installationID,
+			State:          state,
+		}
+
+		dbMigrationOperations, err := client.GetInstallationDBMigrationOperations(request)
+		if err != nil {
+			return errors.Wrap(err, "failed to list installation database migration operations")
+		}
+
+		outputToTable, _ := command.Flags().GetBool("table")
+		if outputToTable {
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetHeader([]string{"ID", "INSTALLATION ID", "STATE", "SOURCE DB", "DESTINATION DB", "REQUEST AT"})
+
+			for _, migration := range dbMigrationOperations {
+				table.Append([]string{
+					migration.ID,
+					migration.InstallationID,
+					string(migration.State),
+					migrationDatabaseID(migration.SourceMultiTenant),
+					migrationDatabaseID(migration.DestinationMultiTenant),
+					utils.TimeFromMillis(migration.RequestAt).Format("2006-01-02 15:04:05 -0700 MST"),
+				})
+			}
+			table.Render()
+
+			return nil
+		}
+
+		return printJSON(dbMigrationOperations)
+	},
+}
+
+var installationDBMigrationGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Get installation database migration operation.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		dbMigrationID, _ := command.Flags().GetString("db-migration")
+
+		dbMigrationOperation, err := client.GetInstallationDBMigrationOperation(dbMigrationID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get installation database migration")
+		}
+
+		return printJSON(dbMigrationOperation)
+	},
+}
+
+var installationDBMigrationCancelCmd = &cobra.Command{
+	Use:   "cancel",
+	Short: "Cancel installation database migration operation that has not started yet.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		dbMigrationID, _ := command.Flags().GetString("db-migration")
+
+		dbMigrationOperation, err := client.CancelInstallationDBMigration(dbMigrationID)
+		if err != nil {
+			return errors.Wrap(err, "failed to cancel installation database migration")
+		}
+
+		return printJSON(dbMigrationOperation)
+	},
+}
+
+func migrationDatabaseID(data *model.MultiTenantDBMigrationData) string {
+	if data == nil {
+		return ""
+	}
+	return data.DatabaseID
+}
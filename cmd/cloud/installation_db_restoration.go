@@ -0,0 +1,153 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"os"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	installationDBRestorationRequestCmd.Flags().String("installation", "", "The id of the installation to restore.")
+	installationDBRestorationRequestCmd.Flags().String("backup", "", "The id of the backup to restore. The backup may belong to a different installation.")
+	installationDBRestorationRequestCmd.Flags().String("restore-time", "", "The RFC3339 time to which the database is restored natively, instead of restoring a backup. Must be within the 7 days backup retention period.")
+	installationDBRestorationRequestCmd.Flags().String("source-installation", "", "The id of the installation whose database is restored to the restore time. Defaults to the restored installation.")
+	installationDBRestorationRequestCmd.MarkFlagRequired("installation")
+
+	installationDBRestorationsListCmd.Flags().String("installation", "", "The id of the installation to query operations.")
+	installationDBRestorationsListCmd.Flags().String("cluster-installation", "", "The id of the cluster installation to query operations.")
+	installationDBRestorationsListCmd.Flags().String("state", "", "The state to filter operations by.")
+	registerPagingFlags(installationDBRestorationsListCmd)
+	installationDBRestorationsListCmd.Flags().Bool("table", false, "Whether to display the returned restoration operations list in a table or not.")
+
+	installationDBRestorationGetCmd.Flags().String("db-restoration", "", "The id of the db restoration operation.")
+	installationDBRestorationGetCmd.MarkFlagRequired("db-restoration")
+
+	installationDBRestorationOperationCmd.AddCommand(installationDBRestorationRequestCmd)
+	installationDBRestorationOperationCmd.AddCommand(installationDBRestorationsListCmd)
+	installationDBRestorationOperationCmd.AddCommand(installationDBRestorationGetCmd)
+}
+
+var installationDBRestorationOperationCmd = &cobra.Command{
+	Use:   "db-restoration",
+	Short: "Manipulate installation db restoration operations managed by the provisioning server.",
+}
+
+var installationDBRestorationRequestCmd = &cobra.Command{
+	Use:   "request",
+	Short: "Request database restoration from a backup or to a point in time.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		installationID, _ := command.Flags().GetString("installation")
+		backupID, _ := command.Flags().GetString("backup")
+		restoreTime, _ := command.Flags().GetString("restore-time")
+		sourceInstallationID, _ := command.Flags().GetString("source-installation")
+
+		request := &model.InstallationDBRestorationRequest{
+			InstallationID: installationID,
+			BackupID:       backupID,
+		}
+		if restoreTime != "" {
+			parsedTime, err := time.Parse(time.RFC3339, restoreTime)
+			if err != nil {
+				return errors.Wrap(err, "failed to parse restore time")
+			}
+			request.Mode = model.InstallationDBRestorationModePointInTime
+			request.SourceInstallationID = sourceInstallationID
+			request.RestoreTime = utils.GetMillisAtTime(parsedTime)
+		}
+
+		dryRun, _ := command.Flags().GetBool("dry-run")
+		if dryRun {
+			return runDryRun(request)
+		}
+
+		restorationOperation, err := client.RequestInstallationDBRestoration(request)
+		if err != nil {
+			return errors.Wrap(err, "failed to request installation database restoration")
+		}
+
+		return printJSON(restorationOperation)
+	},
+}
+
+var installationDBRestorationsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List installation database restoration operations.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		installationID, _ := command.Flags().GetString("installation")
+		clusterInstallationID, _ := command.Flags().GetString("cluster-installation")
+		state, _ := command.Flags().GetString("state")
+		paging := parsePagingFlags(command)
+
+		request := &model.GetInstallationDBRestorationOperationsRequest{
+			Paging:                paging,
+			InstallationID:        installationID,
+			ClusterInstallationID: clusterInstallationID,
+			State:                 state,
+		}
+
+		dbRestorationOperations, err := client.GetInstallationDBRestorationOperations(request)
+		if err != nil {
+			return errors.Wrap(err, "failed to list installation database restoration operations")
+		}
+
+		outputToTable, _ := command.Flags().GetBool("table")
+		if outputToTable {
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetHeader([]string{"ID", "INSTALLATION ID", "MODE", "SOURCE INSTALLATION ID", "BACKUP ID", "STATE", "REQUEST AT"})
+
+			for _, restoration := range dbRestorationOperations {
+				table.Append([]string{
+					restoration.ID,
+					restoration.InstallationID,
+					string(restoration.Mode),
+					restoration.SourceInstallationID,
+					restoration.BackupID,
+					string(restoration.State),
+					utils.TimeFromMillis(restoration.RequestAt).Format("2006-01-02 15:04:05 -0700 MST"),
+				})
+			}
+			table.Render()
+
+			return nil
+		}
+
+		return printJSON(dbRestorationOperations)
+	},
+}
+
+var installationDBRestorationGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Get installation database restoration operation.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		dbRestorationID, _ := command.Flags().GetString("db-restoration")
+
+		dbRestorationOperation, err := client.GetInstallationDBRestoration(dbRestorationID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get installation database restoration")
+		}
+
+		return printJSON(dbRestorationOperation)
+	},
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"strings"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	installationSchedulingEvaluateCmd.Flags().String("installation", "", "The id of the installation to evaluate scheduling for.")
+	installationSchedulingEvaluateCmd.Flags().String("policy", "", "The scheduling policy to evaluate. One of "+strings.Join(model.AllSchedulingPolicies, ", ")+". Defaults to the installation policy.")
+	installationSchedulingEvaluateCmd.MarkFlagRequired("installation")
+
+	installationSchedulingCmd.AddCommand(installationSchedulingEvaluateCmd)
+}
+
+var installationSchedulingCmd = &cobra.Command{
+	Use:   "scheduling",
+	Short: "Inspect installation scheduling decisions of the provisioning server.",
+}
+
+var installationSchedulingEvaluateCmd = &cobra.Command{
+	Use:   "evaluate",
+	Short: "Show which cluster the installation would be scheduled on and why, without making any changes.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		installationID, _ := command.Flags().GetString("installation")
+		policy, _ := command.Flags().GetString("policy")
+
+		result, err := client.DryRunInstallationScheduling(installationID, policy)
+		if err != nil {
+			return errors.Wrap(err, "failed to evaluate installation scheduling")
+		}
+
+		return printJSON(result)
+	},
+}
@@ -23,6 +23,7 @@ var rootCmd = &cobra.Command{
 
 func init() {
 	rootCmd.MarkFlagRequired("database")
+	rootCmd.PersistentFlags().String("api-key", os.Getenv("CLOUD_API_KEY"), "The API key used to authenticate with the provisioning server. Defaults to the CLOUD_API_KEY environment variable.")
 
 	rootCmd.AddCommand(serverCmd)
 	rootCmd.AddCommand(clusterCmd)
@@ -35,6 +36,8 @@ func init() {
 	rootCmd.AddCommand(workbenchCmd)
 	rootCmd.AddCommand(completionCmd)
 	rootCmd.AddCommand(dashboardCmd)
+	rootCmd.AddCommand(apiKeyCmd)
+	rootCmd.AddCommand(eventCmd)
 }
 
 func main() {
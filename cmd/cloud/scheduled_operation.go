@@ -0,0 +1,195 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"os"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	scheduledOperationCreateCmd.Flags().String("installation", "", "The id of the installation to schedule the operation for.")
+	scheduledOperationCreateCmd.Flags().String("type", "", "The type of the operation. One of wake-up, hibernate, update or delete.")
+	scheduledOperationCreateCmd.Flags().String("run-at", "", "The RFC3339 time at which the operation runs.")
+	scheduledOperationCreateCmd.Flags().Duration("interval", 0, "The time between two runs of a recurring operation. The operation runs only once if not set.")
+	scheduledOperationCreateCmd.Flags().String("image", "", "The Mattermost container image set by an update operation.")
+	scheduledOperationCreateCmd.Flags().String("version", "", "The Mattermost version set by an update operation.")
+	scheduledOperationCreateCmd.Flags().String("size", "", "The size of the installation set by an update operation.")
+	scheduledOperationCreateCmd.Flags().String("license", "", "The Mattermost License set by an update operation.")
+	scheduledOperationCreateCmd.Flags().StringArray("mattermost-env", []string{}, "Env vars set by an update operation. Accepts format: KEY_NAME=VALUE. Use the flag multiple times to set multiple env vars.")
+	scheduledOperationCreateCmd.MarkFlagRequired("installation")
+	scheduledOperationCreateCmd.MarkFlagRequired("type")
+	scheduledOperationCreateCmd.MarkFlagRequired("run-at")
+
+	scheduledOperationListCmd.Flags().String("installation", "", "The installation id for which the scheduled operations should be listed.")
+	scheduledOperationListCmd.Flags().String("state", "", "The state to filter scheduled operations by.")
+	registerPagingFlags(scheduledOperationListCmd)
+	scheduledOperationListCmd.Flags().Bool("table", false, "Whether to display the returned scheduled operation list in a table or not.")
+
+	scheduledOperationGetCmd.Flags().String("scheduled-operation", "", "The id of the scheduled operation to get.")
+	scheduledOperationGetCmd.MarkFlagRequired("scheduled-operation")
+
+	scheduledOperationCancelCmd.Flags().String("scheduled-operation", "", "The id of the scheduled operation to cancel.")
+	scheduledOperationCancelCmd.MarkFlagRequired("scheduled-operation")
+
+	scheduledOperationCmd.AddCommand(scheduledOperationCreateCmd)
+	scheduledOperationCmd.AddCommand(scheduledOperationListCmd)
+	scheduledOperationCmd.AddCommand(scheduledOperationGetCmd)
+	scheduledOperationCmd.AddCommand(scheduledOperationCancelCmd)
+}
+
+var scheduledOperationCmd = &cobra.Command{
+	Use:   "scheduled-operation",
+	Short: "Manipulate installation operations scheduled on the provisioning server.",
+}
+
+var scheduledOperationCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Schedule an installation state change.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		installationID, _ := command.Flags().GetString("installation")
+		operationType, _ := command.Flags().GetString("type")
+		runAt, _ := command.Flags().GetString("run-at")
+		interval, _ := command.Flags().GetDuration("interval")
+		mattermostEnv, _ := command.Flags().GetStringArray("mattermost-env")
+
+		parsedRunAt, err := time.Parse(time.RFC3339, runAt)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse run-at time")
+		}
+
+		request := &model.CreateScheduledOperationRequest{
+			InstallationID:  installationID,
+			Type:            model.ScheduledOperationType(operationType),
+			RunAt:           parsedRunAt.UnixNano() / int64(time.Millisecond),
+			IntervalSeconds: int64(interval.Seconds()),
+		}
+
+		if request.Type == model.ScheduledOperationTypeUpdate {
+			envVarMap, err := parseEnvVarInput(mattermostEnv, false)
+			if err != nil {
+				return err
+			}
+
+			request.Patch = &model.PatchInstallationRequest{
+				Version:       getStringFlagPointer(command, "version"),
+				Image:         getStringFlagPointer(command, "image"),
+				Size:          getStringFlagPointer(command, "size"),
+				License:       getStringFlagPointer(command, "license"),
+				MattermostEnv: envVarMap,
+			}
+		}
+
+		dryRun, _ := command.Flags().GetBool("dry-run")
+		if dryRun {
+			return runDryRun(request)
+		}
+
+		operation, err := client.CreateScheduledOperation(request)
+		if err != nil {
+			return errors.Wrap(err, "failed to create scheduled operation")
+		}
+
+		return printJSON(operation)
+	},
+}
+
+var scheduledOperationListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List scheduled operations.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		installationID, _ := command.Flags().GetString("installation")
+		state, _ := command.Flags().GetString("state")
+		paging := parsePagingFlags(command)
+
+		operations, err := client.GetScheduledOperations(&model.GetScheduledOperationsRequest{
+			InstallationID: installationID,
+			State:          state,
+			Paging:         paging,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to get scheduled operations")
+		}
+
+		outputToTable, _ := command.Flags().GetBool("table")
+		if outputToTable {
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetHeader([]string{"ID", "INSTALLATION ID", "TYPE", "STATE", "INTERVAL", "RUN AT"})
+
+			for _, operation := range operations {
+				interval := "once"
+				if operation.IsRecurring() {
+					interval = operation.Interval().String()
+				}
+				table.Append([]string{
+					operation.ID,
+					operation.InstallationID,
+					string(operation.Type),
+					string(operation.State),
+					interval,
+					utils.TimeFromMillis(operation.RunAt).Format("2006-01-02 15:04:05 -0700 MST"),
+				})
+			}
+			table.Render()
+
+			return nil
+		}
+
+		return printJSON(operations)
+	},
+}
+
+var scheduledOperationGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Get scheduled operation.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		operationID, _ := command.Flags().GetString("scheduled-operation")
+
+		operation, err := client.GetScheduledOperation(operationID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get scheduled operation")
+		}
+
+		return printJSON(operation)
+	},
+}
+
+var scheduledOperationCancelCmd = &cobra.Command{
+	Use:   "cancel",
+	Short: "Cancel scheduled operation. Installation state changes already requested are not reverted.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		operationID, _ := command.Flags().GetString("scheduled-operation")
+
+		err := client.CancelScheduledOperation(operationID)
+		if err != nil {
+			return errors.Wrap(err, "failed to cancel scheduled operation")
+		}
+
+		return nil
+	},
+}
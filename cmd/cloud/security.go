@@ -5,7 +5,6 @@
 package main
 
 import (
-	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -65,8 +64,7 @@ var securityClusterLockAPICmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		clusterID, _ := command.Flags().GetString("cluster")
 		err := client.LockAPIForCluster(clusterID)
@@ -84,8 +82,7 @@ var securityClusterUnlockAPICmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		clusterID, _ := command.Flags().GetString("cluster")
 		err := client.UnlockAPIForCluster(clusterID)
@@ -108,8 +105,7 @@ var securityInstallationLockAPICmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		installationID, _ := command.Flags().GetString("installation")
 		err := client.LockAPIForInstallation(installationID)
@@ -127,8 +123,7 @@ var securityInstallationUnlockAPICmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		installationID, _ := command.Flags().GetString("installation")
 		err := client.UnlockAPIForInstallation(installationID)
@@ -151,8 +146,7 @@ var securityClusterInstallationLockAPICmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		clusterInstallationID, _ := command.Flags().GetString("cluster-installation")
 		err := client.LockAPIForClusterInstallation(clusterInstallationID)
@@ -170,8 +164,7 @@ var securityClusterInstallationUnlockAPICmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		clusterInstallationID, _ := command.Flags().GetString("cluster-installation")
 		err := client.UnlockAPIForClusterInstallation(clusterInstallationID)
@@ -194,8 +187,7 @@ var securityGroupLockAPICmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		groupID, _ := command.Flags().GetString("group")
 		err := client.LockAPIForGroup(groupID)
@@ -213,8 +205,7 @@ var securityGroupUnlockAPICmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		groupID, _ := command.Flags().GetString("group")
 		err := client.UnlockAPIForGroup(groupID)
@@ -237,8 +228,7 @@ var securityBackupLockAPICmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		backupID, _ := command.Flags().GetString("backup")
 		err := client.LockAPIForBackup(backupID)
@@ -256,8 +246,7 @@ var securityBackupUnlockAPICmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		backupID, _ := command.Flags().GetString("backup")
 		err := client.UnlockAPIForBackup(backupID)
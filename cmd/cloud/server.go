@@ -21,17 +21,21 @@ import (
 	"github.com/gorilla/mux"
 	awat "github.com/mattermost/awat/model"
 	"github.com/mattermost/mattermost-cloud/internal/api"
+	"github.com/mattermost/mattermost-cloud/internal/common"
+	"github.com/mattermost/mattermost-cloud/internal/fake"
 	"github.com/mattermost/mattermost-cloud/internal/metrics"
 	"github.com/mattermost/mattermost-cloud/internal/provisioner"
 	"github.com/mattermost/mattermost-cloud/internal/store"
 	"github.com/mattermost/mattermost-cloud/internal/supervisor"
 	toolsAWS "github.com/mattermost/mattermost-cloud/internal/tools/aws"
+	"github.com/mattermost/mattermost-cloud/internal/tools/cloud"
 	"github.com/mattermost/mattermost-cloud/internal/tools/helm"
 	"github.com/mattermost/mattermost-cloud/internal/tools/kops"
 	"github.com/mattermost/mattermost-cloud/internal/tools/terraform"
 	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
 	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
 	logrus "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -53,7 +57,9 @@ func init() {
 	serverCmd.PersistentFlags().Bool("debug-helm", false, "Whether to include Helm output in debug logs.")
 	serverCmd.PersistentFlags().Bool("machine-readable-logs", false, "Output the logs in machine readable format.")
 	serverCmd.PersistentFlags().Bool("dev", false, "Set sane defaults for development")
+	serverCmd.PersistentFlags().Bool("api-authentication", false, "Whether to require an API key for all API requests or not.")
 	serverCmd.PersistentFlags().String("backup-restore-tool-image", "mattermost/backup-restore-tool:latest", "Image of Backup Restore Tool to use.")
+	serverCmd.PersistentFlags().String("backup-filestore-tool-image", "amazon/aws-cli:2.2.46", "Image used to copy installation files during backup and restore. Set to empty value to not include files in backups.")
 	serverCmd.PersistentFlags().Int32("backup-job-ttl-seconds", 3600, "Number of seconds after which finished backup jobs will be cleaned up. Set to negative value to not cleanup or 0 to cleanup immediately.")
 
 	// Supervisors
@@ -63,20 +69,72 @@ func init() {
 	serverCmd.PersistentFlags().Bool("installation-supervisor", true, "Whether this server will run an installation supervisor or not.")
 	serverCmd.PersistentFlags().Bool("cluster-installation-supervisor", true, "Whether this server will run a cluster installation supervisor or not.")
 	serverCmd.PersistentFlags().Bool("backup-supervisor", false, "Whether this server will run a backup supervisor or not.")
+	serverCmd.PersistentFlags().Bool("backup-schedule-supervisor", false, "Whether this server will run a backup schedule supervisor requesting scheduled backups and expiring old ones or not.")
 	serverCmd.PersistentFlags().Bool("import-supervisor", false, "Whether this server will run a workspace import supervisor or not.")
 	serverCmd.PersistentFlags().String("awat", "http://localhost:8077", "The location of the Automatic Workspace Archive Translator if the import supervisor is being used.")
 	serverCmd.PersistentFlags().Bool("installation-restoration-supervisor", false, "Whether this server will run an installation restoration supervisor or not.")
+	serverCmd.PersistentFlags().Bool("db-migration-supervisor", false, "Whether this server will run an installation db migration supervisor or not.")
+	serverCmd.PersistentFlags().Bool("installation-clone-supervisor", false, "Whether this server will run an installation clone supervisor or not.")
+	serverCmd.PersistentFlags().Bool("cluster-migration-supervisor", false, "Whether this server will run an installation cluster migration supervisor or not.")
+	serverCmd.PersistentFlags().Bool("cluster-drain-supervisor", false, "Whether this server will run a cluster drain supervisor or not.")
+	serverCmd.PersistentFlags().Bool("cluster-capacity-supervisor", false, "Whether this server will run a cluster capacity supervisor scaling clusters to installation demand or not.")
+	serverCmd.PersistentFlags().Bool("idle-hibernation-supervisor", false, "Whether this server will run an idle hibernation supervisor hibernating installations without active users or not.")
+	serverCmd.PersistentFlags().Bool("scheduled-operation-supervisor", false, "Whether this server will run a scheduled operation supervisor requesting scheduled installation state changes or not.")
+	serverCmd.PersistentFlags().Bool("bulk-operation-supervisor", false, "Whether this server will run a bulk operation supervisor applying bulk operations to installations or not.")
+	serverCmd.PersistentFlags().Bool("webhook-delivery-supervisor", true, "Whether this server will run a webhook delivery supervisor retrying failed webhooks or not.")
+	serverCmd.PersistentFlags().Duration("webhook-delivery-max-age", 24*time.Hour, "The maximum age of a webhook delivery after which failed deliveries are no longer retried.")
+	serverCmd.PersistentFlags().Duration("webhook-delivery-retention", 7*24*time.Hour, "The age after which delivered and failed webhook deliveries are deleted by the webhook delivery supervisor.")
 
 	// Scheduling and installation options
 	serverCmd.PersistentFlags().Bool("balanced-installation-scheduling", false, "Whether to schedule installations on the cluster with the greatest percentage of available resources or not. (slows down scheduling speed as cluster count increases)")
 	serverCmd.PersistentFlags().Int("cluster-resource-threshold", 80, "The percent threshold where new installations won't be scheduled on a multi-tenant cluster.")
 	serverCmd.PersistentFlags().Int("cluster-resource-threshold-scale-value", 0, "The number of worker nodes to scale up by when the threshold is passed. Set to 0 for no scaling. Scaling will never exceed the cluster max worker configuration value.")
+	serverCmd.PersistentFlags().String("installation-scheduling-policy", "", "The default policy used to select clusters for new installations. One of "+strings.Join(model.AllSchedulingPolicies, ", ")+". Defaults to spread with balanced-installation-scheduling and first-fit otherwise. (every policy, including first-fit, queries resources of all clusters which slows down scheduling speed as cluster count increases)")
+	serverCmd.PersistentFlags().Int("cluster-capacity-scale-up-threshold", 80, "The percent of requested cluster CPU or memory above which the cluster capacity supervisor adds worker nodes.")
+	serverCmd.PersistentFlags().Int("cluster-capacity-scale-down-threshold", 30, "The percent of requested cluster CPU and memory below which the cluster capacity supervisor removes worker nodes.")
+	serverCmd.PersistentFlags().Int("cluster-capacity-scale-step", 1, "The number of worker nodes added or removed by the cluster capacity supervisor at a time.")
+	serverCmd.PersistentFlags().Int("cluster-capacity-min-node-count", 2, "The number of worker nodes below which the cluster capacity supervisor does not remove worker nodes.")
+	serverCmd.PersistentFlags().String("cluster-capacity-template", "", "The path to a JSON create cluster request used by the cluster capacity supervisor to create a new cluster when all clusters are full. No clusters are created if not set.")
+	serverCmd.PersistentFlags().Duration("idle-hibernation-period", 7*24*time.Hour, "The period without active users after which the idle hibernation supervisor hibernates an installation.")
+	serverCmd.PersistentFlags().Duration("idle-hibernation-notice", 24*time.Hour, "The period between the idle hibernation webhook notice and the hibernation of an idle installation.")
+	serverCmd.PersistentFlags().Duration("idle-hibernation-check-interval", 15*time.Minute, "The interval at which the idle hibernation supervisor checks the activity of installations.")
 	serverCmd.PersistentFlags().Bool("use-existing-aws-resources", true, "Whether to use existing AWS resources (VPCs, subnets, etc.) or not.")
 	serverCmd.PersistentFlags().Bool("keep-database-data", true, "Whether to preserve database data after installation deletion or not.")
 	serverCmd.PersistentFlags().Bool("keep-filestore-data", true, "Whether to preserve filestore data after installation deletion or not.")
 	serverCmd.PersistentFlags().Bool("require-annotated-installations", false, "Require new installations to have at least one annotation.")
 	serverCmd.PersistentFlags().String("gitlab-oauth", "", "If Helm charts are stored in a Gitlab instance that requires authentication, provide the token here and it will be automatically set in the environment.")
 	serverCmd.PersistentFlags().Bool("force-cr-upgrade", false, "If specified installation CRVersions will be updated to the latest version when supervised.")
+
+	// Fake provisioner
+	serverCmd.PersistentFlags().Bool("fake-provisioner", false, "Whether to use an in-memory fake provisioner and AWS client instead of real cloud resources. Intended for development and end-to-end tests.")
+	serverCmd.PersistentFlags().Duration("fake-provisioner-latency", 0, "The latency of every fake provisioner and AWS operation.")
+	serverCmd.PersistentFlags().Float64("fake-provisioner-failure-rate", 0, "The probability, between 0 and 1, of any fake provisioner or AWS operation failing.")
+	serverCmd.PersistentFlags().StringToInt("fake-provisioner-failures", map[string]int{}, "The number of failures to inject per fake operation, for example: CreateCluster=1,CreatePublicCNAME=2")
+}
+
+// supervisorProvisioner is the provisioner used by the supervisors and the
+// API, backed either by kops or by the in-memory fake.
+type supervisorProvisioner interface {
+	api.Provisioner
+	supervisor.BackupProvisioner
+
+	PrepareCluster(cluster *model.Cluster) bool
+	CreateCluster(cluster *model.Cluster) error
+	ProvisionCluster(cluster *model.Cluster) error
+	UpgradeCluster(cluster *model.Cluster) error
+	ResizeCluster(cluster *model.Cluster) error
+	DeleteCluster(cluster *model.Cluster) error
+	RefreshKopsMetadata(cluster *model.Cluster) error
+
+	ClusterInstallationProvisioner(version string) provisioner.ClusterInstallationProvisioner
+	GetPublicLoadBalancerEndpoint(cluster *model.Cluster, namespace string) (string, error)
+	ExecClusterInstallationJob(cluster *model.Cluster, clusterInstallation *model.ClusterInstallation, args ...string) error
+
+	TriggerRestore(installation *model.Installation, backup *model.InstallationBackup, cluster *model.Cluster) error
+	CheckRestoreStatus(installation *model.Installation, backup *model.InstallationBackup, cluster *model.Cluster) (int64, error)
+	CleanupRestoreJob(installation *model.Installation, backup *model.InstallationBackup, cluster *model.Cluster) error
+
+	Teardown()
 }
 
 var serverCmd = &cobra.Command{
@@ -149,26 +207,63 @@ var serverCmd = &cobra.Command{
 		if clusterResourceThresholdScaleValue < 0 || clusterResourceThresholdScaleValue > 10 {
 			return errors.Errorf("cluster-resource-threshold-scale-value (%d) must be set between 0 and 10", clusterResourceThresholdScaleValue)
 		}
+		installationSchedulingPolicy, _ := command.Flags().GetString("installation-scheduling-policy")
+		if installationSchedulingPolicy != "" && !model.IsSupportedSchedulingPolicy(installationSchedulingPolicy) {
+			return errors.Errorf("installation-scheduling-policy (%s) must be one of %s", installationSchedulingPolicy, strings.Join(model.AllSchedulingPolicies, ", "))
+		}
 
 		clusterSupervisor, _ := command.Flags().GetBool("cluster-supervisor")
 		groupSupervisor, _ := command.Flags().GetBool("group-supervisor")
 		installationSupervisor, _ := command.Flags().GetBool("installation-supervisor")
 		clusterInstallationSupervisor, _ := command.Flags().GetBool("cluster-installation-supervisor")
 		backupSupervisor, _ := command.Flags().GetBool("backup-supervisor")
+		backupScheduleSupervisor, _ := command.Flags().GetBool("backup-schedule-supervisor")
 		importSupervisor, _ := command.Flags().GetBool("import-supervisor")
 		installationRestorationSupervisor, _ := command.Flags().GetBool("installation-restoration-supervisor")
-		supervisorsEnabled := []bool{clusterSupervisor, installationSupervisor, clusterInstallationSupervisor, groupSupervisor, backupSupervisor, installationRestorationSupervisor, importSupervisor}
+		dbMigrationSupervisor, _ := command.Flags().GetBool("db-migration-supervisor")
+		installationCloneSupervisor, _ := command.Flags().GetBool("installation-clone-supervisor")
+		clusterMigrationSupervisor, _ := command.Flags().GetBool("cluster-migration-supervisor")
+		clusterDrainSupervisor, _ := command.Flags().GetBool("cluster-drain-supervisor")
+		clusterCapacitySupervisor, _ := command.Flags().GetBool("cluster-capacity-supervisor")
+		idleHibernationSupervisor, _ := command.Flags().GetBool("idle-hibernation-supervisor")
+		scheduledOperationSupervisor, _ := command.Flags().GetBool("scheduled-operation-supervisor")
+		bulkOperationSupervisor, _ := command.Flags().GetBool("bulk-operation-supervisor")
+		webhookDeliverySupervisor, _ := command.Flags().GetBool("webhook-delivery-supervisor")
+		supervisorsEnabled := []bool{clusterSupervisor, installationSupervisor, clusterInstallationSupervisor, groupSupervisor, backupSupervisor, backupScheduleSupervisor, installationRestorationSupervisor, importSupervisor, dbMigrationSupervisor, installationCloneSupervisor, clusterMigrationSupervisor, clusterDrainSupervisor, clusterCapacitySupervisor, idleHibernationSupervisor, scheduledOperationSupervisor, bulkOperationSupervisor}
+		// The webhook delivery supervisor is enabled by default and only
+		// retries webhooks, so it does not count towards enabled supervisors.
 		if !isAny(supervisorsEnabled) {
 			logger.Warn("Server will be running with no supervisors. Only API functionality will work.")
 		}
 
+		var clusterCapacityOptions supervisor.ClusterCapacityOptions
+		if clusterCapacitySupervisor {
+			var err error
+			clusterCapacityOptions, err = getClusterCapacityOptions(command)
+			if err != nil {
+				return err
+			}
+		}
+
+		var idleHibernationOptions supervisor.IdleHibernationOptions
+		if idleHibernationSupervisor {
+			var err error
+			idleHibernationOptions, err = getIdleHibernationOptions(command)
+			if err != nil {
+				return err
+			}
+		}
+
 		s3StateStore, _ := command.Flags().GetString("state-store")
 		keepDatabaseData, _ := command.Flags().GetBool("keep-database-data")
 		keepFilestoreData, _ := command.Flags().GetBool("keep-filestore-data")
 		useExistingResources, _ := command.Flags().GetBool("use-existing-aws-resources")
 		balancedInstallationScheduling, _ := command.Flags().GetBool("balanced-installation-scheduling")
 		backupRestoreToolImage, _ := command.Flags().GetString("backup-restore-tool-image")
+		backupFilestoreToolImage, _ := command.Flags().GetString("backup-filestore-tool-image")
 		backupJobTTL, _ := command.Flags().GetInt32("backup-job-ttl-seconds")
+		apiAuthentication, _ := command.Flags().GetBool("api-authentication")
+		fakeProvisioner, _ := command.Flags().GetBool("fake-provisioner")
 
 		wd, err := os.Getwd()
 		if err != nil {
@@ -192,20 +287,34 @@ var serverCmd = &cobra.Command{
 			"installation-supervisor":                installationSupervisor,
 			"cluster-installation-supervisor":        clusterInstallationSupervisor,
 			"backup-supervisor":                      backupSupervisor,
+			"backup-schedule-supervisor":             backupScheduleSupervisor,
 			"import-supervisor":                      importSupervisor,
 			"installation-restoration-supervisor":    installationRestorationSupervisor,
+			"db-migration-supervisor":                dbMigrationSupervisor,
+			"installation-clone-supervisor":          installationCloneSupervisor,
+			"cluster-migration-supervisor":           clusterMigrationSupervisor,
+			"cluster-drain-supervisor":               clusterDrainSupervisor,
+			"cluster-capacity-supervisor":            clusterCapacitySupervisor,
+			"idle-hibernation-supervisor":            idleHibernationSupervisor,
+			"scheduled-operation-supervisor":         scheduledOperationSupervisor,
+			"bulk-operation-supervisor":              bulkOperationSupervisor,
+			"webhook-delivery-supervisor":            webhookDeliverySupervisor,
 			"store-version":                          currentVersion,
 			"state-store":                            s3StateStore,
 			"working-directory":                      wd,
 			"balanced-installation-scheduling":       balancedInstallationScheduling,
 			"cluster-resource-threshold":             clusterResourceThreshold,
 			"cluster-resource-threshold-scale-value": clusterResourceThresholdScaleValue,
+			"installation-scheduling-policy":         installationSchedulingPolicy,
 			"use-existing-aws-resources":             useExistingResources,
 			"keep-database-data":                     keepDatabaseData,
 			"keep-filestore-data":                    keepFilestoreData,
 			"force-cr-upgrade":                       forceCRUpgrade,
 			"backup-restore-tool-image":              backupRestoreToolImage,
+			"backup-filestore-tool-image":            backupFilestoreToolImage,
 			"backup-job-ttl-seconds":                 backupJobTTL,
+			"api-authentication":                     apiAuthentication,
+			"fake-provisioner":                       fakeProvisioner,
 			"debug":                                  debugMode,
 			"dev-mode":                               devMode,
 		}).Info("Starting Mattermost Provisioning Server")
@@ -220,74 +329,136 @@ var serverCmd = &cobra.Command{
 		// best-effort attempt to tag the VPC with a human's identity for dev purposes
 		owner := getHumanReadableID()
 
-		awsRegion := os.Getenv("AWS_REGION")
-		if awsRegion == "" {
-			awsRegion = toolsAWS.DefaultAWSRegion
-		}
-		awsConfig := &sdkAWS.Config{
-			Region: sdkAWS.String(awsRegion),
-			// TODO: we should use Retryer for a more robust retry strategy.
-			// https://github.com/aws/aws-sdk-go/blob/99cd35c8c7d369ba8c32c46ed306f6c88d24cfd7/aws/request/retryer.go#L20
-			MaxRetries: sdkAWS.Int(toolsAWS.DefaultAWSClientRetries),
-		}
-		awsClient, err := toolsAWS.NewAWSClientWithConfig(awsConfig, logger)
-		if err != nil {
-			return errors.Wrap(err, "failed to build AWS client")
-		}
+		var awsClient toolsAWS.AWS
+		var cloudProvider cloud.Provider
+		var cloudProvisioner supervisorProvisioner
+		if fakeProvisioner {
+			logger.Warn("[DEV] Server is configured to use the fake provisioner; no cloud resources will be managed")
+
+			behavior := fake.NewBehavior()
+			latency, _ := command.Flags().GetDuration("fake-provisioner-latency")
+			behavior.SetDefaultLatency(latency)
+			failureRate, _ := command.Flags().GetFloat64("fake-provisioner-failure-rate")
+			behavior.SetFailureRate(failureRate)
+			failures, _ := command.Flags().GetStringToInt("fake-provisioner-failures")
+			for operation, count := range failures {
+				behavior.InjectFailures(operation, count)
+			}
 
-		err = checkRequirements(logger)
-		if err != nil {
-			return errors.Wrap(err, "failed health check")
-		}
+			fakeAWS := fake.NewAWS(behavior, logger)
+			awsClient = fakeAWS
+			cloudProvider = fake.NewCloudProvider(fakeAWS)
+			cloudProvisioner = fake.NewProvisioner(behavior, logger)
+		} else {
+			awsRegion := os.Getenv("AWS_REGION")
+			if awsRegion == "" {
+				awsRegion = toolsAWS.DefaultAWSRegion
+			}
+			awsConfig := &sdkAWS.Config{
+				Region: sdkAWS.String(awsRegion),
+				// TODO: we should use Retryer for a more robust retry strategy.
+				// https://github.com/aws/aws-sdk-go/blob/99cd35c8c7d369ba8c32c46ed306f6c88d24cfd7/aws/request/retryer.go#L20
+				MaxRetries: sdkAWS.Int(toolsAWS.DefaultAWSClientRetries),
+			}
+			client, err := toolsAWS.NewAWSClientWithConfig(awsConfig, logger)
+			if err != nil {
+				return errors.Wrap(err, "failed to build AWS client")
+			}
 
-		resourceUtil := utils.NewResourceUtil(instanceID, awsClient)
+			err = checkRequirements(logger)
+			if err != nil {
+				return errors.Wrap(err, "failed health check")
+			}
 
-		provisioningParams := provisioner.ProvisioningParams{
-			S3StateStore:            s3StateStore,
-			AllowCIDRRangeList:      allowListCIDRRange,
-			VpnCIDRList:             vpnListCIDR,
-			Owner:                   owner,
-			UseExistingAWSResources: useExistingResources,
-		}
+			resourceUtil := utils.NewResourceUtil(instanceID, client)
+			awsClient = client
+			cloudProvider = cloud.NewAWSProvider(client, resourceUtil)
 
-		// Setup the provisioner for actually effecting changes to clusters.
-		kopsProvisioner := provisioner.NewKopsProvisioner(
-			provisioningParams,
-			resourceUtil,
-			logger,
-			sqlStore,
-			provisioner.NewBackupOperator(backupRestoreToolImage, awsRegion, backupJobTTL),
-		)
-		defer kopsProvisioner.Teardown()
+			provisioningParams := provisioner.ProvisioningParams{
+				S3StateStore:            s3StateStore,
+				AllowCIDRRangeList:      allowListCIDRRange,
+				VpnCIDRList:             vpnListCIDR,
+				Owner:                   owner,
+				UseExistingAWSResources: useExistingResources,
+			}
+
+			// Setup the provisioner for actually effecting changes to clusters.
+			cloudProvisioner = provisioner.NewKopsProvisioner(
+				provisioningParams,
+				resourceUtil,
+				client,
+				cloudProvider,
+				logger,
+				sqlStore,
+				provisioner.NewBackupOperator(backupRestoreToolImage, backupFilestoreToolImage, awsRegion, backupJobTTL),
+			)
+		}
+		defer cloudProvisioner.Teardown()
+		model.RegisterProvider(cloudProvider.Name())
 
 		cloudMetrics := metrics.New()
+		sqlStore.SetMetrics(cloudMetrics)
+		prometheus.MustRegister(metrics.NewResourceStateCollector(sqlStore, logger))
+
+		scheduling := supervisor.NewInstallationSupervisorSchedulingOptions(balancedInstallationScheduling, clusterResourceThreshold, clusterResourceThresholdScaleValue, installationSchedulingPolicy)
 
 		var multiDoer supervisor.MultiDoer
 		if clusterSupervisor {
-			multiDoer = append(multiDoer, supervisor.NewClusterSupervisor(sqlStore, kopsProvisioner, awsClient, instanceID, logger))
+			multiDoer = append(multiDoer, supervisor.NewInstrumentedDoer("cluster", supervisor.NewClusterSupervisor(sqlStore, cloudProvisioner, cloudProvider, instanceID, logger, cloudMetrics), cloudMetrics))
 		}
 		if groupSupervisor {
-			multiDoer = append(multiDoer, supervisor.NewGroupSupervisor(sqlStore, instanceID, logger))
+			multiDoer = append(multiDoer, supervisor.NewInstrumentedDoer("group", supervisor.NewGroupSupervisor(sqlStore, instanceID, logger), cloudMetrics))
 		}
 		if installationSupervisor {
-			scheduling := supervisor.NewInstallationSupervisorSchedulingOptions(balancedInstallationScheduling, clusterResourceThreshold, clusterResourceThresholdScaleValue)
-			multiDoer = append(multiDoer, supervisor.NewInstallationSupervisor(sqlStore, kopsProvisioner, awsClient, instanceID, keepDatabaseData, keepFilestoreData, scheduling, resourceUtil, logger, cloudMetrics, forceCRUpgrade))
+			multiDoer = append(multiDoer, supervisor.NewInstrumentedDoer("installation", supervisor.NewInstallationSupervisor(sqlStore, cloudProvisioner, cloudProvider, instanceID, keepDatabaseData, keepFilestoreData, scheduling, logger, cloudMetrics, forceCRUpgrade), cloudMetrics))
 		}
 		if clusterInstallationSupervisor {
-			multiDoer = append(multiDoer, supervisor.NewClusterInstallationSupervisor(sqlStore, kopsProvisioner, awsClient, instanceID, logger))
+			multiDoer = append(multiDoer, supervisor.NewInstrumentedDoer("cluster-installation", supervisor.NewClusterInstallationSupervisor(sqlStore, cloudProvisioner, cloudProvider, instanceID, logger), cloudMetrics))
 		}
 		if backupSupervisor {
-			multiDoer = append(multiDoer, supervisor.NewBackupSupervisor(sqlStore, kopsProvisioner, awsClient, instanceID, logger))
+			multiDoer = append(multiDoer, supervisor.NewInstrumentedDoer("backup", supervisor.NewBackupSupervisor(sqlStore, cloudProvisioner, awsClient, instanceID, logger, cloudMetrics), cloudMetrics))
+		}
+		if backupScheduleSupervisor {
+			multiDoer = append(multiDoer, supervisor.NewInstrumentedDoer("backup-schedule", supervisor.NewBackupScheduleSupervisor(sqlStore, cloudProvider, instanceID, logger), cloudMetrics))
 		}
 		if importSupervisor {
 			awatAddress, _ := command.Flags().GetString("awat")
 			if awatAddress == "" {
 				return errors.New("--awat flag must be provided when --import-supervisor flag is provided")
 			}
-			multiDoer = append(multiDoer, supervisor.NewImportSupervisor(awsClient, awat.NewClient(awatAddress), sqlStore, kopsProvisioner, logger))
+			multiDoer = append(multiDoer, supervisor.NewInstrumentedDoer("import", supervisor.NewImportSupervisor(awsClient, awat.NewClient(awatAddress), sqlStore, cloudProvisioner, logger), cloudMetrics))
 		}
 		if installationRestorationSupervisor {
-			multiDoer = append(multiDoer, supervisor.NewInstallationDBRestorationSupervisor(sqlStore, awsClient, kopsProvisioner, instanceID, logger))
+			multiDoer = append(multiDoer, supervisor.NewInstrumentedDoer("installation-db-restoration", supervisor.NewInstallationDBRestorationSupervisor(sqlStore, awsClient, cloudProvider, cloudProvisioner, instanceID, logger), cloudMetrics))
+		}
+		if dbMigrationSupervisor {
+			multiDoer = append(multiDoer, supervisor.NewInstrumentedDoer("installation-db-migration", supervisor.NewInstallationDBMigrationSupervisor(sqlStore, awsClient, cloudProvider, instanceID, cloudProvisioner, logger), cloudMetrics))
+		}
+		if installationCloneSupervisor {
+			multiDoer = append(multiDoer, supervisor.NewInstrumentedDoer("installation-clone", supervisor.NewInstallationCloneSupervisor(sqlStore, cloudProvider, instanceID, logger), cloudMetrics))
+		}
+		if clusterMigrationSupervisor {
+			multiDoer = append(multiDoer, supervisor.NewInstrumentedDoer("installation-cluster-migration", supervisor.NewInstallationClusterMigrationSupervisor(sqlStore, cloudProvisioner, cloudProvider, instanceID, logger), cloudMetrics))
+		}
+		if clusterDrainSupervisor {
+			multiDoer = append(multiDoer, supervisor.NewInstrumentedDoer("cluster-drain", supervisor.NewClusterDrainSupervisor(sqlStore, cloudProvisioner, cloudProvider, instanceID, scheduling, logger), cloudMetrics))
+		}
+		if clusterCapacitySupervisor {
+			multiDoer = append(multiDoer, supervisor.NewInstrumentedDoer("cluster-capacity", supervisor.NewClusterCapacitySupervisor(sqlStore, cloudProvisioner, cloudProvider, instanceID, clusterCapacityOptions, logger), cloudMetrics))
+		}
+		if idleHibernationSupervisor {
+			multiDoer = append(multiDoer, supervisor.NewInstrumentedDoer("idle-hibernation", supervisor.NewIdleHibernationSupervisor(sqlStore, cloudProvisioner, cloudProvider, instanceID, idleHibernationOptions, logger), cloudMetrics))
+		}
+		if scheduledOperationSupervisor {
+			multiDoer = append(multiDoer, supervisor.NewInstrumentedDoer("scheduled-operation", supervisor.NewScheduledOperationSupervisor(sqlStore, cloudProvider, instanceID, logger), cloudMetrics))
+		}
+		if bulkOperationSupervisor {
+			multiDoer = append(multiDoer, supervisor.NewInstrumentedDoer("bulk-operation", supervisor.NewBulkOperationSupervisor(sqlStore, cloudProvider, instanceID, logger), cloudMetrics))
+		}
+		if webhookDeliverySupervisor {
+			webhookDeliveryMaxAge, _ := command.Flags().GetDuration("webhook-delivery-max-age")
+			webhookDeliveryRetention, _ := command.Flags().GetDuration("webhook-delivery-retention")
+			multiDoer = append(multiDoer, supervisor.NewInstrumentedDoer("webhook-delivery", supervisor.NewWebhookDeliverySupervisor(sqlStore, webhookDeliveryMaxAge, webhookDeliveryRetention, instanceID, logger), cloudMetrics))
 		}
 
 		// Setup the supervisor to effect any requested changes. It is wrapped in a
@@ -304,11 +475,13 @@ var serverCmd = &cobra.Command{
 		router := mux.NewRouter()
 
 		api.Register(router, &api.Context{
-			Store:       sqlStore,
-			Supervisor:  supervisor,
-			Provisioner: kopsProvisioner,
-			Environment: awsClient.GetCloudEnvironmentName(),
-			Logger:      logger,
+			Store:                 sqlStore,
+			Supervisor:            supervisor,
+			Provisioner:           cloudProvisioner,
+			Scheduler:             common.NewInstallationScheduler(sqlStore, cloudProvisioner, scheduling.SchedulerOptions(), logger),
+			Environment:           awsClient.GetCloudEnvironmentName(),
+			RequireAuthentication: apiAuthentication,
+			Logger:                logger,
 		})
 
 		listen, _ := command.Flags().GetString("listen")
@@ -469,6 +642,57 @@ func getHumanReadableID() string {
 	return strings.TrimSpace(string(output))
 }
 
+// getClusterCapacityOptions validates and returns the cluster capacity
+// supervisor options.
+func getClusterCapacityOptions(command *cobra.Command) (supervisor.ClusterCapacityOptions, error) {
+	scaleUpThreshold, _ := command.Flags().GetInt("cluster-capacity-scale-up-threshold")
+	scaleDownThreshold, _ := command.Flags().GetInt("cluster-capacity-scale-down-threshold")
+	if scaleDownThreshold < 0 || scaleDownThreshold >= scaleUpThreshold || scaleUpThreshold > 100 {
+		return supervisor.ClusterCapacityOptions{}, errors.Errorf("cluster-capacity-scale-down-threshold (%d) and cluster-capacity-scale-up-threshold (%d) must be set between 0 and 100 with the scale down threshold being lower", scaleDownThreshold, scaleUpThreshold)
+	}
+	scaleStep, _ := command.Flags().GetInt("cluster-capacity-scale-step")
+	if scaleStep < 1 || scaleStep > 10 {
+		return supervisor.ClusterCapacityOptions{}, errors.Errorf("cluster-capacity-scale-step (%d) must be set between 1 and 10", scaleStep)
+	}
+	minNodeCount, _ := command.Flags().GetInt("cluster-capacity-min-node-count")
+	if minNodeCount < 1 {
+		return supervisor.ClusterCapacityOptions{}, errors.Errorf("cluster-capacity-min-node-count (%d) must be at least 1", minNodeCount)
+	}
+
+	var clusterTemplate *model.CreateClusterRequest
+	templatePath, _ := command.Flags().GetString("cluster-capacity-template")
+	if templatePath != "" {
+		templateFile, err := os.Open(templatePath)
+		if err != nil {
+			return supervisor.ClusterCapacityOptions{}, errors.Wrap(err, "failed to open cluster capacity template")
+		}
+		defer templateFile.Close()
+
+		clusterTemplate, err = model.NewCreateClusterRequestFromReader(templateFile)
+		if err != nil {
+			return supervisor.ClusterCapacityOptions{}, errors.Wrap(err, "invalid cluster capacity template")
+		}
+	}
+
+	return supervisor.NewClusterCapacityOptions(scaleUpThreshold, scaleDownThreshold, scaleStep, minNodeCount, clusterTemplate), nil
+}
+
+// getIdleHibernationOptions validates and returns the idle hibernation
+// supervisor options.
+func getIdleHibernationOptions(command *cobra.Command) (supervisor.IdleHibernationOptions, error) {
+	idlePeriod, _ := command.Flags().GetDuration("idle-hibernation-period")
+	noticePeriod, _ := command.Flags().GetDuration("idle-hibernation-notice")
+	if noticePeriod < 0 || noticePeriod >= idlePeriod {
+		return supervisor.IdleHibernationOptions{}, errors.Errorf("idle-hibernation-notice (%s) must be positive and lower than idle-hibernation-period (%s)", noticePeriod, idlePeriod)
+	}
+	checkInterval, _ := command.Flags().GetDuration("idle-hibernation-check-interval")
+	if checkInterval <= 0 {
+		return supervisor.IdleHibernationOptions{}, errors.Errorf("idle-hibernation-check-interval (%s) must be positive", checkInterval)
+	}
+
+	return supervisor.NewIdleHibernationOptions(idlePeriod, noticePeriod, checkInterval), nil
+}
+
 func flagIsUnset(cmd *cobra.Command, flagName string) bool {
 	return !cmd.Flags().Changed(flagName)
 }
@@ -44,6 +44,18 @@ func parseEnvVarInput(rawInput []string, clear bool) (model.EnvVarMap, error) {
 	return envVarMap, nil
 }
 
+// createClient creates a provisioning server client from the server and api-key
+// flags of the given command.
+func createClient(command *cobra.Command) *model.Client {
+	serverAddress, _ := command.Flags().GetString("server")
+	apiKey, _ := command.Flags().GetString("api-key")
+	if apiKey != "" {
+		return model.NewClientWithAPIKey(serverAddress, apiKey)
+	}
+
+	return model.NewClient(serverAddress)
+}
+
 func registerPagingFlags(cmd *cobra.Command) {
 	cmd.Flags().Int("page", 0, "The page to fetch, starting at 0.")
 	cmd.Flags().Int("per-page", 100, "The number of objects to fetch per page.")
@@ -5,6 +5,7 @@
 package main
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/mattermost/mattermost-cloud/model"
@@ -18,6 +19,11 @@ func init() {
 
 	webhookCreateCmd.Flags().String("owner", "", "An opaque identifier describing the owner of the webhook.")
 	webhookCreateCmd.Flags().String("url", "", "The callback URL of the webhook.")
+	webhookCreateCmd.Flags().String("secret", "", "The secret used to sign webhook payloads. A random secret is generated when not provided.")
+	webhookCreateCmd.Flags().StringArray("subscribe-type", []string{}, "Resource type of the payloads sent to the webhook. Accepts multiple values, for example: '... --subscribe-type installation --subscribe-type cluster'")
+	webhookCreateCmd.Flags().StringArray("subscribe-state", []string{}, "New resource state of the payloads sent to the webhook. Accepts multiple values.")
+	webhookCreateCmd.Flags().StringArray("subscribe-resource", []string{}, "ID of the resource whose payloads are sent to the webhook. Accepts multiple values.")
+	webhookCreateCmd.Flags().String("subscribe-owner", "", "Owner of the installations whose payloads are sent to the webhook.")
 	webhookCreateCmd.MarkFlagRequired("owner")
 	webhookCreateCmd.MarkFlagRequired("url")
 
@@ -31,10 +37,17 @@ func init() {
 	webhookDeleteCmd.Flags().String("webhook", "", "The id of the webhook to be deleted.")
 	webhookDeleteCmd.MarkFlagRequired("webhook")
 
+	webhookDeliveriesCmd.Flags().String("webhook", "", "The id of the webhook whose deliveries will be listed.")
+	webhookDeliveriesCmd.Flags().String("state", "", "The delivery state by which to filter deliveries.")
+	webhookDeliveriesCmd.Flags().Bool("table", false, "Whether to display the returned delivery list in a table or not")
+	webhookDeliveriesCmd.MarkFlagRequired("webhook")
+	registerPagingFlags(webhookDeliveriesCmd)
+
 	webhookCmd.AddCommand(webhookCreateCmd)
 	webhookCmd.AddCommand(webhookGetCmd)
 	webhookCmd.AddCommand(webhookListCmd)
 	webhookCmd.AddCommand(webhookDeleteCmd)
+	webhookCmd.AddCommand(webhookDeliveriesCmd)
 }
 
 var webhookCmd = &cobra.Command{
@@ -48,15 +61,31 @@ var webhookCreateCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		ownerID, _ := command.Flags().GetString("owner")
 		url, _ := command.Flags().GetString("url")
+		secret, _ := command.Flags().GetString("secret")
+		subscribeTypes, _ := command.Flags().GetStringArray("subscribe-type")
+		subscribeStates, _ := command.Flags().GetStringArray("subscribe-state")
+		subscribeResources, _ := command.Flags().GetStringArray("subscribe-resource")
+		subscribeOwner, _ := command.Flags().GetString("subscribe-owner")
+
+		var subscription *model.WebhookSubscription
+		if len(subscribeTypes) > 0 || len(subscribeStates) > 0 || len(subscribeResources) > 0 || subscribeOwner != "" {
+			subscription = &model.WebhookSubscription{
+				Types:       subscribeTypes,
+				States:      subscribeStates,
+				ResourceIDs: subscribeResources,
+				OwnerID:     subscribeOwner,
+			}
+		}
 
 		webhook, err := client.CreateWebhook(&model.CreateWebhookRequest{
-			OwnerID: ownerID,
-			URL:     url,
+			OwnerID:      ownerID,
+			URL:          url,
+			Secret:       secret,
+			Subscription: subscription,
 		})
 		if err != nil {
 			return errors.Wrap(err, "failed to create webhook")
@@ -77,8 +106,7 @@ var webhookGetCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		webhookID, _ := command.Flags().GetString("webhook")
 		webhook, err := client.GetWebhook(webhookID)
@@ -104,8 +132,7 @@ var webhookListCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		owner, _ := command.Flags().GetString("owner")
 		paging := parsePagingFlags(command)
@@ -121,10 +148,10 @@ var webhookListCmd = &cobra.Command{
 		if outputToTable {
 			table := tablewriter.NewWriter(os.Stdout)
 			table.SetAlignment(tablewriter.ALIGN_LEFT)
-			table.SetHeader([]string{"ID", "OWNER", "URL"})
+			table.SetHeader([]string{"ID", "OWNER", "URL", "SUBSCRIPTION"})
 
 			for _, webhook := range webhooks {
-				table.Append([]string{webhook.ID, webhook.OwnerID, webhook.URL})
+				table.Append([]string{webhook.ID, webhook.OwnerID, webhook.URL, webhook.Subscription.String()})
 			}
 			table.Render()
 
@@ -146,8 +173,7 @@ var webhookDeleteCmd = &cobra.Command{
 	RunE: func(command *cobra.Command, args []string) error {
 		command.SilenceUsage = true
 
-		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command)
 
 		webhookID, _ := command.Flags().GetString("webhook")
 
@@ -159,3 +185,51 @@ var webhookDeleteCmd = &cobra.Command{
 		return nil
 	},
 }
+
+var webhookDeliveriesCmd = &cobra.Command{
+	Use:   "deliveries",
+	Short: "List delivery attempts of a webhook.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		client := createClient(command)
+
+		webhookID, _ := command.Flags().GetString("webhook")
+		state, _ := command.Flags().GetString("state")
+		paging := parsePagingFlags(command)
+		deliveries, err := client.GetWebhookDeliveries(webhookID, &model.GetWebhookDeliveriesRequest{
+			State:  state,
+			Paging: paging,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to query webhook deliveries")
+		}
+
+		outputToTable, _ := command.Flags().GetBool("table")
+		if outputToTable {
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetHeader([]string{"ID", "STATE", "ATTEMPTS", "LAST STATUS CODE", "LAST ERROR"})
+
+			for _, delivery := range deliveries {
+				table.Append([]string{
+					delivery.ID,
+					delivery.State,
+					fmt.Sprintf("%d", delivery.Attempts),
+					fmt.Sprintf("%d", delivery.LastStatusCode),
+					delivery.LastError,
+				})
+			}
+			table.Render()
+
+			return nil
+		}
+
+		err = printJSON(deliveries)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/model"
+)
+
+// initAPIKey registers API key endpoints on the given router.
+func initAPIKey(apiRouter *mux.Router, context *Context) {
+	addContext := func(handler contextHandlerFunc) *contextHandler {
+		return newContextHandler(context, handler)
+	}
+
+	apiKeysRouter := apiRouter.PathPrefix("/apikeys").Subrouter()
+	apiKeysRouter.Handle("", addContext(handleGetAPIKeys)).Methods("GET")
+	apiKeysRouter.Handle("", addContext(handleCreateAPIKey)).Methods("POST")
+
+	apiKeyRouter := apiRouter.PathPrefix("/apikey/{apikey:[A-Za-z0-9]{26}}").Subrouter()
+	apiKeyRouter.Handle("", addContext(handleGetAPIKey)).Methods("GET")
+	apiKeyRouter.Handle("", addContext(handleDeleteAPIKey)).Methods("DELETE")
+}
+
+// handleCreateAPIKey responds to POST /api/apikeys, creating a new API key.
+// The plain text key is only returned in this response.
+func handleCreateAPIKey(c *Context, w http.ResponseWriter, r *http.Request) {
+	createAPIKeyRequest, err := model.NewCreateAPIKeyRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	key, err := model.NewAPIKeySecret()
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to generate API key")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	apiKey := model.APIKey{
+		Name:    createAPIKeyRequest.Name,
+		Scope:   createAPIKeyRequest.Scope,
+		OwnerID: createAPIKeyRequest.OwnerID,
+		Key:     key,
+	}
+
+	err = c.Store.CreateAPIKey(&apiKey)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to create API key")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, apiKey)
+}
+
+// handleGetAPIKey responds to GET /api/apikey/{apikey}, returning the API key in question.
+func handleGetAPIKey(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	apiKeyID := vars["apikey"]
+	c.Logger = c.Logger.WithField("apikey", apiKeyID)
+
+	apiKey, err := c.Store.GetAPIKey(apiKeyID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query API key")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if apiKey == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, apiKey)
+}
+
+// handleGetAPIKeys responds to GET /api/apikeys, returning the specified page of API keys.
+func handleGetAPIKeys(c *Context, w http.ResponseWriter, r *http.Request) {
+	paging, err := parsePaging(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse paging parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	filter := &model.APIKeyFilter{
+		OwnerID: r.URL.Query().Get("owner"),
+		Paging:  paging,
+	}
+
+	apiKeys, err := c.Store.GetAPIKeys(filter)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query API keys")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if apiKeys == nil {
+		apiKeys = []*model.APIKey{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, apiKeys)
+}
+
+// handleDeleteAPIKey responds to DELETE /api/apikey/{apikey}, revoking the API key.
+func handleDeleteAPIKey(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	apiKeyID := vars["apikey"]
+	c.Logger = c.Logger.WithField("apikey", apiKeyID)
+
+	apiKey, err := c.Store.GetAPIKey(apiKeyID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query API key")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if apiKey == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if apiKey.IsDeleted() {
+		c.Logger.Warn("unable to delete API key that is already deleted")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = c.Store.DeleteAPIKey(apiKeyID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to mark API key as deleted")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,248 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/api"
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeys(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	t.Run("invalid payload", func(t *testing.T) {
+		resp, err := http.Post(fmt.Sprintf("%s/api/apikeys", ts.URL), "application/json", bytes.NewReader([]byte("invalid")))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("invalid scope", func(t *testing.T) {
+		_, err := client.CreateAPIKey(&model.CreateAPIKeyRequest{Name: "key", Scope: "everything"})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("owner bound admin", func(t *testing.T) {
+		_, err := client.CreateAPIKey(&model.CreateAPIKeyRequest{Name: "key", Scope: model.APIKeyScopeAdmin, OwnerID: "owner"})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	apiKey1, err := client.CreateAPIKey(&model.CreateAPIKeyRequest{Name: "key1", Scope: model.APIKeyScopeAdmin})
+	require.NoError(t, err)
+	require.NotEmpty(t, apiKey1.ID)
+	require.NotEmpty(t, apiKey1.Key)
+
+	apiKey2, err := client.CreateAPIKey(&model.CreateAPIKeyRequest{Name: "key2", Scope: model.APIKeyScopeInstallations, OwnerID: "owner"})
+	require.NoError(t, err)
+
+	t.Run("get", func(t *testing.T) {
+		apiKey, err := client.GetAPIKey(apiKey1.ID)
+		require.NoError(t, err)
+		require.Equal(t, apiKey1.Name, apiKey.Name)
+		require.Empty(t, apiKey.Key)
+
+		apiKey, err = client.GetAPIKey(model.NewID())
+		require.NoError(t, err)
+		require.Nil(t, apiKey)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		apiKeys, err := client.GetAPIKeys(&model.GetAPIKeysRequest{Paging: model.AllPagesNotDeleted()})
+		require.NoError(t, err)
+		require.Len(t, apiKeys, 2)
+
+		apiKeys, err = client.GetAPIKeys(&model.GetAPIKeysRequest{Paging: model.AllPagesNotDeleted(), OwnerID: "owner"})
+		require.NoError(t, err)
+		require.Len(t, apiKeys, 1)
+		require.Equal(t, apiKey2.ID, apiKeys[0].ID)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		err := client.DeleteAPIKey(apiKey2.ID)
+		require.NoError(t, err)
+
+		err = client.DeleteAPIKey(apiKey2.ID)
+		require.EqualError(t, err, "failed with status code 400")
+
+		apiKeys, err := client.GetAPIKeys(&model.GetAPIKeysRequest{Paging: model.AllPagesNotDeleted()})
+		require.NoError(t, err)
+		require.Len(t, apiKeys, 1)
+	})
+}
+
+func TestAPIAuthentication(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:                 sqlStore,
+		Supervisor:            &mockSupervisor{},
+		RequireAuthentication: true,
+		Logger:                logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	createAPIKey := func(scope, ownerID string) *model.Client {
+		key, err := model.NewAPIKeySecret()
+		require.NoError(t, err)
+		err = sqlStore.CreateAPIKey(&model.APIKey{Name: scope, Scope: scope, OwnerID: ownerID, Key: key})
+		require.NoError(t, err)
+
+		return model.NewClientWithAPIKey(ts.URL, key)
+	}
+
+	adminClient := createAPIKey(model.APIKeyScopeAdmin, "")
+	readOnlyClient := createAPIKey(model.APIKeyScopeReadOnly, "")
+	installationsClient := createAPIKey(model.APIKeyScopeInstallations, "")
+	ownerClient := createAPIKey(model.APIKeyScopeInstallations, "owner1")
+
+	installation1, err := adminClient.CreateInstallation(&model.CreateInstallationRequest{
+		OwnerID:  "owner1",
+		Version:  "version",
+		DNS:      "dns1.example.com",
+		Affinity: model.InstallationAffinityIsolated,
+	})
+	require.NoError(t, err)
+
+	installation2, err := adminClient.CreateInstallation(&model.CreateInstallationRequest{
+		OwnerID:  "owner2",
+		Version:  "version",
+		DNS:      "dns2.example.com",
+		Affinity: model.InstallationAffinityIsolated,
+	})
+	require.NoError(t, err)
+
+	t.Run("missing credentials", func(t *testing.T) {
+		_, err := model.NewClient(ts.URL).GetClusters(&model.GetClustersRequest{Paging: model.AllPagesNotDeleted()})
+		require.EqualError(t, err, "failed with status code 401")
+	})
+
+	t.Run("invalid credentials", func(t *testing.T) {
+		_, err := model.NewClientWithAPIKey(ts.URL, "invalid").GetClusters(&model.GetClustersRequest{Paging: model.AllPagesNotDeleted()})
+		require.EqualError(t, err, "failed with status code 401")
+	})
+
+	t.Run("revoked credentials", func(t *testing.T) {
+		key, err := model.NewAPIKeySecret()
+		require.NoError(t, err)
+		apiKey := &model.APIKey{Name: "revoked", Scope: model.APIKeyScopeAdmin, Key: key}
+		require.NoError(t, sqlStore.CreateAPIKey(apiKey))
+		require.NoError(t, sqlStore.DeleteAPIKey(apiKey.ID))
+
+		_, err = model.NewClientWithAPIKey(ts.URL, key).GetClusters(&model.GetClustersRequest{Paging: model.AllPagesNotDeleted()})
+		require.EqualError(t, err, "failed with status code 401")
+	})
+
+	t.Run("admin scope", func(t *testing.T) {
+		_, err := adminClient.GetClusters(&model.GetClustersRequest{Paging: model.AllPagesNotDeleted()})
+		require.NoError(t, err)
+
+		apiKeys, err := adminClient.GetAPIKeys(&model.GetAPIKeysRequest{Paging: model.AllPagesNotDeleted()})
+		require.NoError(t, err)
+		require.Len(t, apiKeys, 4)
+	})
+
+	t.Run("read-only scope", func(t *testing.T) {
+		_, err := readOnlyClient.GetClusters(&model.GetClustersRequest{Paging: model.AllPagesNotDeleted()})
+		require.NoError(t, err)
+
+		installation, err := readOnlyClient.GetInstallation(installation1.ID, &model.GetInstallationRequest{})
+		require.NoError(t, err)
+		require.NotNil(t, installation)
+
+		err = readOnlyClient.DeleteInstallation(installation1.ID)
+		require.EqualError(t, err, "failed with status code 403")
+
+		_, err = readOnlyClient.CreateAPIKey(&model.CreateAPIKeyRequest{Name: "key", Scope: model.APIKeyScopeAdmin})
+		require.EqualError(t, err, "failed with status code 403")
+	})
+
+	t.Run("installations scope", func(t *testing.T) {
+		installations, err := installationsClient.GetInstallations(&model.GetInstallationsRequest{Paging: model.AllPagesNotDeleted()})
+		require.NoError(t, err)
+		require.Len(t, installations, 2)
+
+		_, err = installationsClient.GetClusters(&model.GetClustersRequest{Paging: model.AllPagesNotDeleted()})
+		require.EqualError(t, err, "failed with status code 403")
+
+		_, err = installationsClient.GetAPIKeys(&model.GetAPIKeysRequest{Paging: model.AllPagesNotDeleted()})
+		require.EqualError(t, err, "failed with status code 403")
+	})
+
+	t.Run("owner bound", func(t *testing.T) {
+		installations, err := ownerClient.GetInstallations(&model.GetInstallationsRequest{Paging: model.AllPagesNotDeleted()})
+		require.NoError(t, err)
+		require.Len(t, installations, 1)
+		require.Equal(t, installation1.ID, installations[0].ID)
+
+		installations, err = ownerClient.GetInstallations(&model.GetInstallationsRequest{OwnerID: "owner2", Paging: model.AllPagesNotDeleted()})
+		require.NoError(t, err)
+		require.Empty(t, installations)
+
+		installation, err := ownerClient.GetInstallation(installation1.ID, &model.GetInstallationRequest{})
+		require.NoError(t, err)
+		require.NotNil(t, installation)
+
+		installation, err = ownerClient.GetInstallation(installation2.ID, &model.GetInstallationRequest{})
+		require.NoError(t, err)
+		require.Nil(t, installation)
+
+		err = ownerClient.DeleteInstallation(installation2.ID)
+		require.EqualError(t, err, "failed with status code 404")
+
+		_, err = ownerClient.GetInstallationsCount(false)
+		require.EqualError(t, err, "failed with status code 403")
+
+		_, err = ownerClient.CreateInstallation(&model.CreateInstallationRequest{
+			OwnerID:  "owner2",
+			Version:  "version",
+			DNS:      "dns3.example.com",
+			Affinity: model.InstallationAffinityIsolated,
+		})
+		require.EqualError(t, err, "failed with status code 403")
+
+		_, err = ownerClient.CreateBulkOperation(&model.CreateBulkOperationRequest{
+			Type:   model.BulkOperationTypeHibernate,
+			Filter: model.BulkOperationInstallationFilter{OwnerID: "owner1"},
+		})
+		require.EqualError(t, err, "failed with status code 403")
+
+		newOwner := "owner2"
+		_, err = ownerClient.UpdateInstallation(installation1.ID, &model.PatchInstallationRequest{OwnerID: &newOwner})
+		require.EqualError(t, err, "failed with status code 403")
+
+		installation, err = ownerClient.CreateInstallation(&model.CreateInstallationRequest{
+			OwnerID:  "owner1",
+			Version:  "version",
+			DNS:      "dns3.example.com",
+			Affinity: model.InstallationAffinityIsolated,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "owner1", installation.OwnerID)
+	})
+}
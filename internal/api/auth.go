@@ -0,0 +1,117 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/model"
+)
+
+// authenticateRequest verifies the credentials sent with the request and
+// ensures that the matching API key grants access to the requested resource.
+// The API key is stored in the context on success, otherwise a non-zero
+// status is returned.
+func authenticateRequest(c *Context, r *http.Request) int {
+	authHeader := r.Header.Get(model.AuthorizationHeader)
+	if !strings.HasPrefix(authHeader, model.AuthorizationBearerPrefix) {
+		c.Logger.Warn("Request is missing API credentials")
+		return http.StatusUnauthorized
+	}
+	key := strings.TrimSpace(strings.TrimPrefix(authHeader, model.AuthorizationBearerPrefix))
+	if key == "" {
+		c.Logger.Warn("Request is missing API credentials")
+		return http.StatusUnauthorized
+	}
+
+	apiKey, err := c.Store.GetAPIKeyByKeyHash(model.HashAPIKey(key))
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query API key")
+		return http.StatusInternalServerError
+	}
+	if apiKey == nil || apiKey.IsDeleted() {
+		c.Logger.Warn("Request sent with invalid API credentials")
+		return http.StatusUnauthorized
+	}
+
+	c.APIKey = apiKey
+	c.Logger = c.Logger.WithField("api-key", apiKey.ID)
+
+	if !isRequestAllowedForScope(apiKey, r) {
+		c.Logger.Warnf("API key with scope %q is not allowed to access %s %s", apiKey.Scope, r.Method, r.URL.Path)
+		return http.StatusForbidden
+	}
+
+	if apiKey.IsOwnerBound() {
+		return checkInstallationOwnership(c, r)
+	}
+
+	return 0
+}
+
+// isRequestAllowedForScope returns true if the API key scope grants access to the request.
+func isRequestAllowedForScope(apiKey *model.APIKey, r *http.Request) bool {
+	if apiKey.IsOwnerBound() && !isOwnedInstallationPath(r.URL.Path) {
+		return false
+	}
+
+	switch apiKey.Scope {
+	case model.APIKeyScopeAdmin:
+		return true
+	case model.APIKeyScopeReadOnly:
+		return r.Method == http.MethodGet || r.Method == http.MethodHead
+	case model.APIKeyScopeInstallations:
+		return isInstallationPath(r.URL.Path)
+	}
+
+	return false
+}
+
+// isInstallationPath returns true if the path targets installation endpoints.
+func isInstallationPath(path string) bool {
+	return path == "/api/installations" ||
+		strings.HasPrefix(path, "/api/installations/") ||
+		strings.HasPrefix(path, "/api/installation/")
+}
+
+// isOwnedInstallationPath returns true if the path targets endpoints for which
+// the installation owner can be verified.
+func isOwnedInstallationPath(path string) bool {
+	return path == "/api/installations" ||
+		strings.HasPrefix(path, "/api/installation/")
+}
+
+// checkInstallationOwnership ensures that the installation targeted by the
+// request belongs to the owner bound to the API key. Installations of other
+// owners are reported as not found to avoid leaking their existence.
+func checkInstallationOwnership(c *Context, r *http.Request) int {
+	installationID, ok := mux.Vars(r)["installation"]
+	if !ok {
+		return 0
+	}
+
+	installation, err := c.Store.GetInstallation(installationID, false, false)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query installation")
+		return http.StatusInternalServerError
+	}
+	if installation == nil || installation.OwnerID != c.APIKey.OwnerID {
+		return http.StatusNotFound
+	}
+
+	return 0
+}
+
+// ownerRestriction returns the owner ID to which the request should be
+// restricted or an empty string when there is no restriction.
+func ownerRestriction(c *Context) string {
+	if c.APIKey == nil {
+		return ""
+	}
+
+	return c.APIKey.OwnerID
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/model"
+)
+
+// initBulkOperation registers bulk operation endpoints on the given router.
+func initBulkOperation(apiRouter *mux.Router, context *Context) {
+	addContext := func(handler contextHandlerFunc) *contextHandler {
+		return newContextHandler(context, handler)
+	}
+
+	operationsRouter := apiRouter.PathPrefix("/bulk_operations").Subrouter()
+	operationsRouter.Handle("", addContext(handleCreateBulkOperation)).Methods("POST")
+	operationsRouter.Handle("", addContext(handleGetBulkOperations)).Methods("GET")
+
+	operationRouter := apiRouter.PathPrefix("/bulk_operation/{operation:[A-Za-z0-9]{26}}").Subrouter()
+	operationRouter.Handle("", addContext(handleGetBulkOperation)).Methods("GET")
+	operationRouter.Handle("", addContext(handleCancelBulkOperation)).Methods("DELETE")
+	operationRouter.Handle("/items", addContext(handleGetBulkOperationItems)).Methods("GET")
+}
+
+// handleCreateBulkOperation responds to POST /api/installations/bulk_operations,
+// starts applying an operation to all installations matching a filter.
+// Owner bound API keys are rejected by the authentication middleware.
+func handleCreateBulkOperation(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.Logger = c.Logger.
+		WithField("action", "create-bulk-operation")
+
+	operationRequest, err := model.NewCreateBulkOperationRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	operation := &model.BulkOperation{
+		Type:        operationRequest.Type,
+		Filter:      operationRequest.Filter,
+		Patch:       operationRequest.Patch,
+		Annotations: operationRequest.Annotations,
+		Concurrency: operationRequest.Concurrency,
+	}
+
+	err = c.Store.CreateBulkOperation(operation)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to create bulk operation")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	c.Supervisor.Do()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, operation)
+}
+
+// handleGetBulkOperations responds to GET /api/installations/bulk_operations,
+// returns the specified page of bulk operations.
+func handleGetBulkOperations(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.Logger = c.Logger.
+		WithField("action", "list-bulk-operations")
+
+	paging, err := parsePaging(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse paging parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	filter := &model.BulkOperationFilter{
+		Paging: paging,
+	}
+	if state := r.URL.Query().Get("state"); state != "" {
+		filter.States = []model.BulkOperationState{model.BulkOperationState(state)}
+	}
+
+	operations, err := c.Store.GetBulkOperations(filter)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to list bulk operations")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if operations == nil {
+		operations = []*model.BulkOperation{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, operations)
+}
+
+// handleGetBulkOperation responds to GET /api/installations/bulk_operation/{operation},
+// returns the specified bulk operation.
+func handleGetBulkOperation(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	operationID := vars["operation"]
+	c.Logger = c.Logger.
+		WithField("bulkOperation", operationID).
+		WithField("action", "get-bulk-operation")
+
+	operation, err := c.Store.GetBulkOperation(operationID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get bulk operation")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if operation == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, operation)
+}
+
+// handleGetBulkOperationItems responds to GET /api/installations/bulk_operation/{operation}/items,
+// returns the outcome of the bulk operation for each installation.
+func handleGetBulkOperationItems(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	operationID := vars["operation"]
+	c.Logger = c.Logger.
+		WithField("bulkOperation", operationID).
+		WithField("action", "get-bulk-operation-items")
+
+	operation, err := c.Store.GetBulkOperation(operationID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get bulk operation")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if operation == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	items, err := c.Store.GetBulkOperationItems(operation.ID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get bulk operation items")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if items == nil {
+		items = []*model.BulkOperationItem{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, items)
+}
+
+// handleCancelBulkOperation responds to DELETE /api/installations/bulk_operation/{operation},
+// cancels the bulk operation. Installation state changes already requested by
+// the operation are not reverted.
+func handleCancelBulkOperation(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	operationID := vars["operation"]
+	c.Logger = c.Logger.
+		WithField("bulkOperation", operationID).
+		WithField("action", "cancel-bulk-operation")
+
+	operation, status, unlockOnce := lockBulkOperation(c, operationID)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	defer unlockOnce()
+
+	if operation.IsInProgress() {
+		err := c.Store.CancelBulkOperation(operation.ID)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to cancel bulk operation")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
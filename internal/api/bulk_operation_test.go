@@ -0,0 +1,151 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/api"
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkOperations(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+	client := model.NewClient(ts.URL)
+
+	installation1, err := client.CreateInstallation(&model.CreateInstallationRequest{
+		OwnerID: "owner1",
+		DNS:     "dns1.example.com",
+	})
+	require.NoError(t, err)
+
+	installation2, err := client.CreateInstallation(&model.CreateInstallationRequest{
+		OwnerID: "owner1",
+		DNS:     "dns2.example.com",
+	})
+	require.NoError(t, err)
+
+	_, err = client.CreateInstallation(&model.CreateInstallationRequest{
+		OwnerID: "owner2",
+		DNS:     "dns3.example.com",
+	})
+	require.NoError(t, err)
+
+	version := "version2"
+
+	t.Run("invalid requests", func(t *testing.T) {
+		for _, request := range []*model.CreateBulkOperationRequest{
+			{Type: model.BulkOperationTypeHibernate},
+			{Type: "unknown", Filter: model.BulkOperationInstallationFilter{OwnerID: "owner1"}},
+			{Type: model.BulkOperationTypeUpdate, Filter: model.BulkOperationInstallationFilter{OwnerID: "owner1"}},
+			{Type: model.BulkOperationTypeAddAnnotations, Filter: model.BulkOperationInstallationFilter{OwnerID: "owner1"}},
+			{Type: model.BulkOperationTypeHibernate, Filter: model.BulkOperationInstallationFilter{OwnerID: "owner1"}, Concurrency: -1},
+		} {
+			_, err = client.CreateBulkOperation(request)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "400")
+		}
+	})
+
+	operation, err := client.CreateBulkOperation(&model.CreateBulkOperationRequest{
+		Type:   model.BulkOperationTypeUpdate,
+		Filter: model.BulkOperationInstallationFilter{DNSPattern: "dns*.example.com", OwnerID: "owner1"},
+		Patch:  &model.PatchInstallationRequest{Version: &version},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, model.BulkOperationStateInProgress, operation.State)
+	assert.EqualValues(t, model.BulkOperationDefaultConcurrency, operation.Concurrency)
+	assert.Equal(t, version, *operation.Patch.Version)
+
+	t.Run("get operation", func(t *testing.T) {
+		fetched, err := client.GetBulkOperation(operation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, operation, fetched)
+
+		fetched, err = client.GetBulkOperation(model.NewID())
+		require.NoError(t, err)
+		assert.Nil(t, fetched)
+	})
+
+	t.Run("get items", func(t *testing.T) {
+		items, err := client.GetBulkOperationItems(operation.ID)
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+
+		installationIDs := []string{}
+		for _, item := range items {
+			assert.Equal(t, model.BulkOperationItemStatePending, item.State)
+			installationIDs = append(installationIDs, item.InstallationID)
+		}
+		assert.ElementsMatch(t, []string{installation1.ID, installation2.ID}, installationIDs)
+
+		_, err = client.GetBulkOperationItems(model.NewID())
+		require.EqualError(t, err, "failed with status code 404")
+	})
+
+	t.Run("list operations", func(t *testing.T) {
+		operations, err := client.GetBulkOperations(&model.GetBulkOperationsRequest{
+			Paging: model.AllPagesNotDeleted(),
+			State:  string(model.BulkOperationStateInProgress),
+		})
+		require.NoError(t, err)
+		require.Len(t, operations, 1)
+		assert.Equal(t, operation.ID, operations[0].ID)
+	})
+
+	t.Run("cancel operation", func(t *testing.T) {
+		err := client.CancelBulkOperation(operation.ID)
+		require.NoError(t, err)
+
+		fetched, err := client.GetBulkOperation(operation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.BulkOperationStateCancelled, fetched.State)
+
+		items, err := client.GetBulkOperationItems(operation.ID)
+		require.NoError(t, err)
+		for _, item := range items {
+			assert.Equal(t, model.BulkOperationItemStateCancelled, item.State)
+		}
+
+		err = client.CancelBulkOperation(operation.ID)
+		require.NoError(t, err)
+
+		err = client.CancelBulkOperation(model.NewID())
+		require.EqualError(t, err, "failed with status code 404")
+	})
+
+	t.Run("cancel locked operation", func(t *testing.T) {
+		lockedOperation, err := client.CreateBulkOperation(&model.CreateBulkOperationRequest{
+			Type:   model.BulkOperationTypeHibernate,
+			Filter: model.BulkOperationInstallationFilter{OwnerID: "owner2"},
+		})
+		require.NoError(t, err)
+
+		locked, err := sqlStore.LockBulkOperation(lockedOperation.ID, "locker")
+		require.NoError(t, err)
+		require.True(t, locked)
+
+		err = client.CancelBulkOperation(lockedOperation.ID)
+		require.EqualError(t, err, "failed with status code 409")
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/common"
 	"github.com/mattermost/mattermost-cloud/internal/webhook"
 	"github.com/mattermost/mattermost-cloud/model"
 )
@@ -33,6 +34,8 @@ func initCluster(apiRouter *mux.Router, context *Context) {
 	clusterRouter.Handle("/provision", addContext(handleProvisionCluster)).Methods("POST")
 	clusterRouter.Handle("/kubernetes", addContext(handleUpgradeKubernetes)).Methods("PUT")
 	clusterRouter.Handle("/size", addContext(handleResizeCluster)).Methods("PUT")
+	clusterRouter.Handle("/drain", addContext(handleDrainCluster)).Methods("POST")
+	clusterRouter.Handle("/drain", addContext(handleGetClusterDrainStatus)).Methods("GET")
 	clusterRouter.Handle("/utilities", addContext(handleGetAllUtilityMetadata)).Methods("GET")
 	clusterRouter.Handle("/annotations", addContext(handleAddClusterAnnotations)).Methods("POST")
 	clusterRouter.Handle("/annotation/{annotation-name}", addContext(handleDeleteClusterAnnotation)).Methods("DELETE")
@@ -109,69 +112,18 @@ func handleCreateCluster(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	cluster := model.Cluster{
-		Provider: createClusterRequest.Provider,
-		ProviderMetadataAWS: &model.AWSMetadata{
-			Zones: createClusterRequest.Zones,
-		},
-		Provisioner: "kops",
-		ProvisionerMetadataKops: &model.KopsMetadata{
-			ChangeRequest: &model.KopsMetadataRequestedState{
-				Version:            createClusterRequest.Version,
-				AMI:                createClusterRequest.KopsAMI,
-				MasterInstanceType: createClusterRequest.MasterInstanceType,
-				MasterCount:        createClusterRequest.MasterCount,
-				NodeInstanceType:   createClusterRequest.NodeInstanceType,
-				NodeMinCount:       createClusterRequest.NodeMinCount,
-				NodeMaxCount:       createClusterRequest.NodeMaxCount,
-				Networking:         createClusterRequest.Networking,
-				VPC:                createClusterRequest.VPC,
-			},
-		},
-		AllowInstallations: createClusterRequest.AllowInstallations,
-		APISecurityLock:    createClusterRequest.APISecurityLock,
-		State:              model.ClusterStateCreationRequested,
-	}
-
-	err = cluster.SetUtilityDesiredVersions(createClusterRequest.DesiredUtilityVersions)
-	if err != nil {
-		c.Logger.WithError(err).Error("provided utility metadata could not be applied without error")
-		w.WriteHeader(http.StatusBadRequest)
-		return
-	}
-
-	annotations, err := model.AnnotationsFromStringSlice(createClusterRequest.Annotations)
-	if err != nil {
-		c.Logger.WithError(err).Error("failed to validate extra annotations")
-		w.WriteHeader(http.StatusBadRequest)
-		return
-	}
-
-	err = c.Store.CreateCluster(&cluster, annotations)
+	cluster, err := common.CreateCluster(c.Store, createClusterRequest, c.Environment, c.Logger)
 	if err != nil {
 		c.Logger.WithError(err).Error("failed to create cluster")
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(common.ErrToStatus(err))
 		return
 	}
 
-	webhookPayload := &model.WebhookPayload{
-		Type:      model.TypeCluster,
-		ID:        cluster.ID,
-		NewState:  model.ClusterStateCreationRequested,
-		OldState:  "n/a",
-		Timestamp: time.Now().UnixNano(),
-		ExtraData: map[string]string{"Environment": c.Environment},
-	}
-	err = webhook.SendToAllWebhooks(c.Store, webhookPayload, c.Logger.WithField("webhookEvent", webhookPayload.NewState))
-	if err != nil {
-		c.Logger.WithError(err).Error("Unable to process and send webhooks")
-	}
-
 	c.Supervisor.Do()
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusAccepted)
-	outputJSON(c, w, cluster.ToDTO(annotations))
+	outputJSON(c, w, cluster)
 }
 
 // handleRetryCreateCluster responds to POST /api/cluster/{cluster}, retrying a previously
@@ -377,6 +329,12 @@ func handleUpgradeKubernetes(c *Context, w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if clusterDTO.Provisioner == model.ProvisionerLocal {
+		c.Logger.Warnf("unable to upgrade a cluster with the %s provisioner", model.ProvisionerLocal)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
 	oldState := clusterDTO.State
 	newState := model.ClusterStateUpgradeRequested
 
@@ -447,6 +405,12 @@ func handleResizeCluster(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if clusterDTO.Provisioner == model.ProvisionerLocal {
+		c.Logger.Warnf("unable to resize a cluster with the %s provisioner", model.ProvisionerLocal)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
 	// One more check that can't be done without both the request and the cluster.
 	if resizeClusterRequest.NodeMinCount == nil &&
 		resizeClusterRequest.NodeMaxCount != nil &&
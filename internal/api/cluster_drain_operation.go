@@ -0,0 +1,93 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/common"
+	"github.com/mattermost/mattermost-cloud/model"
+)
+
+// handleDrainCluster responds to POST /api/cluster/{cluster}/drain,
+// disallows new installations on the cluster and requests migration
+// of all multitenant installations to other clusters.
+func handleDrainCluster(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["cluster"]
+	c.Logger = c.Logger.
+		WithField("action", "drain-cluster").
+		WithField("cluster", clusterID)
+
+	drainRequest, err := model.NewClusterDrainRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	clusterDTO, status, unlockOnce := lockCluster(c, clusterID)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	defer unlockOnce()
+
+	if clusterDTO.APISecurityLock {
+		logSecurityLockConflict("cluster", c.Logger)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	drainOperation, err := common.TriggerClusterDrain(c.Store, drainRequest, clusterDTO.Cluster, c.Environment, c.Logger)
+	if err != nil {
+		c.Logger.WithError(err).Error("Failed to trigger cluster drain")
+		w.WriteHeader(common.ErrToStatus(err))
+		return
+	}
+
+	unlockOnce()
+	c.Supervisor.Do()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	outputJSON(c, w, drainOperation)
+}
+
+// handleGetClusterDrainStatus responds to GET /api/cluster/{cluster}/drain,
+// returns progress of the most recent drain of the cluster.
+func handleGetClusterDrainStatus(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["cluster"]
+	c.Logger = c.Logger.
+		WithField("action", "get-cluster-drain-status").
+		WithField("cluster", clusterID)
+
+	drainOperations, err := c.Store.GetClusterDrainOperations(&model.ClusterDrainFilter{
+		ClusterID: clusterID,
+		Paging:    model.Paging{Page: 0, PerPage: 1, IncludeDeleted: false},
+	})
+	if err != nil {
+		c.Logger.WithError(err).Error("Failed to get cluster drain operations")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if len(drainOperations) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	drainStatus, err := common.GetClusterDrainStatus(c.Store, drainOperations[0])
+	if err != nil {
+		c.Logger.WithError(err).Error("Failed to get cluster drain status")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, drainStatus)
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/api"
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrainCluster(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+
+	ts := httptest.NewServer(router)
+	client := model.NewClient(ts.URL)
+
+	cluster := &model.Cluster{State: model.ClusterStateStable, AllowInstallations: true}
+	err := sqlStore.CreateCluster(cluster, nil)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		err = sqlStore.CreateClusterInstallation(&model.ClusterInstallation{
+			InstallationID: model.NewID(),
+			ClusterID:      cluster.ID,
+			State:          model.ClusterInstallationStateStable,
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("fail for unknown cluster", func(t *testing.T) {
+		_, err = client.DrainCluster(model.NewID(), &model.ClusterDrainRequest{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "404")
+	})
+
+	t.Run("fail for invalid concurrency", func(t *testing.T) {
+		_, err = client.DrainCluster(cluster.ID, &model.ClusterDrainRequest{MaxConcurrentMigrations: -1})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "400")
+	})
+
+	t.Run("no drain status before drain", func(t *testing.T) {
+		_, err = client.GetClusterDrainStatus(cluster.ID)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "404")
+	})
+
+	var drainOp *model.ClusterDrainOperation
+
+	t.Run("drain cluster", func(t *testing.T) {
+		drainOp, err = client.DrainCluster(cluster.ID, &model.ClusterDrainRequest{MaxConcurrentMigrations: 3})
+		require.NoError(t, err)
+		assert.Equal(t, cluster.ID, drainOp.ClusterID)
+		assert.Equal(t, int64(3), drainOp.MaxConcurrentMigrations)
+		assert.Equal(t, model.ClusterDrainStateRequested, drainOp.State)
+
+		fetchedCluster, err := client.GetCluster(cluster.ID)
+		require.NoError(t, err)
+		assert.False(t, fetchedCluster.AllowInstallations)
+	})
+
+	t.Run("fail if already draining", func(t *testing.T) {
+		_, err = client.DrainCluster(cluster.ID, &model.ClusterDrainRequest{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "400")
+	})
+
+	t.Run("get drain status", func(t *testing.T) {
+		status, err := client.GetClusterDrainStatus(cluster.ID)
+		require.NoError(t, err)
+		assert.Equal(t, drainOp, status.Operation)
+		assert.Equal(t, 2, status.RemainingInstallations)
+		assert.Equal(t, 0, status.MigratingInstallations)
+		assert.Equal(t, 0, status.MigratedInstallations)
+		assert.Equal(t, 0, status.FailedMigrations)
+	})
+
+	t.Run("fail with API security lock", func(t *testing.T) {
+		lockedCluster := &model.Cluster{State: model.ClusterStateStable, AllowInstallations: true}
+		err = sqlStore.CreateCluster(lockedCluster, nil)
+		require.NoError(t, err)
+		err = sqlStore.LockClusterAPI(lockedCluster.ID)
+		require.NoError(t, err)
+
+		_, err = client.DrainCluster(lockedCluster.ID, &model.ClusterDrainRequest{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "403")
+	})
+}
@@ -346,6 +346,34 @@ func TestCreateCluster(t *testing.T) {
 		// TODO: more fields...
 	})
 
+	t.Run("local without kubeconfig", func(t *testing.T) {
+		_, err := client.CreateCluster(&model.CreateClusterRequest{
+			Provisioner: model.ProvisionerLocal,
+		})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("local", func(t *testing.T) {
+		cluster, err := client.CreateCluster(&model.CreateClusterRequest{
+			Provisioner:    model.ProvisionerLocal,
+			KubeconfigPath: "/tmp/kubeconfig",
+		})
+		require.NoError(t, err)
+		require.Equal(t, model.ProvisionerLocal, cluster.Provisioner)
+		require.Equal(t, "/tmp/kubeconfig", cluster.ProvisionerMetadataLocal.KubeconfigPath)
+		require.Nil(t, cluster.ProvisionerMetadataKops)
+
+		cluster.State = model.ClusterStateStable
+		err = sqlStore.UpdateCluster(cluster.Cluster)
+		require.NoError(t, err)
+
+		_, err = client.UpgradeCluster(cluster.ID, &model.PatchUpgradeClusterRequest{Version: sToP("latest")})
+		require.EqualError(t, err, "failed with status code 400")
+
+		_, err = client.ResizeCluster(cluster.ID, &model.PatchClusterSizeRequest{NodeInstanceType: sToP("test1")})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
 	t.Run("handle annotations", func(t *testing.T) {
 		annotations := []*model.Annotation{
 			{ID: "", Name: "multi-tenant"},
@@ -59,15 +59,32 @@ type Store interface {
 	UnlockGroupAPI(groupID string) error
 	DeleteGroup(groupID string) error
 	GetGroupStatus(groupID string) (*model.GroupStatus, error)
+	UpdateGroupRolloutState(groupID, rolloutState string) error
+	GetGroupConfig(groupID string, sequence int64) (*model.GroupConfig, error)
+	GetGroupConfigs(groupID string) ([]*model.GroupConfig, error)
 
 	CreateWebhook(webhook *model.Webhook) error
 	GetWebhook(webhookID string) (*model.Webhook, error)
 	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+	GetWebhookDeliveries(filter *model.WebhookDeliveryFilter) ([]*model.WebhookDelivery, error)
 	DeleteWebhook(webhookID string) error
 
+	GetEvents(filter *model.EventFilter) ([]*model.Event, error)
+
+	CreateAPIKey(apiKey *model.APIKey) error
+	GetAPIKey(id string) (*model.APIKey, error)
+	GetAPIKeyByKeyHash(keyHash string) (*model.APIKey, error)
+	GetAPIKeys(filter *model.APIKeyFilter) ([]*model.APIKey, error)
+	DeleteAPIKey(id string) error
+
 	GetMultitenantDatabases(filter *model.MultitenantDatabaseFilter) ([]*model.MultitenantDatabase, error)
+	GetMultitenantDatabase(id string) (*model.MultitenantDatabase, error)
+	GetMultitenantDatabaseForInstallationID(installationID string) (*model.MultitenantDatabase, error)
 
 	GetOrCreateAnnotations(annotations []*model.Annotation) ([]*model.Annotation, error)
+	GetAnnotationByName(name string) (*model.Annotation, error)
 
 	CreateClusterAnnotations(clusterID string, annotations []*model.Annotation) ([]*model.Annotation, error)
 	DeleteClusterAnnotation(clusterID string, annotationName string) error
@@ -86,9 +103,47 @@ type Store interface {
 	LockInstallationBackupAPI(backupID string) error
 	UnlockInstallationBackupAPI(backupID string) error
 
+	CreateInstallationBackupSchedule(schedule *model.InstallationBackupSchedule) error
+	GetInstallationBackupSchedule(id string) (*model.InstallationBackupSchedule, error)
+	GetInstallationBackupSchedules(filter *model.InstallationBackupScheduleFilter) ([]*model.InstallationBackupSchedule, error)
+	DeleteInstallationBackupSchedule(id string) error
+
+	CreateScheduledOperation(operation *model.ScheduledOperation) error
+	GetScheduledOperation(id string) (*model.ScheduledOperation, error)
+	GetScheduledOperations(filter *model.ScheduledOperationFilter) ([]*model.ScheduledOperation, error)
+	CancelScheduledOperation(id string) error
+
+	CreateBulkOperation(operation *model.BulkOperation) error
+	GetBulkOperation(id string) (*model.BulkOperation, error)
+	GetBulkOperations(filter *model.BulkOperationFilter) ([]*model.BulkOperation, error)
+	GetBulkOperationItems(operationID string) ([]*model.BulkOperationItem, error)
+	CancelBulkOperation(id string) error
+	LockBulkOperation(operationID, lockerID string) (bool, error)
+	UnlockBulkOperation(operationID, lockerID string, force bool) (bool, error)
+
 	TriggerInstallationRestoration(installation *model.Installation, backup *model.InstallationBackup) (*model.InstallationDBRestorationOperation, error)
+	TriggerInstallationPointInTimeRestoration(installation *model.Installation, sourceInstallationID string, restoreTime int64) (*model.InstallationDBRestorationOperation, error)
 	GetInstallationDBRestorationOperation(id string) (*model.InstallationDBRestorationOperation, error)
 	GetInstallationDBRestorationOperations(filter *model.InstallationDBRestorationFilter) ([]*model.InstallationDBRestorationOperation, error)
+
+	TriggerInstallationDBMigration(dbMigrationOp *model.InstallationDBMigrationOperation, installation *model.Installation) (*model.InstallationDBMigrationOperation, error)
+	CancelInstallationDBMigration(dbMigrationOp *model.InstallationDBMigrationOperation, installation *model.Installation) error
+	GetInstallationDBMigrationOperation(id string) (*model.InstallationDBMigrationOperation, error)
+	GetInstallationDBMigrationOperations(filter *model.InstallationDBMigrationFilter) ([]*model.InstallationDBMigrationOperation, error)
+	LockInstallationDBMigrationOperation(id, lockerID string) (bool, error)
+	UnlockInstallationDBMigrationOperation(id, lockerID string, force bool) (bool, error)
+
+	GetAnnotationsForInstallation(installationID string) ([]*model.Annotation, error)
+	TriggerInstallationClone(source, clone *model.Installation, annotations []*model.Annotation) (*model.InstallationCloneOperation, error)
+	GetInstallationCloneOperation(id string) (*model.InstallationCloneOperation, error)
+	GetInstallationCloneOperations(filter *model.InstallationCloneFilter) ([]*model.InstallationCloneOperation, error)
+
+	TriggerInstallationClusterMigration(migrationOp *model.InstallationClusterMigrationOperation, installation *model.Installation) (*model.InstallationClusterMigrationOperation, error)
+	GetInstallationClusterMigrationOperation(id string) (*model.InstallationClusterMigrationOperation, error)
+	GetInstallationClusterMigrationOperations(filter *model.InstallationClusterMigrationFilter) ([]*model.InstallationClusterMigrationOperation, error)
+
+	TriggerClusterDrain(drainOp *model.ClusterDrainOperation, cluster *model.Cluster) (*model.ClusterDrainOperation, error)
+	GetClusterDrainOperations(filter *model.ClusterDrainFilter) ([]*model.ClusterDrainOperation, error)
 }
 
 // Provisioner describes the interface required to communicate with the Kubernetes cluster.
@@ -98,24 +153,36 @@ type Provisioner interface {
 	GetClusterResources(*model.Cluster, bool) (*k8s.ClusterResources, error)
 }
 
+// Scheduler describes the interface required to evaluate installation scheduling.
+type Scheduler interface {
+	Schedule(installation *model.Installation, policy string) (*model.InstallationSchedulingResult, error)
+}
+
 // Context provides the API with all necessary data and interfaces for responding to requests.
 //
 // It is cloned before each request, allowing per-request changes such as logger annotations.
 type Context struct {
-	Store       Store
-	Supervisor  Supervisor
-	Provisioner Provisioner
-	RequestID   string
-	Environment string
-	Logger      logrus.FieldLogger
+	Store                 Store
+	Supervisor            Supervisor
+	Provisioner           Provisioner
+	Scheduler             Scheduler
+	RequestID             string
+	Environment           string
+	RequireAuthentication bool
+	// APIKey is the key used to authenticate the current request, if any.
+	APIKey *model.APIKey
+	Logger logrus.FieldLogger
 }
 
 // Clone creates a shallow copy of context, allowing clones to apply per-request changes.
 func (c *Context) Clone() *Context {
 	return &Context{
-		Store:       c.Store,
-		Supervisor:  c.Supervisor,
-		Provisioner: c.Provisioner,
-		Logger:      c.Logger,
+		Store:                 c.Store,
+		Supervisor:            c.Supervisor,
+		Provisioner:           c.Provisioner,
+		Scheduler:             c.Scheduler,
+		Environment:           c.Environment,
+		RequireAuthentication: c.RequireAuthentication,
+		Logger:                c.Logger,
 	}
 }
@@ -0,0 +1,69 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/model"
+)
+
+// initEvent registers event endpoints on the given router.
+func initEvent(apiRouter *mux.Router, context *Context) {
+	addContext := func(handler contextHandlerFunc) *contextHandler {
+		return newContextHandler(context, handler)
+	}
+
+	eventsRouter := apiRouter.PathPrefix("/events").Subrouter()
+	eventsRouter.Handle("", addContext(handleGetEvents)).Methods("GET")
+}
+
+// handleGetEvents responds to GET /api/events, returning the specified page
+// of resource state change events, the newest first.
+func handleGetEvents(c *Context, w http.ResponseWriter, r *http.Request) {
+	paging, err := parsePaging(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse paging parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	since, err := parseInt64(r.URL, "since", 0)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse since parameter")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	until, err := parseInt64(r.URL, "until", 0)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse until parameter")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	filter := &model.EventFilter{
+		ResourceType: parseString(r.URL, "resource_type", ""),
+		ResourceID:   parseString(r.URL, "resource_id", ""),
+		Since:        since,
+		Until:        until,
+		Paging:       paging,
+	}
+
+	events, err := c.Store.GetEvents(filter)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query events")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if events == nil {
+		events = []*model.Event{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, events)
+}
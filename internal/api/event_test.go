@@ -0,0 +1,128 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/api"
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetEvents(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	t.Run("no events", func(t *testing.T) {
+		events, err := client.GetEvents(&model.GetEventsRequest{
+			Paging: model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		require.Empty(t, events)
+	})
+
+	installationID := model.NewID()
+	event1 := &model.Event{
+		ResourceType: model.TypeInstallation,
+		ResourceID:   installationID,
+		OldState:     model.InstallationStateUpdateRequested,
+		NewState:     model.InstallationStateUpdateInProgress,
+		Timestamp:    1000,
+		InstanceID:   "instance",
+	}
+	err := sqlStore.CreateEvent(event1)
+	require.NoError(t, err)
+
+	event2 := &model.Event{
+		ResourceType: model.TypeInstallation,
+		ResourceID:   installationID,
+		OldState:     model.InstallationStateUpdateInProgress,
+		NewState:     model.InstallationStateUpdateFailed,
+		Timestamp:    2000,
+		InstanceID:   "instance",
+		Error:        "failed to update",
+	}
+	err = sqlStore.CreateEvent(event2)
+	require.NoError(t, err)
+
+	event3 := &model.Event{
+		ResourceType: model.TypeCluster,
+		ResourceID:   model.NewID(),
+		OldState:     model.ClusterStateCreationRequested,
+		NewState:     model.ClusterStateStable,
+		Timestamp:    3000,
+		InstanceID:   "instance",
+	}
+	err = sqlStore.CreateEvent(event3)
+	require.NoError(t, err)
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		resp, err := http.Get(fmt.Sprintf("%s/api/events?since=invalid", ts.URL))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		resp, err = http.Get(fmt.Sprintf("%s/api/events?page=invalid", ts.URL))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	testCases := []struct {
+		Description string
+		Request     *model.GetEventsRequest
+		Expected    []*model.Event
+	}{
+		{
+			"all events",
+			&model.GetEventsRequest{Paging: model.AllPagesNotDeleted()},
+			[]*model.Event{event3, event2, event1},
+		},
+		{
+			"page 0, per page 2",
+			&model.GetEventsRequest{Paging: model.Paging{Page: 0, PerPage: 2}},
+			[]*model.Event{event3, event2},
+		},
+		{
+			"by resource",
+			&model.GetEventsRequest{ResourceID: installationID, Paging: model.AllPagesNotDeleted()},
+			[]*model.Event{event2, event1},
+		},
+		{
+			"by resource type",
+			&model.GetEventsRequest{ResourceType: model.TypeCluster, Paging: model.AllPagesNotDeleted()},
+			[]*model.Event{event3},
+		},
+		{
+			"by time",
+			&model.GetEventsRequest{Since: 1500, Until: 2500, Paging: model.AllPagesNotDeleted()},
+			[]*model.Event{event2},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Description, func(t *testing.T) {
+			events, err := client.GetEvents(testCase.Request)
+			require.NoError(t, err)
+			require.Equal(t, testCase.Expected, events)
+		})
+	}
+}
@@ -6,6 +6,7 @@ package api
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/mux"
 	"github.com/mattermost/mattermost-cloud/model"
@@ -27,6 +28,10 @@ func initGroup(apiRouter *mux.Router, context *Context) {
 	groupRouter.Handle("", addContext(handleUpdateGroup)).Methods("PUT")
 	groupRouter.Handle("", addContext(handleDeleteGroup)).Methods("DELETE")
 	groupRouter.Handle("/status", addContext(handleGetGroupStatus)).Methods("GET")
+	groupRouter.Handle("/rollout/resume", addContext(handleResumeGroupRollout)).Methods("POST")
+	groupRouter.Handle("/rollout/abort", addContext(handleAbortGroupRollout)).Methods("POST")
+	groupRouter.Handle("/history", addContext(handleGetGroupHistory)).Methods("GET")
+	groupRouter.Handle("/rollback", addContext(handleRollbackGroup)).Methods("POST")
 }
 
 // handleGetGroup responds to GET /api/group/{group}, returning the group in question.
@@ -96,6 +101,15 @@ func handleCreateGroup(c *Context, w http.ResponseWriter, r *http.Request) {
 		MaxRolling:      createGroupRequest.MaxRolling,
 		APISecurityLock: createGroupRequest.APISecurityLock,
 		MattermostEnv:   createGroupRequest.MattermostEnv,
+
+		CanarySize:              createGroupRequest.CanarySize,
+		FailureThresholdPercent: createGroupRequest.FailureThresholdPercent,
+
+		Size:      createGroupRequest.Size,
+		License:   createGroupRequest.License,
+		Affinity:  createGroupRequest.Affinity,
+		Database:  createGroupRequest.Database,
+		Filestore: createGroupRequest.Filestore,
 	}
 
 	err = c.Store.CreateGroup(&group)
@@ -202,6 +216,159 @@ func handleDeleteGroup(c *Context, w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// handleGetGroupHistory responds to GET /api/group/{group}/history, returning
+// the configuration of the group at every sequence, most recent first.
+func handleGetGroupHistory(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	groupID := vars["group"]
+	c.Logger = c.Logger.WithField("group", groupID)
+
+	group, err := c.Store.GetGroup(groupID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query group")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if group == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	groupConfigs, err := c.Store.GetGroupConfigs(groupID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query group configs")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if groupConfigs == nil {
+		groupConfigs = []*model.GroupConfig{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, groupConfigs)
+}
+
+// handleRollbackGroup responds to POST /api/group/{group}/rollback, applying
+// the configuration of a previous sequence to the group. The group sequence is
+// bumped so that the installations are updated to the restored configuration.
+func handleRollbackGroup(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	groupID := vars["group"]
+	c.Logger = c.Logger.WithField("group", groupID)
+
+	sequence, err := strconv.ParseInt(r.URL.Query().Get("sequence"), 10, 64)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse sequence")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	group, status, unlockOnce := lockGroup(c, groupID)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	defer unlockOnce()
+
+	if group.APISecurityLock {
+		logSecurityLockConflict("group", c.Logger)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	groupConfig, err := c.Store.GetGroupConfig(group.ID, sequence)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query group config")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if groupConfig == nil {
+		c.Logger.Errorf("group has no configuration with sequence %d", sequence)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	groupConfig.ApplyTo(group)
+	err = c.Store.UpdateGroup(group, true)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to update group")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	c.Logger.Infof("Rolled group back to the configuration of sequence %d", sequence)
+
+	unlockOnce()
+	c.Supervisor.Do()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, group)
+}
+
+// handleResumeGroupRollout responds to POST /api/group/{group}/rollout/resume,
+// resuming the paused rollout of the group configuration.
+func handleResumeGroupRollout(c *Context, w http.ResponseWriter, r *http.Request) {
+	updateGroupRolloutState(c, w, r, model.GroupRolloutStateActive, model.GroupRolloutStatePaused)
+}
+
+// handleAbortGroupRollout responds to POST /api/group/{group}/rollout/abort,
+// aborting the rollout of the group configuration. The remaining installations
+// are not updated until the group configuration changes again.
+func handleAbortGroupRollout(c *Context, w http.ResponseWriter, r *http.Request) {
+	updateGroupRolloutState(c, w, r, model.GroupRolloutStateAborted, model.GroupRolloutStateActive, model.GroupRolloutStatePaused)
+}
+
+// updateGroupRolloutState moves the rollout of the group to the new state if
+// it is in one of the given valid states.
+func updateGroupRolloutState(c *Context, w http.ResponseWriter, r *http.Request, newState string, validStates ...string) {
+	vars := mux.Vars(r)
+	groupID := vars["group"]
+	c.Logger = c.Logger.WithField("group", groupID)
+
+	group, status, unlockOnce := lockGroup(c, groupID)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	defer unlockOnce()
+
+	if group.APISecurityLock {
+		logSecurityLockConflict("group", c.Logger)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	var valid bool
+	for _, state := range validStates {
+		if group.RolloutState == state {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		c.Logger.Warnf("unable to move group rollout to %s while it is %s", newState, group.RolloutState)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err := c.Store.UpdateGroupRolloutState(group.ID, newState)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to update group rollout state")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	group.RolloutState = newState
+
+	unlockOnce()
+	c.Supervisor.Do()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, group)
+}
+
 // handleGetGroupStatus responds to GET /api/group/{group}/status,
 // returning the rollout status of the group in question.
 func handleGetGroupStatus(c *Context, w http.ResponseWriter, r *http.Request) {
@@ -528,6 +528,7 @@ func TestGroupStatus(t *testing.T) {
 			InstallationsUpdated:        0,
 			InstallationsUpdating:       0,
 			InstallationsAwaitingUpdate: 0,
+			RolloutPhase:                model.GroupRolloutPhaseComplete,
 		}
 		groupStatus, err := client.GetGroupStatus(group.ID)
 		require.NoError(t, err)
@@ -540,6 +541,7 @@ func TestGroupStatus(t *testing.T) {
 			InstallationsUpdated:        0,
 			InstallationsUpdating:       0,
 			InstallationsAwaitingUpdate: 0,
+			RolloutPhase:                model.GroupRolloutPhaseComplete,
 		}
 		ignoredGroup, err := client.CreateGroup(&model.CreateGroupRequest{
 			Name:        "group2",
@@ -563,6 +565,7 @@ func TestGroupStatus(t *testing.T) {
 			InstallationsUpdated:        2,
 			InstallationsUpdating:       3,
 			InstallationsAwaitingUpdate: 1,
+			RolloutPhase:                model.GroupRolloutPhasePaused,
 		}
 		var differentSequence int64 = -1
 
@@ -589,6 +592,180 @@ func TestGroupStatus(t *testing.T) {
 	})
 }
 
+func TestGroupRollout(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	group, err := client.CreateGroup(&model.CreateGroupRequest{
+		Name:                    "group1",
+		Version:                 "version",
+		MaxRolling:              2,
+		CanarySize:              1,
+		FailureThresholdPercent: 20,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), group.CanarySize)
+	require.Equal(t, int64(20), group.FailureThresholdPercent)
+	require.Equal(t, model.GroupRolloutStateActive, group.RolloutState)
+
+	t.Run("unknown group", func(t *testing.T) {
+		groupResp, err := client.ResumeGroupRollout(model.NewID())
+		require.EqualError(t, err, "failed with status code 404")
+		assert.Nil(t, groupResp)
+	})
+
+	t.Run("resume active rollout", func(t *testing.T) {
+		groupResp, err := client.ResumeGroupRollout(group.ID)
+		require.EqualError(t, err, "failed with status code 400")
+		assert.Nil(t, groupResp)
+	})
+
+	t.Run("resume paused rollout", func(t *testing.T) {
+		err = sqlStore.UpdateGroupRolloutState(group.ID, model.GroupRolloutStatePaused)
+		require.NoError(t, err)
+
+		groupStatus, err := client.GetGroupStatus(group.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.GroupRolloutPhasePaused, groupStatus.RolloutPhase)
+
+		groupResp, err := client.ResumeGroupRollout(group.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.GroupRolloutStateActive, groupResp.RolloutState)
+	})
+
+	t.Run("while api-security-locked", func(t *testing.T) {
+		err = sqlStore.LockGroupAPI(group.ID)
+		require.NoError(t, err)
+
+		groupResp, err := client.AbortGroupRollout(group.ID)
+		require.EqualError(t, err, "failed with status code 403")
+		assert.Nil(t, groupResp)
+
+		err = sqlStore.UnlockGroupAPI(group.ID)
+		require.NoError(t, err)
+	})
+
+	t.Run("abort rollout", func(t *testing.T) {
+		groupResp, err := client.AbortGroupRollout(group.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.GroupRolloutStateAborted, groupResp.RolloutState)
+
+		groupStatus, err := client.GetGroupStatus(group.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.GroupRolloutPhaseAborted, groupStatus.RolloutPhase)
+
+		_, err = client.ResumeGroupRollout(group.ID)
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("new configuration restarts an aborted rollout", func(t *testing.T) {
+		groupResp, err := client.UpdateGroup(&model.PatchGroupRequest{
+			ID:      group.ID,
+			Version: sToP("version2"),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, model.GroupRolloutStateActive, groupResp.RolloutState)
+	})
+}
+
+func TestGroupRollback(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	group, err := client.CreateGroup(&model.CreateGroupRequest{
+		Name:          "group1",
+		Version:       "version1",
+		Image:         "image1",
+		MattermostEnv: model.EnvVarMap{"KEY1": {Value: "value1"}},
+	})
+	require.NoError(t, err)
+
+	group, err = client.UpdateGroup(&model.PatchGroupRequest{
+		ID:            group.ID,
+		Version:       sToP("version2"),
+		MattermostEnv: model.EnvVarMap{"KEY2": {Value: "value2"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), group.Sequence)
+
+	t.Run("history", func(t *testing.T) {
+		groupConfigs, err := client.GetGroupHistory(group.ID)
+		require.NoError(t, err)
+		require.Len(t, groupConfigs, 2)
+		assert.Equal(t, int64(1), groupConfigs[0].Sequence)
+		assert.Equal(t, "version2", groupConfigs[0].Version)
+		assert.Equal(t, int64(0), groupConfigs[1].Sequence)
+		assert.Equal(t, "version1", groupConfigs[1].Version)
+	})
+
+	t.Run("history of unknown group", func(t *testing.T) {
+		groupConfigs, err := client.GetGroupHistory(model.NewID())
+		require.NoError(t, err)
+		require.Nil(t, groupConfigs)
+	})
+
+	t.Run("invalid sequence", func(t *testing.T) {
+		resp, err := http.Post(fmt.Sprintf("%s/api/group/%s/rollback?sequence=invalid", ts.URL, group.ID), "application/json", nil)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("unknown sequence", func(t *testing.T) {
+		groupResp, err := client.RollbackGroup(group.ID, &model.RollbackGroupRequest{Sequence: 9001})
+		require.EqualError(t, err, "failed with status code 404")
+		assert.Nil(t, groupResp)
+	})
+
+	t.Run("while api-security-locked", func(t *testing.T) {
+		err = sqlStore.LockGroupAPI(group.ID)
+		require.NoError(t, err)
+
+		groupResp, err := client.RollbackGroup(group.ID, &model.RollbackGroupRequest{Sequence: 0})
+		require.EqualError(t, err, "failed with status code 403")
+		assert.Nil(t, groupResp)
+
+		err = sqlStore.UnlockGroupAPI(group.ID)
+		require.NoError(t, err)
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		groupResp, err := client.RollbackGroup(group.ID, &model.RollbackGroupRequest{Sequence: 0})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), groupResp.Sequence)
+		assert.Equal(t, "version1", groupResp.Version)
+		assert.Equal(t, "image1", groupResp.Image)
+		assert.Equal(t, model.EnvVarMap{"KEY1": {Value: "value1"}}, groupResp.MattermostEnv)
+
+		groupConfigs, err := client.GetGroupHistory(group.ID)
+		require.NoError(t, err)
+		require.Len(t, groupConfigs, 3)
+		assert.Equal(t, int64(2), groupConfigs[0].Sequence)
+		assert.Empty(t, groupConfigs[0].Diff(groupConfigs[2]))
+	})
+}
+
 func TestGroupsStatus(t *testing.T) {
 	logger := testlib.MakeLogger(t)
 	sqlStore := store.MakeTestSQLStore(t, logger)
@@ -655,6 +832,7 @@ func TestGroupsStatus(t *testing.T) {
 					InstallationsUpdated:        0,
 					InstallationsUpdating:       0,
 					InstallationsAwaitingUpdate: 0,
+					RolloutPhase:                model.GroupRolloutPhaseComplete,
 				},
 			},
 			{
@@ -664,6 +842,7 @@ func TestGroupsStatus(t *testing.T) {
 					InstallationsUpdated:        0,
 					InstallationsUpdating:       0,
 					InstallationsAwaitingUpdate: 0,
+					RolloutPhase:                model.GroupRolloutPhaseComplete,
 				},
 			},
 		}
@@ -680,6 +859,7 @@ func TestGroupsStatus(t *testing.T) {
 				InstallationsUpdated:        2,
 				InstallationsUpdating:       3,
 				InstallationsAwaitingUpdate: 1,
+				RolloutPhase:                model.GroupRolloutPhasePaused,
 			},
 		}
 		expectedStatusGroup2 := &model.GroupsStatus{
@@ -689,6 +869,7 @@ func TestGroupsStatus(t *testing.T) {
 				InstallationsUpdated:        0,
 				InstallationsUpdating:       0,
 				InstallationsAwaitingUpdate: 0,
+				RolloutPhase:                model.GroupRolloutPhaseComplete,
 			},
 		}
 		var differentSequence int64 = -1
@@ -26,6 +26,14 @@ func (h contextHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		"request": context.RequestID,
 	})
 
+	if context.RequireAuthentication {
+		status := authenticateRequest(context, r)
+		if status != 0 {
+			w.WriteHeader(status)
+			return
+		}
+	}
+
 	h.handler(context, w, r)
 }
 
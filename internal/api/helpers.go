@@ -40,6 +40,20 @@ func parseInt(u *url.URL, name string, defaultValue int) (int, error) {
 	return value, nil
 }
 
+func parseInt64(u *url.URL, name string, defaultValue int64) (int64, error) {
+	valueStr := u.Query().Get(name)
+	if valueStr == "" {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.ParseInt(valueStr, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to parse %s as integer", name)
+	}
+
+	return value, nil
+}
+
 func parseBool(u *url.URL, name string, defaultValue bool) (bool, error) {
 	valueStr := u.Query().Get(name)
 	if valueStr == "" {
@@ -91,6 +105,55 @@ func parseGroupConfig(u *url.URL) (bool, bool, error) {
 	return includeGroupConfig, includeGroupConfigOverrides, nil
 }
 
+// parseInstallationFilter parses the installation filter, sorting and cursor
+// query parameters. Annotation names are not resolved to IDs.
+func parseInstallationFilter(u *url.URL) (*model.InstallationFilter, error) {
+	paging, err := parsePaging(u)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse paging parameters")
+	}
+	createdAfter, err := parseInt64(u, "created_after", 0)
+	if err != nil {
+		return nil, err
+	}
+	createdBefore, err := parseInt64(u, "created_before", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &model.InstallationFilter{
+		Paging:        paging,
+		OwnerID:       parseString(u, "owner", ""),
+		GroupID:       parseString(u, "group", ""),
+		State:         parseString(u, "state", ""),
+		DNS:           parseString(u, "dns_name", ""),
+		DNSContains:   parseString(u, "dns_contains", ""),
+		Version:       parseString(u, "version", ""),
+		Image:         parseString(u, "image", ""),
+		Database:      parseString(u, "database", ""),
+		Filestore:     parseString(u, "filestore", ""),
+		Size:          parseString(u, "size", ""),
+		Affinity:      parseString(u, "affinity", ""),
+		ClusterID:     parseString(u, "cluster", ""),
+		CreatedAfter:  createdAfter,
+		CreatedBefore: createdBefore,
+		SortBy:        parseString(u, "sort_by", ""),
+		SortOrder:     parseString(u, "sort_order", ""),
+		After:         parseString(u, "after", ""),
+	}
+	if !model.IsValidInstallationSortBy(filter.SortBy) {
+		return nil, errors.Errorf("unsupported sort_by value %q", filter.SortBy)
+	}
+	if !model.IsValidSortOrder(filter.SortOrder) {
+		return nil, errors.Errorf("unsupported sort_order value %q", filter.SortOrder)
+	}
+	if filter.After != "" && filter.Paging.Page > 0 {
+		return nil, errors.New("after cannot be combined with page")
+	}
+
+	return filter, nil
+}
+
 func parseDatabaseListRequest(u *url.URL) (string, string) {
 	vpcID := parseString(u, "vpc_id", "")
 	databaseType := parseString(u, "database_type", "")
@@ -26,6 +26,11 @@ func initInstallation(apiRouter *mux.Router, context *Context) {
 	installationsRouter := apiRouter.PathPrefix("/installations").Subrouter()
 	initInstallationBackup(installationsRouter, context)
 	initInstallationRestoration(installationsRouter, context)
+	initInstallationDBMigration(installationsRouter, context)
+	initInstallationClone(installationsRouter, context)
+	initInstallationClusterMigration(installationsRouter, context)
+	initScheduledOperation(installationsRouter, context)
+	initBulkOperation(installationsRouter, context)
 
 	installationsRouter.Handle("", addContext(handleGetInstallations)).Methods("GET")
 	installationsRouter.Handle("", addContext(handleCreateInstallation)).Methods("POST")
@@ -40,6 +45,8 @@ func initInstallation(apiRouter *mux.Router, context *Context) {
 	installationRouter.Handle("/group", addContext(handleLeaveGroup)).Methods("DELETE")
 	installationRouter.Handle("/hibernate", addContext(handleHibernateInstallation)).Methods("POST")
 	installationRouter.Handle("/wakeup", addContext(handleWakeupInstallation)).Methods("POST")
+	installationRouter.Handle("/clone", addContext(handleCloneInstallation)).Methods("POST")
+	installationRouter.Handle("/scheduling/dry-run", addContext(handleInstallationSchedulingDryRun)).Methods("GET")
 	installationRouter.Handle("", addContext(handleDeleteInstallation)).Methods("DELETE")
 	installationRouter.Handle("/annotations", addContext(handleAddInstallationAnnotations)).Methods("POST")
 	installationRouter.Handle("/annotation/{annotation-name}", addContext(handleDeleteInstallationAnnotation)).Methods("DELETE")
@@ -75,12 +82,13 @@ func handleGetInstallation(c *Context, w http.ResponseWriter, r *http.Request) {
 }
 
 // handleGetInstallations responds to GET /api/installations, returning the specified page of installations.
+// Installations can be filtered, sorted and paginated with a cursor through query parameters.
 func handleGetInstallations(c *Context, w http.ResponseWriter, r *http.Request) {
 	var err error
 
-	paging, err := parsePaging(r.URL)
+	filter, err := parseInstallationFilter(r.URL)
 	if err != nil {
-		c.Logger.WithError(err).Error("failed to parse paging parameters")
+		c.Logger.WithError(err).Error("failed to parse filter parameters")
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
@@ -92,17 +100,58 @@ func handleGetInstallations(c *Context, w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	owner := r.URL.Query().Get("owner")
-	group := r.URL.Query().Get("group")
-	state := r.URL.Query().Get("state")
-	dns := r.URL.Query().Get("dns_name")
+	if restrictedOwner := ownerRestriction(c); restrictedOwner != "" {
+		if filter.OwnerID != "" && filter.OwnerID != restrictedOwner {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			outputJSON(c, w, []*model.InstallationDTO{})
+			return
+		}
+		filter.OwnerID = restrictedOwner
+	}
+
+	if filter.After != "" {
+		afterInstallation, err := c.Store.GetInstallation(filter.After, false, false)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to query after installation")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if afterInstallation == nil {
+			c.Logger.Errorf("after installation %s not found", filter.After)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	matchAll, matchAny := r.URL.Query()["annotation"], r.URL.Query()["annotation_any"]
+	if len(matchAll) > 0 || len(matchAny) > 0 {
+		filter.Annotations = &model.AnnotationsFilter{}
 
-	filter := &model.InstallationFilter{
-		OwnerID: owner,
-		GroupID: group,
-		State:   state,
-		Paging:  paging,
-		DNS:     dns,
+		var found bool
+		filter.Annotations.MatchAllIDs, found, err = getAnnotationIDsByName(c, matchAll)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to get annotations")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		// No installation can have an annotation which does not exist.
+		matchesNone := !found
+		if len(matchAny) > 0 {
+			filter.Annotations.MatchAnyIDs, _, err = getAnnotationIDsByName(c, matchAny)
+			if err != nil {
+				c.Logger.WithError(err).Error("failed to get annotations")
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			matchesNone = matchesNone || len(filter.Annotations.MatchAnyIDs) == 0
+		}
+		if matchesNone {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			outputJSON(c, w, []*model.InstallationDTO{})
+			return
+		}
 	}
 
 	installations, err := c.Store.GetInstallationDTOs(filter, includeGroupConfig, includeGroupConfigOverrides)
@@ -165,6 +214,12 @@ func handleCreateInstallation(c *Context, w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if restrictedOwner := ownerRestriction(c); restrictedOwner != "" && createInstallationRequest.OwnerID != restrictedOwner {
+		c.Logger.Warnf("API key is not allowed to create installations for owner %s", createInstallationRequest.OwnerID)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
 	var group *model.Group
 	var status int
 	groupUnlockOnce := func() {}
@@ -180,6 +235,14 @@ func handleCreateInstallation(c *Context, w http.ResponseWriter, r *http.Request
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
+
+		createInstallationRequest.ApplyGroupDefaults(group)
+		err = createInstallationRequest.Validate()
+		if err != nil {
+			c.Logger.WithError(err).Error("installation request is invalid with the group defaults")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
 	}
 
 	installation := model.Installation{
@@ -193,6 +256,7 @@ func handleCreateInstallation(c *Context, w http.ResponseWriter, r *http.Request
 		License:                    createInstallationRequest.License,
 		Size:                       createInstallationRequest.Size,
 		Affinity:                   createInstallationRequest.Affinity,
+		SchedulingPolicy:           createInstallationRequest.SchedulingPolicy,
 		APISecurityLock:            createInstallationRequest.APISecurityLock,
 		MattermostEnv:              createInstallationRequest.MattermostEnv,
 		SingleTenantDatabaseConfig: createInstallationRequest.SingleTenantDatabaseConfig.ToDBConfig(createInstallationRequest.Database),
@@ -217,6 +281,7 @@ func handleCreateInstallation(c *Context, w http.ResponseWriter, r *http.Request
 	webhookPayload := &model.WebhookPayload{
 		Type:      model.TypeInstallation,
 		ID:        installation.ID,
+		OwnerID:   installation.OwnerID,
 		NewState:  model.InstallationStateCreationRequested,
 		OldState:  "n/a",
 		Timestamp: time.Now().UnixNano(),
@@ -284,6 +349,12 @@ func handleUpdateInstallation(c *Context, w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if restrictedOwner := ownerRestriction(c); restrictedOwner != "" && patchInstallationRequest.OwnerID != nil && *patchInstallationRequest.OwnerID != restrictedOwner {
+		c.Logger.Warnf("API key is not allowed to transfer installation to owner %s", *patchInstallationRequest.OwnerID)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
 	newState := model.InstallationStateUpdateRequested
 
 	installationDTO, status, unlockOnce := getInstallationForTransition(c, installationID, newState)
@@ -308,6 +379,7 @@ func handleUpdateInstallation(c *Context, w http.ResponseWriter, r *http.Request
 		webhookPayload := &model.WebhookPayload{
 			Type:      model.TypeInstallation,
 			ID:        installationDTO.ID,
+			OwnerID:   installationDTO.OwnerID,
 			NewState:  newState,
 			OldState:  oldState,
 			Timestamp: time.Now().UnixNano(),
@@ -636,6 +708,7 @@ func updateInstallationState(c *Context, installationDTO *model.InstallationDTO,
 	webhookPayload := &model.WebhookPayload{
 		Type:      model.TypeInstallation,
 		ID:        installationDTO.ID,
+		OwnerID:   installationDTO.OwnerID,
 		NewState:  newState,
 		OldState:  installationDTO.State,
 		Timestamp: time.Now().UnixNano(),
@@ -678,3 +751,23 @@ func getInstallationForTransition(c *Context, installationID, newState string) (
 
 	return installationDTO, 0, unlockOnce
 }
+
+// getAnnotationIDsByName returns the IDs of the annotations with the given
+// names and whether all of them exist.
+func getAnnotationIDsByName(c *Context, names []string) ([]string, bool, error) {
+	ids := []string{}
+	allFound := true
+	for _, name := range names {
+		annotation, err := c.Store.GetAnnotationByName(name)
+		if err != nil {
+			return nil, false, err
+		}
+		if annotation == nil {
+			allFound = false
+			continue
+		}
+		ids = append(ids, annotation.ID)
+	}
+
+	return ids, allFound, nil
+}
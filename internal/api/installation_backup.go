@@ -26,6 +26,12 @@ func initInstallationBackup(apiRouter *mux.Router, context *Context) {
 
 	backupsRouter.Handle("", addContext(handleRequestInstallationBackup)).Methods("POST")
 	backupsRouter.Handle("", addContext(handleGetInstallationBackups)).Methods("GET")
+	backupsRouter.Handle("/schedules", addContext(handleCreateInstallationBackupSchedule)).Methods("POST")
+	backupsRouter.Handle("/schedules", addContext(handleGetInstallationBackupSchedules)).Methods("GET")
+
+	scheduleRouter := backupsRouter.PathPrefix("/schedule/{schedule:[A-Za-z0-9]{26}}").Subrouter()
+	scheduleRouter.Handle("", addContext(handleGetInstallationBackupSchedule)).Methods("GET")
+	scheduleRouter.Handle("", addContext(handleDeleteInstallationBackupSchedule)).Methods("DELETE")
 
 	backupRouter := apiRouter.PathPrefix("/backup/{backup:[A-Za-z0-9]{26}}").Subrouter()
 	backupRouter.Handle("", addContext(handleGetInstallationBackup)).Methods("GET")
@@ -88,6 +94,7 @@ func handleGetInstallationBackups(c *Context, w http.ResponseWriter, r *http.Req
 
 	installationID := r.URL.Query().Get("installation")
 	clusterInstallationID := r.URL.Query().Get("cluster_installation")
+	scheduleID := r.URL.Query().Get("schedule")
 	state := r.URL.Query().Get("state")
 	var states []model.InstallationBackupState
 	if state != "" {
@@ -97,6 +104,7 @@ func handleGetInstallationBackups(c *Context, w http.ResponseWriter, r *http.Req
 	filter := &model.InstallationBackupFilter{
 		InstallationID:        installationID,
 		ClusterInstallationID: clusterInstallationID,
+		ScheduleID:            scheduleID,
 		States:                states,
 		Paging:                paging,
 	}
@@ -199,10 +207,170 @@ func handleDeleteInstallationBackup(c *Context, w http.ResponseWriter, r *http.R
 	w.WriteHeader(http.StatusAccepted)
 }
 
+// handleCreateInstallationBackupSchedule responds to POST /api/installations/backups/schedules,
+// creates a backup schedule for an installation or a group.
+func handleCreateInstallationBackupSchedule(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.Logger = c.Logger.
+		WithField("action", "create-installation-backup-schedule")
+
+	scheduleRequest, err := model.NewCreateInstallationBackupScheduleRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if scheduleRequest.InstallationID != "" {
+		installation, err := c.Store.GetInstallation(scheduleRequest.InstallationID, false, false)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to query installation")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if installation == nil || installation.State == model.InstallationStateDeleted {
+			c.Logger.Error("installation to back up does not exist")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		err = model.EnsureInstallationReadyForBackup(installation)
+		if err != nil {
+			c.Logger.WithError(err).Error("installation cannot be backed up")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+	if scheduleRequest.GroupID != "" {
+		group, err := c.Store.GetGroup(scheduleRequest.GroupID)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to query group")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if group == nil || group.IsDeleted() {
+			c.Logger.Error("group to back up does not exist")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	schedule := &model.InstallationBackupSchedule{
+		InstallationID:         scheduleRequest.InstallationID,
+		GroupID:                scheduleRequest.GroupID,
+		IntervalSeconds:        scheduleRequest.IntervalSeconds,
+		RetentionKeepLast:      scheduleRequest.RetentionKeepLast,
+		RetentionKeepDailyDays: scheduleRequest.RetentionKeepDailyDays,
+	}
+
+	err = c.Store.CreateInstallationBackupSchedule(schedule)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to create backup schedule")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	c.Supervisor.Do()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, schedule)
+}
+
+// handleGetInstallationBackupSchedules responds to GET /api/installations/backups/schedules,
+// returns the specified page of backup schedules.
+func handleGetInstallationBackupSchedules(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.Logger = c.Logger.
+		WithField("action", "list-installation-backup-schedules")
+
+	paging, err := parsePaging(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse paging parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	filter := &model.InstallationBackupScheduleFilter{
+		InstallationID: r.URL.Query().Get("installation"),
+		GroupID:        r.URL.Query().Get("group"),
+		Paging:         paging,
+	}
+
+	schedules, err := c.Store.GetInstallationBackupSchedules(filter)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to list backup schedules")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if schedules == nil {
+		schedules = []*model.InstallationBackupSchedule{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, schedules)
+}
+
+// handleGetInstallationBackupSchedule responds to GET /api/installations/backups/schedule/{schedule},
+// returns the specified backup schedule.
+func handleGetInstallationBackupSchedule(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	scheduleID := vars["schedule"]
+	c.Logger = c.Logger.
+		WithField("backupSchedule", scheduleID).
+		WithField("action", "get-installation-backup-schedule")
+
+	schedule, err := c.Store.GetInstallationBackupSchedule(scheduleID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get backup schedule")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if schedule == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, schedule)
+}
+
+// handleDeleteInstallationBackupSchedule responds to DELETE /api/installations/backups/schedule/{schedule},
+// deletes the backup schedule. Backups created by the schedule are kept.
+func handleDeleteInstallationBackupSchedule(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	scheduleID := vars["schedule"]
+	c.Logger = c.Logger.
+		WithField("backupSchedule", scheduleID).
+		WithField("action", "delete-installation-backup-schedule")
+
+	schedule, err := c.Store.GetInstallationBackupSchedule(scheduleID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get backup schedule")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if schedule == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if !schedule.IsDeleted() {
+		err = c.Store.DeleteInstallationBackupSchedule(schedule.ID)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to delete backup schedule")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func sendInstallationBackupWebhook(c *Context, backup *model.InstallationBackup, oldState string) {
 	webhookPayload := &model.WebhookPayload{
 		Type:      model.TypeInstallationBackup,
 		ID:        backup.ID,
+		OwnerID:   installationOwnerID(c, backup.InstallationID),
 		NewState:  string(backup.State),
 		OldState:  oldState,
 		Timestamp: time.Now().UnixNano(),
@@ -349,3 +349,138 @@ func TestBackupAPILock(t *testing.T) {
 	require.NoError(t, err)
 	assert.False(t, fetchedMeta.APISecurityLock)
 }
+
+func TestInstallationBackupSchedules(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+	client := model.NewClient(ts.URL)
+
+	installation, err := client.CreateInstallation(
+		&model.CreateInstallationRequest{
+			OwnerID:   "owner",
+			Version:   "version",
+			DNS:       "dns1.example.com",
+			Affinity:  model.InstallationAffinityMultiTenant,
+			Database:  model.InstallationDatabaseMultiTenantRDSPostgres,
+			Filestore: model.InstallationFilestoreBifrost,
+		})
+	require.NoError(t, err)
+
+	group, err := client.CreateGroup(&model.CreateGroupRequest{Name: "group"})
+	require.NoError(t, err)
+
+	t.Run("invalid requests", func(t *testing.T) {
+		for _, request := range []*model.CreateInstallationBackupScheduleRequest{
+			{IntervalSeconds: 3600},
+			{InstallationID: installation.ID, GroupID: group.ID, IntervalSeconds: 3600},
+			{InstallationID: installation.ID, IntervalSeconds: 60},
+			{InstallationID: installation.ID, IntervalSeconds: 3600, RetentionKeepLast: -1},
+			{InstallationID: model.NewID(), IntervalSeconds: 3600},
+			{GroupID: model.NewID(), IntervalSeconds: 3600},
+		} {
+			_, err = client.CreateInstallationBackupSchedule(request)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "400")
+		}
+	})
+
+	t.Run("fail for installation not compatible with backups", func(t *testing.T) {
+		installationMySQL, err := client.CreateInstallation(
+			&model.CreateInstallationRequest{
+				OwnerID:  "owner",
+				DNS:      "dns2.example.com",
+				Database: model.InstallationDatabaseMysqlOperator,
+			})
+		require.NoError(t, err)
+
+		_, err = client.CreateInstallationBackupSchedule(&model.CreateInstallationBackupScheduleRequest{
+			InstallationID:  installationMySQL.ID,
+			IntervalSeconds: 3600,
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "400")
+	})
+
+	t.Run("fail for installation not hibernated", func(t *testing.T) {
+		_, err = client.CreateInstallationBackupSchedule(&model.CreateInstallationBackupScheduleRequest{
+			InstallationID:  installation.ID,
+			IntervalSeconds: 3600,
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "400")
+	})
+
+	installation.State = model.InstallationStateHibernating
+	err = sqlStore.UpdateInstallation(installation.Installation)
+	require.NoError(t, err)
+
+	installationSchedule, err := client.CreateInstallationBackupSchedule(&model.CreateInstallationBackupScheduleRequest{
+		InstallationID:    installation.ID,
+		IntervalSeconds:   3600,
+		RetentionKeepLast: 5,
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, installationSchedule.ID)
+	assert.Equal(t, int64(5), installationSchedule.RetentionKeepLast)
+
+	groupSchedule, err := client.CreateInstallationBackupSchedule(&model.CreateInstallationBackupScheduleRequest{
+		GroupID:                group.ID,
+		IntervalSeconds:        86400,
+		RetentionKeepDailyDays: 7,
+	})
+	require.NoError(t, err)
+
+	t.Run("get schedule", func(t *testing.T) {
+		schedule, err := client.GetInstallationBackupSchedule(installationSchedule.ID)
+		require.NoError(t, err)
+		assert.Equal(t, installationSchedule, schedule)
+
+		schedule, err = client.GetInstallationBackupSchedule(model.NewID())
+		require.NoError(t, err)
+		assert.Nil(t, schedule)
+	})
+
+	t.Run("list schedules", func(t *testing.T) {
+		schedules, err := client.GetInstallationBackupSchedules(&model.GetInstallationBackupSchedulesRequest{
+			Paging: model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.InstallationBackupSchedule{installationSchedule, groupSchedule}, schedules)
+
+		schedules, err = client.GetInstallationBackupSchedules(&model.GetInstallationBackupSchedulesRequest{
+			GroupID: group.ID,
+			Paging:  model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.InstallationBackupSchedule{groupSchedule}, schedules)
+	})
+
+	t.Run("delete schedule", func(t *testing.T) {
+		err := client.DeleteInstallationBackupSchedule(installationSchedule.ID)
+		require.NoError(t, err)
+
+		schedule, err := client.GetInstallationBackupSchedule(installationSchedule.ID)
+		require.NoError(t, err)
+		assert.True(t, schedule.IsDeleted())
+
+		schedules, err := client.GetInstallationBackupSchedules(&model.GetInstallationBackupSchedulesRequest{
+			Paging: model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.InstallationBackupSchedule{groupSchedule}, schedules)
+
+		err = client.DeleteInstallationBackupSchedule(model.NewID())
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/common"
+	"github.com/mattermost/mattermost-cloud/model"
+)
+
+// initInstallationClone registers installation clone operation endpoints on the given router.
+func initInstallationClone(apiRouter *mux.Router, context *Context) {
+	addContext := func(handler contextHandlerFunc) *contextHandler {
+		return newContextHandler(context, handler)
+	}
+
+	clonesRouter := apiRouter.PathPrefix("/operations/clones").Subrouter()
+	clonesRouter.Handle("", addContext(handleGetInstallationCloneOperations)).Methods("GET")
+
+	cloneRouter := apiRouter.PathPrefix("/operations/clone/{clone:[A-Za-z0-9]{26}}").Subrouter()
+	cloneRouter.Handle("", addContext(handleGetInstallationCloneOperation)).Methods("GET")
+}
+
+// handleCloneInstallation responds to POST /api/installation/{installation}/clone,
+// requests creation of a new installation from the data of the given one.
+// Only hibernated installations can be cloned, as their data is copied from
+// a backup.
+func handleCloneInstallation(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	installationID := vars["installation"]
+	c.Logger = c.Logger.
+		WithField("action", "clone-installation").
+		WithField("installation", installationID)
+
+	cloneRequest, err := model.NewInstallationCloneRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	installationDTO, status, unlockOnce := lockInstallation(c, installationID)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	defer unlockOnce()
+
+	cloneRequest.SetDefaults(installationDTO.Installation)
+	err = cloneRequest.Validate()
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid installation clone request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if restrictedOwner := ownerRestriction(c); restrictedOwner != "" && cloneRequest.OwnerID != restrictedOwner {
+		c.Logger.Warnf("API key is not allowed to create installations for owner %s", cloneRequest.OwnerID)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	cloneOperation, err := common.TriggerInstallationClone(c.Store, installationDTO.Installation, cloneRequest, c.Environment, c.Logger)
+	if err != nil {
+		c.Logger.WithError(err).Error("Failed to trigger installation clone")
+		w.WriteHeader(common.ErrToStatus(err))
+		return
+	}
+
+	unlockOnce()
+	c.Supervisor.Do()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	outputJSON(c, w, cloneOperation)
+}
+
+// handleGetInstallationCloneOperations responds to GET /api/installations/operations/clones,
+// returns list of installation clone operations.
+func handleGetInstallationCloneOperations(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.Logger = c.Logger.
+		WithField("action", "list-installation-clones")
+
+	paging, err := parsePaging(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse paging parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	installationID := r.URL.Query().Get("installation")
+	cloneInstallationID := r.URL.Query().Get("clone_installation")
+	state := r.URL.Query().Get("state")
+	var states []model.InstallationCloneOperationState
+	if state != "" {
+		states = append(states, model.InstallationCloneOperationState(state))
+	}
+
+	cloneOperations, err := c.Store.GetInstallationCloneOperations(&model.InstallationCloneFilter{
+		Paging:              paging,
+		InstallationID:      installationID,
+		CloneInstallationID: cloneInstallationID,
+		States:              states,
+	})
+	if err != nil {
+		c.Logger.WithError(err).Error("Failed to list installation clones")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, cloneOperations)
+}
+
+// handleGetInstallationCloneOperation responds to GET /api/installations/operations/clone/{clone},
+// returns specified installation clone operation.
+func handleGetInstallationCloneOperation(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	cloneID := vars["clone"]
+
+	c.Logger = c.Logger.
+		WithField("action", "get-installation-clone").
+		WithField("clone-operation", cloneID)
+
+	cloneOperation, err := c.Store.GetInstallationCloneOperation(cloneID)
+	if err != nil {
+		c.Logger.WithError(err).Error("Failed to get installation clone")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if cloneOperation == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, cloneOperation)
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/api"
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/internal/testutil"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloneInstallation(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+
+	ts := httptest.NewServer(router)
+	client := model.NewClient(ts.URL)
+
+	source := testutil.CreateBackupCompatibleInstallation(t, sqlStore)
+	source.OwnerID = "source-owner"
+	source.Version = "5.31.0"
+	source.Size = "1000users"
+	source.MattermostEnv = model.EnvVarMap{"KEY": {Value: "value"}}
+	err := sqlStore.UpdateInstallation(source)
+	require.NoError(t, err)
+
+	_, err = sqlStore.CreateInstallationAnnotations(source.ID, []*model.Annotation{{Name: "source-annotation"}})
+	require.NoError(t, err)
+
+	t.Run("fail for unknown installation", func(t *testing.T) {
+		_, err = client.CloneInstallation(model.NewID(), &model.InstallationCloneRequest{DNS: "clone.example.com"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "404")
+	})
+
+	t.Run("fail for invalid DNS", func(t *testing.T) {
+		_, err = client.CloneInstallation(source.ID, &model.InstallationCloneRequest{DNS: "a"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "400")
+	})
+
+	t.Run("fail if installation not hibernating", func(t *testing.T) {
+		stable := testutil.CreateBackupCompatibleInstallation(t, sqlStore)
+		stable.State = model.InstallationStateStable
+		err = sqlStore.UpdateInstallation(stable)
+		require.NoError(t, err)
+
+		_, err = client.CloneInstallation(stable.ID, &model.InstallationCloneRequest{DNS: "clone.example.com"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "400")
+	})
+
+	var cloneOp *model.InstallationCloneOperation
+
+	t.Run("clone installation", func(t *testing.T) {
+		cloneOp, err = client.CloneInstallation(source.ID, &model.InstallationCloneRequest{DNS: "clone.example.com"})
+		require.NoError(t, err)
+		assert.Equal(t, source.ID, cloneOp.InstallationID)
+		assert.Equal(t, model.InstallationCloneStateRequested, cloneOp.State)
+
+		clone, err := client.GetInstallation(cloneOp.CloneInstallationID, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "clone.example.com", clone.DNS)
+		assert.Equal(t, "source-owner", clone.OwnerID)
+		assert.Equal(t, source.Version, clone.Version)
+		assert.Equal(t, source.Size, clone.Size)
+		assert.Equal(t, source.Database, clone.Database)
+		assert.Equal(t, source.Filestore, clone.Filestore)
+		assert.Equal(t, source.MattermostEnv, clone.MattermostEnv)
+		assert.Equal(t, model.InstallationStateCreationRequested, clone.State)
+		assert.True(t, containsAnnotation("source-annotation", clone.Annotations))
+	})
+
+	t.Run("get clone operations", func(t *testing.T) {
+		fetched, err := client.GetInstallationCloneOperation(cloneOp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, cloneOp, fetched)
+
+		cloneOps, err := client.GetInstallationCloneOperations(&model.GetInstallationCloneOperationsRequest{
+			InstallationID: source.ID,
+			Paging:         model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		require.Len(t, cloneOps, 1)
+		assert.Equal(t, cloneOp.ID, cloneOps[0].ID)
+
+		cloneOps, err = client.GetInstallationCloneOperations(&model.GetInstallationCloneOperationsRequest{
+			State:  string(model.InstallationCloneStateSucceeded),
+			Paging: model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		assert.Len(t, cloneOps, 0)
+	})
+}
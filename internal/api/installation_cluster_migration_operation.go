@@ -0,0 +1,139 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/common"
+	"github.com/mattermost/mattermost-cloud/model"
+)
+
+// initInstallationClusterMigration registers installation cluster migration operation endpoints on the given router.
+func initInstallationClusterMigration(apiRouter *mux.Router, context *Context) {
+	addContext := func(handler contextHandlerFunc) *contextHandler {
+		return newContextHandler(context, handler)
+	}
+
+	migrationsRouter := apiRouter.PathPrefix("/operations/cluster/migrations").Subrouter()
+	migrationsRouter.Handle("", addContext(handleTriggerInstallationClusterMigration)).Methods("POST")
+	migrationsRouter.Handle("", addContext(handleGetInstallationClusterMigrationOperations)).Methods("GET")
+
+	migrationRouter := apiRouter.PathPrefix("/operations/cluster/migration/{migration:[A-Za-z0-9]{26}}").Subrouter()
+	migrationRouter.Handle("", addContext(handleGetInstallationClusterMigrationOperation)).Methods("GET")
+}
+
+// handleTriggerInstallationClusterMigration responds to POST /api/installations/operations/cluster/migrations,
+// requests migration of Installation to another cluster.
+func handleTriggerInstallationClusterMigration(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.Logger = c.Logger.
+		WithField("action", "migrate-installation-cluster")
+
+	migrationRequest, err := model.NewInstallationClusterMigrationRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	err = migrationRequest.Validate()
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid cluster migration request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	c.Logger = c.Logger.
+		WithField("installation", migrationRequest.InstallationID).
+		WithField("target-cluster", migrationRequest.TargetClusterID)
+
+	newState := model.InstallationStateClusterMigrationInProgress
+
+	installationDTO, status, unlockOnce := getInstallationForTransition(c, migrationRequest.InstallationID, newState)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	defer unlockOnce()
+
+	migrationOperation, err := common.TriggerInstallationClusterMigration(c.Store, migrationRequest, installationDTO.Installation, c.Environment, c.Logger)
+	if err != nil {
+		c.Logger.WithError(err).Error("Failed to trigger installation cluster migration")
+		w.WriteHeader(common.ErrToStatus(err))
+		return
+	}
+
+	unlockOnce()
+	c.Supervisor.Do()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	outputJSON(c, w, migrationOperation)
+}
+
+// handleGetInstallationClusterMigrationOperations responds to GET /api/installations/operations/cluster/migrations,
+// returns list of installation cluster migration operations.
+func handleGetInstallationClusterMigrationOperations(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.Logger = c.Logger.
+		WithField("action", "list-installation-cluster-migrations")
+
+	paging, err := parsePaging(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse paging parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	installationID := r.URL.Query().Get("installation")
+	sourceClusterID := r.URL.Query().Get("source_cluster")
+	targetClusterID := r.URL.Query().Get("target_cluster")
+	state := r.URL.Query().Get("state")
+	var states []model.InstallationClusterMigrationOperationState
+	if state != "" {
+		states = append(states, model.InstallationClusterMigrationOperationState(state))
+	}
+
+	migrationOperations, err := c.Store.GetInstallationClusterMigrationOperations(&model.InstallationClusterMigrationFilter{
+		Paging:          paging,
+		InstallationID:  installationID,
+		SourceClusterID: sourceClusterID,
+		TargetClusterID: targetClusterID,
+		States:          states,
+	})
+	if err != nil {
+		c.Logger.WithError(err).Error("Failed to list installation cluster migrations")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, migrationOperations)
+}
+
+// handleGetInstallationClusterMigrationOperation responds to GET /api/installations/operations/cluster/migration/{migration},
+// returns specified installation cluster migration operation.
+func handleGetInstallationClusterMigrationOperation(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	migrationID := vars["migration"]
+
+	c.Logger = c.Logger.
+		WithField("action", "get-installation-cluster-migration").
+		WithField("migration-operation", migrationID)
+
+	migrationOperation, err := c.Store.GetInstallationClusterMigrationOperation(migrationID)
+	if err != nil {
+		c.Logger.WithError(err).Error("Failed to get installation cluster migration")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if migrationOperation == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, migrationOperation)
+}
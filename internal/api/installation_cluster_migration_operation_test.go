@@ -0,0 +1,149 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/api"
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTriggerInstallationClusterMigration(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+
+	ts := httptest.NewServer(router)
+	client := model.NewClient(ts.URL)
+
+	installation := &model.Installation{
+		Database:  model.InstallationDatabaseMultiTenantRDSPostgres,
+		Filestore: model.InstallationFilestoreBifrost,
+		State:     model.InstallationStateStable,
+	}
+	err := sqlStore.CreateInstallation(installation, nil)
+	require.NoError(t, err)
+
+	sourceCluster := &model.Cluster{State: model.ClusterStateStable, AllowInstallations: true}
+	err = sqlStore.CreateCluster(sourceCluster, nil)
+	require.NoError(t, err)
+	targetCluster := &model.Cluster{State: model.ClusterStateStable, AllowInstallations: true}
+	err = sqlStore.CreateCluster(targetCluster, nil)
+	require.NoError(t, err)
+	closedCluster := &model.Cluster{State: model.ClusterStateStable, AllowInstallations: false}
+	err = sqlStore.CreateCluster(closedCluster, nil)
+	require.NoError(t, err)
+
+	sourceCI := &model.ClusterInstallation{
+		InstallationID: installation.ID,
+		ClusterID:      sourceCluster.ID,
+		State:          model.ClusterInstallationStateStable,
+	}
+	err = sqlStore.CreateClusterInstallation(sourceCI)
+	require.NoError(t, err)
+
+	t.Run("fail for invalid request", func(t *testing.T) {
+		_, err = client.MigrateInstallationCluster(&model.InstallationClusterMigrationRequest{InstallationID: installation.ID})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "400")
+	})
+	t.Run("fail for unknown installation", func(t *testing.T) {
+		_, err = client.MigrateInstallationCluster(&model.InstallationClusterMigrationRequest{InstallationID: model.NewID(), TargetClusterID: targetCluster.ID})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "404")
+	})
+	t.Run("fail for unknown target cluster", func(t *testing.T) {
+		_, err = client.MigrateInstallationCluster(&model.InstallationClusterMigrationRequest{InstallationID: installation.ID, TargetClusterID: model.NewID()})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "400")
+	})
+	t.Run("fail for current cluster", func(t *testing.T) {
+		_, err = client.MigrateInstallationCluster(&model.InstallationClusterMigrationRequest{InstallationID: installation.ID, TargetClusterID: sourceCluster.ID})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "400")
+	})
+	t.Run("fail for cluster not allowing installations", func(t *testing.T) {
+		_, err = client.MigrateInstallationCluster(&model.InstallationClusterMigrationRequest{InstallationID: installation.ID, TargetClusterID: closedCluster.ID})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "400")
+	})
+
+	t.Run("fail for installation with data stored on the cluster", func(t *testing.T) {
+		operatorInstallation := &model.Installation{
+			Database:  model.InstallationDatabaseMysqlOperator,
+			Filestore: model.InstallationFilestoreMinioOperator,
+			State:     model.InstallationStateStable,
+		}
+		err = sqlStore.CreateInstallation(operatorInstallation, nil)
+		require.NoError(t, err)
+		err = sqlStore.CreateClusterInstallation(&model.ClusterInstallation{
+			InstallationID: operatorInstallation.ID,
+			ClusterID:      sourceCluster.ID,
+			State:          model.ClusterInstallationStateStable,
+		})
+		require.NoError(t, err)
+
+		_, err = client.MigrateInstallationCluster(&model.InstallationClusterMigrationRequest{InstallationID: operatorInstallation.ID, TargetClusterID: targetCluster.ID})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "400")
+	})
+
+	var migrationOp *model.InstallationClusterMigrationOperation
+
+	t.Run("trigger cluster migration", func(t *testing.T) {
+		migrationOp, err = client.MigrateInstallationCluster(&model.InstallationClusterMigrationRequest{InstallationID: installation.ID, TargetClusterID: targetCluster.ID})
+		require.NoError(t, err)
+		assert.Equal(t, installation.ID, migrationOp.InstallationID)
+		assert.Equal(t, sourceCluster.ID, migrationOp.SourceClusterID)
+		assert.Equal(t, targetCluster.ID, migrationOp.TargetClusterID)
+		assert.Equal(t, sourceCI.ID, migrationOp.SourceClusterInstallationID)
+		assert.Equal(t, model.InstallationClusterMigrationStateRequested, migrationOp.State)
+
+		fetchedInstallation, err := client.GetInstallation(installation.ID, nil)
+		require.NoError(t, err)
+		assert.Equal(t, model.InstallationStateClusterMigrationInProgress, fetchedInstallation.State)
+	})
+
+	t.Run("fail when migration already in progress", func(t *testing.T) {
+		_, err = client.MigrateInstallationCluster(&model.InstallationClusterMigrationRequest{InstallationID: installation.ID, TargetClusterID: targetCluster.ID})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "400")
+	})
+
+	t.Run("get cluster migration operations", func(t *testing.T) {
+		fetched, err := client.GetInstallationClusterMigrationOperation(migrationOp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, migrationOp, fetched)
+
+		migrationOps, err := client.GetInstallationClusterMigrationOperations(&model.GetInstallationClusterMigrationOperationsRequest{
+			TargetClusterID: targetCluster.ID,
+			Paging:          model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		require.Len(t, migrationOps, 1)
+		assert.Equal(t, migrationOp.ID, migrationOps[0].ID)
+
+		migrationOps, err = client.GetInstallationClusterMigrationOperations(&model.GetInstallationClusterMigrationOperationsRequest{
+			SourceClusterID: targetCluster.ID,
+			Paging:          model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		assert.Len(t, migrationOps, 0)
+	})
+}
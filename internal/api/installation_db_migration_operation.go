@@ -0,0 +1,216 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/common"
+	"github.com/mattermost/mattermost-cloud/internal/webhook"
+	"github.com/mattermost/mattermost-cloud/model"
+)
+
+// initInstallationDBMigration registers installation db migration operation endpoints on the given router.
+func initInstallationDBMigration(apiRouter *mux.Router, context *Context) {
+	addContext := func(handler contextHandlerFunc) *contextHandler {
+		return newContextHandler(context, handler)
+	}
+
+	migrationsRouter := apiRouter.PathPrefix("/operations/database/migrations").Subrouter()
+
+	migrationsRouter.Handle("", addContext(handleTriggerInstallationDBMigration)).Methods("POST")
+	migrationsRouter.Handle("", addContext(handleGetInstallationDBMigrationOperations)).Methods("GET")
+
+	migrationRouter := apiRouter.PathPrefix("/operations/database/migration/{migration:[A-Za-z0-9]{26}}").Subrouter()
+	migrationRouter.Handle("", addContext(handleGetInstallationDBMigrationOperation)).Methods("GET")
+	migrationRouter.Handle("/cancel", addContext(handleCancelInstallationDBMigration)).Methods("POST")
+}
+
+// handleTriggerInstallationDBMigration responds to POST /api/installations/operations/database/migrations,
+// requests migration of Installation's database.
+func handleTriggerInstallationDBMigration(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.Logger = c.Logger.
+		WithField("action", "migrate-installation-database")
+
+	migrationRequest, err := model.NewInstallationDBMigrationRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	err = migrationRequest.Validate()
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid db migration request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	c.Logger = c.Logger.
+		WithField("installation", migrationRequest.InstallationID).
+		WithField("destination-database", migrationRequest.DestinationMultiTenant.DatabaseID)
+
+	newState := model.InstallationStateDBMigrationInProgress
+
+	installationDTO, status, unlockOnce := getInstallationForTransition(c, migrationRequest.InstallationID, newState)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	defer unlockOnce()
+
+	dbMigrationOperation, err := common.TriggerInstallationDBMigration(c.Store, migrationRequest, installationDTO.Installation, c.Environment, c.Logger)
+	if err != nil {
+		c.Logger.WithError(err).Error("Failed to trigger installation db migration")
+		w.WriteHeader(common.ErrToStatus(err))
+		return
+	}
+
+	unlockOnce()
+	c.Supervisor.Do()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	outputJSON(c, w, dbMigrationOperation)
+}
+
+// handleGetInstallationDBMigrationOperations responds to GET /api/installations/operations/database/migrations,
+// returns list of installation migration operation.
+func handleGetInstallationDBMigrationOperations(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.Logger = c.Logger.
+		WithField("action", "list-installation-db-migrations")
+
+	paging, err := parsePaging(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse paging parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	installationID := r.URL.Query().Get("installation")
+	state := r.URL.Query().Get("state")
+	var states []model.InstallationDBMigrationOperationState
+	if state != "" {
+		states = append(states, model.InstallationDBMigrationOperationState(state))
+	}
+
+	dbMigrations, err := c.Store.GetInstallationDBMigrationOperations(&model.InstallationDBMigrationFilter{
+		Paging:         paging,
+		InstallationID: installationID,
+		States:         states,
+	})
+	if err != nil {
+		c.Logger.WithError(err).Error("Failed to list installation migrations")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, dbMigrations)
+}
+
+// handleGetInstallationDBMigrationOperation responds to GET /api/installations/operations/database/migration/{migration},
+// returns specified installation migration operation.
+func handleGetInstallationDBMigrationOperation(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	migrationID := vars["migration"]
+
+	c.Logger = c.Logger.
+		WithField("action", "get-installation-db-migration").
+		WithField("migration-operation", migrationID)
+
+	dbMigrationOp, err := c.Store.GetInstallationDBMigrationOperation(migrationID)
+	if err != nil {
+		c.Logger.WithError(err).Error("Failed to get installation migration")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if dbMigrationOp == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, dbMigrationOp)
+}
+
+// handleCancelInstallationDBMigration responds to POST /api/installations/operations/database/migration/{migration}/cancel,
+// cancels specified installation migration operation if no work was done yet.
+func handleCancelInstallationDBMigration(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	migrationID := vars["migration"]
+
+	c.Logger = c.Logger.
+		WithField("action", "cancel-installation-db-migration").
+		WithField("migration-operation", migrationID)
+
+	dbMigrationOp, status, unlockMigrationOnce := lockInstallationDBMigrationOperation(c, migrationID)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	defer unlockMigrationOnce()
+
+	if !dbMigrationOp.IsCancelable() {
+		c.Logger.Warnf("unable to cancel db migration while in state %s", dbMigrationOp.State)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	installationDTO, status, unlockOnce := lockInstallation(c, dbMigrationOp.InstallationID)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	defer unlockOnce()
+
+	oldMigrationState := dbMigrationOp.State
+	oldInstallationState := installationDTO.State
+
+	err := c.Store.CancelInstallationDBMigration(dbMigrationOp, installationDTO.Installation)
+	if err != nil {
+		c.Logger.WithError(err).Error("Failed to cancel installation db migration")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	sendInstallationDBMigrationWebhook(c, dbMigrationOp, string(oldMigrationState))
+
+	installationWebhookPayload := &model.WebhookPayload{
+		Type:      model.TypeInstallation,
+		ID:        installationDTO.ID,
+		OwnerID:   installationDTO.OwnerID,
+		NewState:  installationDTO.State,
+		OldState:  oldInstallationState,
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"DNS": installationDTO.DNS, "Environment": c.Environment},
+	}
+	err = webhook.SendToAllWebhooks(c.Store, installationWebhookPayload, c.Logger.WithField("webhookEvent", installationWebhookPayload.NewState))
+	if err != nil {
+		c.Logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, dbMigrationOp)
+}
+
+func sendInstallationDBMigrationWebhook(c *Context, dbMigrationOp *model.InstallationDBMigrationOperation, oldState string) {
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeInstallationDBMigration,
+		ID:        dbMigrationOp.ID,
+		OwnerID:   installationOwnerID(c, dbMigrationOp.InstallationID),
+		NewState:  string(dbMigrationOp.State),
+		OldState:  oldState,
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"Installation": dbMigrationOp.InstallationID, "Environment": c.Environment},
+	}
+	err := webhook.SendToAllWebhooks(c.Store, webhookPayload, c.Logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		c.Logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+}
@@ -0,0 +1,294 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/api"
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/internal/testutil"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTriggerInstallationDBMigration(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+
+	ts := httptest.NewServer(router)
+	client := model.NewClient(ts.URL)
+
+	installation1 := testutil.CreateBackupCompatibleInstallation(t, sqlStore)
+
+	sourceDB := &model.MultitenantDatabase{
+		ID:            "source-db",
+		DatabaseType:  model.DatabaseEngineTypePostgres,
+		Installations: model.MultitenantDatabaseInstallations{installation1.ID},
+	}
+	err := sqlStore.CreateMultitenantDatabase(sourceDB)
+	require.NoError(t, err)
+
+	destinationDB := &model.MultitenantDatabase{
+		ID:           "destination-db",
+		DatabaseType: model.DatabaseEngineTypePostgres,
+	}
+	err = sqlStore.CreateMultitenantDatabase(destinationDB)
+	require.NoError(t, err)
+
+	migrationRequest := &model.InstallationDBMigrationRequest{
+		InstallationID:         installation1.ID,
+		DestinationDatabase:    model.InstallationDatabaseMultiTenantRDSPostgres,
+		DestinationMultiTenant: &model.MultiTenantDBMigrationData{DatabaseID: destinationDB.ID},
+	}
+
+	t.Run("fail for invalid request", func(t *testing.T) {
+		_, err = client.MigrateInstallationDatabase(&model.InstallationDBMigrationRequest{InstallationID: installation1.ID})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "400")
+	})
+	t.Run("fail for unknown installation", func(t *testing.T) {
+		_, err = client.MigrateInstallationDatabase(&model.InstallationDBMigrationRequest{
+			InstallationID:         model.NewID(),
+			DestinationDatabase:    model.InstallationDatabaseMultiTenantRDSPostgres,
+			DestinationMultiTenant: &model.MultiTenantDBMigrationData{DatabaseID: destinationDB.ID},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "404")
+	})
+	t.Run("fail for unknown destination database", func(t *testing.T) {
+		_, err = client.MigrateInstallationDatabase(&model.InstallationDBMigrationRequest{
+			InstallationID:         installation1.ID,
+			DestinationDatabase:    model.InstallationDatabaseMultiTenantRDSPostgres,
+			DestinationMultiTenant: &model.MultiTenantDBMigrationData{DatabaseID: "unknown"},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "400")
+	})
+	t.Run("fail for same source and destination database", func(t *testing.T) {
+		_, err = client.MigrateInstallationDatabase(&model.InstallationDBMigrationRequest{
+			InstallationID:         installation1.ID,
+			DestinationDatabase:    model.InstallationDatabaseMultiTenantRDSPostgres,
+			DestinationMultiTenant: &model.MultiTenantDBMigrationData{DatabaseID: sourceDB.ID},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "400")
+	})
+	t.Run("fail for not hibernated installation", func(t *testing.T) {
+		installation1.State = model.InstallationStateStable
+		err = sqlStore.UpdateInstallation(installation1)
+		require.NoError(t, err)
+		defer func() {
+			installation1.State = model.InstallationStateHibernating
+			err = sqlStore.UpdateInstallation(installation1)
+			require.NoError(t, err)
+		}()
+
+		_, err = client.MigrateInstallationDatabase(migrationRequest)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "400")
+	})
+
+	migrationOp, err := client.MigrateInstallationDatabase(migrationRequest)
+	require.NoError(t, err)
+	assert.NotEmpty(t, migrationOp.ID)
+	assert.Equal(t, model.InstallationDBMigrationStateRequested, migrationOp.State)
+	assert.Equal(t, installation1.ID, migrationOp.InstallationID)
+	assert.Equal(t, model.InstallationDatabaseMultiTenantRDSPostgres, migrationOp.SourceDatabase)
+	assert.Equal(t, sourceDB.ID, migrationOp.SourceMultiTenant.DatabaseID)
+	assert.Equal(t, destinationDB.ID, migrationOp.DestinationMultiTenant.DatabaseID)
+
+	fetchedInstallation, err := sqlStore.GetInstallation(installation1.ID, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, model.InstallationStateDBMigrationInProgress, fetchedInstallation.State)
+
+	t.Run("fail to trigger second migration", func(t *testing.T) {
+		_, err = client.MigrateInstallationDatabase(migrationRequest)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "400")
+	})
+}
+
+func TestGetInstallationDBMigrationOperations(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+
+	ts := httptest.NewServer(router)
+	client := model.NewClient(ts.URL)
+
+	installation1 := testutil.CreateBackupCompatibleInstallation(t, sqlStore)
+	installation2 := testutil.CreateBackupCompatibleInstallation(t, sqlStore)
+
+	migrationOperations := []*model.InstallationDBMigrationOperation{
+		{
+			InstallationID: installation1.ID,
+			State:          model.InstallationDBMigrationStateRequested,
+		},
+		{
+			InstallationID: installation1.ID,
+			State:          model.InstallationDBMigrationStateFailed,
+		},
+		{
+			InstallationID: installation2.ID,
+			State:          model.InstallationDBMigrationStateRequested,
+		},
+		{
+			InstallationID: installation2.ID,
+			State:          model.InstallationDBMigrationStateSucceeded,
+		},
+	}
+
+	for i := range migrationOperations {
+		err := sqlStore.CreateInstallationDBMigrationOperation(migrationOperations[i])
+		require.NoError(t, err)
+		time.Sleep(1 * time.Millisecond)
+	}
+
+	for _, testCase := range []struct {
+		description string
+		filter      model.GetInstallationDBMigrationOperationsRequest
+		found       []*model.InstallationDBMigrationOperation
+	}{
+		{
+			description: "all not deleted",
+			filter:      model.GetInstallationDBMigrationOperationsRequest{Paging: model.AllPagesNotDeleted()},
+			found:       migrationOperations,
+		},
+		{
+			description: "1 per page",
+			filter:      model.GetInstallationDBMigrationOperationsRequest{Paging: model.Paging{PerPage: 1}},
+			found:       []*model.InstallationDBMigrationOperation{migrationOperations[3]},
+		},
+		{
+			description: "filter by installation ID",
+			filter:      model.GetInstallationDBMigrationOperationsRequest{Paging: model.AllPagesNotDeleted(), InstallationID: installation1.ID},
+			found:       []*model.InstallationDBMigrationOperation{migrationOperations[0], migrationOperations[1]},
+		},
+		{
+			description: "filter by state",
+			filter:      model.GetInstallationDBMigrationOperationsRequest{Paging: model.AllPagesNotDeleted(), State: string(model.InstallationDBMigrationStateRequested)},
+			found:       []*model.InstallationDBMigrationOperation{migrationOperations[0], migrationOperations[2]},
+		},
+		{
+			description: "no results",
+			filter:      model.GetInstallationDBMigrationOperationsRequest{Paging: model.AllPagesNotDeleted(), InstallationID: "no-existent"},
+			found:       []*model.InstallationDBMigrationOperation{},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			migrations, err := client.GetInstallationDBMigrationOperations(&testCase.filter)
+			require.NoError(t, err)
+			require.Equal(t, len(testCase.found), len(migrations))
+
+			for i := 0; i < len(testCase.found); i++ {
+				assert.Equal(t, testCase.found[i], migrations[len(testCase.found)-1-i])
+			}
+		})
+	}
+}
+
+func TestGetInstallationDBMigrationOperation(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+
+	ts := httptest.NewServer(router)
+	client := model.NewClient(ts.URL)
+
+	migrationOp := &model.InstallationDBMigrationOperation{
+		InstallationID: "installation",
+		State:          model.InstallationDBMigrationStateBackupInProgress,
+	}
+	err := sqlStore.CreateInstallationDBMigrationOperation(migrationOp)
+	require.NoError(t, err)
+
+	fetchedOp, err := client.GetInstallationDBMigrationOperation(migrationOp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, migrationOp, fetchedOp)
+
+	t.Run("return 404 if operation not found", func(t *testing.T) {
+		_, err = client.GetInstallationDBMigrationOperation(model.NewID())
+		require.EqualError(t, err, "failed with status code 404")
+	})
+}
+
+func TestCancelInstallationDBMigration(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+
+	ts := httptest.NewServer(router)
+	client := model.NewClient(ts.URL)
+
+	installation1 := testutil.CreateBackupCompatibleInstallation(t, sqlStore)
+
+	migrationOp, err := sqlStore.TriggerInstallationDBMigration(&model.InstallationDBMigrationOperation{}, installation1)
+	require.NoError(t, err)
+
+	t.Run("return 404 if operation not found", func(t *testing.T) {
+		_, err = client.CancelInstallationDBMigration(model.NewID())
+		require.EqualError(t, err, "failed with status code 404")
+	})
+
+	canceledOp, err := client.CancelInstallationDBMigration(migrationOp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.InstallationDBMigrationStateCanceled, canceledOp.State)
+
+	fetchedInstallation, err := sqlStore.GetInstallation(installation1.ID, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, model.InstallationStateHibernating, fetchedInstallation.State)
+
+	t.Run("fail to cancel already canceled operation", func(t *testing.T) {
+		_, err = client.CancelInstallationDBMigration(migrationOp.ID)
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("fail to cancel operation in progress", func(t *testing.T) {
+		inProgressOp := &model.InstallationDBMigrationOperation{
+			InstallationID: installation1.ID,
+			State:          model.InstallationDBMigrationStateBackupInProgress,
+		}
+		err = sqlStore.CreateInstallationDBMigrationOperation(inProgressOp)
+		require.NoError(t, err)
+
+		_, err = client.CancelInstallationDBMigration(inProgressOp.ID)
+		require.EqualError(t, err, "failed with status code 400")
+	})
+}
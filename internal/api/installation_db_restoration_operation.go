@@ -39,9 +39,16 @@ func handleTriggerInstallationDBRestoration(c *Context, w http.ResponseWriter, r
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	restoreRequest.SetDefaults()
+	err = restoreRequest.Validate()
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid db restoration request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
 	c.Logger = c.Logger.
 		WithField("installation", restoreRequest.InstallationID).
-		WithField("backup", restoreRequest.BackupID)
+		WithField("mode", restoreRequest.Mode)
 
 	newState := model.InstallationStateDBRestorationInProgress
 
@@ -52,31 +59,73 @@ func handleTriggerInstallationDBRestoration(c *Context, w http.ResponseWriter, r
 	}
 	defer unlockOnce()
 
+	var dbRestoration *model.InstallationDBRestorationOperation
+	if restoreRequest.Mode == model.InstallationDBRestorationModePointInTime {
+		dbRestoration, status = triggerPointInTimeRestoration(c, installationDTO.Installation, restoreRequest)
+	} else {
+		dbRestoration, status = triggerBackupRestoration(c, installationDTO.Installation, restoreRequest)
+	}
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+
+	unlockOnce()
+	c.Supervisor.Do()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	outputJSON(c, w, dbRestoration)
+}
+
+func triggerBackupRestoration(c *Context, installation *model.Installation, restoreRequest *model.InstallationDBRestorationRequest) (*model.InstallationDBRestorationOperation, int) {
+	c.Logger = c.Logger.WithField("backup", restoreRequest.BackupID)
+
 	backup, err := c.Store.GetInstallationBackup(restoreRequest.BackupID)
 	if err != nil {
 		c.Logger.WithError(err).Errorf("failed to get backup")
-		w.WriteHeader(http.StatusInternalServerError)
-		return
+		return nil, http.StatusInternalServerError
 	}
 	if backup == nil {
 		c.Logger.Error("Backup not found")
-		w.WriteHeader(http.StatusNotFound)
-		return
+		return nil, http.StatusNotFound
 	}
 
-	dbRestoration, err := common.TriggerInstallationDBRestoration(c.Store, installationDTO.Installation, backup, c.Environment, c.Logger)
+	dbRestoration, err := common.TriggerInstallationDBRestoration(c.Store, installation, backup, c.Environment, c.Logger)
 	if err != nil {
 		c.Logger.WithError(err).Error("Failed to trigger installation db restoration")
-		w.WriteHeader(common.ErrToStatus(err))
-		return
+		return nil, common.ErrToStatus(err)
 	}
 
-	unlockOnce()
-	c.Supervisor.Do()
+	return dbRestoration, 0
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusAccepted)
-	outputJSON(c, w, dbRestoration)
+func triggerPointInTimeRestoration(c *Context, installation *model.Installation, restoreRequest *model.InstallationDBRestorationRequest) (*model.InstallationDBRestorationOperation, int) {
+	c.Logger = c.Logger.
+		WithField("source-installation", restoreRequest.SourceInstallationID).
+		WithField("restore-time", restoreRequest.RestoreTime)
+
+	source := installation
+	if restoreRequest.SourceInstallationID != installation.ID {
+		var err error
+		source, err = c.Store.GetInstallation(restoreRequest.SourceInstallationID, false, false)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to get source installation")
+			return nil, http.StatusInternalServerError
+		}
+		if source == nil {
+			c.Logger.Error("Source installation not found")
+			return nil, http.StatusNotFound
+		}
+	}
+
+	dbRestoration, err := common.TriggerInstallationDBPointInTimeRestoration(c.Store, installation, source, restoreRequest.RestoreTime, c.Environment, c.Logger)
+	if err != nil {
+		c.Logger.WithError(err).Error("Failed to trigger installation db point-in-time restoration")
+		return nil, common.ErrToStatus(err)
+	}
+
+	return dbRestoration, 0
 }
 
 // handleGetInstallationDBRestorationOperations responds to GET /api/installations/operations/database/restorations,
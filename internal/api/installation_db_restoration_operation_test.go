@@ -74,6 +74,99 @@ func TestTriggerInstallationDBRestoration(t *testing.T) {
 	fetchedInstallation, err := sqlStore.GetInstallation(installation1.ID, false, false)
 	require.NoError(t, err)
 	assert.Equal(t, model.InstallationStateDBRestorationInProgress, fetchedInstallation.State)
+
+	t.Run("restore backup of different installation", func(t *testing.T) {
+		installation2 := testutil.CreateBackupCompatibleInstallation(t, sqlStore)
+
+		restorationOp, err := client.RestoreInstallationDatabase(installation2.ID, backup1.ID)
+		require.NoError(t, err)
+		assert.Equal(t, installation2.ID, restorationOp.InstallationID)
+		assert.Equal(t, installation1.ID, restorationOp.SourceInstallationID)
+		assert.Equal(t, model.InstallationDBRestorationModeBackup, restorationOp.Mode)
+	})
+
+	t.Run("fail for invalid request", func(t *testing.T) {
+		_, err := client.RequestInstallationDBRestoration(&model.InstallationDBRestorationRequest{InstallationID: installation1.ID})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "400")
+	})
+}
+
+func TestTriggerInstallationDBPointInTimeRestoration(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+
+	ts := httptest.NewServer(router)
+	client := model.NewClient(ts.URL)
+
+	createInstallation := func(database string) *model.Installation {
+		installation := &model.Installation{
+			OwnerID:   "owner",
+			DNS:       model.NewID() + ".example.com",
+			Database:  database,
+			Filestore: model.InstallationFilestoreBifrost,
+			State:     model.InstallationStateHibernating,
+		}
+		err := sqlStore.CreateInstallation(installation, nil)
+		require.NoError(t, err)
+		return installation
+	}
+
+	source := createInstallation(model.InstallationDatabaseSingleTenantRDSPostgres)
+	restoreTime := store.GetMillis()
+
+	t.Run("source installation not found", func(t *testing.T) {
+		installation := createInstallation(model.InstallationDatabaseSingleTenantRDSPostgres)
+
+		_, err := client.RequestInstallationDBRestoration(&model.InstallationDBRestorationRequest{
+			InstallationID:       installation.ID,
+			Mode:                 model.InstallationDBRestorationModePointInTime,
+			SourceInstallationID: model.NewID(),
+			RestoreTime:          restoreTime,
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "404")
+	})
+
+	t.Run("database not supported", func(t *testing.T) {
+		installation := createInstallation(model.InstallationDatabaseMultiTenantRDSPostgres)
+
+		_, err := client.RequestInstallationDBRestoration(&model.InstallationDBRestorationRequest{
+			InstallationID: installation.ID,
+			Mode:           model.InstallationDBRestorationModePointInTime,
+			RestoreTime:    restoreTime,
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "400")
+	})
+
+	t.Run("restore to point in time", func(t *testing.T) {
+		installation := createInstallation(model.InstallationDatabaseSingleTenantRDSPostgres)
+
+		restorationOp, err := client.RequestInstallationDBRestoration(&model.InstallationDBRestorationRequest{
+			InstallationID:       installation.ID,
+			Mode:                 model.InstallationDBRestorationModePointInTime,
+			SourceInstallationID: source.ID,
+			RestoreTime:          restoreTime,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, model.InstallationDBRestorationModePointInTime, restorationOp.Mode)
+		assert.Equal(t, installation.ID, restorationOp.InstallationID)
+		assert.Equal(t, source.ID, restorationOp.SourceInstallationID)
+		assert.Equal(t, restoreTime, restorationOp.RestoreTime)
+
+		fetchedInstallation, err := sqlStore.GetInstallation(installation.ID, false, false)
+		require.NoError(t, err)
+		assert.Equal(t, model.InstallationStateDBRestorationInProgress, fetchedInstallation.State)
+	})
 }
 
 func TestGetInstallationDBRestorationOperations(t *testing.T) {
@@ -0,0 +1,54 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/common"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+// handleInstallationSchedulingDryRun responds to GET /api/installation/{installation}/scheduling/dry-run,
+// returning the cluster the installation would be scheduled on together with
+// the evaluation of all clusters. No changes are made.
+func handleInstallationSchedulingDryRun(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	installationID := vars["installation"]
+	c.Logger = c.Logger.
+		WithField("action", "installation-scheduling-dry-run").
+		WithField("installation", installationID)
+
+	policy := r.URL.Query().Get("policy")
+	if policy != "" && !model.IsSupportedSchedulingPolicy(policy) {
+		c.Logger.WithError(errors.Errorf("unsupported scheduling policy %q", policy)).Error("invalid request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	installation, err := c.Store.GetInstallation(installationID, false, false)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query installation")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if installation == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	result, err := c.Scheduler.Schedule(installation, policy)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to evaluate installation scheduling")
+		w.WriteHeader(common.ErrToStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, result)
+}
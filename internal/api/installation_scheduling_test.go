@@ -0,0 +1,94 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/api"
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockScheduler struct {
+	policy string
+}
+
+func (s *mockScheduler) Schedule(installation *model.Installation, policy string) (*model.InstallationSchedulingResult, error) {
+	s.policy = policy
+	if policy == "" {
+		policy = model.SchedulingPolicyFirstFit
+	}
+
+	return &model.InstallationSchedulingResult{
+		InstallationID: installation.ID,
+		Policy:         policy,
+		ClusterID:      "cluster1",
+		Clusters: []*model.ClusterSchedulingDecision{
+			{ClusterID: "cluster1", Eligible: true},
+			{ClusterID: "cluster2", Reason: "cluster is set to not allow for new installation scheduling"},
+		},
+	}, nil
+}
+
+func TestInstallationSchedulingDryRun(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	scheduler := &mockScheduler{}
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Scheduler:  scheduler,
+		Logger:     logger,
+	})
+
+	ts := httptest.NewServer(router)
+	client := model.NewClient(ts.URL)
+
+	installation := &model.Installation{
+		DNS:   "dns.example.com",
+		State: model.InstallationStateStable,
+	}
+	err := sqlStore.CreateInstallation(installation, nil)
+	require.NoError(t, err)
+
+	t.Run("unknown installation", func(t *testing.T) {
+		_, err = client.DryRunInstallationScheduling(model.NewID(), "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "404")
+	})
+
+	t.Run("unsupported policy", func(t *testing.T) {
+		_, err = client.DryRunInstallationScheduling(installation.ID, "unknown")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "400")
+	})
+
+	t.Run("installation policy", func(t *testing.T) {
+		result, err := client.DryRunInstallationScheduling(installation.ID, "")
+		require.NoError(t, err)
+		assert.Empty(t, scheduler.policy)
+		assert.Equal(t, installation.ID, result.InstallationID)
+		assert.Equal(t, "cluster1", result.ClusterID)
+		require.Len(t, result.Clusters, 2)
+		assert.False(t, result.Clusters[1].Eligible)
+		assert.NotEmpty(t, result.Clusters[1].Reason)
+	})
+
+	t.Run("requested policy", func(t *testing.T) {
+		result, err := client.DryRunInstallationScheduling(installation.ID, model.SchedulingPolicySpread)
+		require.NoError(t, err)
+		assert.Equal(t, model.SchedulingPolicySpread, scheduler.policy)
+		assert.Equal(t, model.SchedulingPolicySpread, result.Policy)
+	})
+}
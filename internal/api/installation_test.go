@@ -115,6 +115,38 @@ func TestGetInstallations(t *testing.T) {
 		})
 	})
 
+	t.Run("filter parameter handling", func(t *testing.T) {
+		t.Run("invalid sort by", func(t *testing.T) {
+			resp, err := http.Get(fmt.Sprintf("%s/api/installations?sort_by=invalid", ts.URL))
+			require.NoError(t, err)
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+
+		t.Run("invalid sort order", func(t *testing.T) {
+			resp, err := http.Get(fmt.Sprintf("%s/api/installations?sort_order=invalid", ts.URL))
+			require.NoError(t, err)
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+
+		t.Run("invalid created after", func(t *testing.T) {
+			resp, err := http.Get(fmt.Sprintf("%s/api/installations?created_after=invalid", ts.URL))
+			require.NoError(t, err)
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+
+		t.Run("unknown after", func(t *testing.T) {
+			resp, err := http.Get(fmt.Sprintf("%s/api/installations?after=%s", ts.URL, model.NewID()))
+			require.NoError(t, err)
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+
+		t.Run("after with page", func(t *testing.T) {
+			resp, err := http.Get(fmt.Sprintf("%s/api/installations?after=%s&page=1", ts.URL, model.NewID()))
+			require.NoError(t, err)
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+	})
+
 	t.Run("results", func(t *testing.T) {
 		ownerID1 := model.NewID()
 		ownerID2 := model.NewID()
@@ -295,6 +327,75 @@ func TestGetInstallations(t *testing.T) {
 					},
 					[]*model.Installation{},
 				},
+				{
+					"filter by size",
+					&model.GetInstallationsRequest{
+						Size:   "1000users",
+						Paging: model.AllPagesNotDeleted(),
+					},
+					[]*model.Installation{installation1},
+				},
+				{
+					"filter by dns substring",
+					&model.GetInstallationsRequest{
+						DNSContains: "dns3",
+						Paging:      model.AllPagesNotDeleted(),
+					},
+					[]*model.Installation{installation3},
+				},
+				{
+					"filter by created after",
+					&model.GetInstallationsRequest{
+						CreatedAfter: installation1.CreateAt,
+						Paging:       model.AllPagesNotDeleted(),
+					},
+					[]*model.Installation{installation2, installation3},
+				},
+				{
+					"filter by all annotations",
+					&model.GetInstallationsRequest{
+						Annotations: []string{"multi-tenant", "super-awesome"},
+						Paging:      model.AllPagesNotDeleted(),
+					},
+					[]*model.Installation{installation1},
+				},
+				{
+					"filter by unknown annotation",
+					&model.GetInstallationsRequest{
+						Annotations: []string{"multi-tenant", "unknown"},
+						Paging:      model.AllPagesNotDeleted(),
+					},
+					[]*model.Installation{},
+				},
+				{
+					"filter by any annotations",
+					&model.GetInstallationsRequest{
+						AnyAnnotations: []string{"unknown", "super-awesome"},
+						Paging:         model.AllPagesNotDeleted(),
+					},
+					[]*model.Installation{installation1},
+				},
+				{
+					"sort by dns descending",
+					&model.GetInstallationsRequest{
+						SortBy:    model.InstallationSortByDNS,
+						SortOrder: model.SortOrderDesc,
+						Paging:    model.AllPagesNotDeleted(),
+					},
+					[]*model.Installation{installation3, installation2, installation1},
+				},
+				{
+					"after cursor",
+					&model.GetInstallationsRequest{
+						After: installation1.ID,
+						Paging: model.Paging{
+							Page:           0,
+							PerPage:        1,
+							IncludeDeleted: false,
+						},
+					},
+					[]*model.Installation{installation2},
+				},
 			}
 
 			for _, testCase := range testCases {
@@ -507,6 +608,32 @@ func TestCreateInstallation(t *testing.T) {
 			require.Equal(t, group.ID, *installation.GroupID)
 		})
 
+		t.Run("create with group defaults", func(t *testing.T) {
+			group, err := client.CreateGroup(&model.CreateGroupRequest{
+				Name:      "name-defaults",
+				Version:   "version1",
+				Image:     "sample/image1",
+				Size:      "1000users",
+				Affinity:  model.InstallationAffinityMultiTenant,
+				Database:  model.InstallationDatabaseMultiTenantRDSPostgres,
+				Filestore: model.InstallationFilestoreBifrost,
+			})
+			require.NoError(t, err)
+
+			installation, err := client.CreateInstallation(&model.CreateInstallationRequest{
+				OwnerID:  "owner",
+				GroupID:  group.ID,
+				DNS:      "dns-defaults.example.com",
+				Size:     "100users",
+				Affinity: model.InstallationAffinityIsolated,
+			})
+			require.NoError(t, err)
+			require.Equal(t, "1000users", installation.Size)
+			require.Equal(t, model.InstallationAffinityMultiTenant, installation.Affinity)
+			require.Equal(t, model.InstallationDatabaseMultiTenantRDSPostgres, installation.Database)
+			require.Equal(t, model.InstallationFilestoreBifrost, installation.Filestore)
+		})
+
 		t.Run("create with deleted group", func(t *testing.T) {
 			group, err := client.CreateGroup(&model.CreateGroupRequest{
 				Name:    "name2",
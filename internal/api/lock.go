@@ -150,3 +150,73 @@ func lockInstallationBackup(c *Context, backupID string) (*model.InstallationBac
 		})
 	}
 }
+
+// lockInstallationDBMigrationOperation synchronizes access to the given installation db migration
+// operation across potentially multiple provisioning servers.
+func lockInstallationDBMigrationOperation(c *Context, migrationID string) (*model.InstallationDBMigrationOperation, int, func()) {
+	dbMigrationOp, err := c.Store.GetInstallationDBMigrationOperation(migrationID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query db migration operation")
+		return nil, http.StatusInternalServerError, nil
+	}
+	if dbMigrationOp == nil {
+		return nil, http.StatusNotFound, nil
+	}
+
+	locked, err := c.Store.LockInstallationDBMigrationOperation(migrationID, c.RequestID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to lock db migration operation")
+		return nil, http.StatusInternalServerError, nil
+	} else if !locked {
+		c.Logger.Error("failed to acquire lock for db migration operation")
+		return nil, http.StatusConflict, nil
+	}
+
+	unlockOnce := sync.Once{}
+
+	return dbMigrationOp, 0, func() {
+		unlockOnce.Do(func() {
+			unlocked, err := c.Store.UnlockInstallationDBMigrationOperation(dbMigrationOp.ID, c.RequestID, false)
+			if err != nil {
+				c.Logger.WithError(err).Errorf("failed to unlock db migration operation")
+			} else if unlocked != true {
+				c.Logger.Warn("failed to release lock for db migration operation")
+			}
+		})
+	}
+}
+
+// lockBulkOperation synchronizes access to the given bulk operation across
+// potentially multiple provisioning servers.
+func lockBulkOperation(c *Context, operationID string) (*model.BulkOperation, int, func()) {
+	operation, err := c.Store.GetBulkOperation(operationID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query bulk operation")
+		return nil, http.StatusInternalServerError, nil
+	}
+	if operation == nil {
+		return nil, http.StatusNotFound, nil
+	}
+
+	locked, err := c.Store.LockBulkOperation(operationID, c.RequestID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to lock bulk operation")
+		return nil, http.StatusInternalServerError, nil
+	} else if !locked {
+		c.Logger.Error("failed to acquire lock for bulk operation")
+		return nil, http.StatusConflict, nil
+	}
+
+	unlockOnce := sync.Once{}
+
+	return operation, 0, func() {
+		unlockOnce.Do(func() {
+			unlocked, err := c.Store.UnlockBulkOperation(operation.ID, c.RequestID, false)
+			if err != nil {
+				c.Logger.WithError(err).Errorf("failed to unlock bulk operation")
+			} else if unlocked != true {
+				c.Logger.Warn("failed to release lock for bulk operation")
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ func TestNewCreateClusterRequestFromReader(t *testing.T) {
 	defaultCreateClusterRequest := func() *model.CreateClusterRequest {
 		return &model.CreateClusterRequest{
 			Provider:           "aws",
+			Provisioner:        model.ProvisionerKops,
 			Version:            "latest",
 			MasterInstanceType: "t3.medium",
 			MasterCount:        1,
@@ -79,6 +80,7 @@ func TestNewCreateClusterRequestFromReader(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, &model.CreateClusterRequest{
 			Provider:           model.ProviderAWS,
+			Provisioner:        model.ProvisionerKops,
 			Version:            "1.12.4",
 			MasterInstanceType: "t3.medium",
 			MasterCount:        1,
@@ -0,0 +1,178 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/model"
+)
+
+// initScheduledOperation registers scheduled operation endpoints on the given router.
+func initScheduledOperation(apiRouter *mux.Router, context *Context) {
+	addContext := func(handler contextHandlerFunc) *contextHandler {
+		return newContextHandler(context, handler)
+	}
+
+	operationsRouter := apiRouter.PathPrefix("/scheduled_operations").Subrouter()
+	operationsRouter.Handle("", addContext(handleCreateScheduledOperation)).Methods("POST")
+	operationsRouter.Handle("", addContext(handleGetScheduledOperations)).Methods("GET")
+
+	operationRouter := apiRouter.PathPrefix("/scheduled_operation/{operation:[A-Za-z0-9]{26}}").Subrouter()
+	operationRouter.Handle("", addContext(handleGetScheduledOperation)).Methods("GET")
+	operationRouter.Handle("", addContext(handleCancelScheduledOperation)).Methods("DELETE")
+}
+
+// handleCreateScheduledOperation responds to POST /api/installations/scheduled_operations,
+// schedules an installation state change.
+func handleCreateScheduledOperation(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.Logger = c.Logger.
+		WithField("action", "create-scheduled-operation")
+
+	operationRequest, err := model.NewCreateScheduledOperationRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	installation, err := c.Store.GetInstallation(operationRequest.InstallationID, false, false)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query installation")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if installation == nil || installation.State == model.InstallationStateDeleted {
+		c.Logger.Error("installation to schedule operation for does not exist")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if restrictedOwner := ownerRestriction(c); restrictedOwner != "" && installation.OwnerID != restrictedOwner {
+		c.Logger.Warnf("API key is not allowed to schedule operations for installations of owner %s", installation.OwnerID)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	if installation.APISecurityLock {
+		logSecurityLockConflict("installation", c.Logger)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	operation := &model.ScheduledOperation{
+		InstallationID:  operationRequest.InstallationID,
+		Type:            operationRequest.Type,
+		Patch:           operationRequest.Patch,
+		RunAt:           operationRequest.RunAt,
+		IntervalSeconds: operationRequest.IntervalSeconds,
+	}
+
+	err = c.Store.CreateScheduledOperation(operation)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to create scheduled operation")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	c.Supervisor.Do()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, operation)
+}
+
+// handleGetScheduledOperations responds to GET /api/installations/scheduled_operations,
+// returns the specified page of scheduled operations.
+func handleGetScheduledOperations(c *Context, w http.ResponseWriter, r *http.Request) {
+	c.Logger = c.Logger.
+		WithField("action", "list-scheduled-operations")
+
+	paging, err := parsePaging(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse paging parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	filter := &model.ScheduledOperationFilter{
+		InstallationID: r.URL.Query().Get("installation"),
+		Paging:         paging,
+	}
+	if state := r.URL.Query().Get("state"); state != "" {
+		filter.States = []model.ScheduledOperationState{model.ScheduledOperationState(state)}
+	}
+
+	operations, err := c.Store.GetScheduledOperations(filter)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to list scheduled operations")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if operations == nil {
+		operations = []*model.ScheduledOperation{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, operations)
+}
+
+// handleGetScheduledOperation responds to GET /api/installations/scheduled_operation/{operation},
+// returns the specified scheduled operation.
+func handleGetScheduledOperation(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	operationID := vars["operation"]
+	c.Logger = c.Logger.
+		WithField("scheduledOperation", operationID).
+		WithField("action", "get-scheduled-operation")
+
+	operation, err := c.Store.GetScheduledOperation(operationID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get scheduled operation")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if operation == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, operation)
+}
+
+// handleCancelScheduledOperation responds to DELETE /api/installations/scheduled_operation/{operation},
+// cancels the scheduled operation. Installation state changes already
+// requested by the operation are not reverted.
+func handleCancelScheduledOperation(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	operationID := vars["operation"]
+	c.Logger = c.Logger.
+		WithField("scheduledOperation", operationID).
+		WithField("action", "cancel-scheduled-operation")
+
+	operation, err := c.Store.GetScheduledOperation(operationID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to get scheduled operation")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if operation == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if !operation.IsDeleted() {
+		err = c.Store.CancelScheduledOperation(operation.ID)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to cancel scheduled operation")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
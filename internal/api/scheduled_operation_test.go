@@ -0,0 +1,136 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/api"
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduledOperations(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+	client := model.NewClient(ts.URL)
+
+	installation, err := client.CreateInstallation(
+		&model.CreateInstallationRequest{
+			OwnerID: "owner",
+			Version: "version",
+			DNS:     "dns1.example.com",
+		})
+	require.NoError(t, err)
+
+	version := "version2"
+
+	t.Run("invalid requests", func(t *testing.T) {
+		for _, request := range []*model.CreateScheduledOperationRequest{
+			{Type: model.ScheduledOperationTypeHibernate, RunAt: 1000},
+			{InstallationID: installation.ID, Type: "unknown", RunAt: 1000},
+			{InstallationID: installation.ID, Type: model.ScheduledOperationTypeUpdate, RunAt: 1000},
+			{InstallationID: installation.ID, Type: model.ScheduledOperationTypeDelete, RunAt: 1000, IntervalSeconds: 86400},
+			{InstallationID: model.NewID(), Type: model.ScheduledOperationTypeHibernate, RunAt: 1000},
+		} {
+			_, err = client.CreateScheduledOperation(request)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "400")
+		}
+	})
+
+	hibernateOperation, err := client.CreateScheduledOperation(&model.CreateScheduledOperationRequest{
+		InstallationID:  installation.ID,
+		Type:            model.ScheduledOperationTypeHibernate,
+		RunAt:           2000,
+		IntervalSeconds: 86400,
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, hibernateOperation.ID)
+	assert.Equal(t, model.ScheduledOperationStateScheduled, hibernateOperation.State)
+
+	updateOperation, err := client.CreateScheduledOperation(&model.CreateScheduledOperationRequest{
+		InstallationID: installation.ID,
+		Type:           model.ScheduledOperationTypeUpdate,
+		Patch:          &model.PatchInstallationRequest{Version: &version},
+		RunAt:          1000,
+	})
+	require.NoError(t, err)
+
+	t.Run("get operation", func(t *testing.T) {
+		operation, err := client.GetScheduledOperation(updateOperation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, updateOperation, operation)
+
+		operation, err = client.GetScheduledOperation(model.NewID())
+		require.NoError(t, err)
+		assert.Nil(t, operation)
+	})
+
+	t.Run("list operations", func(t *testing.T) {
+		operations, err := client.GetScheduledOperations(&model.GetScheduledOperationsRequest{
+			InstallationID: installation.ID,
+			Paging:         model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.ScheduledOperation{updateOperation, hibernateOperation}, operations)
+
+		operations, err = client.GetScheduledOperations(&model.GetScheduledOperationsRequest{
+			State:  string(model.ScheduledOperationStateSucceeded),
+			Paging: model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		assert.Empty(t, operations)
+	})
+
+	t.Run("cancel operation", func(t *testing.T) {
+		err := client.CancelScheduledOperation(hibernateOperation.ID)
+		require.NoError(t, err)
+
+		operation, err := client.GetScheduledOperation(hibernateOperation.ID)
+		require.NoError(t, err)
+		assert.True(t, operation.IsDeleted())
+		assert.Equal(t, model.ScheduledOperationStateCancelled, operation.State)
+
+		operations, err := client.GetScheduledOperations(&model.GetScheduledOperationsRequest{
+			Paging: model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.ScheduledOperation{updateOperation}, operations)
+
+		err = client.CancelScheduledOperation(model.NewID())
+		require.Error(t, err)
+	})
+
+	t.Run("fail for locked installation", func(t *testing.T) {
+		err := sqlStore.LockInstallationAPI(installation.ID)
+		require.NoError(t, err)
+		defer sqlStore.UnlockInstallationAPI(installation.ID)
+
+		_, err = client.CreateScheduledOperation(&model.CreateScheduledOperationRequest{
+			InstallationID: installation.ID,
+			Type:           model.ScheduledOperationTypeDelete,
+			RunAt:          1000,
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "403")
+	})
+}
@@ -24,6 +24,7 @@ func initWebhook(apiRouter *mux.Router, context *Context) {
 	webhookRouter := apiRouter.PathPrefix("/webhook/{webhook:[A-Za-z0-9]{26}}").Subrouter()
 	webhookRouter.Handle("", addContext(handleGetWebhook)).Methods("GET")
 	webhookRouter.Handle("", addContext(handleDeleteWebhook)).Methods("DELETE")
+	webhookRouter.Handle("/deliveries", addContext(handleGetWebhookDeliveries)).Methods("GET")
 }
 
 // handleCreateWebhook responds to POST /api/webhooks, creating a new webhook.
@@ -35,9 +36,21 @@ func handleCreateWebhook(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	secret := createWebhookRequest.Secret
+	if secret == "" {
+		secret, err = model.NewWebhookSecret()
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to generate webhook secret")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
 	webhook := model.Webhook{
-		OwnerID: createWebhookRequest.OwnerID,
-		URL:     createWebhookRequest.URL,
+		OwnerID:      createWebhookRequest.OwnerID,
+		URL:          createWebhookRequest.URL,
+		Secret:       secret,
+		Subscription: createWebhookRequest.Subscription,
 	}
 
 	err = c.Store.CreateWebhook(&webhook)
@@ -68,6 +81,7 @@ func handleGetWebhook(c *Context, w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
 		return
 	}
+	webhook.Secret = ""
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -100,6 +114,9 @@ func handleGetWebhooks(c *Context, w http.ResponseWriter, r *http.Request) {
 	if webhooks == nil {
 		webhooks = []*model.Webhook{}
 	}
+	for _, webhook := range webhooks {
+		webhook.Secret = ""
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -137,3 +154,67 @@ func handleDeleteWebhook(c *Context, w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusOK)
 }
+
+// handleGetWebhookDeliveries responds to GET /api/webhook/{webhook}/deliveries,
+// returning the specified page of deliveries to the webhook, the newest first.
+func handleGetWebhookDeliveries(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	webhookID := vars["webhook"]
+	c.Logger = c.Logger.WithField("webhook", webhookID)
+
+	paging, err := parsePaging(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse paging parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	webhook, err := c.Store.GetWebhook(webhookID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query webhook")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if webhook == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	filter := &model.WebhookDeliveryFilter{
+		WebhookID: webhookID,
+		Paging:    paging,
+	}
+	state := r.URL.Query().Get("state")
+	if state != "" {
+		filter.States = []string{state}
+	}
+
+	deliveries, err := c.Store.GetWebhookDeliveries(filter)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query webhook deliveries")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if deliveries == nil {
+		deliveries = []*model.WebhookDelivery{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, deliveries)
+}
+
+// installationOwnerID returns the owner of the given installation to be
+// included in webhook payloads, or an empty string if it cannot be found.
+func installationOwnerID(c *Context, installationID string) string {
+	installation, err := c.Store.GetInstallation(installationID, false, false)
+	if err != nil {
+		c.Logger.WithError(err).Warn("Failed to get installation owner for webhook")
+		return ""
+	}
+	if installation == nil {
+		return ""
+	}
+
+	return installation.OwnerID
+}
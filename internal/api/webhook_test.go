@@ -78,9 +78,52 @@ func TestCreateWebhook(t *testing.T) {
 		require.NotEmpty(t, webhook.ID)
 		require.Equal(t, "owner", webhook.OwnerID)
 		require.Equal(t, "https://validurl.com", webhook.URL)
+		require.Len(t, webhook.Secret, 64)
 		require.NotEqual(t, 0, webhook.CreateAt)
 		require.EqualValues(t, 0, webhook.DeleteAt)
 	})
+
+	t.Run("custom secret", func(t *testing.T) {
+		webhook, err := client.CreateWebhook(&model.CreateWebhookRequest{
+			OwnerID: "owner",
+			URL:     "https://validurl2.com",
+			Secret:  "secret",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "secret", webhook.Secret)
+
+		webhook, err = client.GetWebhook(webhook.ID)
+		require.NoError(t, err)
+		require.Empty(t, webhook.Secret)
+	})
+
+	t.Run("invalid subscription", func(t *testing.T) {
+		_, err := client.CreateWebhook(&model.CreateWebhookRequest{
+			OwnerID:      "owner",
+			URL:          "https://validurl3.com",
+			Subscription: &model.WebhookSubscription{Types: []string{"unknown"}},
+		})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("subscription", func(t *testing.T) {
+		subscription := &model.WebhookSubscription{
+			Types:   []string{model.TypeInstallation},
+			States:  []string{model.InstallationStateStable},
+			OwnerID: "installation-owner",
+		}
+		webhook, err := client.CreateWebhook(&model.CreateWebhookRequest{
+			OwnerID:      "owner",
+			URL:          "https://validurl4.com",
+			Subscription: subscription,
+		})
+		require.NoError(t, err)
+		require.Equal(t, subscription, webhook.Subscription)
+
+		webhook, err = client.GetWebhook(webhook.ID)
+		require.NoError(t, err)
+		require.Equal(t, subscription, webhook.Subscription)
+	})
 }
 
 func TestGetWebhooks(t *testing.T) {
@@ -181,6 +224,12 @@ func TestGetWebhooks(t *testing.T) {
 		webhook4, err = client.GetWebhook(webhook4.ID)
 		require.NoError(t, err)
 
+		// Secrets are only returned when the webhook is created.
+		for _, webhook := range []*model.Webhook{webhook1, webhook2, webhook3} {
+			require.NotEmpty(t, webhook.Secret)
+			webhook.Secret = ""
+		}
+
 		t.Run("get webhook", func(t *testing.T) {
 			t.Run("webhook 1", func(t *testing.T) {
 				webhook, err := client.GetWebhook(webhook1.ID)
@@ -311,3 +360,62 @@ func TestDeleteWebhook(t *testing.T) {
 		require.True(t, webhook.IsDeleted())
 	})
 }
+
+func TestGetWebhookDeliveries(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	webhook, err := client.CreateWebhook(&model.CreateWebhookRequest{
+		OwnerID: "owner",
+		URL:     "https://validurl.com",
+	})
+	require.NoError(t, err)
+
+	delivery1 := &model.WebhookDelivery{
+		WebhookID: webhook.ID,
+		Payload:   &model.WebhookPayload{ID: model.NewID(), Type: model.TypeCluster},
+	}
+	err = sqlStore.CreateWebhookDelivery(delivery1)
+	require.NoError(t, err)
+
+	time.Sleep(1 * time.Millisecond)
+
+	delivery2 := &model.WebhookDelivery{
+		WebhookID: webhook.ID,
+		Payload:   &model.WebhookPayload{ID: model.NewID(), Type: model.TypeInstallation},
+		State:     model.WebhookDeliveryStateDelivered,
+	}
+	err = sqlStore.CreateWebhookDelivery(delivery2)
+	require.NoError(t, err)
+
+	t.Run("unknown webhook", func(t *testing.T) {
+		_, err := client.GetWebhookDeliveries(model.NewID(), &model.GetWebhookDeliveriesRequest{Paging: model.AllPagesNotDeleted()})
+		require.EqualError(t, err, "failed with status code 404")
+	})
+
+	t.Run("all deliveries", func(t *testing.T) {
+		deliveries, err := client.GetWebhookDeliveries(webhook.ID, &model.GetWebhookDeliveriesRequest{Paging: model.AllPagesNotDeleted()})
+		require.NoError(t, err)
+		require.Equal(t, []*model.WebhookDelivery{delivery2, delivery1}, deliveries)
+	})
+
+	t.Run("filter by state", func(t *testing.T) {
+		deliveries, err := client.GetWebhookDeliveries(webhook.ID, &model.GetWebhookDeliveriesRequest{
+			State:  model.WebhookDeliveryStatePending,
+			Paging: model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, []*model.WebhookDelivery{delivery1}, deliveries)
+	})
+}
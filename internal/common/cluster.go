@@ -0,0 +1,83 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package common
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/webhook"
+	"github.com/mattermost/mattermost-cloud/model"
+	log "github.com/sirupsen/logrus"
+)
+
+type clusterCreationStore interface {
+	CreateCluster(cluster *model.Cluster, annotations []*model.Annotation) error
+	webhookStore
+}
+
+// CreateCluster creates and reports a new cluster from the given request.
+func CreateCluster(store clusterCreationStore, request *model.CreateClusterRequest, env string, logger log.FieldLogger) (*model.ClusterDTO, error) {
+	cluster := model.Cluster{
+		Provider: request.Provider,
+		ProviderMetadataAWS: &model.AWSMetadata{
+			Zones: request.Zones,
+		},
+		Provisioner:        request.Provisioner,
+		AllowInstallations: request.AllowInstallations,
+		APISecurityLock:    request.APISecurityLock,
+		State:              model.ClusterStateCreationRequested,
+	}
+
+	if cluster.Provisioner == model.ProvisionerLocal {
+		cluster.ProvisionerMetadataLocal = &model.LocalMetadata{
+			KubeconfigPath: request.KubeconfigPath,
+		}
+	} else {
+		cluster.ProvisionerMetadataKops = &model.KopsMetadata{
+			ChangeRequest: &model.KopsMetadataRequestedState{
+				Version:            request.Version,
+				AMI:                request.KopsAMI,
+				MasterInstanceType: request.MasterInstanceType,
+				MasterCount:        request.MasterCount,
+				NodeInstanceType:   request.NodeInstanceType,
+				NodeMinCount:       request.NodeMinCount,
+				NodeMaxCount:       request.NodeMaxCount,
+				Networking:         request.Networking,
+				VPC:                request.VPC,
+			},
+		}
+	}
+
+	err := cluster.SetUtilityDesiredVersions(request.DesiredUtilityVersions)
+	if err != nil {
+		return nil, ErrWrap(http.StatusBadRequest, err, "provided utility metadata could not be applied without error")
+	}
+
+	annotations, err := model.AnnotationsFromStringSlice(request.Annotations)
+	if err != nil {
+		return nil, ErrWrap(http.StatusBadRequest, err, "failed to validate extra annotations")
+	}
+
+	err = store.CreateCluster(&cluster, annotations)
+	if err != nil {
+		return nil, ErrWrap(http.StatusInternalServerError, err, "failed to create cluster")
+	}
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeCluster,
+		ID:        cluster.ID,
+		NewState:  model.ClusterStateCreationRequested,
+		OldState:  "n/a",
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"Environment": env},
+	}
+	err = webhook.SendToAllWebhooks(store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	return cluster.ToDTO(annotations), nil
+}
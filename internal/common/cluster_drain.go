@@ -0,0 +1,117 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package common
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/webhook"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+type clusterDrainStore interface {
+	GetClusterDrainOperations(filter *model.ClusterDrainFilter) ([]*model.ClusterDrainOperation, error)
+	TriggerClusterDrain(drainOp *model.ClusterDrainOperation, cluster *model.Cluster) (*model.ClusterDrainOperation, error)
+	webhookStore
+}
+
+// TriggerClusterDrain validates, triggers and reports cluster drain.
+func TriggerClusterDrain(store clusterDrainStore, request *model.ClusterDrainRequest, cluster *model.Cluster, env string, logger log.FieldLogger) (*model.ClusterDrainOperation, error) {
+	switch cluster.State {
+	case model.ClusterStateDeletionRequested, model.ClusterStateDeletionFailed, model.ClusterStateDeleted:
+		return nil, NewErr(http.StatusBadRequest, errors.Errorf("cluster in state %q cannot be drained", cluster.State))
+	}
+
+	activeDrains, err := store.GetClusterDrainOperations(&model.ClusterDrainFilter{
+		ClusterID: cluster.ID,
+		States:    model.AllClusterDrainOperationsStatesPendingWork,
+		Paging:    model.AllPagesNotDeleted(),
+	})
+	if err != nil {
+		return nil, ErrWrap(http.StatusInternalServerError, err, "failed to get cluster drain operations")
+	}
+	if len(activeDrains) > 0 {
+		return nil, NewErr(http.StatusBadRequest, errors.Errorf("cluster is already being drained by operation %s", activeDrains[0].ID))
+	}
+
+	drainOp := &model.ClusterDrainOperation{
+		MaxConcurrentMigrations: request.MaxConcurrentMigrations,
+	}
+
+	drainOp, err = store.TriggerClusterDrain(drainOp, cluster)
+	if err != nil {
+		return nil, ErrWrap(http.StatusInternalServerError, err, "failed to create cluster drain operation")
+	}
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeClusterDrain,
+		ID:        drainOp.ID,
+		NewState:  string(drainOp.State),
+		OldState:  "n/a",
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"Cluster": drainOp.ClusterID, "Environment": env},
+	}
+	err = webhook.SendToAllWebhooks(store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	return drainOp, nil
+}
+
+type clusterDrainStatusStore interface {
+	GetClusterInstallations(filter *model.ClusterInstallationFilter) ([]*model.ClusterInstallation, error)
+	GetInstallationClusterMigrationOperations(filter *model.InstallationClusterMigrationFilter) ([]*model.InstallationClusterMigrationOperation, error)
+}
+
+// GetClusterDrainStatus calculates the progress of the given cluster drain operation.
+func GetClusterDrainStatus(store clusterDrainStatusStore, drainOp *model.ClusterDrainOperation) (*model.ClusterDrainStatus, error) {
+	migrationOps, err := store.GetInstallationClusterMigrationOperations(&model.InstallationClusterMigrationFilter{
+		SourceClusterID: drainOp.ClusterID,
+		Paging:          model.AllPagesNotDeleted(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get installation cluster migrations")
+	}
+
+	clusterInstallations, err := store.GetClusterInstallations(&model.ClusterInstallationFilter{
+		ClusterID: drainOp.ClusterID,
+		Paging:    model.AllPagesNotDeleted(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get cluster installations")
+	}
+
+	status := &model.ClusterDrainStatus{Operation: drainOp}
+	migrating := map[string]bool{}
+
+	for _, migrationOp := range migrationOps {
+		if migrationOp.IsInProgress() {
+			status.MigratingInstallations++
+			migrating[migrationOp.SourceClusterInstallationID] = true
+			continue
+		}
+		if migrationOp.RequestAt < drainOp.RequestAt {
+			continue
+		}
+		switch migrationOp.State {
+		case model.InstallationClusterMigrationStateSucceeded:
+			status.MigratedInstallations++
+		case model.InstallationClusterMigrationStateFailed:
+			status.FailedMigrations++
+		}
+	}
+
+	for _, clusterInstallation := range clusterInstallations {
+		if !migrating[clusterInstallation.ID] {
+			status.RemainingInstallations++
+		}
+	}
+
+	return status, nil
+}
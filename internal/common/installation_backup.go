@@ -18,10 +18,22 @@ type installationBackupStore interface {
 	IsInstallationBackupRunning(installationID string) (bool, error)
 	CreateInstallationBackup(backup *model.InstallationBackup) error
 	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
 }
 
 // TriggerInstallationBackup verifies that backup can be started for an Installation and triggers it.
 func TriggerInstallationBackup(store installationBackupStore, installation *model.Installation, env string, logger log.FieldLogger) (*model.InstallationBackup, error) {
+	return triggerInstallationBackup(store, installation, "", env, logger)
+}
+
+// TriggerScheduledInstallationBackup verifies that backup can be started for an Installation
+// and triggers it on behalf of the given backup schedule.
+func TriggerScheduledInstallationBackup(store installationBackupStore, installation *model.Installation, scheduleID, env string, logger log.FieldLogger) (*model.InstallationBackup, error) {
+	return triggerInstallationBackup(store, installation, scheduleID, env, logger)
+}
+
+func triggerInstallationBackup(store installationBackupStore, installation *model.Installation, scheduleID, env string, logger log.FieldLogger) (*model.InstallationBackup, error) {
 	err := model.EnsureInstallationReadyForBackup(installation)
 	if err != nil {
 		return nil, ErrWrap(http.StatusBadRequest, err, "installation cannot be backed up")
@@ -37,6 +49,7 @@ func TriggerInstallationBackup(store installationBackupStore, installation *mode
 
 	backup := &model.InstallationBackup{
 		InstallationID: installation.ID,
+		ScheduleID:     scheduleID,
 		State:          model.InstallationBackupStateBackupRequested,
 	}
 
@@ -48,6 +61,7 @@ func TriggerInstallationBackup(store installationBackupStore, installation *mode
 	webhookPayload := &model.WebhookPayload{
 		Type:      model.TypeInstallationBackup,
 		ID:        backup.ID,
+		OwnerID:   installation.OwnerID,
 		NewState:  string(backup.State),
 		OldState:  "n/a",
 		Timestamp: time.Now().UnixNano(),
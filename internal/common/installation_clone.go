@@ -0,0 +1,74 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package common
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/webhook"
+	"github.com/mattermost/mattermost-cloud/model"
+	log "github.com/sirupsen/logrus"
+)
+
+type installationCloneStore interface {
+	GetAnnotationsForInstallation(installationID string) ([]*model.Annotation, error)
+	TriggerInstallationClone(source, clone *model.Installation, annotations []*model.Annotation) (*model.InstallationCloneOperation, error)
+	webhookStore
+}
+
+// TriggerInstallationClone validates, triggers and reports creation of the installation clone.
+func TriggerInstallationClone(store installationCloneStore, source *model.Installation, request *model.InstallationCloneRequest, env string, logger log.FieldLogger) (*model.InstallationCloneOperation, error) {
+	err := model.EnsureInstallationReadyForClone(source)
+	if err != nil {
+		return nil, ErrWrap(http.StatusBadRequest, err, "installation cannot be cloned")
+	}
+
+	annotations, err := store.GetAnnotationsForInstallation(source.ID)
+	if err != nil {
+		return nil, ErrWrap(http.StatusInternalServerError, err, "failed to get annotations of source installation")
+	}
+
+	clone := model.NewInstallationCloneFromSource(source, request)
+
+	cloneOp, err := store.TriggerInstallationClone(source, clone, annotations)
+	if err != nil {
+		return nil, ErrWrap(http.StatusInternalServerError, err, "failed to create Installation clone operation")
+	}
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeInstallationClone,
+		ID:        cloneOp.ID,
+		OwnerID:   source.OwnerID,
+		NewState:  string(cloneOp.State),
+		OldState:  "n/a",
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{
+			"Installation":      cloneOp.InstallationID,
+			"CloneInstallation": cloneOp.CloneInstallationID,
+			"Environment":       env,
+		},
+	}
+	err = webhook.SendToAllWebhooks(store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	installationWebhookPayload := &model.WebhookPayload{
+		Type:      model.TypeInstallation,
+		ID:        clone.ID,
+		OwnerID:   clone.OwnerID,
+		NewState:  clone.State,
+		OldState:  "n/a",
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"DNS": clone.DNS, "Environment": env},
+	}
+	err = webhook.SendToAllWebhooks(store, installationWebhookPayload, logger.WithField("webhookEvent", installationWebhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	return cloneOp, nil
+}
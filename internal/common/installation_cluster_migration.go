@@ -0,0 +1,119 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package common
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/webhook"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+type installationClusterMigrationStore interface {
+	GetCluster(id string) (*model.Cluster, error)
+	GetClusterInstallations(filter *model.ClusterInstallationFilter) ([]*model.ClusterInstallation, error)
+	TriggerInstallationClusterMigration(migrationOp *model.InstallationClusterMigrationOperation, installation *model.Installation) (*model.InstallationClusterMigrationOperation, error)
+	webhookStore
+}
+
+// TriggerInstallationClusterMigration validates, triggers and reports installation migration to another cluster.
+func TriggerInstallationClusterMigration(store installationClusterMigrationStore, request *model.InstallationClusterMigrationRequest, installation *model.Installation, env string, logger log.FieldLogger) (*model.InstallationClusterMigrationOperation, error) {
+	err := model.EnsureInstallationReadyForClusterMigration(installation)
+	if err != nil {
+		return nil, ErrWrap(http.StatusBadRequest, err, "installation cannot be migrated")
+	}
+
+	clusterInstallations, err := store.GetClusterInstallations(&model.ClusterInstallationFilter{
+		InstallationID: installation.ID,
+		Paging:         model.AllPagesNotDeleted(),
+	})
+	if err != nil {
+		return nil, ErrWrap(http.StatusInternalServerError, err, "failed to get cluster installations")
+	}
+	if len(clusterInstallations) != 1 {
+		return nil, NewErr(http.StatusBadRequest, errors.Errorf("expected exactly one cluster installation, found %d", len(clusterInstallations)))
+	}
+	sourceClusterInstallation := clusterInstallations[0]
+
+	if sourceClusterInstallation.ClusterID == request.TargetClusterID {
+		return nil, NewErr(http.StatusBadRequest, errors.New("installation is already running on target cluster"))
+	}
+
+	targetCluster, err := store.GetCluster(request.TargetClusterID)
+	if err != nil {
+		return nil, ErrWrap(http.StatusInternalServerError, err, "failed to get target cluster")
+	}
+	if targetCluster == nil {
+		return nil, NewErr(http.StatusBadRequest, errors.Errorf("target cluster %q not found", request.TargetClusterID))
+	}
+	err = model.EnsureClusterReadyForInstallationMigration(targetCluster, installation)
+	if err != nil {
+		return nil, ErrWrap(http.StatusBadRequest, err, "installation cannot be migrated to target cluster")
+	}
+
+	if installation.Affinity == model.InstallationAffinityIsolated {
+		targetClusterInstallations, err := store.GetClusterInstallations(&model.ClusterInstallationFilter{
+			ClusterID: targetCluster.ID,
+			Paging:    model.AllPagesNotDeleted(),
+		})
+		if err != nil {
+			return nil, ErrWrap(http.StatusInternalServerError, err, "failed to get target cluster installations")
+		}
+		if len(targetClusterInstallations) > 0 {
+			return nil, NewErr(http.StatusBadRequest, errors.Errorf("isolated installation cannot be migrated to cluster with %d installations", len(targetClusterInstallations)))
+		}
+	}
+
+	oldInstallationState := installation.State
+
+	migrationOp := &model.InstallationClusterMigrationOperation{
+		SourceClusterID:             sourceClusterInstallation.ClusterID,
+		TargetClusterID:             targetCluster.ID,
+		SourceClusterInstallationID: sourceClusterInstallation.ID,
+	}
+
+	migrationOp, err = store.TriggerInstallationClusterMigration(migrationOp, installation)
+	if err != nil {
+		return nil, ErrWrap(http.StatusInternalServerError, err, "failed to create Installation cluster migration operation")
+	}
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeInstallationClusterMigration,
+		ID:        migrationOp.ID,
+		OwnerID:   installation.OwnerID,
+		NewState:  string(migrationOp.State),
+		OldState:  "n/a",
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{
+			"Installation":  migrationOp.InstallationID,
+			"SourceCluster": migrationOp.SourceClusterID,
+			"TargetCluster": migrationOp.TargetClusterID,
+			"Environment":   env,
+		},
+	}
+	err = webhook.SendToAllWebhooks(store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	installationWebhookPayload := &model.WebhookPayload{
+		Type:      model.TypeInstallation,
+		ID:        installation.ID,
+		OwnerID:   installation.OwnerID,
+		NewState:  installation.State,
+		OldState:  oldInstallationState,
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"DNS": installation.DNS, "Environment": env},
+	}
+	err = webhook.SendToAllWebhooks(store, installationWebhookPayload, logger.WithField("webhookEvent", installationWebhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	return migrationOp, nil
+}
@@ -5,8 +5,13 @@
 package common
 
 import (
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/webhook"
 	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
 )
 
 type dbMigrationValidationStore interface {
@@ -29,3 +34,86 @@ func ValidateDBMigrationDestination(store dbMigrationValidationStore, destinatio
 
 	return nil
 }
+
+type installationDBMigrationStore interface {
+	GetMultitenantDatabase(id string) (*model.MultitenantDatabase, error)
+	GetMultitenantDatabaseForInstallationID(installationID string) (*model.MultitenantDatabase, error)
+	TriggerInstallationDBMigration(dbMigrationOp *model.InstallationDBMigrationOperation, installation *model.Installation) (*model.InstallationDBMigrationOperation, error)
+	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+}
+
+// TriggerInstallationDBMigration validates, triggers and reports installation database migration.
+func TriggerInstallationDBMigration(store installationDBMigrationStore, request *model.InstallationDBMigrationRequest, installation *model.Installation, env string, logger log.FieldLogger) (*model.InstallationDBMigrationOperation, error) {
+	err := model.EnsureInstallationReadyForDBMigration(installation)
+	if err != nil {
+		return nil, ErrWrap(http.StatusBadRequest, err, "installation cannot be migrated")
+	}
+
+	sourceDB, err := store.GetMultitenantDatabaseForInstallationID(installation.ID)
+	if err != nil {
+		return nil, ErrWrap(http.StatusInternalServerError, err, "failed to get current multi-tenant database for installation")
+	}
+	if sourceDB.ID == request.DestinationMultiTenant.DatabaseID {
+		return nil, NewErr(http.StatusBadRequest, errors.New("installation already uses destination database"))
+	}
+
+	destinationDB, err := store.GetMultitenantDatabase(request.DestinationMultiTenant.DatabaseID)
+	if err != nil {
+		return nil, ErrWrap(http.StatusInternalServerError, err, "failed to get destination multi-tenant database")
+	}
+	if destinationDB == nil {
+		return nil, NewErr(http.StatusBadRequest, errors.Errorf("destination database %q not found", request.DestinationMultiTenant.DatabaseID))
+	}
+	if destinationDB.DatabaseType != sourceDB.DatabaseType {
+		return nil, NewErr(http.StatusBadRequest, errors.Errorf("destination database type %q does not match source database type %q", destinationDB.DatabaseType, sourceDB.DatabaseType))
+	}
+	if Contains(destinationDB.MigratedInstallations, installation.ID) {
+		return nil, NewErr(http.StatusBadRequest, errors.Errorf("installation still exists in migrated installations for %q database, clean it up before migration", destinationDB.ID))
+	}
+
+	oldInstallationState := installation.State
+
+	dbMigrationOp := &model.InstallationDBMigrationOperation{
+		SourceDatabase:         installation.Database,
+		DestinationDatabase:    request.DestinationDatabase,
+		SourceMultiTenant:      &model.MultiTenantDBMigrationData{DatabaseID: sourceDB.ID},
+		DestinationMultiTenant: request.DestinationMultiTenant,
+	}
+
+	dbMigrationOp, err = store.TriggerInstallationDBMigration(dbMigrationOp, installation)
+	if err != nil {
+		return nil, ErrWrap(http.StatusInternalServerError, err, "failed to create Installation DB migration operation")
+	}
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeInstallationDBMigration,
+		ID:        dbMigrationOp.ID,
+		OwnerID:   installation.OwnerID,
+		NewState:  string(dbMigrationOp.State),
+		OldState:  "n/a",
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"Installation": dbMigrationOp.InstallationID, "Environment": env},
+	}
+	err = webhook.SendToAllWebhooks(store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	installationWebhookPayload := &model.WebhookPayload{
+		Type:      model.TypeInstallation,
+		ID:        installation.ID,
+		OwnerID:   installation.OwnerID,
+		NewState:  installation.State,
+		OldState:  oldInstallationState,
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"DNS": installation.DNS, "Environment": env},
+	}
+	err = webhook.SendToAllWebhooks(store, installationWebhookPayload, logger.WithField("webhookEvent", installationWebhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	return dbMigrationOp, nil
+}
@@ -15,7 +15,18 @@ import (
 
 type installationRestorationStore interface {
 	TriggerInstallationRestoration(installation *model.Installation, backup *model.InstallationBackup) (*model.InstallationDBRestorationOperation, error)
+	webhookStore
+}
+
+type installationPointInTimeRestorationStore interface {
+	TriggerInstallationPointInTimeRestoration(installation *model.Installation, sourceInstallationID string, restoreTime int64) (*model.InstallationDBRestorationOperation, error)
+	webhookStore
+}
+
+type webhookStore interface {
 	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
 }
 
 // TriggerInstallationDBRestoration validates, triggers and reports installation database restoration.
@@ -32,15 +43,48 @@ func TriggerInstallationDBRestoration(store installationRestorationStore, instal
 		return nil, ErrWrap(http.StatusInternalServerError, err, "failed to create Installation DB restoration operation")
 	}
 
+	reportInstallationDBRestorationTriggered(store, installation, dbRestoration, oldInstallationState, env, logger)
+
+	return dbRestoration, nil
+}
+
+// TriggerInstallationDBPointInTimeRestoration validates, triggers and reports restoration of
+// the installation database to the database of the source installation at the given time.
+func TriggerInstallationDBPointInTimeRestoration(store installationPointInTimeRestorationStore, installation, source *model.Installation, restoreTime int64, env string, logger log.FieldLogger) (*model.InstallationDBRestorationOperation, error) {
+	err := model.EnsureInstallationReadyForDBPointInTimeRestoration(installation, source, restoreTime, time.Now().UnixNano()/int64(time.Millisecond))
+	if err != nil {
+		return nil, ErrWrap(http.StatusBadRequest, err, "installation cannot be restored to point in time")
+	}
+
+	oldInstallationState := installation.State
+
+	dbRestoration, err := store.TriggerInstallationPointInTimeRestoration(installation, source.ID, restoreTime)
+	if err != nil {
+		return nil, ErrWrap(http.StatusInternalServerError, err, "failed to create Installation DB restoration operation")
+	}
+
+	reportInstallationDBRestorationTriggered(store, installation, dbRestoration, oldInstallationState, env, logger)
+
+	return dbRestoration, nil
+}
+
+func reportInstallationDBRestorationTriggered(store webhookStore, installation *model.Installation, dbRestoration *model.InstallationDBRestorationOperation, oldInstallationState, env string, logger log.FieldLogger) {
 	webhookPayload := &model.WebhookPayload{
 		Type:      model.TypeInstallationDBRestoration,
 		ID:        dbRestoration.ID,
+		OwnerID:   installation.OwnerID,
 		NewState:  string(model.InstallationDBRestorationStateRequested),
 		OldState:  "n/a",
 		Timestamp: time.Now().UnixNano(),
-		ExtraData: map[string]string{"Installation": dbRestoration.InstallationID, "Backup": dbRestoration.BackupID, "Environment": env},
+		ExtraData: map[string]string{
+			"Installation":       dbRestoration.InstallationID,
+			"SourceInstallation": dbRestoration.SourceInstallationID,
+			"Mode":               string(dbRestoration.Mode),
+			"Backup":             dbRestoration.BackupID,
+			"Environment":        env,
+		},
 	}
-	err = webhook.SendToAllWebhooks(store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+	err := webhook.SendToAllWebhooks(store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
 	if err != nil {
 		logger.WithError(err).Error("Unable to process and send webhooks")
 	}
@@ -48,6 +92,7 @@ func TriggerInstallationDBRestoration(store installationRestorationStore, instal
 	installationWebhookPayload := &model.WebhookPayload{
 		Type:      model.TypeInstallation,
 		ID:        installation.ID,
+		OwnerID:   installation.OwnerID,
 		NewState:  installation.State,
 		OldState:  oldInstallationState,
 		Timestamp: time.Now().UnixNano(),
@@ -57,6 +102,4 @@ func TriggerInstallationDBRestoration(store installationRestorationStore, instal
 	if err != nil {
 		logger.WithError(err).Error("Unable to process and send webhooks")
 	}
-
-	return dbRestoration, nil
 }
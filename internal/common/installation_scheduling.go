@@ -0,0 +1,467 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package common
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/mattermost/mattermost-cloud/k8s"
+	"github.com/mattermost/mattermost-cloud/model"
+	mmv1alpha1 "github.com/mattermost/mattermost-operator/apis/mattermost/v1alpha1"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// installationSchedulingStore abstracts the database operations required to
+// schedule installations on clusters.
+type installationSchedulingStore interface {
+	GetClusters(filter *model.ClusterFilter) ([]*model.Cluster, error)
+	GetAnnotationsForClusters(filter *model.ClusterFilter) (map[string][]*model.Annotation, error)
+	GetInstallation(installationID string, includeGroupConfig, includeGroupConfigOverrides bool) (*model.Installation, error)
+	GetInstallations(filter *model.InstallationFilter, includeGroupConfig, includeGroupConfigOverrides bool) ([]*model.Installation, error)
+	GetAnnotationsForInstallation(installationID string) ([]*model.Annotation, error)
+	GetClusterInstallations(filter *model.ClusterInstallationFilter) ([]*model.ClusterInstallation, error)
+}
+
+// clusterResourcesProvider abstracts the cluster resource usage lookups
+// required to schedule installations on clusters.
+type clusterResourcesProvider interface {
+	GetClusterResources(cluster *model.Cluster, onlySchedulable bool) (*k8s.ClusterResources, error)
+}
+
+// SchedulingOptions are the various options that control how installations
+// are scheduled on clusters.
+type SchedulingOptions struct {
+	// DefaultPolicy is used for installations without a scheduling policy.
+	DefaultPolicy                      string
+	ClusterResourceThreshold           int
+	ClusterResourceThresholdScaleValue int
+}
+
+// ClusterCandidate is a cluster evaluated as a scheduling target for an
+// installation.
+type ClusterCandidate struct {
+	Cluster *model.Cluster
+	// UnrequestedAnnotations are the cluster annotations which are not present
+	// on the installation.
+	UnrequestedAnnotations []string
+	// CPUPercent and MemoryPercent are the expected cluster resource usage
+	// after scheduling the installation.
+	CPUPercent           int
+	MemoryPercent        int
+	ClusterInstallations int
+	OwnerInstallations   int
+}
+
+// resourcePercent returns the average of expected CPU and memory usage.
+func (c *ClusterCandidate) resourcePercent() int {
+	return (c.CPUPercent + c.MemoryPercent) / 2
+}
+
+// SchedulingPolicy decides which of the clusters capable of hosting an
+// installation are acceptable and in which order they are tried.
+type SchedulingPolicy interface {
+	// Filter returns the reason why the installation should not be scheduled
+	// on the candidate or an empty string if the candidate is acceptable.
+	Filter(candidate *ClusterCandidate) string
+	// Less returns true if candidate a should be tried before candidate b.
+	Less(a, b *ClusterCandidate) bool
+}
+
+// schedulingPolicies maps scheduling policy names to their implementations.
+var schedulingPolicies = map[string]SchedulingPolicy{
+	model.SchedulingPolicyFirstFit:           firstFitPolicy{},
+	model.SchedulingPolicyBinPacking:         binPackingPolicy{},
+	model.SchedulingPolicySpread:             spreadPolicy{},
+	model.SchedulingPolicyAnnotationAffinity: annotationAffinityPolicy{},
+	model.SchedulingPolicyOwnerAntiAffinity:  ownerAntiAffinityPolicy{},
+}
+
+// firstFitPolicy keeps the order in which clusters were returned by the store.
+type firstFitPolicy struct{}
+
+func (firstFitPolicy) Filter(*ClusterCandidate) string  { return "" }
+func (firstFitPolicy) Less(a, b *ClusterCandidate) bool { return false }
+
+// binPackingPolicy prefers the most utilized clusters to keep the number of
+// partially used clusters low.
+type binPackingPolicy struct{}
+
+func (binPackingPolicy) Filter(*ClusterCandidate) string { return "" }
+func (binPackingPolicy) Less(a, b *ClusterCandidate) bool {
+	return a.resourcePercent() > b.resourcePercent()
+}
+
+// spreadPolicy prefers the least utilized clusters.
+type spreadPolicy struct{}
+
+func (spreadPolicy) Filter(*ClusterCandidate) string { return "" }
+func (spreadPolicy) Less(a, b *ClusterCandidate) bool {
+	return a.resourcePercent() < b.resourcePercent()
+}
+
+// annotationAffinityPolicy only accepts clusters whose annotations are all
+// present on the installation.
+type annotationAffinityPolicy struct{}
+
+func (annotationAffinityPolicy) Filter(candidate *ClusterCandidate) string {
+	if len(candidate.UnrequestedAnnotations) > 0 {
+		return fmt.Sprintf("cluster has annotations not present on the installation: %s", strings.Join(candidate.UnrequestedAnnotations, ", "))
+	}
+	return ""
+}
+func (annotationAffinityPolicy) Less(a, b *ClusterCandidate) bool { return false }
+
+// ownerAntiAffinityPolicy prefers clusters hosting the fewest installations of
+// the same owner and falls back to the least utilized cluster.
+type ownerAntiAffinityPolicy struct{}
+
+func (ownerAntiAffinityPolicy) Filter(*ClusterCandidate) string { return "" }
+func (ownerAntiAffinityPolicy) Less(a, b *ClusterCandidate) bool {
+	if a.OwnerInstallations != b.OwnerInstallations {
+		return a.OwnerInstallations < b.OwnerInstallations
+	}
+	return a.resourcePercent() < b.resourcePercent()
+}
+
+// InstallationScheduler selects clusters for installations according to
+// scheduling policies.
+type InstallationScheduler struct {
+	store     installationSchedulingStore
+	resources clusterResourcesProvider
+	options   SchedulingOptions
+	logger    log.FieldLogger
+}
+
+// NewInstallationScheduler creates a new InstallationScheduler.
+func NewInstallationScheduler(store installationSchedulingStore, resources clusterResourcesProvider, options SchedulingOptions, logger log.FieldLogger) *InstallationScheduler {
+	return &InstallationScheduler{
+		store:     store,
+		resources: resources,
+		options:   options,
+		logger:    logger,
+	}
+}
+
+// Policy returns the scheduling policy applied to the installation.
+func (s *InstallationScheduler) Policy(installation *model.Installation) string {
+	if installation.SchedulingPolicy != "" {
+		return installation.SchedulingPolicy
+	}
+	if s.options.DefaultPolicy != "" {
+		return s.options.DefaultPolicy
+	}
+
+	return model.SchedulingPolicyFirstFit
+}
+
+// CanScaleCluster returns true if the cluster can be scaled up when scheduling
+// an installation would exceed the cluster resource threshold.
+func (s *InstallationScheduler) CanScaleCluster(cluster *model.Cluster) bool {
+	return s.options.ClusterResourceThresholdScaleValue != 0 &&
+		cluster.Provisioner != model.ProvisionerLocal &&
+		cluster.ProvisionerMetadataKops != nil &&
+		cluster.ProvisionerMetadataKops.NodeMinCount != cluster.ProvisionerMetadataKops.NodeMaxCount &&
+		cluster.State == model.ClusterStateStable
+}
+
+// CheckCluster returns the reason why the installation cannot be scheduled on
+// the cluster in regards to configuration and state. This does not include
+// resource checks. An empty reason means that the cluster can host the
+// installation.
+func (s *InstallationScheduler) CheckCluster(cluster *model.Cluster, installation *model.Installation) (string, error) {
+	existingClusterInstallations, err := s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
+		Paging:    model.AllPagesNotDeleted(),
+		ClusterID: cluster.ID,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get existing cluster installations")
+	}
+
+	return s.checkCluster(cluster, installation, existingClusterInstallations)
+}
+
+func (s *InstallationScheduler) checkCluster(cluster *model.Cluster, installation *model.Installation, existingClusterInstallations []*model.ClusterInstallation) (string, error) {
+	if cluster.State != model.ClusterStateStable {
+		return fmt.Sprintf("cluster is not stable (currently %s)", cluster.State), nil
+	}
+	if !cluster.AllowInstallations {
+		return "cluster is set to not allow for new installation scheduling", nil
+	}
+	if cluster.Provisioner == model.ProvisionerLocal &&
+		(!installation.InternalDatabase() || !installation.InternalFilestore()) {
+		return "local cluster only supports operator databases and filestores", nil
+	}
+
+	////////////////////////////////////////////////////////////////////////////
+	//                              MULTI-TENANCY                             //
+	////////////////////////////////////////////////////////////////////////////
+	// Current model:                                                         //
+	// - isolation=true  | 1 cluster installations                            //
+	// - isolation=false | X cluster installations, where "X" is as many as   //
+	//                     will fit with the given CPU and Memory threshold.  //
+	////////////////////////////////////////////////////////////////////////////
+	if installation.Affinity == model.InstallationAffinityIsolated {
+		if len(existingClusterInstallations) > 0 {
+			return fmt.Sprintf("cluster already has %d installations", len(existingClusterInstallations)), nil
+		}
+	} else {
+		if len(existingClusterInstallations) == 1 {
+			// This should be the only scenario where we need to check if the
+			// cluster installation running requires isolation or not.
+			existingInstallation, err := s.store.GetInstallation(existingClusterInstallations[0].InstallationID, true, false)
+			if err != nil {
+				return "", errors.Wrap(err, "failed to get existing installation")
+			}
+			if existingInstallation != nil && existingInstallation.Affinity == model.InstallationAffinityIsolated {
+				return fmt.Sprintf("cluster already has an isolated installation %s", existingInstallation.ID), nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// Schedule evaluates all clusters as scheduling targets for the installation
+// using the given policy or the installation policy if none is provided.
+// No changes are made, which makes it suitable for dry runs.
+func (s *InstallationScheduler) Schedule(installation *model.Installation, policy string) (*model.InstallationSchedulingResult, error) {
+	if policy == "" {
+		policy = s.Policy(installation)
+	}
+
+	eligible, decisions, err := s.evaluate(installation, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.InstallationSchedulingResult{
+		InstallationID: installation.ID,
+		Policy:         policy,
+		Clusters:       decisions,
+	}
+	if len(eligible) > 0 {
+		result.ClusterID = eligible[0].Cluster.ID
+	}
+
+	return result, nil
+}
+
+// PrioritizeClusters returns the clusters eligible for hosting the
+// installation in the order they should be tried according to the
+// installation scheduling policy.
+// Note the following:
+//   - This check is done without locking to avoid creating additional
+//     congestion.
+//   - When scheduling an installation, the configuration and resource checks
+//     should be performed again under cluster lock.
+func (s *InstallationScheduler) PrioritizeClusters(installation *model.Installation) ([]*model.Cluster, error) {
+	eligible, decisions, err := s.evaluate(installation, s.Policy(installation))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, decision := range decisions {
+		if !decision.Eligible {
+			s.logger.Debugf("Cluster %s is not eligible for installation %s: %s", decision.ClusterID, installation.ID, decision.Reason)
+		}
+	}
+
+	clusters := make([]*model.Cluster, 0, len(eligible))
+	for _, candidate := range eligible {
+		clusters = append(clusters, candidate.Cluster)
+	}
+
+	return clusters, nil
+}
+
+// evaluate returns the eligible cluster candidates ordered according to the
+// policy together with decisions for all evaluated clusters.
+func (s *InstallationScheduler) evaluate(installation *model.Installation, policyName string) ([]*ClusterCandidate, []*model.ClusterSchedulingDecision, error) {
+	policy, ok := schedulingPolicies[policyName]
+	if !ok {
+		return nil, nil, NewErr(http.StatusBadRequest, errors.Errorf("unsupported scheduling policy %q", policyName))
+	}
+
+	size, err := mmv1alpha1.GetClusterSize(installation.Size)
+	if err != nil {
+		return nil, nil, NewErr(http.StatusBadRequest, errors.Wrap(err, "invalid installation size"))
+	}
+	installationCPURequirement := size.CalculateCPUMilliRequirement(
+		installation.InternalDatabase(),
+		installation.InternalFilestore(),
+	)
+	installationMemRequirement := size.CalculateMemoryMilliRequirement(
+		installation.InternalDatabase(),
+		installation.InternalFilestore(),
+	)
+
+	installationAnnotations, err := s.store.GetAnnotationsForInstallation(installation.ID)
+	if err != nil {
+		return nil, nil, ErrWrap(http.StatusInternalServerError, err, "failed to get annotations for installation")
+	}
+
+	clusterFilter := &model.ClusterFilter{Paging: model.AllPagesNotDeleted()}
+	clusters, err := s.store.GetClusters(clusterFilter)
+	if err != nil {
+		return nil, nil, ErrWrap(http.StatusInternalServerError, err, "failed to get clusters")
+	}
+	clusterAnnotations, err := s.store.GetAnnotationsForClusters(clusterFilter)
+	if err != nil {
+		return nil, nil, ErrWrap(http.StatusInternalServerError, err, "failed to get annotations for clusters")
+	}
+
+	ownerInstallations := map[string]bool{}
+	if installation.OwnerID != "" {
+		installations, err := s.store.GetInstallations(&model.InstallationFilter{
+			OwnerID: installation.OwnerID,
+			Paging:  model.AllPagesNotDeleted(),
+		}, false, false)
+		if err != nil {
+			return nil, nil, ErrWrap(http.StatusInternalServerError, err, "failed to get owner installations")
+		}
+		for _, ownerInstallation := range installations {
+			if ownerInstallation.ID != installation.ID {
+				ownerInstallations[ownerInstallation.ID] = true
+			}
+		}
+	}
+
+	var eligible []*ClusterCandidate
+	var eligibleDecisions, ineligibleDecisions []*model.ClusterSchedulingDecision
+	for _, cluster := range clusters {
+		clusterInstallations, err := s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
+			Paging:    model.AllPagesNotDeleted(),
+			ClusterID: cluster.ID,
+		})
+		if err != nil {
+			return nil, nil, ErrWrap(http.StatusInternalServerError, err, "failed to get cluster installations")
+		}
+
+		candidate := &ClusterCandidate{
+			Cluster:                cluster,
+			UnrequestedAnnotations: annotationsDifference(clusterAnnotations[cluster.ID], installationAnnotations),
+			ClusterInstallations:   len(clusterInstallations),
+		}
+		for _, clusterInstallation := range clusterInstallations {
+			if ownerInstallations[clusterInstallation.InstallationID] {
+				candidate.OwnerInstallations++
+			}
+		}
+
+		reason, err := s.checkCandidate(candidate, installation, installationAnnotations, clusterAnnotations[cluster.ID], clusterInstallations, installationCPURequirement, installationMemRequirement)
+		if err != nil {
+			return nil, nil, ErrWrap(http.StatusInternalServerError, err, "failed to check cluster")
+		}
+		if reason == "" {
+			reason = policy.Filter(candidate)
+		}
+
+		decision := &model.ClusterSchedulingDecision{
+			ClusterID:            cluster.ID,
+			Eligible:             reason == "",
+			Reason:               reason,
+			CPUPercent:           candidate.CPUPercent,
+			MemoryPercent:        candidate.MemoryPercent,
+			ClusterInstallations: candidate.ClusterInstallations,
+			OwnerInstallations:   candidate.OwnerInstallations,
+		}
+		if !decision.Eligible {
+			ineligibleDecisions = append(ineligibleDecisions, decision)
+			continue
+		}
+		eligible = append(eligible, candidate)
+		eligibleDecisions = append(eligibleDecisions, decision)
+	}
+
+	// Candidates and their decisions are sorted together to keep them aligned.
+	sort.Stable(candidatesByPolicy{policy, eligible, eligibleDecisions})
+
+	return eligible, append(eligibleDecisions, ineligibleDecisions...), nil
+}
+
+// checkCandidate returns the reason why the installation cannot be scheduled on
+// the candidate cluster regardless of scheduling policy. Expected resource
+// usage is recorded on the candidate.
+func (s *InstallationScheduler) checkCandidate(
+	candidate *ClusterCandidate,
+	installation *model.Installation,
+	installationAnnotations,
+	clusterAnnotations []*model.Annotation,
+	clusterInstallations []*model.ClusterInstallation,
+	installationCPURequirement,
+	installationMemRequirement int64) (string, error) {
+	for _, clusterInstallation := range clusterInstallations {
+		if clusterInstallation.InstallationID == installation.ID {
+			return "installation is already scheduled on the cluster", nil
+		}
+	}
+
+	// Clusters must have all annotations present on the installation, but can
+	// have additional annotations not present on the installation.
+	missingAnnotations := annotationsDifference(installationAnnotations, clusterAnnotations)
+	if len(missingAnnotations) > 0 {
+		return fmt.Sprintf("cluster is missing installation annotations: %s", strings.Join(missingAnnotations, ", ")), nil
+	}
+
+	reason, err := s.checkCluster(candidate.Cluster, installation, clusterInstallations)
+	if err != nil || reason != "" {
+		return reason, err
+	}
+
+	clusterResources, err := s.resources.GetClusterResources(candidate.Cluster, true)
+	if err != nil {
+		s.logger.WithError(err).Errorf("Failed to get cluster resources for cluster %s", candidate.Cluster.ID)
+		return "failed to get cluster resources", nil
+	}
+	candidate.CPUPercent = clusterResources.CalculateCPUPercentUsed(installationCPURequirement)
+	candidate.MemoryPercent = clusterResources.CalculateMemoryPercentUsed(installationMemRequirement)
+
+	if (candidate.CPUPercent > s.options.ClusterResourceThreshold || candidate.MemoryPercent > s.options.ClusterResourceThreshold) &&
+		!s.CanScaleCluster(candidate.Cluster) {
+		return fmt.Sprintf("cluster would exceed the cluster load threshold (%d%%)", s.options.ClusterResourceThreshold), nil
+	}
+
+	return "", nil
+}
+
+// candidatesByPolicy sorts cluster candidates and their scheduling decisions
+// according to a scheduling policy.
+type candidatesByPolicy struct {
+	policy     SchedulingPolicy
+	candidates []*ClusterCandidate
+	decisions  []*model.ClusterSchedulingDecision
+}
+
+func (c candidatesByPolicy) Len() int { return len(c.candidates) }
+func (c candidatesByPolicy) Less(i, j int) bool {
+	return c.policy.Less(c.candidates[i], c.candidates[j])
+}
+func (c candidatesByPolicy) Swap(i, j int) {
+	c.candidates[i], c.candidates[j] = c.candidates[j], c.candidates[i]
+	c.decisions[i], c.decisions[j] = c.decisions[j], c.decisions[i]
+}
+
+// annotationsDifference returns sorted names of annotations from a which are
+// not present in b.
+func annotationsDifference(a, b []*model.Annotation) []string {
+	names := map[string]bool{}
+	for _, annotation := range b {
+		names[annotation.Name] = true
+	}
+
+	var difference []string
+	for _, annotation := range a {
+		if !names[annotation.Name] {
+			difference = append(difference, annotation.Name)
+		}
+	}
+	sort.Strings(difference)
+
+	return difference
+}
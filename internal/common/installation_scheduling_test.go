@@ -0,0 +1,176 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package common
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/k8s"
+	"github.com/mattermost/mattermost-cloud/model"
+	mmv1alpha1 "github.com/mattermost/mattermost-operator/apis/mattermost/v1alpha1"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockClusterResourcesProvider struct {
+	usedPercent map[string]int64
+	errClusters map[string]bool
+}
+
+func (p *mockClusterResourcesProvider) GetClusterResources(cluster *model.Cluster, onlySchedulable bool) (*k8s.ClusterResources, error) {
+	if p.errClusters[cluster.ID] {
+		return nil, errors.New("failed to reach cluster")
+	}
+	return &k8s.ClusterResources{
+		MilliTotalCPU:    1000000,
+		MilliUsedCPU:     p.usedPercent[cluster.ID] * 10000,
+		MilliTotalMemory: 1000000000000000,
+		MilliUsedMemory:  p.usedPercent[cluster.ID] * 10000000000000,
+	}, nil
+}
+
+func TestInstallationScheduler(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	createCluster := func(annotations []*model.Annotation) *model.Cluster {
+		cluster := &model.Cluster{State: model.ClusterStateStable, AllowInstallations: true}
+		err := sqlStore.CreateCluster(cluster, annotations)
+		require.NoError(t, err)
+		return cluster
+	}
+	createInstallation := func(ownerID string, annotations []*model.Annotation, cluster *model.Cluster) *model.Installation {
+		installation := &model.Installation{
+			OwnerID:   ownerID,
+			Size:      mmv1alpha1.Size100String,
+			Affinity:  model.InstallationAffinityMultiTenant,
+			Database:  model.InstallationDatabaseMultiTenantRDSPostgres,
+			Filestore: model.InstallationFilestoreBifrost,
+			State:     model.InstallationStateCreationRequested,
+		}
+		err := sqlStore.CreateInstallation(installation, annotations)
+		require.NoError(t, err)
+		if cluster != nil {
+			err = sqlStore.CreateClusterInstallation(&model.ClusterInstallation{
+				InstallationID: installation.ID,
+				ClusterID:      cluster.ID,
+				State:          model.ClusterInstallationStateStable,
+			})
+			require.NoError(t, err)
+		}
+		return installation
+	}
+
+	annotations := []*model.Annotation{{Name: "scheduling-test"}}
+
+	cluster1 := createCluster(nil)
+	cluster2 := createCluster(annotations)
+	cluster3 := createCluster(nil)
+	disabledCluster := &model.Cluster{State: model.ClusterStateStable}
+	err := sqlStore.CreateCluster(disabledCluster, nil)
+	require.NoError(t, err)
+
+	resources := &mockClusterResourcesProvider{usedPercent: map[string]int64{
+		cluster1.ID: 40,
+		cluster2.ID: 60,
+		cluster3.ID: 20,
+	}}
+	scheduler := NewInstallationScheduler(sqlStore, resources, SchedulingOptions{ClusterResourceThreshold: 50}, logger)
+
+	owner := model.NewID()
+	createInstallation(owner, nil, cluster3)
+	installation := createInstallation(owner, nil, nil)
+
+	t.Run("policy defaults", func(t *testing.T) {
+		assert.Equal(t, model.SchedulingPolicyFirstFit, scheduler.Policy(installation))
+
+		withDefault := NewInstallationScheduler(sqlStore, resources, SchedulingOptions{DefaultPolicy: model.SchedulingPolicySpread}, logger)
+		assert.Equal(t, model.SchedulingPolicySpread, withDefault.Policy(installation))
+		assert.Equal(t, model.SchedulingPolicyBinPacking, withDefault.Policy(&model.Installation{SchedulingPolicy: model.SchedulingPolicyBinPacking}))
+	})
+
+	t.Run("unsupported policy", func(t *testing.T) {
+		_, err := scheduler.Schedule(installation, "unknown")
+		require.Error(t, err)
+		assert.Equal(t, 400, ErrToStatus(err))
+	})
+
+	t.Run("threshold exceeded", func(t *testing.T) {
+		result, err := scheduler.Schedule(installation, model.SchedulingPolicyFirstFit)
+		require.NoError(t, err)
+		require.Len(t, result.Clusters, 4)
+
+		decisions := map[string]*model.ClusterSchedulingDecision{}
+		for _, decision := range result.Clusters {
+			decisions[decision.ClusterID] = decision
+		}
+		assert.True(t, decisions[cluster1.ID].Eligible)
+		assert.False(t, decisions[cluster2.ID].Eligible)
+		assert.Contains(t, decisions[cluster2.ID].Reason, "threshold")
+		assert.True(t, decisions[cluster3.ID].Eligible)
+		assert.False(t, decisions[disabledCluster.ID].Eligible)
+		assert.Equal(t, 1, decisions[cluster3.ID].OwnerInstallations)
+	})
+
+	t.Run("unreachable cluster is skipped", func(t *testing.T) {
+		resources.errClusters = map[string]bool{cluster1.ID: true}
+		defer func() { resources.errClusters = nil }()
+
+		result, err := scheduler.Schedule(installation, model.SchedulingPolicyFirstFit)
+		require.NoError(t, err)
+		require.Len(t, result.Clusters, 4)
+
+		decisions := map[string]*model.ClusterSchedulingDecision{}
+		for _, decision := range result.Clusters {
+			decisions[decision.ClusterID] = decision
+		}
+		assert.False(t, decisions[cluster1.ID].Eligible)
+		assert.Equal(t, "failed to get cluster resources", decisions[cluster1.ID].Reason)
+		assert.True(t, decisions[cluster3.ID].Eligible)
+	})
+
+	scheduler = NewInstallationScheduler(sqlStore, resources, SchedulingOptions{ClusterResourceThreshold: 80}, logger)
+
+	for _, testCase := range []struct {
+		policy           string
+		expectedClusters []string
+	}{
+		{model.SchedulingPolicySpread, []string{cluster3.ID, cluster1.ID, cluster2.ID}},
+		{model.SchedulingPolicyBinPacking, []string{cluster2.ID, cluster1.ID, cluster3.ID}},
+		{model.SchedulingPolicyAnnotationAffinity, []string{cluster1.ID, cluster3.ID}},
+		{model.SchedulingPolicyOwnerAntiAffinity, []string{cluster1.ID, cluster2.ID, cluster3.ID}},
+	} {
+		t.Run(testCase.policy, func(t *testing.T) {
+			installation.SchedulingPolicy = testCase.policy
+
+			clusters, err := scheduler.PrioritizeClusters(installation)
+			require.NoError(t, err)
+
+			var clusterIDs []string
+			for _, cluster := range clusters {
+				clusterIDs = append(clusterIDs, cluster.ID)
+			}
+			assert.Equal(t, testCase.expectedClusters, clusterIDs)
+
+			result, err := scheduler.Schedule(installation, "")
+			require.NoError(t, err)
+			assert.Equal(t, testCase.policy, result.Policy)
+			assert.Equal(t, testCase.expectedClusters[0], result.ClusterID)
+		})
+	}
+
+	t.Run("installation annotations required on cluster", func(t *testing.T) {
+		annotated := createInstallation(model.NewID(), annotations, nil)
+
+		clusters, err := scheduler.PrioritizeClusters(annotated)
+		require.NoError(t, err)
+		require.Len(t, clusters, 1)
+		assert.Equal(t, cluster2.ID, clusters[0].ID)
+	})
+}
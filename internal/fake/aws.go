@@ -0,0 +1,412 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package fake
+
+import (
+	"fmt"
+	"sync"
+
+	sdkAWS "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/acm"
+	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// EnvironmentName is the cloud environment name reported by the fake AWS client.
+	EnvironmentName = "fake"
+	// PrivateZoneDomainName is the private hosted zone domain of the fake AWS client.
+	PrivateZoneDomainName = "fake.internal.mattermost.com"
+	// PrivateHostedZoneID is the private hosted zone ID of the fake AWS client.
+	PrivateHostedZoneID = "FAKEPRIVATEZONEID"
+	// MultitenantBucketName is the bucket of all multitenant filestores of the fake AWS client.
+	MultitenantBucketName = "fake-multitenant-filestore"
+	// VpcCIDR is the CIDR of every VPC of the fake AWS client.
+	VpcCIDR = "10.0.0.0/16"
+)
+
+// AWS is an in-memory implementation of the aws.AWS interface.
+type AWS struct {
+	behavior *Behavior
+	logger   log.FieldLogger
+
+	mux           sync.Mutex
+	vpcs          map[string]aws.ClusterResources
+	publicCNAMEs  map[string][]string
+	publicIDs     map[string]string
+	privateCNAMEs map[string][]string
+	tags          map[string]map[string]string
+}
+
+// NewAWS creates a new fake AWS client.
+func NewAWS(behavior *Behavior, logger log.FieldLogger) *AWS {
+	return &AWS{
+		behavior:      behavior,
+		logger:        logger.WithField("fake", "aws"),
+		vpcs:          make(map[string]aws.ClusterResources),
+		publicCNAMEs:  make(map[string][]string),
+		publicIDs:     make(map[string]string),
+		privateCNAMEs: make(map[string][]string),
+		tags:          make(map[string]map[string]string),
+	}
+}
+
+// GetPublicCNAME returns the endpoints of the given public CNAME record.
+func (a *AWS) GetPublicCNAME(dnsName string) ([]string, bool) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	endpoints, ok := a.publicCNAMEs[dnsName]
+	return endpoints, ok
+}
+
+// GetCertificateSummaryByTag returns a fake certificate.
+func (a *AWS) GetCertificateSummaryByTag(key, value string, logger log.FieldLogger) (*acm.CertificateSummary, error) {
+	err := a.behavior.invoke("GetCertificateSummaryByTag")
+	if err != nil {
+		return nil, err
+	}
+
+	return &acm.CertificateSummary{
+		CertificateArn: sdkAWS.String(fmt.Sprintf("arn:aws:acm:us-east-1:000000000000:certificate/%s-%s", key, value)),
+		DomainName:     sdkAWS.String(fmt.Sprintf("*.%s", PrivateZoneDomainName)),
+	}, nil
+}
+
+// GetCloudEnvironmentName returns the fake environment name.
+func (a *AWS) GetCloudEnvironmentName() string {
+	return EnvironmentName
+}
+
+// GetAndClaimVpcResources claims a fake VPC for the given cluster.
+func (a *AWS) GetAndClaimVpcResources(clusterID, owner string, logger log.FieldLogger) (aws.ClusterResources, error) {
+	err := a.behavior.invoke("GetAndClaimVpcResources")
+	if err != nil {
+		return aws.ClusterResources{}, err
+	}
+
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	resources, ok := a.vpcs[clusterID]
+	if !ok {
+		resources = newClusterResources(fmt.Sprintf("vpc-%s", model.NewID()))
+		a.vpcs[clusterID] = resources
+	}
+
+	return resources, nil
+}
+
+// GetVpcResources returns the fake VPC claimed by the given cluster.
+func (a *AWS) GetVpcResources(clusterID string, logger log.FieldLogger) (aws.ClusterResources, error) {
+	err := a.behavior.invoke("GetVpcResources")
+	if err != nil {
+		return aws.ClusterResources{}, err
+	}
+
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	resources, ok := a.vpcs[clusterID]
+	if !ok {
+		return aws.ClusterResources{}, errors.Errorf("no VPC claimed by cluster %s", clusterID)
+	}
+
+	return resources, nil
+}
+
+// ReleaseVpc releases the fake VPC claimed by the given cluster.
+func (a *AWS) ReleaseVpc(clusterID string, logger log.FieldLogger) error {
+	err := a.behavior.invoke("ReleaseVpc")
+	if err != nil {
+		return err
+	}
+
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	delete(a.vpcs, clusterID)
+
+	return nil
+}
+
+// AttachPolicyToRole does nothing.
+func (a *AWS) AttachPolicyToRole(roleName, policyName string, logger log.FieldLogger) error {
+	return a.behavior.invoke("AttachPolicyToRole")
+}
+
+// DetachPolicyFromRole does nothing.
+func (a *AWS) DetachPolicyFromRole(roleName, policyName string, logger log.FieldLogger) error {
+	return a.behavior.invoke("DetachPolicyFromRole")
+}
+
+// GetPrivateZoneDomainName returns the fake private zone domain name.
+func (a *AWS) GetPrivateZoneDomainName(logger log.FieldLogger) (string, error) {
+	err := a.behavior.invoke("GetPrivateZoneDomainName")
+	if err != nil {
+		return "", err
+	}
+
+	return PrivateZoneDomainName, nil
+}
+
+// GetPrivateHostedZoneID returns the fake private hosted zone ID.
+func (a *AWS) GetPrivateHostedZoneID() string {
+	return PrivateHostedZoneID
+}
+
+// GetTagByKeyAndZoneID returns a tag of the fake hosted zone.
+func (a *AWS) GetTagByKeyAndZoneID(key string, id string, logger log.FieldLogger) (*aws.Tag, error) {
+	err := a.behavior.invoke("GetTagByKeyAndZoneID")
+	if err != nil {
+		return nil, err
+	}
+
+	return &aws.Tag{Key: key, Value: EnvironmentName}, nil
+}
+
+// CreatePrivateCNAME records a private CNAME.
+func (a *AWS) CreatePrivateCNAME(dnsName string, dnsEndpoints []string, logger log.FieldLogger) error {
+	err := a.behavior.invoke("CreatePrivateCNAME")
+	if err != nil {
+		return err
+	}
+
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	a.privateCNAMEs[dnsName] = dnsEndpoints
+
+	return nil
+}
+
+// CreatePublicCNAME records a public CNAME.
+func (a *AWS) CreatePublicCNAME(dnsName string, dnsEndpoints []string, logger log.FieldLogger) error {
+	err := a.behavior.invoke("CreatePublicCNAME")
+	if err != nil {
+		return err
+	}
+
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	a.publicCNAMEs[dnsName] = dnsEndpoints
+
+	return nil
+}
+
+// UpdatePublicRecordIDForCNAME updates the record ID of a public CNAME.
+func (a *AWS) UpdatePublicRecordIDForCNAME(dnsName, newID string, logger log.FieldLogger) error {
+	err := a.behavior.invoke("UpdatePublicRecordIDForCNAME")
+	if err != nil {
+		return err
+	}
+
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	if _, ok := a.publicCNAMEs[dnsName]; !ok {
+		return errors.Errorf("no public CNAME record found for %s", dnsName)
+	}
+	a.publicIDs[dnsName] = newID
+
+	return nil
+}
+
+// IsProvisionedPrivateCNAME returns true if the private CNAME was created.
+func (a *AWS) IsProvisionedPrivateCNAME(dnsName string, logger log.FieldLogger) bool {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	_, ok := a.privateCNAMEs[dnsName]
+	return ok
+}
+
+// DeletePrivateCNAME deletes a private CNAME.
+func (a *AWS) DeletePrivateCNAME(dnsName string, logger log.FieldLogger) error {
+	err := a.behavior.invoke("DeletePrivateCNAME")
+	if err != nil {
+		return err
+	}
+
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	delete(a.privateCNAMEs, dnsName)
+
+	return nil
+}
+
+// DeletePublicCNAME deletes a public CNAME.
+func (a *AWS) DeletePublicCNAME(dnsName string, logger log.FieldLogger) error {
+	err := a.behavior.invoke("DeletePublicCNAME")
+	if err != nil {
+		return err
+	}
+
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	delete(a.publicCNAMEs, dnsName)
+	delete(a.publicIDs, dnsName)
+
+	return nil
+}
+
+// TagResource records a tag of the given resource.
+func (a *AWS) TagResource(resourceID, key, value string, logger log.FieldLogger) error {
+	err := a.behavior.invoke("TagResource")
+	if err != nil {
+		return err
+	}
+
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	if a.tags[resourceID] == nil {
+		a.tags[resourceID] = make(map[string]string)
+	}
+	a.tags[resourceID][key] = value
+
+	return nil
+}
+
+// UntagResource removes a tag of the given resource.
+func (a *AWS) UntagResource(resourceID, key, value string, logger log.FieldLogger) error {
+	err := a.behavior.invoke("UntagResource")
+	if err != nil {
+		return err
+	}
+
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	delete(a.tags[resourceID], key)
+
+	return nil
+}
+
+// IsValidAMI considers every AMI to be valid.
+func (a *AWS) IsValidAMI(AMIImage string, logger log.FieldLogger) (bool, error) {
+	err := a.behavior.invoke("IsValidAMI")
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// DynamoDBEnsureTableDeleted does nothing.
+func (a *AWS) DynamoDBEnsureTableDeleted(tableName string, logger log.FieldLogger) error {
+	return a.behavior.invoke("DynamoDBEnsureTableDeleted")
+}
+
+// S3EnsureBucketDeleted does nothing.
+func (a *AWS) S3EnsureBucketDeleted(bucketName string, logger log.FieldLogger) error {
+	return a.behavior.invoke("S3EnsureBucketDeleted")
+}
+
+// S3EnsureBucketDirectoryDeleted does nothing.
+func (a *AWS) S3EnsureBucketDirectoryDeleted(bucketName, directory string, logger log.FieldLogger) error {
+	return a.behavior.invoke("S3EnsureBucketDirectoryDeleted")
+}
+
+// S3EnsureObjectDeleted does nothing.
+func (a *AWS) S3EnsureObjectDeleted(bucketName, path string) error {
+	return a.behavior.invoke("S3EnsureObjectDeleted")
+}
+
+// S3CopyBucketDirectory does nothing.
+func (a *AWS) S3CopyBucketDirectory(srcBucketName, srcPrefix, destBucketName, destPrefix string, logger log.FieldLogger) error {
+	return a.behavior.invoke("S3CopyBucketDirectory")
+}
+
+// S3LargeCopy does nothing.
+func (a *AWS) S3LargeCopy(srcBucketName, srcKey, destBucketName, destKey *string) error {
+	return a.behavior.invoke("S3LargeCopy")
+}
+
+// GetMultitenantBucketNameForInstallation returns the fake multitenant bucket.
+func (a *AWS) GetMultitenantBucketNameForInstallation(installationID string, store model.InstallationDatabaseStoreInterface) (string, error) {
+	err := a.behavior.invoke("GetMultitenantBucketNameForInstallation")
+	if err != nil {
+		return "", err
+	}
+
+	return MultitenantBucketName, nil
+}
+
+// GenerateBifrostUtilitySecret returns an empty bifrost secret.
+func (a *AWS) GenerateBifrostUtilitySecret(clusterID string, logger log.FieldLogger) (*corev1.Secret, error) {
+	err := a.behavior.invoke("GenerateBifrostUtilitySecret")
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "bifrost",
+		},
+	}, nil
+}
+
+// GetCIDRByVPCTag returns the fake VPC CIDR.
+func (a *AWS) GetCIDRByVPCTag(vpcTagName string, logger log.FieldLogger) (string, error) {
+	err := a.behavior.invoke("GetCIDRByVPCTag")
+	if err != nil {
+		return "", err
+	}
+
+	return VpcCIDR, nil
+}
+
+// GetVpcResourcesByVpcID returns the resources of the given fake VPC.
+func (a *AWS) GetVpcResourcesByVpcID(vpcID string, logger log.FieldLogger) (aws.ClusterResources, error) {
+	err := a.behavior.invoke("GetVpcResourcesByVpcID")
+	if err != nil {
+		return aws.ClusterResources{}, err
+	}
+
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	for _, resources := range a.vpcs {
+		if resources.VpcID == vpcID {
+			return resources, nil
+		}
+	}
+
+	return newClusterResources(vpcID), nil
+}
+
+// TagResourcesByCluster claims the given fake VPC for the given cluster.
+func (a *AWS) TagResourcesByCluster(clusterResources aws.ClusterResources, clusterID string, owner string, logger log.FieldLogger) error {
+	err := a.behavior.invoke("TagResourcesByCluster")
+	if err != nil {
+		return err
+	}
+
+	a.mux.Lock()
+	defer a.mux.Unlock()
+
+	a.vpcs[clusterID] = clusterResources
+
+	return nil
+}
+
+func newClusterResources(vpcID string) aws.ClusterResources {
+	return aws.ClusterResources{
+		VpcID:                  vpcID,
+		VpcCIDR:                VpcCIDR,
+		PrivateSubnetIDs:       []string{fmt.Sprintf("%s-private", vpcID)},
+		PublicSubnetsIDs:       []string{fmt.Sprintf("%s-public", vpcID)},
+		MasterSecurityGroupIDs: []string{fmt.Sprintf("%s-master", vpcID)},
+		WorkerSecurityGroupIDs: []string{fmt.Sprintf("%s-worker", vpcID)},
+	}
+}
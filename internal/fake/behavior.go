@@ -0,0 +1,112 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+// Package fake provides in-memory implementations of the provisioner and of
+// the AWS client, allowing the supervisors to be run end to end without any
+// cloud access.
+package fake
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrInjectedFailure is the error returned by operations configured to fail.
+var ErrInjectedFailure = errors.New("injected failure")
+
+// Behavior configures the latencies and failures of the fake operations.
+// Operations are identified by the name of the invoked method, for example
+// "CreateCluster" or "CreatePublicCNAME".
+type Behavior struct {
+	mux            sync.Mutex
+	defaultLatency time.Duration
+	latencies      map[string]time.Duration
+	failureRate    float64
+	failures       map[string]int
+	calls          map[string]int
+	random         *rand.Rand
+}
+
+// NewBehavior creates a new Behavior with no latencies and no failures.
+func NewBehavior() *Behavior {
+	return &Behavior{
+		latencies: make(map[string]time.Duration),
+		failures:  make(map[string]int),
+		calls:     make(map[string]int),
+		random:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// SetDefaultLatency sets the latency of operations without a specific latency.
+func (b *Behavior) SetDefaultLatency(latency time.Duration) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	b.defaultLatency = latency
+}
+
+// SetLatency sets the latency of the given operation.
+func (b *Behavior) SetLatency(operation string, latency time.Duration) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	b.latencies[operation] = latency
+}
+
+// SetFailureRate sets the probability, between 0 and 1, of any operation
+// failing.
+func (b *Behavior) SetFailureRate(rate float64) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	b.failureRate = rate
+}
+
+// InjectFailures makes the next count invocations of the given operation fail.
+func (b *Behavior) InjectFailures(operation string, count int) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	b.failures[operation] += count
+}
+
+// Calls returns the number of times the given operation was invoked.
+func (b *Behavior) Calls(operation string) int {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	return b.calls[operation]
+}
+
+// invoke records an invocation of the given operation, waits for its latency
+// and returns an error if the operation should fail.
+func (b *Behavior) invoke(operation string) error {
+	b.mux.Lock()
+	b.calls[operation]++
+
+	latency, ok := b.latencies[operation]
+	if !ok {
+		latency = b.defaultLatency
+	}
+
+	var fail bool
+	if b.failures[operation] > 0 {
+		b.failures[operation]--
+		fail = true
+	} else if b.failureRate > 0 && b.random.Float64() < b.failureRate {
+		fail = true
+	}
+	b.mux.Unlock()
+
+	time.Sleep(latency)
+
+	if fail {
+		return errors.Wrap(ErrInjectedFailure, operation)
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package fake
+
+import (
+	"fmt"
+
+	"github.com/mattermost/mattermost-cloud/internal/tools/cloud"
+	"github.com/mattermost/mattermost-cloud/model"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CloudProvider is a cloud.Provider backed by the fake AWS client. The
+// databases and filestores it returns only exist in memory.
+type CloudProvider struct {
+	*cloud.AWSProvider
+	behavior *Behavior
+}
+
+// NewCloudProvider creates a new CloudProvider on top of the given fake AWS client.
+func NewCloudProvider(awsClient *AWS) *CloudProvider {
+	return &CloudProvider{
+		AWSProvider: cloud.NewAWSProvider(awsClient, nil),
+		behavior:    awsClient.behavior,
+	}
+}
+
+// GetDatabase returns a fake database of the given installation.
+func (p *CloudProvider) GetDatabase(installationID, dbType string) model.Database {
+	return &Database{installationID: installationID, behavior: p.behavior}
+}
+
+// GetDatabaseForInstallation returns a fake database of the given installation.
+func (p *CloudProvider) GetDatabaseForInstallation(installation *model.Installation) model.Database {
+	return p.GetDatabase(installation.ID, installation.Database)
+}
+
+// GetFilestore returns a fake filestore of the given installation.
+func (p *CloudProvider) GetFilestore(installation *model.Installation) model.Filestore {
+	return &Filestore{installationID: installation.ID, behavior: p.behavior}
+}
+
+// Database is an in-memory implementation of model.Database.
+type Database struct {
+	installationID string
+	behavior       *Behavior
+}
+
+// Provision does nothing.
+func (d *Database) Provision(store model.InstallationDatabaseStoreInterface, logger log.FieldLogger) error {
+	return d.behavior.invoke("ProvisionDatabase")
+}
+
+// Teardown does nothing.
+func (d *Database) Teardown(store model.InstallationDatabaseStoreInterface, keepData bool, logger log.FieldLogger) error {
+	return d.behavior.invoke("TeardownDatabase")
+}
+
+// Snapshot does nothing.
+func (d *Database) Snapshot(store model.InstallationDatabaseStoreInterface, logger log.FieldLogger) error {
+	return d.behavior.invoke("SnapshotDatabase")
+}
+
+// GenerateDatabaseSecret returns an empty database secret.
+func (d *Database) GenerateDatabaseSecret(store model.InstallationDatabaseStoreInterface, logger log.FieldLogger) (*corev1.Secret, error) {
+	err := d.behavior.invoke("GenerateDatabaseSecret")
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("%s-database", d.installationID),
+		},
+	}, nil
+}
+
+// RefreshResourceMetadata does nothing.
+func (d *Database) RefreshResourceMetadata(store model.InstallationDatabaseStoreInterface, logger log.FieldLogger) error {
+	return d.behavior.invoke("RefreshResourceMetadata")
+}
+
+// MigrateOut does nothing.
+func (d *Database) MigrateOut(store model.InstallationDatabaseStoreInterface, dbMigration *model.InstallationDBMigrationOperation, logger log.FieldLogger) error {
+	return d.behavior.invoke("MigrateOut")
+}
+
+// MigrateTo does nothing.
+func (d *Database) MigrateTo(store model.InstallationDatabaseStoreInterface, dbMigration *model.InstallationDBMigrationOperation, logger log.FieldLogger) error {
+	return d.behavior.invoke("MigrateTo")
+}
+
+// TeardownMigrated does nothing.
+func (d *Database) TeardownMigrated(store model.InstallationDatabaseStoreInterface, migrationOp *model.InstallationDBMigrationOperation, logger log.FieldLogger) error {
+	return d.behavior.invoke("TeardownMigrated")
+}
+
+// RollbackMigration does nothing.
+func (d *Database) RollbackMigration(store model.InstallationDatabaseStoreInterface, dbMigration *model.InstallationDBMigrationOperation, logger log.FieldLogger) error {
+	return d.behavior.invoke("RollbackMigration")
+}
+
+// RestoreToPointInTime completes immediately.
+func (d *Database) RestoreToPointInTime(store model.InstallationDatabaseStoreInterface, sourceInstallationID string, restoreTime int64, logger log.FieldLogger) (bool, error) {
+	err := d.behavior.invoke("RestoreToPointInTime")
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Filestore is an in-memory implementation of model.Filestore.
+type Filestore struct {
+	installationID string
+	behavior       *Behavior
+}
+
+// Provision does nothing.
+func (f *Filestore) Provision(store model.InstallationDatabaseStoreInterface, logger log.FieldLogger) error {
+	return f.behavior.invoke("ProvisionFilestore")
+}
+
+// Teardown does nothing.
+func (f *Filestore) Teardown(keepData bool, store model.InstallationDatabaseStoreInterface, logger log.FieldLogger) error {
+	return f.behavior.invoke("TeardownFilestore")
+}
+
+// GenerateFilestoreSpecAndSecret returns the configuration of the fake filestore.
+func (f *Filestore) GenerateFilestoreSpecAndSecret(store model.InstallationDatabaseStoreInterface, logger log.FieldLogger) (*model.FilestoreConfig, *corev1.Secret, error) {
+	err := f.behavior.invoke("GenerateFilestoreSpecAndSecret")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &model.FilestoreConfig{
+		URL:    "s3.amazonaws.com",
+		Bucket: MultitenantBucketName,
+		Secret: fmt.Sprintf("%s-filestore", f.installationID),
+	}, nil, nil
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package fake_test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/api"
+	"github.com/mattermost/mattermost-cloud/internal/fake"
+	"github.com/mattermost/mattermost-cloud/internal/metrics"
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitForState runs the supervisors until the state returned by getState
+// matches the expected state.
+func waitForState(t *testing.T, doer supervisor.Doer, expected string, getState func() (string, error)) {
+	var state string
+	for i := 0; i < 20; i++ {
+		err := doer.Do()
+		require.NoError(t, err)
+
+		state, err = getState()
+		require.NoError(t, err)
+		if state == expected {
+			return
+		}
+	}
+
+	require.Failf(t, "unexpected state", "expected %s, but got %s", expected, state)
+}
+
+func TestBehavior(t *testing.T) {
+	behavior := fake.NewBehavior()
+	behavior.InjectFailures("Operation", 2)
+	behavior.SetLatency("Operation", 10*time.Millisecond)
+
+	logger := testlib.MakeLogger(t)
+	awsClient := fake.NewAWS(behavior, logger)
+
+	start := time.Now()
+	_, err := awsClient.GetPrivateZoneDomainName(logger)
+	require.NoError(t, err)
+	assert.True(t, time.Since(start) < 10*time.Millisecond)
+
+	behavior.InjectFailures("GetPrivateZoneDomainName", 2)
+	behavior.SetLatency("GetPrivateZoneDomainName", 10*time.Millisecond)
+	for i := 0; i < 2; i++ {
+		_, err = awsClient.GetPrivateZoneDomainName(logger)
+		require.Error(t, err)
+		assert.Equal(t, fake.ErrInjectedFailure, errors.Cause(err))
+	}
+
+	start = time.Now()
+	_, err = awsClient.GetPrivateZoneDomainName(logger)
+	require.NoError(t, err)
+	assert.True(t, time.Since(start) >= 10*time.Millisecond)
+	assert.Equal(t, 4, behavior.Calls("GetPrivateZoneDomainName"))
+	assert.Equal(t, 0, behavior.Calls("Operation"))
+
+	behavior.SetFailureRate(1)
+	err = awsClient.ReleaseVpc("cluster", logger)
+	require.Error(t, err)
+}
+
+func TestEndToEnd(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	behavior := fake.NewBehavior()
+	awsClient := fake.NewAWS(behavior, logger)
+	cloudProvider := fake.NewCloudProvider(awsClient)
+	provisioner := fake.NewProvisioner(behavior, logger)
+	cloudMetrics := metrics.New()
+
+	scheduling := supervisor.NewInstallationSupervisorSchedulingOptions(false, 80, 0, "")
+	doer := supervisor.MultiDoer{
+		supervisor.NewClusterSupervisor(sqlStore, provisioner, cloudProvider, "instanceID", logger, cloudMetrics),
+		supervisor.NewInstallationSupervisor(sqlStore, provisioner, cloudProvider, "instanceID", false, false, scheduling, logger, cloudMetrics, false),
+		supervisor.NewClusterInstallationSupervisor(sqlStore, provisioner, cloudProvider, "instanceID", logger),
+	}
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:       sqlStore,
+		Supervisor:  doer,
+		Provisioner: provisioner,
+		Environment: fake.EnvironmentName,
+		Logger:      logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	cluster, err := client.CreateCluster(&model.CreateClusterRequest{
+		AllowInstallations: true,
+	})
+	require.NoError(t, err)
+
+	getClusterState := func() (string, error) {
+		cluster, err = client.GetCluster(cluster.ID)
+		if err != nil {
+			return "", err
+		}
+		return cluster.State, nil
+	}
+
+	t.Run("create cluster", func(t *testing.T) {
+		waitForState(t, doer, model.ClusterStateStable, getClusterState)
+		assert.Equal(t, fake.KubernetesVersion, cluster.ProvisionerMetadataKops.Version)
+		assert.Equal(t, int64(2), cluster.ProvisionerMetadataKops.NodeMinCount)
+		assert.Nil(t, cluster.ProvisionerMetadataKops.ChangeRequest)
+		assert.Equal(t, model.NginxDefaultVersion, cluster.UtilityMetadata.ActualVersions.Nginx)
+	})
+
+	t.Run("upgrade cluster with injected failure", func(t *testing.T) {
+		behavior.InjectFailures("UpgradeCluster", 1)
+		version := "1.18.1"
+
+		cluster, err = client.UpgradeCluster(cluster.ID, &model.PatchUpgradeClusterRequest{Version: &version})
+		require.NoError(t, err)
+		waitForState(t, doer, model.ClusterStateUpgradeFailed, getClusterState)
+
+		cluster, err = client.UpgradeCluster(cluster.ID, &model.PatchUpgradeClusterRequest{Version: &version})
+		require.NoError(t, err)
+		waitForState(t, doer, model.ClusterStateStable, getClusterState)
+		assert.Equal(t, "1.18.1", cluster.ProvisionerMetadataKops.Version)
+		assert.Equal(t, 2, behavior.Calls("UpgradeCluster"))
+	})
+
+	installation, err := client.CreateInstallation(&model.CreateInstallationRequest{
+		OwnerID:  "owner",
+		DNS:      "fake.example.com",
+		Affinity: model.InstallationAffinityMultiTenant,
+	})
+	require.NoError(t, err)
+
+	getInstallationState := func() (string, error) {
+		installation, err = client.GetInstallation(installation.ID, nil)
+		if err != nil {
+			return "", err
+		}
+		return installation.State, nil
+	}
+
+	t.Run("create installation", func(t *testing.T) {
+		waitForState(t, doer, model.InstallationStateStable, getInstallationState)
+
+		endpoints, ok := awsClient.GetPublicCNAME("fake.example.com")
+		require.True(t, ok)
+		assert.Len(t, endpoints, 1)
+
+		resources, err := provisioner.GetClusterResources(cluster.Cluster, false)
+		require.NoError(t, err)
+		assert.Equal(t, int64(fake.ClusterInstallationMilliCPU), resources.MilliUsedCPU)
+	})
+
+	t.Run("delete installation", func(t *testing.T) {
+		err = client.DeleteInstallation(installation.ID)
+		require.NoError(t, err)
+		waitForState(t, doer, model.InstallationStateDeleted, getInstallationState)
+
+		_, ok := awsClient.GetPublicCNAME("fake.example.com")
+		assert.False(t, ok)
+	})
+
+	t.Run("delete cluster", func(t *testing.T) {
+		err = client.DeleteCluster(cluster.ID)
+		require.NoError(t, err)
+		waitForState(t, doer, model.ClusterStateDeleted, getClusterState)
+	})
+}
@@ -0,0 +1,592 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package fake
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/mattermost/mattermost-cloud/internal/provisioner"
+	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
+	"github.com/mattermost/mattermost-cloud/k8s"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// KubernetesVersion is the version of clusters created with the "latest" version.
+	KubernetesVersion = "1.19.9"
+	// NodeMilliCPU is the allocatable CPU of every fake cluster node.
+	NodeMilliCPU = 4000
+	// NodeMilliMemory is the allocatable memory of every fake cluster node.
+	NodeMilliMemory = 16 * 1024 * 1024 * 1024 * 1000
+	// ClusterInstallationMilliCPU is the CPU requested by every fake cluster installation.
+	ClusterInstallationMilliCPU = 500
+	// ClusterInstallationMilliMemory is the memory requested by every fake cluster installation.
+	ClusterInstallationMilliMemory = 1024 * 1024 * 1024 * 1000
+
+	backupBucketName = "fake-backups"
+)
+
+var utilityNames = []string{
+	model.NginxCanonicalName,
+	model.NginxInternalCanonicalName,
+	model.PrometheusOperatorCanonicalName,
+	model.ThanosCanonicalName,
+	model.FluentbitCanonicalName,
+	model.TeleportCanonicalName,
+	model.PgbouncerCanonicalName,
+}
+
+type fakeClusterInstallation struct {
+	clusterID   string
+	hibernating bool
+}
+
+// Provisioner is an in-memory provisioner of clusters, cluster installations,
+// backups and restorations. The state of the fake clusters is kept apart from
+// the given model.Cluster objects, so that metadata is only updated once
+// refreshed, as with real clusters.
+type Provisioner struct {
+	behavior *Behavior
+	logger   log.FieldLogger
+
+	mux                  sync.Mutex
+	clusters             map[string]*model.KopsMetadata
+	clusterInstallations map[string]*fakeClusterInstallation
+	jobs                 map[string]int64
+}
+
+// NewProvisioner creates a new fake Provisioner.
+func NewProvisioner(behavior *Behavior, logger log.FieldLogger) *Provisioner {
+	return &Provisioner{
+		behavior:             behavior,
+		logger:               logger.WithField("provisioner", "fake"),
+		clusters:             make(map[string]*model.KopsMetadata),
+		clusterInstallations: make(map[string]*fakeClusterInstallation),
+		jobs:                 make(map[string]int64),
+	}
+}
+
+// Teardown does nothing as the fake provisioner holds no external resources.
+func (p *Provisioner) Teardown() {
+	p.logger.Debug("Performing fake provisioner cleanup")
+}
+
+// PrepareCluster generates the kops name of the cluster.
+func (p *Provisioner) PrepareCluster(cluster *model.Cluster) bool {
+	if cluster.ProvisionerMetadataKops == nil || cluster.ProvisionerMetadataKops.Name != "" {
+		return false
+	}
+
+	cluster.ProvisionerMetadataKops.Name = fmt.Sprintf("%s-kops.k8s.local", cluster.ID)
+
+	return true
+}
+
+// CreateCluster creates a fake cluster matching the change request.
+func (p *Provisioner) CreateCluster(cluster *model.Cluster) error {
+	err := p.behavior.invoke("CreateCluster")
+	if err != nil {
+		return err
+	}
+
+	kopsMetadata := cluster.ProvisionerMetadataKops
+	err = kopsMetadata.ValidateChangeRequest()
+	if err != nil {
+		return errors.Wrap(err, "KopsMetadata ChangeRequest failed validation")
+	}
+
+	state := &model.KopsMetadata{Name: kopsMetadata.Name}
+	applyChangeRequest(state, kopsMetadata.ChangeRequest)
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	p.clusters[cluster.ID] = state
+	p.logger.WithField("cluster", cluster.ID).Info("Created fake cluster")
+
+	return nil
+}
+
+// ProvisionCluster marks the desired utility versions as installed.
+func (p *Provisioner) ProvisionCluster(cluster *model.Cluster) error {
+	err := p.behavior.invoke("ProvisionCluster")
+	if err != nil {
+		return err
+	}
+
+	err = p.ensureCluster(cluster)
+	if err != nil {
+		return err
+	}
+
+	for _, utility := range utilityNames {
+		version := cluster.DesiredUtilityVersion(utility)
+		if version == nil {
+			continue
+		}
+		err = cluster.SetUtilityActualVersion(utility, version)
+		if err != nil {
+			return errors.Wrapf(err, "failed to set actual version of %s", utility)
+		}
+	}
+
+	return nil
+}
+
+// UpgradeCluster applies the requested version and AMI to the fake cluster.
+func (p *Provisioner) UpgradeCluster(cluster *model.Cluster) error {
+	return p.changeCluster("UpgradeCluster", cluster)
+}
+
+// ResizeCluster applies the requested instance types and counts to the fake cluster.
+func (p *Provisioner) ResizeCluster(cluster *model.Cluster) error {
+	return p.changeCluster("ResizeCluster", cluster)
+}
+
+// DeleteCluster deletes the fake cluster.
+func (p *Provisioner) DeleteCluster(cluster *model.Cluster) error {
+	err := p.behavior.invoke("DeleteCluster")
+	if err != nil {
+		return err
+	}
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	delete(p.clusters, cluster.ID)
+	for id, clusterInstallation := range p.clusterInstallations {
+		if clusterInstallation.clusterID == cluster.ID {
+			delete(p.clusterInstallations, id)
+		}
+	}
+
+	return nil
+}
+
+// RefreshKopsMetadata updates the cluster metadata with the state of the fake cluster.
+func (p *Provisioner) RefreshKopsMetadata(cluster *model.Cluster) error {
+	err := p.behavior.invoke("RefreshKopsMetadata")
+	if err != nil {
+		return err
+	}
+
+	if cluster.Provisioner == model.ProvisionerLocal {
+		cluster.ProvisionerMetadataLocal.Version = KubernetesVersion
+		return nil
+	}
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	state, ok := p.clusters[cluster.ID]
+	if !ok {
+		return errors.Errorf("fake cluster %s not found", cluster.ID)
+	}
+
+	kopsMetadata := cluster.ProvisionerMetadataKops
+	kopsMetadata.Name = state.Name
+	kopsMetadata.Version = state.Version
+	kopsMetadata.AMI = state.AMI
+	kopsMetadata.MasterInstanceType = state.MasterInstanceType
+	kopsMetadata.MasterCount = state.MasterCount
+	kopsMetadata.NodeInstanceType = state.NodeInstanceType
+	kopsMetadata.NodeMinCount = state.NodeMinCount
+	kopsMetadata.NodeMaxCount = state.NodeMaxCount
+	kopsMetadata.Networking = state.Networking
+	kopsMetadata.VPC = state.VPC
+	kopsMetadata.MasterInstanceGroups = model.KopsInstanceGroupsMetadata{
+		"master": {
+			NodeInstanceType: state.MasterInstanceType,
+			NodeMinCount:     state.MasterCount,
+			NodeMaxCount:     state.MasterCount,
+		},
+	}
+	kopsMetadata.NodeInstanceGroups = model.KopsInstanceGroupsMetadata{
+		"nodes": {
+			NodeInstanceType: state.NodeInstanceType,
+			NodeMinCount:     state.NodeMinCount,
+			NodeMaxCount:     state.NodeMaxCount,
+		},
+	}
+
+	return nil
+}
+
+// GetClusterResources returns the resources of the fake cluster nodes, of
+// which every cluster installation uses a fixed amount.
+func (p *Provisioner) GetClusterResources(cluster *model.Cluster, onlySchedulable bool) (*k8s.ClusterResources, error) {
+	err := p.behavior.invoke("GetClusterResources")
+	if err != nil {
+		return nil, err
+	}
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	nodes := int64(1)
+	if cluster.Provisioner != model.ProvisionerLocal {
+		state, ok := p.clusters[cluster.ID]
+		if !ok {
+			return nil, errors.Errorf("fake cluster %s not found", cluster.ID)
+		}
+		nodes = state.NodeMinCount
+	}
+
+	var clusterInstallations int64
+	for _, clusterInstallation := range p.clusterInstallations {
+		if clusterInstallation.clusterID == cluster.ID && !clusterInstallation.hibernating {
+			clusterInstallations++
+		}
+	}
+
+	return &k8s.ClusterResources{
+		MilliTotalCPU:    nodes * NodeMilliCPU,
+		MilliUsedCPU:     clusterInstallations * ClusterInstallationMilliCPU,
+		MilliTotalMemory: nodes * NodeMilliMemory,
+		MilliUsedMemory:  clusterInstallations * ClusterInstallationMilliMemory,
+		NodeCount:        nodes,
+	}, nil
+}
+
+// GetPublicLoadBalancerEndpoint returns a fake load balancer hostname.
+func (p *Provisioner) GetPublicLoadBalancerEndpoint(cluster *model.Cluster, namespace string) (string, error) {
+	err := p.behavior.invoke("GetPublicLoadBalancerEndpoint")
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s.%s.elb.%s", namespace, cluster.ID, PrivateZoneDomainName), nil
+}
+
+// ClusterInstallationProvisioner returns the fake provisioner for every custom
+// resource version.
+func (p *Provisioner) ClusterInstallationProvisioner(version string) provisioner.ClusterInstallationProvisioner {
+	return p
+}
+
+// CreateClusterInstallation creates a fake cluster installation.
+func (p *Provisioner) CreateClusterInstallation(cluster *model.Cluster, installation *model.Installation, clusterInstallation *model.ClusterInstallation) error {
+	err := p.behavior.invoke("CreateClusterInstallation")
+	if err != nil {
+		return err
+	}
+
+	err = p.ensureCluster(cluster)
+	if err != nil {
+		return err
+	}
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	p.clusterInstallations[clusterInstallation.ID] = &fakeClusterInstallation{clusterID: cluster.ID}
+
+	return nil
+}
+
+// EnsureCRMigrated does nothing as there are no custom resources to migrate.
+func (p *Provisioner) EnsureCRMigrated(cluster *model.Cluster, clusterInstallation *model.ClusterInstallation) (bool, error) {
+	err := p.behavior.invoke("EnsureCRMigrated")
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// HibernateClusterInstallation hibernates the fake cluster installation.
+func (p *Provisioner) HibernateClusterInstallation(cluster *model.Cluster, installation *model.Installation, clusterInstallation *model.ClusterInstallation) error {
+	return p.setHibernating("HibernateClusterInstallation", clusterInstallation, true)
+}
+
+// UpdateClusterInstallation updates the fake cluster installation, waking it
+// up if hibernating.
+func (p *Provisioner) UpdateClusterInstallation(cluster *model.Cluster, installation *model.Installation, clusterInstallation *model.ClusterInstallation) error {
+	return p.setHibernating("UpdateClusterInstallation", clusterInstallation, false)
+}
+
+// VerifyClusterInstallationMatchesConfig returns true if the fake cluster
+// installation exists.
+func (p *Provisioner) VerifyClusterInstallationMatchesConfig(cluster *model.Cluster, installation *model.Installation, clusterInstallation *model.ClusterInstallation) (bool, error) {
+	err := p.behavior.invoke("VerifyClusterInstallationMatchesConfig")
+	if err != nil {
+		return false, err
+	}
+
+	return p.clusterInstallationExists(clusterInstallation), nil
+}
+
+// DeleteOldClusterInstallationLicenseSecrets does nothing.
+func (p *Provisioner) DeleteOldClusterInstallationLicenseSecrets(cluster *model.Cluster, installation *model.Installation, clusterInstallation *model.ClusterInstallation) error {
+	return p.behavior.invoke("DeleteOldClusterInstallationLicenseSecrets")
+}
+
+// DeleteClusterInstallation deletes the fake cluster installation.
+func (p *Provisioner) DeleteClusterInstallation(cluster *model.Cluster, installation *model.Installation, clusterInstallation *model.ClusterInstallation) error {
+	err := p.behavior.invoke("DeleteClusterInstallation")
+	if err != nil {
+		return err
+	}
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	delete(p.clusterInstallations, clusterInstallation.ID)
+
+	return nil
+}
+
+// IsResourceReady returns true if the fake cluster installation exists.
+func (p *Provisioner) IsResourceReady(cluster *model.Cluster, clusterInstallation *model.ClusterInstallation) (bool, error) {
+	err := p.behavior.invoke("IsResourceReady")
+	if err != nil {
+		return false, err
+	}
+
+	return p.clusterInstallationExists(clusterInstallation), nil
+}
+
+// RefreshSecrets does nothing.
+func (p *Provisioner) RefreshSecrets(cluster *model.Cluster, installation *model.Installation, clusterInstallation *model.ClusterInstallation) error {
+	return p.behavior.invoke("RefreshSecrets")
+}
+
+// ExecClusterInstallationCLI returns an empty JSON list for every command.
+func (p *Provisioner) ExecClusterInstallationCLI(cluster *model.Cluster, clusterInstallation *model.ClusterInstallation, args ...string) ([]byte, error) {
+	err := p.behavior.invoke("ExecClusterInstallationCLI")
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte("[]"), nil
+}
+
+// ExecMattermostCLI returns an empty JSON object for every command.
+func (p *Provisioner) ExecMattermostCLI(cluster *model.Cluster, clusterInstallation *model.ClusterInstallation, args ...string) ([]byte, error) {
+	err := p.behavior.invoke("ExecMattermostCLI")
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte("{}"), nil
+}
+
+// ExecClusterInstallationJob does nothing.
+func (p *Provisioner) ExecClusterInstallationJob(cluster *model.Cluster, clusterInstallation *model.ClusterInstallation, args ...string) error {
+	return p.behavior.invoke("ExecClusterInstallationJob")
+}
+
+// TriggerBackup starts a fake backup job, which completes on the next check.
+func (p *Provisioner) TriggerBackup(backup *model.InstallationBackup, cluster *model.Cluster, installation *model.Installation) (*model.S3DataResidence, error) {
+	err := p.behavior.invoke("TriggerBackup")
+	if err != nil {
+		return nil, err
+	}
+
+	p.startJob(backupJobName(backup))
+
+	return &model.S3DataResidence{
+		Region:     "us-east-1",
+		URL:        "s3.amazonaws.com",
+		Bucket:     backupBucketName,
+		PathPrefix: installation.ID,
+		ObjectKey:  fmt.Sprintf("backup-%s", backup.ID),
+
+		FilestorePrefix:      fmt.Sprintf("backup-%s-filestore", backup.ID),
+		FilestoreManifestKey: fmt.Sprintf("backup-%s-filestore-manifest.txt", backup.ID),
+	}, nil
+}
+
+// CheckBackupStatus completes the fake backup job.
+func (p *Provisioner) CheckBackupStatus(backup *model.InstallationBackup, cluster *model.Cluster) (int64, error) {
+	return p.completeJob("CheckBackupStatus", backupJobName(backup))
+}
+
+// CleanupBackupJob removes the fake backup job.
+func (p *Provisioner) CleanupBackupJob(backup *model.InstallationBackup, cluster *model.Cluster) error {
+	return p.cleanupJob("CleanupBackupJob", backupJobName(backup))
+}
+
+// TriggerRestore starts a fake restoration job, which completes on the next check.
+func (p *Provisioner) TriggerRestore(installation *model.Installation, backup *model.InstallationBackup, cluster *model.Cluster) error {
+	err := p.behavior.invoke("TriggerRestore")
+	if err != nil {
+		return err
+	}
+
+	p.startJob(restoreJobName(installation, backup))
+
+	return nil
+}
+
+// CheckRestoreStatus completes the fake restoration job.
+func (p *Provisioner) CheckRestoreStatus(installation *model.Installation, backup *model.InstallationBackup, cluster *model.Cluster) (int64, error) {
+	return p.completeJob("CheckRestoreStatus", restoreJobName(installation, backup))
+}
+
+// CleanupRestoreJob removes the fake restoration job.
+func (p *Provisioner) CleanupRestoreJob(installation *model.Installation, backup *model.InstallationBackup, cluster *model.Cluster) error {
+	return p.cleanupJob("CleanupRestoreJob", restoreJobName(installation, backup))
+}
+
+func (p *Provisioner) changeCluster(operation string, cluster *model.Cluster) error {
+	err := p.behavior.invoke(operation)
+	if err != nil {
+		return err
+	}
+
+	err = cluster.ProvisionerMetadataKops.ValidateChangeRequest()
+	if err != nil {
+		return errors.Wrap(err, "KopsMetadata ChangeRequest failed validation")
+	}
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	state, ok := p.clusters[cluster.ID]
+	if !ok {
+		return errors.Errorf("fake cluster %s not found", cluster.ID)
+	}
+	applyChangeRequest(state, cluster.ProvisionerMetadataKops.ChangeRequest)
+
+	return nil
+}
+
+// ensureCluster returns an error if the given cluster was not created. Local
+// clusters are registered on first use.
+func (p *Provisioner) ensureCluster(cluster *model.Cluster) error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	if cluster.Provisioner == model.ProvisionerLocal {
+		if _, ok := p.clusters[cluster.ID]; !ok {
+			p.clusters[cluster.ID] = &model.KopsMetadata{Version: KubernetesVersion, NodeMinCount: 1, NodeMaxCount: 1}
+		}
+		return nil
+	}
+
+	if _, ok := p.clusters[cluster.ID]; !ok {
+		return errors.Errorf("fake cluster %s not found", cluster.ID)
+	}
+
+	return nil
+}
+
+func (p *Provisioner) setHibernating(operation string, clusterInstallation *model.ClusterInstallation, hibernating bool) error {
+	err := p.behavior.invoke(operation)
+	if err != nil {
+		return err
+	}
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	fakeClusterInstallation, ok := p.clusterInstallations[clusterInstallation.ID]
+	if !ok {
+		return errors.Errorf("fake cluster installation %s not found", clusterInstallation.ID)
+	}
+	fakeClusterInstallation.hibernating = hibernating
+
+	return nil
+}
+
+func (p *Provisioner) clusterInstallationExists(clusterInstallation *model.ClusterInstallation) bool {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	_, ok := p.clusterInstallations[clusterInstallation.ID]
+	return ok
+}
+
+func (p *Provisioner) startJob(name string) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	p.jobs[name] = -1
+}
+
+func (p *Provisioner) completeJob(operation, name string) (int64, error) {
+	err := p.behavior.invoke(operation)
+	if err != nil {
+		return -1, err
+	}
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	completeAt, ok := p.jobs[name]
+	if !ok {
+		return -1, errors.Errorf("fake job %s not found", name)
+	}
+	if completeAt <= 0 {
+		completeAt = utils.GetMillis()
+		p.jobs[name] = completeAt
+	}
+
+	return completeAt, nil
+}
+
+func (p *Provisioner) cleanupJob(operation, name string) error {
+	err := p.behavior.invoke(operation)
+	if err != nil {
+		return err
+	}
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	delete(p.jobs, name)
+
+	return nil
+}
+
+func backupJobName(backup *model.InstallationBackup) string {
+	return fmt.Sprintf("backup-%s", backup.ID)
+}
+
+func restoreJobName(installation *model.Installation, backup *model.InstallationBackup) string {
+	return fmt.Sprintf("restore-%s-%s", installation.ID, backup.ID)
+}
+
+// applyChangeRequest applies the non-empty values of the change request to
+// the state of a fake cluster.
+func applyChangeRequest(state *model.KopsMetadata, changeRequest *model.KopsMetadataRequestedState) {
+	if changeRequest == nil {
+		return
+	}
+
+	if changeRequest.Version == "latest" {
+		state.Version = KubernetesVersion
+	} else if changeRequest.Version != "" {
+		state.Version = changeRequest.Version
+	}
+	if changeRequest.AMI != "" {
+		state.AMI = changeRequest.AMI
+	}
+	if changeRequest.MasterInstanceType != "" {
+		state.MasterInstanceType = changeRequest.MasterInstanceType
+	}
+	if changeRequest.MasterCount != 0 {
+		state.MasterCount = changeRequest.MasterCount
+	}
+	if changeRequest.NodeInstanceType != "" {
+		state.NodeInstanceType = changeRequest.NodeInstanceType
+	}
+	if changeRequest.NodeMinCount != 0 {
+		state.NodeMinCount = changeRequest.NodeMinCount
+	}
+	if changeRequest.NodeMaxCount != 0 {
+		state.NodeMaxCount = changeRequest.NodeMaxCount
+	}
+	if changeRequest.Networking != "" {
+		state.Networking = changeRequest.Networking
+	}
+	if changeRequest.VPC != "" {
+		state.VPC = changeRequest.VPC
+	}
+}
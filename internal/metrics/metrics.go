@@ -5,14 +5,44 @@
 package metrics
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
+const (
+	// LockResultAcquired is the lock attempt result label value of acquired locks.
+	LockResultAcquired = "acquired"
+	// LockResultContended is the lock attempt result label value of locks held by someone else.
+	LockResultContended = "contended"
+)
+
+// ExternalCommandDurationHist tracks the duration of invocations of external
+// tools such as kops, terraform and helm. It is defined at the package level
+// as the tools are invoked deep within the provisioner.
+var ExternalCommandDurationHist = promauto.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "mm_cloud_external_command_duration_seconds",
+		Help:    "The duration of external tool invocations",
+		Buckets: prometheus.ExponentialBuckets(0.5, 2, 12),
+	},
+	[]string{"tool", "success"},
+)
+
 // CloudMetrics holds all of the metrics needed to properly instrument
 // the Provisioning server
 type CloudMetrics struct {
 	InstallationCreationDurationHist prometheus.Histogram
+
+	// Supervisors
+	SupervisorCyclesCounter     *prometheus.CounterVec
+	SupervisorFailuresCounter   *prometheus.CounterVec
+	SupervisorCycleDurationHist *prometheus.HistogramVec
+	TransitionDurationHist      *prometheus.HistogramVec
+
+	// Store
+	LockAttemptsCounter *prometheus.CounterVec
 }
 
 // New creates a new Prometheus-based Metrics object to be used
@@ -26,5 +56,76 @@ func New() *CloudMetrics {
 				Help:    "The duration of Installation creation tasks",
 				Buckets: prometheus.LinearBuckets(0, 30, 20),
 			}),
+
+		SupervisorCyclesCounter: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "mm_cloud_supervisor_cycles_total",
+				Help: "The number of supervisor cycles",
+			},
+			[]string{"supervisor"},
+		),
+		SupervisorFailuresCounter: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "mm_cloud_supervisor_failures_total",
+				Help: "The number of supervisor cycles which returned an error",
+			},
+			[]string{"supervisor"},
+		),
+		SupervisorCycleDurationHist: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "mm_cloud_supervisor_cycle_duration_seconds",
+				Help:    "The duration of supervisor cycles",
+				Buckets: prometheus.ExponentialBuckets(0.05, 2, 14),
+			},
+			[]string{"supervisor"},
+		),
+		TransitionDurationHist: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "mm_cloud_state_transition_duration_seconds",
+				Help:    "The duration of resource state transitions",
+				Buckets: prometheus.ExponentialBuckets(5, 2, 12),
+			},
+			[]string{"resource", "transition"},
+		),
+
+		LockAttemptsCounter: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "mm_cloud_lock_attempts_total",
+				Help: "The number of attempts to lock resources",
+			},
+			[]string{"table", "result"},
+		),
+	}
+}
+
+// ObserveSupervisorCycle records a cycle of the given supervisor.
+func (m *CloudMetrics) ObserveSupervisorCycle(supervisor string, duration time.Duration, err error) {
+	m.SupervisorCyclesCounter.WithLabelValues(supervisor).Inc()
+	m.SupervisorCycleDurationHist.WithLabelValues(supervisor).Observe(duration.Seconds())
+	if err != nil {
+		m.SupervisorFailuresCounter.WithLabelValues(supervisor).Inc()
+	}
+}
+
+// ObserveTransition records the duration of a completed state transition.
+func (m *CloudMetrics) ObserveTransition(resource, transition string, duration time.Duration) {
+	m.TransitionDurationHist.WithLabelValues(resource, transition).Observe(duration.Seconds())
+}
+
+// ObserveLockAttempt records the outcome of an attempt to lock rows of the given table.
+func (m *CloudMetrics) ObserveLockAttempt(table string, locked bool) {
+	result := LockResultAcquired
+	if !locked {
+		result = LockResultContended
+	}
+	m.LockAttemptsCounter.WithLabelValues(table, result).Inc()
+}
+
+// ObserveExternalCommand records the duration of an invocation of the given external tool.
+func ObserveExternalCommand(tool string, duration time.Duration, err error) {
+	success := "true"
+	if err != nil {
+		success = "false"
 	}
+	ExternalCommandDurationHist.WithLabelValues(tool, success).Observe(duration.Seconds())
 }
@@ -0,0 +1,59 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+var resourcesDesc = prometheus.NewDesc(
+	"mm_cloud_resources",
+	"The number of resources per state",
+	[]string{"resource", "state"},
+	nil,
+)
+
+type resourceStateStore interface {
+	GetResourceStateCounts() (map[string]map[string]int64, error)
+}
+
+// ResourceStateCollector is a Prometheus collector reporting the number of
+// resources in each state. The counts are queried from the store whenever
+// metrics are collected, so that they are accurate regardless of which
+// provisioner instance changed the resources.
+type ResourceStateCollector struct {
+	store  resourceStateStore
+	logger log.FieldLogger
+}
+
+// NewResourceStateCollector creates a new collector of resource states.
+func NewResourceStateCollector(store resourceStateStore, logger log.FieldLogger) *ResourceStateCollector {
+	return &ResourceStateCollector{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// Describe sends the descriptors of the collected metrics to the given channel.
+func (c *ResourceStateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- resourcesDesc
+}
+
+// Collect queries the resource states and sends them as metrics to the given channel.
+func (c *ResourceStateCollector) Collect(ch chan<- prometheus.Metric) {
+	counts, err := c.store.GetResourceStateCounts()
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to query resource state counts")
+		ch <- prometheus.NewInvalidMetric(resourcesDesc, err)
+		return
+	}
+
+	for resource, states := range counts {
+		for state, count := range states {
+			ch <- prometheus.MustNewConstMetric(resourcesDesc, prometheus.GaugeValue, float64(count), resource, state)
+		}
+	}
+}
@@ -340,6 +340,20 @@ func (mr *MockAWSMockRecorder) S3EnsureBucketDeleted(bucketName, logger interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "S3EnsureBucketDeleted", reflect.TypeOf((*MockAWS)(nil).S3EnsureBucketDeleted), bucketName, logger)
 }
 
+// S3EnsureBucketDirectoryDeleted mocks base method
+func (m *MockAWS) S3EnsureBucketDirectoryDeleted(bucketName, directory string, logger logrus.FieldLogger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "S3EnsureBucketDirectoryDeleted", bucketName, directory, logger)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// S3EnsureBucketDirectoryDeleted indicates an expected call of S3EnsureBucketDirectoryDeleted
+func (mr *MockAWSMockRecorder) S3EnsureBucketDirectoryDeleted(bucketName, directory, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "S3EnsureBucketDirectoryDeleted", reflect.TypeOf((*MockAWS)(nil).S3EnsureBucketDirectoryDeleted), bucketName, directory, logger)
+}
+
 // S3EnsureObjectDeleted mocks base method
 func (m *MockAWS) S3EnsureObjectDeleted(bucketName, path string) error {
 	m.ctrl.T.Helper()
@@ -354,6 +368,20 @@ func (mr *MockAWSMockRecorder) S3EnsureObjectDeleted(bucketName, path interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "S3EnsureObjectDeleted", reflect.TypeOf((*MockAWS)(nil).S3EnsureObjectDeleted), bucketName, path)
 }
 
+// S3CopyBucketDirectory mocks base method
+func (m *MockAWS) S3CopyBucketDirectory(srcBucketName, srcPrefix, destBucketName, destPrefix string, logger logrus.FieldLogger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "S3CopyBucketDirectory", srcBucketName, srcPrefix, destBucketName, destPrefix, logger)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// S3CopyBucketDirectory indicates an expected call of S3CopyBucketDirectory
+func (mr *MockAWSMockRecorder) S3CopyBucketDirectory(srcBucketName, srcPrefix, destBucketName, destPrefix, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "S3CopyBucketDirectory", reflect.TypeOf((*MockAWS)(nil).S3CopyBucketDirectory), srcBucketName, srcPrefix, destBucketName, destPrefix, logger)
+}
+
 // S3LargeCopy mocks base method
 func (m *MockAWS) S3LargeCopy(srcBucketName, srcKey, destBucketName, destKey *string) error {
 	m.ctrl.T.Helper()
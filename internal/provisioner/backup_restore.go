@@ -8,10 +8,12 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/util/wait"
 
+	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
 	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -34,15 +36,35 @@ const (
 // ErrJobBackoffLimitReached indicates that job failed all possible attempts and there is no reason for retrying.
 var ErrJobBackoffLimitReached = errors.New("job reached backoff limit")
 
+// filestoreBackupScript copies installation files to the backup directory
+// skipping backup objects and uploads the list of copied files as a manifest.
+const filestoreBackupScript = `set -e
+endpoint=""
+if [ -n "$FILESTORE_ENDPOINT" ]; then endpoint="--endpoint-url $FILESTORE_ENDPOINT"; fi
+aws s3 sync $endpoint --only-show-errors --exclude 'backup-*' "$FILESTORE_SOURCE" "$FILESTORE_DESTINATION"
+aws s3 ls $endpoint --recursive "$FILESTORE_DESTINATION" | aws s3 cp $endpoint - "$FILESTORE_MANIFEST"
+`
+
+// filestoreRestoreScript copies backed up files back to the installation
+// file store. Files created after the backup are left untouched.
+const filestoreRestoreScript = `set -e
+endpoint=""
+if [ -n "$FILESTORE_ENDPOINT" ]; then endpoint="--endpoint-url $FILESTORE_ENDPOINT"; fi
+aws s3 cp $endpoint "$FILESTORE_MANIFEST" - > /dev/null
+aws s3 sync $endpoint --only-show-errors "$FILESTORE_SOURCE" "$FILESTORE_DESTINATION"
+`
+
 // BackupOperator provides methods to run, check and cleanup backup jobs.
 type BackupOperator struct {
 	jobTTLSecondsAfterFinish *int32
 	backupRestoreImage       string
+	filestoreImage           string
 	awsRegion                string
 }
 
 // NewBackupOperator creates new BackupOperator.
-func NewBackupOperator(image, region string, jobTTLSeconds int32) *BackupOperator {
+// If filestoreImage is empty, installation files are not included in backups.
+func NewBackupOperator(image, filestoreImage, region string, jobTTLSeconds int32) *BackupOperator {
 	jobTTL := &jobTTLSeconds
 	if jobTTLSeconds < 0 {
 		jobTTL = nil
@@ -51,6 +73,7 @@ func NewBackupOperator(image, region string, jobTTLSeconds int32) *BackupOperato
 	return &BackupOperator{
 		jobTTLSecondsAfterFinish: jobTTL,
 		backupRestoreImage:       image,
+		filestoreImage:           filestoreImage,
 		awsRegion:                region,
 	}
 }
@@ -78,9 +101,10 @@ func (o BackupOperator) TriggerBackup(
 		storageEndpoint = bifrostEndpoint
 		envVars = bifrostEnvs()
 	}
-	if installation.Filestore == model.InstallationFilestoreBifrost ||
-		installation.Filestore == model.InstallationFilestoreMultiTenantAwsS3 {
-		dataResidence.PathPrefix = installation.ID
+	dataResidence.PathPrefix = installationFilestorePrefix(installation)
+	if o.filestoreImage != "" {
+		dataResidence.FilestorePrefix = backupFilestorePrefix(backup.ID)
+		dataResidence.FilestoreManifestKey = backupFilestoreManifestKey(backup.ID)
 	}
 
 	envVars = append(envVars, prepareEnvs(dataResidence, storageEndpoint, fileStoreCfg.Secret, dbSecret)...)
@@ -88,6 +112,17 @@ func (o BackupOperator) TriggerBackup(
 	backupJobName := makeJobName(backupAction, backup.ID)
 	job := o.createBackupRestoreJob(backupJobName, installation.ID, backupAction, envVars, backupBackoffLimit)
 
+	if dataResidence.HasFilestoreSnapshot() {
+		filestoreEnvs := prepareFilestoreEnvs(
+			dataResidence,
+			filestoreEndpoint(installation, fileStoreCfg.URL),
+			s3DirURL(dataResidence.Bucket, dataResidence.PathPrefix),
+			s3DirURL(dataResidence.Bucket, dataResidence.FilestoreFullPath()),
+			fileStoreCfg.Secret,
+		)
+		addFilestoreContainer(job, o.createFilestoreContainer(filestoreBackupScript, filestoreEnvs))
+	}
+
 	err := o.startJob(jobsClient, job, logger)
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to start backup job")
@@ -119,8 +154,9 @@ func (o BackupOperator) TriggerRestore(
 	if backup.DataResidence == nil {
 		return errors.New("Installation backup is invalid - data residence is nil")
 	}
+	dataResidence := RestoreDataResidence(backup, installation, fileStoreCfg)
 
-	storageEndpoint := backup.DataResidence.URL
+	storageEndpoint := dataResidence.URL
 	var envVars []corev1.EnvVar
 
 	if installation.Filestore == model.InstallationFilestoreBifrost {
@@ -128,11 +164,25 @@ func (o BackupOperator) TriggerRestore(
 		envVars = bifrostEnvs()
 	}
 
-	envVars = append(envVars, prepareEnvs(*backup.DataResidence, storageEndpoint, fileStoreCfg.Secret, dbSecret)...)
+	envVars = append(envVars, prepareEnvs(dataResidence, storageEndpoint, fileStoreCfg.Secret, dbSecret)...)
 
 	restoreJobName := makeJobName(restoreAction, backup.ID)
 	job := o.createBackupRestoreJob(restoreJobName, installation.ID, restoreAction, envVars, restoreBackoffLimit)
 
+	if dataResidence.HasFilestoreSnapshot() {
+		if o.filestoreImage == "" {
+			return errors.New("backup contains installation files, but filestore image is not configured")
+		}
+		filestoreEnvs := prepareFilestoreEnvs(
+			dataResidence,
+			filestoreEndpoint(installation, fileStoreCfg.URL),
+			s3DirURL(dataResidence.Bucket, dataResidence.FilestoreFullPath()),
+			s3DirURL(fileStoreCfg.Bucket, installationFilestorePrefix(installation)),
+			fileStoreCfg.Secret,
+		)
+		addFilestoreContainer(job, o.createFilestoreContainer(filestoreRestoreScript, filestoreEnvs))
+	}
+
 	err := o.startJob(jobsClient, job, logger)
 	if err != nil {
 		return errors.Wrap(err, "Failed to start restore job")
@@ -140,6 +190,24 @@ func (o BackupOperator) TriggerRestore(
 	return nil
 }
 
+// RestoreDataResidence returns the location from which the backup is restored
+// to the installation. Restore jobs only have access to the file store of the
+// installation they restore, so the backup of another installation must first
+// be copied to the returned location. The copy is kept with the installation
+// files and removed along with them.
+func RestoreDataResidence(backup *model.InstallationBackup, installation *model.Installation, fileStoreCfg *model.FilestoreConfig) model.S3DataResidence {
+	dataResidence := *backup.DataResidence
+	if backup.InstallationID == installation.ID {
+		return dataResidence
+	}
+
+	dataResidence.URL = fileStoreCfg.URL
+	dataResidence.Bucket = fileStoreCfg.Bucket
+	dataResidence.PathPrefix = installationFilestorePrefix(installation)
+
+	return dataResidence
+}
+
 // CheckRestoreStatus checks status of restore job,
 // returns job start time, when the job finished or -1 if it is still running.
 func (o BackupOperator) CheckRestoreStatus(jobsClient v1.JobInterface, backup *model.InstallationBackup, logger log.FieldLogger) (int64, error) {
@@ -288,6 +356,81 @@ func (o BackupOperator) createBackupRestoreContainer(action string, envs []corev
 	}
 }
 
+func (o BackupOperator) createFilestoreContainer(script string, envs []corev1.EnvVar) corev1.Container {
+	return corev1.Container{
+		Name:    "filestore",
+		Image:   o.filestoreImage,
+		Command: []string{"/bin/sh", "-c"},
+		Args:    []string{script},
+		Env:     envs,
+	}
+}
+
+func addFilestoreContainer(job *batchv1.Job, container corev1.Container) {
+	job.Spec.Template.Spec.Containers = append(job.Spec.Template.Spec.Containers, container)
+}
+
+func prepareFilestoreEnvs(dataRes model.S3DataResidence, endpoint, source, destination, fileStoreSecret string) []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{
+			Name:  "AWS_DEFAULT_REGION",
+			Value: dataRes.Region,
+		},
+		{
+			Name:  "FILESTORE_ENDPOINT",
+			Value: endpoint,
+		},
+		{
+			Name:  "FILESTORE_SOURCE",
+			Value: source,
+		},
+		{
+			Name:  "FILESTORE_DESTINATION",
+			Value: destination,
+		},
+		{
+			Name:  "FILESTORE_MANIFEST",
+			Value: fmt.Sprintf("s3://%s/%s", dataRes.Bucket, dataRes.FilestoreManifestFullPath()),
+		},
+		{
+			Name:      "AWS_ACCESS_KEY_ID",
+			ValueFrom: envSourceFromSecret(fileStoreSecret, "accesskey"),
+		},
+		{
+			Name:      "AWS_SECRET_ACCESS_KEY",
+			ValueFrom: envSourceFromSecret(fileStoreSecret, "secretkey"),
+		},
+	}
+}
+
+// filestoreEndpoint returns endpoint which should be used by AWS CLI to
+// reach installation file store. Empty value means default AWS endpoint.
+func filestoreEndpoint(installation *model.Installation, url string) string {
+	if installation.Filestore == model.InstallationFilestoreBifrost {
+		return fmt.Sprintf("http://%s", bifrostEndpoint)
+	}
+	if url == "" || url == aws.S3URL {
+		return ""
+	}
+	return fmt.Sprintf("https://%s", url)
+}
+
+func installationFilestorePrefix(installation *model.Installation) string {
+	if installation.Filestore == model.InstallationFilestoreBifrost ||
+		installation.Filestore == model.InstallationFilestoreMultiTenantAwsS3 {
+		return installation.ID
+	}
+	return ""
+}
+
+func s3DirURL(bucket, dir string) string {
+	dir = strings.Trim(dir, "/")
+	if dir == "" {
+		return fmt.Sprintf("s3://%s/", bucket)
+	}
+	return fmt.Sprintf("s3://%s/%s/", bucket, dir)
+}
+
 func prepareEnvs(dataRes model.S3DataResidence, endpoint string, fileStoreSecret, dbSecret string) []corev1.EnvVar {
 	envs := []corev1.EnvVar{
 		{
@@ -373,6 +516,14 @@ func backupObjectKey(id string) string {
 	return fmt.Sprintf("backup-%s", id)
 }
 
+func backupFilestorePrefix(id string) string {
+	return fmt.Sprintf("backup-%s-filestore", id)
+}
+
+func backupFilestoreManifestKey(id string) string {
+	return fmt.Sprintf("backup-%s-filestore-manifest.txt", id)
+}
+
 func makeJobName(action, id string) string {
 	return fmt.Sprintf("database-%s-%s", action, id)
 }
@@ -40,18 +40,25 @@ func TestOperator_TriggerBackup(t *testing.T) {
 		State:          model.InstallationBackupStateBackupRequested,
 	}
 
-	operator := NewBackupOperator("mattermost/backup-restore:test", "us", 100)
+	operator := NewBackupOperator("mattermost/backup-restore:test", "amazon/aws-cli:test", "us", 100)
 
 	for _, testCase := range []struct {
 		description          string
 		installation         *model.Installation
 		expectedFileStoreURL string
 		extraEnvs            map[string]string
+		filestoreEnvs        map[string]string
 	}{
 		{
 			description:          "s3 installation",
 			installation:         &model.Installation{ID: "installation-1", Filestore: model.InstallationFilestoreAwsS3},
 			expectedFileStoreURL: "filestore.com",
+			filestoreEnvs: map[string]string{
+				"FILESTORE_ENDPOINT":    "https://filestore.com",
+				"FILESTORE_SOURCE":      "s3://plastic/",
+				"FILESTORE_DESTINATION": "s3://plastic/backup-backup-1-filestore/",
+				"FILESTORE_MANIFEST":    "s3://plastic/backup-backup-1-filestore-manifest.txt",
+			},
 		},
 		{
 			description:          "bifrost installation",
@@ -62,6 +69,12 @@ func TestOperator_TriggerBackup(t *testing.T) {
 				"BRT_STORAGE_TLS":         "false",
 				"BRT_STORAGE_TYPE":        "bifrost",
 			},
+			filestoreEnvs: map[string]string{
+				"FILESTORE_ENDPOINT":    "http://bifrost.bifrost:80",
+				"FILESTORE_SOURCE":      "s3://plastic/installation-1/",
+				"FILESTORE_DESTINATION": "s3://plastic/installation-1/backup-backup-1-filestore/",
+				"FILESTORE_MANIFEST":    "s3://plastic/installation-1/backup-backup-1-filestore-manifest.txt",
+			},
 		},
 	} {
 		t.Run(testCase.description, func(t *testing.T) {
@@ -82,6 +95,8 @@ func TestOperator_TriggerBackup(t *testing.T) {
 			assert.Equal(t, "us", dataRes.Region)
 			assert.Equal(t, "plastic", dataRes.Bucket)
 			assert.Equal(t, "backup-backup-1", dataRes.ObjectKey)
+			assert.Equal(t, "backup-backup-1-filestore", dataRes.FilestorePrefix)
+			assert.Equal(t, "backup-backup-1-filestore-manifest.txt", dataRes.FilestoreManifestKey)
 
 			createdJob, err := jobClinet.Get(context.Background(), "database-backup-backup-1", metav1.GetOptions{})
 			require.NoError(t, err)
@@ -111,9 +126,46 @@ func TestOperator_TriggerBackup(t *testing.T) {
 			for k, v := range testCase.extraEnvs {
 				assertEnvVarEqual(t, k, v, envs)
 			}
+
+			require.Len(t, podTemplate.Spec.Containers, 2)
+			filestoreContainer := podTemplate.Spec.Containers[1]
+			assert.Equal(t, "amazon/aws-cli:test", filestoreContainer.Image)
+			assert.Equal(t, []string{filestoreBackupScript}, filestoreContainer.Args)
+
+			filestoreEnvs := filestoreContainer.Env
+			assertEnvVarEqual(t, "AWS_DEFAULT_REGION", "us", filestoreEnvs)
+			assertEnvVarFromSecret(t, "AWS_ACCESS_KEY_ID", fileStoreSecret, "accesskey", filestoreEnvs)
+			assertEnvVarFromSecret(t, "AWS_SECRET_ACCESS_KEY", fileStoreSecret, "secretkey", filestoreEnvs)
+			for k, v := range testCase.filestoreEnvs {
+				assertEnvVarEqual(t, k, v, filestoreEnvs)
+			}
 		})
 	}
 
+	t.Run("do not backup files if filestore image not set", func(t *testing.T) {
+		k8sClient := fake.NewSimpleClientset()
+		jobClinet := k8sClient.BatchV1().Jobs("installation-1")
+		go setJobActiveWhenExists(t, jobClinet, "database-backup-backup-1")
+
+		installation := &model.Installation{ID: "installation-1", Filestore: model.InstallationFilestoreMultiTenantAwsS3}
+
+		operator := NewBackupOperator("image", "", "us", 100)
+
+		dataRes, err := operator.TriggerBackup(
+			jobClinet,
+			backupMeta,
+			installation,
+			fileStoreCfg,
+			databaseSecret,
+			logrus.New())
+		require.NoError(t, err)
+		assert.False(t, dataRes.HasFilestoreSnapshot())
+
+		createdJob, err := jobClinet.Get(context.Background(), "database-backup-backup-1", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Len(t, createdJob.Spec.Template.Spec.Containers, 1)
+	})
+
 	t.Run("succeed if job already exists", func(t *testing.T) {
 		existing := &batchv1.Job{
 			ObjectMeta: metav1.ObjectMeta{Name: "database-backup-backup-1", Namespace: "installation-1"},
@@ -146,7 +198,7 @@ func TestOperator_TriggerBackup(t *testing.T) {
 
 		installation := &model.Installation{ID: "installation-1", Filestore: model.InstallationFilestoreMultiTenantAwsS3}
 
-		operator := NewBackupOperator("image", "us", -1)
+		operator := NewBackupOperator("image", "", "us", -1)
 
 		_, err := operator.TriggerBackup(
 			jobClinet,
@@ -189,7 +241,7 @@ func TestOperator_CheckJobStatus(t *testing.T) {
 	k8sClient := fake.NewSimpleClientset()
 	jobClinet := k8sClient.BatchV1().Jobs("installation-1")
 
-	operator := NewBackupOperator("mattermost/backup-restore:test", "us", 0)
+	operator := NewBackupOperator("mattermost/backup-restore:test", "amazon/aws-cli:test", "us", 0)
 
 	startTime := metav1.NewTime(time.Now().Add(time.Minute))
 	endTime := metav1.NewTime(time.Now().Add(time.Hour))
@@ -262,7 +314,7 @@ func TestOperator_CheckJobStatus(t *testing.T) {
 
 // Tests CleanupBackupJob and CleanupRestoreJob as their logic is almost exactly the same
 func TestBackupOperator_CleanupJob(t *testing.T) {
-	operator := NewBackupOperator("mattermost/backup-restore:test", "us", 100)
+	operator := NewBackupOperator("mattermost/backup-restore:test", "amazon/aws-cli:test", "us", 100)
 	backup := &model.InstallationBackup{
 		ID:             "backup-1",
 		InstallationID: "installation-1",
@@ -332,7 +384,7 @@ func TestOperator_TriggerRestore(t *testing.T) {
 		},
 	}
 
-	operator := NewBackupOperator("mattermost/backup-restore:test", "us", 100)
+	operator := NewBackupOperator("mattermost/backup-restore:test", "amazon/aws-cli:test", "us", 100)
 
 	for _, testCase := range []struct {
 		description          string
@@ -401,6 +453,114 @@ func TestOperator_TriggerRestore(t *testing.T) {
 		})
 	}
 
+	t.Run("restore files", func(t *testing.T) {
+		backupWithFiles := *backupMeta
+		dataRes := *backupMeta.DataResidence
+		dataRes.FilestorePrefix = "backup-backup-rest-1-filestore"
+		dataRes.FilestoreManifestKey = "backup-backup-rest-1-filestore-manifest.txt"
+		backupWithFiles.DataResidence = &dataRes
+
+		fileStoreCfg := &model.FilestoreConfig{
+			URL:    "s3.amazonaws.com",
+			Bucket: "metal",
+			Secret: fileStoreSecret,
+		}
+		installation := &model.Installation{ID: "installation-1", Filestore: model.InstallationFilestoreMultiTenantAwsS3}
+
+		t.Run("fail if filestore image not set", func(t *testing.T) {
+			k8sClient := fake.NewSimpleClientset()
+			jobClinet := k8sClient.BatchV1().Jobs("installation-1")
+
+			operator := NewBackupOperator("image", "", "us", 100)
+
+			err := operator.TriggerRestore(
+				jobClinet,
+				&backupWithFiles,
+				installation,
+				fileStoreCfg,
+				databaseSecret,
+				logrus.New())
+			require.Error(t, err)
+		})
+
+		k8sClient := fake.NewSimpleClientset()
+		jobClinet := k8sClient.BatchV1().Jobs("installation-1")
+		go setJobActiveWhenExists(t, jobClinet, "database-restore-backup-rest-1")
+
+		err := operator.TriggerRestore(
+			jobClinet,
+			&backupWithFiles,
+			installation,
+			fileStoreCfg,
+			databaseSecret,
+			logrus.New())
+		require.NoError(t, err)
+
+		createdJob, err := jobClinet.Get(context.Background(), "database-restore-backup-rest-1", metav1.GetOptions{})
+		require.NoError(t, err)
+
+		containers := createdJob.Spec.Template.Spec.Containers
+		require.Len(t, containers, 2)
+		assert.Equal(t, "amazon/aws-cli:test", containers[1].Image)
+		assert.Equal(t, []string{filestoreRestoreScript}, containers[1].Args)
+
+		envs := containers[1].Env
+		assertEnvVarEqual(t, "AWS_DEFAULT_REGION", "us-east", envs)
+		assertEnvVarEqual(t, "FILESTORE_ENDPOINT", "", envs)
+		assertEnvVarEqual(t, "FILESTORE_SOURCE", "s3://plastic/my-deer/backup-backup-rest-1-filestore/", envs)
+		assertEnvVarEqual(t, "FILESTORE_DESTINATION", "s3://metal/installation-1/", envs)
+		assertEnvVarEqual(t, "FILESTORE_MANIFEST", "s3://plastic/my-deer/backup-backup-rest-1-filestore-manifest.txt", envs)
+		assertEnvVarFromSecret(t, "AWS_ACCESS_KEY_ID", fileStoreSecret, "accesskey", envs)
+		assertEnvVarFromSecret(t, "AWS_SECRET_ACCESS_KEY", fileStoreSecret, "secretkey", envs)
+	})
+
+	t.Run("restore backup of another installation", func(t *testing.T) {
+		backupWithFiles := *backupMeta
+		dataRes := *backupMeta.DataResidence
+		dataRes.FilestorePrefix = "backup-backup-rest-1-filestore"
+		dataRes.FilestoreManifestKey = "backup-backup-rest-1-filestore-manifest.txt"
+		backupWithFiles.DataResidence = &dataRes
+
+		fileStoreCfg := &model.FilestoreConfig{
+			URL:    "s3.amazonaws.com",
+			Bucket: "metal",
+			Secret: fileStoreSecret,
+		}
+		installation := &model.Installation{ID: "installation-2", Filestore: model.InstallationFilestoreMultiTenantAwsS3}
+
+		k8sClient := fake.NewSimpleClientset()
+		jobClinet := k8sClient.BatchV1().Jobs("installation-2")
+		go setJobActiveWhenExists(t, jobClinet, "database-restore-backup-rest-1")
+
+		err := operator.TriggerRestore(
+			jobClinet,
+			&backupWithFiles,
+			installation,
+			fileStoreCfg,
+			databaseSecret,
+			logrus.New())
+		require.NoError(t, err)
+
+		createdJob, err := jobClinet.Get(context.Background(), "database-restore-backup-rest-1", metav1.GetOptions{})
+		require.NoError(t, err)
+
+		// The backup is read from its copy in the file store of the restored
+		// installation, which its credentials have access to.
+		containers := createdJob.Spec.Template.Spec.Containers
+		require.Len(t, containers, 2)
+		envs := containers[0].Env
+		assertEnvVarEqual(t, "BRT_STORAGE_BUCKET", "metal", envs)
+		assertEnvVarEqual(t, "BRT_STORAGE_ENDPOINT", "s3.amazonaws.com", envs)
+		assertEnvVarEqual(t, "BRT_STORAGE_PATH_PREFIX", "installation-2", envs)
+		assertEnvVarEqual(t, "BRT_STORAGE_OBJECT_KEY", "backup-backup-rest-1", envs)
+		assertEnvVarFromSecret(t, "BRT_STORAGE_ACCESS_KEY", fileStoreSecret, "accesskey", envs)
+
+		envs = containers[1].Env
+		assertEnvVarEqual(t, "FILESTORE_SOURCE", "s3://metal/installation-2/backup-backup-rest-1-filestore/", envs)
+		assertEnvVarEqual(t, "FILESTORE_DESTINATION", "s3://metal/installation-2/", envs)
+		assertEnvVarEqual(t, "FILESTORE_MANIFEST", "s3://metal/installation-2/backup-backup-rest-1-filestore-manifest.txt", envs)
+	})
+
 	t.Run("succeed if job already exists", func(t *testing.T) {
 		existing := &batchv1.Job{
 			ObjectMeta: metav1.ObjectMeta{Name: "database-restore-backup-rest-1", Namespace: "installation-1"},
@@ -428,7 +588,7 @@ func TestOperator_TriggerRestore(t *testing.T) {
 
 		installation := &model.Installation{ID: "installation-1", Filestore: model.InstallationFilestoreMultiTenantAwsS3}
 
-		operator := NewBackupOperator("image", "us", -1)
+		operator := NewBackupOperator("image", "", "us", -1)
 
 		err := operator.TriggerRestore(
 			jobClinet,
@@ -460,6 +620,42 @@ func TestOperator_TriggerRestore(t *testing.T) {
 	})
 }
 
+func TestRestoreDataResidence(t *testing.T) {
+	backup := &model.InstallationBackup{
+		ID:             "backup-1",
+		InstallationID: "installation-1",
+		DataResidence: &model.S3DataResidence{
+			Region:     "us-east",
+			URL:        "s3.amazonaws.com",
+			Bucket:     "plastic",
+			PathPrefix: "installation-1",
+			ObjectKey:  "backup-backup-1",
+		},
+	}
+	fileStoreCfg := &model.FilestoreConfig{
+		URL:    "s3.us-east-1.amazonaws.com",
+		Bucket: "metal",
+	}
+
+	t.Run("same installation", func(t *testing.T) {
+		installation := &model.Installation{ID: "installation-1", Filestore: model.InstallationFilestoreBifrost}
+
+		assert.Equal(t, *backup.DataResidence, RestoreDataResidence(backup, installation, fileStoreCfg))
+	})
+
+	t.Run("another installation", func(t *testing.T) {
+		installation := &model.Installation{ID: "installation-2", Filestore: model.InstallationFilestoreBifrost}
+
+		assert.Equal(t, model.S3DataResidence{
+			Region:     "us-east",
+			URL:        "s3.us-east-1.amazonaws.com",
+			Bucket:     "metal",
+			PathPrefix: "installation-2",
+			ObjectKey:  "backup-backup-1",
+		}, RestoreDataResidence(backup, installation, fileStoreCfg))
+	})
+}
+
 func setJobActiveWhenExists(t *testing.T, client v1.JobInterface, name string) {
 	ctx := context.Background()
 	err := wait.Poll(1*time.Second, 30*time.Second, func() (bool, error) {
@@ -9,6 +9,8 @@ import (
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
+	"github.com/mattermost/mattermost-cloud/internal/tools/cloud"
 	"github.com/mattermost/mattermost-cloud/internal/tools/kops"
 	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
 	"github.com/mattermost/mattermost-cloud/model"
@@ -27,6 +29,8 @@ type ProvisioningParams struct {
 type KopsProvisioner struct {
 	params         ProvisioningParams
 	resourceUtil   *utils.ResourceUtil
+	awsClient      aws.AWS
+	cloud          cloud.Provider
 	logger         log.FieldLogger
 	store          model.InstallationDatabaseStoreInterface
 	backupOperator *BackupOperator
@@ -37,6 +41,8 @@ type KopsProvisioner struct {
 func NewKopsProvisioner(
 	provisioningParams ProvisioningParams,
 	resourceUtil *utils.ResourceUtil,
+	awsClient aws.AWS,
+	cloudProvider cloud.Provider,
 	logger log.FieldLogger,
 	store model.InstallationDatabaseStoreInterface,
 	backupOperator *BackupOperator) *KopsProvisioner {
@@ -46,6 +52,8 @@ func NewKopsProvisioner(
 		params:         provisioningParams,
 		logger:         logger,
 		resourceUtil:   resourceUtil,
+		awsClient:      awsClient,
+		cloud:          cloudProvider,
 		store:          store,
 		backupOperator: backupOperator,
 		kopsCache:      make(map[string]*kops.Cmd),
@@ -61,10 +69,20 @@ func (provisioner *KopsProvisioner) Teardown() {
 	}
 }
 
+// kopsCacheKey returns the key under which the kops client of a cluster is
+// cached. Local clusters have no kops name, so their ID is used instead.
+func kopsCacheKey(cluster *model.Cluster) string {
+	if cluster.Provisioner == model.ProvisionerLocal {
+		return cluster.ID
+	}
+
+	return cluster.ProvisionerMetadataKops.Name
+}
+
 // getKopsClusterConfigLocationFromCache returns the cached kubecfg for a k8s
 // cluster. If the config is not cached, it is fetched with kops.
-func (provisioner *KopsProvisioner) getCachedKopsClusterKubecfg(name string, logger log.FieldLogger) (string, error) {
-	kopsClient, err := provisioner.getCachedKopsClient(name, logger)
+func (provisioner *KopsProvisioner) getCachedKopsClusterKubecfg(cluster *model.Cluster, logger log.FieldLogger) (string, error) {
+	kopsClient, err := provisioner.getCachedKopsClient(cluster, logger)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to get cached kops client")
 	}
@@ -72,7 +90,8 @@ func (provisioner *KopsProvisioner) getCachedKopsClusterKubecfg(name string, log
 	return kopsClient.GetKubeConfigPath(), nil
 }
 
-func (provisioner *KopsProvisioner) getCachedKopsClient(name string, logger log.FieldLogger) (*kops.Cmd, error) {
+func (provisioner *KopsProvisioner) getCachedKopsClient(cluster *model.Cluster, logger log.FieldLogger) (*kops.Cmd, error) {
+	name := kopsCacheKey(cluster)
 	if kopsClient, ok := provisioner.kopsCache[name]; ok {
 		logger.Debugf("Using cached kops client for %s", name)
 		kopsClient.SetLogger(logger)
@@ -80,18 +99,38 @@ func (provisioner *KopsProvisioner) getCachedKopsClient(name string, logger log.
 	}
 
 	logger.Debugf("Building kops client cache for %s", name)
+	kopsClient, err := provisioner.newKopsClient(cluster, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	provisioner.kopsCache[name] = kopsClient
+	logger.Debugf("Kops config cached at %s for %s", kopsClient.GetKubeConfigPath(), name)
+
+	return kopsClient, nil
+}
+
+// newKopsClient creates a kops client holding the kubecfg of the given
+// cluster. Local clusters are registered with an existing kubecfg, which is
+// used as is.
+func (provisioner *KopsProvisioner) newKopsClient(cluster *model.Cluster, logger log.FieldLogger) (*kops.Cmd, error) {
+	if cluster.Provisioner == model.ProvisionerLocal {
+		kopsClient, err := kops.NewFromKubeconfig(cluster.ProvisionerMetadataLocal.KubeconfigPath, logger)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load kubecfg of local cluster")
+		}
+		return kopsClient, nil
+	}
+
 	kopsClient, err := kops.New(provisioner.params.S3StateStore, logger)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create kops wrapper")
 	}
-	err = kopsClient.ExportKubecfg(name)
+	err = kopsClient.ExportKubecfg(cluster.ProvisionerMetadataKops.Name)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to export kubecfg")
 	}
 
-	provisioner.kopsCache[name] = kopsClient
-	logger.Debugf("Kops config cached at %s for %s", kopsClient.GetKubeConfigPath(), name)
-
 	return kopsClient, nil
 }
 
@@ -112,21 +151,21 @@ func (provisioner *KopsProvisioner) invalidateCachedKopsClient(name string, logg
 // invalidateCachedKopsClientOnError can be used to invalidate cache when the
 // provided error is not nil. This can be used with defer to perform cache
 // cleanup if an error is encountered that may have been due to a bad cached config.
-func (provisioner *KopsProvisioner) invalidateCachedKopsClientOnError(err error, name string, logger log.FieldLogger) {
+func (provisioner *KopsProvisioner) invalidateCachedKopsClientOnError(err error, cluster *model.Cluster, logger log.FieldLogger) {
 	if err == nil {
 		return
 	}
 
-	provisioner.invalidateCachedKopsClient(name, logger)
+	provisioner.invalidateCachedKopsClient(kopsCacheKey(cluster), logger)
 }
 
-func (provisioner *KopsProvisioner) k8sClient(clusterName string, logger log.FieldLogger) (*k8s.KubeClient, func(err error), error) {
-	configLocation, err := provisioner.getCachedKopsClusterKubecfg(clusterName, logger)
+func (provisioner *KopsProvisioner) k8sClient(cluster *model.Cluster, logger log.FieldLogger) (*k8s.KubeClient, func(err error), error) {
+	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster, logger)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "failed to get kops config from cache")
 	}
 	invalidateOnError := func(err error) {
-		provisioner.invalidateCachedKopsClientOnError(err, clusterName, logger)
+		provisioner.invalidateCachedKopsClientOnError(err, cluster, logger)
 	}
 	defer invalidateOnError(err)
 
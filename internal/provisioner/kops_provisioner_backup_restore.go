@@ -19,7 +19,7 @@ func (provisioner *KopsProvisioner) TriggerBackup(backup *model.InstallationBack
 	})
 	logger.Info("Triggering backup for installation")
 
-	k8sClient, invalidateCache, err := provisioner.k8sClient(cluster.ProvisionerMetadataKops.Name, logger)
+	k8sClient, invalidateCache, err := provisioner.k8sClient(cluster, logger)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create k8s client")
 	}
@@ -58,7 +58,7 @@ func (provisioner *KopsProvisioner) CheckBackupStatus(backup *model.Installation
 	})
 	logger.Info("Checking backup status for installation")
 
-	k8sClient, invalidateCache, err := provisioner.k8sClient(cluster.ProvisionerMetadataKops.Name, logger)
+	k8sClient, invalidateCache, err := provisioner.k8sClient(cluster, logger)
 	if err != nil {
 		return -1, errors.Wrap(err, "failed to create k8s client")
 	}
@@ -78,7 +78,7 @@ func (provisioner *KopsProvisioner) CleanupBackupJob(backup *model.InstallationB
 	})
 	logger.Info("Cleaning up backup job for installation")
 
-	k8sClient, invalidateCache, err := provisioner.k8sClient(cluster.ProvisionerMetadataKops.Name, logger)
+	k8sClient, invalidateCache, err := provisioner.k8sClient(cluster, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to create k8s client")
 	}
@@ -98,7 +98,7 @@ func (provisioner *KopsProvisioner) TriggerRestore(installation *model.Installat
 	})
 	logger.Info("Triggering restoration for installation")
 
-	k8sClient, invalidateCache, err := provisioner.k8sClient(cluster.ProvisionerMetadataKops.Name, logger)
+	k8sClient, invalidateCache, err := provisioner.k8sClient(cluster, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to create k8s client")
 	}
@@ -122,48 +122,63 @@ func (provisioner *KopsProvisioner) TriggerRestore(installation *model.Installat
 		return errors.New("database secret cannot be empty for database restoration")
 	}
 
+	if backup.DataResidence != nil && backup.InstallationID != installation.ID {
+		logger.Info("Copying backup of another installation to the installation file store")
+		restoreResidence := RestoreDataResidence(backup, installation, filestoreCfg)
+		err = provisioner.awsClient.S3CopyBucketDirectory(
+			backup.DataResidence.Bucket,
+			backup.DataResidence.FullPath(),
+			restoreResidence.Bucket,
+			restoreResidence.FullPath(),
+			logger,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to copy backup to the installation file store")
+		}
+	}
+
 	jobsClient := k8sClient.Clientset.BatchV1().Jobs(installation.ID)
 
 	return provisioner.backupOperator.TriggerRestore(jobsClient, backup, installation, filestoreCfg, dbSecret.Name, logger)
 }
 
-// CheckRestoreStatus checks status of running backup job,
+// CheckRestoreStatus checks status of running restore job of the installation,
 // returns job completion time, when the job finished or -1 if it is still running.
-func (provisioner *KopsProvisioner) CheckRestoreStatus(backup *model.InstallationBackup, cluster *model.Cluster) (int64, error) {
+func (provisioner *KopsProvisioner) CheckRestoreStatus(installation *model.Installation, backup *model.InstallationBackup, cluster *model.Cluster) (int64, error) {
 	logger := provisioner.logger.WithFields(log.Fields{
 		"cluster":      cluster.ID,
-		"installation": backup.InstallationID,
+		"installation": installation.ID,
 		"backup":       backup.ID,
 	})
 	logger.Info("Checking restoration status for installation")
 
-	k8sClient, invalidateCache, err := provisioner.k8sClient(cluster.ProvisionerMetadataKops.Name, logger)
+	k8sClient, invalidateCache, err := provisioner.k8sClient(cluster, logger)
 	if err != nil {
 		return -1, errors.Wrap(err, "failed to create k8s client")
 	}
 	defer invalidateCache(err)
 
-	jobsClient := k8sClient.Clientset.BatchV1().Jobs(backup.InstallationID)
+	jobsClient := k8sClient.Clientset.BatchV1().Jobs(installation.ID)
 
 	return provisioner.backupOperator.CheckRestoreStatus(jobsClient, backup, logger)
 }
 
 // CleanupRestoreJob deletes restore job from the cluster if it exists.
-func (provisioner *KopsProvisioner) CleanupRestoreJob(backup *model.InstallationBackup, cluster *model.Cluster) error {
+func (provisioner *KopsProvisioner) CleanupRestoreJob(installation *model.Installation, backup *model.InstallationBackup, cluster *model.Cluster) error {
 	logger := provisioner.logger.WithFields(log.Fields{
 		"cluster":      cluster.ID,
-		"installation": backup.InstallationID,
+		"installation": installation.ID,
 		"backup":       backup.ID,
 	})
 	logger.Info("Cleaning up restoration job for installation")
 
-	k8sClient, invalidateCache, err := provisioner.k8sClient(cluster.ProvisionerMetadataKops.Name, logger)
+	k8sClient, invalidateCache, err := provisioner.k8sClient(cluster, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to create k8s client")
 	}
 	defer invalidateCache(err)
 
-	jobsClient := k8sClient.Clientset.BatchV1().Jobs(backup.InstallationID)
+	jobsClient := k8sClient.Clientset.BatchV1().Jobs(installation.ID)
 
 	return provisioner.backupOperator.CleanupRestoreJob(jobsClient, backup, logger)
 }
@@ -19,6 +19,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
+	"github.com/mattermost/mattermost-cloud/internal/tools/cloud"
 	"github.com/mattermost/mattermost-cloud/internal/tools/kops"
 	"github.com/mattermost/mattermost-cloud/internal/tools/terraform"
 	"github.com/mattermost/mattermost-cloud/k8s"
@@ -48,7 +49,7 @@ func (provisioner *KopsProvisioner) PrepareCluster(cluster *model.Cluster) bool
 }
 
 // CreateCluster creates a cluster using kops and terraform.
-func (provisioner *KopsProvisioner) CreateCluster(cluster *model.Cluster, awsClient aws.AWS) error {
+func (provisioner *KopsProvisioner) CreateCluster(cluster *model.Cluster) error {
 	logger := provisioner.logger.WithField("cluster", cluster.ID)
 
 	kopsMetadata := cluster.ProvisionerMetadataKops
@@ -59,7 +60,7 @@ func (provisioner *KopsProvisioner) CreateCluster(cluster *model.Cluster, awsCli
 	}
 
 	if kopsMetadata.ChangeRequest.AMI != "" && kopsMetadata.ChangeRequest.AMI != "latest" {
-		isAMIValid, err := awsClient.IsValidAMI(kopsMetadata.ChangeRequest.AMI, logger)
+		isAMIValid, err := provisioner.awsClient.IsValidAMI(kopsMetadata.ChangeRequest.AMI, logger)
 		if err != nil {
 			return errors.Wrapf(err, "error checking the AWS AMI image %s", kopsMetadata.ChangeRequest.AMI)
 		}
@@ -68,8 +69,8 @@ func (provisioner *KopsProvisioner) CreateCluster(cluster *model.Cluster, awsCli
 		}
 	}
 
-	cncVPCName := fmt.Sprintf("mattermost-cloud-%s-command-control", awsClient.GetCloudEnvironmentName())
-	cncVPCCIDR, err := awsClient.GetCIDRByVPCTag(cncVPCName, logger)
+	cncVPCName := fmt.Sprintf("mattermost-cloud-%s-command-control", provisioner.awsClient.GetCloudEnvironmentName())
+	cncVPCCIDR, err := provisioner.cloud.GetNetworkCIDRByName(cncVPCName, logger)
 	if err != nil {
 		return errors.Wrapf(err, "failed to get the CIDR for the VPC Name %s", cncVPCName)
 	}
@@ -83,14 +84,14 @@ func (provisioner *KopsProvisioner) CreateCluster(cluster *model.Cluster, awsCli
 	}
 	defer kops.Close()
 
-	var clusterResources aws.ClusterResources
+	network := &cloud.ClusterNetwork{}
 	if kopsMetadata.ChangeRequest.VPC != "" && provisioner.params.UseExistingAWSResources {
-		clusterResources, err = awsClient.GetVpcResourcesByVpcID(kopsMetadata.ChangeRequest.VPC, logger)
+		network, err = provisioner.cloud.GetNetwork(kopsMetadata.ChangeRequest.VPC, logger)
 		if err != nil {
 			return err
 		}
 	} else if provisioner.params.UseExistingAWSResources {
-		clusterResources, err = awsClient.GetAndClaimVpcResources(cluster.ID, provisioner.params.Owner, logger)
+		network, err = provisioner.cloud.ClaimClusterNetwork(cluster.ID, provisioner.params.Owner, logger)
 		if err != nil {
 			return err
 		}
@@ -101,15 +102,15 @@ func (provisioner *KopsProvisioner) CreateCluster(cluster *model.Cluster, awsCli
 		cluster.Provider,
 		kopsMetadata.ChangeRequest,
 		cluster.ProviderMetadataAWS.Zones,
-		clusterResources.PrivateSubnetIDs,
-		clusterResources.PublicSubnetsIDs,
-		clusterResources.MasterSecurityGroupIDs,
-		clusterResources.WorkerSecurityGroupIDs,
+		network.PrivateSubnetIDs,
+		network.PublicSubnetIDs,
+		network.MasterSecurityGroupIDs,
+		network.WorkerSecurityGroupIDs,
 		allowSSHCIDRS,
 	)
 	// release VPC resources
 	if err != nil {
-		releaseErr := awsClient.ReleaseVpc(cluster.ID, logger)
+		releaseErr := provisioner.cloud.ReleaseClusterNetwork(cluster.ID, logger)
 		if releaseErr != nil {
 			logger.WithError(releaseErr).Error("Unable to release VPC")
 		}
@@ -118,7 +119,7 @@ func (provisioner *KopsProvisioner) CreateCluster(cluster *model.Cluster, awsCli
 	}
 	// Tag Public subnets & respective VPC for the secondary cluster if there is no error.
 	if kopsMetadata.ChangeRequest.VPC != "" {
-		err = awsClient.TagResourcesByCluster(clusterResources, cluster.ID, provisioner.params.Owner, logger)
+		err = provisioner.cloud.TagClusterNetwork(network, cluster.ID, provisioner.params.Owner, logger)
 		if err != nil {
 			return err
 		}
@@ -193,12 +194,12 @@ func (provisioner *KopsProvisioner) CreateCluster(cluster *model.Cluster, awsCli
 	logger.WithField("name", kopsMetadata.Name).Info("Successfully updated storage class")
 
 	iamRole := fmt.Sprintf("nodes.%s", kopsMetadata.Name)
-	err = awsClient.AttachPolicyToRole(iamRole, aws.CustomNodePolicyName, logger)
+	err = provisioner.awsClient.AttachPolicyToRole(iamRole, aws.CustomNodePolicyName, logger)
 	if err != nil {
 		return errors.Wrap(err, "unable to attach custom node policy")
 	}
 
-	ugh, err := newUtilityGroupHandle(kops, provisioner, cluster, awsClient, logger)
+	ugh, err := newUtilityGroupHandle(kops, provisioner, cluster, provisioner.awsClient, logger)
 	if err != nil {
 		return err
 	}
@@ -209,22 +210,29 @@ func (provisioner *KopsProvisioner) CreateCluster(cluster *model.Cluster, awsCli
 // ProvisionCluster installs all the baseline kubernetes resources needed for
 // managing installations. This can be called on an already-provisioned cluster
 // to reprovision with the newest version of the resources.
-func (provisioner *KopsProvisioner) ProvisionCluster(cluster *model.Cluster, awsClient aws.AWS) error {
+func (provisioner *KopsProvisioner) ProvisionCluster(cluster *model.Cluster) error {
 	logger := provisioner.logger.WithField("cluster", cluster.ID)
 
 	logger.Info("Provisioning cluster")
 
-	kopsClient, err := provisioner.getCachedKopsClient(cluster.ProvisionerMetadataKops.Name, logger)
+	kopsClient, err := provisioner.getCachedKopsClient(cluster, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to get kops client from cache")
 	}
-	defer provisioner.invalidateCachedKopsClientOnError(err, cluster.ProvisionerMetadataKops.Name, logger)
+	defer provisioner.invalidateCachedKopsClientOnError(err, cluster, logger)
+
+	// Local clusters are not running in AWS, so the AWS specific resources
+	// such as bifrost, calico and the spot termination handler are skipped.
+	local := cluster.Provisioner == model.ProvisionerLocal
 
 	// Start by gathering resources that will be needed later. If any of this
 	// fails then no cluster changes have been made which reduces risk.
-	bifrostSecret, err := awsClient.GenerateBifrostUtilitySecret(cluster.ID, logger)
-	if err != nil {
-		return errors.Wrap(err, "failed to generate bifrost secret")
+	var bifrostSecret *v1.Secret
+	if !local {
+		bifrostSecret, err = provisioner.awsClient.GenerateBifrostUtilitySecret(cluster.ID, logger)
+		if err != nil {
+			return errors.Wrap(err, "failed to generate bifrost secret")
+		}
 	}
 
 	// Begin deploying the mattermost operator.
@@ -266,17 +274,21 @@ func (provisioner *KopsProvisioner) ProvisionCluster(cluster *model.Cluster, aws
 	// The bifrost utility cannot have downtime so it is not part of the namespace
 	// cleanup and recreation flow. We always only update bifrost.
 	bifrostNamespace := "bifrost"
-	namespaces = append(namespaces, bifrostNamespace)
+	if !local {
+		namespaces = append(namespaces, bifrostNamespace)
+	}
 	logger.Info("Creating utility namespaces")
 	_, err = k8sClient.CreateOrUpdateNamespaces(namespaces)
 	if err != nil {
 		return errors.Wrap(err, "failed to create bifrost namespace")
 	}
 
-	logger.Info("Creating or updating bifrost secret")
-	_, err = k8sClient.CreateOrUpdateSecret(bifrostNamespace, bifrostSecret)
-	if err != nil {
-		return errors.Wrap(err, "failed to create bifrost secret")
+	if !local {
+		logger.Info("Creating or updating bifrost secret")
+		_, err = k8sClient.CreateOrUpdateSecret(bifrostNamespace, bifrostSecret)
+		if err != nil {
+			return errors.Wrap(err, "failed to create bifrost secret")
+		}
 	}
 
 	// Need to remove two items from the calico because the fields after the creation are imutable so the
@@ -343,20 +355,25 @@ func (provisioner *KopsProvisioner) ProvisionCluster(cluster *model.Cluster, aws
 		}, {
 			Path:            "manifests/operator-manifests/mattermost/operator.yaml",
 			DeployNamespace: mattermostOperatorNamespace,
-		}, {
-			Path:            "manifests/bifrost/bifrost.yaml",
-			DeployNamespace: bifrostNamespace,
-		}, {
-			Path:            "manifests/calico-policy-only.yaml",
-			DeployNamespace: "kube-system",
 		}, {
 			Path:            "manifests/metric-server/metric-server.yaml",
 			DeployNamespace: "kube-system",
-		}, {
-			Path:            "manifests/k8s-spot-termination-handler/k8s-spot-termination-handler.yaml",
-			DeployNamespace: "kube-system",
 		},
 	}
+	if !local {
+		files = append(files, []k8s.ManifestFile{
+			{
+				Path:            "manifests/bifrost/bifrost.yaml",
+				DeployNamespace: bifrostNamespace,
+			}, {
+				Path:            "manifests/calico-policy-only.yaml",
+				DeployNamespace: "kube-system",
+			}, {
+				Path:            "manifests/k8s-spot-termination-handler/k8s-spot-termination-handler.yaml",
+				DeployNamespace: "kube-system",
+			},
+		}...)
+	}
 
 	err = k8sClient.CreateFromFiles(files)
 	if err != nil {
@@ -367,11 +384,13 @@ func (provisioner *KopsProvisioner) ProvisionCluster(cluster *model.Cluster, aws
 	// due container download / init / container creation / volume allocation
 	wait = 240
 	appsWithDeployment := map[string]string{
-		"minio-operator":                     minioOperatorNamespace,
-		"mattermost-operator":                mattermostOperatorNamespace,
-		"bifrost":                            bifrostNamespace,
-		"calico-typha-horizontal-autoscaler": "kube-system",
-		"calico-typha":                       "kube-system",
+		"minio-operator":      minioOperatorNamespace,
+		"mattermost-operator": mattermostOperatorNamespace,
+	}
+	if !local {
+		appsWithDeployment["bifrost"] = bifrostNamespace
+		appsWithDeployment["calico-typha-horizontal-autoscaler"] = "kube-system"
+		appsWithDeployment["calico-typha"] = "kube-system"
 	}
 	for deployment, namespace := range appsWithDeployment {
 		pods, err := k8sClient.GetPodsFromDeployment(namespace, deployment)
@@ -417,7 +436,10 @@ func (provisioner *KopsProvisioner) ProvisionCluster(cluster *model.Cluster, aws
 		}
 	}
 
-	supportAppsWithDaemonSets := map[string]string{"calico-node": "kube-system"}
+	supportAppsWithDaemonSets := map[string]string{}
+	if !local {
+		supportAppsWithDaemonSets["calico-node"] = "kube-system"
+	}
 	for daemonSet, namespace := range supportAppsWithDaemonSets {
 
 		pods, err := k8sClient.GetPodsFromDaemonSet(namespace, daemonSet)
@@ -440,13 +462,15 @@ func (provisioner *KopsProvisioner) ProvisionCluster(cluster *model.Cluster, aws
 		}
 	}
 
-	iamRole := fmt.Sprintf("nodes.%s", cluster.ProvisionerMetadataKops.Name)
-	err = awsClient.AttachPolicyToRole(iamRole, aws.CustomNodePolicyName, logger)
-	if err != nil {
-		return errors.Wrap(err, "unable to attach custom node policy")
+	if !local {
+		iamRole := fmt.Sprintf("nodes.%s", cluster.ProvisionerMetadataKops.Name)
+		err = provisioner.awsClient.AttachPolicyToRole(iamRole, aws.CustomNodePolicyName, logger)
+		if err != nil {
+			return errors.Wrap(err, "unable to attach custom node policy")
+		}
 	}
 
-	ugh, err := newUtilityGroupHandle(kopsClient, provisioner, cluster, awsClient, logger)
+	ugh, err := newUtilityGroupHandle(kopsClient, provisioner, cluster, provisioner.awsClient, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to create new cluster utility group handle")
 	}
@@ -456,13 +480,13 @@ func (provisioner *KopsProvisioner) ProvisionCluster(cluster *model.Cluster, aws
 		return errors.Wrap(err, "failed to upgrade all services in utility group")
 	}
 
-	logger.WithField("name", cluster.ProvisionerMetadataKops.Name).Info("Successfully provisioned cluster")
+	logger.WithField("name", kopsCacheKey(cluster)).Info("Successfully provisioned cluster")
 
 	return nil
 }
 
 // UpgradeCluster upgrades a cluster to the latest recommended production ready k8s version.
-func (provisioner *KopsProvisioner) UpgradeCluster(cluster *model.Cluster, awsClient aws.AWS) error {
+func (provisioner *KopsProvisioner) UpgradeCluster(cluster *model.Cluster) error {
 	logger := provisioner.logger.WithField("cluster", cluster.ID)
 
 	kopsMetadata := cluster.ProvisionerMetadataKops
@@ -473,7 +497,7 @@ func (provisioner *KopsProvisioner) UpgradeCluster(cluster *model.Cluster, awsCl
 	}
 
 	if kopsMetadata.ChangeRequest.AMI != "" && kopsMetadata.ChangeRequest.AMI != "latest" {
-		isAMIValid, err := awsClient.IsValidAMI(kopsMetadata.ChangeRequest.AMI, logger)
+		isAMIValid, err := provisioner.awsClient.IsValidAMI(kopsMetadata.ChangeRequest.AMI, logger)
 		if err != nil {
 			return errors.Wrapf(err, "error checking the AWS AMI image %s", kopsMetadata.ChangeRequest.AMI)
 		}
@@ -586,7 +610,7 @@ func (provisioner *KopsProvisioner) UpgradeCluster(cluster *model.Cluster, awsCl
 	}
 
 	iamRole := fmt.Sprintf("nodes.%s", kopsMetadata.Name)
-	err = awsClient.AttachPolicyToRole(iamRole, aws.CustomNodePolicyName, logger)
+	err = provisioner.awsClient.AttachPolicyToRole(iamRole, aws.CustomNodePolicyName, logger)
 	if err != nil {
 		return errors.Wrap(err, "unable to attach custom node policy")
 	}
@@ -600,11 +624,11 @@ func (provisioner *KopsProvisioner) UpgradeCluster(cluster *model.Cluster, awsCl
 func (provisioner *KopsProvisioner) RotateClusterNodes(cluster *model.Cluster) error {
 	logger := provisioner.logger.WithField("cluster", cluster.ID)
 
-	kopsClient, err := provisioner.getCachedKopsClient(cluster.ProvisionerMetadataKops.Name, logger)
+	kopsClient, err := provisioner.getCachedKopsClient(cluster, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to get kops client from cache")
 	}
-	defer provisioner.invalidateCachedKopsClientOnError(err, cluster.ProvisionerMetadataKops.Name, logger)
+	defer provisioner.invalidateCachedKopsClientOnError(err, cluster, logger)
 
 	k8sClient, err := k8s.NewFromFile(kopsClient.GetKubeConfigPath(), logger)
 	if err != nil {
@@ -638,7 +662,7 @@ func (provisioner *KopsProvisioner) RotateClusterNodes(cluster *model.Cluster) e
 }
 
 // ResizeCluster resizes a cluster.
-func (provisioner *KopsProvisioner) ResizeCluster(cluster *model.Cluster, awsClient aws.AWS) error {
+func (provisioner *KopsProvisioner) ResizeCluster(cluster *model.Cluster) error {
 	logger := provisioner.logger.WithField("cluster", cluster.ID)
 
 	kopsMetadata := cluster.ProvisionerMetadataKops
@@ -738,7 +762,7 @@ func (provisioner *KopsProvisioner) ResizeCluster(cluster *model.Cluster, awsCli
 	}
 
 	iamRole := fmt.Sprintf("nodes.%s", kopsMetadata.Name)
-	err = awsClient.AttachPolicyToRole(iamRole, aws.CustomNodePolicyName, logger)
+	err = provisioner.awsClient.AttachPolicyToRole(iamRole, aws.CustomNodePolicyName, logger)
 	if err != nil {
 		return errors.Wrap(err, "unable to attach custom node policy")
 	}
@@ -749,7 +773,7 @@ func (provisioner *KopsProvisioner) ResizeCluster(cluster *model.Cluster, awsCli
 }
 
 // DeleteCluster deletes a previously created cluster using kops and terraform.
-func (provisioner *KopsProvisioner) DeleteCluster(cluster *model.Cluster, awsClient aws.AWS) error {
+func (provisioner *KopsProvisioner) DeleteCluster(cluster *model.Cluster) error {
 	logger := provisioner.logger.WithField("cluster", cluster.ID)
 
 	kopsMetadata := cluster.ProvisionerMetadataKops
@@ -759,13 +783,13 @@ func (provisioner *KopsProvisioner) DeleteCluster(cluster *model.Cluster, awsCli
 
 	logger.Info("Deleting cluster")
 
-	kopsClient, err := provisioner.getCachedKopsClient(cluster.ProvisionerMetadataKops.Name, logger)
+	kopsClient, err := provisioner.getCachedKopsClient(cluster, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to get kops client from cache")
 	}
-	defer provisioner.invalidateCachedKopsClientOnError(err, cluster.ProvisionerMetadataKops.Name, logger)
+	defer provisioner.invalidateCachedKopsClientOnError(err, cluster, logger)
 
-	ugh, err := newUtilityGroupHandle(kopsClient, provisioner, cluster, awsClient, logger)
+	ugh, err := newUtilityGroupHandle(kopsClient, provisioner, cluster, provisioner.awsClient, logger)
 	if err != nil {
 		return errors.Wrap(err, "couldn't create new utility group handle while deleting the cluster")
 	}
@@ -775,8 +799,16 @@ func (provisioner *KopsProvisioner) DeleteCluster(cluster *model.Cluster, awsCli
 		return errors.Wrap(err, "failed to destroy all services in the utility group")
 	}
 
+	// Local clusters are owned by whoever registered them, so only the
+	// resources installed by the provisioner are removed.
+	if cluster.Provisioner == model.ProvisionerLocal {
+		provisioner.invalidateCachedKopsClient(kopsCacheKey(cluster), logger)
+		logger.Info("Successfully deregistered local cluster")
+		return nil
+	}
+
 	iamRole := fmt.Sprintf("nodes.%s", kopsMetadata.Name)
-	err = awsClient.DetachPolicyFromRole(iamRole, aws.CustomNodePolicyName, logger)
+	err = provisioner.awsClient.DetachPolicyFromRole(iamRole, aws.CustomNodePolicyName, logger)
 	if err != nil {
 		return errors.Wrap(err, "unable to detach custom node policy")
 	}
@@ -826,12 +858,12 @@ func (provisioner *KopsProvisioner) DeleteCluster(cluster *model.Cluster, awsCli
 		}
 	}
 
-	err = awsClient.ReleaseVpc(cluster.ID, logger)
+	err = provisioner.cloud.ReleaseClusterNetwork(cluster.ID, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to release cluster VPC")
 	}
 
-	provisioner.invalidateCachedKopsClient(cluster.ProvisionerMetadataKops.Name, logger)
+	provisioner.invalidateCachedKopsClient(kopsCacheKey(cluster), logger)
 
 	logger.Info("Successfully deleted cluster")
 
@@ -842,11 +874,11 @@ func (provisioner *KopsProvisioner) DeleteCluster(cluster *model.Cluster, awsCli
 func (provisioner *KopsProvisioner) GetClusterResources(cluster *model.Cluster, onlySchedulable bool) (*k8s.ClusterResources, error) {
 	logger := provisioner.logger.WithField("cluster", cluster.ID)
 
-	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster.ProvisionerMetadataKops.Name, logger)
+	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster, logger)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get kops config from cache")
 	}
-	defer provisioner.invalidateCachedKopsClientOnError(err, cluster.ProvisionerMetadataKops.Name, logger)
+	defer provisioner.invalidateCachedKopsClientOnError(err, cluster, logger)
 
 	k8sClient, err := k8s.NewFromFile(configLocation, logger)
 	if err != nil {
@@ -860,7 +892,7 @@ func (provisioner *KopsProvisioner) GetClusterResources(cluster *model.Cluster,
 	}
 	usedCPU, usedMemory := k8s.CalculateTotalPodMilliResourceRequests(allPods)
 
-	var totalCPU, totalMemory int64
+	var totalCPU, totalMemory, nodeCount int64
 	nodes, err := k8sClient.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, err
@@ -890,6 +922,7 @@ func (provisioner *KopsProvisioner) GetClusterResources(cluster *model.Cluster,
 		if !skipNode {
 			totalCPU += node.Status.Allocatable.Cpu().MilliValue()
 			totalMemory += node.Status.Allocatable.Memory().MilliValue()
+			nodeCount++
 		}
 	}
 
@@ -898,6 +931,7 @@ func (provisioner *KopsProvisioner) GetClusterResources(cluster *model.Cluster,
 		MilliUsedCPU:     usedCPU,
 		MilliTotalMemory: totalMemory,
 		MilliUsedMemory:  usedMemory,
+		NodeCount:        nodeCount,
 	}, nil
 }
 
@@ -908,11 +942,11 @@ func (provisioner *KopsProvisioner) RefreshKopsMetadata(cluster *model.Cluster)
 
 	logger.Info("Refreshing kops metadata")
 
-	kopsClient, err := provisioner.getCachedKopsClient(cluster.ProvisionerMetadataKops.Name, logger)
+	kopsClient, err := provisioner.getCachedKopsClient(cluster, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to get kops client from cache")
 	}
-	defer provisioner.invalidateCachedKopsClientOnError(err, cluster.ProvisionerMetadataKops.Name, logger)
+	defer provisioner.invalidateCachedKopsClientOnError(err, cluster, logger)
 
 	k8sClient, err := k8s.NewFromFile(kopsClient.GetKubeConfigPath(), logger)
 	if err != nil {
@@ -926,7 +960,14 @@ func (provisioner *KopsProvisioner) RefreshKopsMetadata(cluster *model.Cluster)
 
 	// The GitVersion string usually looks like v1.14.2 so we trim the "v" off
 	// to match the version syntax used in kops.
-	cluster.ProvisionerMetadataKops.Version = strings.TrimLeft(versionInfo.GitVersion, "v")
+	version := strings.TrimLeft(versionInfo.GitVersion, "v")
+
+	if cluster.Provisioner == model.ProvisionerLocal {
+		cluster.ProvisionerMetadataLocal.Version = version
+		return nil
+	}
+
+	cluster.ProvisionerMetadataKops.Version = version
 
 	err = kopsClient.UpdateMetadata(cluster.ProvisionerMetadataKops)
 	if err != nil {
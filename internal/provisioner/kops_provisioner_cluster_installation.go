@@ -76,11 +76,11 @@ func (provisioner *kopsCIAlpha) CreateClusterInstallation(cluster *model.Cluster
 	})
 	logger.Info("Creating cluster installation")
 
-	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster.ProvisionerMetadataKops.Name, logger)
+	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to get kops config from cache")
 	}
-	defer provisioner.invalidateCachedKopsClientOnError(err, cluster.ProvisionerMetadataKops.Name, logger)
+	defer provisioner.invalidateCachedKopsClientOnError(err, cluster, logger)
 
 	k8sClient, err := k8s.NewFromFile(configLocation, logger)
 	if err != nil {
@@ -154,11 +154,11 @@ func (provisioner *kopsCIAlpha) HibernateClusterInstallation(cluster *model.Clus
 		"installation": clusterInstallation.InstallationID,
 	})
 
-	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster.ProvisionerMetadataKops.Name, logger)
+	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to get kops config from cache")
 	}
-	defer provisioner.invalidateCachedKopsClientOnError(err, cluster.ProvisionerMetadataKops.Name, logger)
+	defer provisioner.invalidateCachedKopsClientOnError(err, cluster, logger)
 
 	k8sClient, err := k8s.NewFromFile(configLocation, logger)
 	if err != nil {
@@ -199,11 +199,11 @@ func (provisioner *kopsCIAlpha) UpdateClusterInstallation(cluster *model.Cluster
 		"installation": clusterInstallation.InstallationID,
 	})
 
-	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster.ProvisionerMetadataKops.Name, logger)
+	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to get kops config from cache")
 	}
-	defer provisioner.invalidateCachedKopsClientOnError(err, cluster.ProvisionerMetadataKops.Name, logger)
+	defer provisioner.invalidateCachedKopsClientOnError(err, cluster, logger)
 
 	k8sClient, err := k8s.NewFromFile(configLocation, logger)
 	if err != nil {
@@ -293,7 +293,7 @@ func (provisioner *kopsCIAlpha) RefreshSecrets(cluster *model.Cluster, installat
 	})
 	logger.Info("Refreshing secrets for cluster installation")
 
-	k8sClient, invalidateCache, err := provisioner.k8sClient(cluster.ProvisionerMetadataKops.Name, logger)
+	k8sClient, invalidateCache, err := provisioner.k8sClient(cluster, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to create k8s client")
 	}
@@ -455,11 +455,11 @@ func (provisioner *kopsCIAlpha) DeleteClusterInstallation(cluster *model.Cluster
 		"installation": clusterInstallation.InstallationID,
 	})
 
-	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster.ProvisionerMetadataKops.Name, logger)
+	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to get kops config from cache")
 	}
-	defer provisioner.invalidateCachedKopsClientOnError(err, cluster.ProvisionerMetadataKops.Name, logger)
+	defer provisioner.invalidateCachedKopsClientOnError(err, cluster, logger)
 
 	k8sClient, err := k8s.NewFromFile(configLocation, logger)
 	if err != nil {
@@ -535,11 +535,11 @@ func (provisioner *KopsProvisioner) ExecClusterInstallationCLI(cluster *model.Cl
 		"installation": clusterInstallation.InstallationID,
 	})
 
-	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster.ProvisionerMetadataKops.Name, logger)
+	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster, logger)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get kops config from cache")
 	}
-	defer provisioner.invalidateCachedKopsClientOnError(err, cluster.ProvisionerMetadataKops.Name, logger)
+	defer provisioner.invalidateCachedKopsClientOnError(err, cluster, logger)
 
 	k8sClient, err := k8s.NewFromFile(configLocation, logger)
 	if err != nil {
@@ -600,7 +600,7 @@ func (provisioner *KopsProvisioner) ExecClusterInstallationJob(cluster *model.Cl
 	})
 	logger.Info("Executing job with CLI command on cluster installation")
 
-	k8sClient, invalidateCache, err := provisioner.k8sClient(cluster.ProvisionerMetadataKops.Name, logger)
+	k8sClient, invalidateCache, err := provisioner.k8sClient(cluster, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to create k8s client")
 	}
@@ -662,11 +662,11 @@ func (provisioner *KopsProvisioner) ExecClusterInstallationJob(cluster *model.Cl
 // getClusterInstallationResource gets the cluster installation resource from
 // the kubernetes API.
 func (provisioner *kopsCIAlpha) getClusterInstallationResource(cluster *model.Cluster, clusterInstallation *model.ClusterInstallation, logger log.FieldLogger) (*mmv1alpha1.ClusterInstallation, error) {
-	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster.ProvisionerMetadataKops.Name, logger)
+	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster, logger)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get kops config from cache")
 	}
-	defer provisioner.invalidateCachedKopsClientOnError(err, cluster.ProvisionerMetadataKops.Name, logger)
+	defer provisioner.invalidateCachedKopsClientOnError(err, cluster, logger)
 
 	k8sClient, err := k8s.NewFromFile(configLocation, logger)
 	if err != nil {
@@ -695,11 +695,11 @@ func (provisioner *KopsProvisioner) DeleteOldClusterInstallationLicenseSecrets(c
 		"installation": clusterInstallation.InstallationID,
 	})
 
-	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster.ProvisionerMetadataKops.Name, logger)
+	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to get kops config from cache")
 	}
-	defer provisioner.invalidateCachedKopsClientOnError(err, cluster.ProvisionerMetadataKops.Name, logger)
+	defer provisioner.invalidateCachedKopsClientOnError(err, cluster, logger)
 
 	k8sClient, err := k8s.NewFromFile(configLocation, logger)
 	if err != nil {
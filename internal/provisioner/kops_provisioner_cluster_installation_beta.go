@@ -33,11 +33,11 @@ func (provisioner *kopsCIBeta) CreateClusterInstallation(cluster *model.Cluster,
 	})
 	logger.Info("Creating cluster installation")
 
-	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster.ProvisionerMetadataKops.Name, logger)
+	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to get kops config from cache")
 	}
-	defer provisioner.invalidateCachedKopsClientOnError(err, cluster.ProvisionerMetadataKops.Name, logger)
+	defer provisioner.invalidateCachedKopsClientOnError(err, cluster, logger)
 
 	k8sClient, err := k8s.NewFromFile(configLocation, logger)
 	if err != nil {
@@ -102,11 +102,11 @@ func (provisioner *kopsCIBeta) HibernateClusterInstallation(cluster *model.Clust
 		"installation": clusterInstallation.InstallationID,
 	})
 
-	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster.ProvisionerMetadataKops.Name, logger)
+	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to get kops config from cache")
 	}
-	defer provisioner.invalidateCachedKopsClientOnError(err, cluster.ProvisionerMetadataKops.Name, logger)
+	defer provisioner.invalidateCachedKopsClientOnError(err, cluster, logger)
 
 	k8sClient, err := k8s.NewFromFile(configLocation, logger)
 	if err != nil {
@@ -147,11 +147,11 @@ func (provisioner *kopsCIBeta) UpdateClusterInstallation(cluster *model.Cluster,
 		"installation": clusterInstallation.InstallationID,
 	})
 
-	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster.ProvisionerMetadataKops.Name, logger)
+	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to get kops config from cache")
 	}
-	defer provisioner.invalidateCachedKopsClientOnError(err, cluster.ProvisionerMetadataKops.Name, logger)
+	defer provisioner.invalidateCachedKopsClientOnError(err, cluster, logger)
 
 	k8sClient, err := k8s.NewFromFile(configLocation, logger)
 	if err != nil {
@@ -242,7 +242,7 @@ func (provisioner *kopsCIBeta) RefreshSecrets(cluster *model.Cluster, installati
 	})
 	logger.Info("Refreshing secrets for cluster installation")
 
-	k8sClient, invalidateCache, err := provisioner.k8sClient(cluster.ProvisionerMetadataKops.Name, logger)
+	k8sClient, invalidateCache, err := provisioner.k8sClient(cluster, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to create k8s client")
 	}
@@ -355,11 +355,11 @@ func (provisioner *kopsCIBeta) EnsureCRMigrated(cluster *model.Cluster, clusterI
 	})
 	logger.Info("Ensuring cluster installation migrated to v1beta")
 
-	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster.ProvisionerMetadataKops.Name, logger)
+	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster, logger)
 	if err != nil {
 		return false, errors.Wrap(err, "failed to get kops config from cache")
 	}
-	defer provisioner.invalidateCachedKopsClientOnError(err, cluster.ProvisionerMetadataKops.Name, logger)
+	defer provisioner.invalidateCachedKopsClientOnError(err, cluster, logger)
 
 	k8sClient, err := k8s.NewFromFile(configLocation, logger)
 	if err != nil {
@@ -424,11 +424,11 @@ func (provisioner *kopsCIBeta) DeleteClusterInstallation(cluster *model.Cluster,
 		"installation": clusterInstallation.InstallationID,
 	})
 
-	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster.ProvisionerMetadataKops.Name, logger)
+	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to get kops config from cache")
 	}
-	defer provisioner.invalidateCachedKopsClientOnError(err, cluster.ProvisionerMetadataKops.Name, logger)
+	defer provisioner.invalidateCachedKopsClientOnError(err, cluster, logger)
 
 	k8sClient, err := k8s.NewFromFile(configLocation, logger)
 	if err != nil {
@@ -489,11 +489,11 @@ func (provisioner *kopsCIBeta) IsResourceReady(cluster *model.Cluster, clusterIn
 // getMattermostCustomResource gets the cluster installation resource from
 // the kubernetes API.
 func (provisioner *kopsCIBeta) getMattermostCustomResource(cluster *model.Cluster, clusterInstallation *model.ClusterInstallation, logger log.FieldLogger) (*mmv1beta1.Mattermost, error) {
-	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster.ProvisionerMetadataKops.Name, logger)
+	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster, logger)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get kops config from cache")
 	}
-	defer provisioner.invalidateCachedKopsClientOnError(err, cluster.ProvisionerMetadataKops.Name, logger)
+	defer provisioner.invalidateCachedKopsClientOnError(err, cluster, logger)
 
 	k8sClient, err := k8s.NewFromFile(configLocation, logger)
 	if err != nil {
@@ -7,6 +7,8 @@ package provisioner
 import (
 	"crypto/sha256"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/mattermost/mattermost-cloud/internal/testlib"
@@ -19,20 +21,24 @@ import (
 
 func TestGetCachedKopsClient(t *testing.T) {
 	logger := testlib.MakeLogger(t)
-	provisioner := NewKopsProvisioner(ProvisioningParams{}, nil, logger, nil, nil)
+	provisioner := NewKopsProvisioner(ProvisioningParams{}, nil, nil, nil, logger, nil, nil)
 
 	// Using &kops.Cmd{} here because kops.New() checks for the binary in your
 	// PATH which isn't needed for the test and fails in CI/CD.
 	provisioner.kopsCache["test"] = &kops.Cmd{}
+	cluster := &model.Cluster{
+		ID:                      model.NewID(),
+		ProvisionerMetadataKops: &model.KopsMetadata{Name: "test"},
+	}
 
 	t.Run("get cached client", func(t *testing.T) {
-		cachedClient, err := provisioner.getCachedKopsClient("test", logger)
+		cachedClient, err := provisioner.getCachedKopsClient(cluster, logger)
 		require.NoError(t, err)
 		assert.NotNil(t, cachedClient)
 	})
 
 	t.Run("get cached kubecfg", func(t *testing.T) {
-		config, err := provisioner.getCachedKopsClusterKubecfg("test", logger)
+		config, err := provisioner.getCachedKopsClusterKubecfg(cluster, logger)
 		require.NoError(t, err)
 		assert.NotEmpty(t, config)
 	})
@@ -52,15 +58,55 @@ func TestGetCachedKopsClient(t *testing.T) {
 
 	t.Run("invalidate cache on error; error is nil", func(t *testing.T) {
 		var cacheError error
-		provisioner.invalidateCachedKopsClientOnError(cacheError, "test", logger)
+		provisioner.invalidateCachedKopsClientOnError(cacheError, cluster, logger)
 		require.NotNil(t, provisioner.kopsCache["test"])
 	})
 
 	t.Run("invalidate cache on error; error is not nil", func(t *testing.T) {
 		cacheError := errors.New("not nil")
-		provisioner.invalidateCachedKopsClientOnError(cacheError, "test", logger)
+		provisioner.invalidateCachedKopsClientOnError(cacheError, cluster, logger)
 		require.Nil(t, provisioner.kopsCache["test"])
 	})
+
+	t.Run("local cluster", func(t *testing.T) {
+		kubeconfig, err := ioutil.TempFile("", "kubeconfig-")
+		require.NoError(t, err)
+		defer os.Remove(kubeconfig.Name())
+		_, err = kubeconfig.WriteString("apiVersion: v1")
+		require.NoError(t, err)
+		require.NoError(t, kubeconfig.Close())
+
+		localCluster := &model.Cluster{
+			ID:                       model.NewID(),
+			Provisioner:              model.ProvisionerLocal,
+			ProvisionerMetadataLocal: &model.LocalMetadata{KubeconfigPath: kubeconfig.Name()},
+		}
+
+		config, err := provisioner.getCachedKopsClusterKubecfg(localCluster, logger)
+		require.NoError(t, err)
+		require.NotNil(t, provisioner.kopsCache[localCluster.ID])
+
+		content, err := ioutil.ReadFile(config)
+		require.NoError(t, err)
+		assert.Equal(t, "apiVersion: v1", string(content))
+
+		err = provisioner.invalidateCachedKopsClient(localCluster.ID, logger)
+		require.NoError(t, err)
+		_, err = os.Stat(config)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("local cluster with missing kubeconfig", func(t *testing.T) {
+		localCluster := &model.Cluster{
+			ID:                       model.NewID(),
+			Provisioner:              model.ProvisionerLocal,
+			ProvisionerMetadataLocal: &model.LocalMetadata{KubeconfigPath: "/does/not/exist"},
+		}
+
+		_, err := provisioner.getCachedKopsClient(localCluster, logger)
+		require.Error(t, err)
+		require.Nil(t, provisioner.kopsCache[localCluster.ID])
+	})
 }
 
 func TestGenerateCILicenseName(t *testing.T) {
@@ -129,11 +129,11 @@ func (provisioner *KopsProvisioner) GetPublicLoadBalancerEndpoint(cluster *model
 		"nginx-namespace": namespace,
 	})
 
-	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster.ProvisionerMetadataKops.Name, logger)
+	configLocation, err := provisioner.getCachedKopsClusterKubecfg(cluster, logger)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to get kops config from cache")
 	}
-	defer provisioner.invalidateCachedKopsClientOnError(err, cluster.ProvisionerMetadataKops.Name, logger)
+	defer provisioner.invalidateCachedKopsClientOnError(err, cluster, logger)
 
 	k8sClient, err := k8s.NewFromFile(configLocation, logger)
 	if err != nil {
@@ -113,12 +113,28 @@ func (n *nginx) Migrate() error {
 }
 
 func (n *nginx) NewHelmDeployment() (*helmDeployment, error) {
-	awsACMCert, err := n.awsClient.GetCertificateSummaryByTag(aws.DefaultInstallCertificatesTagKey, aws.DefaultInstallCertificatesTagValue, n.logger)
+	// Local clusters are not fronted by an AWS load balancer, so there is no
+	// certificate or VPC to configure.
+	if n.cluster.Provisioner == model.ProvisionerLocal {
+		return &helmDeployment{
+			chartDeploymentName: "nginx",
+			chartName:           "ingress-nginx/ingress-nginx",
+			namespace:           "nginx",
+			desiredVersion:      n.desiredVersion,
+
+			cluster:         n.cluster,
+			kopsProvisioner: n.provisioner,
+			kops:            n.kops,
+			logger:          n.logger,
+		}, nil
+	}
+
+	certificate, err := n.provisioner.cloud.GetCertificateByTag(aws.DefaultInstallCertificatesTagKey, aws.DefaultInstallCertificatesTagValue, n.logger)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to retrive the AWS ACM")
 	}
 
-	clusterResources, err := n.awsClient.GetVpcResources(n.cluster.ID, n.logger)
+	network, err := n.provisioner.cloud.GetClusterNetwork(n.cluster.ID, n.logger)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to retrive the VPC information")
 	}
@@ -127,7 +143,7 @@ func (n *nginx) NewHelmDeployment() (*helmDeployment, error) {
 		chartDeploymentName: "nginx",
 		chartName:           "ingress-nginx/ingress-nginx",
 		namespace:           "nginx",
-		setArgument:         fmt.Sprintf("controller.service.annotations.service\\.beta\\.kubernetes\\.io/aws-load-balancer-ssl-cert=%s,controller.config.proxy-real-ip-cidr=%s", *awsACMCert.CertificateArn, clusterResources.VpcCIDR),
+		setArgument:         fmt.Sprintf("controller.service.annotations.service\\.beta\\.kubernetes\\.io/aws-load-balancer-ssl-cert=%s,controller.config.proxy-real-ip-cidr=%s", certificate.ID, network.CIDR),
 		desiredVersion:      n.desiredVersion,
 
 		cluster:         n.cluster,
@@ -113,7 +113,7 @@ func (n *nginxInternal) Migrate() error {
 
 func (n *nginxInternal) NewHelmDeployment() (*helmDeployment, error) {
 
-	awsACMPrivateCert, err := n.awsClient.GetCertificateSummaryByTag(aws.DefaultInstallPrivateCertificatesTagKey, aws.DefaultInstallPrivateCertificatesTagValue, n.logger)
+	certificate, err := n.provisioner.cloud.GetCertificateByTag(aws.DefaultInstallPrivateCertificatesTagKey, aws.DefaultInstallPrivateCertificatesTagValue, n.logger)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to retrive the AWS Private ACM")
 	}
@@ -122,7 +122,7 @@ func (n *nginxInternal) NewHelmDeployment() (*helmDeployment, error) {
 		chartDeploymentName: "nginx-internal",
 		chartName:           "ingress-nginx/ingress-nginx",
 		namespace:           "nginx-internal",
-		setArgument:         fmt.Sprintf("controller.service.annotations.service\\.beta\\.kubernetes\\.io/aws-load-balancer-ssl-cert=%s", *awsACMPrivateCert.CertificateArn),
+		setArgument:         fmt.Sprintf("controller.service.annotations.service\\.beta\\.kubernetes\\.io/aws-load-balancer-ssl-cert=%s", certificate.ID),
 		desiredVersion:      n.desiredVersion,
 
 		cluster:         n.cluster,
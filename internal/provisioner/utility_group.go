@@ -117,8 +117,16 @@ func newUtilityGroupHandle(kops *kops.Cmd, provisioner *KopsProvisioner, cluster
 
 	// the order of utilities here matters; the utilities are deployed
 	// in order to resolve dependencies between them
+	utilities := []Utility{nginx, nginxInternal, prometheusOperator, thanos, fluentbit, teleport, pgbouncer}
+
+	// Local clusters only get the public ingress; the remaining utilities
+	// depend on AWS resources such as private DNS, S3 and DynamoDB.
+	if cluster.Provisioner == model.ProvisionerLocal {
+		utilities = []Utility{nginx}
+	}
+
 	return &utilityGroup{
-		utilities:   []Utility{nginx, nginxInternal, prometheusOperator, thanos, fluentbit, teleport, pgbouncer},
+		utilities:   utilities,
 		kops:        kops,
 		provisioner: provisioner,
 		cluster:     cluster,
@@ -0,0 +1,111 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+const apiKeyTable = "APIKey"
+
+var apiKeySelect sq.SelectBuilder
+
+func init() {
+	apiKeySelect = sq.
+		Select("ID", "Name", "Scope", "OwnerID", "KeyHash", "CreateAt", "DeleteAt").
+		From(apiKeyTable)
+}
+
+// GetAPIKey fetches the given API key by id.
+func (sqlStore *SQLStore) GetAPIKey(id string) (*model.APIKey, error) {
+	return sqlStore.getAPIKey(apiKeySelect.Where("ID = ?", id))
+}
+
+// GetAPIKeyByKeyHash fetches the API key matching the given key hash.
+func (sqlStore *SQLStore) GetAPIKeyByKeyHash(keyHash string) (*model.APIKey, error) {
+	return sqlStore.getAPIKey(apiKeySelect.Where("KeyHash = ?", keyHash))
+}
+
+func (sqlStore *SQLStore) getAPIKey(builder sq.SelectBuilder) (*model.APIKey, error) {
+	var apiKey model.APIKey
+	err := sqlStore.getBuilder(sqlStore.db, &apiKey, builder)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get API key")
+	}
+
+	return &apiKey, nil
+}
+
+// GetAPIKeys fetches the given page of created API keys. The first page is 0.
+func (sqlStore *SQLStore) GetAPIKeys(filter *model.APIKeyFilter) ([]*model.APIKey, error) {
+	builder := apiKeySelect.
+		OrderBy("CreateAt ASC")
+
+	builder = applyPagingFilter(builder, filter.Paging)
+
+	if filter.OwnerID != "" {
+		builder = builder.Where("OwnerID = ?", filter.OwnerID)
+	}
+
+	var apiKeys []*model.APIKey
+	err := sqlStore.selectBuilder(sqlStore.db, &apiKeys, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for API keys")
+	}
+
+	return apiKeys, nil
+}
+
+// CreateAPIKey records the given API key to the database, assigning it a unique ID.
+// Only the hash of the plain text key is persisted.
+func (sqlStore *SQLStore) CreateAPIKey(apiKey *model.APIKey) error {
+	if apiKey.Key == "" {
+		return errors.New("API key must not be empty")
+	}
+
+	apiKey.ID = model.NewID()
+	apiKey.KeyHash = model.HashAPIKey(apiKey.Key)
+	apiKey.CreateAt = GetMillis()
+
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Insert(apiKeyTable).
+		SetMap(map[string]interface{}{
+			"ID":       apiKey.ID,
+			"Name":     apiKey.Name,
+			"Scope":    apiKey.Scope,
+			"OwnerID":  apiKey.OwnerID,
+			"KeyHash":  apiKey.KeyHash,
+			"CreateAt": apiKey.CreateAt,
+			"DeleteAt": 0,
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create API key")
+	}
+
+	return nil
+}
+
+// DeleteAPIKey marks the given API key as deleted, but does not remove the
+// record from the database.
+func (sqlStore *SQLStore) DeleteAPIKey(id string) error {
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Update(apiKeyTable).
+		Set("DeleteAt", GetMillis()).
+		Where("ID = ?", id).
+		Where("DeleteAt = 0"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to mark API key as deleted")
+	}
+
+	return nil
+}
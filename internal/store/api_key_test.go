@@ -0,0 +1,96 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeys(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	t.Run("get unknown API key", func(t *testing.T) {
+		apiKey, err := sqlStore.GetAPIKey("unknown")
+		require.NoError(t, err)
+		require.Nil(t, apiKey)
+	})
+
+	t.Run("fail to create API key without key", func(t *testing.T) {
+		err := sqlStore.CreateAPIKey(&model.APIKey{Name: "no-key", Scope: model.APIKeyScopeAdmin})
+		require.Error(t, err)
+	})
+
+	apiKey1 := &model.APIKey{
+		Name:  "admin",
+		Key:   "secret1",
+		Scope: model.APIKeyScopeAdmin,
+	}
+	err := sqlStore.CreateAPIKey(apiKey1)
+	require.NoError(t, err)
+	assert.NotEmpty(t, apiKey1.ID)
+	assert.Equal(t, model.HashAPIKey("secret1"), apiKey1.KeyHash)
+
+	time.Sleep(1 * time.Millisecond)
+
+	apiKey2 := &model.APIKey{
+		Name:    "owner",
+		Key:     "secret2",
+		Scope:   model.APIKeyScopeInstallations,
+		OwnerID: "owner1",
+	}
+	err = sqlStore.CreateAPIKey(apiKey2)
+	require.NoError(t, err)
+
+	// Plain text keys are never persisted.
+	apiKey1.Key = ""
+	apiKey2.Key = ""
+
+	t.Run("get API key", func(t *testing.T) {
+		fetched, err := sqlStore.GetAPIKey(apiKey1.ID)
+		require.NoError(t, err)
+		assert.Equal(t, apiKey1, fetched)
+	})
+
+	t.Run("get API key by hash", func(t *testing.T) {
+		fetched, err := sqlStore.GetAPIKeyByKeyHash(model.HashAPIKey("secret2"))
+		require.NoError(t, err)
+		assert.Equal(t, apiKey2, fetched)
+
+		fetched, err = sqlStore.GetAPIKeyByKeyHash(model.HashAPIKey("unknown"))
+		require.NoError(t, err)
+		assert.Nil(t, fetched)
+	})
+
+	t.Run("get API keys", func(t *testing.T) {
+		apiKeys, err := sqlStore.GetAPIKeys(&model.APIKeyFilter{Paging: model.AllPagesNotDeleted()})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.APIKey{apiKey1, apiKey2}, apiKeys)
+
+		apiKeys, err = sqlStore.GetAPIKeys(&model.APIKeyFilter{Paging: model.AllPagesNotDeleted(), OwnerID: "owner1"})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.APIKey{apiKey2}, apiKeys)
+	})
+
+	t.Run("delete API key", func(t *testing.T) {
+		err := sqlStore.DeleteAPIKey(apiKey1.ID)
+		require.NoError(t, err)
+
+		fetched, err := sqlStore.GetAPIKey(apiKey1.ID)
+		require.NoError(t, err)
+		assert.True(t, fetched.IsDeleted())
+
+		apiKeys, err := sqlStore.GetAPIKeys(&model.APIKeyFilter{Paging: model.AllPagesNotDeleted()})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.APIKey{apiKey2}, apiKeys)
+	})
+}
@@ -0,0 +1,388 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	bulkOperationTable     = "BulkOperation"
+	bulkOperationItemTable = "BulkOperationItem"
+)
+
+var bulkOperationSelect sq.SelectBuilder
+var bulkOperationItemSelect sq.SelectBuilder
+
+func init() {
+	bulkOperationSelect = sq.
+		Select("ID",
+			"Type",
+			"FilterRaw",
+			"PatchRaw",
+			"AnnotationsRaw",
+			"Concurrency",
+			"State",
+			"CreateAt",
+			"CompleteAt",
+			"DeleteAt",
+			"LockAcquiredBy",
+			"LockAcquiredAt",
+		).
+		From(bulkOperationTable)
+
+	bulkOperationItemSelect = sq.
+		Select("BulkOperationID",
+			"InstallationID",
+			"State",
+			"Error",
+			"UpdateAt",
+		).
+		From(bulkOperationItemTable)
+}
+
+type rawBulkOperation struct {
+	*model.BulkOperation
+	FilterRaw      []byte
+	PatchRaw       []byte
+	AnnotationsRaw []byte
+}
+
+type rawBulkOperations []*rawBulkOperation
+
+func (r *rawBulkOperation) toBulkOperation() (*model.BulkOperation, error) {
+	// We only need to set values that are converted from a raw database format.
+	err := json.Unmarshal(r.FilterRaw, &r.BulkOperation.Filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(r.PatchRaw) > 0 {
+		patch := &model.PatchInstallationRequest{}
+		err = json.Unmarshal(r.PatchRaw, patch)
+		if err != nil {
+			return nil, err
+		}
+		r.BulkOperation.Patch = patch
+	}
+	if len(r.AnnotationsRaw) > 0 {
+		err = json.Unmarshal(r.AnnotationsRaw, &r.BulkOperation.Annotations)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return r.BulkOperation, nil
+}
+
+func (r *rawBulkOperations) toBulkOperations() ([]*model.BulkOperation, error) {
+	if r == nil {
+		return []*model.BulkOperation{}, nil
+	}
+	operations := make([]*model.BulkOperation, 0, len(*r))
+
+	for _, raw := range *r {
+		operation, err := raw.toBulkOperation()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create bulk operation from raw")
+		}
+		operations = append(operations, operation)
+	}
+	return operations, nil
+}
+
+// CreateBulkOperation records the given bulk operation to the database,
+// assigning it a unique ID, together with a pending item for each
+// installation matching the operation filter.
+func (sqlStore *SQLStore) CreateBulkOperation(operation *model.BulkOperation) error {
+	operation.ID = model.NewID()
+	operation.CreateAt = GetMillis()
+	operation.State = model.BulkOperationStateInProgress
+
+	filterRaw, err := json.Marshal(operation.Filter)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal installation filter")
+	}
+	var patchRaw []byte
+	if operation.Patch != nil {
+		patchRaw, err = json.Marshal(operation.Patch)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal installation patch")
+		}
+	}
+	var annotationsRaw []byte
+	if len(operation.Annotations) > 0 {
+		annotationsRaw, err = json.Marshal(operation.Annotations)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal annotations")
+		}
+	}
+
+	tx, err := sqlStore.beginTransaction(sqlStore.db)
+	if err != nil {
+		return errors.Wrap(err, "failed to start transaction")
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	installationIDs, err := sqlStore.getBulkOperationInstallationIDs(tx, operation.Filter)
+	if err != nil {
+		return err
+	}
+
+	_, err = sqlStore.execBuilder(tx, sq.
+		Insert(bulkOperationTable).
+		SetMap(map[string]interface{}{
+			"ID":             operation.ID,
+			"Type":           operation.Type,
+			"FilterRaw":      filterRaw,
+			"PatchRaw":       patchRaw,
+			"AnnotationsRaw": annotationsRaw,
+			"Concurrency":    operation.Concurrency,
+			"State":          operation.State,
+			"CreateAt":       operation.CreateAt,
+			"CompleteAt":     0,
+			"DeleteAt":       0,
+			"LockAcquiredBy": nil,
+			"LockAcquiredAt": 0,
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create bulk operation")
+	}
+
+	for _, installationID := range installationIDs {
+		_, err = sqlStore.execBuilder(tx, sq.
+			Insert(bulkOperationItemTable).
+			SetMap(map[string]interface{}{
+				"BulkOperationID": operation.ID,
+				"InstallationID":  installationID,
+				"State":           model.BulkOperationItemStatePending,
+				"Error":           "",
+				"UpdateAt":        operation.CreateAt,
+			}),
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to create bulk operation item")
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// getBulkOperationInstallationIDs returns the IDs of the installations which
+// are not deleted and match the given bulk operation filter.
+func (sqlStore *SQLStore) getBulkOperationInstallationIDs(db dbInterface, operationFilter model.BulkOperationInstallationFilter) ([]string, error) {
+	filter := &model.InstallationFilter{
+		Paging:     model.AllPagesNotDeleted(),
+		OwnerID:    operationFilter.OwnerID,
+		GroupID:    operationFilter.GroupID,
+		State:      operationFilter.State,
+		DNSPattern: operationFilter.DNSPattern,
+	}
+
+	if len(operationFilter.Annotations) > 0 {
+		filter.Annotations = &model.AnnotationsFilter{}
+		for _, name := range operationFilter.Annotations {
+			annotation, err := sqlStore.getAnnotationByName(db, name)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get annotation '%s' by name", name)
+			}
+			if annotation == nil {
+				// No installation can have an annotation which does not exist.
+				return []string{}, nil
+			}
+			filter.Annotations.MatchAllIDs = append(filter.Annotations.MatchAllIDs, annotation.ID)
+		}
+	}
+
+	builder := sq.Select("Installation.ID").
+		From("Installation").
+		OrderBy("CreateAt ASC")
+	builder = sqlStore.applyInstallationFilter(builder, filter)
+
+	var installationIDs []string
+	err := sqlStore.selectBuilder(db, &installationIDs, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for bulk operation installations")
+	}
+
+	return installationIDs, nil
+}
+
+// GetBulkOperation fetches the given bulk operation by id.
+func (sqlStore *SQLStore) GetBulkOperation(id string) (*model.BulkOperation, error) {
+	var rawOperation rawBulkOperation
+	err := sqlStore.getBuilder(sqlStore.db, &rawOperation,
+		bulkOperationSelect.Where("ID = ?", id),
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get bulk operation by id")
+	}
+
+	return rawOperation.toBulkOperation()
+}
+
+// GetBulkOperations fetches the given page of bulk operations. The first page is 0.
+func (sqlStore *SQLStore) GetBulkOperations(filter *model.BulkOperationFilter) ([]*model.BulkOperation, error) {
+	builder := bulkOperationSelect.
+		OrderBy("CreateAt ASC")
+	builder = applyPagingFilter(builder, filter.Paging)
+
+	if len(filter.States) > 0 {
+		builder = builder.Where(sq.Eq{"State": filter.States})
+	}
+
+	var rawOperations rawBulkOperations
+	err := sqlStore.selectBuilder(sqlStore.db, &rawOperations, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for bulk operations")
+	}
+
+	return rawOperations.toBulkOperations()
+}
+
+// GetUnlockedBulkOperationsPendingWork returns all unlocked bulk operations
+// which are in progress.
+func (sqlStore *SQLStore) GetUnlockedBulkOperationsPendingWork() ([]*model.BulkOperation, error) {
+	builder := bulkOperationSelect.
+		Where("DeleteAt = 0").
+		Where("LockAcquiredAt = 0").
+		Where("State = ?", model.BulkOperationStateInProgress).
+		OrderBy("CreateAt ASC")
+
+	var rawOperations rawBulkOperations
+	err := sqlStore.selectBuilder(sqlStore.db, &rawOperations, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get bulk operations pending work")
+	}
+
+	return rawOperations.toBulkOperations()
+}
+
+// UpdateBulkOperationState updates the state of the given bulk operation.
+func (sqlStore *SQLStore) UpdateBulkOperationState(operation *model.BulkOperation) error {
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Update(bulkOperationTable).
+		SetMap(map[string]interface{}{
+			"State":      operation.State,
+			"CompleteAt": operation.CompleteAt,
+		}).
+		Where("ID = ?", operation.ID),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to update bulk operation state")
+	}
+
+	return nil
+}
+
+// CancelBulkOperation marks the given bulk operation and its pending items as
+// cancelled. Items for which the installation state change was already
+// requested are left as they are.
+func (sqlStore *SQLStore) CancelBulkOperation(id string) error {
+	tx, err := sqlStore.beginTransaction(sqlStore.db)
+	if err != nil {
+		return errors.Wrap(err, "failed to start transaction")
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	now := GetMillis()
+	result, err := sqlStore.execBuilder(tx, sq.
+		Update(bulkOperationTable).
+		SetMap(map[string]interface{}{
+			"State":      model.BulkOperationStateCancelled,
+			"CompleteAt": now,
+		}).
+		Where("ID = ?", id).
+		Where("State = ?", model.BulkOperationStateInProgress),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to cancel bulk operation")
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to check affected rows when cancelling bulk operation")
+	}
+	if rows == 0 {
+		return nil
+	}
+
+	_, err = sqlStore.execBuilder(tx, sq.
+		Update(bulkOperationItemTable).
+		SetMap(map[string]interface{}{
+			"State":    model.BulkOperationItemStateCancelled,
+			"UpdateAt": now,
+		}).
+		Where("BulkOperationID = ?", id).
+		Where("State = ?", model.BulkOperationItemStatePending),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to cancel bulk operation items")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// GetBulkOperationItems fetches the items of the given bulk operation.
+func (sqlStore *SQLStore) GetBulkOperationItems(operationID string) ([]*model.BulkOperationItem, error) {
+	var items []*model.BulkOperationItem
+	err := sqlStore.selectBuilder(sqlStore.db, &items,
+		bulkOperationItemSelect.
+			Where("BulkOperationID = ?", operationID).
+			OrderBy("InstallationID ASC"),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for bulk operation items")
+	}
+
+	return items, nil
+}
+
+// UpdateBulkOperationItem updates the state of the given bulk operation item.
+func (sqlStore *SQLStore) UpdateBulkOperationItem(item *model.BulkOperationItem) error {
+	item.UpdateAt = GetMillis()
+
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Update(bulkOperationItemTable).
+		SetMap(map[string]interface{}{
+			"State":    item.State,
+			"Error":    item.Error,
+			"UpdateAt": item.UpdateAt,
+		}).
+		Where("BulkOperationID = ?", item.BulkOperationID).
+		Where("InstallationID = ?", item.InstallationID),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to update bulk operation item")
+	}
+
+	return nil
+}
+
+// LockBulkOperation marks the bulk operation as locked for exclusive use by the caller.
+func (sqlStore *SQLStore) LockBulkOperation(operationID, lockerID string) (bool, error) {
+	return sqlStore.lockRows(bulkOperationTable, []string{operationID}, lockerID)
+}
+
+// UnlockBulkOperation releases a lock previously acquired against a caller.
+func (sqlStore *SQLStore) UnlockBulkOperation(operationID, lockerID string, force bool) (bool, error) {
+	return sqlStore.unlockRows(bulkOperationTable, []string{operationID}, lockerID, force)
+}
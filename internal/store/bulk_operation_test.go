@@ -0,0 +1,208 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkOperations(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	ownerID := model.NewID()
+	installation1 := &model.Installation{
+		OwnerID: ownerID,
+		DNS:     "installation1.test.example.com",
+		State:   model.InstallationStateStable,
+	}
+	err := sqlStore.CreateInstallation(installation1, []*model.Annotation{{Name: "annotation1"}, {Name: "annotation2"}})
+	require.NoError(t, err)
+
+	installation2 := &model.Installation{
+		OwnerID: ownerID,
+		DNS:     "installation2.test.example.com",
+		State:   model.InstallationStateHibernating,
+	}
+	err = sqlStore.CreateInstallation(installation2, []*model.Annotation{{Name: "annotation1"}})
+	require.NoError(t, err)
+
+	installation3 := &model.Installation{
+		OwnerID: model.NewID(),
+		DNS:     "installation3_other.example.com",
+		State:   model.InstallationStateStable,
+	}
+	err = sqlStore.CreateInstallation(installation3, nil)
+	require.NoError(t, err)
+
+	deletedInstallation := &model.Installation{
+		OwnerID: ownerID,
+		DNS:     "deleted.test.example.com",
+		State:   model.InstallationStateDeleted,
+	}
+	err = sqlStore.CreateInstallation(deletedInstallation, nil)
+	require.NoError(t, err)
+	err = sqlStore.DeleteInstallation(deletedInstallation.ID)
+	require.NoError(t, err)
+
+	version := "5.39.0"
+	operation1 := &model.BulkOperation{
+		Type:        model.BulkOperationTypeUpdate,
+		Filter:      model.BulkOperationInstallationFilter{OwnerID: ownerID},
+		Patch:       &model.PatchInstallationRequest{Version: &version},
+		Concurrency: 1,
+	}
+	err = sqlStore.CreateBulkOperation(operation1)
+	require.NoError(t, err)
+	require.NotEmpty(t, operation1.ID)
+	assert.Equal(t, model.BulkOperationStateInProgress, operation1.State)
+
+	itemInstallationIDs := func(t *testing.T, operationID string) []string {
+		items, err := sqlStore.GetBulkOperationItems(operationID)
+		require.NoError(t, err)
+
+		installationIDs := []string{}
+		for _, item := range items {
+			assert.Equal(t, model.BulkOperationItemStatePending, item.State)
+			installationIDs = append(installationIDs, item.InstallationID)
+		}
+		return installationIDs
+	}
+
+	t.Run("get operation", func(t *testing.T) {
+		operation, err := sqlStore.GetBulkOperation(operation1.ID)
+		require.NoError(t, err)
+		assert.Equal(t, operation1, operation)
+	})
+
+	t.Run("get unknown operation", func(t *testing.T) {
+		operation, err := sqlStore.GetBulkOperation(model.NewID())
+		require.NoError(t, err)
+		assert.Nil(t, operation)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		testCases := []struct {
+			description string
+			filter      model.BulkOperationInstallationFilter
+			expected    []string
+		}{
+			{"owner", model.BulkOperationInstallationFilter{OwnerID: ownerID}, []string{installation1.ID, installation2.ID}},
+			{"state", model.BulkOperationInstallationFilter{State: model.InstallationStateHibernating}, []string{installation2.ID}},
+			{"annotations", model.BulkOperationInstallationFilter{Annotations: []string{"annotation1", "annotation2"}}, []string{installation1.ID}},
+			{"unknown annotation", model.BulkOperationInstallationFilter{Annotations: []string{"annotation1", "unknown"}}, []string{}},
+			{"dns pattern", model.BulkOperationInstallationFilter{DNSPattern: "*.test.example.com"}, []string{installation1.ID, installation2.ID}},
+			{"dns pattern with underscore", model.BulkOperationInstallationFilter{DNSPattern: "installation_*"}, []string{}},
+			{"dns pattern and state", model.BulkOperationInstallationFilter{DNSPattern: "installation*", State: model.InstallationStateStable}, []string{installation1.ID, installation3.ID}},
+		}
+
+		for _, testCase := range testCases {
+			t.Run(testCase.description, func(t *testing.T) {
+				operation := &model.BulkOperation{
+					Type:        model.BulkOperationTypeHibernate,
+					Filter:      testCase.filter,
+					Concurrency: 1,
+				}
+				err := sqlStore.CreateBulkOperation(operation)
+				require.NoError(t, err)
+
+				assert.ElementsMatch(t, testCase.expected, itemInstallationIDs(t, operation.ID))
+			})
+		}
+	})
+
+	t.Run("pending work", func(t *testing.T) {
+		operations, err := sqlStore.GetUnlockedBulkOperationsPendingWork()
+		require.NoError(t, err)
+		require.NotEmpty(t, operations)
+		assert.Equal(t, operation1.ID, operations[0].ID)
+
+		locked, err := sqlStore.LockBulkOperation(operation1.ID, "locker")
+		require.NoError(t, err)
+		require.True(t, locked)
+
+		operations, err = sqlStore.GetUnlockedBulkOperationsPendingWork()
+		require.NoError(t, err)
+		for _, operation := range operations {
+			assert.NotEqual(t, operation1.ID, operation.ID)
+		}
+
+		unlocked, err := sqlStore.UnlockBulkOperation(operation1.ID, "locker", false)
+		require.NoError(t, err)
+		require.True(t, unlocked)
+	})
+
+	t.Run("update item", func(t *testing.T) {
+		items, err := sqlStore.GetBulkOperationItems(operation1.ID)
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+
+		items[0].State = model.BulkOperationItemStateFailed
+		items[0].Error = "failure"
+		err = sqlStore.UpdateBulkOperationItem(items[0])
+		require.NoError(t, err)
+
+		updatedItems, err := sqlStore.GetBulkOperationItems(operation1.ID)
+		require.NoError(t, err)
+		assert.Equal(t, items, updatedItems)
+	})
+
+	t.Run("cancel operation", func(t *testing.T) {
+		err = sqlStore.CancelBulkOperation(operation1.ID)
+		require.NoError(t, err)
+
+		operation, err := sqlStore.GetBulkOperation(operation1.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.BulkOperationStateCancelled, operation.State)
+		assert.NotZero(t, operation.CompleteAt)
+
+		items, err := sqlStore.GetBulkOperationItems(operation1.ID)
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		assert.Equal(t, model.BulkOperationItemStateFailed, items[0].State)
+		assert.Equal(t, model.BulkOperationItemStateCancelled, items[1].State)
+
+		operations, err := sqlStore.GetBulkOperations(&model.BulkOperationFilter{
+			Paging: model.AllPagesNotDeleted(),
+			States: []model.BulkOperationState{model.BulkOperationStateCancelled},
+		})
+		require.NoError(t, err)
+		require.Len(t, operations, 1)
+		assert.Equal(t, operation1.ID, operations[0].ID)
+	})
+
+	t.Run("complete operation", func(t *testing.T) {
+		operation := &model.BulkOperation{
+			Type:        model.BulkOperationTypeAddAnnotations,
+			Filter:      model.BulkOperationInstallationFilter{OwnerID: ownerID},
+			Annotations: []string{"annotation3"},
+			Concurrency: 1,
+		}
+		err := sqlStore.CreateBulkOperation(operation)
+		require.NoError(t, err)
+
+		operation.State = model.BulkOperationStateCompleted
+		operation.CompleteAt = GetMillis()
+		err = sqlStore.UpdateBulkOperationState(operation)
+		require.NoError(t, err)
+
+		fetched, err := sqlStore.GetBulkOperation(operation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, operation, fetched)
+
+		err = sqlStore.CancelBulkOperation(operation.ID)
+		require.NoError(t, err)
+
+		fetched, err = sqlStore.GetBulkOperation(operation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.BulkOperationStateCompleted, fetched.State)
+	})
+}
@@ -43,9 +43,17 @@ func buildRawMetadata(cluster *model.Cluster) (*RawClusterMetadata, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to marshal ProviderMetadataAWS")
 	}
-	provisionerMetadataJSON, err := json.Marshal(cluster.ProvisionerMetadataKops)
-	if err != nil {
-		return nil, errors.Wrap(err, "unable to marshal ProvisionerMetadataKops")
+	var provisionerMetadataJSON []byte
+	if cluster.Provisioner == model.ProvisionerLocal {
+		provisionerMetadataJSON, err = json.Marshal(cluster.ProvisionerMetadataLocal)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to marshal ProvisionerMetadataLocal")
+		}
+	} else {
+		provisionerMetadataJSON, err = json.Marshal(cluster.ProvisionerMetadataKops)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to marshal ProvisionerMetadataKops")
+		}
 	}
 	utilityMetadataJSON, err := json.Marshal(cluster.UtilityMetadata)
 	if err != nil {
@@ -65,7 +73,11 @@ func (r *rawCluster) toCluster() (*model.Cluster, error) {
 	if err != nil {
 		return nil, err
 	}
-	r.Cluster.ProvisionerMetadataKops, err = model.NewKopsMetadata(r.ProvisionerMetadataRaw)
+	if r.Cluster.Provisioner == model.ProvisionerLocal {
+		r.Cluster.ProvisionerMetadataLocal, err = model.NewLocalMetadata(r.ProvisionerMetadataRaw)
+	} else {
+		r.Cluster.ProvisionerMetadataKops, err = model.NewKopsMetadata(r.ProvisionerMetadataRaw)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -131,6 +143,14 @@ func (sqlStore *SQLStore) applyClustersFilter(builder sq.SelectBuilder, filter *
 			GroupBy("Cluster.ID").
 			Having(fmt.Sprintf("count(DISTINCT %s.AnnotationID) = ?", clusterAnnotationTable), len(filter.Annotations.MatchAllIDs))
 	}
+	if filter.Annotations != nil && len(filter.Annotations.MatchAnyIDs) > 0 {
+		annotatedQuery, args, _ := sq.
+			Select(fmt.Sprintf("%s.ClusterID", clusterAnnotationTable)).
+			From(clusterAnnotationTable).
+			Where(sq.Eq{fmt.Sprintf("%s.AnnotationID", clusterAnnotationTable): filter.Annotations.MatchAnyIDs}).
+			ToSql()
+		builder = builder.Where(fmt.Sprintf("Cluster.ID IN (%s)", annotatedQuery), args...)
+	}
 
 	return builder
 }
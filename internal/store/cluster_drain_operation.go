@@ -0,0 +1,206 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	clusterDrainTable = "ClusterDrainOperation"
+)
+
+var clusterDrainSelect sq.SelectBuilder
+
+func init() {
+	clusterDrainSelect = sq.
+		Select("ID",
+			"ClusterID",
+			"MaxConcurrentMigrations",
+			"RequestAt",
+			"State",
+			"CompleteAt",
+			"DeleteAt",
+			"LockAcquiredBy",
+			"LockAcquiredAt",
+		).
+		From(clusterDrainTable)
+}
+
+// TriggerClusterDrain creates new ClusterDrainOperation in Requested state
+// and disallows scheduling new installations on the cluster.
+func (sqlStore *SQLStore) TriggerClusterDrain(drainOp *model.ClusterDrainOperation, cluster *model.Cluster) (*model.ClusterDrainOperation, error) {
+	drainOp.ClusterID = cluster.ID
+	drainOp.State = model.ClusterDrainStateRequested
+
+	tx, err := sqlStore.beginTransaction(sqlStore.db)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to start transaction")
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	err = sqlStore.createClusterDrain(tx, drainOp)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cluster drain")
+	}
+
+	cluster.AllowInstallations = false
+	_, err = sqlStore.execBuilder(tx, sq.
+		Update("Cluster").
+		Set("AllowInstallations", cluster.AllowInstallations).
+		Where("ID = ?", cluster.ID),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to disallow installations on cluster")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return drainOp, nil
+}
+
+// CreateClusterDrainOperation records cluster drain operation to the database, assigning it a unique ID.
+func (sqlStore *SQLStore) CreateClusterDrainOperation(drainOp *model.ClusterDrainOperation) error {
+	return sqlStore.createClusterDrain(sqlStore.db, drainOp)
+}
+
+func (sqlStore *SQLStore) createClusterDrain(db execer, drainOp *model.ClusterDrainOperation) error {
+	drainOp.ID = model.NewID()
+	drainOp.RequestAt = GetMillis()
+
+	_, err := sqlStore.execBuilder(db, sq.
+		Insert(clusterDrainTable).
+		SetMap(map[string]interface{}{
+			"ID":                      drainOp.ID,
+			"ClusterID":               drainOp.ClusterID,
+			"MaxConcurrentMigrations": drainOp.MaxConcurrentMigrations,
+			"RequestAt":               drainOp.RequestAt,
+			"State":                   drainOp.State,
+			"CompleteAt":              drainOp.CompleteAt,
+			"DeleteAt":                0,
+			"LockAcquiredBy":          nil,
+			"LockAcquiredAt":          0,
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create cluster drain operation")
+	}
+
+	return nil
+}
+
+// GetClusterDrainOperation fetches the given cluster drain operation.
+func (sqlStore *SQLStore) GetClusterDrainOperation(id string) (*model.ClusterDrainOperation, error) {
+	builder := clusterDrainSelect.
+		Where("ID = ?", id)
+
+	var drainOp model.ClusterDrainOperation
+	err := sqlStore.getBuilder(sqlStore.db, &drainOp, builder)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to query for cluster drain operation")
+	}
+
+	return &drainOp, nil
+}
+
+// GetClusterDrainOperations fetches the given page of cluster drain operations. The first page is 0.
+func (sqlStore *SQLStore) GetClusterDrainOperations(filter *model.ClusterDrainFilter) ([]*model.ClusterDrainOperation, error) {
+	builder := clusterDrainSelect.
+		OrderBy("RequestAt DESC")
+	builder = sqlStore.applyClusterDrainFilter(builder, filter)
+
+	return sqlStore.getClusterDrainOperations(builder)
+}
+
+// GetUnlockedClusterDrainOperationsPendingWork returns unlocked cluster drain operations in a pending state.
+func (sqlStore *SQLStore) GetUnlockedClusterDrainOperationsPendingWork() ([]*model.ClusterDrainOperation, error) {
+	builder := clusterDrainSelect.
+		Where(sq.Eq{
+			"State": model.AllClusterDrainOperationsStatesPendingWork,
+		}).
+		Where("LockAcquiredAt = 0").
+		OrderBy("RequestAt ASC")
+
+	return sqlStore.getClusterDrainOperations(builder)
+}
+
+func (sqlStore *SQLStore) getClusterDrainOperations(builder builder) ([]*model.ClusterDrainOperation, error) {
+	var drainOps []*model.ClusterDrainOperation
+	err := sqlStore.selectBuilder(sqlStore.db, &drainOps, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for cluster drain operations")
+	}
+
+	return drainOps, nil
+}
+
+// UpdateClusterDrainOperationState updates the given cluster drain operation state.
+func (sqlStore *SQLStore) UpdateClusterDrainOperationState(drainOp *model.ClusterDrainOperation) error {
+	return sqlStore.updateClusterDrainFields(
+		sqlStore.db,
+		drainOp.ID, map[string]interface{}{
+			"State": drainOp.State,
+		})
+}
+
+// UpdateClusterDrainOperation updates the given cluster drain operation.
+func (sqlStore *SQLStore) UpdateClusterDrainOperation(drainOp *model.ClusterDrainOperation) error {
+	return sqlStore.updateClusterDrainFields(
+		sqlStore.db,
+		drainOp.ID, map[string]interface{}{
+			"State":                   drainOp.State,
+			"MaxConcurrentMigrations": drainOp.MaxConcurrentMigrations,
+			"CompleteAt":              drainOp.CompleteAt,
+		})
+}
+
+func (sqlStore *SQLStore) updateClusterDrainFields(db execer, id string, fields map[string]interface{}) error {
+	_, err := sqlStore.execBuilder(db, sq.
+		Update(clusterDrainTable).
+		SetMap(fields).
+		Where("ID = ?", id))
+	if err != nil {
+		return errors.Wrapf(err, "failed to update cluster drain operation fields: %s", getMapKeys(fields))
+	}
+
+	return nil
+}
+
+// LockClusterDrainOperations marks ClusterDrainOperations as locked for exclusive use by the caller.
+func (sqlStore *SQLStore) LockClusterDrainOperations(ids []string, lockerID string) (bool, error) {
+	return sqlStore.lockRows(clusterDrainTable, ids, lockerID)
+}
+
+// UnlockClusterDrainOperations releases locks previously acquired against a caller.
+func (sqlStore *SQLStore) UnlockClusterDrainOperations(ids []string, lockerID string, force bool) (bool, error) {
+	return sqlStore.unlockRows(clusterDrainTable, ids, lockerID, force)
+}
+
+func (sqlStore *SQLStore) applyClusterDrainFilter(builder sq.SelectBuilder, filter *model.ClusterDrainFilter) sq.SelectBuilder {
+	builder = applyPagingFilter(builder, filter.Paging)
+
+	if len(filter.IDs) > 0 {
+		builder = builder.Where(sq.Eq{"ID": filter.IDs})
+	}
+	if filter.ClusterID != "" {
+		builder = builder.Where("ClusterID = ?", filter.ClusterID)
+	}
+	if len(filter.States) > 0 {
+		builder = builder.Where(sq.Eq{
+			"State": filter.States,
+		})
+	}
+
+	return builder
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTriggerClusterDrain(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	cluster := &model.Cluster{
+		State:              model.ClusterStateStable,
+		AllowInstallations: true,
+	}
+	err := sqlStore.CreateCluster(cluster, nil)
+	require.NoError(t, err)
+
+	drainOp := &model.ClusterDrainOperation{MaxConcurrentMigrations: 3}
+
+	drainOp, err = sqlStore.TriggerClusterDrain(drainOp, cluster)
+	require.NoError(t, err)
+	assert.NotEmpty(t, drainOp.ID)
+	assert.Equal(t, cluster.ID, drainOp.ClusterID)
+	assert.Equal(t, model.ClusterDrainStateRequested, drainOp.State)
+
+	fetchedOp, err := sqlStore.GetClusterDrainOperation(drainOp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, drainOp, fetchedOp)
+
+	cluster, err = sqlStore.GetCluster(cluster.ID)
+	require.NoError(t, err)
+	assert.False(t, cluster.AllowInstallations)
+	assert.Equal(t, model.ClusterStateStable, cluster.State)
+
+	t.Run("unknown cluster drain operation", func(t *testing.T) {
+		fetchedOp, err = sqlStore.GetClusterDrainOperation("unknown")
+		require.NoError(t, err)
+		assert.Nil(t, fetchedOp)
+	})
+}
+
+func TestGetClusterDrainOperations(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	drainOps := []*model.ClusterDrainOperation{
+		{ClusterID: "cluster1", State: model.ClusterDrainStateFailed},
+		{ClusterID: "cluster1", State: model.ClusterDrainStateInProgress},
+		{ClusterID: "cluster2", State: model.ClusterDrainStateRequested},
+		{ClusterID: "cluster3", State: model.ClusterDrainStateSucceeded},
+	}
+
+	for i := range drainOps {
+		err := sqlStore.CreateClusterDrainOperation(drainOps[i])
+		require.NoError(t, err)
+		time.Sleep(1 * time.Millisecond) // Ensure RequestAt is different for all operations.
+	}
+
+	for _, testCase := range []struct {
+		description string
+		filter      *model.ClusterDrainFilter
+		fetchedIds  []string
+	}{
+		{
+			description: "fetch all",
+			filter:      &model.ClusterDrainFilter{Paging: model.AllPagesNotDeleted()},
+			fetchedIds:  []string{drainOps[3].ID, drainOps[2].ID, drainOps[1].ID, drainOps[0].ID},
+		},
+		{
+			description: "fetch for cluster",
+			filter:      &model.ClusterDrainFilter{ClusterID: "cluster1", Paging: model.AllPagesNotDeleted()},
+			fetchedIds:  []string{drainOps[1].ID, drainOps[0].ID},
+		},
+		{
+			description: "fetch by state",
+			filter:      &model.ClusterDrainFilter{States: model.AllClusterDrainOperationsStatesPendingWork, Paging: model.AllPagesNotDeleted()},
+			fetchedIds:  []string{drainOps[2].ID, drainOps[1].ID},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			fetchedOps, err := sqlStore.GetClusterDrainOperations(testCase.filter)
+			require.NoError(t, err)
+			assert.Equal(t, len(testCase.fetchedIds), len(fetchedOps))
+
+			for i, op := range fetchedOps {
+				assert.Equal(t, testCase.fetchedIds[i], op.ID)
+			}
+		})
+	}
+
+	t.Run("pending work", func(t *testing.T) {
+		pendingOps, err := sqlStore.GetUnlockedClusterDrainOperationsPendingWork()
+		require.NoError(t, err)
+		assert.Equal(t, 2, len(pendingOps))
+
+		locked, err := sqlStore.LockClusterDrainOperations([]string{drainOps[1].ID}, "abc")
+		require.NoError(t, err)
+		assert.True(t, locked)
+
+		pendingOps, err = sqlStore.GetUnlockedClusterDrainOperationsPendingWork()
+		require.NoError(t, err)
+		assert.Equal(t, 1, len(pendingOps))
+	})
+}
+
+func TestUpdateClusterDrainOperation(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	drainOp := &model.ClusterDrainOperation{
+		ClusterID:               "cluster",
+		MaxConcurrentMigrations: 1,
+		State:                   model.ClusterDrainStateRequested,
+	}
+	err := sqlStore.CreateClusterDrainOperation(drainOp)
+	require.NoError(t, err)
+
+	drainOp.State = model.ClusterDrainStateInProgress
+	err = sqlStore.UpdateClusterDrainOperationState(drainOp)
+	require.NoError(t, err)
+
+	drainOp.State = model.ClusterDrainStateSucceeded
+	drainOp.MaxConcurrentMigrations = 2
+	drainOp.CompleteAt = 100
+	err = sqlStore.UpdateClusterDrainOperation(drainOp)
+	require.NoError(t, err)
+
+	fetchedOp, err := sqlStore.GetClusterDrainOperation(drainOp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, drainOp, fetchedOp)
+}
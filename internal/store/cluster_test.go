@@ -92,6 +92,28 @@ func TestClusters(t *testing.T) {
 		require.Equal(t, []*model.Cluster{cluster1, cluster2}, actualClusters)
 	})
 
+	t.Run("local cluster metadata", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := MakeTestSQLStore(t, logger)
+		defer CloseConnection(t, sqlStore)
+
+		cluster := &model.Cluster{
+			Provider:                 model.ProviderAWS,
+			Provisioner:              model.ProvisionerLocal,
+			ProvisionerMetadataLocal: &model.LocalMetadata{KubeconfigPath: "/tmp/kubeconfig"},
+			UtilityMetadata:          &model.UtilityMetadata{},
+			State:                    model.ClusterStateCreationRequested,
+		}
+
+		err := sqlStore.CreateCluster(cluster, nil)
+		require.NoError(t, err)
+
+		actualCluster, err := sqlStore.GetCluster(cluster.ID)
+		require.NoError(t, err)
+		require.Equal(t, cluster, actualCluster)
+		require.Nil(t, actualCluster.ProvisionerMetadataKops)
+	})
+
 	t.Run("update clusters", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := MakeTestSQLStore(t, logger)
@@ -499,6 +521,20 @@ func TestClustersAnnotationsFilter(t *testing.T) {
 		require.Equal(t, clusters[4], filteredClusters[0])
 	})
 
+	t.Run("filter for any: private, abcd", func(t *testing.T) {
+		filter := &model.ClusterFilter{
+			Paging:      model.AllPagesNotDeleted(),
+			Annotations: &model.AnnotationsFilter{MatchAnyIDs: []string{annotations[4].ID, annotations[0].ID}},
+		}
+
+		filteredClusters, err := sqlStore.GetClusters(filter)
+		require.NoError(t, err)
+		require.Equal(t, 3, len(filteredClusters))
+		require.Equal(t, clusters[2], filteredClusters[0])
+		require.Equal(t, clusters[4], filteredClusters[1])
+		require.Equal(t, clusters[6], filteredClusters[2])
+	})
+
 	t.Run("filter without annotations", func(t *testing.T) {
 		filter := &model.ClusterFilter{
 			Paging: model.AllPagesNotDeleted(),
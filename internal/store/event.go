@@ -0,0 +1,96 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	eventTable = "Event"
+)
+
+var eventSelect sq.SelectBuilder
+
+func init() {
+	eventSelect = sq.
+		Select("ID",
+			"ResourceType",
+			"ResourceID",
+			"OldState",
+			"NewState",
+			"Timestamp",
+			"InstanceID",
+			"Error",
+		).
+		From(eventTable)
+}
+
+// GetEvents fetches the given page of events, the newest first. The first page is 0.
+func (sqlStore *SQLStore) GetEvents(filter *model.EventFilter) ([]*model.Event, error) {
+	builder := eventSelect.
+		OrderBy("Timestamp DESC")
+
+	if filter.PerPage != model.AllPerPage {
+		builder = builder.
+			Limit(uint64(filter.PerPage)).
+			Offset(uint64(filter.Page * filter.PerPage))
+	}
+	if filter.ResourceType != "" {
+		builder = builder.Where("ResourceType = ?", filter.ResourceType)
+	}
+	if filter.ResourceID != "" {
+		builder = builder.Where("ResourceID = ?", filter.ResourceID)
+	}
+	if filter.OldState != "" {
+		builder = builder.Where("OldState = ?", filter.OldState)
+	}
+	if filter.NewState != "" {
+		builder = builder.Where("NewState = ?", filter.NewState)
+	}
+	if filter.Since != 0 {
+		builder = builder.Where("Timestamp >= ?", filter.Since)
+	}
+	if filter.Until != 0 {
+		builder = builder.Where("Timestamp <= ?", filter.Until)
+	}
+
+	var events []*model.Event
+	err := sqlStore.selectBuilder(sqlStore.db, &events, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for events")
+	}
+
+	return events, nil
+}
+
+// CreateEvent records the given event to the database, assigning it a unique ID.
+func (sqlStore *SQLStore) CreateEvent(event *model.Event) error {
+	event.ID = model.NewID()
+	if event.Timestamp == 0 {
+		event.Timestamp = GetMillis()
+	}
+
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Insert(eventTable).
+		SetMap(map[string]interface{}{
+			"ID":           event.ID,
+			"ResourceType": event.ResourceType,
+			"ResourceID":   event.ResourceID,
+			"OldState":     event.OldState,
+			"NewState":     event.NewState,
+			"Timestamp":    event.Timestamp,
+			"InstanceID":   event.InstanceID,
+			"Error":        event.Error,
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create event")
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvents(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+
+	installationID := model.NewID()
+
+	event1 := &model.Event{
+		ResourceType: model.TypeInstallation,
+		ResourceID:   installationID,
+		OldState:     model.InstallationStateCreationRequested,
+		NewState:     model.InstallationStateCreationInProgress,
+		Timestamp:    1000,
+		InstanceID:   "instance1",
+	}
+	err := sqlStore.CreateEvent(event1)
+	require.NoError(t, err)
+	require.NotEmpty(t, event1.ID)
+
+	event2 := &model.Event{
+		ResourceType: model.TypeInstallation,
+		ResourceID:   installationID,
+		OldState:     model.InstallationStateCreationInProgress,
+		NewState:     model.InstallationStateCreationFailed,
+		Timestamp:    2000,
+		InstanceID:   "instance2",
+		Error:        "failed to create installation",
+	}
+	err = sqlStore.CreateEvent(event2)
+	require.NoError(t, err)
+
+	event3 := &model.Event{
+		ResourceType: model.TypeCluster,
+		ResourceID:   model.NewID(),
+		OldState:     model.ClusterStateCreationRequested,
+		NewState:     model.ClusterStateStable,
+		InstanceID:   "instance1",
+	}
+	err = sqlStore.CreateEvent(event3)
+	require.NoError(t, err)
+	require.NotEqual(t, 0, event3.Timestamp)
+
+	t.Run("all events", func(t *testing.T) {
+		events, err := sqlStore.GetEvents(&model.EventFilter{
+			Paging: model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, []*model.Event{event3, event2, event1}, events)
+	})
+
+	t.Run("paging", func(t *testing.T) {
+		events, err := sqlStore.GetEvents(&model.EventFilter{
+			Paging: model.Paging{Page: 1, PerPage: 1},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []*model.Event{event2}, events)
+	})
+
+	t.Run("resource filters", func(t *testing.T) {
+		events, err := sqlStore.GetEvents(&model.EventFilter{
+			ResourceID: installationID,
+			Paging:     model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, []*model.Event{event2, event1}, events)
+
+		events, err = sqlStore.GetEvents(&model.EventFilter{
+			ResourceType: model.TypeCluster,
+			Paging:       model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, []*model.Event{event3}, events)
+	})
+
+	t.Run("state filters", func(t *testing.T) {
+		events, err := sqlStore.GetEvents(&model.EventFilter{
+			OldState: model.InstallationStateCreationInProgress,
+			Paging:   model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, []*model.Event{event2}, events)
+
+		events, err = sqlStore.GetEvents(&model.EventFilter{
+			NewState: model.InstallationStateCreationInProgress,
+			Paging:   model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, []*model.Event{event1}, events)
+	})
+
+	t.Run("time filters", func(t *testing.T) {
+		events, err := sqlStore.GetEvents(&model.EventFilter{
+			Since:  1500,
+			Until:  2500,
+			Paging: model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, []*model.Event{event2}, events)
+	})
+}
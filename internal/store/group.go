@@ -25,7 +25,9 @@ func init() {
 	groupSelect = sq.
 		Select("ID", "Name", "Description", "Version", "Image", "Sequence",
 			"CreateAt", "DeleteAt", "MattermostEnvRaw", "MaxRolling",
-			"APISecurityLock", "LockAcquiredBy", "LockAcquiredAt").
+			"APISecurityLock", "LockAcquiredBy", "LockAcquiredAt", "CanarySize",
+			"FailureThresholdPercent", "RolloutState", "Size", "License",
+			"Affinity", "Database", "Filestore").
 		From(`"Group"`)
 }
 
@@ -103,6 +105,7 @@ type GroupRollingMetadata struct {
 	InstallationIDsToBeRolled []string
 	InstallationsTotalCount   int64
 	InstallationsRolling      int64
+	Progress                  model.GroupRolloutProgress
 }
 
 // GetGroupRollingMetadata returns installation IDs and metadata related to
@@ -158,6 +161,12 @@ func (sqlStore *SQLStore) GetGroupRollingMetadata(groupID string) (*GroupRolling
 		return nil, errors.Errorf("found more stable+hibernating installations (%d) than total installations (%d)", count, metadata.InstallationsTotalCount)
 	}
 
+	progress, err := sqlStore.getGroupRolloutProgress(group)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get group rollout progress")
+	}
+	metadata.Progress = *progress
+
 	return metadata, nil
 }
 
@@ -229,15 +238,66 @@ func (sqlStore *SQLStore) GetGroupStatus(groupID string) (*model.GroupStatus, er
 
 	updatingCount := totalCount - updatedCount - awaitingUpdateCount - hibernatingCount
 
+	progress, err := sqlStore.getGroupRolloutProgress(group)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get group rollout progress")
+	}
+
 	return &model.GroupStatus{
 		InstallationsTotal:          totalCount,
 		InstallationsUpdated:        updatedCount,
 		InstallationsUpdating:       updatingCount,
 		InstallationsHibernating:    hibernatingCount,
 		InstallationsAwaitingUpdate: awaitingUpdateCount,
+		InstallationsFailed:         progress.InstallationsFailed,
+		RolloutPhase:                group.RolloutPhase(*progress),
 	}, nil
 }
 
+// getGroupRolloutProgress returns how far the current configuration of the
+// given group has been rolled out.
+func (sqlStore *SQLStore) getGroupRolloutProgress(group *model.Group) (*model.GroupRolloutProgress, error) {
+	var awaitingUpdateResult countResult
+	err := sqlStore.queryInstallationsToBeRolledOut(
+		[]string{"Count (*)"},
+		group,
+		&awaitingUpdateResult,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for count of installation to be rolled out")
+	}
+	awaitingUpdateCount, err := awaitingUpdateResult.value()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get count result of installations to be rolled out")
+	}
+
+	var stateCounts []stateCount
+	err = sqlStore.selectBuilder(sqlStore.db, &stateCounts, sq.
+		Select("State", "COUNT(*) AS Count").
+		From("Installation").
+		Where("GroupID = ?", group.ID).
+		Where("GroupSequence = ?", group.Sequence).
+		Where("DeleteAt = 0").
+		GroupBy("State"),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count rolled out installation states")
+	}
+
+	progress := &model.GroupRolloutProgress{InstallationsAwaitingUpdate: awaitingUpdateCount}
+	for _, stateCount := range stateCounts {
+		progress.InstallationsRolledOut += stateCount.Count
+		switch stateCount.State {
+		case model.InstallationStateStable, model.InstallationStateHibernating:
+			progress.InstallationsSucceeded += stateCount.Count
+		case model.InstallationStateUpdateFailed:
+			progress.InstallationsFailed += stateCount.Count
+		}
+	}
+
+	return progress, nil
+}
+
 func (sqlStore *SQLStore) queryInstallationsToBeRolledOut(columns []string, group *model.Group, dest interface{}) error {
 	builder := sq.
 		Select(columns...).
@@ -299,33 +359,58 @@ func (sqlStore *SQLStore) GetGroups(filter *model.GroupFilter) ([]*model.Group,
 func (sqlStore *SQLStore) CreateGroup(group *model.Group) error {
 	group.ID = model.NewID()
 	group.CreateAt = GetMillis()
+	group.RolloutState = model.GroupRolloutStateActive
 	envVarMap, err := group.MattermostEnv.ToJSON()
 	if err != nil {
 		return err
 	}
 
-	_, err = sqlStore.execBuilder(sqlStore.db, sq.
+	tx, err := sqlStore.beginTransaction(sqlStore.db)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	_, err = sqlStore.execBuilder(tx, sq.
 		Insert(`"Group"`).
 		SetMap(map[string]interface{}{
-			"ID":               group.ID,
-			"Sequence":         0,
-			"Name":             group.Name,
-			"Image":            group.Image,
-			"Description":      group.Description,
-			"Version":          group.Version,
-			"MattermostEnvRaw": envVarMap,
-			"MaxRolling":       group.MaxRolling,
-			"CreateAt":         group.CreateAt,
-			"DeleteAt":         0,
-			"APISecurityLock":  group.APISecurityLock,
-			"LockAcquiredBy":   nil,
-			"LockAcquiredAt":   0,
+			"ID":                      group.ID,
+			"Sequence":                0,
+			"Name":                    group.Name,
+			"Image":                   group.Image,
+			"Description":             group.Description,
+			"Version":                 group.Version,
+			"MattermostEnvRaw":        envVarMap,
+			"MaxRolling":              group.MaxRolling,
+			"CreateAt":                group.CreateAt,
+			"DeleteAt":                0,
+			"APISecurityLock":         group.APISecurityLock,
+			"LockAcquiredBy":          nil,
+			"LockAcquiredAt":          0,
+			"CanarySize":              group.CanarySize,
+			"RolloutState":            group.RolloutState,
+			"FailureThresholdPercent": group.FailureThresholdPercent,
+			"Size":                    group.Size,
+			"License":                 group.License,
+			"Affinity":                group.Affinity,
+			"Database":                group.Database,
+			"Filestore":               group.Filestore,
 		}),
 	)
 	if err != nil {
 		return errors.Wrap(err, "failed to create group")
 	}
 
+	err = sqlStore.createGroupConfig(tx, group)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Wrap(err, "failed to commit the transaction")
+	}
+
 	return nil
 }
 
@@ -351,23 +436,48 @@ func (sqlStore *SQLStore) UpdateGroup(group *model.Group, forceUpdateSequence bo
 	// - Name
 	// - Description
 	// - MaxRolling
-	if forceUpdateSequence ||
+	// - CanarySize
+	// - FailureThresholdPercent
+	// - Affinity, Database and Filestore, which only apply to new installations
+	//
+	// A new group sequence starts a new rollout, so a paused or aborted
+	// rollout becomes active again. The configuration of every sequence is
+	// recorded in the group config history.
+	sequenceUpdated := forceUpdateSequence ||
 		originalGroup.Version != group.Version ||
 		originalGroup.Image != group.Image ||
-		string(originalEnvVarMap) != string(envVarMap) {
+		originalGroup.Size != group.Size ||
+		originalGroup.License != group.License ||
+		string(originalEnvVarMap) != string(envVarMap)
+	if sequenceUpdated {
 		group.Sequence = originalGroup.Sequence + 1
+		group.RolloutState = model.GroupRolloutStateActive
 	}
 
-	_, err = sqlStore.execBuilder(sqlStore.db, sq.
+	tx, err := sqlStore.beginTransaction(sqlStore.db)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	_, err = sqlStore.execBuilder(tx, sq.
 		Update(`"Group"`).
 		SetMap(map[string]interface{}{
-			"Sequence":         group.Sequence,
-			"Name":             group.Name,
-			"Description":      group.Description,
-			"Version":          group.Version,
-			"Image":            group.Image,
-			"MattermostEnvRaw": envVarMap,
-			"MaxRolling":       group.MaxRolling,
+			"Sequence":                group.Sequence,
+			"Name":                    group.Name,
+			"Description":             group.Description,
+			"Version":                 group.Version,
+			"Image":                   group.Image,
+			"MattermostEnvRaw":        envVarMap,
+			"MaxRolling":              group.MaxRolling,
+			"CanarySize":              group.CanarySize,
+			"RolloutState":            group.RolloutState,
+			"FailureThresholdPercent": group.FailureThresholdPercent,
+			"Size":                    group.Size,
+			"License":                 group.License,
+			"Affinity":                group.Affinity,
+			"Database":                group.Database,
+			"Filestore":               group.Filestore,
 		}).
 		Where("ID = ?", group.ID),
 	)
@@ -375,6 +485,32 @@ func (sqlStore *SQLStore) UpdateGroup(group *model.Group, forceUpdateSequence bo
 		return errors.Wrap(err, "failed to update group")
 	}
 
+	if sequenceUpdated {
+		err = sqlStore.createGroupConfig(tx, group)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Wrap(err, "failed to commit the transaction")
+	}
+
+	return nil
+}
+
+// UpdateGroupRolloutState updates the rollout state of the given group.
+func (sqlStore *SQLStore) UpdateGroupRolloutState(groupID, rolloutState string) error {
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Update(`"Group"`).
+		Set("RolloutState", rolloutState).
+		Where("ID = ?", groupID),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to update group rollout state")
+	}
+
 	return nil
 }
 
@@ -0,0 +1,123 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	groupConfigTable = "GroupConfig"
+)
+
+var groupConfigSelect sq.SelectBuilder
+
+type rawGroupConfig struct {
+	*model.GroupConfig
+	MattermostEnvRaw []byte
+}
+
+type rawGroupConfigs []*rawGroupConfig
+
+func init() {
+	groupConfigSelect = sq.
+		Select("GroupID", "Sequence", "Version", "Image", "Size", "License",
+			"MattermostEnvRaw", "CreateAt").
+		From(groupConfigTable)
+}
+
+func (r *rawGroupConfig) toGroupConfig() (*model.GroupConfig, error) {
+	// We only need to set values that are converted from a raw database format.
+	if r.MattermostEnvRaw != nil {
+		mattermostEnv, err := model.EnvVarFromJSON(r.MattermostEnvRaw)
+		if err != nil {
+			return nil, err
+		}
+		r.GroupConfig.MattermostEnv = *mattermostEnv
+	}
+
+	return r.GroupConfig, nil
+}
+
+func (rs *rawGroupConfigs) toGroupConfigs() ([]*model.GroupConfig, error) {
+	var groupConfigs []*model.GroupConfig
+	for _, rawGroupConfig := range *rs {
+		groupConfig, err := rawGroupConfig.toGroupConfig()
+		if err != nil {
+			return nil, err
+		}
+		groupConfigs = append(groupConfigs, groupConfig)
+	}
+
+	return groupConfigs, nil
+}
+
+// GetGroupConfig fetches the configuration of the given group at the given
+// sequence.
+func (sqlStore *SQLStore) GetGroupConfig(groupID string, sequence int64) (*model.GroupConfig, error) {
+	var rawGroupConfig rawGroupConfig
+	err := sqlStore.getBuilder(sqlStore.db, &rawGroupConfig,
+		groupConfigSelect.
+			Where("GroupID = ?", groupID).
+			Where("Sequence = ?", sequence),
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get group config")
+	}
+
+	return rawGroupConfig.toGroupConfig()
+}
+
+// GetGroupConfigs fetches the configuration history of the given group, most
+// recent first.
+func (sqlStore *SQLStore) GetGroupConfigs(groupID string) ([]*model.GroupConfig, error) {
+	var rawGroupConfigs rawGroupConfigs
+	err := sqlStore.selectBuilder(sqlStore.db, &rawGroupConfigs,
+		groupConfigSelect.
+			Where("GroupID = ?", groupID).
+			OrderBy("Sequence DESC"),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for group configs")
+	}
+
+	return rawGroupConfigs.toGroupConfigs()
+}
+
+// createGroupConfig records the current configuration of the given group.
+func (sqlStore *SQLStore) createGroupConfig(db execer, group *model.Group) error {
+	groupConfig := model.NewGroupConfig(group)
+	groupConfig.CreateAt = GetMillis()
+
+	envVarMap, err := groupConfig.MattermostEnv.ToJSON()
+	if err != nil {
+		return err
+	}
+
+	_, err = sqlStore.execBuilder(db, sq.
+		Insert(groupConfigTable).
+		SetMap(map[string]interface{}{
+			"GroupID":          groupConfig.GroupID,
+			"Sequence":         groupConfig.Sequence,
+			"Version":          groupConfig.Version,
+			"Image":            groupConfig.Image,
+			"Size":             groupConfig.Size,
+			"License":          groupConfig.License,
+			"MattermostEnvRaw": envVarMap,
+			"CreateAt":         groupConfig.CreateAt,
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create group config")
+	}
+
+	return nil
+}
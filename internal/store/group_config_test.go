@@ -0,0 +1,82 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupConfigs(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	group := &model.Group{
+		Name:          "name1",
+		Version:       "version1",
+		Image:         "image1",
+		MattermostEnv: model.EnvVarMap{"KEY1": {Value: "value1"}},
+	}
+	err := sqlStore.CreateGroup(group)
+	require.NoError(t, err)
+
+	t.Run("created group", func(t *testing.T) {
+		groupConfig, err := sqlStore.GetGroupConfig(group.ID, 0)
+		require.NoError(t, err)
+		require.NotNil(t, groupConfig)
+		assert.Equal(t, group.ID, groupConfig.GroupID)
+		assert.Equal(t, "version1", groupConfig.Version)
+		assert.Equal(t, "image1", groupConfig.Image)
+		assert.Equal(t, group.MattermostEnv, groupConfig.MattermostEnv)
+		assert.NotEqual(t, 0, groupConfig.CreateAt)
+	})
+
+	t.Run("updates without a new sequence are not recorded", func(t *testing.T) {
+		group.Name = "name2"
+		group.MaxRolling = 5
+		err = sqlStore.UpdateGroup(group, false)
+		require.NoError(t, err)
+
+		groupConfigs, err := sqlStore.GetGroupConfigs(group.ID)
+		require.NoError(t, err)
+		require.Len(t, groupConfigs, 1)
+	})
+
+	t.Run("updates with a new sequence are recorded", func(t *testing.T) {
+		group.Version = "version2"
+		err = sqlStore.UpdateGroup(group, false)
+		require.NoError(t, err)
+
+		err = sqlStore.UpdateGroup(group, true)
+		require.NoError(t, err)
+
+		groupConfigs, err := sqlStore.GetGroupConfigs(group.ID)
+		require.NoError(t, err)
+		require.Len(t, groupConfigs, 3)
+		assert.Equal(t, int64(2), groupConfigs[0].Sequence)
+		assert.Equal(t, "version2", groupConfigs[0].Version)
+		assert.Equal(t, int64(1), groupConfigs[1].Sequence)
+		assert.Equal(t, "version2", groupConfigs[1].Version)
+		assert.Equal(t, int64(0), groupConfigs[2].Sequence)
+		assert.Equal(t, "version1", groupConfigs[2].Version)
+	})
+
+	t.Run("unknown sequence", func(t *testing.T) {
+		groupConfig, err := sqlStore.GetGroupConfig(group.ID, 9001)
+		require.NoError(t, err)
+		require.Nil(t, groupConfig)
+	})
+
+	t.Run("unknown group", func(t *testing.T) {
+		groupConfigs, err := sqlStore.GetGroupConfigs(model.NewID())
+		require.NoError(t, err)
+		require.Empty(t, groupConfigs)
+	})
+}
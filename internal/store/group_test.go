@@ -484,6 +484,7 @@ func TestGetGroupRollingMetadata(t *testing.T) {
 				InstallationIDsToBeRolled: []string{installation1.ID},
 				InstallationsTotalCount:   1,
 				InstallationsRolling:      0,
+				Progress:                  model.GroupRolloutProgress{InstallationsAwaitingUpdate: 1},
 			}
 			metadata, err := sqlStore.GetGroupRollingMetadata(group1.ID)
 			require.NoError(t, err)
@@ -569,6 +570,49 @@ func TestUpdateGroup(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, oldSequence, group1.Sequence)
 
+	group1.CanarySize = 2
+	group1.FailureThresholdPercent = 10
+	err = sqlStore.UpdateGroup(group1, false)
+	require.NoError(t, err)
+	assert.Equal(t, oldSequence, group1.Sequence)
+
+	group1.Affinity = model.InstallationAffinityMultiTenant
+	group1.Database = model.InstallationDatabaseMultiTenantRDSPostgres
+	group1.Filestore = model.InstallationFilestoreBifrost
+	err = sqlStore.UpdateGroup(group1, false)
+	require.NoError(t, err)
+	assert.Equal(t, oldSequence, group1.Sequence)
+
+	group1.Size = "1000users"
+	err = sqlStore.UpdateGroup(group1, false)
+	require.NoError(t, err)
+	assert.Equal(t, oldSequence+1, group1.Sequence)
+
+	oldSequence = group1.Sequence
+	group1.License = "license1"
+	err = sqlStore.UpdateGroup(group1, false)
+	require.NoError(t, err)
+	assert.Equal(t, oldSequence+1, group1.Sequence)
+
+	oldSequence = group1.Sequence
+
+	t.Run("new sequence resets the rollout state", func(t *testing.T) {
+		err = sqlStore.UpdateGroupRolloutState(group1.ID, model.GroupRolloutStatePaused)
+		require.NoError(t, err)
+		group1.RolloutState = model.GroupRolloutStatePaused
+
+		group1.Name = "name5"
+		err = sqlStore.UpdateGroup(group1, false)
+		require.NoError(t, err)
+		assert.Equal(t, model.GroupRolloutStatePaused, group1.RolloutState)
+
+		group1.Image = "image5"
+		err = sqlStore.UpdateGroup(group1, false)
+		require.NoError(t, err)
+		assert.Equal(t, oldSequence+1, group1.Sequence)
+		assert.Equal(t, model.GroupRolloutStateActive, group1.RolloutState)
+	})
+
 	actualGroup1, err := sqlStore.GetGroup(group1.ID)
 	require.NoError(t, err)
 	assert.Equal(t, group1, actualGroup1)
@@ -646,6 +690,7 @@ func TestGetGroupStatus(t *testing.T) {
 			InstallationsTotal:          0,
 			InstallationsUpdated:        0,
 			InstallationsAwaitingUpdate: 0,
+			RolloutPhase:                model.GroupRolloutPhaseComplete,
 		}
 		groupStatus, err := sqlStore.GetGroupStatus(group1.ID)
 		require.NoError(t, err)
@@ -676,6 +721,7 @@ func TestGetGroupStatus(t *testing.T) {
 			InstallationsUpdated:        0,
 			InstallationsUpdating:       1,
 			InstallationsAwaitingUpdate: 0,
+			RolloutPhase:                model.GroupRolloutPhaseComplete,
 		}
 		groupStatus, err := sqlStore.GetGroupStatus(group1.ID)
 		require.NoError(t, err)
@@ -693,6 +739,7 @@ func TestGetGroupStatus(t *testing.T) {
 			InstallationsUpdated:        0,
 			InstallationsUpdating:       0,
 			InstallationsAwaitingUpdate: 1,
+			RolloutPhase:                model.GroupRolloutPhasePaused,
 		}
 		groupStatus, err = sqlStore.GetGroupStatus(group1.ID)
 		require.NoError(t, err)
@@ -707,9 +754,33 @@ func TestGetGroupStatus(t *testing.T) {
 			InstallationsTotal:          1,
 			InstallationsUpdated:        1,
 			InstallationsAwaitingUpdate: 0,
+			RolloutPhase:                model.GroupRolloutPhaseComplete,
+		}
+		groupStatus, err = sqlStore.GetGroupStatus(group1.ID)
+		require.NoError(t, err)
+		assert.Equal(t, expectedStatus, groupStatus)
+
+		// rolled out and failed
+		installation1.State = model.InstallationStateUpdateFailed
+		err = sqlStore.UpdateInstallation(installation1)
+		require.NoError(t, err)
+
+		expectedStatus = &model.GroupStatus{
+			InstallationsTotal:    1,
+			InstallationsUpdating: 1,
+			InstallationsFailed:   1,
+			RolloutPhase:          model.GroupRolloutPhaseComplete,
 		}
 		groupStatus, err = sqlStore.GetGroupStatus(group1.ID)
 		require.NoError(t, err)
 		assert.Equal(t, expectedStatus, groupStatus)
+
+		// rollout aborted
+		err = sqlStore.UpdateGroupRolloutState(group1.ID, model.GroupRolloutStateAborted)
+		require.NoError(t, err)
+
+		groupStatus, err = sqlStore.GetGroupStatus(group1.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.GroupRolloutPhaseAborted, groupStatus.RolloutPhase)
 	})
 }
@@ -8,6 +8,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/mattermost/mattermost-cloud/model"
@@ -20,9 +21,10 @@ func init() {
 	installationSelect = sq.
 		Select(
 			"Installation.ID", "OwnerID", "Version", "Image", "DNS", "Database", "Filestore", "Size",
-			"Affinity", "GroupID", "GroupSequence", "State", "License",
+			"Affinity", "SchedulingPolicy", "GroupID", "GroupSequence", "State", "License",
 			"MattermostEnvRaw", "SingleTenantDatabaseConfigRaw", "CreateAt", "DeleteAt",
 			"APISecurityLock", "LockAcquiredBy", "LockAcquiredAt", "CRVersion",
+			"HibernationNoticeAt",
 		).
 		From("Installation")
 }
@@ -105,8 +107,7 @@ func (sqlStore *SQLStore) GetInstallation(id string, includeGroupConfig, include
 
 // GetInstallations fetches the given page of created installations. The first page is 0.
 func (sqlStore *SQLStore) GetInstallations(filter *model.InstallationFilter, includeGroupConfig, includeGroupConfigOverrides bool) ([]*model.Installation, error) {
-	builder := installationSelect.
-		OrderBy("CreateAt ASC")
+	builder := applyInstallationSort(installationSelect, filter)
 	builder = sqlStore.applyInstallationFilter(builder, filter)
 
 	var rawInstallations rawInstallations
@@ -155,10 +156,121 @@ func (sqlStore *SQLStore) applyInstallationFilter(builder sq.SelectBuilder, filt
 	if filter.DNS != "" {
 		builder = builder.Where("DNS = ?", filter.DNS)
 	}
+	if filter.DNSPattern != "" {
+		builder = builder.Where(`DNS LIKE ? ESCAPE '\'`, dnsPatternToLike(filter.DNSPattern))
+	}
+	if filter.DNSContains != "" {
+		builder = builder.Where(`DNS LIKE ? ESCAPE '\'`, "%"+escapeLike(filter.DNSContains)+"%")
+	}
+	if filter.Version != "" {
+		builder = builder.Where("Version = ?", filter.Version)
+	}
+	if filter.Image != "" {
+		builder = builder.Where("Image = ?", filter.Image)
+	}
+	if filter.Database != "" {
+		builder = builder.Where("Database = ?", filter.Database)
+	}
+	if filter.Filestore != "" {
+		builder = builder.Where("Filestore = ?", filter.Filestore)
+	}
+	if filter.Size != "" {
+		builder = builder.Where("Size = ?", filter.Size)
+	}
+	if filter.Affinity != "" {
+		builder = builder.Where("Affinity = ?", filter.Affinity)
+	}
+	if filter.ClusterID != "" {
+		builder = builder.Where(
+			"Installation.ID IN (SELECT ClusterInstallation.InstallationID FROM ClusterInstallation WHERE ClusterInstallation.ClusterID = ? AND ClusterInstallation.DeleteAt = 0)",
+			filter.ClusterID,
+		)
+	}
+	if filter.CreatedAfter > 0 {
+		builder = builder.Where("Installation.CreateAt > ?", filter.CreatedAfter)
+	}
+	if filter.CreatedBefore > 0 {
+		builder = builder.Where("Installation.CreateAt < ?", filter.CreatedBefore)
+	}
+	if filter.Annotations != nil && len(filter.Annotations.MatchAllIDs) > 0 {
+		// The annotations are matched in a subquery, so that the filter can
+		// be applied to queries already joining the annotation tables.
+		annotatedQuery, args, _ := sq.
+			Select(fmt.Sprintf("%s.InstallationID", installationAnnotationTable)).
+			From(installationAnnotationTable).
+			Where(sq.Eq{fmt.Sprintf("%s.AnnotationID", installationAnnotationTable): filter.Annotations.MatchAllIDs}).
+			GroupBy(fmt.Sprintf("%s.InstallationID", installationAnnotationTable)).
+			Having(fmt.Sprintf("count(DISTINCT %s.AnnotationID) = ?", installationAnnotationTable), len(filter.Annotations.MatchAllIDs)).
+			ToSql()
+		builder = builder.Where(fmt.Sprintf("Installation.ID IN (%s)", annotatedQuery), args...)
+	}
+	if filter.Annotations != nil && len(filter.Annotations.MatchAnyIDs) > 0 {
+		annotatedQuery, args, _ := sq.
+			Select(fmt.Sprintf("%s.InstallationID", installationAnnotationTable)).
+			From(installationAnnotationTable).
+			Where(sq.Eq{fmt.Sprintf("%s.AnnotationID", installationAnnotationTable): filter.Annotations.MatchAnyIDs}).
+			ToSql()
+		builder = builder.Where(fmt.Sprintf("Installation.ID IN (%s)", annotatedQuery), args...)
+	}
 
 	return builder
 }
 
+// installationSortColumns maps the supported sort fields to their columns.
+var installationSortColumns = map[string]string{
+	model.InstallationSortByCreateAt: "CreateAt",
+	model.InstallationSortByDNS:      "DNS",
+	model.InstallationSortByVersion:  "Version",
+	model.InstallationSortByState:    "State",
+	model.InstallationSortByOwner:    "OwnerID",
+}
+
+// applyInstallationSort orders the installations as requested by the filter
+// and skips installations up to and including the After cursor. The ID is
+// always used as a tie-breaker, so that the order is stable across pages.
+func applyInstallationSort(builder sq.SelectBuilder, filter *model.InstallationFilter) sq.SelectBuilder {
+	column, ok := installationSortColumns[filter.SortBy]
+	if !ok {
+		column = installationSortColumns[model.InstallationSortByCreateAt]
+	}
+
+	direction, comparison := "ASC", ">"
+	if filter.SortOrder == model.SortOrderDesc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if filter.After != "" {
+		// The sort value of the cursor is looked up in a subquery, so that
+		// only the ID of the last installation needs to be passed around.
+		builder = builder.Where(
+			fmt.Sprintf(
+				"(Installation.%[1]s %[2]s %[3]s OR (Installation.%[1]s = %[3]s AND Installation.ID %[2]s ?))",
+				column, comparison,
+				fmt.Sprintf("(SELECT AfterInstallation.%s FROM Installation AfterInstallation WHERE AfterInstallation.ID = ?)", column),
+			),
+			filter.After, filter.After, filter.After,
+		)
+	}
+
+	return builder.OrderBy(
+		fmt.Sprintf("Installation.%s %s", column, direction),
+		fmt.Sprintf("Installation.ID %s", direction),
+	)
+}
+
+// dnsPatternToLike converts a DNS pattern, where * matches any sequence of
+// characters, to a LIKE pattern escaped with a backslash.
+func dnsPatternToLike(pattern string) string {
+	return strings.ReplaceAll(escapeLike(pattern), "*", "%")
+}
+
+// escapeLike escapes the LIKE wildcards in the given value with a backslash.
+func escapeLike(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+	return replacer.Replace(value)
+}
+
 // GetInstallationsCount returns the number of installations filtered by the
 // deleteAt field.
 func (sqlStore *SQLStore) GetInstallationsCount(includeDeleted bool) (int64, error) {
@@ -298,7 +410,21 @@ func (sqlStore *SQLStore) CreateInstallation(installation *model.Installation, a
 	}
 	defer tx.RollbackUnlessCommitted()
 
-	err = sqlStore.createInstallation(tx, installation)
+	err = sqlStore.createInstallationWithAnnotations(tx, installation, annotations)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Wrap(err, "failed to commit the transaction")
+	}
+
+	return nil
+}
+
+func (sqlStore *SQLStore) createInstallationWithAnnotations(tx *Transaction, installation *model.Installation, annotations []*model.Annotation) error {
+	err := sqlStore.createInstallation(tx, installation)
 	if err != nil {
 		return errors.Wrap(err, "failed to create installation")
 	}
@@ -315,11 +441,6 @@ func (sqlStore *SQLStore) CreateInstallation(installation *model.Installation, a
 		}
 	}
 
-	err = tx.Commit()
-	if err != nil {
-		return errors.Wrap(err, "failed to commit the transaction")
-	}
-
 	return nil
 }
 
@@ -344,6 +465,7 @@ func (sqlStore *SQLStore) createInstallation(db execer, installation *model.Inst
 		"Filestore":        installation.Filestore,
 		"Size":             installation.Size,
 		"Affinity":         installation.Affinity,
+		"SchedulingPolicy": installation.SchedulingPolicy,
 		"State":            installation.State,
 		"License":          installation.License,
 		"MattermostEnvRaw": []byte(envJSON),
@@ -403,6 +525,7 @@ func (sqlStore *SQLStore) updateInstallation(db execer, installation *model.Inst
 			"Filestore":        installation.Filestore,
 			"Size":             installation.Size,
 			"Affinity":         installation.Affinity,
+			"SchedulingPolicy": installation.SchedulingPolicy,
 			"License":          installation.License,
 			"MattermostEnvRaw": []byte(envJSON),
 			"State":            installation.State,
@@ -471,6 +594,24 @@ func (sqlStore *SQLStore) UpdateInstallationCRVersion(installationID, crVersion
 	return nil
 }
 
+// UpdateInstallationHibernationNotice updates the time at which the given
+// installation was notified about being hibernated for inactivity. A value
+// of 0 clears the notice.
+func (sqlStore *SQLStore) UpdateInstallationHibernationNotice(installationID string, noticeAt int64) error {
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Update("Installation").
+		SetMap(map[string]interface{}{
+			"HibernationNoticeAt": noticeAt,
+		}).
+		Where("ID = ?", installationID),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to update installation hibernation notice")
+	}
+
+	return nil
+}
+
 // GetInstallationsTotalDatabaseWeight returns the total weight value of the
 // provided installations.
 func (sqlStore *SQLStore) GetInstallationsTotalDatabaseWeight(installationIDs []string) (float64, error) {
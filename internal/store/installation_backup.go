@@ -25,6 +25,7 @@ func init() {
 		Select("ID",
 			"InstallationID",
 			"ClusterInstallationID",
+			"ScheduleID",
 			"DataResidenceRaw",
 			"State",
 			"RequestAt",
@@ -153,6 +154,7 @@ func (sqlStore *SQLStore) CreateInstallationBackup(backup *model.InstallationBac
 			"ID":                    backup.ID,
 			"InstallationID":        backup.InstallationID,
 			"ClusterInstallationID": backup.ClusterInstallationID,
+			"ScheduleID":            backup.ScheduleID,
 			"DataResidenceRaw":      nil,
 			"State":                 backup.State,
 			"RequestAt":             backup.RequestAt,
@@ -329,6 +331,9 @@ func (sqlStore *SQLStore) applyInstallationBackupFilter(builder sq.SelectBuilder
 	if filter.ClusterInstallationID != "" {
 		builder = builder.Where("ClusterInstallationID = ?", filter.ClusterInstallationID)
 	}
+	if filter.ScheduleID != "" {
+		builder = builder.Where("ScheduleID = ?", filter.ScheduleID)
+	}
 	if len(filter.States) > 0 {
 		builder = builder.Where(sq.Eq{
 			"State": filter.States,
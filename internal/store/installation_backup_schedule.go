@@ -0,0 +1,171 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	backupScheduleTable = "InstallationBackupSchedule"
+)
+
+var installationBackupScheduleSelect sq.SelectBuilder
+
+func init() {
+	installationBackupScheduleSelect = sq.
+		Select("ID",
+			"InstallationID",
+			"GroupID",
+			"IntervalSeconds",
+			"RetentionKeepLast",
+			"RetentionKeepDailyDays",
+			"LastBackupAt",
+			"NextBackupAt",
+			"LastError",
+			"CreateAt",
+			"DeleteAt",
+			"LockAcquiredBy",
+			"LockAcquiredAt",
+		).
+		From(backupScheduleTable)
+}
+
+// CreateInstallationBackupSchedule records the given backup schedule to the
+// database, assigning it a unique ID. The first backup is due immediately.
+func (sqlStore *SQLStore) CreateInstallationBackupSchedule(schedule *model.InstallationBackupSchedule) error {
+	schedule.ID = model.NewID()
+	schedule.CreateAt = GetMillis()
+	schedule.NextBackupAt = schedule.CreateAt
+
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Insert(backupScheduleTable).
+		SetMap(map[string]interface{}{
+			"ID":                     schedule.ID,
+			"InstallationID":         schedule.InstallationID,
+			"GroupID":                schedule.GroupID,
+			"IntervalSeconds":        schedule.IntervalSeconds,
+			"RetentionKeepLast":      schedule.RetentionKeepLast,
+			"RetentionKeepDailyDays": schedule.RetentionKeepDailyDays,
+			"LastBackupAt":           0,
+			"NextBackupAt":           schedule.NextBackupAt,
+			"LastError":              "",
+			"CreateAt":               schedule.CreateAt,
+			"DeleteAt":               0,
+			"LockAcquiredBy":         nil,
+			"LockAcquiredAt":         0,
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create backup schedule")
+	}
+
+	return nil
+}
+
+// GetInstallationBackupSchedule fetches the given backup schedule by id.
+func (sqlStore *SQLStore) GetInstallationBackupSchedule(id string) (*model.InstallationBackupSchedule, error) {
+	var schedule model.InstallationBackupSchedule
+	err := sqlStore.getBuilder(sqlStore.db, &schedule,
+		installationBackupScheduleSelect.Where("ID = ?", id),
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get backup schedule by id")
+	}
+
+	return &schedule, nil
+}
+
+// GetInstallationBackupSchedules fetches the given page of backup schedules. The first page is 0.
+func (sqlStore *SQLStore) GetInstallationBackupSchedules(filter *model.InstallationBackupScheduleFilter) ([]*model.InstallationBackupSchedule, error) {
+	builder := installationBackupScheduleSelect.
+		OrderBy("CreateAt ASC")
+	builder = applyPagingFilter(builder, filter.Paging)
+
+	if filter.InstallationID != "" {
+		builder = builder.Where("InstallationID = ?", filter.InstallationID)
+	}
+	if filter.GroupID != "" {
+		builder = builder.Where("GroupID = ?", filter.GroupID)
+	}
+
+	var schedules []*model.InstallationBackupSchedule
+	err := sqlStore.selectBuilder(sqlStore.db, &schedules, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for backup schedules")
+	}
+
+	return schedules, nil
+}
+
+// GetUnlockedInstallationBackupSchedulesPendingWork returns all unlocked
+// backup schedules which are not deleted. Retention rules are applied on
+// every pass, so every active schedule is considered pending work.
+func (sqlStore *SQLStore) GetUnlockedInstallationBackupSchedulesPendingWork() ([]*model.InstallationBackupSchedule, error) {
+	builder := installationBackupScheduleSelect.
+		Where("DeleteAt = 0").
+		Where("LockAcquiredAt = 0").
+		OrderBy("NextBackupAt ASC")
+
+	var schedules []*model.InstallationBackupSchedule
+	err := sqlStore.selectBuilder(sqlStore.db, &schedules, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get backup schedules pending work")
+	}
+
+	return schedules, nil
+}
+
+// UpdateInstallationBackupScheduleBackupTimes updates the time of the last
+// and of the next backup of the given schedule, along with the error of the
+// last scheduled run.
+func (sqlStore *SQLStore) UpdateInstallationBackupScheduleBackupTimes(schedule *model.InstallationBackupSchedule) error {
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Update(backupScheduleTable).
+		SetMap(map[string]interface{}{
+			"LastBackupAt": schedule.LastBackupAt,
+			"NextBackupAt": schedule.NextBackupAt,
+			"LastError":    schedule.LastError,
+		}).
+		Where("ID = ?", schedule.ID),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to update backup schedule times")
+	}
+
+	return nil
+}
+
+// DeleteInstallationBackupSchedule marks the given backup schedule as deleted,
+// but does not remove the record from the database.
+func (sqlStore *SQLStore) DeleteInstallationBackupSchedule(id string) error {
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Update(backupScheduleTable).
+		Set("DeleteAt", GetMillis()).
+		Where("ID = ?", id).
+		Where("DeleteAt = 0"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to mark backup schedule as deleted")
+	}
+
+	return nil
+}
+
+// LockInstallationBackupSchedule marks the backup schedule as locked for exclusive use by the caller.
+func (sqlStore *SQLStore) LockInstallationBackupSchedule(scheduleID, lockerID string) (bool, error) {
+	return sqlStore.lockRows(backupScheduleTable, []string{scheduleID}, lockerID)
+}
+
+// UnlockInstallationBackupSchedule releases a lock previously acquired against a caller.
+func (sqlStore *SQLStore) UnlockInstallationBackupSchedule(scheduleID, lockerID string, force bool) (bool, error) {
+	return sqlStore.unlockRows(backupScheduleTable, []string{scheduleID}, lockerID, force)
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallationBackupSchedules(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	schedule1 := &model.InstallationBackupSchedule{
+		InstallationID:    model.NewID(),
+		IntervalSeconds:   3600,
+		RetentionKeepLast: 3,
+	}
+	err := sqlStore.CreateInstallationBackupSchedule(schedule1)
+	require.NoError(t, err)
+	require.NotEmpty(t, schedule1.ID)
+	assert.Equal(t, schedule1.CreateAt, schedule1.NextBackupAt)
+
+	time.Sleep(1 * time.Millisecond)
+
+	schedule2 := &model.InstallationBackupSchedule{
+		GroupID:                model.NewID(),
+		IntervalSeconds:        86400,
+		RetentionKeepDailyDays: 7,
+	}
+	err = sqlStore.CreateInstallationBackupSchedule(schedule2)
+	require.NoError(t, err)
+
+	t.Run("get schedule", func(t *testing.T) {
+		schedule, err := sqlStore.GetInstallationBackupSchedule(schedule1.ID)
+		require.NoError(t, err)
+		assert.Equal(t, schedule1, schedule)
+	})
+
+	t.Run("get unknown schedule", func(t *testing.T) {
+		schedule, err := sqlStore.GetInstallationBackupSchedule(model.NewID())
+		require.NoError(t, err)
+		assert.Nil(t, schedule)
+	})
+
+	t.Run("get schedules", func(t *testing.T) {
+		schedules, err := sqlStore.GetInstallationBackupSchedules(&model.InstallationBackupScheduleFilter{Paging: model.AllPagesNotDeleted()})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.InstallationBackupSchedule{schedule1, schedule2}, schedules)
+
+		schedules, err = sqlStore.GetInstallationBackupSchedules(&model.InstallationBackupScheduleFilter{
+			GroupID: schedule2.GroupID,
+			Paging:  model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.InstallationBackupSchedule{schedule2}, schedules)
+
+		schedules, err = sqlStore.GetInstallationBackupSchedules(&model.InstallationBackupScheduleFilter{
+			InstallationID: schedule1.InstallationID,
+			Paging:         model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.InstallationBackupSchedule{schedule1}, schedules)
+	})
+
+	t.Run("update backup times", func(t *testing.T) {
+		schedule1.LastBackupAt = GetMillis()
+		schedule1.NextBackupAt = schedule1.LastBackupAt + 3600*1000
+		schedule1.LastError = "installation is not hibernated"
+		err := sqlStore.UpdateInstallationBackupScheduleBackupTimes(schedule1)
+		require.NoError(t, err)
+
+		schedule, err := sqlStore.GetInstallationBackupSchedule(schedule1.ID)
+		require.NoError(t, err)
+		assert.Equal(t, schedule1, schedule)
+	})
+
+	t.Run("pending work", func(t *testing.T) {
+		schedules, err := sqlStore.GetUnlockedInstallationBackupSchedulesPendingWork()
+		require.NoError(t, err)
+		assert.Equal(t, []*model.InstallationBackupSchedule{schedule2, schedule1}, schedules)
+
+		locked, err := sqlStore.LockInstallationBackupSchedule(schedule2.ID, "locker")
+		require.NoError(t, err)
+		require.True(t, locked)
+
+		schedules, err = sqlStore.GetUnlockedInstallationBackupSchedulesPendingWork()
+		require.NoError(t, err)
+		assert.Equal(t, []*model.InstallationBackupSchedule{schedule1}, schedules)
+
+		unlocked, err := sqlStore.UnlockInstallationBackupSchedule(schedule2.ID, "locker", false)
+		require.NoError(t, err)
+		require.True(t, unlocked)
+	})
+
+	t.Run("delete schedule", func(t *testing.T) {
+		err := sqlStore.DeleteInstallationBackupSchedule(schedule1.ID)
+		require.NoError(t, err)
+
+		schedule, err := sqlStore.GetInstallationBackupSchedule(schedule1.ID)
+		require.NoError(t, err)
+		assert.True(t, schedule.IsDeleted())
+
+		schedules, err := sqlStore.GetInstallationBackupSchedules(&model.InstallationBackupScheduleFilter{Paging: model.AllPagesNotDeleted()})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.InstallationBackupSchedule{schedule2}, schedules)
+
+		schedules, err = sqlStore.GetUnlockedInstallationBackupSchedulesPendingWork()
+		require.NoError(t, err)
+		assert.Equal(t, []*model.InstallationBackupSchedule{schedule2}, schedules)
+	})
+}
@@ -0,0 +1,221 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	installationCloneTable = "InstallationCloneOperation"
+)
+
+var installationCloneSelect sq.SelectBuilder
+
+func init() {
+	installationCloneSelect = sq.
+		Select("ID",
+			"InstallationID",
+			"CloneInstallationID",
+			"RequestAt",
+			"State",
+			"BackupID",
+			"InstallationDBRestorationOperationID",
+			"CompleteAt",
+			"DeleteAt",
+			"LockAcquiredBy",
+			"LockAcquiredAt",
+		).
+		From(installationCloneTable)
+}
+
+// TriggerInstallationClone creates the clone Installation together with new
+// InstallationCloneOperation in Requested state.
+func (sqlStore *SQLStore) TriggerInstallationClone(source, clone *model.Installation, annotations []*model.Annotation) (*model.InstallationCloneOperation, error) {
+	tx, err := sqlStore.beginTransaction(sqlStore.db)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to start transaction")
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	err = sqlStore.createInstallationWithAnnotations(tx, clone, annotations)
+	if err != nil {
+		return nil, err
+	}
+
+	cloneOp := &model.InstallationCloneOperation{
+		InstallationID:      source.ID,
+		CloneInstallationID: clone.ID,
+		State:               model.InstallationCloneStateRequested,
+	}
+	err = sqlStore.createInstallationClone(tx, cloneOp)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create installation clone operation")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return cloneOp, nil
+}
+
+// CreateInstallationCloneOperation records installation clone operation to the database, assigning it a unique ID.
+func (sqlStore *SQLStore) CreateInstallationCloneOperation(cloneOp *model.InstallationCloneOperation) error {
+	return sqlStore.createInstallationClone(sqlStore.db, cloneOp)
+}
+
+func (sqlStore *SQLStore) createInstallationClone(db execer, cloneOp *model.InstallationCloneOperation) error {
+	cloneOp.ID = model.NewID()
+	cloneOp.RequestAt = GetMillis()
+
+	_, err := sqlStore.execBuilder(db, sq.
+		Insert(installationCloneTable).
+		SetMap(map[string]interface{}{
+			"ID":                                   cloneOp.ID,
+			"InstallationID":                       cloneOp.InstallationID,
+			"CloneInstallationID":                  cloneOp.CloneInstallationID,
+			"RequestAt":                            cloneOp.RequestAt,
+			"State":                                cloneOp.State,
+			"BackupID":                             cloneOp.BackupID,
+			"InstallationDBRestorationOperationID": cloneOp.InstallationDBRestorationOperationID,
+			"CompleteAt":                           cloneOp.CompleteAt,
+			"DeleteAt":                             0,
+			"LockAcquiredBy":                       nil,
+			"LockAcquiredAt":                       0,
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create installation clone operation")
+	}
+
+	return nil
+}
+
+// GetInstallationCloneOperation fetches the given installation clone operation.
+func (sqlStore *SQLStore) GetInstallationCloneOperation(id string) (*model.InstallationCloneOperation, error) {
+	builder := installationCloneSelect.
+		Where("ID = ?", id)
+
+	var cloneOp model.InstallationCloneOperation
+	err := sqlStore.getBuilder(sqlStore.db, &cloneOp, builder)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to query for installation clone operation")
+	}
+
+	return &cloneOp, nil
+}
+
+// GetInstallationCloneOperations fetches the given page of installation clone operations. The first page is 0.
+func (sqlStore *SQLStore) GetInstallationCloneOperations(filter *model.InstallationCloneFilter) ([]*model.InstallationCloneOperation, error) {
+	builder := installationCloneSelect.
+		OrderBy("RequestAt DESC")
+	builder = sqlStore.applyInstallationCloneFilter(builder, filter)
+
+	return sqlStore.getInstallationCloneOperations(builder)
+}
+
+// GetUnlockedInstallationCloneOperationsPendingWork returns unlocked installation clone operations in a pending state.
+func (sqlStore *SQLStore) GetUnlockedInstallationCloneOperationsPendingWork() ([]*model.InstallationCloneOperation, error) {
+	builder := installationCloneSelect.
+		Where(sq.Eq{
+			"State": model.AllInstallationCloneOperationsStatesPendingWork,
+		}).
+		Where("LockAcquiredAt = 0").
+		OrderBy("RequestAt ASC")
+
+	return sqlStore.getInstallationCloneOperations(builder)
+}
+
+func (sqlStore *SQLStore) getInstallationCloneOperations(builder builder) ([]*model.InstallationCloneOperation, error) {
+	var cloneOps []*model.InstallationCloneOperation
+	err := sqlStore.selectBuilder(sqlStore.db, &cloneOps, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for installation clone operations")
+	}
+
+	return cloneOps, nil
+}
+
+// UpdateInstallationCloneOperationState updates the given installation clone operation state.
+func (sqlStore *SQLStore) UpdateInstallationCloneOperationState(cloneOp *model.InstallationCloneOperation) error {
+	return sqlStore.updateInstallationCloneFields(
+		sqlStore.db,
+		cloneOp.ID, map[string]interface{}{
+			"State": cloneOp.State,
+		})
+}
+
+// UpdateInstallationCloneOperation updates the given installation clone operation.
+func (sqlStore *SQLStore) UpdateInstallationCloneOperation(cloneOp *model.InstallationCloneOperation) error {
+	return sqlStore.updateInstallationCloneFields(
+		sqlStore.db,
+		cloneOp.ID, map[string]interface{}{
+			"State":                                cloneOp.State,
+			"BackupID":                             cloneOp.BackupID,
+			"InstallationDBRestorationOperationID": cloneOp.InstallationDBRestorationOperationID,
+			"CompleteAt":                           cloneOp.CompleteAt,
+		})
+}
+
+func (sqlStore *SQLStore) updateInstallationCloneFields(db execer, id string, fields map[string]interface{}) error {
+	_, err := sqlStore.execBuilder(db, sq.
+		Update(installationCloneTable).
+		SetMap(fields).
+		Where("ID = ?", id))
+	if err != nil {
+		return errors.Wrapf(err, "failed to update installation clone operation fields: %s", getMapKeys(fields))
+	}
+
+	return nil
+}
+
+// LockInstallationCloneOperation marks the InstallationCloneOperation as locked for exclusive use by the caller.
+func (sqlStore *SQLStore) LockInstallationCloneOperation(id, lockerID string) (bool, error) {
+	return sqlStore.lockRows(installationCloneTable, []string{id}, lockerID)
+}
+
+// LockInstallationCloneOperations marks InstallationCloneOperations as locked for exclusive use by the caller.
+func (sqlStore *SQLStore) LockInstallationCloneOperations(ids []string, lockerID string) (bool, error) {
+	return sqlStore.lockRows(installationCloneTable, ids, lockerID)
+}
+
+// UnlockInstallationCloneOperation releases a lock previously acquired against a caller.
+func (sqlStore *SQLStore) UnlockInstallationCloneOperation(id, lockerID string, force bool) (bool, error) {
+	return sqlStore.unlockRows(installationCloneTable, []string{id}, lockerID, force)
+}
+
+// UnlockInstallationCloneOperations releases locks previously acquired against a caller.
+func (sqlStore *SQLStore) UnlockInstallationCloneOperations(ids []string, lockerID string, force bool) (bool, error) {
+	return sqlStore.unlockRows(installationCloneTable, ids, lockerID, force)
+}
+
+func (sqlStore *SQLStore) applyInstallationCloneFilter(builder sq.SelectBuilder, filter *model.InstallationCloneFilter) sq.SelectBuilder {
+	builder = applyPagingFilter(builder, filter.Paging)
+
+	if len(filter.IDs) > 0 {
+		builder = builder.Where(sq.Eq{"ID": filter.IDs})
+	}
+	if filter.InstallationID != "" {
+		builder = builder.Where("InstallationID = ?", filter.InstallationID)
+	}
+	if filter.CloneInstallationID != "" {
+		builder = builder.Where("CloneInstallationID = ?", filter.CloneInstallationID)
+	}
+	if len(filter.States) > 0 {
+		builder = builder.Where(sq.Eq{
+			"State": filter.States,
+		})
+	}
+
+	return builder
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTriggerInstallationClone(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	source := setupHibernatingInstallation(t, sqlStore)
+
+	clone := model.NewInstallationCloneFromSource(source, &model.InstallationCloneRequest{
+		DNS:     "clone.example.com",
+		OwnerID: "owner",
+	})
+	annotations := []*model.Annotation{{Name: "clone-annotation"}}
+
+	cloneOp, err := sqlStore.TriggerInstallationClone(source, clone, annotations)
+	require.NoError(t, err)
+	assert.NotEmpty(t, cloneOp.ID)
+	assert.NotEmpty(t, clone.ID)
+	assert.Equal(t, source.ID, cloneOp.InstallationID)
+	assert.Equal(t, clone.ID, cloneOp.CloneInstallationID)
+	assert.Equal(t, model.InstallationCloneStateRequested, cloneOp.State)
+
+	fetchedOp, err := sqlStore.GetInstallationCloneOperation(cloneOp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, cloneOp, fetchedOp)
+
+	fetchedClone, err := sqlStore.GetInstallation(clone.ID, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, "clone.example.com", fetchedClone.DNS)
+	assert.Equal(t, "owner", fetchedClone.OwnerID)
+	assert.Equal(t, model.InstallationStateCreationRequested, fetchedClone.State)
+
+	fetchedAnnotations, err := sqlStore.GetAnnotationsForInstallation(clone.ID)
+	require.NoError(t, err)
+	require.Len(t, fetchedAnnotations, 1)
+	assert.Equal(t, "clone-annotation", fetchedAnnotations[0].Name)
+
+	t.Run("unknown clone operation", func(t *testing.T) {
+		fetchedOp, err = sqlStore.GetInstallationCloneOperation("unknown")
+		require.NoError(t, err)
+		assert.Nil(t, fetchedOp)
+	})
+}
+
+func TestGetInstallationCloneOperations(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	installation1 := setupHibernatingInstallation(t, sqlStore)
+	installation2 := setupHibernatingInstallation(t, sqlStore)
+
+	cloneOps := []*model.InstallationCloneOperation{
+		{InstallationID: installation1.ID, CloneInstallationID: "clone1", State: model.InstallationCloneStateRequested},
+		{InstallationID: installation1.ID, CloneInstallationID: "clone2", State: model.InstallationCloneStateBackupInProgress},
+		{InstallationID: installation1.ID, CloneInstallationID: "clone3", State: model.InstallationCloneStateFailed},
+		{InstallationID: installation2.ID, CloneInstallationID: "clone4", State: model.InstallationCloneStateRequested},
+		{InstallationID: installation2.ID, CloneInstallationID: "clone5", State: model.InstallationCloneStateSucceeded},
+	}
+
+	for i := range cloneOps {
+		err := sqlStore.CreateInstallationCloneOperation(cloneOps[i])
+		require.NoError(t, err)
+		time.Sleep(1 * time.Millisecond) // Ensure RequestAt is different for all operations.
+	}
+
+	for _, testCase := range []struct {
+		description string
+		filter      *model.InstallationCloneFilter
+		fetchedIds  []string
+	}{
+		{
+			description: "fetch all",
+			filter:      &model.InstallationCloneFilter{Paging: model.AllPagesNotDeleted()},
+			fetchedIds:  []string{cloneOps[4].ID, cloneOps[3].ID, cloneOps[2].ID, cloneOps[1].ID, cloneOps[0].ID},
+		},
+		{
+			description: "fetch all for installation 1",
+			filter:      &model.InstallationCloneFilter{InstallationID: installation1.ID, Paging: model.AllPagesNotDeleted()},
+			fetchedIds:  []string{cloneOps[2].ID, cloneOps[1].ID, cloneOps[0].ID},
+		},
+		{
+			description: "fetch for clone installation",
+			filter:      &model.InstallationCloneFilter{CloneInstallationID: "clone4", Paging: model.AllPagesNotDeleted()},
+			fetchedIds:  []string{cloneOps[3].ID},
+		},
+		{
+			description: "fetch requested operations",
+			filter:      &model.InstallationCloneFilter{States: []model.InstallationCloneOperationState{model.InstallationCloneStateRequested}, Paging: model.AllPagesNotDeleted()},
+			fetchedIds:  []string{cloneOps[3].ID, cloneOps[0].ID},
+		},
+		{
+			description: "fetch with IDs",
+			filter:      &model.InstallationCloneFilter{IDs: []string{cloneOps[0].ID, cloneOps[4].ID}, Paging: model.AllPagesNotDeleted()},
+			fetchedIds:  []string{cloneOps[4].ID, cloneOps[0].ID},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			fetchedOps, err := sqlStore.GetInstallationCloneOperations(testCase.filter)
+			require.NoError(t, err)
+			assert.Equal(t, len(testCase.fetchedIds), len(fetchedOps))
+
+			for i, op := range fetchedOps {
+				assert.Equal(t, testCase.fetchedIds[i], op.ID)
+			}
+		})
+	}
+
+	t.Run("pending work", func(t *testing.T) {
+		pendingOps, err := sqlStore.GetUnlockedInstallationCloneOperationsPendingWork()
+		require.NoError(t, err)
+		assert.Equal(t, 3, len(pendingOps))
+
+		locked, err := sqlStore.LockInstallationCloneOperation(cloneOps[0].ID, "abc")
+		require.NoError(t, err)
+		assert.True(t, locked)
+
+		pendingOps, err = sqlStore.GetUnlockedInstallationCloneOperationsPendingWork()
+		require.NoError(t, err)
+		assert.Equal(t, 2, len(pendingOps))
+	})
+}
+
+func TestUpdateInstallationCloneOperation(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	installation := setupHibernatingInstallation(t, sqlStore)
+
+	cloneOp := &model.InstallationCloneOperation{
+		InstallationID: installation.ID,
+		State:          model.InstallationCloneStateRequested,
+	}
+
+	err := sqlStore.CreateInstallationCloneOperation(cloneOp)
+	require.NoError(t, err)
+
+	t.Run("update state only", func(t *testing.T) {
+		cloneOp.State = model.InstallationCloneStateBackupInProgress
+		cloneOp.BackupID = "backup"
+
+		err = sqlStore.UpdateInstallationCloneOperationState(cloneOp)
+		require.NoError(t, err)
+
+		fetched, err := sqlStore.GetInstallationCloneOperation(cloneOp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.InstallationCloneStateBackupInProgress, fetched.State)
+		assert.Equal(t, "", fetched.BackupID)
+	})
+
+	t.Run("full update", func(t *testing.T) {
+		cloneOp.InstallationDBRestorationOperationID = "restoration"
+		cloneOp.CompleteAt = 100
+		cloneOp.State = model.InstallationCloneStateSucceeded
+		err = sqlStore.UpdateInstallationCloneOperation(cloneOp)
+		require.NoError(t, err)
+
+		fetched, err := sqlStore.GetInstallationCloneOperation(cloneOp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.InstallationCloneStateSucceeded, fetched.State)
+		assert.Equal(t, "backup", fetched.BackupID)
+		assert.Equal(t, "restoration", fetched.InstallationDBRestorationOperationID)
+		assert.Equal(t, int64(100), fetched.CompleteAt)
+	})
+}
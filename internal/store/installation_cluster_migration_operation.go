@@ -0,0 +1,215 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	installationClusterMigrationTable = "InstallationClusterMigrationOperation"
+)
+
+var installationClusterMigrationSelect sq.SelectBuilder
+
+func init() {
+	installationClusterMigrationSelect = sq.
+		Select("ID",
+			"InstallationID",
+			"SourceClusterID",
+			"TargetClusterID",
+			"SourceClusterInstallationID",
+			"TargetClusterInstallationID",
+			"RequestAt",
+			"State",
+			"CompleteAt",
+			"DeleteAt",
+			"LockAcquiredBy",
+			"LockAcquiredAt",
+		).
+		From(installationClusterMigrationTable)
+}
+
+// TriggerInstallationClusterMigration creates new InstallationClusterMigrationOperation in Requested state
+// and changes installation state to InstallationStateClusterMigrationInProgress.
+func (sqlStore *SQLStore) TriggerInstallationClusterMigration(migrationOp *model.InstallationClusterMigrationOperation, installation *model.Installation) (*model.InstallationClusterMigrationOperation, error) {
+	migrationOp.InstallationID = installation.ID
+	migrationOp.State = model.InstallationClusterMigrationStateRequested
+	migrationOp.TargetClusterInstallationID = ""
+
+	tx, err := sqlStore.beginTransaction(sqlStore.db)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to start transaction")
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	err = sqlStore.createInstallationClusterMigration(tx, migrationOp)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create installation cluster migration")
+	}
+
+	installation.State = model.InstallationStateClusterMigrationInProgress
+	err = sqlStore.updateInstallation(tx, installation)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update installation")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return migrationOp, nil
+}
+
+// CreateInstallationClusterMigrationOperation records installation cluster migration to the database, assigning it a unique ID.
+func (sqlStore *SQLStore) CreateInstallationClusterMigrationOperation(migrationOp *model.InstallationClusterMigrationOperation) error {
+	return sqlStore.createInstallationClusterMigration(sqlStore.db, migrationOp)
+}
+
+func (sqlStore *SQLStore) createInstallationClusterMigration(db execer, migrationOp *model.InstallationClusterMigrationOperation) error {
+	migrationOp.ID = model.NewID()
+	migrationOp.RequestAt = GetMillis()
+
+	_, err := sqlStore.execBuilder(db, sq.
+		Insert(installationClusterMigrationTable).
+		SetMap(map[string]interface{}{
+			"ID":                          migrationOp.ID,
+			"InstallationID":              migrationOp.InstallationID,
+			"SourceClusterID":             migrationOp.SourceClusterID,
+			"TargetClusterID":             migrationOp.TargetClusterID,
+			"SourceClusterInstallationID": migrationOp.SourceClusterInstallationID,
+			"TargetClusterInstallationID": migrationOp.TargetClusterInstallationID,
+			"RequestAt":                   migrationOp.RequestAt,
+			"State":                       migrationOp.State,
+			"CompleteAt":                  migrationOp.CompleteAt,
+			"DeleteAt":                    0,
+			"LockAcquiredBy":              nil,
+			"LockAcquiredAt":              0,
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create installation cluster migration operation")
+	}
+
+	return nil
+}
+
+// GetInstallationClusterMigrationOperation fetches the given installation cluster migration operation.
+func (sqlStore *SQLStore) GetInstallationClusterMigrationOperation(id string) (*model.InstallationClusterMigrationOperation, error) {
+	builder := installationClusterMigrationSelect.
+		Where("ID = ?", id)
+
+	var migrationOp model.InstallationClusterMigrationOperation
+	err := sqlStore.getBuilder(sqlStore.db, &migrationOp, builder)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to query for installation cluster migration operation")
+	}
+
+	return &migrationOp, nil
+}
+
+// GetInstallationClusterMigrationOperations fetches the given page of installation cluster migration operations. The first page is 0.
+func (sqlStore *SQLStore) GetInstallationClusterMigrationOperations(filter *model.InstallationClusterMigrationFilter) ([]*model.InstallationClusterMigrationOperation, error) {
+	builder := installationClusterMigrationSelect.
+		OrderBy("RequestAt DESC")
+	builder = sqlStore.applyInstallationClusterMigrationFilter(builder, filter)
+
+	return sqlStore.getInstallationClusterMigrationOperations(builder)
+}
+
+// GetUnlockedInstallationClusterMigrationOperationsPendingWork returns unlocked installation cluster migration operations in a pending state.
+func (sqlStore *SQLStore) GetUnlockedInstallationClusterMigrationOperationsPendingWork() ([]*model.InstallationClusterMigrationOperation, error) {
+	builder := installationClusterMigrationSelect.
+		Where(sq.Eq{
+			"State": model.AllInstallationClusterMigrationOperationsStatesPendingWork,
+		}).
+		Where("LockAcquiredAt = 0").
+		OrderBy("RequestAt ASC")
+
+	return sqlStore.getInstallationClusterMigrationOperations(builder)
+}
+
+func (sqlStore *SQLStore) getInstallationClusterMigrationOperations(builder builder) ([]*model.InstallationClusterMigrationOperation, error) {
+	var migrationOps []*model.InstallationClusterMigrationOperation
+	err := sqlStore.selectBuilder(sqlStore.db, &migrationOps, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for installation cluster migration operations")
+	}
+
+	return migrationOps, nil
+}
+
+// UpdateInstallationClusterMigrationOperationState updates the given installation cluster migration operation state.
+func (sqlStore *SQLStore) UpdateInstallationClusterMigrationOperationState(migrationOp *model.InstallationClusterMigrationOperation) error {
+	return sqlStore.updateInstallationClusterMigrationFields(
+		sqlStore.db,
+		migrationOp.ID, map[string]interface{}{
+			"State": migrationOp.State,
+		})
+}
+
+// UpdateInstallationClusterMigrationOperation updates the given installation cluster migration operation.
+func (sqlStore *SQLStore) UpdateInstallationClusterMigrationOperation(migrationOp *model.InstallationClusterMigrationOperation) error {
+	return sqlStore.updateInstallationClusterMigrationFields(
+		sqlStore.db,
+		migrationOp.ID, map[string]interface{}{
+			"State":                       migrationOp.State,
+			"TargetClusterInstallationID": migrationOp.TargetClusterInstallationID,
+			"CompleteAt":                  migrationOp.CompleteAt,
+		})
+}
+
+func (sqlStore *SQLStore) updateInstallationClusterMigrationFields(db execer, id string, fields map[string]interface{}) error {
+	_, err := sqlStore.execBuilder(db, sq.
+		Update(installationClusterMigrationTable).
+		SetMap(fields).
+		Where("ID = ?", id))
+	if err != nil {
+		return errors.Wrapf(err, "failed to update installation cluster migration operation fields: %s", getMapKeys(fields))
+	}
+
+	return nil
+}
+
+// LockInstallationClusterMigrationOperations marks InstallationClusterMigrationOperations as locked for exclusive use by the caller.
+func (sqlStore *SQLStore) LockInstallationClusterMigrationOperations(ids []string, lockerID string) (bool, error) {
+	return sqlStore.lockRows(installationClusterMigrationTable, ids, lockerID)
+}
+
+// UnlockInstallationClusterMigrationOperations releases locks previously acquired against a caller.
+func (sqlStore *SQLStore) UnlockInstallationClusterMigrationOperations(ids []string, lockerID string, force bool) (bool, error) {
+	return sqlStore.unlockRows(installationClusterMigrationTable, ids, lockerID, force)
+}
+
+func (sqlStore *SQLStore) applyInstallationClusterMigrationFilter(builder sq.SelectBuilder, filter *model.InstallationClusterMigrationFilter) sq.SelectBuilder {
+	builder = applyPagingFilter(builder, filter.Paging)
+
+	if len(filter.IDs) > 0 {
+		builder = builder.Where(sq.Eq{"ID": filter.IDs})
+	}
+	if filter.InstallationID != "" {
+		builder = builder.Where("InstallationID = ?", filter.InstallationID)
+	}
+	if filter.SourceClusterID != "" {
+		builder = builder.Where("SourceClusterID = ?", filter.SourceClusterID)
+	}
+	if filter.TargetClusterID != "" {
+		builder = builder.Where("TargetClusterID = ?", filter.TargetClusterID)
+	}
+	if len(filter.States) > 0 {
+		builder = builder.Where(sq.Eq{
+			"State": filter.States,
+		})
+	}
+
+	return builder
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTriggerInstallationClusterMigration(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	installation := &model.Installation{
+		Database:  model.InstallationDatabaseMultiTenantRDSPostgres,
+		Filestore: model.InstallationFilestoreBifrost,
+		State:     model.InstallationStateStable,
+	}
+	err := sqlStore.CreateInstallation(installation, nil)
+	require.NoError(t, err)
+
+	migrationOp := &model.InstallationClusterMigrationOperation{
+		SourceClusterID:             "source-cluster",
+		TargetClusterID:             "target-cluster",
+		SourceClusterInstallationID: "source-ci",
+	}
+
+	migrationOp, err = sqlStore.TriggerInstallationClusterMigration(migrationOp, installation)
+	require.NoError(t, err)
+	assert.NotEmpty(t, migrationOp.ID)
+	assert.Equal(t, installation.ID, migrationOp.InstallationID)
+	assert.Equal(t, model.InstallationClusterMigrationStateRequested, migrationOp.State)
+
+	fetchedOp, err := sqlStore.GetInstallationClusterMigrationOperation(migrationOp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, migrationOp, fetchedOp)
+
+	installation, err = sqlStore.GetInstallation(installation.ID, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, model.InstallationStateClusterMigrationInProgress, installation.State)
+
+	t.Run("unknown cluster migration operation", func(t *testing.T) {
+		fetchedOp, err = sqlStore.GetInstallationClusterMigrationOperation("unknown")
+		require.NoError(t, err)
+		assert.Nil(t, fetchedOp)
+	})
+}
+
+func TestGetInstallationClusterMigrationOperations(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	migrationOps := []*model.InstallationClusterMigrationOperation{
+		{InstallationID: "installation1", SourceClusterID: "cluster1", TargetClusterID: "cluster2", State: model.InstallationClusterMigrationStateRequested},
+		{InstallationID: "installation1", SourceClusterID: "cluster2", TargetClusterID: "cluster3", State: model.InstallationClusterMigrationStateDNSSwitch},
+		{InstallationID: "installation2", SourceClusterID: "cluster1", TargetClusterID: "cluster3", State: model.InstallationClusterMigrationStateFailed},
+		{InstallationID: "installation3", SourceClusterID: "cluster1", TargetClusterID: "cluster2", State: model.InstallationClusterMigrationStateSucceeded},
+	}
+
+	for i := range migrationOps {
+		err := sqlStore.CreateInstallationClusterMigrationOperation(migrationOps[i])
+		require.NoError(t, err)
+		time.Sleep(1 * time.Millisecond) // Ensure RequestAt is different for all operations.
+	}
+
+	for _, testCase := range []struct {
+		description string
+		filter      *model.InstallationClusterMigrationFilter
+		fetchedIds  []string
+	}{
+		{
+			description: "fetch all",
+			filter:      &model.InstallationClusterMigrationFilter{Paging: model.AllPagesNotDeleted()},
+			fetchedIds:  []string{migrationOps[3].ID, migrationOps[2].ID, migrationOps[1].ID, migrationOps[0].ID},
+		},
+		{
+			description: "fetch for installation",
+			filter:      &model.InstallationClusterMigrationFilter{InstallationID: "installation1", Paging: model.AllPagesNotDeleted()},
+			fetchedIds:  []string{migrationOps[1].ID, migrationOps[0].ID},
+		},
+		{
+			description: "fetch for source cluster",
+			filter:      &model.InstallationClusterMigrationFilter{SourceClusterID: "cluster1", Paging: model.AllPagesNotDeleted()},
+			fetchedIds:  []string{migrationOps[3].ID, migrationOps[2].ID, migrationOps[0].ID},
+		},
+		{
+			description: "fetch for target cluster",
+			filter:      &model.InstallationClusterMigrationFilter{TargetClusterID: "cluster3", Paging: model.AllPagesNotDeleted()},
+			fetchedIds:  []string{migrationOps[2].ID, migrationOps[1].ID},
+		},
+		{
+			description: "fetch by state",
+			filter:      &model.InstallationClusterMigrationFilter{States: []model.InstallationClusterMigrationOperationState{model.InstallationClusterMigrationStateFailed}, Paging: model.AllPagesNotDeleted()},
+			fetchedIds:  []string{migrationOps[2].ID},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			fetchedOps, err := sqlStore.GetInstallationClusterMigrationOperations(testCase.filter)
+			require.NoError(t, err)
+			assert.Equal(t, len(testCase.fetchedIds), len(fetchedOps))
+
+			for i, op := range fetchedOps {
+				assert.Equal(t, testCase.fetchedIds[i], op.ID)
+			}
+		})
+	}
+
+	t.Run("pending work", func(t *testing.T) {
+		pendingOps, err := sqlStore.GetUnlockedInstallationClusterMigrationOperationsPendingWork()
+		require.NoError(t, err)
+		assert.Equal(t, 2, len(pendingOps))
+
+		locked, err := sqlStore.LockInstallationClusterMigrationOperations([]string{migrationOps[0].ID}, "abc")
+		require.NoError(t, err)
+		assert.True(t, locked)
+
+		pendingOps, err = sqlStore.GetUnlockedInstallationClusterMigrationOperationsPendingWork()
+		require.NoError(t, err)
+		assert.Equal(t, 1, len(pendingOps))
+	})
+}
+
+func TestUpdateInstallationClusterMigrationOperation(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	migrationOp := &model.InstallationClusterMigrationOperation{
+		InstallationID: "installation",
+		State:          model.InstallationClusterMigrationStateRequested,
+	}
+	err := sqlStore.CreateInstallationClusterMigrationOperation(migrationOp)
+	require.NoError(t, err)
+
+	migrationOp.State = model.InstallationClusterMigrationStateTargetCreationInProgress
+	err = sqlStore.UpdateInstallationClusterMigrationOperationState(migrationOp)
+	require.NoError(t, err)
+
+	migrationOp.TargetClusterInstallationID = "target-ci"
+	migrationOp.CompleteAt = 100
+	err = sqlStore.UpdateInstallationClusterMigrationOperation(migrationOp)
+	require.NoError(t, err)
+
+	fetchedOp, err := sqlStore.GetInstallationClusterMigrationOperation(migrationOp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, migrationOp, fetchedOp)
+}
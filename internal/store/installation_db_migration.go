@@ -121,6 +121,36 @@ func (sqlStore *SQLStore) TriggerInstallationDBMigration(dbMigrationOp *model.In
 	return dbMigrationOp, nil
 }
 
+// CancelInstallationDBMigration sets InstallationDBMigrationOperation to Canceled state
+// and reverts installation state to InstallationStateHibernating.
+func (sqlStore *SQLStore) CancelInstallationDBMigration(dbMigrationOp *model.InstallationDBMigrationOperation, installation *model.Installation) error {
+	tx, err := sqlStore.beginTransaction(sqlStore.db)
+	if err != nil {
+		return errors.Wrap(err, "failed to start transaction")
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	dbMigrationOp.State = model.InstallationDBMigrationStateCanceled
+	dbMigrationOp.CompleteAt = GetMillis()
+	err = sqlStore.updateInstallationDBMigration(tx, dbMigrationOp)
+	if err != nil {
+		return errors.Wrap(err, "failed to update installation db migration")
+	}
+
+	installation.State = model.InstallationStateHibernating
+	err = sqlStore.updateInstallation(tx, installation)
+	if err != nil {
+		return errors.Wrap(err, "failed to update installation")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return nil
+}
+
 // CreateInstallationDBMigrationOperation records installation db migration to the database, assigning it a unique ID.
 func (sqlStore *SQLStore) CreateInstallationDBMigrationOperation(dbMigration *model.InstallationDBMigrationOperation) error {
 	return sqlStore.createInstallationDBMigration(sqlStore.db, dbMigration)
@@ -40,6 +40,34 @@ func TestTriggerInstallationDBMigration(t *testing.T) {
 	assert.Equal(t, model.InstallationStateDBMigrationInProgress, installation.State)
 }
 
+func TestCancelInstallationDBMigration(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	installation := setupHibernatingInstallation(t, sqlStore)
+
+	dbMigrationOp := &model.InstallationDBMigrationOperation{
+		SourceDatabase:      "source",
+		DestinationDatabase: "destination",
+	}
+
+	migrationOp, err := sqlStore.TriggerInstallationDBMigration(dbMigrationOp, installation)
+	require.NoError(t, err)
+
+	err = sqlStore.CancelInstallationDBMigration(migrationOp, installation)
+	require.NoError(t, err)
+
+	fetchOp, err := sqlStore.GetInstallationDBMigrationOperation(migrationOp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.InstallationDBMigrationStateCanceled, fetchOp.State)
+	assert.NotZero(t, fetchOp.CompleteAt)
+
+	installation, err = sqlStore.GetInstallation(installation.ID, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, model.InstallationStateHibernating, installation.State)
+}
+
 func TestInstallationDBMigrationOperation(t *testing.T) {
 	logger := testlib.MakeLogger(t)
 	sqlStore := MakeTestSQLStore(t, logger)
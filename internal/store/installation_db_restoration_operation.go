@@ -22,7 +22,10 @@ func init() {
 	installationDBRestorationSelect = sq.
 		Select("ID",
 			"InstallationID",
+			"Mode",
+			"SourceInstallationID",
 			"BackupID",
+			"RestoreTime",
 			"RequestAt",
 			"State",
 			"TargetInstallationState",
@@ -39,19 +42,36 @@ func init() {
 // For now tho transactions are not accessible outside the store, therefore it is implemented this way.
 
 // TriggerInstallationRestoration creates new InstallationDBRestorationOperation in Requested state
-// and changes installation state to InstallationStateDBRestorationInProgress.
+// restoring the given backup and changes installation state to InstallationStateDBRestorationInProgress.
 func (sqlStore *SQLStore) TriggerInstallationRestoration(installation *model.Installation, backup *model.InstallationBackup) (*model.InstallationDBRestorationOperation, error) {
+	return sqlStore.triggerInstallationRestoration(installation, &model.InstallationDBRestorationOperation{
+		InstallationID:       installation.ID,
+		Mode:                 model.InstallationDBRestorationModeBackup,
+		SourceInstallationID: backup.InstallationID,
+		BackupID:             backup.ID,
+	})
+}
+
+// TriggerInstallationPointInTimeRestoration creates new InstallationDBRestorationOperation in Requested state
+// restoring the database of the source installation to the given time and changes installation state
+// to InstallationStateDBRestorationInProgress.
+func (sqlStore *SQLStore) TriggerInstallationPointInTimeRestoration(installation *model.Installation, sourceInstallationID string, restoreTime int64) (*model.InstallationDBRestorationOperation, error) {
+	return sqlStore.triggerInstallationRestoration(installation, &model.InstallationDBRestorationOperation{
+		InstallationID:       installation.ID,
+		Mode:                 model.InstallationDBRestorationModePointInTime,
+		SourceInstallationID: sourceInstallationID,
+		RestoreTime:          restoreTime,
+	})
+}
+
+func (sqlStore *SQLStore) triggerInstallationRestoration(installation *model.Installation, dbRestorationOp *model.InstallationDBRestorationOperation) (*model.InstallationDBRestorationOperation, error) {
 	targetInstallationState, err := model.DetermineAfterRestorationState(installation)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to determine target installation state")
 	}
 
-	dbRestorationOp := &model.InstallationDBRestorationOperation{
-		InstallationID:          installation.ID,
-		BackupID:                backup.ID,
-		State:                   model.InstallationDBRestorationStateRequested,
-		TargetInstallationState: targetInstallationState,
-	}
+	dbRestorationOp.State = model.InstallationDBRestorationStateRequested
+	dbRestorationOp.TargetInstallationState = targetInstallationState
 
 	tx, err := sqlStore.beginTransaction(sqlStore.db)
 	if err != nil {
@@ -86,13 +106,19 @@ func (sqlStore *SQLStore) CreateInstallationDBRestorationOperation(dbRestoration
 func (sqlStore *SQLStore) createInstallationDBRestoration(db execer, dbRestoration *model.InstallationDBRestorationOperation) error {
 	dbRestoration.ID = model.NewID()
 	dbRestoration.RequestAt = GetMillis()
+	if dbRestoration.Mode == "" {
+		dbRestoration.Mode = model.InstallationDBRestorationModeBackup
+	}
 
 	_, err := sqlStore.execBuilder(db, sq.
 		Insert(installationDBRestorationTable).
 		SetMap(map[string]interface{}{
 			"ID":                      dbRestoration.ID,
 			"InstallationID":          dbRestoration.InstallationID,
+			"Mode":                    dbRestoration.Mode,
+			"SourceInstallationID":    dbRestoration.SourceInstallationID,
 			"BackupID":                dbRestoration.BackupID,
+			"RestoreTime":             dbRestoration.RestoreTime,
 			"State":                   dbRestoration.State,
 			"RequestAt":               dbRestoration.RequestAt,
 			"TargetInstallationState": dbRestoration.TargetInstallationState,
@@ -31,6 +31,34 @@ func TestTriggerInstallationRestoration(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, installation.ID, restorationOp.InstallationID)
 	assert.Equal(t, backup.ID, restorationOp.BackupID)
+	assert.Equal(t, model.InstallationDBRestorationModeBackup, restorationOp.Mode)
+	assert.Equal(t, installation.ID, restorationOp.SourceInstallationID)
+
+	fetchOp, err := sqlStore.GetInstallationDBRestorationOperation(restorationOp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, restorationOp, fetchOp)
+
+	installation, err = sqlStore.GetInstallation(installation.ID, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, model.InstallationStateDBRestorationInProgress, installation.State)
+}
+
+func TestTriggerInstallationPointInTimeRestoration(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	installation := setupHibernatingInstallation(t, sqlStore)
+	sourceInstallationID := model.NewID()
+	restoreTime := GetMillis() - 1000
+
+	restorationOp, err := sqlStore.TriggerInstallationPointInTimeRestoration(installation, sourceInstallationID, restoreTime)
+	require.NoError(t, err)
+	assert.Equal(t, installation.ID, restorationOp.InstallationID)
+	assert.Equal(t, model.InstallationDBRestorationModePointInTime, restorationOp.Mode)
+	assert.Equal(t, sourceInstallationID, restorationOp.SourceInstallationID)
+	assert.Equal(t, restoreTime, restorationOp.RestoreTime)
+	assert.Empty(t, restorationOp.BackupID)
 
 	fetchOp, err := sqlStore.GetInstallationDBRestorationOperation(restorationOp.ID)
 	require.NoError(t, err)
@@ -37,7 +37,20 @@ func (sqlStore *SQLStore) GetInstallationDTOs(filter *model.InstallationFilter,
 		return nil, errors.Wrap(err, "failed to get installations")
 	}
 
-	annotations, err := sqlStore.GetAnnotationsForInstallations(filter)
+	if len(installations) == 0 {
+		return []*model.InstallationDTO{}, nil
+	}
+
+	// The annotations are fetched for the returned installations only, as the
+	// sorting and paging of the filter do not apply to annotation rows.
+	installationIDs := make([]string, 0, len(installations))
+	for _, installation := range installations {
+		installationIDs = append(installationIDs, installation.ID)
+	}
+	annotations, err := sqlStore.GetAnnotationsForInstallations(&model.InstallationFilter{
+		Paging:          model.AllPagesWithDeleted(),
+		InstallationIDs: installationIDs,
+	})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get annotations for installations")
 	}
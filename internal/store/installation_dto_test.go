@@ -6,6 +6,7 @@ package store
 
 import (
 	"testing"
+	"time"
 
 	"github.com/mattermost/mattermost-cloud/internal/testlib"
 	"github.com/mattermost/mattermost-cloud/model"
@@ -70,6 +71,8 @@ func TestInstallationDTOs(t *testing.T) {
 	err = sqlStore.CreateInstallation(installation1, annotations)
 	require.NoError(t, err)
 
+	time.Sleep(1 * time.Millisecond)
+
 	err = sqlStore.CreateInstallation(installation2, nil)
 	require.NoError(t, err)
 
@@ -5,9 +5,12 @@
 package store
 
 import (
+	"fmt"
+	"sort"
 	"testing"
 	"time"
 
+	sq "github.com/Masterminds/squirrel"
 	"github.com/mattermost/mattermost-cloud/internal/testlib"
 	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/stretchr/testify/assert"
@@ -314,6 +317,182 @@ func TestInstallations(t *testing.T) {
 	}
 }
 
+func TestGetInstallationsFilterAndSort(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	annotations := []*model.Annotation{{Name: "annotation1"}, {Name: "annotation2"}}
+
+	installation1 := &model.Installation{
+		OwnerID:   "owner-b",
+		Version:   "5.37.0",
+		Image:     "mattermost/mattermost-enterprise-edition",
+		DNS:       "alpha.example.com",
+		Database:  model.InstallationDatabaseMysqlOperator,
+		Filestore: model.InstallationFilestoreMinioOperator,
+		Size:      mmv1alpha1.Size100String,
+		Affinity:  model.InstallationAffinityIsolated,
+		State:     model.InstallationStateStable,
+	}
+	err := sqlStore.CreateInstallation(installation1, annotations[:1])
+	require.NoError(t, err)
+
+	time.Sleep(1 * time.Millisecond)
+
+	installation2 := &model.Installation{
+		OwnerID:   "owner-a",
+		Version:   "5.38.0",
+		Image:     "mattermost/mattermost-team-edition",
+		DNS:       "beta.example.com",
+		Database:  model.InstallationDatabaseMultiTenantRDSPostgres,
+		Filestore: model.InstallationFilestoreBifrost,
+		Size:      mmv1alpha1.Size1000String,
+		Affinity:  model.InstallationAffinityMultiTenant,
+		State:     model.InstallationStateHibernating,
+	}
+	err = sqlStore.CreateInstallation(installation2, annotations[1:])
+	require.NoError(t, err)
+
+	time.Sleep(1 * time.Millisecond)
+
+	installation3 := &model.Installation{
+		OwnerID:   "owner-c",
+		Version:   "5.37.0",
+		Image:     "mattermost/mattermost-enterprise-edition",
+		DNS:       "gamma_test.example.com",
+		Database:  model.InstallationDatabaseMysqlOperator,
+		Filestore: model.InstallationFilestoreMinioOperator,
+		Size:      mmv1alpha1.Size100String,
+		Affinity:  model.InstallationAffinityMultiTenant,
+		State:     model.InstallationStateStable,
+	}
+	err = sqlStore.CreateInstallation(installation3, nil)
+	require.NoError(t, err)
+
+	clusterInstallation := &model.ClusterInstallation{
+		ClusterID:      model.NewID(),
+		InstallationID: installation2.ID,
+		Namespace:      installation2.ID,
+		State:          model.ClusterInstallationStateStable,
+	}
+	err = sqlStore.CreateClusterInstallation(clusterInstallation)
+	require.NoError(t, err)
+
+	// Installations 1 and 3 share the version and state, so they are sorted
+	// by ID when sorting by either.
+	tiedFirst, tiedSecond := installation1, installation3
+	if tiedSecond.ID < tiedFirst.ID {
+		tiedFirst, tiedSecond = tiedSecond, tiedFirst
+	}
+
+	testCases := []struct {
+		Description string
+		Filter      *model.InstallationFilter
+		Expected    []*model.Installation
+	}{
+		{"version", &model.InstallationFilter{Version: "5.37.0"}, []*model.Installation{installation1, installation3}},
+		{"image", &model.InstallationFilter{Image: "mattermost/mattermost-team-edition"}, []*model.Installation{installation2}},
+		{"database", &model.InstallationFilter{Database: model.InstallationDatabaseMultiTenantRDSPostgres}, []*model.Installation{installation2}},
+		{"filestore", &model.InstallationFilter{Filestore: model.InstallationFilestoreMinioOperator}, []*model.Installation{installation1, installation3}},
+		{"size", &model.InstallationFilter{Size: mmv1alpha1.Size1000String}, []*model.Installation{installation2}},
+		{"affinity", &model.InstallationFilter{Affinity: model.InstallationAffinityMultiTenant}, []*model.Installation{installation2, installation3}},
+		{"cluster", &model.InstallationFilter{ClusterID: clusterInstallation.ClusterID}, []*model.Installation{installation2}},
+		{"unknown cluster", &model.InstallationFilter{ClusterID: model.NewID()}, []*model.Installation{}},
+		{"dns contains", &model.InstallationFilter{DNSContains: "ta."}, []*model.Installation{installation2}},
+		{"dns contains underscore", &model.InstallationFilter{DNSContains: "a_t"}, []*model.Installation{installation3}},
+		{"created after", &model.InstallationFilter{CreatedAfter: installation1.CreateAt}, []*model.Installation{installation2, installation3}},
+		{"created before", &model.InstallationFilter{CreatedBefore: installation3.CreateAt}, []*model.Installation{installation1, installation2}},
+		{"created between", &model.InstallationFilter{CreatedAfter: installation1.CreateAt, CreatedBefore: installation3.CreateAt}, []*model.Installation{installation2}},
+		{"all annotations", &model.InstallationFilter{Annotations: &model.AnnotationsFilter{MatchAllIDs: []string{annotations[0].ID, annotations[1].ID}}}, []*model.Installation{}},
+		{"any annotations", &model.InstallationFilter{Annotations: &model.AnnotationsFilter{MatchAnyIDs: []string{annotations[0].ID, annotations[1].ID}}}, []*model.Installation{installation1, installation2}},
+		{"sort by dns descending", &model.InstallationFilter{SortBy: model.InstallationSortByDNS, SortOrder: model.SortOrderDesc}, []*model.Installation{installation3, installation2, installation1}},
+		{"sort by owner", &model.InstallationFilter{SortBy: model.InstallationSortByOwner}, []*model.Installation{installation2, installation1, installation3}},
+		{"sort by version", &model.InstallationFilter{SortBy: model.InstallationSortByVersion}, []*model.Installation{tiedFirst, tiedSecond, installation2}},
+		{"sort by version, after tied cursor", &model.InstallationFilter{SortBy: model.InstallationSortByVersion, After: tiedFirst.ID}, []*model.Installation{tiedSecond, installation2}},
+		{"sort by state descending, after cursor", &model.InstallationFilter{SortBy: model.InstallationSortByState, SortOrder: model.SortOrderDesc, After: tiedFirst.ID}, []*model.Installation{installation2}},
+		{"after cursor", &model.InstallationFilter{After: installation2.ID}, []*model.Installation{installation3}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Description, func(t *testing.T) {
+			testCase.Filter.Paging = model.AllPagesNotDeleted()
+			actual, err := sqlStore.GetInstallations(testCase.Filter, false, false)
+			require.NoError(t, err)
+			if len(testCase.Expected) == 0 {
+				require.Empty(t, actual)
+				return
+			}
+			require.Equal(t, testCase.Expected, actual)
+		})
+	}
+
+	t.Run("paginate with cursor", func(t *testing.T) {
+		filter := &model.InstallationFilter{
+			Paging:    model.Paging{Page: 0, PerPage: 2},
+			SortBy:    model.InstallationSortByDNS,
+			SortOrder: model.SortOrderDesc,
+		}
+		page, err := sqlStore.GetInstallations(filter, false, false)
+		require.NoError(t, err)
+		require.Equal(t, []*model.Installation{installation3, installation2}, page)
+
+		filter.After = page[len(page)-1].ID
+		page, err = sqlStore.GetInstallations(filter, false, false)
+		require.NoError(t, err)
+		require.Equal(t, []*model.Installation{installation1}, page)
+	})
+}
+
+func TestGetInstallationsPaginateWithSameCreateAt(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	var ids []string
+	for i := 0; i < 5; i++ {
+		installation := &model.Installation{
+			DNS:   fmt.Sprintf("installation%d.example.com", i),
+			State: model.InstallationStateStable,
+		}
+		err := sqlStore.CreateInstallation(installation, nil)
+		require.NoError(t, err)
+		ids = append(ids, installation.ID)
+	}
+	sort.Strings(ids)
+
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.Update("Installation").Set("CreateAt", 1000))
+	require.NoError(t, err)
+
+	for _, sortOrder := range []string{model.SortOrderAsc, model.SortOrderDesc} {
+		t.Run(sortOrder, func(t *testing.T) {
+			filter := &model.InstallationFilter{
+				Paging:    model.Paging{Page: 0, PerPage: 2},
+				SortOrder: sortOrder,
+			}
+
+			var actual []string
+			for {
+				page, err := sqlStore.GetInstallations(filter, false, false)
+				require.NoError(t, err)
+				for _, installation := range page {
+					actual = append(actual, installation.ID)
+				}
+				if len(page) < filter.Paging.PerPage {
+					break
+				}
+				filter.After = page[len(page)-1].ID
+			}
+
+			expected := append([]string{}, ids...)
+			if sortOrder == model.SortOrderDesc {
+				sort.Sort(sort.Reverse(sort.StringSlice(expected)))
+			}
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
+
 func TestGetUnlockedInstallationPendingWork(t *testing.T) {
 	logger := testlib.MakeLogger(t)
 	sqlStore := MakeTestSQLStore(t, logger)
@@ -901,6 +1080,40 @@ func TestUpdateInstallationCRVersion(t *testing.T) {
 	assert.Equal(t, storedInstallation.CRVersion, model.V1betaCRVersion)
 }
 
+func TestUpdateInstallationHibernationNotice(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	installation1 := &model.Installation{
+		OwnerID:   model.NewID(),
+		Version:   "version",
+		DNS:       "dns4.example.com",
+		Database:  model.InstallationDatabaseMysqlOperator,
+		Filestore: model.InstallationFilestoreMinioOperator,
+		Size:      mmv1alpha1.Size100String,
+		Affinity:  model.InstallationAffinityIsolated,
+		State:     model.InstallationStateStable,
+	}
+
+	err := sqlStore.CreateInstallation(installation1, nil)
+	require.NoError(t, err)
+
+	err = sqlStore.UpdateInstallationHibernationNotice(installation1.ID, 100)
+	require.NoError(t, err)
+
+	storedInstallation, err := sqlStore.GetInstallation(installation1.ID, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), storedInstallation.HibernationNoticeAt)
+
+	err = sqlStore.UpdateInstallationHibernationNotice(installation1.ID, 0)
+	require.NoError(t, err)
+
+	storedInstallation, err = sqlStore.GetInstallation(installation1.ID, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), storedInstallation.HibernationNoticeAt)
+}
+
 func TestGetInstallationsTotalDatabaseWeight(t *testing.T) {
 	logger := testlib.MakeLogger(t)
 	sqlStore := MakeTestSQLStore(t, logger)
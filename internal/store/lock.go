@@ -39,6 +39,10 @@ func (sqlStore *SQLStore) lockRows(table string, ids []string, lockerID string)
 		sqlStore.logger.Warnf("Locked only %d of %d rows in %s", count, len(ids), table)
 	}
 
+	if sqlStore.metrics != nil {
+		sqlStore.metrics.ObserveLockAttempt(table, locked)
+	}
+
 	return locked, nil
 }
 
@@ -1311,6 +1311,409 @@ var migrations = []migration{
 			return err
 		}
 
+		return nil
+	}},
+	{semver.MustParse("0.27.0"), semver.MustParse("0.28.0"), func(e execer) error {
+		// Add APIKey table.
+		_, err := e.Exec(`
+			CREATE TABLE APIKey (
+				ID TEXT PRIMARY KEY,
+				Name TEXT NOT NULL,
+				Scope TEXT NOT NULL,
+				OwnerID TEXT NOT NULL,
+				KeyHash TEXT NOT NULL,
+				CreateAt BIGINT NOT NULL,
+				DeleteAt BIGINT NOT NULL
+			);
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`CREATE UNIQUE INDEX APIKey_KeyHash ON APIKey (KeyHash);`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.28.0"), semver.MustParse("0.29.0"), func(e execer) error {
+		// 1. Add Secret column to Webhooks table.
+		// 2. Add WebhookDelivery table.
+		_, err := e.Exec(`ALTER TABLE Webhooks ADD COLUMN Secret TEXT NOT NULL DEFAULT '';`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`
+			CREATE TABLE WebhookDelivery (
+				ID TEXT PRIMARY KEY,
+				WebhookID TEXT NOT NULL,
+				PayloadRaw BYTEA NOT NULL,
+				State TEXT NOT NULL,
+				Attempts BIGINT NOT NULL,
+				LastAttemptAt BIGINT NOT NULL,
+				NextAttemptAt BIGINT NOT NULL,
+				LastStatusCode BIGINT NOT NULL,
+				LastError TEXT NOT NULL,
+				CreateAt BIGINT NOT NULL,
+				LockAcquiredBy TEXT NULL,
+				LockAcquiredAt BIGINT NOT NULL
+			);
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`CREATE INDEX WebhookDelivery_WebhookID ON WebhookDelivery (WebhookID);`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`CREATE INDEX WebhookDelivery_State_NextAttemptAt ON WebhookDelivery (State, NextAttemptAt);`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.29.0"), semver.MustParse("0.30.0"), func(e execer) error {
+		// Add SubscriptionRaw column to Webhooks table.
+		_, err := e.Exec(`ALTER TABLE Webhooks ADD COLUMN SubscriptionRaw BYTEA NULL;`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.30.0"), semver.MustParse("0.31.0"), func(e execer) error {
+		// Add Event table.
+		_, err := e.Exec(`
+			CREATE TABLE Event (
+				ID TEXT PRIMARY KEY,
+				ResourceType TEXT NOT NULL,
+				ResourceID TEXT NOT NULL,
+				OldState TEXT NOT NULL,
+				NewState TEXT NOT NULL,
+				Timestamp BIGINT NOT NULL,
+				InstanceID TEXT NOT NULL,
+				Error TEXT NOT NULL
+			);
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`CREATE INDEX Event_ResourceID_Timestamp ON Event (ResourceID, Timestamp);`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`CREATE INDEX Event_Timestamp ON Event (Timestamp);`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.31.0"), semver.MustParse("0.32.0"), func(e execer) error {
+		// 1. Add ScheduleID column to InstallationBackup table.
+		// 2. Add InstallationBackupSchedule table.
+		_, err := e.Exec(`ALTER TABLE InstallationBackup ADD COLUMN ScheduleID TEXT NOT NULL DEFAULT '';`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`
+			CREATE TABLE InstallationBackupSchedule (
+				ID TEXT PRIMARY KEY,
+				InstallationID TEXT NOT NULL,
+				GroupID TEXT NOT NULL,
+				IntervalSeconds BIGINT NOT NULL,
+				RetentionKeepLast BIGINT NOT NULL,
+				RetentionKeepDailyDays BIGINT NOT NULL,
+				LastBackupAt BIGINT NOT NULL,
+				NextBackupAt BIGINT NOT NULL,
+				CreateAt BIGINT NOT NULL,
+				DeleteAt BIGINT NOT NULL,
+				LockAcquiredBy TEXT NULL,
+				LockAcquiredAt BIGINT NOT NULL
+			);
+		`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.32.0"), semver.MustParse("0.33.0"), func(e execer) error {
+		// Add Mode, SourceInstallationID and RestoreTime columns to
+		// InstallationDBRestorationOperation table. Existing restorations
+		// restored backups of the same installation.
+		_, err := e.Exec(`ALTER TABLE InstallationDBRestorationOperation ADD COLUMN Mode TEXT NOT NULL DEFAULT 'backup';`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`ALTER TABLE InstallationDBRestorationOperation ADD COLUMN SourceInstallationID TEXT NOT NULL DEFAULT '';`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`ALTER TABLE InstallationDBRestorationOperation ADD COLUMN RestoreTime BIGINT NOT NULL DEFAULT 0;`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`UPDATE InstallationDBRestorationOperation SET SourceInstallationID = InstallationID;`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.33.0"), semver.MustParse("0.34.0"), func(e execer) error {
+		// Add InstallationCloneOperation table.
+		_, err := e.Exec(`
+			CREATE TABLE InstallationCloneOperation (
+				ID TEXT PRIMARY KEY,
+				InstallationID TEXT NOT NULL,
+				CloneInstallationID TEXT NOT NULL,
+				RequestAt BIGINT NOT NULL,
+				State TEXT NOT NULL,
+				BackupID TEXT NOT NULL,
+				InstallationDBRestorationOperationID TEXT NOT NULL,
+				CompleteAt BIGINT NOT NULL,
+				DeleteAt BIGINT NOT NULL,
+				LockAcquiredBy TEXT NULL,
+				LockAcquiredAt BIGINT NOT NULL
+			);
+		`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.34.0"), semver.MustParse("0.35.0"), func(e execer) error {
+		// Add InstallationClusterMigrationOperation table.
+		_, err := e.Exec(`
+			CREATE TABLE InstallationClusterMigrationOperation (
+				ID TEXT PRIMARY KEY,
+				InstallationID TEXT NOT NULL,
+				SourceClusterID TEXT NOT NULL,
+				TargetClusterID TEXT NOT NULL,
+				SourceClusterInstallationID TEXT NOT NULL,
+				TargetClusterInstallationID TEXT NOT NULL,
+				RequestAt BIGINT NOT NULL,
+				State TEXT NOT NULL,
+				CompleteAt BIGINT NOT NULL,
+				DeleteAt BIGINT NOT NULL,
+				LockAcquiredBy TEXT NULL,
+				LockAcquiredAt BIGINT NOT NULL
+			);
+		`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.35.0"), semver.MustParse("0.36.0"), func(e execer) error {
+		// Add ClusterDrainOperation table.
+		_, err := e.Exec(`
+			CREATE TABLE ClusterDrainOperation (
+				ID TEXT PRIMARY KEY,
+				ClusterID TEXT NOT NULL,
+				MaxConcurrentMigrations BIGINT NOT NULL,
+				RequestAt BIGINT NOT NULL,
+				State TEXT NOT NULL,
+				CompleteAt BIGINT NOT NULL,
+				DeleteAt BIGINT NOT NULL,
+				LockAcquiredBy TEXT NULL,
+				LockAcquiredAt BIGINT NOT NULL
+			);
+		`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.36.0"), semver.MustParse("0.37.0"), func(e execer) error {
+		// Add SchedulingPolicy column to Installation table.
+		_, err := e.Exec(`ALTER TABLE Installation ADD COLUMN SchedulingPolicy TEXT NOT NULL DEFAULT '';`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.37.0"), semver.MustParse("0.38.0"), func(e execer) error {
+		// Add HibernationNoticeAt column to Installation table.
+		_, err := e.Exec(`ALTER TABLE Installation ADD COLUMN HibernationNoticeAt BIGINT NOT NULL DEFAULT 0;`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.38.0"), semver.MustParse("0.39.0"), func(e execer) error {
+		// Add ScheduledOperation table.
+		_, err := e.Exec(`
+			CREATE TABLE ScheduledOperation (
+				ID TEXT PRIMARY KEY,
+				InstallationID TEXT NOT NULL,
+				Type TEXT NOT NULL,
+				PatchRaw BYTEA NULL,
+				RunAt BIGINT NOT NULL,
+				IntervalSeconds BIGINT NOT NULL,
+				State TEXT NOT NULL,
+				LastRunAt BIGINT NOT NULL,
+				Error TEXT NOT NULL,
+				CreateAt BIGINT NOT NULL,
+				DeleteAt BIGINT NOT NULL,
+				LockAcquiredBy TEXT NULL,
+				LockAcquiredAt BIGINT NOT NULL
+			);
+		`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.39.0"), semver.MustParse("0.40.0"), func(e execer) error {
+		// Add rollout strategy columns to Group table.
+		_, err := e.Exec(`ALTER TABLE "Group" ADD COLUMN CanarySize BIGINT NOT NULL DEFAULT 0;`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`ALTER TABLE "Group" ADD COLUMN FailureThresholdPercent BIGINT NOT NULL DEFAULT 0;`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`ALTER TABLE "Group" ADD COLUMN RolloutState TEXT NOT NULL DEFAULT 'active';`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.40.0"), semver.MustParse("0.41.0"), func(e execer) error {
+		// Add GroupConfig table recording the configuration history of groups.
+		_, err := e.Exec(`
+			CREATE TABLE GroupConfig (
+				GroupID TEXT NOT NULL,
+				Sequence BIGINT NOT NULL,
+				Version TEXT NOT NULL,
+				Image TEXT NOT NULL,
+				MattermostEnvRaw BYTEA NULL,
+				CreateAt BIGINT NOT NULL,
+				PRIMARY KEY (GroupID, Sequence)
+			);
+		`)
+		if err != nil {
+			return err
+		}
+
+		// Record the current configuration of existing groups.
+		_, err = e.Exec(`
+			INSERT INTO GroupConfig (GroupID, Sequence, Version, Image, MattermostEnvRaw, CreateAt)
+			SELECT ID, Sequence, Version, Image, MattermostEnvRaw, CreateAt FROM "Group";
+		`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.41.0"), semver.MustParse("0.42.0"), func(e execer) error {
+		// Add installation default columns to Group and GroupConfig tables.
+		_, err := e.Exec(`ALTER TABLE "Group" ADD COLUMN Size TEXT NOT NULL DEFAULT '';`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`ALTER TABLE "Group" ADD COLUMN License TEXT NOT NULL DEFAULT '';`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`ALTER TABLE "Group" ADD COLUMN Affinity TEXT NOT NULL DEFAULT '';`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`ALTER TABLE "Group" ADD COLUMN Database TEXT NOT NULL DEFAULT '';`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`ALTER TABLE "Group" ADD COLUMN Filestore TEXT NOT NULL DEFAULT '';`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`ALTER TABLE GroupConfig ADD COLUMN Size TEXT NOT NULL DEFAULT '';`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`ALTER TABLE GroupConfig ADD COLUMN License TEXT NOT NULL DEFAULT '';`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.42.0"), semver.MustParse("0.43.0"), func(e execer) error {
+		// Add BulkOperation and BulkOperationItem tables.
+		_, err := e.Exec(`
+			CREATE TABLE BulkOperation (
+				ID TEXT PRIMARY KEY,
+				Type TEXT NOT NULL,
+				FilterRaw BYTEA NOT NULL,
+				PatchRaw BYTEA NULL,
+				AnnotationsRaw BYTEA NULL,
+				Concurrency BIGINT NOT NULL,
+				State TEXT NOT NULL,
+				CreateAt BIGINT NOT NULL,
+				CompleteAt BIGINT NOT NULL,
+				DeleteAt BIGINT NOT NULL,
+				LockAcquiredBy TEXT NULL,
+				LockAcquiredAt BIGINT NOT NULL
+			);
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`
+			CREATE TABLE BulkOperationItem (
+				BulkOperationID TEXT NOT NULL,
+				InstallationID TEXT NOT NULL,
+				State TEXT NOT NULL,
+				Error TEXT NOT NULL,
+				UpdateAt BIGINT NOT NULL,
+				PRIMARY KEY (BulkOperationID, InstallationID)
+			);
+		`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.43.0"), semver.MustParse("0.44.0"), func(e execer) error {
+		// Record installations skipped by scheduled backups.
+		_, err := e.Exec(`ALTER TABLE InstallationBackupSchedule ADD COLUMN LastError TEXT NOT NULL DEFAULT '';`)
+		if err != nil {
+			return err
+		}
+
 		return nil
 	}},
 }
@@ -0,0 +1,57 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+// resourceStateTables maps the resource types to the tables storing them.
+var resourceStateTables = map[string]string{
+	model.TypeCluster:                      "Cluster",
+	model.TypeInstallation:                 "Installation",
+	model.TypeClusterInstallation:          "ClusterInstallation",
+	model.TypeInstallationBackup:           backupTable,
+	model.TypeInstallationDBRestoration:    installationDBRestorationTable,
+	model.TypeInstallationDBMigration:      installationDBMigrationTable,
+	model.TypeInstallationClone:            installationCloneTable,
+	model.TypeInstallationClusterMigration: installationClusterMigrationTable,
+	model.TypeClusterDrain:                 clusterDrainTable,
+	model.TypeScheduledOperation:           scheduledOperationTable,
+	model.TypeBulkOperation:                bulkOperationTable,
+}
+
+type stateCount struct {
+	State string
+	Count int64
+}
+
+// GetResourceStateCounts returns the number of resources which are not
+// deleted in each state, keyed by resource type and state.
+func (sqlStore *SQLStore) GetResourceStateCounts() (map[string]map[string]int64, error) {
+	counts := make(map[string]map[string]int64, len(resourceStateTables))
+
+	for resourceType, table := range resourceStateTables {
+		var stateCounts []stateCount
+		err := sqlStore.selectBuilder(sqlStore.db, &stateCounts, sq.
+			Select("State", "COUNT(*) AS Count").
+			From(table).
+			Where("DeleteAt = 0").
+			GroupBy("State"),
+		)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to count %s states", resourceType)
+		}
+
+		counts[resourceType] = make(map[string]int64, len(stateCounts))
+		for _, stateCount := range stateCounts {
+			counts[resourceType][stateCount.State] = stateCount.Count
+		}
+	}
+
+	return counts, nil
+}
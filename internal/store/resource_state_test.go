@@ -0,0 +1,71 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetResourceStateCounts(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+
+	counts, err := sqlStore.GetResourceStateCounts()
+	require.NoError(t, err)
+	require.Len(t, counts, 11)
+	require.Empty(t, counts[model.TypeCluster])
+
+	for _, state := range []string{model.ClusterStateStable, model.ClusterStateStable, model.ClusterStateCreationRequested} {
+		err = sqlStore.CreateCluster(&model.Cluster{State: state}, nil)
+		require.NoError(t, err)
+	}
+
+	deletedCluster := &model.Cluster{State: model.ClusterStateStable}
+	err = sqlStore.CreateCluster(deletedCluster, nil)
+	require.NoError(t, err)
+	err = sqlStore.DeleteCluster(deletedCluster.ID)
+	require.NoError(t, err)
+
+	counts, err = sqlStore.GetResourceStateCounts()
+	require.NoError(t, err)
+	require.Equal(t, map[string]int64{
+		model.ClusterStateStable:            2,
+		model.ClusterStateCreationRequested: 1,
+	}, counts[model.TypeCluster])
+	require.Empty(t, counts[model.TypeInstallation])
+}
+
+type mockLockMetrics struct {
+	attempts map[string][]bool
+}
+
+func (m *mockLockMetrics) ObserveLockAttempt(table string, locked bool) {
+	m.attempts[table] = append(m.attempts[table], locked)
+}
+
+func TestLockMetrics(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	metrics := &mockLockMetrics{attempts: map[string][]bool{}}
+	sqlStore.SetMetrics(metrics)
+
+	cluster := &model.Cluster{}
+	err := sqlStore.CreateCluster(cluster, nil)
+	require.NoError(t, err)
+
+	locked, err := sqlStore.LockCluster(cluster.ID, model.NewID())
+	require.NoError(t, err)
+	require.True(t, locked)
+
+	locked, err = sqlStore.LockCluster(cluster.ID, model.NewID())
+	require.NoError(t, err)
+	require.False(t, locked)
+
+	require.Equal(t, map[string][]bool{"Cluster": {true, false}}, metrics.attempts)
+}
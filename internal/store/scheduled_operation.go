@@ -0,0 +1,241 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	scheduledOperationTable = "ScheduledOperation"
+)
+
+var scheduledOperationSelect sq.SelectBuilder
+
+func init() {
+	scheduledOperationSelect = sq.
+		Select("ID",
+			"InstallationID",
+			"Type",
+			"PatchRaw",
+			"RunAt",
+			"IntervalSeconds",
+			"State",
+			"LastRunAt",
+			"Error",
+			"CreateAt",
+			"DeleteAt",
+			"LockAcquiredBy",
+			"LockAcquiredAt",
+		).
+		From(scheduledOperationTable)
+}
+
+type rawScheduledOperation struct {
+	*model.ScheduledOperation
+	PatchRaw []byte
+}
+
+type rawScheduledOperations []*rawScheduledOperation
+
+func (r *rawScheduledOperation) toScheduledOperation() (*model.ScheduledOperation, error) {
+	// We only need to set values that are converted from a raw database format.
+	if len(r.PatchRaw) > 0 {
+		patch := &model.PatchInstallationRequest{}
+		err := json.Unmarshal(r.PatchRaw, patch)
+		if err != nil {
+			return nil, err
+		}
+		r.ScheduledOperation.Patch = patch
+	}
+
+	return r.ScheduledOperation, nil
+}
+
+func (r *rawScheduledOperations) toScheduledOperations() ([]*model.ScheduledOperation, error) {
+	if r == nil {
+		return []*model.ScheduledOperation{}, nil
+	}
+	operations := make([]*model.ScheduledOperation, 0, len(*r))
+
+	for _, raw := range *r {
+		operation, err := raw.toScheduledOperation()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create scheduled operation from raw")
+		}
+		operations = append(operations, operation)
+	}
+	return operations, nil
+}
+
+// CreateScheduledOperation records the given scheduled operation to the
+// database, assigning it a unique ID.
+func (sqlStore *SQLStore) CreateScheduledOperation(operation *model.ScheduledOperation) error {
+	operation.ID = model.NewID()
+	operation.CreateAt = GetMillis()
+	operation.State = model.ScheduledOperationStateScheduled
+
+	var patchRaw []byte
+	if operation.Patch != nil {
+		var err error
+		patchRaw, err = json.Marshal(operation.Patch)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal installation patch")
+		}
+	}
+
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Insert(scheduledOperationTable).
+		SetMap(map[string]interface{}{
+			"ID":              operation.ID,
+			"InstallationID":  operation.InstallationID,
+			"Type":            operation.Type,
+			"PatchRaw":        patchRaw,
+			"RunAt":           operation.RunAt,
+			"IntervalSeconds": operation.IntervalSeconds,
+			"State":           operation.State,
+			"LastRunAt":       0,
+			"Error":           "",
+			"CreateAt":        operation.CreateAt,
+			"DeleteAt":        0,
+			"LockAcquiredBy":  nil,
+			"LockAcquiredAt":  0,
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create scheduled operation")
+	}
+
+	return nil
+}
+
+// GetScheduledOperation fetches the given scheduled operation by id.
+func (sqlStore *SQLStore) GetScheduledOperation(id string) (*model.ScheduledOperation, error) {
+	var rawOperation rawScheduledOperation
+	err := sqlStore.getBuilder(sqlStore.db, &rawOperation,
+		scheduledOperationSelect.Where("ID = ?", id),
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get scheduled operation by id")
+	}
+
+	return rawOperation.toScheduledOperation()
+}
+
+// GetScheduledOperations fetches the given page of scheduled operations. The first page is 0.
+func (sqlStore *SQLStore) GetScheduledOperations(filter *model.ScheduledOperationFilter) ([]*model.ScheduledOperation, error) {
+	builder := scheduledOperationSelect.
+		OrderBy("RunAt ASC")
+	builder = applyPagingFilter(builder, filter.Paging)
+
+	if filter.InstallationID != "" {
+		builder = builder.Where("InstallationID = ?", filter.InstallationID)
+	}
+	if len(filter.States) > 0 {
+		builder = builder.Where(sq.Eq{"State": filter.States})
+	}
+
+	var rawOperations rawScheduledOperations
+	err := sqlStore.selectBuilder(sqlStore.db, &rawOperations, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for scheduled operations")
+	}
+
+	return rawOperations.toScheduledOperations()
+}
+
+// GetUnlockedScheduledOperationsPendingWork returns all unlocked scheduled
+// operations which are due.
+func (sqlStore *SQLStore) GetUnlockedScheduledOperationsPendingWork() ([]*model.ScheduledOperation, error) {
+	builder := scheduledOperationSelect.
+		Where("DeleteAt = 0").
+		Where("LockAcquiredAt = 0").
+		Where("State = ?", model.ScheduledOperationStateScheduled).
+		Where("RunAt <= ?", GetMillis()).
+		OrderBy("RunAt ASC")
+
+	var rawOperations rawScheduledOperations
+	err := sqlStore.selectBuilder(sqlStore.db, &rawOperations, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get scheduled operations pending work")
+	}
+
+	return rawOperations.toScheduledOperations()
+}
+
+// UpdateScheduledOperationRun updates the state and run times of the given
+// scheduled operation.
+func (sqlStore *SQLStore) UpdateScheduledOperationRun(operation *model.ScheduledOperation) error {
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Update(scheduledOperationTable).
+		SetMap(map[string]interface{}{
+			"State":     operation.State,
+			"RunAt":     operation.RunAt,
+			"LastRunAt": operation.LastRunAt,
+			"Error":     operation.Error,
+		}).
+		Where("ID = ?", operation.ID),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to update scheduled operation run")
+	}
+
+	return nil
+}
+
+// CancelScheduledOperation marks the given scheduled operation as cancelled
+// and deleted, but does not remove the record from the database. Operations
+// which already ran are only marked as deleted.
+func (sqlStore *SQLStore) CancelScheduledOperation(id string) error {
+	tx, err := sqlStore.beginTransaction(sqlStore.db)
+	if err != nil {
+		return errors.Wrap(err, "failed to start transaction")
+	}
+	defer tx.RollbackUnlessCommitted()
+
+	_, err = sqlStore.execBuilder(tx, sq.
+		Update(scheduledOperationTable).
+		Set("State", model.ScheduledOperationStateCancelled).
+		Where("ID = ?", id).
+		Where("State = ?", model.ScheduledOperationStateScheduled),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to cancel scheduled operation")
+	}
+
+	_, err = sqlStore.execBuilder(tx, sq.
+		Update(scheduledOperationTable).
+		Set("DeleteAt", GetMillis()).
+		Where("ID = ?", id).
+		Where("DeleteAt = 0"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to mark scheduled operation as deleted")
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// LockScheduledOperation marks the scheduled operation as locked for exclusive use by the caller.
+func (sqlStore *SQLStore) LockScheduledOperation(operationID, lockerID string) (bool, error) {
+	return sqlStore.lockRows(scheduledOperationTable, []string{operationID}, lockerID)
+}
+
+// UnlockScheduledOperation releases a lock previously acquired against a caller.
+func (sqlStore *SQLStore) UnlockScheduledOperation(operationID, lockerID string, force bool) (bool, error) {
+	return sqlStore.unlockRows(scheduledOperationTable, []string{operationID}, lockerID, force)
+}
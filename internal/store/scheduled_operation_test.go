@@ -0,0 +1,135 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduledOperations(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	version := "5.39.0"
+	now := GetMillis()
+
+	operation1 := &model.ScheduledOperation{
+		InstallationID:  model.NewID(),
+		Type:            model.ScheduledOperationTypeWakeUp,
+		RunAt:           now - 1000,
+		IntervalSeconds: 86400,
+	}
+	err := sqlStore.CreateScheduledOperation(operation1)
+	require.NoError(t, err)
+	require.NotEmpty(t, operation1.ID)
+	assert.Equal(t, model.ScheduledOperationStateScheduled, operation1.State)
+
+	operation2 := &model.ScheduledOperation{
+		InstallationID: model.NewID(),
+		Type:           model.ScheduledOperationTypeUpdate,
+		Patch:          &model.PatchInstallationRequest{Version: &version},
+		RunAt:          now - 2000,
+	}
+	err = sqlStore.CreateScheduledOperation(operation2)
+	require.NoError(t, err)
+
+	operation3 := &model.ScheduledOperation{
+		InstallationID: operation1.InstallationID,
+		Type:           model.ScheduledOperationTypeDelete,
+		RunAt:          now + 60*60*1000,
+	}
+	err = sqlStore.CreateScheduledOperation(operation3)
+	require.NoError(t, err)
+
+	t.Run("get operation", func(t *testing.T) {
+		operation, err := sqlStore.GetScheduledOperation(operation2.ID)
+		require.NoError(t, err)
+		assert.Equal(t, operation2, operation)
+	})
+
+	t.Run("get unknown operation", func(t *testing.T) {
+		operation, err := sqlStore.GetScheduledOperation(model.NewID())
+		require.NoError(t, err)
+		assert.Nil(t, operation)
+	})
+
+	t.Run("get operations", func(t *testing.T) {
+		operations, err := sqlStore.GetScheduledOperations(&model.ScheduledOperationFilter{Paging: model.AllPagesNotDeleted()})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.ScheduledOperation{operation2, operation1, operation3}, operations)
+
+		operations, err = sqlStore.GetScheduledOperations(&model.ScheduledOperationFilter{
+			InstallationID: operation1.InstallationID,
+			Paging:         model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.ScheduledOperation{operation1, operation3}, operations)
+	})
+
+	t.Run("pending work", func(t *testing.T) {
+		operations, err := sqlStore.GetUnlockedScheduledOperationsPendingWork()
+		require.NoError(t, err)
+		assert.Equal(t, []*model.ScheduledOperation{operation2, operation1}, operations)
+
+		locked, err := sqlStore.LockScheduledOperation(operation2.ID, "locker")
+		require.NoError(t, err)
+		require.True(t, locked)
+
+		operations, err = sqlStore.GetUnlockedScheduledOperationsPendingWork()
+		require.NoError(t, err)
+		assert.Equal(t, []*model.ScheduledOperation{operation1}, operations)
+
+		unlocked, err := sqlStore.UnlockScheduledOperation(operation2.ID, "locker", false)
+		require.NoError(t, err)
+		require.True(t, unlocked)
+	})
+
+	t.Run("update run", func(t *testing.T) {
+		operation2.State = model.ScheduledOperationStateFailed
+		operation2.LastRunAt = GetMillis()
+		operation2.Error = "failed"
+		err := sqlStore.UpdateScheduledOperationRun(operation2)
+		require.NoError(t, err)
+
+		operation, err := sqlStore.GetScheduledOperation(operation2.ID)
+		require.NoError(t, err)
+		assert.Equal(t, operation2, operation)
+
+		operations, err := sqlStore.GetScheduledOperations(&model.ScheduledOperationFilter{
+			States: []model.ScheduledOperationState{model.ScheduledOperationStateFailed},
+			Paging: model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []*model.ScheduledOperation{operation2}, operations)
+	})
+
+	t.Run("cancel operation", func(t *testing.T) {
+		err := sqlStore.CancelScheduledOperation(operation1.ID)
+		require.NoError(t, err)
+
+		operation, err := sqlStore.GetScheduledOperation(operation1.ID)
+		require.NoError(t, err)
+		assert.True(t, operation.IsDeleted())
+		assert.Equal(t, model.ScheduledOperationStateCancelled, operation.State)
+
+		err = sqlStore.CancelScheduledOperation(operation2.ID)
+		require.NoError(t, err)
+
+		operation, err = sqlStore.GetScheduledOperation(operation2.ID)
+		require.NoError(t, err)
+		assert.True(t, operation.IsDeleted())
+		assert.Equal(t, model.ScheduledOperationStateFailed, operation.State)
+
+		operations, err := sqlStore.GetUnlockedScheduledOperationsPendingWork()
+		require.NoError(t, err)
+		assert.Empty(t, operations)
+	})
+}
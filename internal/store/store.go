@@ -23,8 +23,14 @@ import (
 
 // SQLStore abstracts access to the database.
 type SQLStore struct {
-	db     *sqlx.DB
-	logger logrus.FieldLogger
+	db      *sqlx.DB
+	logger  logrus.FieldLogger
+	metrics lockMetrics
+}
+
+// lockMetrics records the outcome of attempts to lock rows.
+type lockMetrics interface {
+	ObserveLockAttempt(table string, locked bool)
 }
 
 // New constructs a new instance of SQLStore.
@@ -84,11 +90,16 @@ func New(dsn string, logger logrus.FieldLogger) (*SQLStore, error) {
 	}
 
 	return &SQLStore{
-		db,
-		logger,
+		db:     db,
+		logger: logger,
 	}, nil
 }
 
+// SetMetrics configures the store to record lock contention in the given metrics.
+func (sqlStore *SQLStore) SetMetrics(metrics lockMetrics) {
+	sqlStore.metrics = metrics
+}
+
 // queryer is an interface describing a resource that can query.
 //
 // It exactly matches sqlx.Queryer, existing simply to constrain sqlx usage to this file.
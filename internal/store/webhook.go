@@ -6,6 +6,7 @@ package store
 
 import (
 	"database/sql"
+	"encoding/json"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/mattermost/mattermost-cloud/model"
@@ -16,13 +17,50 @@ var webhookSelect sq.SelectBuilder
 
 func init() {
 	webhookSelect = sq.
-		Select("ID", "OwnerID", "URL", "CreateAt", "DeleteAt").From("Webhooks")
+		Select("ID", "OwnerID", "URL", "Secret", "SubscriptionRaw", "CreateAt", "DeleteAt").From("Webhooks")
+}
+
+type rawWebhook struct {
+	*model.Webhook
+	SubscriptionRaw []byte
+}
+
+type rawWebhooks []*rawWebhook
+
+func (r *rawWebhook) toWebhook() (*model.Webhook, error) {
+	// We only need to set values that are converted from a raw database format.
+	if len(r.SubscriptionRaw) > 0 {
+		subscription := model.WebhookSubscription{}
+		err := json.Unmarshal(r.SubscriptionRaw, &subscription)
+		if err != nil {
+			return nil, err
+		}
+		r.Webhook.Subscription = &subscription
+	}
+
+	return r.Webhook, nil
+}
+
+func (r *rawWebhooks) toWebhooks() ([]*model.Webhook, error) {
+	if r == nil {
+		return []*model.Webhook{}, nil
+	}
+	webhooks := make([]*model.Webhook, 0, len(*r))
+
+	for _, raw := range *r {
+		webhook, err := raw.toWebhook()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create webhook from raw")
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, nil
 }
 
 // GetWebhook fetches the given webhook by id.
 func (sqlStore *SQLStore) GetWebhook(id string) (*model.Webhook, error) {
-	var webhook model.Webhook
-	err := sqlStore.getBuilder(sqlStore.db, &webhook,
+	var rawWebhook rawWebhook
+	err := sqlStore.getBuilder(sqlStore.db, &rawWebhook,
 		webhookSelect.Where("ID = ?", id),
 	)
 	if err == sql.ErrNoRows {
@@ -31,7 +69,12 @@ func (sqlStore *SQLStore) GetWebhook(id string) (*model.Webhook, error) {
 		return nil, errors.Wrap(err, "failed to get webhook by id")
 	}
 
-	return &webhook, nil
+	webhook, err := rawWebhook.toWebhook()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert webhook from raw")
+	}
+
+	return webhook, nil
 }
 
 // GetWebhooks fetches the given page of created webhooks. The first page is 0.
@@ -45,28 +88,44 @@ func (sqlStore *SQLStore) GetWebhooks(filter *model.WebhookFilter) ([]*model.Web
 		builder = builder.Where("OwnerID = ?", filter.OwnerID)
 	}
 
-	var webhooks []*model.Webhook
-	err := sqlStore.selectBuilder(sqlStore.db, &webhooks, builder)
+	var rawWebhooks rawWebhooks
+	err := sqlStore.selectBuilder(sqlStore.db, &rawWebhooks, builder)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to query for webhooks")
 	}
 
+	webhooks, err := rawWebhooks.toWebhooks()
+	if err != nil {
+		return nil, err
+	}
+
 	return webhooks, nil
 }
 
 // CreateWebhook records the given webhook to the database, assigning it a unique ID.
 func (sqlStore *SQLStore) CreateWebhook(webhook *model.Webhook) error {
+	var subscription []byte
+	if webhook.Subscription != nil {
+		var err error
+		subscription, err = json.Marshal(webhook.Subscription)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal webhook subscription")
+		}
+	}
+
 	webhook.ID = model.NewID()
 	webhook.CreateAt = GetMillis()
 
 	_, err := sqlStore.execBuilder(sqlStore.db, sq.
 		Insert("Webhooks").
 		SetMap(map[string]interface{}{
-			"ID":       webhook.ID,
-			"OwnerID":  webhook.OwnerID,
-			"URL":      webhook.URL,
-			"CreateAt": webhook.CreateAt,
-			"DeleteAt": 0,
+			"ID":              webhook.ID,
+			"OwnerID":         webhook.OwnerID,
+			"URL":             webhook.URL,
+			"Secret":          webhook.Secret,
+			"SubscriptionRaw": subscription,
+			"CreateAt":        webhook.CreateAt,
+			"DeleteAt":        0,
 		}),
 	)
 	if err != nil {
@@ -0,0 +1,240 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	webhookDeliveryTable = "WebhookDelivery"
+)
+
+var webhookDeliverySelect sq.SelectBuilder
+
+func init() {
+	webhookDeliverySelect = sq.
+		Select("ID",
+			"WebhookID",
+			"PayloadRaw",
+			"State",
+			"Attempts",
+			"LastAttemptAt",
+			"NextAttemptAt",
+			"LastStatusCode",
+			"LastError",
+			"CreateAt",
+			"LockAcquiredBy",
+			"LockAcquiredAt",
+		).
+		From(webhookDeliveryTable)
+}
+
+type rawWebhookDelivery struct {
+	*model.WebhookDelivery
+	PayloadRaw []byte
+}
+
+type rawWebhookDeliveries []*rawWebhookDelivery
+
+func (r *rawWebhookDelivery) toWebhookDelivery() (*model.WebhookDelivery, error) {
+	// We only need to set values that are converted from a raw database format.
+	var err error
+	if len(r.PayloadRaw) > 0 {
+		payload := model.WebhookPayload{}
+		err = json.Unmarshal(r.PayloadRaw, &payload)
+		if err != nil {
+			return nil, err
+		}
+		r.WebhookDelivery.Payload = &payload
+	}
+
+	return r.WebhookDelivery, nil
+}
+
+func (r *rawWebhookDeliveries) toWebhookDeliveries() ([]*model.WebhookDelivery, error) {
+	if r == nil {
+		return []*model.WebhookDelivery{}, nil
+	}
+	deliveries := make([]*model.WebhookDelivery, 0, len(*r))
+
+	for _, raw := range *r {
+		delivery, err := raw.toWebhookDelivery()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create webhook delivery from raw")
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+// GetWebhookDelivery fetches the given webhook delivery by id.
+func (sqlStore *SQLStore) GetWebhookDelivery(id string) (*model.WebhookDelivery, error) {
+	var rawDelivery rawWebhookDelivery
+	err := sqlStore.getBuilder(sqlStore.db, &rawDelivery,
+		webhookDeliverySelect.Where("ID = ?", id),
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get webhook delivery by id")
+	}
+
+	delivery, err := rawDelivery.toWebhookDelivery()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert webhook delivery from raw")
+	}
+
+	return delivery, nil
+}
+
+// GetWebhookDeliveries fetches the given page of webhook deliveries, the newest first. The first page is 0.
+func (sqlStore *SQLStore) GetWebhookDeliveries(filter *model.WebhookDeliveryFilter) ([]*model.WebhookDelivery, error) {
+	builder := webhookDeliverySelect.
+		OrderBy("CreateAt DESC")
+
+	if filter.PerPage != model.AllPerPage {
+		builder = builder.
+			Limit(uint64(filter.PerPage)).
+			Offset(uint64(filter.Page * filter.PerPage))
+	}
+	if filter.WebhookID != "" {
+		builder = builder.Where("WebhookID = ?", filter.WebhookID)
+	}
+	if len(filter.States) > 0 {
+		builder = builder.Where(sq.Eq{"State": filter.States})
+	}
+
+	var rawDeliveries rawWebhookDeliveries
+	err := sqlStore.selectBuilder(sqlStore.db, &rawDeliveries, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for webhook deliveries")
+	}
+
+	deliveries, err := rawDeliveries.toWebhookDeliveries()
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// GetUnlockedWebhookDeliveriesPendingWork returns unlocked webhook deliveries
+// which are due for another delivery attempt.
+func (sqlStore *SQLStore) GetUnlockedWebhookDeliveriesPendingWork() ([]*model.WebhookDelivery, error) {
+	builder := webhookDeliverySelect.
+		Where("State = ?", model.WebhookDeliveryStatePending).
+		Where("NextAttemptAt <= ?", GetMillis()).
+		Where("LockAcquiredAt = 0").
+		OrderBy("CreateAt ASC")
+
+	var rawDeliveries rawWebhookDeliveries
+	err := sqlStore.selectBuilder(sqlStore.db, &rawDeliveries, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get webhook deliveries pending work")
+	}
+
+	deliveries, err := rawDeliveries.toWebhookDeliveries()
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// CreateWebhookDelivery records the given webhook delivery to the database, assigning it a unique ID.
+func (sqlStore *SQLStore) CreateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	payload, err := json.Marshal(delivery.Payload)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal webhook payload")
+	}
+
+	delivery.ID = model.NewID()
+	delivery.CreateAt = GetMillis()
+	if delivery.State == "" {
+		delivery.State = model.WebhookDeliveryStatePending
+	}
+
+	_, err = sqlStore.execBuilder(sqlStore.db, sq.
+		Insert(webhookDeliveryTable).
+		SetMap(map[string]interface{}{
+			"ID":             delivery.ID,
+			"WebhookID":      delivery.WebhookID,
+			"PayloadRaw":     payload,
+			"State":          delivery.State,
+			"Attempts":       delivery.Attempts,
+			"LastAttemptAt":  delivery.LastAttemptAt,
+			"NextAttemptAt":  delivery.NextAttemptAt,
+			"LastStatusCode": delivery.LastStatusCode,
+			"LastError":      delivery.LastError,
+			"CreateAt":       delivery.CreateAt,
+			"LockAcquiredBy": nil,
+			"LockAcquiredAt": 0,
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create webhook delivery")
+	}
+
+	return nil
+}
+
+// UpdateWebhookDelivery updates the state and the outcome of the latest
+// attempt of the given webhook delivery.
+func (sqlStore *SQLStore) UpdateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Update(webhookDeliveryTable).
+		SetMap(map[string]interface{}{
+			"State":          delivery.State,
+			"Attempts":       delivery.Attempts,
+			"LastAttemptAt":  delivery.LastAttemptAt,
+			"NextAttemptAt":  delivery.NextAttemptAt,
+			"LastStatusCode": delivery.LastStatusCode,
+			"LastError":      delivery.LastError,
+		}).
+		Where("ID = ?", delivery.ID),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to update webhook delivery")
+	}
+
+	return nil
+}
+
+// DeleteWebhookDeliveries permanently deletes the delivered and failed
+// webhook deliveries created before the given time in milliseconds, and
+// returns the number of deleted deliveries. Pending deliveries are kept.
+func (sqlStore *SQLStore) DeleteWebhookDeliveries(createdBefore int64) (int64, error) {
+	result, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Delete(webhookDeliveryTable).
+		Where("State != ?", model.WebhookDeliveryStatePending).
+		Where("CreateAt < ?", createdBefore),
+	)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to delete webhook deliveries")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get rows affected")
+	}
+
+	return rows, nil
+}
+
+// LockWebhookDelivery marks the webhook delivery as locked for exclusive use by the caller.
+func (sqlStore *SQLStore) LockWebhookDelivery(deliveryID, lockerID string) (bool, error) {
+	return sqlStore.lockRows(webhookDeliveryTable, []string{deliveryID}, lockerID)
+}
+
+// UnlockWebhookDelivery releases a lock previously acquired against a caller.
+func (sqlStore *SQLStore) UnlockWebhookDelivery(deliveryID, lockerID string, force bool) (bool, error) {
+	return sqlStore.unlockRows(webhookDeliveryTable, []string{deliveryID}, lockerID, force)
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDeliveries(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+
+	webhookID := model.NewID()
+	payload := &model.WebhookPayload{
+		Timestamp: 1,
+		ID:        model.NewID(),
+		Type:      model.TypeInstallation,
+		NewState:  model.InstallationStateStable,
+		OldState:  model.InstallationStateCreationRequested,
+		ExtraData: map[string]string{"Environment": "test"},
+	}
+
+	delivery1 := &model.WebhookDelivery{
+		WebhookID: webhookID,
+		Payload:   payload,
+	}
+	err := sqlStore.CreateWebhookDelivery(delivery1)
+	require.NoError(t, err)
+	require.NotEmpty(t, delivery1.ID)
+	require.Equal(t, model.WebhookDeliveryStatePending, delivery1.State)
+
+	time.Sleep(1 * time.Millisecond)
+
+	delivery2 := &model.WebhookDelivery{
+		WebhookID:     webhookID,
+		Payload:       payload,
+		NextAttemptAt: GetMillis() + 60*1000,
+	}
+	err = sqlStore.CreateWebhookDelivery(delivery2)
+	require.NoError(t, err)
+
+	delivery3 := &model.WebhookDelivery{
+		WebhookID: model.NewID(),
+		Payload:   payload,
+	}
+	err = sqlStore.CreateWebhookDelivery(delivery3)
+	require.NoError(t, err)
+
+	t.Run("get delivery", func(t *testing.T) {
+		delivery, err := sqlStore.GetWebhookDelivery(delivery1.ID)
+		require.NoError(t, err)
+		require.Equal(t, delivery1, delivery)
+
+		delivery, err = sqlStore.GetWebhookDelivery("unknown")
+		require.NoError(t, err)
+		require.Nil(t, delivery)
+	})
+
+	t.Run("get deliveries", func(t *testing.T) {
+		deliveries, err := sqlStore.GetWebhookDeliveries(&model.WebhookDeliveryFilter{
+			WebhookID: webhookID,
+			Paging:    model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, []*model.WebhookDelivery{delivery2, delivery1}, deliveries)
+
+		deliveries, err = sqlStore.GetWebhookDeliveries(&model.WebhookDeliveryFilter{
+			WebhookID: webhookID,
+			Paging:    model.Paging{Page: 1, PerPage: 1},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []*model.WebhookDelivery{delivery1}, deliveries)
+
+		deliveries, err = sqlStore.GetWebhookDeliveries(&model.WebhookDeliveryFilter{
+			States: []string{model.WebhookDeliveryStateDelivered},
+			Paging: model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		require.Empty(t, deliveries)
+	})
+
+	t.Run("get pending work", func(t *testing.T) {
+		deliveries, err := sqlStore.GetUnlockedWebhookDeliveriesPendingWork()
+		require.NoError(t, err)
+		require.Len(t, deliveries, 2)
+		require.Equal(t, delivery1.ID, deliveries[0].ID)
+		require.Equal(t, delivery3.ID, deliveries[1].ID)
+
+		locked, err := sqlStore.LockWebhookDelivery(delivery3.ID, "locker")
+		require.NoError(t, err)
+		require.True(t, locked)
+
+		deliveries, err = sqlStore.GetUnlockedWebhookDeliveriesPendingWork()
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+
+		unlocked, err := sqlStore.UnlockWebhookDelivery(delivery3.ID, "locker", false)
+		require.NoError(t, err)
+		require.True(t, unlocked)
+	})
+
+	t.Run("update delivery", func(t *testing.T) {
+		delivery1.State = model.WebhookDeliveryStateDelivered
+		delivery1.Attempts = 2
+		delivery1.LastAttemptAt = GetMillis()
+		delivery1.LastStatusCode = 200
+		delivery1.LastError = "previous error"
+		err := sqlStore.UpdateWebhookDelivery(delivery1)
+		require.NoError(t, err)
+
+		delivery, err := sqlStore.GetWebhookDelivery(delivery1.ID)
+		require.NoError(t, err)
+		require.Equal(t, delivery1, delivery)
+
+		deliveries, err := sqlStore.GetUnlockedWebhookDeliveriesPendingWork()
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		require.Equal(t, delivery3.ID, deliveries[0].ID)
+	})
+
+	t.Run("delete old deliveries", func(t *testing.T) {
+		deleted, err := sqlStore.DeleteWebhookDeliveries(delivery1.CreateAt)
+		require.NoError(t, err)
+		require.Zero(t, deleted)
+
+		deleted, err = sqlStore.DeleteWebhookDeliveries(GetMillis() + 1)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, deleted)
+
+		delivery, err := sqlStore.GetWebhookDelivery(delivery1.ID)
+		require.NoError(t, err)
+		require.Nil(t, delivery)
+
+		delivery, err = sqlStore.GetWebhookDelivery(delivery2.ID)
+		require.NoError(t, err)
+		require.NotNil(t, delivery)
+	})
+}
@@ -30,11 +30,16 @@ func TestWebhooks(t *testing.T) {
 		webhook1 := &model.Webhook{
 			OwnerID: "owner1",
 			URL:     "https://url1.com",
+			Secret:  "secret",
 		}
 
 		webhook2 := &model.Webhook{
 			OwnerID: "owner2",
 			URL:     "https://url2.com",
+			Subscription: &model.WebhookSubscription{
+				Types:   []string{model.TypeInstallation},
+				OwnerID: "installation-owner",
+			},
 		}
 
 		err := sqlStore.CreateWebhook(webhook1)
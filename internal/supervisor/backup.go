@@ -7,10 +7,12 @@ package supervisor
 import (
 	"time"
 
+	"github.com/mattermost/mattermost-cloud/internal/metrics"
 	"github.com/mattermost/mattermost-cloud/internal/provisioner"
 	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
 	"github.com/mattermost/mattermost-cloud/internal/webhook"
 	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -34,6 +36,10 @@ type installationBackupStore interface {
 	GetCluster(id string) (*model.Cluster, error)
 
 	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+	CreateEvent(event *model.Event) error
+	GetEvents(filter *model.EventFilter) ([]*model.Event, error)
 }
 
 // BackupProvisioner provisions backup jobs on a cluster.
@@ -52,23 +58,31 @@ type BackupSupervisor struct {
 	aws        aws.AWS
 	instanceID string
 	logger     log.FieldLogger
+	metrics    *metrics.CloudMetrics
 
 	backupOperator BackupProvisioner
 }
 
+// backupMeasuredTransitions are the backup transitions whose duration is recorded in metrics.
+var backupMeasuredTransitions = []measuredTransition{
+	{"backup", string(model.InstallationBackupStateBackupRequested), string(model.InstallationBackupStateBackupSucceeded)},
+}
+
 // NewBackupSupervisor creates a new BackupSupervisor.
 func NewBackupSupervisor(
 	store installationBackupStore,
 	backupOperator BackupProvisioner,
 	aws aws.AWS,
 	instanceID string,
-	logger log.FieldLogger) *BackupSupervisor {
+	logger log.FieldLogger,
+	metrics *metrics.CloudMetrics) *BackupSupervisor {
 	return &BackupSupervisor{
 		store:          store,
 		backupOperator: backupOperator,
 		aws:            aws,
 		instanceID:     instanceID,
 		logger:         logger,
+		metrics:        metrics,
 	}
 }
 
@@ -82,7 +96,7 @@ func (s *BackupSupervisor) Do() error {
 	installations, err := s.store.GetUnlockedInstallationBackupPendingWork()
 	if err != nil {
 		s.logger.WithError(err).Warn("Failed to query for backup pending work")
-		return nil
+		return errors.Wrap(err, "failed to query for backup pending work")
 	}
 
 	for _, installation := range installations {
@@ -121,7 +135,8 @@ func (s *BackupSupervisor) Supervise(backup *model.InstallationBackup) {
 
 	logger.Debugf("Supervising backup in state %s", backup.State)
 
-	newState := s.transitionBackup(backup, s.instanceID, logger)
+	start := time.Now()
+	newState, stateErr := s.transitionBackup(backup, s.instanceID, logger)
 
 	backup, err = s.store.GetInstallationBackup(backup.ID)
 	if err != nil {
@@ -145,11 +160,14 @@ func (s *BackupSupervisor) Supervise(backup *model.InstallationBackup) {
 	webhookPayload := &model.WebhookPayload{
 		Type:      model.TypeInstallationBackup,
 		ID:        backup.ID,
+		OwnerID:   installationOwnerID(s.store, backup.InstallationID, logger),
 		NewState:  string(backup.State),
 		OldState:  string(oldState),
 		Timestamp: time.Now().UnixNano(),
 		ExtraData: map[string]string{"Environment": s.aws.GetCloudEnvironmentName()},
 	}
+	observeTransition(s.store, s.metrics, backupMeasuredTransitions, webhookPayload, start, logger)
+	recordStateChangeEvent(s.store, webhookPayload, s.instanceID, stateErr, logger)
 	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
 	if err != nil {
 		logger.WithError(err).Error("Unable to process and send webhooks")
@@ -159,7 +177,7 @@ func (s *BackupSupervisor) Supervise(backup *model.InstallationBackup) {
 }
 
 // transitionBackup works with the given backup to transition it to a final state.
-func (s *BackupSupervisor) transitionBackup(backup *model.InstallationBackup, instanceID string, logger log.FieldLogger) model.InstallationBackupState {
+func (s *BackupSupervisor) transitionBackup(backup *model.InstallationBackup, instanceID string, logger log.FieldLogger) (model.InstallationBackupState, error) {
 	switch backup.State {
 	case model.InstallationBackupStateBackupRequested:
 		return s.triggerBackup(backup, instanceID, logger)
@@ -172,51 +190,51 @@ func (s *BackupSupervisor) transitionBackup(backup *model.InstallationBackup, in
 
 	default:
 		logger.Warnf("Found backup pending work in unexpected state %s", backup.State)
-		return backup.State
+		return backup.State, nil
 	}
 }
 
-func (s *BackupSupervisor) triggerBackup(backup *model.InstallationBackup, instanceID string, logger log.FieldLogger) model.InstallationBackupState {
+func (s *BackupSupervisor) triggerBackup(backup *model.InstallationBackup, instanceID string, logger log.FieldLogger) (model.InstallationBackupState, error) {
 	installation, err := s.store.GetInstallation(backup.InstallationID, false, false)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get installation")
-		return backup.State
+		return backup.State, nil
 	}
 	if installation == nil {
 		logger.Errorf("Installation, with id %q not found, setting backup as failed", backup.InstallationID)
-		return model.InstallationBackupStateBackupFailed
+		return model.InstallationBackupStateBackupFailed, errors.Errorf("installation %s not found", backup.InstallationID)
 	}
 
 	installationLock := newInstallationLock(installation.ID, instanceID, s.store, logger)
 	if !installationLock.TryLock() {
 		logger.Errorf("Failed to lock installation %s", installation.ID)
-		return backup.State
+		return backup.State, nil
 	}
 	defer installationLock.Unlock()
 
 	err = model.EnsureInstallationReadyForBackup(installation)
 	if err != nil {
 		logger.WithError(err).Errorf("Installation is not backup compatible %s", installation.ID)
-		return backup.State
+		return backup.State, nil
 	}
 
 	backupCI, ciLock, err := claimClusterInstallation(s.store, installation, instanceID, logger)
 	if err != nil {
 		logger.WithError(err).Error("Failed to claim Cluster Installation for backup")
-		return backup.State
+		return backup.State, nil
 	}
 	defer ciLock.Unlock()
 
 	cluster, err := s.store.GetCluster(backupCI.ClusterID)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get cluster")
-		return backup.State
+		return backup.State, nil
 	}
 
 	dataRes, err := s.backupOperator.TriggerBackup(backup, cluster, installation)
 	if err != nil {
 		logger.WithError(err).Error("Failed to trigger backup")
-		return backup.State
+		return backup.State, nil
 	}
 
 	backup.DataResidence = dataRes
@@ -225,32 +243,32 @@ func (s *BackupSupervisor) triggerBackup(backup *model.InstallationBackup, insta
 	err = s.store.UpdateInstallationBackupSchedulingData(backup)
 	if err != nil {
 		logger.Error("Failed to update backup data residency")
-		return backup.State
+		return backup.State, nil
 	}
 
-	return model.InstallationBackupStateBackupInProgress
+	return model.InstallationBackupStateBackupInProgress, nil
 }
 
-func (s *BackupSupervisor) monitorBackup(backup *model.InstallationBackup, instanceID string, logger log.FieldLogger) model.InstallationBackupState {
+func (s *BackupSupervisor) monitorBackup(backup *model.InstallationBackup, instanceID string, logger log.FieldLogger) (model.InstallationBackupState, error) {
 	cluster, err := getClusterForClusterInstallation(s.store, backup.ClusterInstallationID)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get cluster")
-		return backup.State
+		return backup.State, nil
 	}
 
 	startTime, err := s.backupOperator.CheckBackupStatus(backup, cluster)
 	if err != nil {
 		if err == provisioner.ErrJobBackoffLimitReached {
 			logger.WithError(err).Error("Backup job backoff limit reached, backup failed")
-			return model.InstallationBackupStateBackupFailed
+			return model.InstallationBackupStateBackupFailed, err
 		}
 		logger.WithError(err).Error("Failed to check backup state")
-		return backup.State
+		return backup.State, nil
 	}
 
 	if startTime <= 0 {
 		logger.Debugf("Backup in progress")
-		return backup.State
+		return backup.State, nil
 	}
 
 	backup.StartAt = startTime
@@ -258,41 +276,55 @@ func (s *BackupSupervisor) monitorBackup(backup *model.InstallationBackup, insta
 	err = s.store.UpdateInstallationBackupStartTime(backup)
 	if err != nil {
 		logger.Error("Failed to update backup data start time")
-		return backup.State
+		return backup.State, nil
 	}
 
-	return model.InstallationBackupStateBackupSucceeded
+	return model.InstallationBackupStateBackupSucceeded, nil
 }
 
-func (s *BackupSupervisor) deleteBackup(backup *model.InstallationBackup, instanceID string, logger log.FieldLogger) model.InstallationBackupState {
+func (s *BackupSupervisor) deleteBackup(backup *model.InstallationBackup, instanceID string, logger log.FieldLogger) (model.InstallationBackupState, error) {
 	cluster, err := getClusterForClusterInstallation(s.store, backup.ClusterInstallationID)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get cluster for backup")
-		return backup.State
+		return backup.State, nil
 	}
 
 	err = s.backupOperator.CleanupBackupJob(backup, cluster)
 	if err != nil {
 		logger.WithError(err).Error("Failed to cleanup backup from cluster")
-		return backup.State
+		return backup.State, nil
 	}
 
 	if backup.DataResidence.URL != aws.S3URL {
 		logger.WithError(err).Error("Only backups from S3 can be deleted")
-		return model.InstallationBackupStateDeletionFailed
+		return model.InstallationBackupStateDeletionFailed, errors.Errorf("only backups from S3 can be deleted, backup is stored at %q", backup.DataResidence.URL)
 	}
 
 	err = s.aws.S3EnsureObjectDeleted(backup.DataResidence.Bucket, backup.DataResidence.FullPath())
 	if err != nil {
 		logger.WithError(err).Error("Failed to delete backup from S3")
-		return backup.State
+		return backup.State, nil
+	}
+
+	if backup.DataResidence.HasFilestoreSnapshot() {
+		err = s.aws.S3EnsureBucketDirectoryDeleted(backup.DataResidence.Bucket, backup.DataResidence.FilestoreFullPath(), logger)
+		if err != nil {
+			logger.WithError(err).Error("Failed to delete backed up files from S3")
+			return backup.State, nil
+		}
+
+		err = s.aws.S3EnsureObjectDeleted(backup.DataResidence.Bucket, backup.DataResidence.FilestoreManifestFullPath())
+		if err != nil {
+			logger.WithError(err).Error("Failed to delete backed up files manifest from S3")
+			return backup.State, nil
+		}
 	}
 
 	err = s.store.DeleteInstallationBackup(backup.ID)
 	if err != nil {
 		logger.WithError(err).Error("Failed to mark backup as deleted")
-		return backup.State
+		return backup.State, nil
 	}
 
-	return model.InstallationBackupStateDeleted
+	return model.InstallationBackupStateDeleted, nil
 }
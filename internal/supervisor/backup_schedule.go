@@ -0,0 +1,275 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/common"
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/tools/cloud"
+	"github.com/mattermost/mattermost-cloud/internal/webhook"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// backupScheduleStore abstracts the database operations required by the backup schedule supervisor.
+type backupScheduleStore interface {
+	GetUnlockedInstallationBackupSchedulesPendingWork() ([]*model.InstallationBackupSchedule, error)
+	GetInstallationBackupSchedule(id string) (*model.InstallationBackupSchedule, error)
+	UpdateInstallationBackupScheduleBackupTimes(schedule *model.InstallationBackupSchedule) error
+	installationBackupScheduleLockStore
+
+	GetInstallation(installationID string, includeGroupConfig, includeGroupConfigOverrides bool) (*model.Installation, error)
+	GetInstallations(filter *model.InstallationFilter, includeGroupConfig, includeGroupConfigOverrides bool) ([]*model.Installation, error)
+	installationLockStore
+
+	IsInstallationBackupRunning(installationID string) (bool, error)
+	IsInstallationBackupBeingUsed(backupID string) (bool, error)
+	CreateInstallationBackup(backup *model.InstallationBackup) error
+	GetInstallationBackup(id string) (*model.InstallationBackup, error)
+	GetInstallationBackups(filter *model.InstallationBackupFilter) ([]*model.InstallationBackup, error)
+	UpdateInstallationBackupState(backup *model.InstallationBackup) error
+	installationBackupLockStore
+
+	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+	CreateEvent(event *model.Event) error
+}
+
+// BackupScheduleSupervisor requests the backups of installation backup
+// schedules when they are due and expires the backups which are no longer
+// retained by their schedule.
+//
+// Backups can only be requested for installations which are ready to be
+// backed up, for example hibernated installations; other installations are
+// skipped until the next scheduled backup.
+type BackupScheduleSupervisor struct {
+	store      backupScheduleStore
+	cloud      cloud.Provider
+	instanceID string
+	logger     log.FieldLogger
+}
+
+// NewBackupScheduleSupervisor creates a new BackupScheduleSupervisor.
+func NewBackupScheduleSupervisor(store backupScheduleStore, cloud cloud.Provider, instanceID string, logger log.FieldLogger) *BackupScheduleSupervisor {
+	return &BackupScheduleSupervisor{
+		store:      store,
+		cloud:      cloud,
+		instanceID: instanceID,
+		logger:     logger,
+	}
+}
+
+// Shutdown performs graceful shutdown tasks for the backup schedule supervisor.
+func (s *BackupScheduleSupervisor) Shutdown() {
+	s.logger.Debug("Shutting down backup schedule supervisor")
+}
+
+// Do looks for active backup schedules and works on them.
+func (s *BackupScheduleSupervisor) Do() error {
+	schedules, err := s.store.GetUnlockedInstallationBackupSchedulesPendingWork()
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to query for backup schedules pending work")
+		return errors.Wrap(err, "failed to query for backup schedules pending work")
+	}
+
+	for _, schedule := range schedules {
+		s.Supervise(schedule)
+	}
+
+	return nil
+}
+
+// Supervise requests the backups of the given schedule if they are due and
+// applies the retention rules of the schedule.
+func (s *BackupScheduleSupervisor) Supervise(schedule *model.InstallationBackupSchedule) {
+	logger := s.logger.WithFields(log.Fields{
+		"backupSchedule": schedule.ID,
+	})
+
+	lock := newBackupScheduleLock(schedule.ID, s.instanceID, s.store, logger)
+	if !lock.TryLock() {
+		return
+	}
+	defer lock.Unlock()
+
+	// Before working on the schedule, it is crucial that we ensure that it
+	// was not already worked on by another provisioning server.
+	originalNextBackupAt := schedule.NextBackupAt
+	schedule, err := s.store.GetInstallationBackupSchedule(schedule.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get refreshed backup schedule")
+		return
+	}
+	if schedule == nil || schedule.IsDeleted() {
+		logger.Debug("Backup schedule was deleted; skipping...")
+		return
+	}
+	if schedule.NextBackupAt != originalNextBackupAt {
+		logger.Warn("Another provisioner has worked on this backup schedule; skipping...")
+		return
+	}
+
+	now := store.GetMillis()
+	if schedule.IsDue(now) {
+		schedule.LastError = s.requestBackups(schedule, logger)
+		schedule.LastBackupAt = now
+		schedule.NextBackupAt = now + schedule.Interval().Milliseconds()
+		err = s.store.UpdateInstallationBackupScheduleBackupTimes(schedule)
+		if err != nil {
+			logger.WithError(err).Error("Failed to update backup schedule times")
+			return
+		}
+	}
+
+	s.applyRetention(schedule, logger)
+}
+
+// requestBackups requests a backup of every installation covered by the
+// schedule. It returns a description of the installations which were skipped,
+// or an empty string if every backup was requested.
+func (s *BackupScheduleSupervisor) requestBackups(schedule *model.InstallationBackupSchedule, logger log.FieldLogger) string {
+	installations, err := s.scheduledInstallations(schedule)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get installations of backup schedule")
+		return errors.Wrap(err, "failed to get installations of backup schedule").Error()
+	}
+
+	var skipped []string
+	for _, installation := range installations {
+		err = s.requestBackup(schedule, installation, logger.WithField("installation", installation.ID))
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("installation %s: %s", installation.ID, err))
+		}
+	}
+	if len(skipped) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("skipped %d installations; %s", len(skipped), strings.Join(skipped, "; "))
+}
+
+func (s *BackupScheduleSupervisor) scheduledInstallations(schedule *model.InstallationBackupSchedule) ([]*model.Installation, error) {
+	if schedule.GroupID != "" {
+		return s.store.GetInstallations(&model.InstallationFilter{
+			GroupID: schedule.GroupID,
+			Paging:  model.AllPagesNotDeleted(),
+		}, false, false)
+	}
+
+	installation, err := s.store.GetInstallation(schedule.InstallationID, false, false)
+	if err != nil {
+		return nil, err
+	}
+	if installation == nil || installation.State == model.InstallationStateDeleted {
+		return nil, nil
+	}
+
+	return []*model.Installation{installation}, nil
+}
+
+func (s *BackupScheduleSupervisor) requestBackup(schedule *model.InstallationBackupSchedule, installation *model.Installation, logger log.FieldLogger) error {
+	installationLock := newInstallationLock(installation.ID, s.instanceID, s.store, logger)
+	if !installationLock.TryLock() {
+		logger.Warn("Failed to lock installation; skipping scheduled backup")
+		return errors.New("failed to lock installation")
+	}
+	defer installationLock.Unlock()
+
+	installation, err := s.store.GetInstallation(installation.ID, false, false)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get refreshed installation")
+		return errors.Wrap(err, "failed to get refreshed installation")
+	}
+
+	backup, err := common.TriggerScheduledInstallationBackup(s.store, installation, schedule.ID, s.cloud.GetCloudEnvironmentName(), logger)
+	if err != nil {
+		logger.WithError(err).Warn("Installation cannot be backed up; skipping scheduled backup")
+		return err
+	}
+
+	logger.WithField("backup", backup.ID).Info("Requested scheduled installation backup")
+
+	return nil
+}
+
+// applyRetention requests the deletion of the scheduled backups which are
+// no longer retained.
+func (s *BackupScheduleSupervisor) applyRetention(schedule *model.InstallationBackupSchedule, logger log.FieldLogger) {
+	if !schedule.HasRetention() {
+		return
+	}
+
+	backups, err := s.store.GetInstallationBackups(&model.InstallationBackupFilter{
+		ScheduleID: schedule.ID,
+		States:     []model.InstallationBackupState{model.InstallationBackupStateBackupSucceeded},
+		Paging:     model.AllPagesNotDeleted(),
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to get scheduled backups")
+		return
+	}
+
+	for _, backup := range schedule.ExpiredBackups(backups, time.Now()) {
+		s.expireBackup(backup, logger.WithField("backup", backup.ID))
+	}
+}
+
+func (s *BackupScheduleSupervisor) expireBackup(backup *model.InstallationBackup, logger log.FieldLogger) {
+	lock := newBackupLock(backup.ID, s.instanceID, s.store, logger)
+	if !lock.TryLock() {
+		return
+	}
+	defer lock.Unlock()
+
+	backup, err := s.store.GetInstallationBackup(backup.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get refreshed backup")
+		return
+	}
+	if backup.State != model.InstallationBackupStateBackupSucceeded || backup.APISecurityLock {
+		return
+	}
+
+	isUsed, err := s.store.IsInstallationBackupBeingUsed(backup.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to check if backup is being used")
+		return
+	}
+	if isUsed {
+		logger.Debug("Expired backup is being used by migration or restoration; skipping deletion")
+		return
+	}
+
+	oldState := backup.State
+	backup.State = model.InstallationBackupStateDeletionRequested
+	err = s.store.UpdateInstallationBackupState(backup)
+	if err != nil {
+		logger.WithError(err).Error("Failed to request deletion of expired backup")
+		return
+	}
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeInstallationBackup,
+		ID:        backup.ID,
+		OwnerID:   installationOwnerID(s.store, backup.InstallationID, logger),
+		NewState:  string(backup.State),
+		OldState:  string(oldState),
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"Installation": backup.InstallationID, "Environment": s.cloud.GetCloudEnvironmentName()},
+	}
+	recordStateChangeEvent(s.store, webhookPayload, s.instanceID, nil, logger)
+	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	logger.Info("Requested deletion of expired scheduled backup")
+}
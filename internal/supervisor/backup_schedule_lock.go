@@ -0,0 +1,49 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	log "github.com/sirupsen/logrus"
+)
+
+type installationBackupScheduleLockStore interface {
+	LockInstallationBackupSchedule(scheduleID, lockerID string) (bool, error)
+	UnlockInstallationBackupSchedule(scheduleID, lockerID string, force bool) (bool, error)
+}
+
+type backupScheduleLock struct {
+	scheduleID string
+	lockerID   string
+	store      installationBackupScheduleLockStore
+	logger     log.FieldLogger
+}
+
+func newBackupScheduleLock(scheduleID, lockerID string, store installationBackupScheduleLockStore, logger log.FieldLogger) *backupScheduleLock {
+	return &backupScheduleLock{
+		scheduleID: scheduleID,
+		lockerID:   lockerID,
+		store:      store,
+		logger:     logger,
+	}
+}
+
+func (l *backupScheduleLock) TryLock() bool {
+	locked, err := l.store.LockInstallationBackupSchedule(l.scheduleID, l.lockerID)
+	if err != nil {
+		l.logger.WithError(err).Error("failed to lock backup schedule")
+		return false
+	}
+
+	return locked
+}
+
+func (l *backupScheduleLock) Unlock() {
+	unlocked, err := l.store.UnlockInstallationBackupSchedule(l.scheduleID, l.lockerID, false)
+	if err != nil {
+		l.logger.WithError(err).Error("failed to unlock backup schedule")
+	} else if unlocked != true {
+		l.logger.Error("failed to release lock for backup schedule")
+	}
+}
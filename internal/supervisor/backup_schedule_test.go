@@ -0,0 +1,182 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/internal/testutil"
+	"github.com/mattermost/mattermost-cloud/internal/tools/cloud"
+	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackupScheduleSupervisor(t *testing.T) {
+	t.Run("request backup of installation", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+
+		installation := testutil.CreateBackupCompatibleInstallation(t, sqlStore)
+
+		schedule := &model.InstallationBackupSchedule{
+			InstallationID:  installation.ID,
+			IntervalSeconds: 3600,
+		}
+		err := sqlStore.CreateInstallationBackupSchedule(schedule)
+		require.NoError(t, err)
+
+		scheduleSupervisor := supervisor.NewBackupScheduleSupervisor(sqlStore, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
+		err = scheduleSupervisor.Do()
+		require.NoError(t, err)
+
+		backups, err := sqlStore.GetInstallationBackups(&model.InstallationBackupFilter{
+			ScheduleID: schedule.ID,
+			Paging:     model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		require.Len(t, backups, 1)
+		assert.Equal(t, installation.ID, backups[0].InstallationID)
+		assert.Equal(t, model.InstallationBackupStateBackupRequested, backups[0].State)
+
+		schedule, err = sqlStore.GetInstallationBackupSchedule(schedule.ID)
+		require.NoError(t, err)
+		assert.Equal(t, schedule.LastBackupAt+3600*1000, schedule.NextBackupAt)
+		assert.Empty(t, schedule.LastError)
+
+		// The next backup is not due yet.
+		err = scheduleSupervisor.Do()
+		require.NoError(t, err)
+
+		backups, err = sqlStore.GetInstallationBackups(&model.InstallationBackupFilter{
+			ScheduleID: schedule.ID,
+			Paging:     model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		assert.Len(t, backups, 1)
+	})
+
+	t.Run("request backups of group installations", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+
+		group := &model.Group{Name: "group"}
+		err := sqlStore.CreateGroup(group)
+		require.NoError(t, err)
+
+		installation1 := testutil.CreateBackupCompatibleInstallation(t, sqlStore)
+		installation2 := testutil.CreateBackupCompatibleInstallation(t, sqlStore)
+		installation3 := testutil.CreateBackupCompatibleInstallation(t, sqlStore)
+		for _, installation := range []*model.Installation{installation1, installation2} {
+			installation.GroupID = &group.ID
+			err = sqlStore.UpdateInstallation(installation)
+			require.NoError(t, err)
+		}
+		installation2.State = model.InstallationStateStable
+		err = sqlStore.UpdateInstallationState(installation2)
+		require.NoError(t, err)
+
+		schedule := &model.InstallationBackupSchedule{
+			GroupID:         group.ID,
+			IntervalSeconds: 3600,
+		}
+		err = sqlStore.CreateInstallationBackupSchedule(schedule)
+		require.NoError(t, err)
+
+		scheduleSupervisor := supervisor.NewBackupScheduleSupervisor(sqlStore, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
+		scheduleSupervisor.Supervise(schedule)
+
+		backups, err := sqlStore.GetInstallationBackups(&model.InstallationBackupFilter{
+			ScheduleID: schedule.ID,
+			Paging:     model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		require.Len(t, backups, 1)
+		assert.Equal(t, installation1.ID, backups[0].InstallationID)
+
+		backups, err = sqlStore.GetInstallationBackups(&model.InstallationBackupFilter{
+			InstallationID: installation3.ID,
+			Paging:         model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		assert.Empty(t, backups)
+
+		schedule, err = sqlStore.GetInstallationBackupSchedule(schedule.ID)
+		require.NoError(t, err)
+		assert.Contains(t, schedule.LastError, "skipped 1 installations")
+		assert.Contains(t, schedule.LastError, installation2.ID)
+		assert.NotContains(t, schedule.LastError, installation1.ID)
+	})
+
+	t.Run("expire backups", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+
+		installation := testutil.CreateBackupCompatibleInstallation(t, sqlStore)
+
+		schedule := &model.InstallationBackupSchedule{
+			InstallationID:    installation.ID,
+			IntervalSeconds:   3600,
+			RetentionKeepLast: 1,
+		}
+		err := sqlStore.CreateInstallationBackupSchedule(schedule)
+		require.NoError(t, err)
+		schedule.NextBackupAt = store.GetMillis() + 3600*1000
+		err = sqlStore.UpdateInstallationBackupScheduleBackupTimes(schedule)
+		require.NoError(t, err)
+
+		var backups []*model.InstallationBackup
+		for i := 0; i < 3; i++ {
+			backup := &model.InstallationBackup{
+				InstallationID: installation.ID,
+				ScheduleID:     schedule.ID,
+				State:          model.InstallationBackupStateBackupSucceeded,
+			}
+			err = sqlStore.CreateInstallationBackup(backup)
+			require.NoError(t, err)
+			backups = append(backups, backup)
+			time.Sleep(1 * time.Millisecond)
+		}
+		manualBackup := &model.InstallationBackup{
+			InstallationID: installation.ID,
+			State:          model.InstallationBackupStateBackupSucceeded,
+		}
+		err = sqlStore.CreateInstallationBackup(manualBackup)
+		require.NoError(t, err)
+
+		// Backups used by a restoration are not expired.
+		restoration := &model.InstallationDBRestorationOperation{
+			InstallationID: installation.ID,
+			BackupID:       backups[0].ID,
+			State:          model.InstallationDBRestorationStateRequested,
+		}
+		err = sqlStore.CreateInstallationDBRestorationOperation(restoration)
+		require.NoError(t, err)
+
+		scheduleSupervisor := supervisor.NewBackupScheduleSupervisor(sqlStore, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
+		scheduleSupervisor.Supervise(schedule)
+
+		expectedStates := map[string]model.InstallationBackupState{
+			backups[0].ID:   model.InstallationBackupStateBackupSucceeded,
+			backups[1].ID:   model.InstallationBackupStateDeletionRequested,
+			backups[2].ID:   model.InstallationBackupStateBackupSucceeded,
+			manualBackup.ID: model.InstallationBackupStateBackupSucceeded,
+		}
+		for id, state := range expectedStates {
+			backup, err := sqlStore.GetInstallationBackup(id)
+			require.NoError(t, err)
+			assert.Equal(t, state, backup.State)
+		}
+
+		events, err := sqlStore.GetEvents(&model.EventFilter{ResourceID: backups[1].ID, Paging: model.AllPagesNotDeleted()})
+		require.NoError(t, err)
+		assert.Len(t, events, 1)
+	})
+}
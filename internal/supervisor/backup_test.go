@@ -104,6 +104,22 @@ func (s mockBackupStore) GetWebhooks(filter *model.WebhookFilter) ([]*model.Webh
 	return nil, nil
 }
 
+func (s mockBackupStore) CreateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	return nil
+}
+
+func (s mockBackupStore) UpdateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	return nil
+}
+
+func (s mockBackupStore) CreateEvent(event *model.Event) error {
+	return nil
+}
+
+func (s mockBackupStore) GetEvents(filter *model.EventFilter) ([]*model.Event, error) {
+	return nil, nil
+}
+
 type mockBackupProvisioner struct {
 	BackupStartTime int64
 	err             error
@@ -127,7 +143,7 @@ func TestBackupSupervisorDo(t *testing.T) {
 		mockStore := &mockBackupStore{}
 		mockBackupOp := &mockBackupProvisioner{}
 
-		backupSupervisor := supervisor.NewBackupSupervisor(mockStore, mockBackupOp, &mockAWS{}, "instanceID", logger)
+		backupSupervisor := supervisor.NewBackupSupervisor(mockStore, mockBackupOp, &mockAWS{}, "instanceID", logger, cloudMetrics)
 		err := backupSupervisor.Do()
 		require.NoError(t, err)
 
@@ -159,7 +175,7 @@ func TestBackupSupervisorDo(t *testing.T) {
 			UnlockChan: make(chan interface{}),
 		}
 
-		backupSupervisor := supervisor.NewBackupSupervisor(mockStore, &mockBackupProvisioner{}, &mockAWS{}, "instanceID", logger)
+		backupSupervisor := supervisor.NewBackupSupervisor(mockStore, &mockBackupProvisioner{}, &mockAWS{}, "instanceID", logger, cloudMetrics)
 		err := backupSupervisor.Do()
 		require.NoError(t, err)
 
@@ -184,7 +200,7 @@ func TestBackupMetadataSupervisorSupervise(t *testing.T) {
 		err := sqlStore.CreateInstallationBackup(backupMeta)
 		require.NoError(t, err)
 
-		backupSupervisor := supervisor.NewBackupSupervisor(sqlStore, mockBackupOp, &mockAWS{}, "instanceID", logger)
+		backupSupervisor := supervisor.NewBackupSupervisor(sqlStore, mockBackupOp, &mockAWS{}, "instanceID", logger, cloudMetrics)
 		backupSupervisor.Supervise(backupMeta)
 
 		// Assert
@@ -212,7 +228,7 @@ func TestBackupMetadataSupervisorSupervise(t *testing.T) {
 		err = sqlStore.CreateInstallationBackup(backupMeta)
 		require.NoError(t, err)
 
-		backupSupervisor := supervisor.NewBackupSupervisor(sqlStore, mockBackupOp, &mockAWS{}, "instanceID", logger)
+		backupSupervisor := supervisor.NewBackupSupervisor(sqlStore, mockBackupOp, &mockAWS{}, "instanceID", logger, cloudMetrics)
 		backupSupervisor.Supervise(backupMeta)
 
 		// Assert
@@ -233,7 +249,7 @@ func TestBackupMetadataSupervisorSupervise(t *testing.T) {
 		err := sqlStore.CreateInstallationBackup(backupMeta)
 		require.NoError(t, err)
 
-		backupSupervisor := supervisor.NewBackupSupervisor(sqlStore, mockBackupOp, &mockAWS{}, "instanceID", logger)
+		backupSupervisor := supervisor.NewBackupSupervisor(sqlStore, mockBackupOp, &mockAWS{}, "instanceID", logger, cloudMetrics)
 		backupSupervisor.Supervise(backupMeta)
 
 		// Assert
@@ -283,7 +299,7 @@ func TestBackupMetadataSupervisorSupervise(t *testing.T) {
 				err := sqlStore.CreateInstallationBackup(backupMeta)
 				require.NoError(t, err)
 
-				backupSupervisor := supervisor.NewBackupSupervisor(sqlStore, testCase.mockBackupOp, &mockAWS{}, "instanceID", logger)
+				backupSupervisor := supervisor.NewBackupSupervisor(sqlStore, testCase.mockBackupOp, &mockAWS{}, "instanceID", logger, cloudMetrics)
 				backupSupervisor.Supervise(backupMeta)
 
 				// Assert
@@ -319,11 +335,14 @@ func TestBackupMetadataSupervisorSupervise(t *testing.T) {
 			Bucket:     "my-bucket",
 			PathPrefix: installation.ID,
 			ObjectKey:  "backup-123",
+
+			FilestorePrefix:      "backup-123-filestore",
+			FilestoreManifestKey: "backup-123-filestore-manifest.txt",
 		}
 		err = sqlStore.UpdateInstallationBackupSchedulingData(backup)
 		require.NoError(t, err)
 
-		backupSupervisor := supervisor.NewBackupSupervisor(sqlStore, mockBackupOp, &mockAWS{}, "instanceID", logger)
+		backupSupervisor := supervisor.NewBackupSupervisor(sqlStore, mockBackupOp, &mockAWS{}, "instanceID", logger, cloudMetrics)
 		backupSupervisor.Supervise(backup)
 
 		// Assert
@@ -331,6 +350,7 @@ func TestBackupMetadataSupervisorSupervise(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, model.InstallationBackupStateDeleted, backup.State)
 		assert.NotEqualValues(t, 0, backup.DeleteAt)
+		assert.True(t, backup.DataResidence.HasFilestoreSnapshot())
 	})
 
 	t.Run("full backup lifecycle", func(t *testing.T) {
@@ -349,7 +369,7 @@ func TestBackupMetadataSupervisorSupervise(t *testing.T) {
 		require.NoError(t, err)
 
 		// Requested -> InProgress
-		backupSupervisor := supervisor.NewBackupSupervisor(sqlStore, mockBackupOp, &mockAWS{}, "instanceID", logger)
+		backupSupervisor := supervisor.NewBackupSupervisor(sqlStore, mockBackupOp, &mockAWS{}, "instanceID", logger, cloudMetrics)
 		backupSupervisor.Supervise(backup)
 
 		backup, err = sqlStore.GetInstallationBackup(backup.ID)
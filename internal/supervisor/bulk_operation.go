@@ -0,0 +1,305 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/tools/cloud"
+	"github.com/mattermost/mattermost-cloud/internal/webhook"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// bulkOperationStore abstracts the database operations required by the bulk operation supervisor.
+type bulkOperationStore interface {
+	GetUnlockedBulkOperationsPendingWork() ([]*model.BulkOperation, error)
+	GetBulkOperation(id string) (*model.BulkOperation, error)
+	UpdateBulkOperationState(operation *model.BulkOperation) error
+	GetBulkOperationItems(operationID string) ([]*model.BulkOperationItem, error)
+	UpdateBulkOperationItem(item *model.BulkOperationItem) error
+	bulkOperationLockStore
+
+	GetAnnotationsForInstallation(installationID string) ([]*model.Annotation, error)
+	CreateInstallationAnnotations(installationID string, annotations []*model.Annotation) ([]*model.Annotation, error)
+	installationStateChangeStore
+}
+
+// BulkOperationSupervisor works on the installations targeted by bulk
+// operations.
+//
+// On each pass, installations for which a state change was requested are
+// checked for having settled, and pending installations are worked on until
+// the number of installations with a requested state change reaches the
+// operation concurrency. Installations which are being worked on by something
+// else are retried on the next pass. An operation is completed once all of
+// its installations are done, whether they succeeded or failed.
+type BulkOperationSupervisor struct {
+	store      bulkOperationStore
+	cloud      cloud.Provider
+	instanceID string
+	logger     log.FieldLogger
+}
+
+// NewBulkOperationSupervisor creates a new BulkOperationSupervisor.
+func NewBulkOperationSupervisor(store bulkOperationStore, cloud cloud.Provider, instanceID string, logger log.FieldLogger) *BulkOperationSupervisor {
+	return &BulkOperationSupervisor{
+		store:      store,
+		cloud:      cloud,
+		instanceID: instanceID,
+		logger:     logger,
+	}
+}
+
+// Shutdown performs graceful shutdown tasks for the bulk operation supervisor.
+func (s *BulkOperationSupervisor) Shutdown() {
+	s.logger.Debug("Shutting down bulk operation supervisor")
+}
+
+// Do looks for bulk operations in progress and works on them.
+func (s *BulkOperationSupervisor) Do() error {
+	operations, err := s.store.GetUnlockedBulkOperationsPendingWork()
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to query for bulk operations pending work")
+		return errors.Wrap(err, "failed to query for bulk operations pending work")
+	}
+
+	for _, operation := range operations {
+		s.Supervise(operation)
+	}
+
+	return nil
+}
+
+// Supervise works on the installations of the given bulk operation.
+func (s *BulkOperationSupervisor) Supervise(operation *model.BulkOperation) {
+	logger := s.logger.WithFields(log.Fields{
+		"bulkOperation": operation.ID,
+		"type":          operation.Type,
+	})
+
+	lock := newBulkOperationLock(operation.ID, s.instanceID, s.store, logger)
+	if !lock.TryLock() {
+		return
+	}
+	defer lock.Unlock()
+
+	// The operation may have been cancelled since it was fetched.
+	operation, err := s.store.GetBulkOperation(operation.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get refreshed bulk operation")
+		return
+	}
+	if operation == nil || operation.IsDeleted() || !operation.IsInProgress() {
+		logger.Debug("Bulk operation is not in progress anymore; skipping...")
+		return
+	}
+
+	items, err := s.store.GetBulkOperationItems(operation.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get bulk operation items")
+		return
+	}
+
+	var inFlight int64
+	for _, item := range items {
+		if item.State != model.BulkOperationItemStateRequested {
+			continue
+		}
+		itemLogger := logger.WithField("installation", item.InstallationID)
+		if s.checkRequested(operation, item, itemLogger) {
+			s.updateItem(item, itemLogger)
+		}
+		if item.State == model.BulkOperationItemStateRequested {
+			inFlight++
+		}
+	}
+
+	for _, item := range items {
+		if item.State != model.BulkOperationItemStatePending {
+			continue
+		}
+		if inFlight >= operation.Concurrency {
+			break
+		}
+		itemLogger := logger.WithField("installation", item.InstallationID)
+		if s.run(operation, item, itemLogger) {
+			continue
+		}
+		s.updateItem(item, itemLogger)
+		if item.State == model.BulkOperationItemStateRequested {
+			inFlight++
+		}
+	}
+
+	for _, item := range items {
+		if !item.IsDone() {
+			return
+		}
+	}
+
+	oldState := operation.State
+	operation.State = model.BulkOperationStateCompleted
+	operation.CompleteAt = store.GetMillis()
+	err = s.store.UpdateBulkOperationState(operation)
+	if err != nil {
+		logger.WithError(err).Error("Failed to mark bulk operation as completed")
+		return
+	}
+
+	logger.Infof("Bulk operation completed for %d installations", len(items))
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeBulkOperation,
+		ID:        operation.ID,
+		OwnerID:   operation.Filter.OwnerID,
+		NewState:  string(operation.State),
+		OldState:  string(oldState),
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"Type": string(operation.Type), "Environment": s.cloud.GetCloudEnvironmentName()},
+	}
+	recordStateChangeEvent(s.store, webhookPayload, s.instanceID, nil, logger)
+	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+}
+
+// updateItem records the outcome of the bulk operation for an installation.
+func (s *BulkOperationSupervisor) updateItem(item *model.BulkOperationItem, logger log.FieldLogger) {
+	if item.State == model.BulkOperationItemStateFailed {
+		logger.WithField("error", item.Error).Warn("Bulk operation failed for installation")
+	}
+
+	err := s.store.UpdateBulkOperationItem(item)
+	if err != nil {
+		logger.WithError(err).Error("Failed to update bulk operation item")
+	}
+}
+
+// checkRequested checks whether the installation of the given requested item
+// settled. It returns true if the item state changed.
+func (s *BulkOperationSupervisor) checkRequested(operation *model.BulkOperation, item *model.BulkOperationItem, logger log.FieldLogger) bool {
+	installation, err := s.store.GetInstallation(item.InstallationID, false, false)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get installation")
+		return false
+	}
+	if installation == nil {
+		item.State = model.BulkOperationItemStateFailed
+		item.Error = "installation does not exist"
+		return true
+	}
+
+	if installation.State == operation.Type.SettledInstallationState() {
+		item.State = model.BulkOperationItemStateSucceeded
+		return true
+	}
+	for _, state := range model.AllInstallationStatesPendingWork {
+		if installation.State == state {
+			return false
+		}
+	}
+
+	item.State = model.BulkOperationItemStateFailed
+	item.Error = errors.Errorf("installation settled in state %q", installation.State).Error()
+	return true
+}
+
+// run applies the bulk operation to the installation of the given pending
+// item. It returns true if the installation should be retried on the next
+// pass.
+func (s *BulkOperationSupervisor) run(operation *model.BulkOperation, item *model.BulkOperationItem, logger log.FieldLogger) bool {
+	installation, installationLock, retry, err := lockInstallationForStateChange(s.store, item.InstallationID, s.instanceID, logger)
+	if err != nil {
+		item.State = model.BulkOperationItemStateFailed
+		item.Error = err.Error()
+		return false
+	}
+	if retry {
+		return true
+	}
+	defer installationLock.Unlock()
+
+	if operation.Type == model.BulkOperationTypeAddAnnotations {
+		return s.addAnnotations(operation, item, installation, logger)
+	}
+
+	newState := operation.Type.InstallationState()
+	if operation.Type != model.BulkOperationTypeUpdate && installation.State == operation.Type.SettledInstallationState() {
+		logger.Debugf("Installation is already in %q state", installation.State)
+		item.State = model.BulkOperationItemStateSucceeded
+		return false
+	}
+
+	requested, retry, err := requestInstallationStateChange(s.store, installation, newState, operation.Patch, s.instanceID, s.cloud.GetCloudEnvironmentName(), logger)
+	if err != nil {
+		item.State = model.BulkOperationItemStateFailed
+		item.Error = err.Error()
+		return false
+	}
+	if retry {
+		return true
+	}
+	if !requested {
+		item.State = model.BulkOperationItemStateSucceeded
+		return false
+	}
+
+	item.State = model.BulkOperationItemStateRequested
+	logger.Infof("Bulk operation requested installation state %q", newState)
+
+	return false
+}
+
+// addAnnotations adds the annotations of the bulk operation which are not
+// set yet to the given installation. It returns true if the installation
+// should be retried on the next pass.
+func (s *BulkOperationSupervisor) addAnnotations(operation *model.BulkOperation, item *model.BulkOperationItem, installation *model.Installation, logger log.FieldLogger) bool {
+	existing, err := s.store.GetAnnotationsForInstallation(installation.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get installation annotations")
+		return true
+	}
+
+	annotations, err := model.AnnotationsFromStringSlice(operation.Annotations)
+	if err != nil {
+		item.State = model.BulkOperationItemStateFailed
+		item.Error = err.Error()
+		return false
+	}
+
+	var missing []*model.Annotation
+	for _, annotation := range annotations {
+		found := false
+		for _, existingAnnotation := range existing {
+			if existingAnnotation.Name == annotation.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, annotation)
+		}
+	}
+
+	if len(missing) > 0 {
+		_, err = s.store.CreateInstallationAnnotations(installation.ID, missing)
+		if errors.Is(err, store.ErrInstallationAnnotationDoNotMatchClusters) {
+			item.State = model.BulkOperationItemStateFailed
+			item.Error = err.Error()
+			return false
+		}
+		if err != nil {
+			logger.WithError(err).Error("Failed to create installation annotations")
+			return true
+		}
+	}
+
+	item.State = model.BulkOperationItemStateSucceeded
+	return false
+}
@@ -0,0 +1,244 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/internal/tools/cloud"
+	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkOperationSupervisor(t *testing.T) {
+	setupInstallation := func(t *testing.T, sqlStore *store.SQLStore, ownerID, state string, annotations []*model.Annotation) *model.Installation {
+		installation := &model.Installation{
+			OwnerID: ownerID,
+			Version: "5.38.0",
+			DNS:     model.NewID() + ".example.com",
+			State:   state,
+		}
+		err := sqlStore.CreateInstallation(installation, annotations)
+		require.NoError(t, err)
+
+		return installation
+	}
+
+	setupOperation := func(t *testing.T, sqlStore *store.SQLStore, operation *model.BulkOperation) *model.BulkOperation {
+		err := sqlStore.CreateBulkOperation(operation)
+		require.NoError(t, err)
+
+		return operation
+	}
+
+	getItemStates := func(t *testing.T, sqlStore *store.SQLStore, operationID string) map[string]model.BulkOperationItemState {
+		items, err := sqlStore.GetBulkOperationItems(operationID)
+		require.NoError(t, err)
+
+		states := map[string]model.BulkOperationItemState{}
+		for _, item := range items {
+			states[item.InstallationID] = item.State
+		}
+		return states
+	}
+
+	getOperationState := func(t *testing.T, sqlStore *store.SQLStore, operationID string) model.BulkOperationState {
+		operation, err := sqlStore.GetBulkOperation(operationID)
+		require.NoError(t, err)
+		return operation.State
+	}
+
+	setInstallationState := func(t *testing.T, sqlStore *store.SQLStore, installationID, state string) {
+		installation, err := sqlStore.GetInstallation(installationID, false, false)
+		require.NoError(t, err)
+		installation.State = state
+		err = sqlStore.UpdateInstallationState(installation)
+		require.NoError(t, err)
+	}
+
+	t.Run("hibernation with concurrency", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		ownerID := model.NewID()
+		installation1 := setupInstallation(t, sqlStore, ownerID, model.InstallationStateStable, nil)
+		installation2 := setupInstallation(t, sqlStore, ownerID, model.InstallationStateHibernating, nil)
+		installation3 := setupInstallation(t, sqlStore, ownerID, model.InstallationStateStable, nil)
+		operation := setupOperation(t, sqlStore, &model.BulkOperation{
+			Type:        model.BulkOperationTypeHibernate,
+			Filter:      model.BulkOperationInstallationFilter{OwnerID: ownerID},
+			Concurrency: 1,
+		})
+
+		operationSupervisor := supervisor.NewBulkOperationSupervisor(sqlStore, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
+		err := operationSupervisor.Do()
+		require.NoError(t, err)
+
+		states := getItemStates(t, sqlStore, operation.ID)
+		requested := 0
+		for _, state := range states {
+			if state == model.BulkOperationItemStateRequested {
+				requested++
+			}
+		}
+		assert.Equal(t, 1, requested)
+		assert.Equal(t, model.BulkOperationStateInProgress, getOperationState(t, sqlStore, operation.ID))
+
+		for i := 0; i < 3; i++ {
+			for installationID, state := range getItemStates(t, sqlStore, operation.ID) {
+				if state != model.BulkOperationItemStateRequested {
+					continue
+				}
+				if installationID == installation3.ID {
+					// The third installation fails to hibernate.
+					setInstallationState(t, sqlStore, installationID, model.InstallationStateStable)
+				} else {
+					setInstallationState(t, sqlStore, installationID, model.InstallationStateHibernating)
+				}
+			}
+			err = operationSupervisor.Do()
+			require.NoError(t, err)
+		}
+
+		states = getItemStates(t, sqlStore, operation.ID)
+		assert.Equal(t, model.BulkOperationItemStateSucceeded, states[installation1.ID])
+		assert.Equal(t, model.BulkOperationItemStateSucceeded, states[installation2.ID])
+		assert.Equal(t, model.BulkOperationItemStateFailed, states[installation3.ID])
+		assert.Equal(t, model.BulkOperationStateCompleted, getOperationState(t, sqlStore, operation.ID))
+
+		items, err := sqlStore.GetBulkOperationItems(operation.ID)
+		require.NoError(t, err)
+		for _, item := range items {
+			if item.InstallationID == installation3.ID {
+				assert.Equal(t, `installation settled in state "stable"`, item.Error)
+			}
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		ownerID := model.NewID()
+		installation1 := setupInstallation(t, sqlStore, ownerID, model.InstallationStateStable, nil)
+		installation2 := setupInstallation(t, sqlStore, ownerID, model.InstallationStateStable, nil)
+		err := sqlStore.LockInstallationAPI(installation2.ID)
+		require.NoError(t, err)
+
+		version := "5.39.0"
+		operation := setupOperation(t, sqlStore, &model.BulkOperation{
+			Type:        model.BulkOperationTypeUpdate,
+			Filter:      model.BulkOperationInstallationFilter{OwnerID: ownerID},
+			Patch:       &model.PatchInstallationRequest{Version: &version},
+			Concurrency: 5,
+		})
+
+		operationSupervisor := supervisor.NewBulkOperationSupervisor(sqlStore, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
+		err = operationSupervisor.Do()
+		require.NoError(t, err)
+
+		installation, err := sqlStore.GetInstallation(installation1.ID, false, false)
+		require.NoError(t, err)
+		assert.Equal(t, model.InstallationStateUpdateRequested, installation.State)
+		assert.Equal(t, version, installation.Version)
+
+		states := getItemStates(t, sqlStore, operation.ID)
+		assert.Equal(t, model.BulkOperationItemStateRequested, states[installation1.ID])
+		assert.Equal(t, model.BulkOperationItemStateFailed, states[installation2.ID])
+
+		setInstallationState(t, sqlStore, installation1.ID, model.InstallationStateStable)
+		err = operationSupervisor.Do()
+		require.NoError(t, err)
+
+		assert.Equal(t, model.BulkOperationItemStateSucceeded, getItemStates(t, sqlStore, operation.ID)[installation1.ID])
+		assert.Equal(t, model.BulkOperationStateCompleted, getOperationState(t, sqlStore, operation.ID))
+	})
+
+	t.Run("add annotations", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		ownerID := model.NewID()
+		installation1 := setupInstallation(t, sqlStore, ownerID, model.InstallationStateStable, []*model.Annotation{{Name: "annotation1"}})
+		installation2 := setupInstallation(t, sqlStore, ownerID, model.InstallationStateHibernating, nil)
+		operation := setupOperation(t, sqlStore, &model.BulkOperation{
+			Type:        model.BulkOperationTypeAddAnnotations,
+			Filter:      model.BulkOperationInstallationFilter{OwnerID: ownerID},
+			Annotations: []string{"annotation1", "annotation2"},
+			Concurrency: 1,
+		})
+
+		operationSupervisor := supervisor.NewBulkOperationSupervisor(sqlStore, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
+		err := operationSupervisor.Do()
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]model.BulkOperationItemState{
+			installation1.ID: model.BulkOperationItemStateSucceeded,
+			installation2.ID: model.BulkOperationItemStateSucceeded,
+		}, getItemStates(t, sqlStore, operation.ID))
+		assert.Equal(t, model.BulkOperationStateCompleted, getOperationState(t, sqlStore, operation.ID))
+
+		for _, installationID := range []string{installation1.ID, installation2.ID} {
+			annotations, err := sqlStore.GetAnnotationsForInstallation(installationID)
+			require.NoError(t, err)
+			names := []string{}
+			for _, annotation := range annotations {
+				names = append(names, annotation.Name)
+			}
+			assert.ElementsMatch(t, []string{"annotation1", "annotation2"}, names)
+		}
+	})
+
+	t.Run("cancelled operation", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		ownerID := model.NewID()
+		installation := setupInstallation(t, sqlStore, ownerID, model.InstallationStateStable, nil)
+		operation := setupOperation(t, sqlStore, &model.BulkOperation{
+			Type:        model.BulkOperationTypeDelete,
+			Filter:      model.BulkOperationInstallationFilter{OwnerID: ownerID},
+			Concurrency: 1,
+		})
+		err := sqlStore.CancelBulkOperation(operation.ID)
+		require.NoError(t, err)
+
+		operationSupervisor := supervisor.NewBulkOperationSupervisor(sqlStore, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
+		operationSupervisor.Supervise(operation)
+
+		fetched, err := sqlStore.GetInstallation(installation.ID, false, false)
+		require.NoError(t, err)
+		assert.Equal(t, model.InstallationStateStable, fetched.State)
+		assert.Equal(t, model.BulkOperationItemStateCancelled, getItemStates(t, sqlStore, operation.ID)[installation.ID])
+		assert.Equal(t, model.BulkOperationStateCancelled, getOperationState(t, sqlStore, operation.ID))
+	})
+
+	t.Run("no matching installations", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		operation := setupOperation(t, sqlStore, &model.BulkOperation{
+			Type:        model.BulkOperationTypeWakeUp,
+			Filter:      model.BulkOperationInstallationFilter{OwnerID: model.NewID()},
+			Concurrency: 1,
+		})
+
+		operationSupervisor := supervisor.NewBulkOperationSupervisor(sqlStore, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
+		err := operationSupervisor.Do()
+		require.NoError(t, err)
+
+		assert.Equal(t, model.BulkOperationStateCompleted, getOperationState(t, sqlStore, operation.ID))
+	})
+}
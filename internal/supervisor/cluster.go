@@ -7,9 +7,11 @@ package supervisor
 import (
 	"time"
 
-	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
+	"github.com/mattermost/mattermost-cloud/internal/metrics"
+	"github.com/mattermost/mattermost-cloud/internal/tools/cloud"
 	"github.com/mattermost/mattermost-cloud/internal/webhook"
 	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -24,16 +26,20 @@ type clusterStore interface {
 	DeleteCluster(clusterID string) error
 
 	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+	CreateEvent(event *model.Event) error
+	GetEvents(filter *model.EventFilter) ([]*model.Event, error)
 }
 
 // clusterProvisioner abstracts the provisioning operations required by the cluster supervisor.
 type clusterProvisioner interface {
 	PrepareCluster(cluster *model.Cluster) bool
-	CreateCluster(cluster *model.Cluster, aws aws.AWS) error
-	ProvisionCluster(cluster *model.Cluster, aws aws.AWS) error
-	UpgradeCluster(cluster *model.Cluster, aws aws.AWS) error
-	ResizeCluster(cluster *model.Cluster, aws aws.AWS) error
-	DeleteCluster(cluster *model.Cluster, aws aws.AWS) error
+	CreateCluster(cluster *model.Cluster) error
+	ProvisionCluster(cluster *model.Cluster) error
+	UpgradeCluster(cluster *model.Cluster) error
+	ResizeCluster(cluster *model.Cluster) error
+	DeleteCluster(cluster *model.Cluster) error
 	RefreshKopsMetadata(cluster *model.Cluster) error
 }
 
@@ -44,19 +50,30 @@ type clusterProvisioner interface {
 type ClusterSupervisor struct {
 	store       clusterStore
 	provisioner clusterProvisioner
-	aws         aws.AWS
+	cloud       cloud.Provider
 	instanceID  string
 	logger      log.FieldLogger
+	metrics     *metrics.CloudMetrics
+}
+
+// clusterMeasuredTransitions are the cluster transitions whose duration is recorded in metrics.
+var clusterMeasuredTransitions = []measuredTransition{
+	{"creation", model.ClusterStateCreationRequested, model.ClusterStateStable},
+	{"provisioning", model.ClusterStateProvisioningRequested, model.ClusterStateStable},
+	{"upgrade", model.ClusterStateUpgradeRequested, model.ClusterStateStable},
+	{"resize", model.ClusterStateResizeRequested, model.ClusterStateStable},
+	{"deletion", model.ClusterStateDeletionRequested, model.ClusterStateDeleted},
 }
 
 // NewClusterSupervisor creates a new ClusterSupervisor.
-func NewClusterSupervisor(store clusterStore, clusterProvisioner clusterProvisioner, aws aws.AWS, instanceID string, logger log.FieldLogger) *ClusterSupervisor {
+func NewClusterSupervisor(store clusterStore, clusterProvisioner clusterProvisioner, cloudProvider cloud.Provider, instanceID string, logger log.FieldLogger, metrics *metrics.CloudMetrics) *ClusterSupervisor {
 	return &ClusterSupervisor{
 		store:       store,
 		provisioner: clusterProvisioner,
-		aws:         aws,
+		cloud:       cloudProvider,
 		instanceID:  instanceID,
 		logger:      logger,
+		metrics:     metrics,
 	}
 }
 
@@ -70,7 +87,7 @@ func (s *ClusterSupervisor) Do() error {
 	clusters, err := s.store.GetUnlockedClustersPendingWork()
 	if err != nil {
 		s.logger.WithError(err).Warn("Failed to query for clusters pending work")
-		return nil
+		return errors.Wrap(err, "failed to query for clusters pending work")
 	}
 
 	for _, cluster := range clusters {
@@ -109,7 +126,8 @@ func (s *ClusterSupervisor) Supervise(cluster *model.Cluster) {
 
 	logger.Debugf("Supervising cluster in state %s", cluster.State)
 
-	newState := s.transitionCluster(cluster, logger)
+	start := time.Now()
+	newState, stateErr := s.transitionCluster(cluster, logger)
 
 	cluster, err = s.store.GetCluster(cluster.ID)
 	if err != nil {
@@ -135,8 +153,10 @@ func (s *ClusterSupervisor) Supervise(cluster *model.Cluster) {
 		NewState:  newState,
 		OldState:  oldState,
 		Timestamp: time.Now().UnixNano(),
-		ExtraData: map[string]string{"Environment": s.aws.GetCloudEnvironmentName()},
+		ExtraData: map[string]string{"Environment": s.cloud.GetCloudEnvironmentName()},
 	}
+	observeTransition(s.store, s.metrics, clusterMeasuredTransitions, webhookPayload, start, logger)
+	recordStateChangeEvent(s.store, webhookPayload, s.instanceID, stateErr, logger)
 	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
 	if err != nil {
 		logger.WithError(err).Error("Unable to process and send webhooks")
@@ -146,7 +166,7 @@ func (s *ClusterSupervisor) Supervise(cluster *model.Cluster) {
 }
 
 // Do works with the given cluster to transition it to a final state.
-func (s *ClusterSupervisor) transitionCluster(cluster *model.Cluster, logger log.FieldLogger) string {
+func (s *ClusterSupervisor) transitionCluster(cluster *model.Cluster, logger log.FieldLogger) (string, error) {
 	switch cluster.State {
 	case model.ClusterStateCreationRequested:
 		return s.createCluster(cluster, logger)
@@ -162,71 +182,87 @@ func (s *ClusterSupervisor) transitionCluster(cluster *model.Cluster, logger log
 		return s.deleteCluster(cluster, logger)
 	default:
 		logger.Warnf("Found cluster pending work in unexpected state %s", cluster.State)
-		return cluster.State
+		return cluster.State, nil
 	}
 }
 
-func (s *ClusterSupervisor) createCluster(cluster *model.Cluster, logger log.FieldLogger) string {
+func (s *ClusterSupervisor) createCluster(cluster *model.Cluster, logger log.FieldLogger) (string, error) {
+	// Local clusters already exist, so only the provisioning is required.
+	if cluster.Provisioner == model.ProvisionerLocal {
+		logger.Info("Skipping creation of local cluster")
+		return s.provisionCluster(cluster, logger)
+	}
+
 	var err error
 
 	if s.provisioner.PrepareCluster(cluster) {
 		err = s.store.UpdateCluster(cluster)
 		if err != nil {
 			logger.WithError(err).Error("Failed to record updated cluster after creation")
-			return model.ClusterStateCreationFailed
+			return model.ClusterStateCreationFailed, errors.Wrap(err, "failed to record updated cluster after creation")
 		}
 	}
 
-	err = s.provisioner.CreateCluster(cluster, s.aws)
+	err = s.provisioner.CreateCluster(cluster)
 	if err != nil {
 		logger.WithError(err).Error("Failed to create cluster")
-		return model.ClusterStateCreationFailed
+		return model.ClusterStateCreationFailed, errors.Wrap(err, "failed to create cluster")
 	}
 
 	logger.Info("Finished creating cluster")
 	return s.provisionCluster(cluster, logger)
 }
 
-func (s *ClusterSupervisor) provisionCluster(cluster *model.Cluster, logger log.FieldLogger) string {
-	err := s.provisioner.ProvisionCluster(cluster, s.aws)
+func (s *ClusterSupervisor) provisionCluster(cluster *model.Cluster, logger log.FieldLogger) (string, error) {
+	err := s.provisioner.ProvisionCluster(cluster)
 	if err != nil {
 		logger.WithError(err).Error("Failed to provision cluster")
-		return model.ClusterStateProvisioningFailed
+		return model.ClusterStateProvisioningFailed, errors.Wrap(err, "failed to provision cluster")
 	}
 
 	logger.Info("Finished provisioning cluster")
 	return s.refreshClusterMetadata(cluster, logger)
 }
 
-func (s *ClusterSupervisor) upgradeCluster(cluster *model.Cluster, logger log.FieldLogger) string {
-	err := s.provisioner.UpgradeCluster(cluster, s.aws)
-	if err != nil {
-		logger.WithError(err).Error("Failed to upgrade cluster")
+func (s *ClusterSupervisor) upgradeCluster(cluster *model.Cluster, logger log.FieldLogger) (string, error) {
+	if cluster.Provisioner == model.ProvisionerLocal {
+		logger.Info("Skipping upgrade of local cluster")
+		return s.refreshClusterMetadata(cluster, logger)
+	}
+
+	upgradeErr := s.provisioner.UpgradeCluster(cluster)
+	if upgradeErr != nil {
+		logger.WithError(upgradeErr).Error("Failed to upgrade cluster")
 		logger.Info("Updating cluster store with latest cluster data")
-		err = s.store.UpdateCluster(cluster)
+		err := s.store.UpdateCluster(cluster)
 		if err != nil {
 			logger.WithError(err).Error("Failed to save updated cluster metadata")
-			return model.ClusterStateRefreshMetadata
+			return model.ClusterStateRefreshMetadata, nil
 		}
-		return model.ClusterStateUpgradeFailed
+		return model.ClusterStateUpgradeFailed, errors.Wrap(upgradeErr, "failed to upgrade cluster")
 	}
 
 	logger.Info("Finished upgrading cluster")
 	return s.refreshClusterMetadata(cluster, logger)
 }
 
-func (s *ClusterSupervisor) resizeCluster(cluster *model.Cluster, logger log.FieldLogger) string {
-	err := s.provisioner.ResizeCluster(cluster, s.aws)
+func (s *ClusterSupervisor) resizeCluster(cluster *model.Cluster, logger log.FieldLogger) (string, error) {
+	if cluster.Provisioner == model.ProvisionerLocal {
+		logger.Info("Skipping resize of local cluster")
+		return s.refreshClusterMetadata(cluster, logger)
+	}
+
+	err := s.provisioner.ResizeCluster(cluster)
 	if err != nil {
 		logger.WithError(err).Error("Failed to resize cluster")
-		return model.ClusterStateResizeFailed
+		return model.ClusterStateResizeFailed, errors.Wrap(err, "failed to resize cluster")
 	}
 
 	logger.Info("Finished resizing cluster")
 	return s.refreshClusterMetadata(cluster, logger)
 }
 
-func (s *ClusterSupervisor) refreshClusterMetadata(cluster *model.Cluster, logger log.FieldLogger) string {
+func (s *ClusterSupervisor) refreshClusterMetadata(cluster *model.Cluster, logger log.FieldLogger) (string, error) {
 	if cluster.ProvisionerMetadataKops != nil {
 		cluster.ProvisionerMetadataKops.ClearChangeRequest()
 		cluster.ProvisionerMetadataKops.ClearRotatorRequest()
@@ -236,30 +272,30 @@ func (s *ClusterSupervisor) refreshClusterMetadata(cluster *model.Cluster, logge
 	err := s.provisioner.RefreshKopsMetadata(cluster)
 	if err != nil {
 		logger.WithError(err).Error("Failed to refresh cluster")
-		return model.ClusterStateRefreshMetadata
+		return model.ClusterStateRefreshMetadata, nil
 	}
 	err = s.store.UpdateCluster(cluster)
 	if err != nil {
 		logger.WithError(err).Error("Failed to save updated cluster metadata")
-		return model.ClusterStateRefreshMetadata
+		return model.ClusterStateRefreshMetadata, nil
 	}
 
-	return model.ClusterStateStable
+	return model.ClusterStateStable, nil
 }
 
-func (s *ClusterSupervisor) deleteCluster(cluster *model.Cluster, logger log.FieldLogger) string {
-	err := s.provisioner.DeleteCluster(cluster, s.aws)
+func (s *ClusterSupervisor) deleteCluster(cluster *model.Cluster, logger log.FieldLogger) (string, error) {
+	err := s.provisioner.DeleteCluster(cluster)
 	if err != nil {
 		logger.WithError(err).Error("Failed to delete cluster")
-		return model.ClusterStateDeletionFailed
+		return model.ClusterStateDeletionFailed, errors.Wrap(err, "failed to delete cluster")
 	}
 
 	err = s.store.DeleteCluster(cluster.ID)
 	if err != nil {
 		logger.WithError(err).Error("Failed to record updated cluster after deletion")
-		return model.ClusterStateDeletionFailed
+		return model.ClusterStateDeletionFailed, errors.Wrap(err, "failed to record updated cluster after deletion")
 	}
 
 	logger.Info("Finished deleting cluster")
-	return model.ClusterStateDeleted
+	return model.ClusterStateDeleted, nil
 }
@@ -0,0 +1,259 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"github.com/mattermost/mattermost-cloud/internal/common"
+	"github.com/mattermost/mattermost-cloud/internal/tools/cloud"
+	"github.com/mattermost/mattermost-cloud/k8s"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// clusterCapacityStore abstracts the database operations required by the cluster capacity supervisor.
+type clusterCapacityStore interface {
+	GetClusters(clusterFilter *model.ClusterFilter) ([]*model.Cluster, error)
+	GetCluster(id string) (*model.Cluster, error)
+	CreateCluster(cluster *model.Cluster, annotations []*model.Annotation) error
+	UpdateCluster(cluster *model.Cluster) error
+	clusterLockStore
+
+	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+	CreateEvent(event *model.Event) error
+}
+
+// clusterResourcesProvisioner abstracts the provisioning operations required by the cluster capacity supervisor.
+type clusterResourcesProvisioner interface {
+	GetClusterResources(cluster *model.Cluster, onlySchedulable bool) (*k8s.ClusterResources, error)
+}
+
+// ClusterCapacityOptions are the various options that control how cluster
+// capacity is adjusted.
+type ClusterCapacityOptions struct {
+	scaleUpThreshold   int
+	scaleDownThreshold int
+	scaleStep          int64
+	minNodeCount       int64
+	clusterTemplate    *model.CreateClusterRequest
+}
+
+// NewClusterCapacityOptions creates a new ClusterCapacityOptions. New clusters
+// are only created when a cluster template is provided.
+func NewClusterCapacityOptions(scaleUpThreshold, scaleDownThreshold, scaleStep, minNodeCount int, clusterTemplate *model.CreateClusterRequest) ClusterCapacityOptions {
+	return ClusterCapacityOptions{
+		scaleUpThreshold:   scaleUpThreshold,
+		scaleDownThreshold: scaleDownThreshold,
+		scaleStep:          int64(scaleStep),
+		minNodeCount:       int64(minNodeCount),
+		clusterTemplate:    clusterTemplate,
+	}
+}
+
+// ClusterCapacitySupervisor periodically checks the resources requested on
+// every cluster and adjusts the cluster worker node count between the
+// configured minimum node count and the kops NodeMaxCount. When all clusters accepting
+// installations are full and cannot grow anymore, a new cluster is created
+// from the cluster template unless a cluster failed creation.
+//
+// Worker nodes are added when the requested CPU or memory exceeds the scale
+// up threshold and removed when both are below the scale down threshold, as
+// long as the remaining nodes would not exceed the scale up threshold.
+type ClusterCapacitySupervisor struct {
+	store       clusterCapacityStore
+	provisioner clusterResourcesProvisioner
+	cloud       cloud.Provider
+	instanceID  string
+	options     ClusterCapacityOptions
+	logger      log.FieldLogger
+}
+
+// NewClusterCapacitySupervisor creates a new ClusterCapacitySupervisor.
+func NewClusterCapacitySupervisor(store clusterCapacityStore, provisioner clusterResourcesProvisioner, cloudProvider cloud.Provider, instanceID string, options ClusterCapacityOptions, logger log.FieldLogger) *ClusterCapacitySupervisor {
+	return &ClusterCapacitySupervisor{
+		store:       store,
+		provisioner: provisioner,
+		cloud:       cloudProvider,
+		instanceID:  instanceID,
+		options:     options,
+		logger:      logger,
+	}
+}
+
+// Shutdown performs graceful shutdown tasks for the cluster capacity supervisor.
+func (s *ClusterCapacitySupervisor) Shutdown() {
+	s.logger.Debug("Shutting down cluster capacity supervisor")
+}
+
+// Do checks the capacity of all clusters and adjusts it to the current demand.
+func (s *ClusterCapacitySupervisor) Do() error {
+	clusters, err := s.store.GetClusters(&model.ClusterFilter{
+		Paging: model.AllPagesNotDeleted(),
+	})
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to query clusters")
+		return errors.Wrap(err, "failed to query clusters")
+	}
+
+	var acceptingClusters, availableClusters, failedClusters int
+	for _, cluster := range clusters {
+		switch cluster.State {
+		case model.ClusterStateCreationRequested, model.ClusterStateProvisioningRequested:
+			// A new cluster is on its way, so there is no need to create
+			// another one yet.
+			availableClusters++
+			continue
+		case model.ClusterStateCreationFailed:
+			// Creating another cluster would most likely fail the same way.
+			failedClusters++
+			continue
+		}
+		if !cluster.AllowInstallations {
+			s.Supervise(cluster)
+			continue
+		}
+
+		acceptingClusters++
+		if s.Supervise(cluster) {
+			availableClusters++
+		}
+	}
+
+	if acceptingClusters > 0 && availableClusters == 0 {
+		if failedClusters > 0 {
+			s.logger.Warnf("All clusters are full, but no cluster is created while %d clusters failed creation", failedClusters)
+			return nil
+		}
+		s.createCluster()
+	}
+
+	return nil
+}
+
+// Supervise adjusts the worker node count of the given cluster to the
+// resources requested on it. It returns true if the cluster has capacity
+// for new installations now or once its pending work completes.
+func (s *ClusterCapacitySupervisor) Supervise(cluster *model.Cluster) bool {
+	logger := s.logger.WithFields(log.Fields{
+		"cluster": cluster.ID,
+	})
+
+	switch cluster.State {
+	case model.ClusterStateStable:
+	case model.ClusterStateRefreshMetadata, model.ClusterStateUpgradeRequested, model.ClusterStateResizeRequested:
+		// The cluster is expected to accept installations again once the
+		// pending work completes.
+		return true
+	default:
+		return false
+	}
+
+	clusterResources, err := s.provisioner.GetClusterResources(cluster, true)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get cluster resources")
+		// Don't create new clusters when the usage is unknown.
+		return true
+	}
+	cpuPercent := clusterResources.CalculateCPUPercentUsed(0)
+	memoryPercent := clusterResources.CalculateMemoryPercentUsed(0)
+	usedPercent := cpuPercent
+	if memoryPercent > usedPercent {
+		usedPercent = memoryPercent
+	}
+
+	logger.Debugf("Cluster resource usage: CPU=%d%%, Memory=%d%%", cpuPercent, memoryPercent)
+
+	if cluster.Provisioner == model.ProvisionerLocal || cluster.ProvisionerMetadataKops == nil {
+		return usedPercent < s.options.scaleUpThreshold
+	}
+
+	// The kops metadata holds the instance group sizes, which may differ from
+	// the nodes actually running.
+	nodeCount := clusterResources.NodeCount
+	if nodeCount == 0 {
+		logger.Warn("Cluster has no schedulable worker nodes")
+		return false
+	}
+	maxNodeCount := cluster.ProvisionerMetadataKops.NodeMaxCount
+
+	if usedPercent >= s.options.scaleUpThreshold {
+		if nodeCount >= maxNodeCount {
+			logger.Debugf("Cluster is above the scale up threshold (%d%%) and already at max worker node count %d", s.options.scaleUpThreshold, maxNodeCount)
+			return false
+		}
+
+		newNodeCount := nodeCount + s.options.scaleStep
+		if newNodeCount > maxNodeCount {
+			newNodeCount = maxNodeCount
+		}
+
+		return s.resizeCluster(cluster, newNodeCount, logger)
+	}
+
+	if usedPercent < s.options.scaleDownThreshold && nodeCount > s.options.minNodeCount {
+		newNodeCount := nodeCount - s.options.scaleStep
+		if newNodeCount < s.options.minNodeCount {
+			newNodeCount = s.options.minNodeCount
+		}
+
+		// Avoid removing nodes which would be added again right away.
+		if int64(usedPercent)*nodeCount/newNodeCount < int64(s.options.scaleUpThreshold) {
+			s.resizeCluster(cluster, newNodeCount, logger)
+		}
+	}
+
+	return true
+}
+
+// resizeCluster requests the cluster worker node count to be changed and
+// returns true if the request was made.
+func (s *ClusterCapacitySupervisor) resizeCluster(cluster *model.Cluster, nodeCount int64, logger log.FieldLogger) bool {
+	lock := newClusterLock(cluster.ID, s.instanceID, s.store, logger)
+	if !lock.TryLock() {
+		logger.Debug("Failed to lock cluster for resizing")
+		return false
+	}
+	defer lock.Unlock()
+
+	// Ensure that the cluster was not changed by another provisioning server
+	// since its resources were checked.
+	originalNodeCount := cluster.ProvisionerMetadataKops.NodeMinCount
+	cluster, err := s.store.GetCluster(cluster.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get refreshed cluster")
+		return false
+	}
+	if cluster == nil || cluster.State != model.ClusterStateStable || cluster.ProvisionerMetadataKops.NodeMinCount != originalNodeCount {
+		logger.Warn("Another provisioner has worked on this cluster; skipping...")
+		return false
+	}
+
+	err = requestClusterWorkerNodeCount(s.store, cluster, nodeCount, s.instanceID, s.cloud.GetCloudEnvironmentName(), logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to request cluster resize")
+		return false
+	}
+
+	return true
+}
+
+// createCluster creates a new cluster from the cluster template.
+func (s *ClusterCapacitySupervisor) createCluster() {
+	if s.options.clusterTemplate == nil {
+		s.logger.Warn("All clusters are full, but no cluster template is configured")
+		return
+	}
+
+	template := *s.options.clusterTemplate
+	cluster, err := common.CreateCluster(s.store, &template, s.cloud.GetCloudEnvironmentName(), s.logger)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to create cluster from template")
+		return
+	}
+
+	s.logger.WithField("cluster", cluster.ID).Info("All clusters are full; requested creation of a new cluster")
+}
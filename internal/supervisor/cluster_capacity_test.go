@@ -0,0 +1,262 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/internal/tools/cloud"
+	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
+	"github.com/mattermost/mattermost-cloud/k8s"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockClusterResourcesProvisioner struct {
+	usedPercent map[string]int64
+	// nodeCount overrides the node count, which defaults to the kops
+	// NodeMinCount of the cluster.
+	nodeCount map[string]int64
+}
+
+func (p *mockClusterResourcesProvisioner) GetClusterResources(cluster *model.Cluster, onlySchedulable bool) (*k8s.ClusterResources, error) {
+	nodeCount, ok := p.nodeCount[cluster.ID]
+	if !ok && cluster.ProvisionerMetadataKops != nil {
+		nodeCount = cluster.ProvisionerMetadataKops.NodeMinCount
+	}
+
+	return &k8s.ClusterResources{
+		MilliTotalCPU:    1000,
+		MilliUsedCPU:     p.usedPercent[cluster.ID] * 10,
+		MilliTotalMemory: 1000,
+		MilliUsedMemory:  p.usedPercent[cluster.ID] * 5,
+		NodeCount:        nodeCount,
+	}, nil
+}
+
+func TestClusterCapacitySupervisor(t *testing.T) {
+	clusterTemplate := &model.CreateClusterRequest{}
+	clusterTemplate.SetDefaults()
+	options := supervisor.NewClusterCapacityOptions(80, 30, 1, 1, clusterTemplate)
+
+	setupCluster := func(t *testing.T, sqlStore *store.SQLStore, nodeCount, maxNodeCount int64) *model.Cluster {
+		cluster := &model.Cluster{
+			State:              model.ClusterStateStable,
+			AllowInstallations: true,
+			ProvisionerMetadataKops: &model.KopsMetadata{
+				MasterCount:  1,
+				NodeMinCount: nodeCount,
+				NodeMaxCount: maxNodeCount,
+				NodeInstanceGroups: model.KopsInstanceGroupsMetadata{
+					"nodes-a": model.KopsInstanceGroupMetadata{NodeMinCount: nodeCount, NodeMaxCount: nodeCount},
+				},
+			},
+		}
+		err := sqlStore.CreateCluster(cluster, nil)
+		require.NoError(t, err)
+
+		return cluster
+	}
+
+	expectNodeCountChange := func(t *testing.T, sqlStore *store.SQLStore, cluster *model.Cluster, expectedNodeCount int64) {
+		t.Helper()
+		cluster, err := sqlStore.GetCluster(cluster.ID)
+		require.NoError(t, err)
+		if expectedNodeCount == 0 {
+			assert.Equal(t, model.ClusterStateStable, cluster.State)
+			assert.Nil(t, cluster.ProvisionerMetadataKops.ChangeRequest)
+			return
+		}
+		assert.Equal(t, model.ClusterStateResizeRequested, cluster.State)
+		require.NotNil(t, cluster.ProvisionerMetadataKops.ChangeRequest)
+		assert.Equal(t, expectedNodeCount, cluster.ProvisionerMetadataKops.ChangeRequest.NodeMinCount)
+	}
+
+	countClusters := func(t *testing.T, sqlStore *store.SQLStore) int {
+		clusters, err := sqlStore.GetClusters(&model.ClusterFilter{Paging: model.AllPagesNotDeleted()})
+		require.NoError(t, err)
+		return len(clusters)
+	}
+
+	t.Run("scale clusters to demand", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		busyCluster := setupCluster(t, sqlStore, 2, 4)
+		idleCluster := setupCluster(t, sqlStore, 3, 4)
+		steadyCluster := setupCluster(t, sqlStore, 2, 4)
+		smallIdleCluster := setupCluster(t, sqlStore, 1, 4)
+		provisioner := &mockClusterResourcesProvisioner{usedPercent: map[string]int64{
+			busyCluster.ID:      90,
+			idleCluster.ID:      10,
+			steadyCluster.ID:    50,
+			smallIdleCluster.ID: 10,
+		}}
+
+		capacitySupervisor := supervisor.NewClusterCapacitySupervisor(sqlStore, provisioner, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", options, logger)
+		err := capacitySupervisor.Do()
+		require.NoError(t, err)
+
+		expectNodeCountChange(t, sqlStore, busyCluster, 3)
+		expectNodeCountChange(t, sqlStore, idleCluster, 2)
+		expectNodeCountChange(t, sqlStore, steadyCluster, 0)
+		expectNodeCountChange(t, sqlStore, smallIdleCluster, 0)
+		assert.Equal(t, 4, countClusters(t, sqlStore))
+	})
+
+	t.Run("scale from the running node count", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		busyCluster := setupCluster(t, sqlStore, 4, 6)
+		idleCluster := setupCluster(t, sqlStore, 2, 6)
+		provisioner := &mockClusterResourcesProvisioner{
+			usedPercent: map[string]int64{busyCluster.ID: 90, idleCluster.ID: 10},
+			nodeCount:   map[string]int64{busyCluster.ID: 3, idleCluster.ID: 4},
+		}
+
+		capacitySupervisor := supervisor.NewClusterCapacitySupervisor(sqlStore, provisioner, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", options, logger)
+		err := capacitySupervisor.Do()
+		require.NoError(t, err)
+
+		expectNodeCountChange(t, sqlStore, busyCluster, 4)
+		expectNodeCountChange(t, sqlStore, idleCluster, 3)
+	})
+
+	t.Run("do not remove nodes below the minimum node count", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		atMinimumCluster := setupCluster(t, sqlStore, 3, 6)
+		aboveMinimumCluster := setupCluster(t, sqlStore, 4, 6)
+		provisioner := &mockClusterResourcesProvisioner{usedPercent: map[string]int64{
+			atMinimumCluster.ID:    10,
+			aboveMinimumCluster.ID: 10,
+		}}
+		options := supervisor.NewClusterCapacityOptions(80, 30, 2, 3, nil)
+
+		capacitySupervisor := supervisor.NewClusterCapacitySupervisor(sqlStore, provisioner, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", options, logger)
+		err := capacitySupervisor.Do()
+		require.NoError(t, err)
+
+		expectNodeCountChange(t, sqlStore, atMinimumCluster, 0)
+		expectNodeCountChange(t, sqlStore, aboveMinimumCluster, 3)
+	})
+
+	t.Run("do not remove nodes which would be needed again", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		cluster := setupCluster(t, sqlStore, 2, 4)
+		provisioner := &mockClusterResourcesProvisioner{usedPercent: map[string]int64{cluster.ID: 29}}
+		options := supervisor.NewClusterCapacityOptions(50, 30, 1, 1, nil)
+
+		capacitySupervisor := supervisor.NewClusterCapacitySupervisor(sqlStore, provisioner, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", options, logger)
+		err := capacitySupervisor.Do()
+		require.NoError(t, err)
+
+		expectNodeCountChange(t, sqlStore, cluster, 0)
+	})
+
+	t.Run("create cluster when all clusters are full", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		cluster := setupCluster(t, sqlStore, 4, 4)
+		provisioner := &mockClusterResourcesProvisioner{usedPercent: map[string]int64{cluster.ID: 90}}
+
+		capacitySupervisor := supervisor.NewClusterCapacitySupervisor(sqlStore, provisioner, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", options, logger)
+		err := capacitySupervisor.Do()
+		require.NoError(t, err)
+
+		clusters, err := sqlStore.GetClusters(&model.ClusterFilter{Paging: model.AllPagesNotDeleted()})
+		require.NoError(t, err)
+		require.Len(t, clusters, 2)
+		for _, newCluster := range clusters {
+			if newCluster.ID == cluster.ID {
+				continue
+			}
+			assert.Equal(t, model.ClusterStateCreationRequested, newCluster.State)
+			assert.Equal(t, clusterTemplate.NodeInstanceType, newCluster.ProvisionerMetadataKops.ChangeRequest.NodeInstanceType)
+		}
+
+		t.Run("wait for the new cluster", func(t *testing.T) {
+			err = capacitySupervisor.Do()
+			require.NoError(t, err)
+			assert.Equal(t, 2, countClusters(t, sqlStore))
+		})
+	})
+
+	t.Run("do not create cluster while a cluster has pending work", func(t *testing.T) {
+		for _, state := range []string{
+			model.ClusterStateRefreshMetadata,
+			model.ClusterStateUpgradeRequested,
+			model.ClusterStateResizeRequested,
+		} {
+			t.Run(state, func(t *testing.T) {
+				logger := testlib.MakeLogger(t)
+				sqlStore := store.MakeTestSQLStore(t, logger)
+				defer store.CloseConnection(t, sqlStore)
+
+				cluster := setupCluster(t, sqlStore, 4, 4)
+				cluster.State = state
+				err := sqlStore.UpdateCluster(cluster)
+				require.NoError(t, err)
+				provisioner := &mockClusterResourcesProvisioner{usedPercent: map[string]int64{cluster.ID: 90}}
+
+				capacitySupervisor := supervisor.NewClusterCapacitySupervisor(sqlStore, provisioner, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", options, logger)
+				err = capacitySupervisor.Do()
+				require.NoError(t, err)
+
+				assert.Equal(t, 1, countClusters(t, sqlStore))
+			})
+		}
+	})
+
+	t.Run("do not create cluster while a cluster failed creation", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		cluster := setupCluster(t, sqlStore, 4, 4)
+		failedCluster := setupCluster(t, sqlStore, 2, 4)
+		failedCluster.State = model.ClusterStateCreationFailed
+		err := sqlStore.UpdateCluster(failedCluster)
+		require.NoError(t, err)
+		provisioner := &mockClusterResourcesProvisioner{usedPercent: map[string]int64{cluster.ID: 90}}
+
+		capacitySupervisor := supervisor.NewClusterCapacitySupervisor(sqlStore, provisioner, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", options, logger)
+		for i := 0; i < 2; i++ {
+			err = capacitySupervisor.Do()
+			require.NoError(t, err)
+			assert.Equal(t, 2, countClusters(t, sqlStore))
+		}
+	})
+
+	t.Run("no cluster template", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		cluster := setupCluster(t, sqlStore, 4, 4)
+		provisioner := &mockClusterResourcesProvisioner{usedPercent: map[string]int64{cluster.ID: 90}}
+		options := supervisor.NewClusterCapacityOptions(80, 30, 1, 1, nil)
+
+		capacitySupervisor := supervisor.NewClusterCapacitySupervisor(sqlStore, provisioner, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", options, logger)
+		err := capacitySupervisor.Do()
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, countClusters(t, sqlStore))
+	})
+}
@@ -0,0 +1,362 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/common"
+	"github.com/mattermost/mattermost-cloud/internal/tools/cloud"
+	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
+	"github.com/mattermost/mattermost-cloud/internal/webhook"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// clusterDrainStore abstracts the database operations required by the supervisor.
+type clusterDrainStore interface {
+	GetUnlockedClusterDrainOperationsPendingWork() ([]*model.ClusterDrainOperation, error)
+	GetClusterDrainOperation(id string) (*model.ClusterDrainOperation, error)
+	UpdateClusterDrainOperationState(drainOp *model.ClusterDrainOperation) error
+	UpdateClusterDrainOperation(drainOp *model.ClusterDrainOperation) error
+	clusterDrainOperationLockStore
+
+	GetCluster(id string) (*model.Cluster, error)
+	GetClusters(filter *model.ClusterFilter) ([]*model.Cluster, error)
+	GetAnnotationsForClusters(filter *model.ClusterFilter) (map[string][]*model.Annotation, error)
+	GetClusterInstallations(filter *model.ClusterInstallationFilter) ([]*model.ClusterInstallation, error)
+
+	GetInstallation(installationID string, includeGroupConfig, includeGroupConfigOverrides bool) (*model.Installation, error)
+	GetInstallations(filter *model.InstallationFilter, includeGroupConfig, includeGroupConfigOverrides bool) ([]*model.Installation, error)
+	GetAnnotationsForInstallation(installationID string) ([]*model.Annotation, error)
+	installationLockStore
+
+	GetInstallationClusterMigrationOperations(filter *model.InstallationClusterMigrationFilter) ([]*model.InstallationClusterMigrationOperation, error)
+	TriggerInstallationClusterMigration(migrationOp *model.InstallationClusterMigrationOperation, installation *model.Installation) (*model.InstallationClusterMigrationOperation, error)
+
+	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+	CreateEvent(event *model.Event) error
+}
+
+// ClusterDrainSupervisor finds pending work and effects the required changes.
+//
+// The degree of parallelism is controlled by a weighted semaphore, intended to be shared with
+// other clients needing to coordinate background jobs.
+type ClusterDrainSupervisor struct {
+	store      clusterDrainStore
+	cloud      cloud.Provider
+	scheduler  *common.InstallationScheduler
+	instanceID string
+	logger     log.FieldLogger
+}
+
+// NewClusterDrainSupervisor creates a new ClusterDrainSupervisor.
+func NewClusterDrainSupervisor(store clusterDrainStore, provisioner clusterResourcesProvisioner, cloudProvider cloud.Provider, instanceID string, scheduling InstallationSupervisorSchedulingOptions, logger log.FieldLogger) *ClusterDrainSupervisor {
+	return &ClusterDrainSupervisor{
+		store:      store,
+		cloud:      cloudProvider,
+		scheduler:  common.NewInstallationScheduler(store, provisioner, scheduling.SchedulerOptions(), logger),
+		instanceID: instanceID,
+		logger:     logger,
+	}
+}
+
+// Shutdown performs graceful shutdown tasks for the supervisor.
+func (s *ClusterDrainSupervisor) Shutdown() {
+	s.logger.Debug("Shutting down cluster drain supervisor")
+}
+
+// Do looks for work to be done on any pending cluster drain operations and attempts to schedule the required work.
+func (s *ClusterDrainSupervisor) Do() error {
+	drainOperations, err := s.store.GetUnlockedClusterDrainOperationsPendingWork()
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to query for pending work")
+		return errors.Wrap(err, "failed to query for pending work")
+	}
+
+	for _, drainOp := range drainOperations {
+		s.Supervise(drainOp)
+	}
+
+	return nil
+}
+
+// Supervise schedules the required work on the given cluster drain operation.
+func (s *ClusterDrainSupervisor) Supervise(drainOp *model.ClusterDrainOperation) {
+	logger := s.logger.WithFields(log.Fields{
+		"clusterDrainOperation": drainOp.ID,
+		"cluster":               drainOp.ClusterID,
+	})
+
+	lock := newClusterDrainOperationLock(drainOp.ID, s.instanceID, s.store, logger)
+	if !lock.TryLock() {
+		return
+	}
+	defer lock.Unlock()
+
+	// Before working on the cluster drain operation, it is crucial that we ensure that it
+	// was not updated to a new state by another provisioning server.
+	originalState := drainOp.State
+	drainOp, err := s.store.GetClusterDrainOperation(drainOp.ID)
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to get refreshed cluster drain operation")
+		return
+	}
+	if drainOp.State != originalState {
+		logger.WithField("oldDrainState", originalState).
+			WithField("newDrainState", drainOp.State).
+			Warn("Another provisioner has worked on this cluster drain operation; skipping...")
+		return
+	}
+
+	logger.Debugf("Supervising cluster drain operation in state %s", drainOp.State)
+
+	newState := s.transitionDrain(drainOp, s.instanceID, logger)
+
+	drainOp, err = s.store.GetClusterDrainOperation(drainOp.ID)
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to get cluster drain operation and thus persist state %s", newState)
+		return
+	}
+
+	if drainOp.State == newState {
+		return
+	}
+
+	oldState := drainOp.State
+	drainOp.State = newState
+
+	err = s.store.UpdateClusterDrainOperationState(drainOp)
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to set cluster drain operation state to %s", newState)
+		return
+	}
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeClusterDrain,
+		ID:        drainOp.ID,
+		NewState:  string(drainOp.State),
+		OldState:  string(oldState),
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"Cluster": drainOp.ClusterID, "Environment": s.cloud.GetCloudEnvironmentName()},
+	}
+	recordStateChangeEvent(s.store, webhookPayload, s.instanceID, nil, logger)
+	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	logger.Debugf("Transitioned cluster drain operation from %s to %s", oldState, drainOp.State)
+}
+
+// transitionDrain works with the given cluster drain operation to transition it to a final state.
+func (s *ClusterDrainSupervisor) transitionDrain(drainOp *model.ClusterDrainOperation, instanceID string, logger log.FieldLogger) model.ClusterDrainOperationState {
+	switch drainOp.State {
+	case model.ClusterDrainStateRequested, model.ClusterDrainStateInProgress:
+		return s.evacuateCluster(drainOp, instanceID, logger)
+	default:
+		logger.Warnf("Found cluster drain operation pending work in unexpected state %s", drainOp.State)
+		return drainOp.State
+	}
+}
+
+// evacuateCluster starts cluster migrations for the installations remaining on
+// the drained cluster while respecting the concurrency limit of the drain and
+// completes the drain once no more installations can be moved.
+func (s *ClusterDrainSupervisor) evacuateCluster(drainOp *model.ClusterDrainOperation, instanceID string, logger log.FieldLogger) model.ClusterDrainOperationState {
+	cluster, err := s.store.GetCluster(drainOp.ClusterID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get cluster")
+		return drainOp.State
+	}
+	if cluster == nil {
+		logger.Error("Drained cluster not found")
+		return s.completeDrain(drainOp, model.ClusterDrainStateFailed, logger)
+	}
+
+	migrationOps, err := s.store.GetInstallationClusterMigrationOperations(&model.InstallationClusterMigrationFilter{
+		SourceClusterID: cluster.ID,
+		Paging:          model.AllPagesNotDeleted(),
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to get installation cluster migrations")
+		return drainOp.State
+	}
+
+	migrating := map[string]bool{}
+	failed := map[string]bool{}
+	for _, migrationOp := range migrationOps {
+		if migrationOp.IsInProgress() {
+			migrating[migrationOp.InstallationID] = true
+		} else if migrationOp.State == model.InstallationClusterMigrationStateFailed && migrationOp.RequestAt >= drainOp.RequestAt {
+			failed[migrationOp.InstallationID] = true
+		}
+	}
+
+	clusterInstallations, err := s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
+		ClusterID: cluster.ID,
+		Paging:    model.AllPagesNotDeleted(),
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to get cluster installations")
+		return drainOp.State
+	}
+
+	var candidates []*model.Installation
+	var waiting, stuck int
+	for _, clusterInstallation := range clusterInstallations {
+		if migrating[clusterInstallation.InstallationID] {
+			continue
+		}
+
+		installation, err := s.store.GetInstallation(clusterInstallation.InstallationID, false, false)
+		if err != nil {
+			logger.WithError(err).Errorf("Failed to get installation %s", clusterInstallation.InstallationID)
+			return drainOp.State
+		}
+		if installation == nil || installation.Affinity != model.InstallationAffinityMultiTenant {
+			continue
+		}
+
+		switch {
+		case failed[installation.ID]:
+			stuck++
+		case model.EnsureInstallationDataOutsideCluster(installation) != nil:
+			logger.Warnf("Installation %s stores its data on the cluster and cannot be migrated off it", installation.ID)
+			stuck++
+		case installation.State == model.InstallationStateStable:
+			candidates = append(candidates, installation)
+		case isTransientInstallationState(installation.State):
+			waiting++
+		default:
+			logger.Warnf("Installation %s in state %q cannot be migrated off the cluster", installation.ID, installation.State)
+			stuck++
+		}
+	}
+
+	if drainOp.State == model.ClusterDrainStateRequested {
+		logger.Infof("Draining %d installations from cluster", len(candidates)+len(migrating)+waiting)
+	}
+
+	slots := int(drainOp.MaxConcurrentMigrations) - len(migrating)
+	for _, installation := range candidates {
+		if slots <= 0 {
+			waiting++
+			continue
+		}
+
+		migrationOp, err := s.migrateInstallation(installation, cluster, instanceID, logger)
+		if err != nil {
+			logger.WithError(err).Warnf("Failed to migrate installation %s off the cluster", installation.ID)
+			if common.ErrToStatus(err) >= http.StatusInternalServerError {
+				waiting++
+			} else {
+				stuck++
+			}
+			continue
+		}
+
+		logger.Infof("Started cluster migration %s of installation %s to cluster %s", migrationOp.ID, installation.ID, migrationOp.TargetClusterID)
+		migrating[installation.ID] = true
+		slots--
+	}
+
+	if len(migrating) > 0 || waiting > 0 {
+		return model.ClusterDrainStateInProgress
+	}
+	if stuck > 0 {
+		logger.Warnf("Cluster drain finished with %d installations left on the cluster", stuck)
+		return s.completeDrain(drainOp, model.ClusterDrainStateFailed, logger)
+	}
+
+	logger.Info("All multitenant installations were migrated off the cluster")
+
+	return s.completeDrain(drainOp, model.ClusterDrainStateSucceeded, logger)
+}
+
+// migrateInstallation triggers the cluster migration of the installation to the
+// cluster selected by the installation scheduler.
+func (s *ClusterDrainSupervisor) migrateInstallation(installation *model.Installation, drainedCluster *model.Cluster, instanceID string, logger log.FieldLogger) (*model.InstallationClusterMigrationOperation, error) {
+	targetCluster, err := s.selectTargetCluster(installation, drainedCluster, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select target cluster")
+	}
+	if targetCluster == nil {
+		return nil, common.NewErr(http.StatusBadRequest, errors.New("no eligible target cluster found"))
+	}
+
+	installation, lock, err := getAndLockInstallation(s.store, installation.ID, instanceID, logger)
+	if err != nil {
+		return nil, common.ErrWrap(http.StatusInternalServerError, err, "failed to get and lock installation")
+	}
+	defer lock.Unlock()
+
+	request := &model.InstallationClusterMigrationRequest{
+		InstallationID:  installation.ID,
+		TargetClusterID: targetCluster.ID,
+	}
+	migrationOp, err := common.TriggerInstallationClusterMigration(s.store, request, installation, s.cloud.GetCloudEnvironmentName(), logger)
+	if err != nil {
+		return nil, err
+	}
+	return migrationOp, nil
+}
+
+// selectTargetCluster returns the cluster the scheduler prioritizes for the
+// installation, excluding the drained cluster, or nil if there is none.
+func (s *ClusterDrainSupervisor) selectTargetCluster(installation *model.Installation, drainedCluster *model.Cluster, logger log.FieldLogger) (*model.Cluster, error) {
+	clusters, err := s.scheduler.PrioritizeClusters(installation)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to prioritize clusters")
+	}
+
+	for _, cluster := range clusters {
+		if cluster.ID == drainedCluster.ID {
+			continue
+		}
+		err = model.EnsureClusterReadyForInstallationMigration(cluster, installation)
+		if err != nil {
+			logger.WithError(err).Debugf("Cluster %s is not eligible for migration", cluster.ID)
+			continue
+		}
+
+		return cluster, nil
+	}
+
+	return nil, nil
+}
+
+func (s *ClusterDrainSupervisor) completeDrain(drainOp *model.ClusterDrainOperation, state model.ClusterDrainOperationState, logger log.FieldLogger) model.ClusterDrainOperationState {
+	drainOp.CompleteAt = utils.GetMillis()
+	err := s.store.UpdateClusterDrainOperation(drainOp)
+	if err != nil {
+		logger.WithError(err).Error("Failed to set complete at for cluster drain operation")
+		return drainOp.State
+	}
+
+	return state
+}
+
+// isTransientInstallationState returns true if the installation is expected
+// to leave the given state without user interaction.
+func isTransientInstallationState(state string) bool {
+	switch state {
+	case model.InstallationStateDBRestorationInProgress,
+		model.InstallationStateDBMigrationInProgress,
+		model.InstallationStateClusterMigrationInProgress:
+		return true
+	}
+	for _, pendingState := range model.AllInstallationStatesPendingWork {
+		if state == pendingState {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import log "github.com/sirupsen/logrus"
+
+type clusterDrainOperationLockStore interface {
+	LockClusterDrainOperations(ids []string, lockerID string) (bool, error)
+	UnlockClusterDrainOperations(ids []string, lockerID string, force bool) (bool, error)
+}
+
+type clusterDrainOperationLock struct {
+	ids      []string
+	lockerID string
+	store    clusterDrainOperationLockStore
+	logger   log.FieldLogger
+}
+
+func newClusterDrainOperationLock(id, lockerID string, store clusterDrainOperationLockStore, logger log.FieldLogger) *clusterDrainOperationLock {
+	return &clusterDrainOperationLock{
+		ids:      []string{id},
+		lockerID: lockerID,
+		store:    store,
+		logger:   logger,
+	}
+}
+
+func (l *clusterDrainOperationLock) TryLock() bool {
+	locked, err := l.store.LockClusterDrainOperations(l.ids, l.lockerID)
+	if err != nil {
+		l.logger.WithError(err).Error("failed to lock clusterDrainOperations")
+		return false
+	}
+
+	return locked
+}
+
+func (l *clusterDrainOperationLock) Unlock() {
+	unlocked, err := l.store.UnlockClusterDrainOperations(l.ids, l.lockerID, false)
+	if err != nil {
+		l.logger.WithError(err).Error("failed to unlock clusterDrainOperations")
+	} else if !unlocked {
+		l.logger.Error("failed to release lock for clusterDrainOperations")
+	}
+}
@@ -0,0 +1,253 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/internal/tools/cloud"
+	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
+	"github.com/mattermost/mattermost-cloud/k8s"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterDrainSupervisor_Supervise(t *testing.T) {
+	newSupervisorWithProvisioner := func(sqlStore *store.SQLStore, provisioner *mockInstallationProvisioner, t *testing.T) *supervisor.ClusterDrainSupervisor {
+		return supervisor.NewClusterDrainSupervisor(
+			sqlStore,
+			provisioner,
+			cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}),
+			"instanceID",
+			supervisor.NewInstallationSupervisorSchedulingOptions(false, 80, 0, ""),
+			testlib.MakeLogger(t),
+		)
+	}
+	newSupervisor := func(sqlStore *store.SQLStore, t *testing.T) *supervisor.ClusterDrainSupervisor {
+		return newSupervisorWithProvisioner(sqlStore, &mockInstallationProvisioner{}, t)
+	}
+
+	t.Run("start migrations up to concurrency limit", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		drainOp, cluster := setupClusterDrain(t, sqlStore, 2)
+		for i := 0; i < 3; i++ {
+			setupDrainedInstallation(t, sqlStore, cluster, model.InstallationAffinityMultiTenant, model.InstallationStateStable)
+		}
+		target1 := setupDrainTargetCluster(t, sqlStore, true)
+		target2 := setupDrainTargetCluster(t, sqlStore, true)
+		setupDrainTargetCluster(t, sqlStore, false)
+
+		newSupervisor(sqlStore, t).Supervise(drainOp)
+
+		drainOp, err := sqlStore.GetClusterDrainOperation(drainOp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.ClusterDrainStateInProgress, drainOp.State)
+
+		migrationOps, err := sqlStore.GetInstallationClusterMigrationOperations(&model.InstallationClusterMigrationFilter{
+			SourceClusterID: cluster.ID,
+			Paging:          model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		require.Len(t, migrationOps, 2)
+
+		for _, migrationOp := range migrationOps {
+			assert.Contains(t, []string{target1.ID, target2.ID}, migrationOp.TargetClusterID)
+			installation, err := sqlStore.GetInstallation(migrationOp.InstallationID, false, false)
+			require.NoError(t, err)
+			assert.Equal(t, model.InstallationStateClusterMigrationInProgress, installation.State)
+		}
+
+		t.Run("wait for running migrations", func(t *testing.T) {
+			newSupervisor(sqlStore, t).Supervise(drainOp)
+
+			migrationOps, err = sqlStore.GetInstallationClusterMigrationOperations(&model.InstallationClusterMigrationFilter{
+				SourceClusterID: cluster.ID,
+				Paging:          model.AllPagesNotDeleted(),
+			})
+			require.NoError(t, err)
+			assert.Len(t, migrationOps, 2)
+		})
+	})
+
+	t.Run("succeed when only isolated installations remain", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		drainOp, cluster := setupClusterDrain(t, sqlStore, 1)
+		setupDrainedInstallation(t, sqlStore, cluster, model.InstallationAffinityIsolated, model.InstallationStateStable)
+
+		newSupervisor(sqlStore, t).Supervise(drainOp)
+
+		drainOp, err := sqlStore.GetClusterDrainOperation(drainOp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.ClusterDrainStateSucceeded, drainOp.State)
+		assert.NotZero(t, drainOp.CompleteAt)
+	})
+
+	t.Run("wait for installations in transient state", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		drainOp, cluster := setupClusterDrain(t, sqlStore, 1)
+		setupDrainedInstallation(t, sqlStore, cluster, model.InstallationAffinityMultiTenant, model.InstallationStateUpdateRequested)
+		setupDrainTargetCluster(t, sqlStore, true)
+
+		newSupervisor(sqlStore, t).Supervise(drainOp)
+
+		drainOp, err := sqlStore.GetClusterDrainOperation(drainOp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.ClusterDrainStateInProgress, drainOp.State)
+	})
+
+	t.Run("fail when no target cluster is eligible", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		drainOp, cluster := setupClusterDrain(t, sqlStore, 1)
+		setupDrainedInstallation(t, sqlStore, cluster, model.InstallationAffinityMultiTenant, model.InstallationStateStable)
+		setupDrainTargetCluster(t, sqlStore, false)
+
+		newSupervisor(sqlStore, t).Supervise(drainOp)
+
+		drainOp, err := sqlStore.GetClusterDrainOperation(drainOp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.ClusterDrainStateFailed, drainOp.State)
+	})
+
+	t.Run("fail when no target cluster has enough resources", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		drainOp, cluster := setupClusterDrain(t, sqlStore, 1)
+		setupDrainedInstallation(t, sqlStore, cluster, model.InstallationAffinityMultiTenant, model.InstallationStateStable)
+		setupDrainTargetCluster(t, sqlStore, true)
+
+		provisioner := &mockInstallationProvisioner{
+			UseCustomClusterResources: true,
+			CustomClusterResources: &k8s.ClusterResources{
+				MilliTotalCPU:    1000,
+				MilliUsedCPU:     900,
+				MilliTotalMemory: 1000,
+				MilliUsedMemory:  900,
+			},
+		}
+		newSupervisorWithProvisioner(sqlStore, provisioner, t).Supervise(drainOp)
+
+		drainOp, err := sqlStore.GetClusterDrainOperation(drainOp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.ClusterDrainStateFailed, drainOp.State)
+	})
+
+	t.Run("fail when installation data is stored on the cluster", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		drainOp, cluster := setupClusterDrain(t, sqlStore, 1)
+		installation := setupDrainedInstallation(t, sqlStore, cluster, model.InstallationAffinityMultiTenant, model.InstallationStateStable)
+		installation.Filestore = model.InstallationFilestoreMinioOperator
+		err := sqlStore.UpdateInstallation(installation)
+		require.NoError(t, err)
+		setupDrainTargetCluster(t, sqlStore, true)
+
+		newSupervisor(sqlStore, t).Supervise(drainOp)
+
+		drainOp, err = sqlStore.GetClusterDrainOperation(drainOp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.ClusterDrainStateFailed, drainOp.State)
+
+		migrationOps, err := sqlStore.GetInstallationClusterMigrationOperations(&model.InstallationClusterMigrationFilter{
+			InstallationID: installation.ID,
+			Paging:         model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		assert.Empty(t, migrationOps)
+	})
+
+	t.Run("do not retry failed migrations", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		drainOp, cluster := setupClusterDrain(t, sqlStore, 1)
+		installation := setupDrainedInstallation(t, sqlStore, cluster, model.InstallationAffinityMultiTenant, model.InstallationStateStable)
+		target := setupDrainTargetCluster(t, sqlStore, true)
+
+		err := sqlStore.CreateInstallationClusterMigrationOperation(&model.InstallationClusterMigrationOperation{
+			InstallationID:  installation.ID,
+			SourceClusterID: cluster.ID,
+			TargetClusterID: target.ID,
+			State:           model.InstallationClusterMigrationStateFailed,
+		})
+		require.NoError(t, err)
+
+		newSupervisor(sqlStore, t).Supervise(drainOp)
+
+		drainOp, err = sqlStore.GetClusterDrainOperation(drainOp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.ClusterDrainStateFailed, drainOp.State)
+
+		migrationOps, err := sqlStore.GetInstallationClusterMigrationOperations(&model.InstallationClusterMigrationFilter{
+			InstallationID: installation.ID,
+			Paging:         model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		assert.Len(t, migrationOps, 1)
+	})
+}
+
+func setupClusterDrain(t *testing.T, sqlStore *store.SQLStore, maxConcurrentMigrations int64) (*model.ClusterDrainOperation, *model.Cluster) {
+	cluster := &model.Cluster{State: model.ClusterStateStable, AllowInstallations: true}
+	err := sqlStore.CreateCluster(cluster, nil)
+	require.NoError(t, err)
+
+	drainOp, err := sqlStore.TriggerClusterDrain(&model.ClusterDrainOperation{
+		MaxConcurrentMigrations: maxConcurrentMigrations,
+	}, cluster)
+	require.NoError(t, err)
+
+	return drainOp, cluster
+}
+
+func setupDrainedInstallation(t *testing.T, sqlStore *store.SQLStore, cluster *model.Cluster, affinity, state string) *model.Installation {
+	installation := &model.Installation{
+		Database:  model.InstallationDatabaseMultiTenantRDSPostgres,
+		Filestore: model.InstallationFilestoreBifrost,
+		Affinity:  affinity,
+		Size:      model.InstallationDefaultSize,
+		State:     state,
+	}
+	err := sqlStore.CreateInstallation(installation, nil)
+	require.NoError(t, err)
+
+	err = sqlStore.CreateClusterInstallation(&model.ClusterInstallation{
+		InstallationID: installation.ID,
+		ClusterID:      cluster.ID,
+		Namespace:      installation.ID,
+		State:          model.ClusterInstallationStateStable,
+	})
+	require.NoError(t, err)
+
+	return installation
+}
+
+func setupDrainTargetCluster(t *testing.T, sqlStore *store.SQLStore, allowInstallations bool) *model.Cluster {
+	cluster := &model.Cluster{State: model.ClusterStateStable, AllowInstallations: allowInstallations}
+	err := sqlStore.CreateCluster(cluster, nil)
+	require.NoError(t, err)
+
+	return cluster
+}
@@ -11,9 +11,10 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
-	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
+	"github.com/mattermost/mattermost-cloud/internal/tools/cloud"
 	"github.com/mattermost/mattermost-cloud/internal/webhook"
 	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
 )
 
 // clusterInstallationStore abstracts the database operations required to query installations.
@@ -32,6 +33,9 @@ type clusterInstallationStore interface {
 	GetInstallationBackups(filter *model.InstallationBackupFilter) ([]*model.InstallationBackup, error)
 
 	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+	CreateEvent(event *model.Event) error
 }
 
 // clusterInstallationProvisioner abstracts the provisioning operations required by the cluster installation supervisor.
@@ -46,17 +50,17 @@ type clusterInstallationProvisioner interface {
 type ClusterInstallationSupervisor struct {
 	store       clusterInstallationStore
 	provisioner clusterInstallationProvisioner
-	aws         aws.AWS
+	cloud       cloud.Provider
 	instanceID  string
 	logger      log.FieldLogger
 }
 
 // NewClusterInstallationSupervisor creates a new ClusterInstallationSupervisor.
-func NewClusterInstallationSupervisor(store clusterInstallationStore, clusterInstallationProvisioner clusterInstallationProvisioner, aws aws.AWS, instanceID string, logger log.FieldLogger) *ClusterInstallationSupervisor {
+func NewClusterInstallationSupervisor(store clusterInstallationStore, clusterInstallationProvisioner clusterInstallationProvisioner, cloudProvider cloud.Provider, instanceID string, logger log.FieldLogger) *ClusterInstallationSupervisor {
 	return &ClusterInstallationSupervisor{
 		store:       store,
 		provisioner: clusterInstallationProvisioner,
-		aws:         aws,
+		cloud:       cloudProvider,
 		instanceID:  instanceID,
 		logger:      logger,
 	}
@@ -72,7 +76,7 @@ func (s *ClusterInstallationSupervisor) Do() error {
 	clusterInstallations, err := s.store.GetUnlockedClusterInstallationsPendingWork()
 	if err != nil {
 		s.logger.WithError(err).Warn("Failed to query for cluster installations pending work")
-		return nil
+		return errors.Wrap(err, "failed to query for cluster installations pending work")
 	}
 
 	for _, clusterInstallation := range clusterInstallations {
@@ -111,7 +115,7 @@ func (s *ClusterInstallationSupervisor) Supervise(clusterInstallation *model.Clu
 
 	logger.Debugf("Supervising cluster installation in state %s", clusterInstallation.State)
 
-	newState := s.transitionClusterInstallation(clusterInstallation, logger)
+	newState, stateErr := s.transitionClusterInstallation(clusterInstallation, logger)
 
 	clusterInstallation, err = s.store.GetClusterInstallation(clusterInstallation.ID)
 	if err != nil {
@@ -134,11 +138,13 @@ func (s *ClusterInstallationSupervisor) Supervise(clusterInstallation *model.Clu
 	webhookPayload := &model.WebhookPayload{
 		Type:      model.TypeClusterInstallation,
 		ID:        clusterInstallation.ID,
+		OwnerID:   installationOwnerID(s.store, clusterInstallation.InstallationID, logger),
 		NewState:  newState,
 		OldState:  oldState,
 		Timestamp: time.Now().UnixNano(),
-		ExtraData: map[string]string{"ClusterID": clusterInstallation.ClusterID, "Environment": s.aws.GetCloudEnvironmentName()},
+		ExtraData: map[string]string{"ClusterID": clusterInstallation.ClusterID, "Environment": s.cloud.GetCloudEnvironmentName()},
 	}
+	recordStateChangeEvent(s.store, webhookPayload, s.instanceID, stateErr, logger)
 	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
 	if err != nil {
 		logger.WithError(err).Error("Unable to process and send webhooks")
@@ -160,25 +166,25 @@ func failedClusterInstallationState(state string) string {
 }
 
 // transitionClusterInstallation works with the given cluster installation to transition it to a final state.
-func (s *ClusterInstallationSupervisor) transitionClusterInstallation(clusterInstallation *model.ClusterInstallation, logger log.FieldLogger) string {
+func (s *ClusterInstallationSupervisor) transitionClusterInstallation(clusterInstallation *model.ClusterInstallation, logger log.FieldLogger) (string, error) {
 	cluster, err := s.store.GetCluster(clusterInstallation.ClusterID)
 	if err != nil {
 		logger.WithError(err).Warnf("Failed to query cluster %s", clusterInstallation.ClusterID)
-		return clusterInstallation.State
+		return clusterInstallation.State, nil
 	}
 	if cluster == nil {
 		logger.Errorf("Failed to find cluster %s", clusterInstallation.ClusterID)
-		return failedClusterInstallationState(clusterInstallation.State)
+		return failedClusterInstallationState(clusterInstallation.State), errors.Errorf("failed to find cluster %s", clusterInstallation.ClusterID)
 	}
 
 	installation, err := s.store.GetInstallation(clusterInstallation.InstallationID, true, false)
 	if err != nil {
 		logger.WithError(err).Warnf("Failed to query installation %s", clusterInstallation.InstallationID)
-		return clusterInstallation.State
+		return clusterInstallation.State, nil
 	}
 	if installation == nil {
 		logger.Errorf("Failed to find installation %s", clusterInstallation.InstallationID)
-		return failedClusterInstallationState(clusterInstallation.State)
+		return failedClusterInstallationState(clusterInstallation.State), errors.Errorf("failed to find installation %s", clusterInstallation.InstallationID)
 	}
 
 	switch clusterInstallation.State {
@@ -190,29 +196,29 @@ func (s *ClusterInstallationSupervisor) transitionClusterInstallation(clusterIns
 		return s.checkReconcilingClusterInstallation(clusterInstallation, installation, cluster, logger)
 	default:
 		logger.Warnf("Found cluster installation pending work in unexpected state %s", clusterInstallation.State)
-		return clusterInstallation.State
+		return clusterInstallation.State, nil
 	}
 }
 
-func (s *ClusterInstallationSupervisor) createClusterInstallation(clusterInstallation *model.ClusterInstallation, logger log.FieldLogger, installation *model.Installation, cluster *model.Cluster) string {
+func (s *ClusterInstallationSupervisor) createClusterInstallation(clusterInstallation *model.ClusterInstallation, logger log.FieldLogger, installation *model.Installation, cluster *model.Cluster) (string, error) {
 	err := s.provisioner.ClusterInstallationProvisioner(installation.CRVersion).
 		CreateClusterInstallation(cluster, installation, clusterInstallation)
 	if err != nil {
 		logger.WithError(err).Error("Failed to provision cluster installation")
-		return model.ClusterInstallationStateCreationRequested
+		return model.ClusterInstallationStateCreationRequested, nil
 	}
 
 	err = s.store.UpdateClusterInstallation(clusterInstallation)
 	if err != nil {
 		logger.WithError(err).Error("Failed to record updated cluster installation after provisioning")
-		return model.ClusterInstallationStateCreationFailed
+		return model.ClusterInstallationStateCreationFailed, errors.Wrap(err, "failed to record updated cluster installation after provisioning")
 	}
 
 	logger.Info("Finished creating cluster installation")
-	return model.ClusterInstallationStateReconciling
+	return model.ClusterInstallationStateReconciling, nil
 }
 
-func (s *ClusterInstallationSupervisor) deleteClusterInstallation(clusterInstallation *model.ClusterInstallation, logger log.FieldLogger, installation *model.Installation, cluster *model.Cluster) string {
+func (s *ClusterInstallationSupervisor) deleteClusterInstallation(clusterInstallation *model.ClusterInstallation, logger log.FieldLogger, installation *model.Installation, cluster *model.Cluster) (string, error) {
 	backups, err := s.store.GetInstallationBackups(&model.InstallationBackupFilter{
 		ClusterInstallationID: clusterInstallation.ID,
 		States:                model.AllInstallationBackupsStatesRunning,
@@ -220,50 +226,50 @@ func (s *ClusterInstallationSupervisor) deleteClusterInstallation(clusterInstall
 	})
 	if err != nil {
 		logger.WithError(err).Error("Failed to get installation backups running in cluster installation namespace")
-		return clusterInstallation.State
+		return clusterInstallation.State, nil
 	}
 	if len(backups) > 0 {
 		logger.Warn("Cannot delete cluster installation while backups are running in its namespace")
-		return clusterInstallation.State
+		return clusterInstallation.State, nil
 	}
 
 	err = s.provisioner.ClusterInstallationProvisioner(installation.CRVersion).
 		DeleteClusterInstallation(cluster, installation, clusterInstallation)
 	if err != nil {
 		logger.WithError(err).Error("Failed to delete cluster installation")
-		return model.ClusterInstallationStateDeletionFailed
+		return model.ClusterInstallationStateDeletionFailed, errors.Wrap(err, "failed to delete cluster installation")
 	}
 
 	err = s.store.DeleteClusterInstallation(clusterInstallation.ID)
 	if err != nil {
 		logger.WithError(err).Error("Failed to record deleted cluster installation after deletion")
-		return model.ClusterStateDeletionFailed
+		return model.ClusterStateDeletionFailed, errors.Wrap(err, "failed to record deleted cluster installation after deletion")
 	}
 
 	logger.Info("Finished deleting cluster installation")
-	return model.ClusterInstallationStateDeleted
+	return model.ClusterInstallationStateDeleted, nil
 }
 
-func (s *ClusterInstallationSupervisor) checkReconcilingClusterInstallation(clusterInstallation *model.ClusterInstallation, installation *model.Installation, cluster *model.Cluster, logger log.FieldLogger) string {
+func (s *ClusterInstallationSupervisor) checkReconcilingClusterInstallation(clusterInstallation *model.ClusterInstallation, installation *model.Installation, cluster *model.Cluster, logger log.FieldLogger) (string, error) {
 	isReady, err := s.provisioner.ClusterInstallationProvisioner(installation.CRVersion).
 		IsResourceReady(cluster, clusterInstallation)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get cluster installation resource")
-		return model.ClusterInstallationStateReconciling
+		return model.ClusterInstallationStateReconciling, nil
 	}
 
 	if !isReady {
 		logger.Info("Cluster installation is still reconciling")
-		return model.ClusterInstallationStateReconciling
+		return model.ClusterInstallationStateReconciling, nil
 	}
 
 	err = s.provisioner.ClusterInstallationProvisioner(installation.CRVersion).
 		DeleteOldClusterInstallationLicenseSecrets(cluster, installation, clusterInstallation)
 	if err != nil {
 		logger.WithError(err).Error("Failed to ensure old license secrets were deleted")
-		return model.ClusterInstallationStateReconciling
+		return model.ClusterInstallationStateReconciling, nil
 	}
 
 	logger.Info("Cluster installation finished reconciling")
-	return model.ClusterInstallationStateStable
+	return model.ClusterInstallationStateStable, nil
 }
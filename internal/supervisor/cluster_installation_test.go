@@ -12,6 +12,8 @@ import (
 	"github.com/mattermost/mattermost-cloud/internal/store"
 	"github.com/mattermost/mattermost-cloud/internal/supervisor"
 	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/internal/tools/cloud"
+	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
 	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/stretchr/testify/require"
 )
@@ -64,6 +66,18 @@ func (s *mockClusterInstallationStore) GetWebhooks(filter *model.WebhookFilter)
 	return nil, nil
 }
 
+func (s *mockClusterInstallationStore) CreateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	return nil
+}
+
+func (s *mockClusterInstallationStore) UpdateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	return nil
+}
+
+func (s *mockClusterInstallationStore) CreateEvent(event *model.Event) error {
+	return nil
+}
+
 type mockClusterInstallationProvisioner struct{}
 
 func (p *mockClusterInstallationProvisioner) ClusterInstallationProvisioner(version string) provisioner.ClusterInstallationProvisioner {
@@ -75,7 +89,7 @@ func TestClusterInstallationSupervisorDo(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		mockStore := &mockClusterInstallationStore{}
 
-		supervisor := supervisor.NewClusterInstallationSupervisor(mockStore, &mockClusterInstallationProvisioner{}, &mockAWS{}, "instanceID", logger)
+		supervisor := supervisor.NewClusterInstallationSupervisor(mockStore, &mockClusterInstallationProvisioner{}, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
 		err := supervisor.Do()
 		require.NoError(t, err)
 
@@ -100,7 +114,7 @@ func TestClusterInstallationSupervisorDo(t *testing.T) {
 		}
 		mockStore.ClusterInstallation = mockStore.UnlockedClusterInstallationsPendingWork[0]
 
-		supervisor := supervisor.NewClusterInstallationSupervisor(mockStore, &mockClusterInstallationProvisioner{}, &mockAWS{}, "instanceID", logger)
+		supervisor := supervisor.NewClusterInstallationSupervisor(mockStore, &mockClusterInstallationProvisioner{}, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
 		err := supervisor.Do()
 		require.NoError(t, err)
 
@@ -132,7 +146,7 @@ func TestClusterInstallationSupervisorSupervise(t *testing.T) {
 			t.Run(tc.Description, func(t *testing.T) {
 				logger := testlib.MakeLogger(t)
 				sqlStore := store.MakeTestSQLStore(t, logger)
-				supervisor := supervisor.NewClusterInstallationSupervisor(sqlStore, &mockClusterInstallationProvisioner{}, &mockAWS{}, "instanceID", logger)
+				supervisor := supervisor.NewClusterInstallationSupervisor(sqlStore, &mockClusterInstallationProvisioner{}, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
 
 				installation := &model.Installation{}
 				err := sqlStore.CreateInstallation(installation, nil)
@@ -167,7 +181,7 @@ func TestClusterInstallationSupervisorSupervise(t *testing.T) {
 			t.Run(tc.Description, func(t *testing.T) {
 				logger := testlib.MakeLogger(t)
 				sqlStore := store.MakeTestSQLStore(t, logger)
-				supervisor := supervisor.NewClusterInstallationSupervisor(sqlStore, &mockClusterInstallationProvisioner{}, &mockAWS{}, "instanceID", logger)
+				supervisor := supervisor.NewClusterInstallationSupervisor(sqlStore, &mockClusterInstallationProvisioner{}, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
 
 				cluster := &model.Cluster{}
 				err := sqlStore.CreateCluster(cluster, nil)
@@ -191,7 +205,7 @@ func TestClusterInstallationSupervisorSupervise(t *testing.T) {
 	t.Run("cannot delete when backup is running", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewClusterInstallationSupervisor(sqlStore, &mockClusterInstallationProvisioner{}, &mockAWS{}, "instanceID", logger)
+		supervisor := supervisor.NewClusterInstallationSupervisor(sqlStore, &mockClusterInstallationProvisioner{}, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
 
 		cluster := &model.Cluster{}
 		err := sqlStore.CreateCluster(cluster, nil)
@@ -237,7 +251,7 @@ func TestClusterInstallationSupervisorSupervise(t *testing.T) {
 			t.Run(tc.Description, func(t *testing.T) {
 				logger := testlib.MakeLogger(t)
 				sqlStore := store.MakeTestSQLStore(t, logger)
-				supervisor := supervisor.NewClusterInstallationSupervisor(sqlStore, &mockClusterInstallationProvisioner{}, &mockAWS{}, "instanceID", logger)
+				supervisor := supervisor.NewClusterInstallationSupervisor(sqlStore, &mockClusterInstallationProvisioner{}, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
 
 				cluster := &model.Cluster{}
 				err := sqlStore.CreateCluster(cluster, nil)
@@ -265,7 +279,7 @@ func TestClusterInstallationSupervisorSupervise(t *testing.T) {
 	t.Run("state has changed since cluster installation was selected to be worked on", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewClusterInstallationSupervisor(sqlStore, &mockClusterInstallationProvisioner{}, &mockAWS{}, "instanceID", logger)
+		supervisor := supervisor.NewClusterInstallationSupervisor(sqlStore, &mockClusterInstallationProvisioner{}, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
 
 		cluster := &model.Cluster{}
 		err := sqlStore.CreateCluster(cluster, nil)
@@ -10,14 +10,17 @@ import (
 	"github.com/mattermost/mattermost-cloud/internal/store"
 	"github.com/mattermost/mattermost-cloud/internal/supervisor"
 	"github.com/mattermost/mattermost-cloud/internal/testlib"
-	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
+	"github.com/mattermost/mattermost-cloud/internal/tools/cloud"
+	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
 	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 )
 
 type mockClusterStore struct {
 	Cluster                     *model.Cluster
 	UnlockedClustersPendingWork []*model.Cluster
+	PendingWorkError            error
 	Clusters                    []*model.Cluster
 
 	UnlockChan         chan interface{}
@@ -29,7 +32,7 @@ func (s *mockClusterStore) GetCluster(clusterID string) (*model.Cluster, error)
 }
 
 func (s *mockClusterStore) GetUnlockedClustersPendingWork() ([]*model.Cluster, error) {
-	return s.UnlockedClustersPendingWork, nil
+	return s.UnlockedClustersPendingWork, s.PendingWorkError
 }
 
 func (s *mockClusterStore) GetClusters(clusterFilter *model.ClusterFilter) ([]*model.Cluster, error) {
@@ -60,29 +63,47 @@ func (s *mockClusterStore) GetWebhooks(filter *model.WebhookFilter) ([]*model.We
 	return nil, nil
 }
 
-type mockClusterProvisioner struct{}
+func (s *mockClusterStore) CreateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	return nil
+}
+
+func (s *mockClusterStore) UpdateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	return nil
+}
+
+func (s *mockClusterStore) CreateEvent(event *model.Event) error {
+	return nil
+}
+
+func (s *mockClusterStore) GetEvents(filter *model.EventFilter) ([]*model.Event, error) {
+	return nil, nil
+}
+
+type mockClusterProvisioner struct {
+	UpgradeError error
+}
 
 func (p *mockClusterProvisioner) PrepareCluster(cluster *model.Cluster) bool {
 	return true
 }
 
-func (p *mockClusterProvisioner) CreateCluster(cluster *model.Cluster, aws aws.AWS) error {
+func (p *mockClusterProvisioner) CreateCluster(cluster *model.Cluster) error {
 	return nil
 }
 
-func (p *mockClusterProvisioner) ProvisionCluster(cluster *model.Cluster, aws aws.AWS) error {
+func (p *mockClusterProvisioner) ProvisionCluster(cluster *model.Cluster) error {
 	return nil
 }
 
-func (p *mockClusterProvisioner) UpgradeCluster(cluster *model.Cluster, aws aws.AWS) error {
-	return nil
+func (p *mockClusterProvisioner) UpgradeCluster(cluster *model.Cluster) error {
+	return p.UpgradeError
 }
 
-func (p *mockClusterProvisioner) ResizeCluster(cluster *model.Cluster, aws aws.AWS) error {
+func (p *mockClusterProvisioner) ResizeCluster(cluster *model.Cluster) error {
 	return nil
 }
 
-func (p *mockClusterProvisioner) DeleteCluster(cluster *model.Cluster, aws aws.AWS) error {
+func (p *mockClusterProvisioner) DeleteCluster(cluster *model.Cluster) error {
 	return nil
 }
 
@@ -95,13 +116,24 @@ func TestClusterSupervisorDo(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		mockStore := &mockClusterStore{}
 
-		supervisor := supervisor.NewClusterSupervisor(mockStore, &mockClusterProvisioner{}, &mockAWS{}, "instanceID", logger)
+		supervisor := supervisor.NewClusterSupervisor(mockStore, &mockClusterProvisioner{}, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger, cloudMetrics)
 		err := supervisor.Do()
 		require.NoError(t, err)
 
 		require.Equal(t, 0, mockStore.UpdateClusterCalls)
 	})
 
+	t.Run("pending work query failure", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		mockStore := &mockClusterStore{PendingWorkError: errors.New("database is down")}
+
+		supervisor := supervisor.NewClusterSupervisor(mockStore, &mockClusterProvisioner{}, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger, cloudMetrics)
+		err := supervisor.Do()
+		require.EqualError(t, err, "failed to query for clusters pending work: database is down")
+
+		require.Equal(t, 0, mockStore.UpdateClusterCalls)
+	})
+
 	t.Run("mock cluster creation", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		mockStore := &mockClusterStore{}
@@ -113,7 +145,7 @@ func TestClusterSupervisorDo(t *testing.T) {
 		mockStore.Cluster = mockStore.UnlockedClustersPendingWork[0]
 		mockStore.UnlockChan = make(chan interface{})
 
-		supervisor := supervisor.NewClusterSupervisor(mockStore, &mockClusterProvisioner{}, &mockAWS{}, "instanceID", logger)
+		supervisor := supervisor.NewClusterSupervisor(mockStore, &mockClusterProvisioner{}, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger, cloudMetrics)
 		err := supervisor.Do()
 		require.NoError(t, err)
 
@@ -141,7 +173,7 @@ func TestClusterSupervisorSupervise(t *testing.T) {
 		t.Run(tc.Description, func(t *testing.T) {
 			logger := testlib.MakeLogger(t)
 			sqlStore := store.MakeTestSQLStore(t, logger)
-			supervisor := supervisor.NewClusterSupervisor(sqlStore, &mockClusterProvisioner{}, &mockAWS{}, "instanceID", logger)
+			supervisor := supervisor.NewClusterSupervisor(sqlStore, &mockClusterProvisioner{}, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger, cloudMetrics)
 
 			cluster := &model.Cluster{
 				Provider:                model.ProviderAWS,
@@ -156,13 +188,59 @@ func TestClusterSupervisorSupervise(t *testing.T) {
 			cluster, err = sqlStore.GetCluster(cluster.ID)
 			require.NoError(t, err)
 			require.Equal(t, tc.ExpectedState, cluster.State)
+
+			events, err := sqlStore.GetEvents(&model.EventFilter{
+				ResourceID: cluster.ID,
+				Paging:     model.AllPagesNotDeleted(),
+			})
+			require.NoError(t, err)
+			if tc.InitialState == tc.ExpectedState {
+				require.Empty(t, events)
+				return
+			}
+			require.Len(t, events, 1)
+			require.Equal(t, model.TypeCluster, events[0].ResourceType)
+			require.Equal(t, tc.InitialState, events[0].OldState)
+			require.Equal(t, tc.ExpectedState, events[0].NewState)
+			require.Equal(t, "instanceID", events[0].InstanceID)
+			require.Empty(t, events[0].Error)
 		})
 	}
 
+	t.Run("failure transition records the cause", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		provisioner := &mockClusterProvisioner{UpgradeError: errors.New("kops update failed")}
+		supervisor := supervisor.NewClusterSupervisor(sqlStore, provisioner, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger, cloudMetrics)
+
+		cluster := &model.Cluster{
+			Provider:                model.ProviderAWS,
+			ProvisionerMetadataKops: &model.KopsMetadata{},
+			State:                   model.ClusterStateUpgradeRequested,
+		}
+		err := sqlStore.CreateCluster(cluster, nil)
+		require.NoError(t, err)
+
+		supervisor.Supervise(cluster)
+
+		cluster, err = sqlStore.GetCluster(cluster.ID)
+		require.NoError(t, err)
+		require.Equal(t, model.ClusterStateUpgradeFailed, cluster.State)
+
+		events, err := sqlStore.GetEvents(&model.EventFilter{
+			ResourceID: cluster.ID,
+			Paging:     model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		require.Equal(t, model.ClusterStateUpgradeFailed, events[0].NewState)
+		require.Equal(t, "failed to upgrade cluster: kops update failed", events[0].Error)
+	})
+
 	t.Run("state has changed since cluster was selected to be worked on", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewClusterSupervisor(sqlStore, &mockClusterProvisioner{}, &mockAWS{}, "instanceID", logger)
+		supervisor := supervisor.NewClusterSupervisor(sqlStore, &mockClusterProvisioner{}, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger, cloudMetrics)
 
 		cluster := &model.Cluster{
 			Provider: model.ProviderAWS,
@@ -5,6 +5,10 @@
 package supervisor
 
 import (
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
+	"github.com/mattermost/mattermost-cloud/internal/webhook"
 	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -81,3 +85,275 @@ func getClusterForClusterInstallation(store getClusterForClusterInstallationStor
 
 	return cluster, nil
 }
+
+type installationOwnerStore interface {
+	GetInstallation(installationID string, includeGroupConfig, includeGroupConfigOverrides bool) (*model.Installation, error)
+}
+
+// installationOwnerID returns the owner of the given installation so that it
+// can be included in webhook payloads. An empty string is returned when the
+// installation cannot be found.
+func installationOwnerID(store installationOwnerStore, installationID string, logger log.FieldLogger) string {
+	installation, err := store.GetInstallation(installationID, false, false)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to get installation owner for webhook")
+		return ""
+	}
+	if installation == nil {
+		return ""
+	}
+
+	return installation.OwnerID
+}
+
+type eventStore interface {
+	CreateEvent(event *model.Event) error
+}
+
+// recordStateChangeEvent persists the state change described by the webhook
+// payload to the event history. The optional stateErr describes the failure
+// which caused the state change.
+func recordStateChangeEvent(store eventStore, payload *model.WebhookPayload, instanceID string, stateErr error, logger log.FieldLogger) {
+	event := model.NewStateChangeEvent(payload, instanceID)
+	if stateErr != nil {
+		event.Error = stateErr.Error()
+	}
+
+	err := store.CreateEvent(event)
+	if err != nil {
+		logger.WithError(err).Error("Failed to record state change event")
+	}
+}
+
+// measuredTransition describes a state transition whose duration is recorded
+// in metrics. The transition starts when a supervisor begins to work on the
+// request state and ends when the completed state is reached.
+type measuredTransition struct {
+	name           string
+	requestState   string
+	completedState string
+}
+
+type transitionEventStore interface {
+	GetEvents(filter *model.EventFilter) ([]*model.Event, error)
+}
+
+type transitionObserver interface {
+	ObserveTransition(resource, transition string, duration time.Duration)
+}
+
+// observeTransition records the duration of the transition completed by the
+// state change described by the payload, if it is one of the given measured
+// transitions. Transitions completed within a single supervise cycle are
+// measured from the start of the cycle, others from the event recorded when
+// the request state was left. It must be called before the event of the
+// completing state change is recorded.
+func observeTransition(store transitionEventStore, observer transitionObserver, transitions []measuredTransition, payload *model.WebhookPayload, cycleStart time.Time, logger log.FieldLogger) {
+	for _, transition := range transitions {
+		if transition.completedState != payload.NewState {
+			continue
+		}
+		if transition.requestState == payload.OldState {
+			observer.ObserveTransition(payload.Type, transition.name, time.Since(cycleStart))
+			return
+		}
+
+		started, err := getLatestEvent(store, &model.EventFilter{ResourceID: payload.ID, OldState: transition.requestState})
+		if err != nil {
+			logger.WithError(err).Warn("Failed to get transition start event")
+			return
+		}
+		if started == nil {
+			continue
+		}
+
+		// Ensure that the transition was not already completed before, in
+		// which case the completed state is reached by another transition.
+		previous, err := getLatestEvent(store, &model.EventFilter{ResourceID: payload.ID, NewState: transition.completedState})
+		if err != nil {
+			logger.WithError(err).Warn("Failed to get previous transition completion event")
+			return
+		}
+		if previous != nil && previous.Timestamp > started.Timestamp {
+			continue
+		}
+
+		observer.ObserveTransition(payload.Type, transition.name, time.Since(utils.TimeFromMillis(started.Timestamp)))
+		return
+	}
+}
+
+// getLatestEvent returns the newest event matching the filter or nil if there is none.
+func getLatestEvent(store transitionEventStore, filter *model.EventFilter) (*model.Event, error) {
+	filter.Paging = model.Paging{Page: 0, PerPage: 1}
+	events, err := store.GetEvents(filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get events")
+	}
+	if len(events) == 0 {
+		return nil, nil
+	}
+
+	return events[0], nil
+}
+
+type clusterResizeStore interface {
+	UpdateCluster(cluster *model.Cluster) error
+	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+	eventStore
+}
+
+// requestClusterWorkerNodeCount requests resizing of the cluster worker nodes
+// to the given count. The cluster is expected to be locked by the caller.
+func requestClusterWorkerNodeCount(store clusterResizeStore, cluster *model.Cluster, nodeCount int64, instanceID, env string, logger log.FieldLogger) error {
+	logger.WithField("cluster", cluster.ID).Infof("Scaling cluster worker nodes from %d to %d (max=%d)",
+		cluster.ProvisionerMetadataKops.NodeMinCount,
+		nodeCount,
+		cluster.ProvisionerMetadataKops.NodeMaxCount,
+	)
+
+	oldState := cluster.State
+	cluster.State = model.ClusterStateResizeRequested
+	cluster.ProvisionerMetadataKops.ChangeRequest = &model.KopsMetadataRequestedState{
+		NodeMinCount: nodeCount,
+	}
+
+	err := store.UpdateCluster(cluster)
+	if err != nil {
+		return errors.Wrap(err, "failed to update cluster")
+	}
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeCluster,
+		ID:        cluster.ID,
+		NewState:  model.ClusterStateResizeRequested,
+		OldState:  oldState,
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"Environment": env},
+	}
+	recordStateChangeEvent(store, webhookPayload, instanceID, nil, logger)
+	err = webhook.SendToAllWebhooks(store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	return nil
+}
+
+type installationStateChangeStore interface {
+	GetInstallation(installationID string, includeGroupConfig, includeGroupConfigOverrides bool) (*model.Installation, error)
+	UpdateInstallation(installation *model.Installation) error
+	UpdateInstallationState(installation *model.Installation) error
+	IsInstallationBackupRunning(installationID string) (bool, error)
+	installationLockStore
+
+	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+	eventStore
+}
+
+// lockInstallationForStateChange locks the given installation and returns it
+// refreshed, so that a state change can be requested on behalf of an
+// operation. It returns true if the operation should be retried on the next
+// pass and an error if the installation cannot be changed at all. The
+// returned lock must be released by the caller.
+func lockInstallationForStateChange(store installationStateChangeStore, installationID, instanceID string, logger log.FieldLogger) (*model.Installation, *installationLock, bool, error) {
+	installation, err := store.GetInstallation(installationID, false, false)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get installation")
+		return nil, nil, true, nil
+	}
+	if installation == nil || installation.State == model.InstallationStateDeleted {
+		return nil, nil, false, errors.New("installation does not exist")
+	}
+
+	lock := newInstallationLock(installation.ID, instanceID, store, logger)
+	if !lock.TryLock() {
+		logger.Debug("Failed to lock installation; retrying later")
+		return nil, nil, true, nil
+	}
+
+	installation, err = store.GetInstallation(installation.ID, false, false)
+	if err != nil {
+		lock.Unlock()
+		logger.WithError(err).Error("Failed to get refreshed installation")
+		return nil, nil, true, nil
+	}
+
+	if installation.APISecurityLock {
+		lock.Unlock()
+		return nil, nil, false, errors.New("installation is locked by the API security lock")
+	}
+
+	return installation, lock, false, nil
+}
+
+// requestInstallationStateChange requests the given state of the locked
+// installation. Update requests apply the patch first and deletion requests
+// wait for running backups. It returns true if the state was requested,
+// true if the request should be retried on the next pass, and an error if
+// the installation cannot be transitioned to the state. Update requests
+// whose patch does not change the installation are neither requested nor
+// retried.
+func requestInstallationStateChange(store installationStateChangeStore, installation *model.Installation, newState string, patch *model.PatchInstallationRequest, instanceID, env string, logger log.FieldLogger) (bool, bool, error) {
+	if !installation.ValidTransitionState(newState) {
+		for _, state := range model.AllInstallationStatesPendingWork {
+			if installation.State == state {
+				logger.Debugf("Installation is in %q state; retrying later", installation.State)
+				return false, true, nil
+			}
+		}
+		return false, false, errors.Errorf("installation cannot be transitioned to %q while in state %q", newState, installation.State)
+	}
+
+	var err error
+	oldState := installation.State
+	switch newState {
+	case model.InstallationStateUpdateRequested:
+		if patch == nil || !patch.Apply(installation) {
+			logger.Debug("Installation update does not change the installation")
+			return false, false, nil
+		}
+		installation.State = newState
+		err = store.UpdateInstallation(installation)
+	case model.InstallationStateDeletionRequested:
+		running, backupErr := store.IsInstallationBackupRunning(installation.ID)
+		if backupErr != nil {
+			logger.WithError(backupErr).Error("Failed to check for running backups")
+			return false, true, nil
+		}
+		if running {
+			logger.Debug("Installation backup is running; retrying deletion later")
+			return false, true, nil
+		}
+		installation.State = newState
+		err = store.UpdateInstallationState(installation)
+	default:
+		installation.State = newState
+		err = store.UpdateInstallationState(installation)
+	}
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to update installation state to %q", newState)
+		return false, true, nil
+	}
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeInstallation,
+		ID:        installation.ID,
+		OwnerID:   installation.OwnerID,
+		NewState:  installation.State,
+		OldState:  oldState,
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"DNS": installation.DNS, "Environment": env},
+	}
+	recordStateChangeEvent(store, webhookPayload, instanceID, nil, logger)
+	err = webhook.SendToAllWebhooks(store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	return true, false, nil
+}
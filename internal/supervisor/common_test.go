@@ -0,0 +1,115 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+type mockTransitionEventStore struct {
+	events []*model.Event
+}
+
+// GetEvents returns the newest matching event, mimicking the filters used to
+// observe transitions.
+func (s *mockTransitionEventStore) GetEvents(filter *model.EventFilter) ([]*model.Event, error) {
+	for i := len(s.events) - 1; i >= 0; i-- {
+		event := s.events[i]
+		if filter.OldState != "" && event.OldState != filter.OldState {
+			continue
+		}
+		if filter.NewState != "" && event.NewState != filter.NewState {
+			continue
+		}
+		return []*model.Event{event}, nil
+	}
+
+	return nil, nil
+}
+
+type mockTransitionObserver struct {
+	transitions map[string]time.Duration
+}
+
+func (o *mockTransitionObserver) ObserveTransition(resource, transition string, duration time.Duration) {
+	o.transitions[resource+"/"+transition] = duration
+}
+
+func TestObserveTransition(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	transitions := []measuredTransition{
+		{"creation", model.ClusterStateCreationRequested, model.ClusterStateStable},
+		{"wake-up", model.InstallationStateWakeUpRequested, model.InstallationStateStable},
+	}
+	minutesAgo := func(minutes int) int64 {
+		return utils.GetMillisAtTime(time.Now().Add(-time.Duration(minutes) * time.Minute))
+	}
+
+	t.Run("single cycle", func(t *testing.T) {
+		observer := &mockTransitionObserver{transitions: map[string]time.Duration{}}
+		payload := &model.WebhookPayload{
+			Type:     model.TypeCluster,
+			OldState: model.ClusterStateCreationRequested,
+			NewState: model.ClusterStateStable,
+		}
+
+		observeTransition(&mockTransitionEventStore{}, observer, transitions, payload, time.Now().Add(-time.Minute), logger)
+		require.Len(t, observer.transitions, 1)
+		require.True(t, observer.transitions["cluster/creation"] >= time.Minute)
+	})
+
+	t.Run("not measured", func(t *testing.T) {
+		observer := &mockTransitionObserver{transitions: map[string]time.Duration{}}
+		payload := &model.WebhookPayload{
+			Type:     model.TypeCluster,
+			OldState: model.ClusterStateUpgradeRequested,
+			NewState: model.ClusterStateUpgradeFailed,
+		}
+
+		observeTransition(&mockTransitionEventStore{}, observer, transitions, payload, time.Now(), logger)
+		require.Empty(t, observer.transitions)
+	})
+
+	t.Run("multiple cycles", func(t *testing.T) {
+		observer := &mockTransitionObserver{transitions: map[string]time.Duration{}}
+		store := &mockTransitionEventStore{events: []*model.Event{
+			{OldState: model.InstallationStateUpdateInProgress, NewState: model.InstallationStateStable, Timestamp: minutesAgo(60)},
+			{OldState: model.InstallationStateWakeUpRequested, NewState: model.InstallationStateUpdateInProgress, Timestamp: minutesAgo(10)},
+		}}
+		payload := &model.WebhookPayload{
+			Type:     model.TypeInstallation,
+			OldState: model.InstallationStateUpdateInProgress,
+			NewState: model.InstallationStateStable,
+		}
+
+		observeTransition(store, observer, transitions, payload, time.Now(), logger)
+		require.Len(t, observer.transitions, 1)
+		require.True(t, observer.transitions["installation/wake-up"] >= 10*time.Minute)
+		require.True(t, observer.transitions["installation/wake-up"] < 60*time.Minute)
+	})
+
+	t.Run("completed by another transition", func(t *testing.T) {
+		observer := &mockTransitionObserver{transitions: map[string]time.Duration{}}
+		store := &mockTransitionEventStore{events: []*model.Event{
+			{OldState: model.InstallationStateWakeUpRequested, NewState: model.InstallationStateUpdateInProgress, Timestamp: minutesAgo(60)},
+			{OldState: model.InstallationStateUpdateInProgress, NewState: model.InstallationStateStable, Timestamp: minutesAgo(50)},
+			{OldState: model.InstallationStateUpdateRequested, NewState: model.InstallationStateUpdateInProgress, Timestamp: minutesAgo(10)},
+		}}
+		payload := &model.WebhookPayload{
+			Type:     model.TypeInstallation,
+			OldState: model.InstallationStateUpdateInProgress,
+			NewState: model.InstallationStateStable,
+		}
+
+		observeTransition(store, observer, transitions, payload, time.Now(), logger)
+		require.Empty(t, observer.transitions)
+	})
+}
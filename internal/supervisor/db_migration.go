@@ -50,6 +50,9 @@ type installationDBMigrationStore interface {
 	GetCluster(id string) (*model.Cluster, error)
 
 	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+	CreateEvent(event *model.Event) error
 
 	model.InstallationDatabaseStoreInterface
 }
@@ -106,7 +109,7 @@ func (s *DBMigrationSupervisor) Do() error {
 	installationDBMigrations, err := s.store.GetUnlockedInstallationDBMigrationOperationsPendingWork()
 	if err != nil {
 		s.logger.WithError(err).Warn("Failed to query for pending work")
-		return nil
+		return errors.Wrap(err, "failed to query for pending work")
 	}
 
 	for _, migration := range installationDBMigrations {
@@ -145,7 +148,7 @@ func (s *DBMigrationSupervisor) Supervise(migration *model.InstallationDBMigrati
 
 	logger.Debugf("Supervising migration in state %s", migration.State)
 
-	newState := s.transitionMigration(migration, s.instanceID, logger)
+	newState, stateErr := s.transitionMigration(migration, s.instanceID, logger)
 
 	migration, err = s.store.GetInstallationDBMigrationOperation(migration.ID)
 	if err != nil {
@@ -169,11 +172,13 @@ func (s *DBMigrationSupervisor) Supervise(migration *model.InstallationDBMigrati
 	webhookPayload := &model.WebhookPayload{
 		Type:      model.TypeInstallationDBMigration,
 		ID:        migration.ID,
+		OwnerID:   installationOwnerID(s.store, migration.InstallationID, logger),
 		NewState:  string(migration.State),
 		OldState:  string(oldState),
 		Timestamp: time.Now().UnixNano(),
 		ExtraData: map[string]string{"Environment": s.aws.GetCloudEnvironmentName()},
 	}
+	recordStateChangeEvent(s.store, webhookPayload, s.instanceID, stateErr, logger)
 	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
 	if err != nil {
 		logger.WithError(err).Error("Unable to process and send webhooks")
@@ -183,7 +188,7 @@ func (s *DBMigrationSupervisor) Supervise(migration *model.InstallationDBMigrati
 }
 
 // transitionMigration works with the given db migration to transition it to a final state.
-func (s *DBMigrationSupervisor) transitionMigration(dbMigration *model.InstallationDBMigrationOperation, instanceID string, logger log.FieldLogger) model.InstallationDBMigrationOperationState {
+func (s *DBMigrationSupervisor) transitionMigration(dbMigration *model.InstallationDBMigrationOperation, instanceID string, logger log.FieldLogger) (model.InstallationDBMigrationOperationState, error) {
 	switch dbMigration.State {
 	case model.InstallationDBMigrationStateRequested:
 		return s.triggerInstallationBackup(dbMigration, instanceID, logger)
@@ -205,63 +210,63 @@ func (s *DBMigrationSupervisor) transitionMigration(dbMigration *model.Installat
 		return s.failMigration(dbMigration, instanceID, logger)
 	default:
 		logger.Warnf("Found migration pending work in unexpected state %s", dbMigration.State)
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 }
 
 // TODO: Possibly allow passing existing backupID to migrate from.
-func (s *DBMigrationSupervisor) triggerInstallationBackup(dbMigration *model.InstallationDBMigrationOperation, instanceID string, logger log.FieldLogger) model.InstallationDBMigrationOperationState {
+func (s *DBMigrationSupervisor) triggerInstallationBackup(dbMigration *model.InstallationDBMigrationOperation, instanceID string, logger log.FieldLogger) (model.InstallationDBMigrationOperationState, error) {
 	installation, lock, err := getAndLockInstallation(s.store, dbMigration.InstallationID, instanceID, logger)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get and lock installation")
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 	defer lock.Unlock()
 
 	backup, err := common.TriggerInstallationBackup(s.store, installation, s.environment, logger)
 	if err != nil {
 		logger.WithError(err).Error("Failed to trigger installation backup")
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 
 	dbMigration.BackupID = backup.ID
 	err = s.store.UpdateInstallationDBMigrationOperation(dbMigration)
 	if err != nil {
 		logger.WithError(err).Error("Failed to set backup ID for DB migration")
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 
-	return model.InstallationDBMigrationStateBackupInProgress
+	return model.InstallationDBMigrationStateBackupInProgress, nil
 }
 
-func (s *DBMigrationSupervisor) waitForInstallationBackup(dbMigration *model.InstallationDBMigrationOperation, instanceID string, logger log.FieldLogger) model.InstallationDBMigrationOperationState {
+func (s *DBMigrationSupervisor) waitForInstallationBackup(dbMigration *model.InstallationDBMigrationOperation, instanceID string, logger log.FieldLogger) (model.InstallationDBMigrationOperationState, error) {
 	backup, err := s.store.GetInstallationBackup(dbMigration.BackupID)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get installation backup")
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 
 	switch backup.State {
 	case model.InstallationBackupStateBackupSucceeded:
 		logger.Info("Backup for migration finished successfully")
-		return model.InstallationDBMigrationStateDatabaseSwitch
+		return model.InstallationDBMigrationStateDatabaseSwitch, nil
 	case model.InstallationBackupStateBackupFailed:
 		logger.Error("Backup for migration failed")
-		return model.InstallationDBMigrationStateFailing
+		return model.InstallationDBMigrationStateFailing, errors.Errorf("backup %s for migration failed", backup.ID)
 	case model.InstallationBackupStateBackupInProgress, model.InstallationBackupStateBackupRequested:
 		logger.Debug("Backup for migration in progress")
-		return dbMigration.State
+		return dbMigration.State, nil
 	default:
 		logger.Errorf("Unexpected state of installation backup for migration: %q", backup.State)
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 }
 
-func (s *DBMigrationSupervisor) switchDatabase(dbMigration *model.InstallationDBMigrationOperation, instanceID string, logger log.FieldLogger) model.InstallationDBMigrationOperationState {
+func (s *DBMigrationSupervisor) switchDatabase(dbMigration *model.InstallationDBMigrationOperation, instanceID string, logger log.FieldLogger) (model.InstallationDBMigrationOperationState, error) {
 	installation, lock, err := getAndLockInstallation(s.store, dbMigration.InstallationID, instanceID, logger)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get and lock installation")
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 	defer lock.Unlock()
 
@@ -270,123 +275,123 @@ func (s *DBMigrationSupervisor) switchDatabase(dbMigration *model.InstallationDB
 	err = sourceDB.MigrateOut(s.store, dbMigration, logger)
 	if err != nil {
 		logger.WithError(err).Errorf("Failed to migrate installation out of database")
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 
 	destinationDB := s.dbProvider.GetDatabase(installation.ID, dbMigration.DestinationDatabase)
 	err = destinationDB.MigrateTo(s.store, dbMigration, logger)
 	if err != nil {
 		logger.WithError(err).Errorf("Failed to migrate installation to database")
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 
 	installation.Database = dbMigration.DestinationDatabase
 	err = s.store.UpdateInstallation(installation)
 	if err != nil {
 		logger.WithError(err).Errorf("Failed to switch database for installation")
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 
-	return model.InstallationDBMigrationStateRefreshSecrets
+	return model.InstallationDBMigrationStateRefreshSecrets, nil
 }
 
-func (s *DBMigrationSupervisor) refreshCredentials(dbMigration *model.InstallationDBMigrationOperation, instanceID string, logger log.FieldLogger) model.InstallationDBMigrationOperationState {
+func (s *DBMigrationSupervisor) refreshCredentials(dbMigration *model.InstallationDBMigrationOperation, instanceID string, logger log.FieldLogger) (model.InstallationDBMigrationOperationState, error) {
 	installation, lock, err := getAndLockInstallation(s.store, dbMigration.InstallationID, instanceID, logger)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get and lock installation")
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 	defer lock.Unlock()
 
 	err = s.refreshSecrets(installation)
 	if err != nil {
 		logger.WithError(err).Error("Failed to refresh credentials for cluster installations")
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 
-	return model.InstallationDBMigrationStateTriggerRestoration
+	return model.InstallationDBMigrationStateTriggerRestoration, nil
 }
 
-func (s *DBMigrationSupervisor) triggerInstallationRestoration(dbMigration *model.InstallationDBMigrationOperation, instanceID string, logger log.FieldLogger) model.InstallationDBMigrationOperationState {
+func (s *DBMigrationSupervisor) triggerInstallationRestoration(dbMigration *model.InstallationDBMigrationOperation, instanceID string, logger log.FieldLogger) (model.InstallationDBMigrationOperationState, error) {
 	installation, lock, err := getAndLockInstallation(s.store, dbMigration.InstallationID, instanceID, logger)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get and lock installation")
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 	defer lock.Unlock()
 
 	backup, err := s.store.GetInstallationBackup(dbMigration.BackupID)
 	if err != nil {
 		logger.WithError(err).Errorf("Failed to get backup")
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 	if backup == nil {
 		logger.Errorf("Backup not found on restoration phase")
-		return model.InstallationDBMigrationStateFailing
+		return model.InstallationDBMigrationStateFailing, errors.Errorf("backup %s not found on restoration phase", dbMigration.BackupID)
 	}
 
 	dbRestoration, err := common.TriggerInstallationDBRestoration(s.store, installation, backup, s.environment, logger)
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to trigger installation db restoration")
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 
 	dbMigration.InstallationDBRestorationOperationID = dbRestoration.ID
 	err = s.store.UpdateInstallationDBMigrationOperation(dbMigration)
 	if err != nil {
 		logger.WithError(err).Error("Failed to set restoration operation ID for DB migration")
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 
-	return model.InstallationDBMigrationStateRestorationInProgress
+	return model.InstallationDBMigrationStateRestorationInProgress, nil
 }
 
-func (s *DBMigrationSupervisor) waitForInstallationRestoration(dbMigration *model.InstallationDBMigrationOperation, instanceID string, logger log.FieldLogger) model.InstallationDBMigrationOperationState {
+func (s *DBMigrationSupervisor) waitForInstallationRestoration(dbMigration *model.InstallationDBMigrationOperation, instanceID string, logger log.FieldLogger) (model.InstallationDBMigrationOperationState, error) {
 	restoration, err := s.store.GetInstallationDBRestorationOperation(dbMigration.InstallationDBRestorationOperationID)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get installation restoration")
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 
 	switch restoration.State {
 	case model.InstallationDBRestorationStateSucceeded:
 		logger.Info("Restoration for migration finished successfully")
-		return model.InstallationDBMigrationStateUpdatingInstallationConfig
+		return model.InstallationDBMigrationStateUpdatingInstallationConfig, nil
 	case model.InstallationDBRestorationStateFailed, model.InstallationDBRestorationStateInvalid:
 		logger.Error("Restoration for migration failed or is invalid")
-		return model.InstallationDBMigrationStateFailing
+		return model.InstallationDBMigrationStateFailing, errors.Errorf("restoration %s for migration is %s", restoration.ID, restoration.State)
 	default:
 		logger.Debug("Restoration for migration in progress")
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 }
 
-func (s *DBMigrationSupervisor) updateInstallationConfig(dbMigration *model.InstallationDBMigrationOperation, instanceID string, logger log.FieldLogger) model.InstallationDBMigrationOperationState {
+func (s *DBMigrationSupervisor) updateInstallationConfig(dbMigration *model.InstallationDBMigrationOperation, instanceID string, logger log.FieldLogger) (model.InstallationDBMigrationOperationState, error) {
 	installation, err := s.store.GetInstallation(dbMigration.InstallationID, false, false)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get installation")
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 	if installation == nil {
 		logger.Error("Installation not found")
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 
 	clusterInstallation, ciLock, err := claimClusterInstallation(s.store, installation, instanceID, logger)
 	if err != nil {
 		logger.WithError(err).Error("Failed to claim cluster installation")
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 	defer ciLock.Unlock()
 
 	cluster, err := s.store.GetCluster(clusterInstallation.ClusterID)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get cluster")
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 	if cluster == nil {
 		logger.Error("Cluster not found")
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 
 	command := []string{"/bin/sh", "-c", "mattermost config set SqlSettings.DataSource $MM_CONFIG"}
@@ -394,17 +399,17 @@ func (s *DBMigrationSupervisor) updateInstallationConfig(dbMigration *model.Inst
 	err = s.dbMigrationCIProvisioner.ExecClusterInstallationJob(cluster, clusterInstallation, command...)
 	if err != nil {
 		logger.WithError(err).Error("Failed to execute command on cluster installation")
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 
-	return model.InstallationDBMigrationStateFinalizing
+	return model.InstallationDBMigrationStateFinalizing, nil
 }
 
-func (s *DBMigrationSupervisor) finalizeMigration(dbMigration *model.InstallationDBMigrationOperation, instanceID string, logger log.FieldLogger) model.InstallationDBMigrationOperationState {
+func (s *DBMigrationSupervisor) finalizeMigration(dbMigration *model.InstallationDBMigrationOperation, instanceID string, logger log.FieldLogger) (model.InstallationDBMigrationOperationState, error) {
 	installation, lock, err := getAndLockInstallation(s.store, dbMigration.InstallationID, instanceID, logger)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get and lock installation")
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 	defer lock.Unlock()
 
@@ -414,18 +419,20 @@ func (s *DBMigrationSupervisor) finalizeMigration(dbMigration *model.Installatio
 	err = s.store.UpdateInstallation(installation)
 	if err != nil {
 		logger.WithError(err).Errorf("Failed to set installation back to hibernating after migration")
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 
 	webhookPayload := &model.WebhookPayload{
 		Type:      model.TypeInstallation,
 		ID:        installation.ID,
+		OwnerID:   installation.OwnerID,
 		NewState:  installation.State,
 		OldState:  oldState,
 		Timestamp: time.Now().UnixNano(),
 		ExtraData: map[string]string{"DNS": installation.DNS, "Environment": s.environment},
 	}
 
+	recordStateChangeEvent(s.store, webhookPayload, s.instanceID, nil, logger)
 	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
 	if err != nil {
 		logger.WithError(err).Error("Unable to process and send webhooks")
@@ -435,17 +442,17 @@ func (s *DBMigrationSupervisor) finalizeMigration(dbMigration *model.Installatio
 	err = s.store.UpdateInstallationDBMigrationOperation(dbMigration)
 	if err != nil {
 		logger.WithError(err).Errorf("Failed to set complete at for db migration")
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 
-	return model.InstallationDBMigrationStateSucceeded
+	return model.InstallationDBMigrationStateSucceeded, nil
 }
 
-func (s *DBMigrationSupervisor) failMigration(dbMigration *model.InstallationDBMigrationOperation, instanceID string, logger log.FieldLogger) model.InstallationDBMigrationOperationState {
+func (s *DBMigrationSupervisor) failMigration(dbMigration *model.InstallationDBMigrationOperation, instanceID string, logger log.FieldLogger) (model.InstallationDBMigrationOperationState, error) {
 	installation, lock, err := getAndLockInstallation(s.store, dbMigration.InstallationID, instanceID, logger)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get and lock installation")
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 	defer lock.Unlock()
 
@@ -455,24 +462,26 @@ func (s *DBMigrationSupervisor) failMigration(dbMigration *model.InstallationDBM
 	err = s.store.UpdateInstallation(installation)
 	if err != nil {
 		logger.WithError(err).Errorf("Failed to set installation back to hibernating after migration")
-		return dbMigration.State
+		return dbMigration.State, nil
 	}
 
 	webhookPayload := &model.WebhookPayload{
 		Type:      model.TypeInstallation,
 		ID:        installation.ID,
+		OwnerID:   installation.OwnerID,
 		NewState:  installation.State,
 		OldState:  oldState,
 		Timestamp: time.Now().UnixNano(),
 		ExtraData: map[string]string{"DNS": installation.DNS, "Environment": s.environment},
 	}
 
+	recordStateChangeEvent(s.store, webhookPayload, s.instanceID, nil, logger)
 	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
 	if err != nil {
 		logger.WithError(err).Error("Unable to process and send webhooks")
 	}
 
-	return model.InstallationDBMigrationStateFailed
+	return model.InstallationDBMigrationStateFailed, nil
 }
 
 func (s *DBMigrationSupervisor) refreshSecrets(installation *model.Installation) error {
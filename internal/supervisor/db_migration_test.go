@@ -145,6 +145,18 @@ func (m *mockDBMigrationStore) GetWebhooks(filter *model.WebhookFilter) ([]*mode
 	return nil, nil
 }
 
+func (m *mockDBMigrationStore) CreateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	return nil
+}
+
+func (m *mockDBMigrationStore) UpdateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	return nil
+}
+
+func (m *mockDBMigrationStore) CreateEvent(event *model.Event) error {
+	return nil
+}
+
 type mockDatabase struct{}
 
 func (m *mockDatabase) TeardownMigrated(store model.InstallationDatabaseStoreInterface, migrationOp *model.InstallationDBMigrationOperation, logger log.FieldLogger) error {
@@ -189,6 +201,10 @@ func (m *mockResourceUtil) GetDatabase(installationID, dbType string) model.Data
 	return &mockDatabase{}
 }
 
+func (m *mockResourceUtil) GetDatabaseForInstallation(installation *model.Installation) model.Database {
+	return &mockDatabase{}
+}
+
 type mockMigrationProvisioner struct {
 	expectedCommand []string
 }
@@ -4,6 +4,12 @@
 
 package supervisor
 
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/metrics"
+)
+
 // Doer describes an action to be done.
 type Doer interface {
 	Do() error
@@ -31,3 +37,34 @@ func (md MultiDoer) Shutdown() {
 		doer.Shutdown()
 	}
 }
+
+// InstrumentedDoer records metrics about the cycles of the wrapped doer.
+type InstrumentedDoer struct {
+	name    string
+	doer    Doer
+	metrics *metrics.CloudMetrics
+}
+
+// NewInstrumentedDoer wraps the given doer to record metrics about its
+// cycles under the given name.
+func NewInstrumentedDoer(name string, doer Doer, metrics *metrics.CloudMetrics) *InstrumentedDoer {
+	return &InstrumentedDoer{
+		name:    name,
+		doer:    doer,
+		metrics: metrics,
+	}
+}
+
+// Do executes the wrapped doer, recording the duration and outcome of the cycle.
+func (d *InstrumentedDoer) Do() error {
+	start := time.Now()
+	err := d.doer.Do()
+	d.metrics.ObserveSupervisorCycle(d.name, time.Since(start), err)
+
+	return err
+}
+
+// Shutdown tells the wrapped doer to perform shutdown tasks.
+func (d *InstrumentedDoer) Shutdown() {
+	d.doer.Shutdown()
+}
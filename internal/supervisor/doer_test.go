@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -86,3 +87,17 @@ func TestMultiDoer(t *testing.T) {
 		}
 	})
 }
+
+func TestInstrumentedDoer(t *testing.T) {
+	successDoer := supervisor.NewInstrumentedDoer("test-success", &testDoer{calls: make(chan bool, 2)}, cloudMetrics)
+	require.NoError(t, successDoer.Do())
+	require.NoError(t, successDoer.Do())
+
+	failureDoer := supervisor.NewInstrumentedDoer("test-failure", &failDoer{}, cloudMetrics)
+	require.EqualError(t, failureDoer.Do(), "failed")
+
+	require.Equal(t, float64(2), testutil.ToFloat64(cloudMetrics.SupervisorCyclesCounter.WithLabelValues("test-success")))
+	require.Equal(t, float64(0), testutil.ToFloat64(cloudMetrics.SupervisorFailuresCounter.WithLabelValues("test-success")))
+	require.Equal(t, float64(1), testutil.ToFloat64(cloudMetrics.SupervisorCyclesCounter.WithLabelValues("test-failure")))
+	require.Equal(t, float64(1), testutil.ToFloat64(cloudMetrics.SupervisorFailuresCounter.WithLabelValues("test-failure")))
+}
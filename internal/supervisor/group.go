@@ -5,6 +5,7 @@
 package supervisor
 
 import (
+	"fmt"
 	"math/rand"
 	"time"
 
@@ -13,11 +14,14 @@ import (
 	"github.com/mattermost/mattermost-cloud/internal/store"
 	"github.com/mattermost/mattermost-cloud/internal/webhook"
 	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
 )
 
 // groupStore abstracts the database operations required to query groups.
 type groupStore interface {
 	GetUnlockedGroupsPendingWork() ([]*model.Group, error)
+	GetGroup(id string) (*model.Group, error)
+	UpdateGroupRolloutState(groupID, rolloutState string) error
 	GetGroupRollingMetadata(groupID string) (*store.GroupRollingMetadata, error)
 	LockGroup(groupID, lockerID string) (bool, error)
 	UnlockGroup(groupID, lockerID string, force bool) (bool, error)
@@ -28,11 +32,20 @@ type groupStore interface {
 	UnlockInstallation(installationID, lockerID string, force bool) (bool, error)
 
 	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+	CreateEvent(event *model.Event) error
 }
 
 // GroupSupervisor finds installations belonging to groups that need to have
 // their configuration reconciled to match a new group configuration setting.
 //
+// When the group has a canary size, only that many installations are updated
+// until they have all finished updating. The rollout is paused when a canary
+// installation fails to update or when the share of failed updates reaches
+// the group failure threshold. Paused rollouts must be resumed through the
+// API.
+//
 // The degree of parallelism is controlled by a weighted semaphore, intended to
 // be shared with other clients needing to coordinate background jobs.
 type GroupSupervisor struct {
@@ -61,7 +74,7 @@ func (s *GroupSupervisor) Do() error {
 	groups, err := s.store.GetUnlockedGroupsPendingWork()
 	if err != nil {
 		s.logger.WithError(err).Warn("Failed to query for groups")
-		return nil
+		return errors.Wrap(err, "failed to query for groups")
 	}
 
 	for _, group := range groups {
@@ -94,19 +107,55 @@ func (s *GroupSupervisor) Supervise(group *model.Group) {
 		return
 	}
 
+	// The rollout may have been paused or aborted through the API since the
+	// group was queried.
+	group, err := s.store.GetGroup(group.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get refreshed group")
+		return
+	}
+	if group == nil || !group.IsRolloutActive() {
+		logger.Warn("Group rollout is not active; skipping...")
+		return
+	}
+
 	groupMetadata, err := s.store.GetGroupRollingMetadata(group.ID)
 	if err != nil {
 		logger.WithError(err).Error("Unable to get installations in group")
 		return
 	}
 
+	progress := groupMetadata.Progress
 	logger = logger.WithFields(log.Fields{
-		"installations-total":   groupMetadata.InstallationsTotalCount,
-		"installations-rolling": groupMetadata.InstallationsRolling,
+		"installations-total":      groupMetadata.InstallationsTotalCount,
+		"installations-rolling":    groupMetadata.InstallationsRolling,
+		"installations-rolled-out": progress.InstallationsRolledOut,
+		"installations-failed":     progress.InstallationsFailed,
 	})
 
-	if int64(groupMetadata.InstallationsRolling) >= group.MaxRolling {
-		logger.Infof("Group already has %d rolling installations with a max of %d", groupMetadata.InstallationsRolling, group.MaxRolling)
+	if progress.IsCanaryFailed(group.CanarySize) {
+		s.pauseRollout(group, "a canary installation failed to update", logger)
+		return
+	}
+	if progress.IsFailureThresholdReached(group.FailureThresholdPercent) {
+		s.pauseRollout(group, fmt.Sprintf("%d of %d updated installations failed", progress.InstallationsFailed, progress.InstallationsFinished()), logger)
+		return
+	}
+
+	maxRolling := group.MaxRolling
+	if progress.IsCanaryInProgress(group.CanarySize) {
+		canariesRemaining := group.CanarySize - progress.InstallationsRolledOut
+		if canariesRemaining <= 0 {
+			logger.Info("Waiting for canary installations to finish updating")
+			return
+		}
+		if groupMetadata.InstallationsRolling+canariesRemaining < maxRolling {
+			maxRolling = groupMetadata.InstallationsRolling + canariesRemaining
+		}
+	}
+
+	if int64(groupMetadata.InstallationsRolling) >= maxRolling {
+		logger.Infof("Group already has %d rolling installations with a max of %d", groupMetadata.InstallationsRolling, maxRolling)
 		return
 	}
 
@@ -118,7 +167,7 @@ func (s *GroupSupervisor) Supervise(group *model.Group) {
 
 	var moved int64
 	for _, id := range groupMetadata.InstallationIDsToBeRolled {
-		if groupMetadata.InstallationsRolling+moved >= group.MaxRolling {
+		if groupMetadata.InstallationsRolling+moved >= maxRolling {
 			// We have bumped up against the max rolling count with the new
 			// installations added to the rolling pool.
 			break
@@ -147,10 +196,12 @@ func (s *GroupSupervisor) Supervise(group *model.Group) {
 			webhookPayload := &model.WebhookPayload{
 				Type:      model.TypeInstallation,
 				ID:        installation.ID,
+				OwnerID:   installation.OwnerID,
 				NewState:  installation.State,
 				OldState:  oldState,
 				Timestamp: time.Now().UnixNano(),
 			}
+			recordStateChangeEvent(s.store, webhookPayload, s.instanceID, nil, logger)
 			err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
 			if err != nil {
 				logger.WithError(err).Error("Unable to process and send webhooks")
@@ -161,3 +212,29 @@ func (s *GroupSupervisor) Supervise(group *model.Group) {
 
 	logger.Infof("Moved %d installations to %s", moved, model.InstallationStateUpdateRequested)
 }
+
+// pauseRollout pauses the rollout of the group configuration for the given
+// reason.
+func (s *GroupSupervisor) pauseRollout(group *model.Group, reason string, logger log.FieldLogger) {
+	err := s.store.UpdateGroupRolloutState(group.ID, model.GroupRolloutStatePaused)
+	if err != nil {
+		logger.WithError(err).Error("Failed to pause group rollout")
+		return
+	}
+
+	logger.Warnf("Paused group rollout: %s", reason)
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeGroupRollout,
+		ID:        group.ID,
+		NewState:  model.GroupRolloutStatePaused,
+		OldState:  group.RolloutState,
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"Reason": reason},
+	}
+	recordStateChangeEvent(s.store, webhookPayload, s.instanceID, nil, logger)
+	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+}
@@ -5,6 +5,7 @@
 package supervisor_test
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -31,6 +32,14 @@ func (s *mockGroupStore) GetUnlockedGroupsPendingWork() ([]*model.Group, error)
 	return s.UnlockedGroupsPendingWork, nil
 }
 
+func (s *mockGroupStore) GetGroup(id string) (*model.Group, error) {
+	return s.Group, nil
+}
+
+func (s *mockGroupStore) UpdateGroupRolloutState(groupID, rolloutState string) error {
+	return nil
+}
+
 func (s *mockGroupStore) GetGroupRollingMetadata(groupID string) (*store.GroupRollingMetadata, error) {
 	return s.GroupRollingMetadata, nil
 }
@@ -75,6 +84,18 @@ func (s *mockGroupStore) GetWebhooks(filter *model.WebhookFilter) ([]*model.Webh
 	return nil, nil
 }
 
+func (s *mockGroupStore) CreateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	return nil
+}
+
+func (s *mockGroupStore) UpdateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	return nil
+}
+
+func (s *mockGroupStore) CreateEvent(event *model.Event) error {
+	return nil
+}
+
 func TestGroupSupervisorDo(t *testing.T) {
 	t.Run("no groups pending work", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
@@ -369,3 +390,176 @@ func TestGroupSupervisor(t *testing.T) {
 		})
 	})
 }
+
+func TestGroupSupervisorRolloutStrategy(t *testing.T) {
+	createInstallation := func(t *testing.T, sqlStore *store.SQLStore, group *model.Group, state string, rolledOut bool) *model.Installation {
+		t.Helper()
+
+		installation := &model.Installation{
+			OwnerID:  model.NewID(),
+			Version:  "version",
+			DNS:      fmt.Sprintf("dns-%s.example.com", model.NewID()),
+			Size:     mmv1alpha1.Size100String,
+			Affinity: model.InstallationAffinityIsolated,
+			GroupID:  &group.ID,
+			State:    state,
+		}
+		err := sqlStore.CreateInstallation(installation, nil)
+		require.NoError(t, err)
+
+		if rolledOut {
+			installation.GroupSequence = &group.Sequence
+			err = sqlStore.UpdateInstallation(installation)
+			require.NoError(t, err)
+		}
+		time.Sleep(1 * time.Millisecond)
+
+		return installation
+	}
+
+	countInstallations := func(t *testing.T, sqlStore *store.SQLStore, state string) int {
+		t.Helper()
+
+		installations, err := sqlStore.GetInstallations(&model.InstallationFilter{
+			Paging: model.AllPagesNotDeleted(),
+		}, false, false)
+		require.NoError(t, err)
+
+		var count int
+		for _, installation := range installations {
+			if installation.State == state {
+				count++
+			}
+		}
+		return count
+	}
+
+	expectRolloutState := func(t *testing.T, sqlStore *store.SQLStore, group *model.Group, rolloutState string) {
+		t.Helper()
+
+		group, err := sqlStore.GetGroup(group.ID)
+		require.NoError(t, err)
+		require.Equal(t, rolloutState, group.RolloutState)
+	}
+
+	t.Run("canary", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		groupSupervisor := supervisor.NewGroupSupervisor(sqlStore, "instanceID", logger)
+
+		group := &model.Group{MaxRolling: 10, CanarySize: 1}
+		err := sqlStore.CreateGroup(group)
+		require.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			createInstallation(t, sqlStore, group, model.InstallationStateStable, false)
+		}
+
+		groupSupervisor.Supervise(group)
+		require.Equal(t, 1, countInstallations(t, sqlStore, model.InstallationStateUpdateRequested))
+
+		t.Run("wait for the canary to finish updating", func(t *testing.T) {
+			installations, err := sqlStore.GetInstallations(&model.InstallationFilter{
+				Paging: model.AllPagesNotDeleted(),
+			}, false, false)
+			require.NoError(t, err)
+			for _, installation := range installations {
+				if installation.State == model.InstallationStateUpdateRequested {
+					installation.State = model.InstallationStateUpdateInProgress
+					installation.GroupSequence = &group.Sequence
+					err = sqlStore.UpdateInstallation(installation)
+					require.NoError(t, err)
+				}
+			}
+
+			groupSupervisor.Supervise(group)
+			require.Equal(t, 2, countInstallations(t, sqlStore, model.InstallationStateStable))
+
+			t.Run("roll out the rest once the canary is stable", func(t *testing.T) {
+				for _, installation := range installations {
+					if installation.State == model.InstallationStateUpdateInProgress {
+						installation.State = model.InstallationStateStable
+						err = sqlStore.UpdateInstallation(installation)
+						require.NoError(t, err)
+					}
+				}
+
+				groupSupervisor.Supervise(group)
+				require.Equal(t, 2, countInstallations(t, sqlStore, model.InstallationStateUpdateRequested))
+				expectRolloutState(t, sqlStore, group, model.GroupRolloutStateActive)
+			})
+		})
+	})
+
+	t.Run("canary failed", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		groupSupervisor := supervisor.NewGroupSupervisor(sqlStore, "instanceID", logger)
+
+		group := &model.Group{MaxRolling: 10, CanarySize: 2}
+		err := sqlStore.CreateGroup(group)
+		require.NoError(t, err)
+
+		createInstallation(t, sqlStore, group, model.InstallationStateUpdateFailed, true)
+		createInstallation(t, sqlStore, group, model.InstallationStateStable, false)
+		createInstallation(t, sqlStore, group, model.InstallationStateStable, false)
+
+		groupSupervisor.Supervise(group)
+		require.Equal(t, 0, countInstallations(t, sqlStore, model.InstallationStateUpdateRequested))
+		expectRolloutState(t, sqlStore, group, model.GroupRolloutStatePaused)
+	})
+
+	t.Run("failure threshold reached", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		groupSupervisor := supervisor.NewGroupSupervisor(sqlStore, "instanceID", logger)
+
+		group := &model.Group{MaxRolling: 10, FailureThresholdPercent: 50}
+		err := sqlStore.CreateGroup(group)
+		require.NoError(t, err)
+
+		createInstallation(t, sqlStore, group, model.InstallationStateUpdateFailed, true)
+		createInstallation(t, sqlStore, group, model.InstallationStateStable, true)
+		createInstallation(t, sqlStore, group, model.InstallationStateStable, false)
+
+		groupSupervisor.Supervise(group)
+		require.Equal(t, 0, countInstallations(t, sqlStore, model.InstallationStateUpdateRequested))
+		expectRolloutState(t, sqlStore, group, model.GroupRolloutStatePaused)
+	})
+
+	t.Run("failure threshold not reached", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		groupSupervisor := supervisor.NewGroupSupervisor(sqlStore, "instanceID", logger)
+
+		group := &model.Group{MaxRolling: 10, FailureThresholdPercent: 50}
+		err := sqlStore.CreateGroup(group)
+		require.NoError(t, err)
+
+		createInstallation(t, sqlStore, group, model.InstallationStateUpdateFailed, true)
+		createInstallation(t, sqlStore, group, model.InstallationStateStable, true)
+		createInstallation(t, sqlStore, group, model.InstallationStateStable, true)
+		createInstallation(t, sqlStore, group, model.InstallationStateStable, false)
+
+		groupSupervisor.Supervise(group)
+		require.Equal(t, 1, countInstallations(t, sqlStore, model.InstallationStateUpdateRequested))
+		expectRolloutState(t, sqlStore, group, model.GroupRolloutStateActive)
+	})
+
+	t.Run("paused rollout", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		groupSupervisor := supervisor.NewGroupSupervisor(sqlStore, "instanceID", logger)
+
+		group := &model.Group{MaxRolling: 10}
+		err := sqlStore.CreateGroup(group)
+		require.NoError(t, err)
+		err = sqlStore.UpdateGroupRolloutState(group.ID, model.GroupRolloutStatePaused)
+		require.NoError(t, err)
+
+		createInstallation(t, sqlStore, group, model.InstallationStateStable, false)
+
+		groupSupervisor.Supervise(group)
+		require.Equal(t, 0, countInstallations(t, sqlStore, model.InstallationStateUpdateRequested))
+	})
+}
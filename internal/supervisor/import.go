@@ -137,7 +137,7 @@ func (s *ImportSupervisor) Shutdown() {
 	s.logger.Debug("Shutting down import supervisor")
 }
 
-func (s *ImportSupervisor) importTranslation(imprt *awat.ImportStatus) error {
+func (s *ImportSupervisor) importTranslation(imprt *awat.ImportStatus) (importErr error) {
 	// get installation metadata
 	installation, err := s.store.GetInstallation(imprt.InstallationID, false, false)
 	if err != nil {
@@ -165,13 +165,16 @@ func (s *ImportSupervisor) importTranslation(imprt *awat.ImportStatus) error {
 	if err != nil {
 		return errors.Wrapf(err, "failed to mark Installation %s as %s", installation.ID, model.InstallationStateImportInProgress)
 	}
-	err = webhook.SendToAllWebhooks(s.store, &model.WebhookPayload{
+	webhookPayload := &model.WebhookPayload{
 		Type:      model.TypeInstallation,
 		ID:        installation.ID,
+		OwnerID:   installation.OwnerID,
 		NewState:  model.InstallationStateImportInProgress,
 		OldState:  model.InstallationStateStable,
 		ExtraData: map[string]string{"TranslationID": imprt.TranslationID, "ImportID": imprt.ID},
-	}, s.logger.WithField("webhookEvent", model.InstallationStateImportInProgress))
+	}
+	recordStateChangeEvent(s.store, webhookPayload, s.ID, nil, s.logger)
+	err = webhook.SendToAllWebhooks(s.store, webhookPayload, s.logger.WithField("webhookEvent", model.InstallationStateImportInProgress))
 	if err != nil {
 		s.logger.WithError(err).Errorf("failed to send webhooks")
 	}
@@ -183,13 +186,16 @@ func (s *ImportSupervisor) importTranslation(imprt *awat.ImportStatus) error {
 			s.logger.WithError(err).Errorf("Failed to mark Installation %s as state stable", installation.ID)
 			return
 		}
-		err = webhook.SendToAllWebhooks(s.store, &model.WebhookPayload{
+		webhookPayload := &model.WebhookPayload{
 			Type:      model.TypeInstallation,
 			ID:        installation.ID,
+			OwnerID:   installation.OwnerID,
 			NewState:  model.InstallationStateStable,
 			OldState:  model.InstallationStateImportInProgress,
 			ExtraData: map[string]string{"TranslationID": imprt.TranslationID, "ImportID": imprt.ID},
-		}, s.logger.WithField("webhookEvent", model.InstallationStateImportInProgress))
+		}
+		recordStateChangeEvent(s.store, webhookPayload, s.ID, importErr, s.logger)
+		err = webhook.SendToAllWebhooks(s.store, webhookPayload, s.logger.WithField("webhookEvent", model.InstallationStateImportInProgress))
 		if err != nil {
 			s.logger.WithError(err).Errorf("failed to send webhooks")
 		}
@@ -5,6 +5,8 @@
 package supervisor
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/mattermost/mattermost-cloud/internal/provisioner"
@@ -12,9 +14,9 @@ import (
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/mattermost/mattermost-cloud/internal/common"
 	"github.com/mattermost/mattermost-cloud/internal/metrics"
-	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
-	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
+	"github.com/mattermost/mattermost-cloud/internal/tools/cloud"
 	"github.com/mattermost/mattermost-cloud/internal/webhook"
 	"github.com/mattermost/mattermost-cloud/k8s"
 	"github.com/mattermost/mattermost-cloud/model"
@@ -28,11 +30,13 @@ const (
 // installationStore abstracts the database operations required to query installations.
 type installationStore interface {
 	GetClusters(clusterFilter *model.ClusterFilter) ([]*model.Cluster, error)
+	GetAnnotationsForClusters(filter *model.ClusterFilter) (map[string][]*model.Annotation, error)
 	GetCluster(id string) (*model.Cluster, error)
 	UpdateCluster(cluster *model.Cluster) error
 	clusterLockStore
 
 	GetInstallation(installationID string, includeGroupConfig, includeGroupConfigOverrides bool) (*model.Installation, error)
+	GetInstallations(filter *model.InstallationFilter, includeGroupConfig, includeGroupConfigOverrides bool) ([]*model.Installation, error)
 	GetUnlockedInstallationsPendingWork() ([]*model.Installation, error)
 	UpdateInstallation(installation *model.Installation) error
 	UpdateInstallationGroupSequence(installation *model.Installation) error
@@ -59,6 +63,10 @@ type installationStore interface {
 	installationBackupLockStore
 
 	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+	CreateEvent(event *model.Event) error
+	GetEvents(filter *model.EventFilter) ([]*model.Event, error)
 
 	model.InstallationDatabaseStoreInterface
 }
@@ -77,47 +85,57 @@ type installationProvisioner interface {
 type InstallationSupervisor struct {
 	store             installationStore
 	provisioner       installationProvisioner
-	aws               aws.AWS
+	cloud             cloud.Provider
 	instanceID        string
 	keepDatabaseData  bool
 	keepFilestoreData bool
 	scheduling        InstallationSupervisorSchedulingOptions
-	resourceUtil      *utils.ResourceUtil
+	scheduler         *common.InstallationScheduler
 	logger            log.FieldLogger
 	metrics           *metrics.CloudMetrics
 	forceCRUpgrade    bool
 }
 
+// installationMeasuredTransitions are the installation transitions whose
+// duration is recorded in metrics. Installation creation is measured
+// separately from the creation time of the installation.
+var installationMeasuredTransitions = []measuredTransition{
+	{"hibernation", model.InstallationStateHibernationRequested, model.InstallationStateHibernating},
+	{"wake-up", model.InstallationStateWakeUpRequested, model.InstallationStateStable},
+	{"deletion", model.InstallationStateDeletionRequested, model.InstallationStateDeleted},
+}
+
 // InstallationSupervisorSchedulingOptions are the various options that control
 // how installation scheduling occurs.
 type InstallationSupervisorSchedulingOptions struct {
-	balanceInstallations               bool
 	clusterResourceThreshold           int
 	clusterResourceThresholdScaleValue int
+	schedulingPolicy                   string
 }
 
 // NewInstallationSupervisor creates a new InstallationSupervisor.
 func NewInstallationSupervisor(
 	store installationStore,
 	installationProvisioner installationProvisioner,
-	aws aws.AWS,
+	cloudProvider cloud.Provider,
 	instanceID string,
 	keepDatabaseData,
 	keepFilestoreData bool,
 	scheduling InstallationSupervisorSchedulingOptions,
-	resourceUtil *utils.ResourceUtil,
 	logger log.FieldLogger,
 	metrics *metrics.CloudMetrics,
 	forceCRUpgrade bool) *InstallationSupervisor {
+	scheduler := common.NewInstallationScheduler(store, installationProvisioner, scheduling.SchedulerOptions(), logger)
+
 	return &InstallationSupervisor{
 		store:             store,
 		provisioner:       installationProvisioner,
-		aws:               aws,
+		cloud:             cloudProvider,
 		instanceID:        instanceID,
 		keepDatabaseData:  keepDatabaseData,
 		keepFilestoreData: keepFilestoreData,
 		scheduling:        scheduling,
-		resourceUtil:      resourceUtil,
+		scheduler:         scheduler,
 		logger:            logger,
 		metrics:           metrics,
 		forceCRUpgrade:    forceCRUpgrade,
@@ -125,11 +143,31 @@ func NewInstallationSupervisor(
 }
 
 // NewInstallationSupervisorSchedulingOptions creates a new InstallationSupervisorSchedulingOptions.
-func NewInstallationSupervisorSchedulingOptions(balanceInstallations bool, clusterResourceThreshold, clusterResourceThresholdScaleValue int) InstallationSupervisorSchedulingOptions {
+// When no scheduling policy is provided, installations are spread across
+// clusters if balancing is enabled and placed on the first fitting cluster
+// otherwise.
+func NewInstallationSupervisorSchedulingOptions(balanceInstallations bool, clusterResourceThreshold, clusterResourceThresholdScaleValue int, schedulingPolicy string) InstallationSupervisorSchedulingOptions {
+	if schedulingPolicy == "" {
+		schedulingPolicy = model.SchedulingPolicyFirstFit
+		if balanceInstallations {
+			schedulingPolicy = model.SchedulingPolicySpread
+		}
+	}
+
 	return InstallationSupervisorSchedulingOptions{
-		balanceInstallations:               balanceInstallations,
 		clusterResourceThreshold:           clusterResourceThreshold,
 		clusterResourceThresholdScaleValue: clusterResourceThresholdScaleValue,
+		schedulingPolicy:                   schedulingPolicy,
+	}
+}
+
+// SchedulerOptions returns the options of the installation scheduler used by
+// the supervisor.
+func (o InstallationSupervisorSchedulingOptions) SchedulerOptions() common.SchedulingOptions {
+	return common.SchedulingOptions{
+		DefaultPolicy:                      o.schedulingPolicy,
+		ClusterResourceThreshold:           o.clusterResourceThreshold,
+		ClusterResourceThresholdScaleValue: o.clusterResourceThresholdScaleValue,
 	}
 }
 
@@ -143,7 +181,7 @@ func (s *InstallationSupervisor) Do() error {
 	installations, err := s.store.GetUnlockedInstallationsPendingWork()
 	if err != nil {
 		s.logger.WithError(err).Warn("Failed to query for installation pending work")
-		return nil
+		return errors.Wrap(err, "failed to query for installation pending work")
 	}
 
 	for _, installation := range installations {
@@ -182,7 +220,8 @@ func (s *InstallationSupervisor) Supervise(installation *model.Installation) {
 
 	logger.Debugf("Supervising installation in state %s", installation.State)
 
-	newState := s.transitionInstallation(installation, s.instanceID, logger)
+	start := time.Now()
+	newState, stateErr := s.transitionInstallation(installation, s.instanceID, logger)
 
 	installation, err = s.store.GetInstallation(installation.ID, true, false)
 	if err != nil {
@@ -227,11 +266,22 @@ func (s *InstallationSupervisor) Supervise(installation *model.Installation) {
 	webhookPayload := &model.WebhookPayload{
 		Type:      model.TypeInstallation,
 		ID:        installation.ID,
+		OwnerID:   installation.OwnerID,
 		NewState:  installation.State,
 		OldState:  oldState,
 		Timestamp: time.Now().UnixNano(),
-		ExtraData: map[string]string{"DNS": installation.DNS, "Environment": s.aws.GetCloudEnvironmentName()},
+		ExtraData: map[string]string{"DNS": installation.DNS, "Environment": s.cloud.GetCloudEnvironmentName()},
 	}
+	observeTransition(s.store, s.metrics, installationMeasuredTransitions, webhookPayload, start, logger)
+
+	if installation.State != newState {
+		// The state was changed by the final group configuration check.
+		stateErr = nil
+	}
+	if installation.State == model.InstallationStateCreationNoCompatibleClusters {
+		stateErr = s.noCompatibleClustersError(installation, logger)
+	}
+	recordStateChangeEvent(s.store, webhookPayload, s.instanceID, stateErr, logger)
 	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
 	if err != nil {
 		logger.WithError(err).Error("Unable to process and send webhooks")
@@ -241,7 +291,7 @@ func (s *InstallationSupervisor) Supervise(installation *model.Installation) {
 }
 
 // transitionInstallation works with the given installation to transition it to a final state.
-func (s *InstallationSupervisor) transitionInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) string {
+func (s *InstallationSupervisor) transitionInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
 	switch installation.State {
 	case model.InstallationStateCreationRequested,
 		model.InstallationStateCreationNoCompatibleClusters:
@@ -283,11 +333,11 @@ func (s *InstallationSupervisor) transitionInstallation(installation *model.Inst
 
 	default:
 		logger.Warnf("Found installation pending work in unexpected state %s", installation.State)
-		return installation.State
+		return installation.State, nil
 	}
 }
 
-func (s *InstallationSupervisor) createInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) string {
+func (s *InstallationSupervisor) createInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
 	// Before starting, we check the installation and group sequence numbers and
 	// sync them if they are not already. This is used to check if the group
 	// configuration has changed during the creation process or not.
@@ -299,7 +349,7 @@ func (s *InstallationSupervisor) createInstallation(installation *model.Installa
 		err := s.store.UpdateInstallationGroupSequence(installation)
 		if err != nil {
 			logger.WithError(err).Errorf("Failed to set installation sequence to %d", *installation.GroupSequence)
-			return installation.State
+			return installation.State, nil
 		}
 	}
 
@@ -309,7 +359,7 @@ func (s *InstallationSupervisor) createInstallation(installation *model.Installa
 	})
 	if err != nil {
 		logger.WithError(err).Warn("Failed to find cluster installations")
-		return model.InstallationStateCreationRequested
+		return model.InstallationStateCreationRequested, nil
 	}
 
 	if len(clusterInstallations) > 0 {
@@ -317,31 +367,11 @@ func (s *InstallationSupervisor) createInstallation(installation *model.Installa
 		return s.preProvisionInstallation(installation, instanceID, logger)
 	}
 
-	clusterFilter := &model.ClusterFilter{
-		Paging: model.AllPagesNotDeleted(),
-	}
-
-	// Get only clusters that have all annotations present on the installation.
-	// Clusters can have additional annotations not present on the installation.
-	annotations, err := s.store.GetAnnotationsForInstallation(installation.ID)
+	logger.Infof("Scheduling installation with %s policy", s.scheduler.Policy(installation))
+	clusters, err := s.scheduler.PrioritizeClusters(installation)
 	if err != nil {
-		logger.WithError(err).Warn("Failed to get annotations for Installation")
-		return model.InstallationStateCreationRequested
-	}
-	if len(annotations) > 0 {
-		clusterFilter.Annotations = &model.AnnotationsFilter{MatchAllIDs: getAnnotationsIDs(annotations)}
-	}
-
-	// Proceed to requesting cluster installation creation on any available clusters.
-	clusters, err := s.store.GetClusters(clusterFilter)
-	if err != nil {
-		logger.WithError(err).Warn("Failed to query clusters")
-		return model.InstallationStateCreationRequested
-	}
-
-	if s.scheduling.balanceInstallations {
-		logger.Info("Attempting to schedule installation on the lowest-utilized cluster")
-		clusters = s.prioritizeLowerUtilizedClusters(clusters, installation, instanceID, logger)
+		logger.WithError(err).Warn("Failed to prioritize clusters")
+		return model.InstallationStateCreationRequested, nil
 	}
 
 	for _, cluster := range clusters {
@@ -354,65 +384,32 @@ func (s *InstallationSupervisor) createInstallation(installation *model.Installa
 	// TODO: Support creating a cluster on demand if no existing cluster meets the criteria.
 	logger.Warn("No compatible clusters available for installation scheduling")
 
-	return model.InstallationStateCreationNoCompatibleClusters
+	return model.InstallationStateCreationNoCompatibleClusters, nil
 }
 
-// prioritizeLowerUtilizedClusters attempts filter the given cluster list and
-// order it by lowest resource usage first. This should be considered best
-// effort.
-// Note the following:
-//   - This check is done without locking to avoid creating additional
-//     congestion.
-//   - Resource usage ordering is done by taking an average of CPU + memory
-//     percentages.
-//   - The returned list will generally be in order of lowest-to-highest
-//     resource usage, but the only guarantee is that the first cluster in the
-//     list will be the lowest at the time it was checked.
-//   - When scheduling an installation, all of the standard scheduling checks
-//     should be performed again under cluster lock.
-func (s *InstallationSupervisor) prioritizeLowerUtilizedClusters(clusters []*model.Cluster, installation *model.Installation, instanceID string, logger log.FieldLogger) []*model.Cluster {
-	lowestResourcePercent := 10000
-	var filteredPrioritizedClusters []*model.Cluster
-
-	for _, cluster := range clusters {
-		if !s.installationCanBeScheduledOnCluster(cluster, installation, logger) {
-			continue
-		}
+// noCompatibleClustersError explains why the installation could not be
+// scheduled on any cluster. The clusters are evaluated again, so the result
+// reflects their current state.
+func (s *InstallationSupervisor) noCompatibleClustersError(installation *model.Installation, logger log.FieldLogger) error {
+	result, err := s.scheduler.Schedule(installation, "")
+	if err != nil {
+		logger.WithError(err).Warn("Failed to evaluate installation scheduling")
+		return errors.Wrap(err, "failed to evaluate installation scheduling")
+	}
+	if len(result.Clusters) == 0 {
+		return errors.New("no clusters available")
+	}
 
-		clusterResources, err := s.provisioner.GetClusterResources(cluster, true)
-		if err != nil {
-			logger.WithError(err).Error("Failed to get cluster resources")
+	var reasons []string
+	for _, decision := range result.Clusters {
+		if decision.Eligible {
+			reasons = append(reasons, fmt.Sprintf("cluster %s: eligible, but could not accept the installation", decision.ClusterID))
 			continue
 		}
-		size, err := mmv1alpha1.GetClusterSize(installation.Size)
-		if err != nil {
-			logger.WithError(err).Error("Invalid cluster installation size")
-			continue
-		}
-
-		installationCPURequirement := size.CalculateCPUMilliRequirement(
-			installation.InternalDatabase(),
-			installation.InternalFilestore(),
-		)
-		installationMemRequirement := size.CalculateMemoryMilliRequirement(
-			installation.InternalDatabase(),
-			installation.InternalFilestore(),
-		)
-		cpuPercent := clusterResources.CalculateCPUPercentUsed(installationCPURequirement)
-		memoryPercent := clusterResources.CalculateMemoryPercentUsed(installationMemRequirement)
-		combinedPercent := (cpuPercent + memoryPercent) / 2
-		logger.Debugf("Cluster %s analyzed with %d%% expected resource usage", cluster.ID, combinedPercent)
-		if combinedPercent < lowestResourcePercent {
-			// This is the lowest utilized cluster so far so prepend.
-			filteredPrioritizedClusters = append([]*model.Cluster{cluster}, filteredPrioritizedClusters...)
-			lowestResourcePercent = combinedPercent
-		} else {
-			// Otherwise just append it to the end of the list.
-			filteredPrioritizedClusters = append(filteredPrioritizedClusters, cluster)
-		}
+		reasons = append(reasons, fmt.Sprintf("cluster %s: %s", decision.ClusterID, decision.Reason))
 	}
 
-	return filteredPrioritizedClusters
+	return errors.Errorf("no compatible clusters with %s scheduling policy (%s)", result.Policy, strings.Join(reasons, "; "))
 }
 
 // createClusterInstallation attempts to schedule a cluster installation onto the given cluster.
@@ -453,9 +450,7 @@ func (s *InstallationSupervisor) createClusterInstallation(cluster *model.Cluste
 	memoryPercent := clusterResources.CalculateMemoryPercentUsed(installationMemRequirement)
 
 	if cpuPercent > s.scheduling.clusterResourceThreshold || memoryPercent > s.scheduling.clusterResourceThreshold {
-		if s.scheduling.clusterResourceThresholdScaleValue == 0 ||
-			cluster.ProvisionerMetadataKops.NodeMinCount == cluster.ProvisionerMetadataKops.NodeMaxCount ||
-			cluster.State != model.ClusterStateStable {
+		if !s.scheduler.CanScaleCluster(cluster) {
 			logger.Debugf("Cluster %s would exceed the cluster load threshold (%d%%): CPU=%d%% (+%dm), Memory=%d%% (+%dMi)",
 				cluster.ID,
 				s.scheduling.clusterResourceThreshold,
@@ -475,35 +470,11 @@ func (s *InstallationSupervisor) createClusterInstallation(cluster *model.Cluste
 			newWorkerCount = cluster.ProvisionerMetadataKops.NodeMaxCount
 		}
 
-		cluster.State = model.ClusterStateResizeRequested
-		cluster.ProvisionerMetadataKops.ChangeRequest = &model.KopsMetadataRequestedState{
-			NodeMinCount: newWorkerCount,
-		}
-
-		logger.WithField("cluster", cluster.ID).Infof("Scaling cluster worker nodes from %d to %d (max=%d)",
-			cluster.ProvisionerMetadataKops.NodeMinCount,
-			cluster.ProvisionerMetadataKops.ChangeRequest.NodeMinCount,
-			cluster.ProvisionerMetadataKops.NodeMaxCount,
-		)
-		err = s.store.UpdateCluster(cluster)
+		err = requestClusterWorkerNodeCount(s.store, cluster, newWorkerCount, s.instanceID, s.cloud.GetCloudEnvironmentName(), logger)
 		if err != nil {
-			logger.WithError(err).Error("Failed to update cluster")
+			logger.WithError(err).Error("Failed to request cluster resize")
 			return nil
 		}
-
-		webhookPayload := &model.WebhookPayload{
-			Type:      model.TypeCluster,
-			ID:        cluster.ID,
-			NewState:  model.ClusterStateResizeRequested,
-			OldState:  model.ClusterStateStable,
-			Timestamp: time.Now().UnixNano(),
-			ExtraData: map[string]string{"Environment": s.aws.GetCloudEnvironmentName()},
-		}
-
-		err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
-		if err != nil {
-			logger.WithError(err).Error("Unable to process and send webhooks")
-		}
 	}
 
 	// The cluster can support the cluster installation.
@@ -524,11 +495,13 @@ func (s *InstallationSupervisor) createClusterInstallation(cluster *model.Cluste
 	webhookPayload := &model.WebhookPayload{
 		Type:      model.TypeClusterInstallation,
 		ID:        clusterInstallation.ID,
+		OwnerID:   installation.OwnerID,
 		NewState:  model.ClusterInstallationStateCreationRequested,
 		OldState:  "n/a",
 		Timestamp: time.Now().UnixNano(),
-		ExtraData: map[string]string{"Environment": s.aws.GetCloudEnvironmentName()},
+		ExtraData: map[string]string{"Environment": s.cloud.GetCloudEnvironmentName()},
 	}
+	recordStateChangeEvent(s.store, webhookPayload, s.instanceID, nil, logger)
 	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
 	if err != nil {
 		logger.WithError(err).Error("Unable to process and send webhooks")
@@ -543,67 +516,30 @@ func (s *InstallationSupervisor) createClusterInstallation(cluster *model.Cluste
 // scheduled on the given cluster in regards to configuration and state. This
 // does not include resource checks.
 func (s *InstallationSupervisor) installationCanBeScheduledOnCluster(cluster *model.Cluster, installation *model.Installation, logger log.FieldLogger) bool {
-	if cluster.State != model.ClusterStateStable {
-		logger.Debugf("Cluster %s is not stable (currently %s)", cluster.ID, cluster.State)
-		return false
-	}
-	if !cluster.AllowInstallations {
-		logger.Debugf("Cluster %s is set to not allow for new installation scheduling", cluster.ID)
-		return false
-	}
-
-	existingClusterInstallations, err := s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
-		Paging:    model.AllPagesNotDeleted(),
-		ClusterID: cluster.ID,
-	})
+	reason, err := s.scheduler.CheckCluster(cluster, installation)
 	if err != nil {
-		logger.WithError(err).Error("Failed to get existing cluster installations")
+		logger.WithError(err).Errorf("Failed to check if installation can be scheduled on cluster %s", cluster.ID)
 		return false
 	}
-
-	////////////////////////////////////////////////////////////////////////////
-	//                              MULTI-TENANCY                             //
-	////////////////////////////////////////////////////////////////////////////
-	// Current model:                                                         //
-	// - isolation=true  | 1 cluster installations                            //
-	// - isolation=false | X cluster installations, where "X" is as many as   //
-	//                     will fit with the given CPU and Memory threshold.  //
-	////////////////////////////////////////////////////////////////////////////
-	if installation.Affinity == model.InstallationAffinityIsolated {
-		if len(existingClusterInstallations) > 0 {
-			logger.Debugf("Cluster %s already has %d installations", cluster.ID, len(existingClusterInstallations))
-			return false
-		}
-	} else {
-		if len(existingClusterInstallations) == 1 {
-			// This should be the only scenario where we need to check if the
-			// cluster installation running requires isolation or not.
-			installation, err := s.store.GetInstallation(existingClusterInstallations[0].InstallationID, true, false)
-			if err != nil {
-				logger.WithError(err).Error("Failed to get existing installation")
-				return false
-			}
-			if installation.Affinity == model.InstallationAffinityIsolated {
-				logger.Debugf("Cluster %s already has an isolated installation %s", cluster.ID, installation.ID)
-				return false
-			}
-		}
+	if reason != "" {
+		logger.Debugf("Cluster %s cannot host the installation: %s", cluster.ID, reason)
+		return false
 	}
 
 	return true
 }
 
-func (s *InstallationSupervisor) preProvisionInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) string {
-	err := s.resourceUtil.GetDatabaseForInstallation(installation).Provision(s.store, logger)
+func (s *InstallationSupervisor) preProvisionInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
+	err := s.cloud.GetDatabaseForInstallation(installation).Provision(s.store, logger)
 	if err != nil {
 		logger.WithError(err).Error("Failed to provision installation database")
-		return model.InstallationStateCreationPreProvisioning
+		return model.InstallationStateCreationPreProvisioning, nil
 	}
 
-	err = s.resourceUtil.GetFilestore(installation).Provision(s.store, logger)
+	err = s.cloud.GetFilestore(installation).Provision(s.store, logger)
 	if err != nil {
 		logger.WithError(err).Error("Failed to provision installation filestore")
-		return model.InstallationStateCreationPreProvisioning
+		return model.InstallationStateCreationPreProvisioning, nil
 	}
 
 	logger.Info("Installation pre-provisioning complete")
@@ -611,16 +547,16 @@ func (s *InstallationSupervisor) preProvisionInstallation(installation *model.In
 	return s.configureInstallationDNS(installation, instanceID, logger)
 }
 
-func (s *InstallationSupervisor) waitForCreationStable(installation *model.Installation, instanceID string, logger log.FieldLogger) string {
+func (s *InstallationSupervisor) waitForCreationStable(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
 	// TODO: Check group config for changes.
 
 	stable, err := s.checkIfClusterInstallationsAreStable(installation, logger)
 	if err != nil {
 		logger.WithError(err).Error("Installation creation failed")
-		return model.InstallationStateCreationFailed
+		return model.InstallationStateCreationFailed, errors.Wrap(err, "installation creation failed")
 	}
 	if !stable {
-		return model.InstallationStateCreationInProgress
+		return model.InstallationStateCreationInProgress, nil
 	}
 
 	logger.Info("Created cluster installations are now stable")
@@ -628,14 +564,14 @@ func (s *InstallationSupervisor) waitForCreationStable(installation *model.Insta
 	return s.finalCreationTasks(installation, logger)
 }
 
-func (s *InstallationSupervisor) configureInstallationDNS(installation *model.Installation, instanceID string, logger log.FieldLogger) string {
+func (s *InstallationSupervisor) configureInstallationDNS(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
 	clusterInstallations, err := s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
 		InstallationID: installation.ID,
 		Paging:         model.AllPagesNotDeleted(),
 	})
 	if err != nil {
 		logger.WithError(err).Warn("Failed to find cluster installations")
-		return model.InstallationStateCreationDNS
+		return model.InstallationStateCreationDNS, nil
 	}
 
 	var endpoints []string
@@ -643,26 +579,26 @@ func (s *InstallationSupervisor) configureInstallationDNS(installation *model.In
 		cluster, err := s.store.GetCluster(clusterInstallation.ClusterID)
 		if err != nil {
 			logger.WithError(err).Warnf("Failed to query cluster %s", clusterInstallation.ClusterID)
-			return model.InstallationStateCreationDNS
+			return model.InstallationStateCreationDNS, nil
 		}
 		if cluster == nil {
 			logger.Errorf("Failed to find cluster %s", clusterInstallation.ClusterID)
-			return failedClusterInstallationState(clusterInstallation.State)
+			return failedClusterInstallationState(clusterInstallation.State), errors.Errorf("failed to find cluster %s", clusterInstallation.ClusterID)
 		}
 
 		endpoint, err := s.provisioner.GetPublicLoadBalancerEndpoint(cluster, "nginx")
 		if err != nil {
 			logger.WithError(err).Error("Couldn't get the load balancer endpoint (nginx) for Cluster Installation")
-			return model.InstallationStateCreationDNS
+			return model.InstallationStateCreationDNS, nil
 		}
 
 		endpoints = append(endpoints, endpoint)
 	}
 
-	err = s.aws.CreatePublicCNAME(installation.DNS, endpoints, logger)
+	err = s.cloud.CreatePublicCNAME(installation.DNS, endpoints, logger)
 	if err != nil {
 		logger.WithError(err).Error("Failed to create DNS CNAME record")
-		return model.InstallationStateCreationDNS
+		return model.InstallationStateCreationDNS, nil
 	}
 
 	logger.Infof("Successfully configured DNS %s", installation.DNS)
@@ -670,7 +606,7 @@ func (s *InstallationSupervisor) configureInstallationDNS(installation *model.In
 	return s.waitForCreationStable(installation, instanceID, logger)
 }
 
-func (s *InstallationSupervisor) updateInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) string {
+func (s *InstallationSupervisor) updateInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
 	// Before starting, we check the installation and group sequence numbers and
 	// sync them if they are not already. This is used to check if the group
 	// configuration has changed during the upgrade process or not.
@@ -682,7 +618,7 @@ func (s *InstallationSupervisor) updateInstallation(installation *model.Installa
 		err := s.store.UpdateInstallationGroupSequence(installation)
 		if err != nil {
 			logger.WithError(err).Errorf("Failed to set installation sequence to %d", *installation.GroupSequence)
-			return installation.State
+			return installation.State, nil
 		}
 	}
 
@@ -693,7 +629,7 @@ func (s *InstallationSupervisor) updateInstallation(installation *model.Installa
 		err := s.store.UpdateInstallationCRVersion(installation.ID, latestCRVersion)
 		if err != nil {
 			logger.WithError(err).Error("Failed to update installation CRVersion")
-			return installation.State
+			return installation.State, nil
 		}
 	}
 
@@ -703,7 +639,7 @@ func (s *InstallationSupervisor) updateInstallation(installation *model.Installa
 	})
 	if err != nil {
 		logger.WithError(err).Warn("Failed to find cluster installations")
-		return installation.State
+		return installation.State, nil
 	}
 
 	clusterInstallationIDs := getClusterInstallationIDs(clusterInstallations)
@@ -711,7 +647,7 @@ func (s *InstallationSupervisor) updateInstallation(installation *model.Installa
 		clusterInstallationLocks := newClusterInstallationLocks(clusterInstallationIDs, instanceID, s.store, logger)
 		if !clusterInstallationLocks.TryLock() {
 			logger.Debugf("Failed to lock %d cluster installations", len(clusterInstallations))
-			return installation.State
+			return installation.State, nil
 		}
 		defer clusterInstallationLocks.Unlock()
 
@@ -722,7 +658,7 @@ func (s *InstallationSupervisor) updateInstallation(installation *model.Installa
 		})
 		if err != nil {
 			logger.WithError(err).Warnf("Failed to fetch %d cluster installations by ids", len(clusterInstallations))
-			return installation.State
+			return installation.State, nil
 		}
 
 		if len(clusterInstallations) != len(clusterInstallationIDs) {
@@ -734,29 +670,29 @@ func (s *InstallationSupervisor) updateInstallation(installation *model.Installa
 		cluster, err := s.store.GetCluster(clusterInstallation.ClusterID)
 		if err != nil {
 			logger.WithError(err).Warnf("Failed to query cluster %s", clusterInstallation.ClusterID)
-			return clusterInstallation.State
+			return clusterInstallation.State, nil
 		}
 		if cluster == nil {
 			logger.Errorf("Failed to find cluster %s", clusterInstallation.ClusterID)
-			return failedClusterInstallationState(clusterInstallation.State)
+			return failedClusterInstallationState(clusterInstallation.State), errors.Errorf("failed to find cluster %s", clusterInstallation.ClusterID)
 		}
 
 		isReady, err := s.provisioner.ClusterInstallationProvisioner(installation.CRVersion).
 			EnsureCRMigrated(cluster, clusterInstallation)
 		if err != nil {
 			logger.WithError(err).Error("Failed to migrate cluster installation CR")
-			return installation.State
+			return installation.State, nil
 		}
 		if !isReady {
 			logger.Info("Cluster installation CR migration not finished")
-			return installation.State
+			return installation.State, nil
 		}
 
 		err = s.provisioner.ClusterInstallationProvisioner(installation.CRVersion).
 			UpdateClusterInstallation(cluster, installation, clusterInstallation)
 		if err != nil {
 			logger.WithError(err).Error("Failed to update cluster installation")
-			return installation.State
+			return installation.State, nil
 		}
 
 		if clusterInstallation.State != model.ClusterInstallationStateReconciling {
@@ -765,17 +701,19 @@ func (s *InstallationSupervisor) updateInstallation(installation *model.Installa
 			err = s.store.UpdateClusterInstallation(clusterInstallation)
 			if err != nil {
 				logger.Errorf("Failed to change cluster installation state to %s", model.ClusterInstallationStateReconciling)
-				return installation.State
+				return installation.State, nil
 			}
 
 			webhookPayload := &model.WebhookPayload{
 				Type:      model.TypeClusterInstallation,
 				ID:        clusterInstallation.ID,
+				OwnerID:   installation.OwnerID,
 				NewState:  clusterInstallation.State,
 				OldState:  oldState,
 				Timestamp: time.Now().UnixNano(),
-				ExtraData: map[string]string{"Environment": s.aws.GetCloudEnvironmentName()},
+				ExtraData: map[string]string{"Environment": s.cloud.GetCloudEnvironmentName()},
 			}
+			recordStateChangeEvent(s.store, webhookPayload, s.instanceID, nil, logger)
 			err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
 			if err != nil {
 				logger.WithError(err).Error("Unable to process and send webhooks")
@@ -788,32 +726,32 @@ func (s *InstallationSupervisor) updateInstallation(installation *model.Installa
 	return s.waitForUpdateStable(installation, instanceID, logger)
 }
 
-func (s *InstallationSupervisor) waitForUpdateStable(installation *model.Installation, instanceID string, logger log.FieldLogger) string {
+func (s *InstallationSupervisor) waitForUpdateStable(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
 	// If the installation belongs to a group that has been updated, we requeue
 	// the installation update.
 	if !installation.InstallationSequenceMatchesMergedGroupSequence() {
 		logger.Warnf("The installation's group configuration has changed; moving installation back to %s", model.InstallationStateUpdateRequested)
-		return model.InstallationStateUpdateRequested
+		return model.InstallationStateUpdateRequested, nil
 	}
 
 	stable, err := s.checkIfClusterInstallationsAreStable(installation, logger)
 	if err != nil {
 		logger.WithError(err).Error("Installation update failed")
-		return model.InstallationStateUpdateFailed
+		return model.InstallationStateUpdateFailed, errors.Wrap(err, "installation update failed")
 	}
 	if !stable {
-		return model.InstallationStateUpdateInProgress
+		return model.InstallationStateUpdateInProgress, nil
 	}
 
-	err = s.aws.UpdatePublicRecordIDForCNAME(installation.DNS, installation.DNS, logger)
+	err = s.cloud.UpdatePublicRecordIDForCNAME(installation.DNS, installation.DNS, logger)
 	if err != nil {
 		logger.WithError(err).Warn("Failed to update the installation route53 record to the standard ID value")
-		return installation.State
+		return installation.State, nil
 	}
 
 	logger.Info("Finished updating installation")
 
-	return model.InstallationStateStable
+	return model.InstallationStateStable, nil
 }
 
 // Unused stub function
@@ -854,17 +792,17 @@ func (s *InstallationSupervisor) verifyClusterInstallationResourcesMatchInstalla
 	return true, nil
 }
 
-func (s *InstallationSupervisor) hibernateInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) string {
-	err := s.aws.UpdatePublicRecordIDForCNAME(installation.DNS, aws.HibernatingInstallationResourceRecordIDPrefix+installation.DNS, logger)
+func (s *InstallationSupervisor) hibernateInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
+	err := s.cloud.UpdatePublicRecordIDForCNAME(installation.DNS, cloud.HibernatingInstallationResourceRecordIDPrefix+installation.DNS, logger)
 	if err != nil {
 		logger.WithError(err).Warn("Failed to update the installation route53 record with hibernation prefix")
-		return installation.State
+		return installation.State, nil
 	}
 
-	err = s.resourceUtil.GetDatabaseForInstallation(installation).RefreshResourceMetadata(s.store, logger)
+	err = s.cloud.GetDatabaseForInstallation(installation).RefreshResourceMetadata(s.store, logger)
 	if err != nil {
 		logger.WithError(err).Warn("Failed to update database resource metadata")
-		return installation.State
+		return installation.State, nil
 	}
 
 	clusterInstallations, err := s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
@@ -873,12 +811,12 @@ func (s *InstallationSupervisor) hibernateInstallation(installation *model.Insta
 	})
 	if err != nil {
 		logger.WithError(err).Warn("Failed to find cluster installations")
-		return installation.State
+		return installation.State, nil
 	}
 
 	if len(clusterInstallations) == 0 {
 		logger.Warn("Cluster installation list contained no results")
-		return installation.State
+		return installation.State, nil
 	}
 
 	clusterInstallationIDs := getClusterInstallationIDs(clusterInstallations)
@@ -886,7 +824,7 @@ func (s *InstallationSupervisor) hibernateInstallation(installation *model.Insta
 	clusterInstallationLocks := newClusterInstallationLocks(clusterInstallationIDs, instanceID, s.store, logger)
 	if !clusterInstallationLocks.TryLock() {
 		logger.Debugf("Failed to lock %d cluster installations", len(clusterInstallations))
-		return installation.State
+		return installation.State, nil
 	}
 	defer clusterInstallationLocks.Unlock()
 
@@ -897,7 +835,7 @@ func (s *InstallationSupervisor) hibernateInstallation(installation *model.Insta
 	})
 	if err != nil {
 		logger.WithError(err).Warnf("Failed to fetch %d cluster installations by ids", len(clusterInstallations))
-		return installation.State
+		return installation.State, nil
 	}
 
 	if len(clusterInstallations) != len(clusterInstallationIDs) {
@@ -908,25 +846,25 @@ func (s *InstallationSupervisor) hibernateInstallation(installation *model.Insta
 		cluster, err := s.store.GetCluster(clusterInstallation.ClusterID)
 		if err != nil {
 			logger.WithError(err).Warnf("Failed to query cluster %s", clusterInstallation.ClusterID)
-			return clusterInstallation.State
+			return clusterInstallation.State, nil
 		}
 		if cluster == nil {
 			logger.Errorf("Failed to find cluster %s", clusterInstallation.ClusterID)
-			return failedClusterInstallationState(clusterInstallation.State)
+			return failedClusterInstallationState(clusterInstallation.State), errors.Errorf("failed to find cluster %s", clusterInstallation.ClusterID)
 		}
 
 		err = s.provisioner.ClusterInstallationProvisioner(installation.CRVersion).
 			HibernateClusterInstallation(cluster, installation, clusterInstallation)
 		if err != nil {
 			logger.WithError(err).Error("Failed to update cluster installation")
-			return installation.State
+			return installation.State, nil
 		}
 
 		clusterInstallation.State = model.ClusterInstallationStateReconciling
 		err = s.store.UpdateClusterInstallation(clusterInstallation)
 		if err != nil {
 			logger.WithError(err).Errorf("Failed to change cluster installation state to %s", model.ClusterInstallationStateReconciling)
-			return installation.State
+			return installation.State, nil
 		}
 	}
 
@@ -935,41 +873,41 @@ func (s *InstallationSupervisor) hibernateInstallation(installation *model.Insta
 	return s.waitForHibernationStable(installation, instanceID, logger)
 }
 
-func (s *InstallationSupervisor) waitForHibernationStable(installation *model.Installation, instanceID string, logger log.FieldLogger) string {
+func (s *InstallationSupervisor) waitForHibernationStable(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
 	stable, err := s.checkIfClusterInstallationsAreStable(installation, logger)
 	if err != nil {
 		// TODO: there is no real failure state for hibernating so handle this
 		// better in the future.
 		logger.WithError(err).Warn("Installation hibernation failed")
-		return model.InstallationStateHibernationInProgress
+		return model.InstallationStateHibernationInProgress, nil
 	}
 	if !stable {
-		return model.InstallationStateHibernationInProgress
+		return model.InstallationStateHibernationInProgress, nil
 	}
 
 	logger.Info("Finished hibernating installation")
 
-	return model.InstallationStateHibernating
+	return model.InstallationStateHibernating, nil
 }
 
-func (s *InstallationSupervisor) wakeUpInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) string {
-	err := s.resourceUtil.GetDatabaseForInstallation(installation).RefreshResourceMetadata(s.store, logger)
+func (s *InstallationSupervisor) wakeUpInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
+	err := s.cloud.GetDatabaseForInstallation(installation).RefreshResourceMetadata(s.store, logger)
 	if err != nil {
 		logger.WithError(err).Warn("Failed to update database resource metadata")
-		return installation.State
+		return installation.State, nil
 	}
 
 	return s.updateInstallation(installation, instanceID, logger)
 }
 
-func (s *InstallationSupervisor) deleteInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) string {
+func (s *InstallationSupervisor) deleteInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
 	clusterInstallations, err := s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
 		Paging:         model.AllPagesWithDeleted(),
 		InstallationID: installation.ID,
 	})
 	if err != nil {
 		logger.WithError(err).Warn("Failed to find cluster installations")
-		return installation.State
+		return installation.State, nil
 	}
 
 	clusterInstallationIDs := getClusterInstallationIDs(clusterInstallations)
@@ -977,7 +915,7 @@ func (s *InstallationSupervisor) deleteInstallation(installation *model.Installa
 		clusterInstallationLocks := newClusterInstallationLocks(clusterInstallationIDs, instanceID, s.store, logger)
 		if !clusterInstallationLocks.TryLock() {
 			logger.Debugf("Failed to lock %d cluster installations", len(clusterInstallations))
-			return installation.State
+			return installation.State, nil
 		}
 		defer clusterInstallationLocks.Unlock()
 
@@ -988,7 +926,7 @@ func (s *InstallationSupervisor) deleteInstallation(installation *model.Installa
 		})
 		if err != nil {
 			logger.WithError(err).Warnf("Failed to fetch %d cluster installations by ids", len(clusterInstallations))
-			return installation.State
+			return installation.State, nil
 		}
 
 		if len(clusterInstallations) != len(clusterInstallationIDs) {
@@ -1028,14 +966,14 @@ func (s *InstallationSupervisor) deleteInstallation(installation *model.Installa
 
 		default:
 			logger.Errorf("Cannot delete installation with cluster installation in state %s", clusterInstallation.State)
-			return model.InstallationStateDeletionFailed
+			return model.InstallationStateDeletionFailed, errors.Errorf("cannot delete installation with cluster installation in state %s", clusterInstallation.State)
 		}
 
 		clusterInstallation.State = model.ClusterInstallationStateDeletionRequested
 		err = s.store.UpdateClusterInstallation(clusterInstallation)
 		if err != nil {
 			logger.WithError(err).Warnf("Failed to mark cluster installation %s for deletion", clusterInstallation.ID)
-			return installation.State
+			return installation.State, nil
 		}
 
 		deletingClusterInstallations++
@@ -1051,21 +989,21 @@ func (s *InstallationSupervisor) deleteInstallation(installation *model.Installa
 
 	if failedClusterInstallations > 0 {
 		logger.Infof("Found %d failed cluster installations", failedClusterInstallations)
-		return model.InstallationStateDeletionFailed
+		return model.InstallationStateDeletionFailed, errors.Errorf("found %d failed cluster installations", failedClusterInstallations)
 	}
 
 	if deletedClusterInstallations < len(clusterInstallations) {
-		return model.InstallationStateDeletionInProgress
+		return model.InstallationStateDeletionInProgress, nil
 	}
 
 	return s.finalDeletionCleanup(installation, instanceID, logger)
 }
 
-func (s *InstallationSupervisor) finalDeletionCleanup(installation *model.Installation, instanceID string, logger log.FieldLogger) string {
-	err := s.aws.DeletePublicCNAME(installation.DNS, logger)
+func (s *InstallationSupervisor) finalDeletionCleanup(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
+	err := s.cloud.DeletePublicCNAME(installation.DNS, logger)
 	if err != nil {
 		logger.WithError(err).Error("Failed to delete installation DNS")
-		return model.InstallationStateDeletionFinalCleanup
+		return model.InstallationStateDeletionFinalCleanup, nil
 	}
 
 	// Backups are stored in Installations file store, therefore if file store is deleted
@@ -1074,35 +1012,35 @@ func (s *InstallationSupervisor) finalDeletionCleanup(installation *model.Instal
 		finished, err := s.deleteBackups(installation, instanceID, logger)
 		if err != nil {
 			logger.WithError(err).Error("Failed to delete backups")
-			return model.InstallationStateDeletionFinalCleanup
+			return model.InstallationStateDeletionFinalCleanup, nil
 		}
 		if !finished {
 			logger.Info("Installation backups deletion in progress")
-			return model.InstallationStateDeletionFinalCleanup
+			return model.InstallationStateDeletionFinalCleanup, nil
 		}
 	}
 
-	err = s.resourceUtil.GetDatabaseForInstallation(installation).Teardown(s.store, s.keepDatabaseData, logger)
+	err = s.cloud.GetDatabaseForInstallation(installation).Teardown(s.store, s.keepDatabaseData, logger)
 	if err != nil {
 		logger.WithError(err).Error("Failed to delete database")
-		return model.InstallationStateDeletionFinalCleanup
+		return model.InstallationStateDeletionFinalCleanup, nil
 	}
 
-	err = s.resourceUtil.GetFilestore(installation).Teardown(s.keepFilestoreData, s.store, logger)
+	err = s.cloud.GetFilestore(installation).Teardown(s.keepFilestoreData, s.store, logger)
 	if err != nil {
 		logger.WithError(err).Error("Failed to delete filestore")
-		return model.InstallationStateDeletionFinalCleanup
+		return model.InstallationStateDeletionFinalCleanup, nil
 	}
 
 	err = s.store.DeleteInstallation(installation.ID)
 	if err != nil {
 		logger.WithError(err).Warn("Failed to mark installation as deleted")
-		return model.InstallationStateDeletionFinalCleanup
+		return model.InstallationStateDeletionFinalCleanup, nil
 	}
 
 	logger.Info("Finished deleting installation")
 
-	return model.InstallationStateDeleted
+	return model.InstallationStateDeleted, nil
 }
 
 func (s *InstallationSupervisor) deleteBackups(installation *model.Installation, instanceID string, logger log.FieldLogger) (bool, error) {
@@ -1188,10 +1126,10 @@ func (s *InstallationSupervisor) deleteBackups(installation *model.Installation,
 	return true, nil
 }
 
-func (s *InstallationSupervisor) finalCreationTasks(installation *model.Installation, logger log.FieldLogger) string {
+func (s *InstallationSupervisor) finalCreationTasks(installation *model.Installation, logger log.FieldLogger) (string, error) {
 	logger.Info("Finished final creation tasks")
 	s.metrics.InstallationCreationDurationHist.Observe(elapsedTimeInSeconds(installation.CreateAt))
-	return model.InstallationStateStable
+	return model.InstallationStateStable, nil
 }
 
 // Helper funcs
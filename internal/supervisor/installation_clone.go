@@ -0,0 +1,409 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/common"
+	"github.com/mattermost/mattermost-cloud/internal/tools/cloud"
+	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
+	"github.com/mattermost/mattermost-cloud/internal/webhook"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// installationCloneStore abstracts the database operations required by the supervisor.
+type installationCloneStore interface {
+	GetUnlockedInstallationCloneOperationsPendingWork() ([]*model.InstallationCloneOperation, error)
+	GetInstallationCloneOperation(id string) (*model.InstallationCloneOperation, error)
+	UpdateInstallationCloneOperationState(cloneOp *model.InstallationCloneOperation) error
+	UpdateInstallationCloneOperation(cloneOp *model.InstallationCloneOperation) error
+	installationCloneOperationLockStore
+
+	TriggerInstallationRestoration(installation *model.Installation, backup *model.InstallationBackup) (*model.InstallationDBRestorationOperation, error)
+	GetInstallationDBRestorationOperation(id string) (*model.InstallationDBRestorationOperation, error)
+
+	IsInstallationBackupRunning(installationID string) (bool, error)
+	CreateInstallationBackup(backup *model.InstallationBackup) error
+	GetInstallationBackup(id string) (*model.InstallationBackup, error)
+
+	GetInstallation(installationID string, includeGroupConfig, includeGroupConfigOverrides bool) (*model.Installation, error)
+	UpdateInstallation(installation *model.Installation) error
+	installationLockStore
+
+	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+	CreateEvent(event *model.Event) error
+}
+
+// InstallationCloneSupervisor finds pending work and effects the required changes.
+//
+// The degree of parallelism is controlled by a weighted semaphore, intended to be shared with
+// other clients needing to coordinate background jobs.
+type InstallationCloneSupervisor struct {
+	store       installationCloneStore
+	cloud       cloud.Provider
+	instanceID  string
+	environment string
+	logger      log.FieldLogger
+}
+
+// NewInstallationCloneSupervisor creates a new InstallationCloneSupervisor.
+func NewInstallationCloneSupervisor(
+	store installationCloneStore,
+	cloud cloud.Provider,
+	instanceID string,
+	logger log.FieldLogger) *InstallationCloneSupervisor {
+	return &InstallationCloneSupervisor{
+		store:       store,
+		cloud:       cloud,
+		instanceID:  instanceID,
+		environment: cloud.GetCloudEnvironmentName(),
+		logger:      logger,
+	}
+}
+
+// Shutdown performs graceful shutdown tasks for the supervisor.
+func (s *InstallationCloneSupervisor) Shutdown() {
+	s.logger.Debug("Shutting down installation clone supervisor")
+}
+
+// Do looks for work to be done on any pending clone operations and attempts to schedule the required work.
+func (s *InstallationCloneSupervisor) Do() error {
+	cloneOperations, err := s.store.GetUnlockedInstallationCloneOperationsPendingWork()
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to query for pending work")
+		return errors.Wrap(err, "failed to query for pending work")
+	}
+
+	for _, cloneOp := range cloneOperations {
+		s.Supervise(cloneOp)
+	}
+
+	return nil
+}
+
+// Supervise schedules the required work on the given clone operation.
+func (s *InstallationCloneSupervisor) Supervise(cloneOp *model.InstallationCloneOperation) {
+	logger := s.logger.WithFields(log.Fields{
+		"cloneOperation": cloneOp.ID,
+	})
+
+	lock := newInstallationCloneOperationLock(cloneOp.ID, s.instanceID, s.store, logger)
+	if !lock.TryLock() {
+		return
+	}
+	defer lock.Unlock()
+
+	// Before working on the clone operation, it is crucial that we ensure that it
+	// was not updated to a new state by another provisioning server.
+	originalState := cloneOp.State
+	cloneOp, err := s.store.GetInstallationCloneOperation(cloneOp.ID)
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to get refreshed clone operation")
+		return
+	}
+	if cloneOp.State != originalState {
+		logger.WithField("oldCloneState", originalState).
+			WithField("newCloneState", cloneOp.State).
+			Warn("Another provisioner has worked on this clone operation; skipping...")
+		return
+	}
+
+	logger.Debugf("Supervising clone operation in state %s", cloneOp.State)
+
+	newState, stateErr := s.transitionClone(cloneOp, s.instanceID, logger)
+
+	cloneOp, err = s.store.GetInstallationCloneOperation(cloneOp.ID)
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to get clone operation and thus persist state %s", newState)
+		return
+	}
+
+	if cloneOp.State == newState {
+		return
+	}
+
+	oldState := cloneOp.State
+	cloneOp.State = newState
+
+	err = s.store.UpdateInstallationCloneOperationState(cloneOp)
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to set clone operation state to %s", newState)
+		return
+	}
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeInstallationClone,
+		ID:        cloneOp.ID,
+		OwnerID:   installationOwnerID(s.store, cloneOp.InstallationID, logger),
+		NewState:  string(cloneOp.State),
+		OldState:  string(oldState),
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{
+			"Installation":      cloneOp.InstallationID,
+			"CloneInstallation": cloneOp.CloneInstallationID,
+			"Environment":       s.environment,
+		},
+	}
+	recordStateChangeEvent(s.store, webhookPayload, s.instanceID, stateErr, logger)
+	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	logger.Debugf("Transitioned clone operation from %s to %s", oldState, cloneOp.State)
+}
+
+// transitionClone works with the given clone operation to transition it to a final state.
+func (s *InstallationCloneSupervisor) transitionClone(cloneOp *model.InstallationCloneOperation, instanceID string, logger log.FieldLogger) (model.InstallationCloneOperationState, error) {
+	switch cloneOp.State {
+	case model.InstallationCloneStateRequested:
+		return s.triggerSourceBackup(cloneOp, instanceID, logger)
+	case model.InstallationCloneStateBackupInProgress:
+		return s.waitForSourceBackup(cloneOp, logger)
+	case model.InstallationCloneStateCreationInProgress:
+		return s.waitForCloneCreation(cloneOp, instanceID, logger)
+	case model.InstallationCloneStateHibernationInProgress:
+		return s.waitForCloneHibernation(cloneOp, instanceID, logger)
+	case model.InstallationCloneStateRestorationInProgress:
+		return s.waitForCloneRestoration(cloneOp, instanceID, logger)
+	case model.InstallationCloneStateWakeUpInProgress:
+		return s.waitForCloneWakeUp(cloneOp, logger)
+	case model.InstallationCloneStateFailing:
+		return s.failClone(cloneOp, logger)
+	default:
+		logger.Warnf("Found clone operation pending work in unexpected state %s", cloneOp.State)
+		return cloneOp.State, nil
+	}
+}
+
+func (s *InstallationCloneSupervisor) triggerSourceBackup(cloneOp *model.InstallationCloneOperation, instanceID string, logger log.FieldLogger) (model.InstallationCloneOperationState, error) {
+	installation, lock, err := getAndLockInstallation(s.store, cloneOp.InstallationID, instanceID, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get and lock source installation")
+		return cloneOp.State, nil
+	}
+	defer lock.Unlock()
+
+	backup, err := common.TriggerInstallationBackup(s.store, installation, s.environment, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to trigger source installation backup")
+		if common.ErrToStatus(err) == http.StatusBadRequest {
+			// The source installation cannot be backed up in its current
+			// state, so retrying would leave the operation requested forever.
+			return model.InstallationCloneStateFailing, errors.Wrap(err, "failed to trigger source installation backup")
+		}
+		return cloneOp.State, nil
+	}
+
+	cloneOp.BackupID = backup.ID
+	err = s.store.UpdateInstallationCloneOperation(cloneOp)
+	if err != nil {
+		logger.WithError(err).Error("Failed to set backup ID for clone operation")
+		return cloneOp.State, nil
+	}
+
+	return model.InstallationCloneStateBackupInProgress, nil
+}
+
+func (s *InstallationCloneSupervisor) waitForSourceBackup(cloneOp *model.InstallationCloneOperation, logger log.FieldLogger) (model.InstallationCloneOperationState, error) {
+	backup, err := s.store.GetInstallationBackup(cloneOp.BackupID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get installation backup")
+		return cloneOp.State, nil
+	}
+	if backup == nil {
+		logger.Error("Backup for clone operation not found")
+		return model.InstallationCloneStateFailing, errors.Errorf("backup %s for clone operation not found", cloneOp.BackupID)
+	}
+
+	switch backup.State {
+	case model.InstallationBackupStateBackupSucceeded:
+		logger.Info("Backup for clone operation finished successfully")
+		return model.InstallationCloneStateCreationInProgress, nil
+	case model.InstallationBackupStateBackupFailed:
+		logger.Error("Backup for clone operation failed")
+		return model.InstallationCloneStateFailing, errors.Errorf("backup %s for clone operation failed", backup.ID)
+	case model.InstallationBackupStateBackupInProgress, model.InstallationBackupStateBackupRequested:
+		logger.Debug("Backup for clone operation in progress")
+		return cloneOp.State, nil
+	default:
+		logger.Errorf("Unexpected state of installation backup for clone operation: %q", backup.State)
+		return cloneOp.State, nil
+	}
+}
+
+func (s *InstallationCloneSupervisor) waitForCloneCreation(cloneOp *model.InstallationCloneOperation, instanceID string, logger log.FieldLogger) (model.InstallationCloneOperationState, error) {
+	clone, err := s.store.GetInstallation(cloneOp.CloneInstallationID, false, false)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get clone installation")
+		return cloneOp.State, nil
+	}
+	if clone == nil {
+		logger.Error("Clone installation not found")
+		return model.InstallationCloneStateFailing, errors.Errorf("clone installation %s not found", cloneOp.CloneInstallationID)
+	}
+
+	switch clone.State {
+	case model.InstallationStateStable:
+		logger.Info("Clone installation created, requesting hibernation before restoration")
+		return s.transitionCloneInstallation(cloneOp, model.InstallationStateHibernationRequested, model.InstallationCloneStateHibernationInProgress, instanceID, logger)
+	case model.InstallationStateCreationFailed,
+		model.InstallationStateDeletionRequested,
+		model.InstallationStateDeletionInProgress,
+		model.InstallationStateDeletionFinalCleanup,
+		model.InstallationStateDeleted:
+		logger.Errorf("Clone installation cannot be created, it is in state %q", clone.State)
+		return model.InstallationCloneStateFailing, errors.Errorf("clone installation cannot be created, it is in state %q", clone.State)
+	default:
+		logger.Debugf("Clone installation creation in progress, state is %q", clone.State)
+		return cloneOp.State, nil
+	}
+}
+
+func (s *InstallationCloneSupervisor) waitForCloneHibernation(cloneOp *model.InstallationCloneOperation, instanceID string, logger log.FieldLogger) (model.InstallationCloneOperationState, error) {
+	clone, lock, err := getAndLockInstallation(s.store, cloneOp.CloneInstallationID, instanceID, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get and lock clone installation")
+		return cloneOp.State, nil
+	}
+	defer lock.Unlock()
+
+	if clone.State != model.InstallationStateHibernating {
+		logger.Debugf("Clone installation hibernation in progress, state is %q", clone.State)
+		return cloneOp.State, nil
+	}
+
+	backup, err := s.store.GetInstallationBackup(cloneOp.BackupID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get installation backup")
+		return cloneOp.State, nil
+	}
+	if backup == nil {
+		logger.Error("Backup for clone operation not found")
+		return model.InstallationCloneStateFailing, errors.Errorf("backup %s for clone operation not found", cloneOp.BackupID)
+	}
+
+	dbRestoration, err := common.TriggerInstallationDBRestoration(s.store, clone, backup, s.environment, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to trigger clone installation db restoration")
+		return cloneOp.State, nil
+	}
+
+	cloneOp.InstallationDBRestorationOperationID = dbRestoration.ID
+	err = s.store.UpdateInstallationCloneOperation(cloneOp)
+	if err != nil {
+		logger.WithError(err).Error("Failed to set restoration operation ID for clone operation")
+		return cloneOp.State, nil
+	}
+
+	return model.InstallationCloneStateRestorationInProgress, nil
+}
+
+func (s *InstallationCloneSupervisor) waitForCloneRestoration(cloneOp *model.InstallationCloneOperation, instanceID string, logger log.FieldLogger) (model.InstallationCloneOperationState, error) {
+	restoration, err := s.store.GetInstallationDBRestorationOperation(cloneOp.InstallationDBRestorationOperationID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get installation restoration")
+		return cloneOp.State, nil
+	}
+	if restoration == nil {
+		logger.Error("Restoration for clone operation not found")
+		return model.InstallationCloneStateFailing, errors.Errorf("restoration %s for clone operation not found", cloneOp.InstallationDBRestorationOperationID)
+	}
+
+	switch restoration.State {
+	case model.InstallationDBRestorationStateSucceeded:
+		logger.Info("Restoration for clone operation finished successfully, waking up clone installation")
+		return s.transitionCloneInstallation(cloneOp, model.InstallationStateWakeUpRequested, model.InstallationCloneStateWakeUpInProgress, instanceID, logger)
+	case model.InstallationDBRestorationStateFailed, model.InstallationDBRestorationStateInvalid:
+		logger.Error("Restoration for clone operation failed or is invalid")
+		return model.InstallationCloneStateFailing, errors.Errorf("restoration %s for clone operation is %s", restoration.ID, restoration.State)
+	default:
+		logger.Debug("Restoration for clone operation in progress")
+		return cloneOp.State, nil
+	}
+}
+
+func (s *InstallationCloneSupervisor) waitForCloneWakeUp(cloneOp *model.InstallationCloneOperation, logger log.FieldLogger) (model.InstallationCloneOperationState, error) {
+	clone, err := s.store.GetInstallation(cloneOp.CloneInstallationID, false, false)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get clone installation")
+		return cloneOp.State, nil
+	}
+	if clone == nil {
+		logger.Error("Clone installation not found")
+		return model.InstallationCloneStateFailing, errors.Errorf("clone installation %s not found", cloneOp.CloneInstallationID)
+	}
+
+	if clone.State != model.InstallationStateStable {
+		logger.Debugf("Clone installation wake up in progress, state is %q", clone.State)
+		return cloneOp.State, nil
+	}
+
+	cloneOp.CompleteAt = utils.GetMillis()
+	err = s.store.UpdateInstallationCloneOperation(cloneOp)
+	if err != nil {
+		logger.WithError(err).Error("Failed to set complete at for clone operation")
+		return cloneOp.State, nil
+	}
+
+	return model.InstallationCloneStateSucceeded, nil
+}
+
+// failClone marks the clone operation as failed. The clone installation is
+// left in place so that it can be inspected or deleted.
+func (s *InstallationCloneSupervisor) failClone(cloneOp *model.InstallationCloneOperation, logger log.FieldLogger) (model.InstallationCloneOperationState, error) {
+	logger.Warnf("Installation clone failed, clone installation %q needs to be cleaned up manually", cloneOp.CloneInstallationID)
+
+	cloneOp.CompleteAt = utils.GetMillis()
+	err := s.store.UpdateInstallationCloneOperation(cloneOp)
+	if err != nil {
+		logger.WithError(err).Error("Failed to set complete at for clone operation")
+		return cloneOp.State, nil
+	}
+
+	return model.InstallationCloneStateFailed, nil
+}
+
+// transitionCloneInstallation sets the clone installation to the given state
+// and returns the next state of the clone operation.
+func (s *InstallationCloneSupervisor) transitionCloneInstallation(cloneOp *model.InstallationCloneOperation, installationState string, nextState model.InstallationCloneOperationState, instanceID string, logger log.FieldLogger) (model.InstallationCloneOperationState, error) {
+	clone, lock, err := getAndLockInstallation(s.store, cloneOp.CloneInstallationID, instanceID, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get and lock clone installation")
+		return cloneOp.State, nil
+	}
+	defer lock.Unlock()
+
+	oldState := clone.State
+	clone.State = installationState
+	err = s.store.UpdateInstallation(clone)
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to set clone installation state to %s", installationState)
+		return cloneOp.State, nil
+	}
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeInstallation,
+		ID:        clone.ID,
+		OwnerID:   clone.OwnerID,
+		NewState:  clone.State,
+		OldState:  oldState,
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"DNS": clone.DNS, "Environment": s.environment},
+	}
+	recordStateChangeEvent(s.store, webhookPayload, s.instanceID, nil, logger)
+	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	return nextState, nil
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import log "github.com/sirupsen/logrus"
+
+type installationCloneOperationLockStore interface {
+	LockInstallationCloneOperations(ids []string, lockerID string) (bool, error)
+	UnlockInstallationCloneOperations(ids []string, lockerID string, force bool) (bool, error)
+}
+
+type installationCloneOperationLock struct {
+	ids      []string
+	lockerID string
+	store    installationCloneOperationLockStore
+	logger   log.FieldLogger
+}
+
+func newInstallationCloneOperationLock(id, lockerID string, store installationCloneOperationLockStore, logger log.FieldLogger) *installationCloneOperationLock {
+	return &installationCloneOperationLock{
+		ids:      []string{id},
+		lockerID: lockerID,
+		store:    store,
+		logger:   logger,
+	}
+}
+
+func (l *installationCloneOperationLock) TryLock() bool {
+	locked, err := l.store.LockInstallationCloneOperations(l.ids, l.lockerID)
+	if err != nil {
+		l.logger.WithError(err).Error("failed to lock installationCloneOperations")
+		return false
+	}
+
+	return locked
+}
+
+func (l *installationCloneOperationLock) Unlock() {
+	unlocked, err := l.store.UnlockInstallationCloneOperations(l.ids, l.lockerID, false)
+	if err != nil {
+		l.logger.WithError(err).Error("failed to unlock installationCloneOperations")
+	} else if !unlocked {
+		l.logger.Error("failed to release lock for installationCloneOperations")
+	}
+}
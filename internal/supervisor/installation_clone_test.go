@@ -0,0 +1,273 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/internal/tools/cloud"
+	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallationCloneSupervisor_Supervise(t *testing.T) {
+	t.Run("trigger backup", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		cloneOp, source, _ := setupInstallationClone(t, sqlStore, model.InstallationCloneStateRequested)
+
+		cloneSupervisor := supervisor.NewInstallationCloneSupervisor(sqlStore, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
+		cloneSupervisor.Supervise(cloneOp)
+
+		cloneOp, err := sqlStore.GetInstallationCloneOperation(cloneOp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.InstallationCloneStateBackupInProgress, cloneOp.State)
+		assert.NotEmpty(t, cloneOp.BackupID)
+
+		backup, err := sqlStore.GetInstallationBackup(cloneOp.BackupID)
+		require.NoError(t, err)
+		require.NotNil(t, backup)
+		assert.Equal(t, source.ID, backup.InstallationID)
+	})
+
+	t.Run("fail when source cannot be backed up", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		cloneOp, source, _ := setupInstallationClone(t, sqlStore, model.InstallationCloneStateRequested)
+		source.State = model.InstallationStateStable
+		err := sqlStore.UpdateInstallation(source)
+		require.NoError(t, err)
+
+		cloneSupervisor := supervisor.NewInstallationCloneSupervisor(sqlStore, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
+		cloneSupervisor.Supervise(cloneOp)
+
+		cloneOp, err = sqlStore.GetInstallationCloneOperation(cloneOp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.InstallationCloneStateFailing, cloneOp.State)
+		assert.Empty(t, cloneOp.BackupID)
+	})
+
+	t.Run("wait for source backup", func(t *testing.T) {
+		for _, testCase := range []struct {
+			description   string
+			backupState   model.InstallationBackupState
+			expectedState model.InstallationCloneOperationState
+		}{
+			{"when backup in progress", model.InstallationBackupStateBackupInProgress, model.InstallationCloneStateBackupInProgress},
+			{"when backup succeeded", model.InstallationBackupStateBackupSucceeded, model.InstallationCloneStateCreationInProgress},
+			{"when backup failed", model.InstallationBackupStateBackupFailed, model.InstallationCloneStateFailing},
+		} {
+			t.Run(testCase.description, func(t *testing.T) {
+				logger := testlib.MakeLogger(t)
+				sqlStore := store.MakeTestSQLStore(t, logger)
+				defer store.CloseConnection(t, sqlStore)
+
+				cloneOp, source, _ := setupInstallationClone(t, sqlStore, model.InstallationCloneStateBackupInProgress)
+				setupCloneBackup(t, sqlStore, cloneOp, source, testCase.backupState)
+
+				cloneSupervisor := supervisor.NewInstallationCloneSupervisor(sqlStore, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
+				cloneSupervisor.Supervise(cloneOp)
+
+				cloneOp, err := sqlStore.GetInstallationCloneOperation(cloneOp.ID)
+				require.NoError(t, err)
+				assert.Equal(t, testCase.expectedState, cloneOp.State)
+			})
+		}
+	})
+
+	t.Run("wait for clone creation", func(t *testing.T) {
+		for _, testCase := range []struct {
+			description               string
+			cloneState                string
+			expectedState             model.InstallationCloneOperationState
+			expectedInstallationState string
+		}{
+			{"when creation in progress", model.InstallationStateCreationInProgress, model.InstallationCloneStateCreationInProgress, model.InstallationStateCreationInProgress},
+			{"when clone stable", model.InstallationStateStable, model.InstallationCloneStateHibernationInProgress, model.InstallationStateHibernationRequested},
+			{"when creation failed", model.InstallationStateCreationFailed, model.InstallationCloneStateFailing, model.InstallationStateCreationFailed},
+		} {
+			t.Run(testCase.description, func(t *testing.T) {
+				logger := testlib.MakeLogger(t)
+				sqlStore := store.MakeTestSQLStore(t, logger)
+				defer store.CloseConnection(t, sqlStore)
+
+				cloneOp, _, clone := setupInstallationClone(t, sqlStore, model.InstallationCloneStateCreationInProgress)
+				clone.State = testCase.cloneState
+				err := sqlStore.UpdateInstallation(clone)
+				require.NoError(t, err)
+
+				cloneSupervisor := supervisor.NewInstallationCloneSupervisor(sqlStore, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
+				cloneSupervisor.Supervise(cloneOp)
+
+				cloneOp, err = sqlStore.GetInstallationCloneOperation(cloneOp.ID)
+				require.NoError(t, err)
+				assert.Equal(t, testCase.expectedState, cloneOp.State)
+
+				clone, err = sqlStore.GetInstallation(clone.ID, false, false)
+				require.NoError(t, err)
+				assert.Equal(t, testCase.expectedInstallationState, clone.State)
+			})
+		}
+	})
+
+	t.Run("trigger restoration when clone hibernated", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		cloneOp, source, clone := setupInstallationClone(t, sqlStore, model.InstallationCloneStateHibernationInProgress)
+		backup := setupCloneBackup(t, sqlStore, cloneOp, source, model.InstallationBackupStateBackupSucceeded)
+		clone.State = model.InstallationStateHibernating
+		err := sqlStore.UpdateInstallation(clone)
+		require.NoError(t, err)
+
+		cloneSupervisor := supervisor.NewInstallationCloneSupervisor(sqlStore, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
+		cloneSupervisor.Supervise(cloneOp)
+
+		cloneOp, err = sqlStore.GetInstallationCloneOperation(cloneOp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.InstallationCloneStateRestorationInProgress, cloneOp.State)
+		assert.NotEmpty(t, cloneOp.InstallationDBRestorationOperationID)
+
+		restoration, err := sqlStore.GetInstallationDBRestorationOperation(cloneOp.InstallationDBRestorationOperationID)
+		require.NoError(t, err)
+		require.NotNil(t, restoration)
+		assert.Equal(t, clone.ID, restoration.InstallationID)
+		assert.Equal(t, backup.ID, restoration.BackupID)
+	})
+
+	t.Run("wait for clone restoration", func(t *testing.T) {
+		for _, testCase := range []struct {
+			description      string
+			restorationState model.InstallationDBRestorationState
+			expectedState    model.InstallationCloneOperationState
+		}{
+			{"when restoration in progress", model.InstallationDBRestorationStateInProgress, model.InstallationCloneStateRestorationInProgress},
+			{"when restoration succeeded", model.InstallationDBRestorationStateSucceeded, model.InstallationCloneStateWakeUpInProgress},
+			{"when restoration failed", model.InstallationDBRestorationStateFailed, model.InstallationCloneStateFailing},
+		} {
+			t.Run(testCase.description, func(t *testing.T) {
+				logger := testlib.MakeLogger(t)
+				sqlStore := store.MakeTestSQLStore(t, logger)
+				defer store.CloseConnection(t, sqlStore)
+
+				cloneOp, _, clone := setupInstallationClone(t, sqlStore, model.InstallationCloneStateRestorationInProgress)
+				clone.State = model.InstallationStateHibernating
+				err := sqlStore.UpdateInstallation(clone)
+				require.NoError(t, err)
+
+				restoration := &model.InstallationDBRestorationOperation{
+					InstallationID: clone.ID,
+					State:          testCase.restorationState,
+				}
+				err = sqlStore.CreateInstallationDBRestorationOperation(restoration)
+				require.NoError(t, err)
+				cloneOp.InstallationDBRestorationOperationID = restoration.ID
+				err = sqlStore.UpdateInstallationCloneOperation(cloneOp)
+				require.NoError(t, err)
+
+				cloneSupervisor := supervisor.NewInstallationCloneSupervisor(sqlStore, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
+				cloneSupervisor.Supervise(cloneOp)
+
+				cloneOp, err = sqlStore.GetInstallationCloneOperation(cloneOp.ID)
+				require.NoError(t, err)
+				assert.Equal(t, testCase.expectedState, cloneOp.State)
+
+				if testCase.expectedState == model.InstallationCloneStateWakeUpInProgress {
+					clone, err = sqlStore.GetInstallation(clone.ID, false, false)
+					require.NoError(t, err)
+					assert.Equal(t, model.InstallationStateWakeUpRequested, clone.State)
+				}
+			})
+		}
+	})
+
+	t.Run("succeed when clone woken up", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		cloneOp, _, clone := setupInstallationClone(t, sqlStore, model.InstallationCloneStateWakeUpInProgress)
+		clone.State = model.InstallationStateStable
+		err := sqlStore.UpdateInstallation(clone)
+		require.NoError(t, err)
+
+		cloneSupervisor := supervisor.NewInstallationCloneSupervisor(sqlStore, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
+		cloneSupervisor.Supervise(cloneOp)
+
+		cloneOp, err = sqlStore.GetInstallationCloneOperation(cloneOp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.InstallationCloneStateSucceeded, cloneOp.State)
+		assert.True(t, cloneOp.CompleteAt > 0)
+	})
+
+	t.Run("fail clone", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		cloneOp, _, clone := setupInstallationClone(t, sqlStore, model.InstallationCloneStateFailing)
+
+		cloneSupervisor := supervisor.NewInstallationCloneSupervisor(sqlStore, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
+		cloneSupervisor.Supervise(cloneOp)
+
+		cloneOp, err := sqlStore.GetInstallationCloneOperation(cloneOp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.InstallationCloneStateFailed, cloneOp.State)
+
+		clone, err = sqlStore.GetInstallation(clone.ID, false, false)
+		require.NoError(t, err)
+		assert.NotNil(t, clone)
+	})
+}
+
+func setupInstallationClone(t *testing.T, sqlStore *store.SQLStore, state model.InstallationCloneOperationState) (*model.InstallationCloneOperation, *model.Installation, *model.Installation) {
+	source := &model.Installation{
+		Database:  model.InstallationDatabaseMultiTenantRDSPostgres,
+		Filestore: model.InstallationFilestoreBifrost,
+		State:     model.InstallationStateHibernating,
+		DNS:       fmt.Sprintf("dns-%s", uuid.NewRandom().String()[:6]),
+	}
+	err := sqlStore.CreateInstallation(source, nil)
+	require.NoError(t, err)
+
+	clone := model.NewInstallationCloneFromSource(source, &model.InstallationCloneRequest{
+		DNS: fmt.Sprintf("dns-%s", uuid.NewRandom().String()[:6]),
+	})
+	cloneOp, err := sqlStore.TriggerInstallationClone(source, clone, nil)
+	require.NoError(t, err)
+
+	cloneOp.State = state
+	err = sqlStore.UpdateInstallationCloneOperationState(cloneOp)
+	require.NoError(t, err)
+
+	return cloneOp, source, clone
+}
+
+func setupCloneBackup(t *testing.T, sqlStore *store.SQLStore, cloneOp *model.InstallationCloneOperation, source *model.Installation, state model.InstallationBackupState) *model.InstallationBackup {
+	backup := &model.InstallationBackup{
+		InstallationID: source.ID,
+		State:          state,
+	}
+	err := sqlStore.CreateInstallationBackup(backup)
+	require.NoError(t, err)
+
+	cloneOp.BackupID = backup.ID
+	err = sqlStore.UpdateInstallationCloneOperation(cloneOp)
+	require.NoError(t, err)
+
+	return backup
+}
@@ -0,0 +1,553 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/tools/cloud"
+	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
+	"github.com/mattermost/mattermost-cloud/internal/webhook"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// installationClusterMigrationStore abstracts the database operations required by the supervisor.
+type installationClusterMigrationStore interface {
+	GetUnlockedInstallationClusterMigrationOperationsPendingWork() ([]*model.InstallationClusterMigrationOperation, error)
+	GetInstallationClusterMigrationOperation(id string) (*model.InstallationClusterMigrationOperation, error)
+	UpdateInstallationClusterMigrationOperationState(migrationOp *model.InstallationClusterMigrationOperation) error
+	UpdateInstallationClusterMigrationOperation(migrationOp *model.InstallationClusterMigrationOperation) error
+	installationClusterMigrationOperationLockStore
+
+	GetCluster(id string) (*model.Cluster, error)
+	clusterLockStore
+
+	GetInstallation(installationID string, includeGroupConfig, includeGroupConfigOverrides bool) (*model.Installation, error)
+	UpdateInstallationState(installation *model.Installation) error
+	installationLockStore
+
+	CreateClusterInstallation(clusterInstallation *model.ClusterInstallation) error
+	GetClusterInstallation(clusterInstallationID string) (*model.ClusterInstallation, error)
+	GetClusterInstallations(filter *model.ClusterInstallationFilter) ([]*model.ClusterInstallation, error)
+	UpdateClusterInstallation(clusterInstallation *model.ClusterInstallation) error
+	clusterInstallationLockStore
+
+	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+	CreateEvent(event *model.Event) error
+}
+
+// installationClusterMigrationProvisioner abstracts the provisioning operations required by the supervisor.
+type installationClusterMigrationProvisioner interface {
+	GetPublicLoadBalancerEndpoint(cluster *model.Cluster, namespace string) (string, error)
+}
+
+// InstallationClusterMigrationSupervisor finds pending work and effects the required changes.
+//
+// The degree of parallelism is controlled by a weighted semaphore, intended to be shared with
+// other clients needing to coordinate background jobs.
+type InstallationClusterMigrationSupervisor struct {
+	store       installationClusterMigrationStore
+	provisioner installationClusterMigrationProvisioner
+	cloud       cloud.Provider
+	instanceID  string
+	logger      log.FieldLogger
+}
+
+// NewInstallationClusterMigrationSupervisor creates a new InstallationClusterMigrationSupervisor.
+func NewInstallationClusterMigrationSupervisor(
+	store installationClusterMigrationStore,
+	provisioner installationClusterMigrationProvisioner,
+	cloudProvider cloud.Provider,
+	instanceID string,
+	logger log.FieldLogger) *InstallationClusterMigrationSupervisor {
+	return &InstallationClusterMigrationSupervisor{
+		store:       store,
+		provisioner: provisioner,
+		cloud:       cloudProvider,
+		instanceID:  instanceID,
+		logger:      logger,
+	}
+}
+
+// Shutdown performs graceful shutdown tasks for the supervisor.
+func (s *InstallationClusterMigrationSupervisor) Shutdown() {
+	s.logger.Debug("Shutting down installation cluster migration supervisor")
+}
+
+// Do looks for work to be done on any pending cluster migration operations and attempts to schedule the required work.
+func (s *InstallationClusterMigrationSupervisor) Do() error {
+	migrationOperations, err := s.store.GetUnlockedInstallationClusterMigrationOperationsPendingWork()
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to query for pending work")
+		return errors.Wrap(err, "failed to query for pending work")
+	}
+
+	for _, migrationOp := range migrationOperations {
+		s.Supervise(migrationOp)
+	}
+
+	return nil
+}
+
+// Supervise schedules the required work on the given cluster migration operation.
+func (s *InstallationClusterMigrationSupervisor) Supervise(migrationOp *model.InstallationClusterMigrationOperation) {
+	logger := s.logger.WithFields(log.Fields{
+		"clusterMigrationOperation": migrationOp.ID,
+	})
+
+	lock := newInstallationClusterMigrationOperationLock(migrationOp.ID, s.instanceID, s.store, logger)
+	if !lock.TryLock() {
+		return
+	}
+	defer lock.Unlock()
+
+	// Before working on the cluster migration operation, it is crucial that we ensure that it
+	// was not updated to a new state by another provisioning server.
+	originalState := migrationOp.State
+	migrationOp, err := s.store.GetInstallationClusterMigrationOperation(migrationOp.ID)
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to get refreshed cluster migration operation")
+		return
+	}
+	if migrationOp.State != originalState {
+		logger.WithField("oldMigrationState", originalState).
+			WithField("newMigrationState", migrationOp.State).
+			Warn("Another provisioner has worked on this cluster migration operation; skipping...")
+		return
+	}
+
+	logger.Debugf("Supervising cluster migration operation in state %s", migrationOp.State)
+
+	newState, stateErr := s.transitionMigration(migrationOp, s.instanceID, logger)
+
+	migrationOp, err = s.store.GetInstallationClusterMigrationOperation(migrationOp.ID)
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to get cluster migration operation and thus persist state %s", newState)
+		return
+	}
+
+	if migrationOp.State == newState {
+		return
+	}
+
+	oldState := migrationOp.State
+	migrationOp.State = newState
+
+	err = s.store.UpdateInstallationClusterMigrationOperationState(migrationOp)
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to set cluster migration operation state to %s", newState)
+		return
+	}
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeInstallationClusterMigration,
+		ID:        migrationOp.ID,
+		OwnerID:   installationOwnerID(s.store, migrationOp.InstallationID, logger),
+		NewState:  string(migrationOp.State),
+		OldState:  string(oldState),
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{
+			"Installation":  migrationOp.InstallationID,
+			"SourceCluster": migrationOp.SourceClusterID,
+			"TargetCluster": migrationOp.TargetClusterID,
+			"Environment":   s.cloud.GetCloudEnvironmentName(),
+		},
+	}
+	recordStateChangeEvent(s.store, webhookPayload, s.instanceID, stateErr, logger)
+	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	logger.Debugf("Transitioned cluster migration operation from %s to %s", oldState, migrationOp.State)
+}
+
+// transitionMigration works with the given cluster migration operation to transition it to a final state.
+func (s *InstallationClusterMigrationSupervisor) transitionMigration(migrationOp *model.InstallationClusterMigrationOperation, instanceID string, logger log.FieldLogger) (model.InstallationClusterMigrationOperationState, error) {
+	switch migrationOp.State {
+	case model.InstallationClusterMigrationStateRequested:
+		return s.createTargetClusterInstallation(migrationOp, instanceID, logger)
+	case model.InstallationClusterMigrationStateTargetCreationInProgress:
+		return s.waitForTargetClusterInstallation(migrationOp, logger)
+	case model.InstallationClusterMigrationStateDNSSwitch:
+		return s.switchDNS(migrationOp, instanceID, logger)
+	case model.InstallationClusterMigrationStateSourceDeletionInProgress:
+		return s.waitForSourceDeletion(migrationOp, instanceID, logger)
+	case model.InstallationClusterMigrationStateRollbackRequested:
+		return s.rollbackMigration(migrationOp, instanceID, logger)
+	case model.InstallationClusterMigrationStateRollbackInProgress:
+		return s.waitForRollback(migrationOp, instanceID, logger)
+	default:
+		logger.Warnf("Found cluster migration operation pending work in unexpected state %s", migrationOp.State)
+		return migrationOp.State, nil
+	}
+}
+
+func (s *InstallationClusterMigrationSupervisor) createTargetClusterInstallation(migrationOp *model.InstallationClusterMigrationOperation, instanceID string, logger log.FieldLogger) (model.InstallationClusterMigrationOperationState, error) {
+	installation, err := s.store.GetInstallation(migrationOp.InstallationID, false, false)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get installation")
+		return migrationOp.State, nil
+	}
+	if installation == nil {
+		logger.Error("Installation not found")
+		return migrationOp.State, nil
+	}
+
+	cluster, err := s.store.GetCluster(migrationOp.TargetClusterID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get target cluster")
+		return migrationOp.State, nil
+	}
+	if cluster == nil {
+		logger.Error("Target cluster not found")
+		return model.InstallationClusterMigrationStateRollbackRequested, errors.Errorf("target cluster %s not found", migrationOp.TargetClusterID)
+	}
+
+	clusterLock := newClusterLock(cluster.ID, instanceID, s.store, logger)
+	if !clusterLock.TryLock() {
+		logger.Debugf("Failed to lock cluster %s", cluster.ID)
+		return migrationOp.State, nil
+	}
+	defer clusterLock.Unlock()
+
+	err = model.EnsureClusterReadyForInstallationMigration(cluster, installation)
+	if err != nil {
+		logger.WithError(err).Error("Target cluster is no longer ready for the migration")
+		return model.InstallationClusterMigrationStateRollbackRequested, errors.Wrap(err, "target cluster is no longer ready for the migration")
+	}
+
+	// A previous pass may have created the target cluster installation
+	// without recording it on the migration operation.
+	clusterInstallation, err := s.getTargetClusterInstallation(installation.ID, cluster.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get existing target cluster installation")
+		return migrationOp.State, nil
+	}
+	if clusterInstallation == nil {
+		clusterInstallation = &model.ClusterInstallation{
+			ClusterID:      cluster.ID,
+			InstallationID: installation.ID,
+			Namespace:      installation.ID,
+			State:          model.ClusterInstallationStateCreationRequested,
+		}
+		err = s.store.CreateClusterInstallation(clusterInstallation)
+		if err != nil {
+			logger.WithError(err).Error("Failed to create cluster installation on target cluster")
+			return migrationOp.State, nil
+		}
+
+		webhookPayload := &model.WebhookPayload{
+			Type:      model.TypeClusterInstallation,
+			ID:        clusterInstallation.ID,
+			OwnerID:   installation.OwnerID,
+			NewState:  clusterInstallation.State,
+			OldState:  "n/a",
+			Timestamp: time.Now().UnixNano(),
+			ExtraData: map[string]string{"Environment": s.cloud.GetCloudEnvironmentName()},
+		}
+		recordStateChangeEvent(s.store, webhookPayload, s.instanceID, nil, logger)
+		err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+		if err != nil {
+			logger.WithError(err).Error("Unable to process and send webhooks")
+		}
+	}
+
+	migrationOp.TargetClusterInstallationID = clusterInstallation.ID
+	err = s.store.UpdateInstallationClusterMigrationOperation(migrationOp)
+	if err != nil {
+		logger.WithError(err).Error("Failed to set target cluster installation ID for cluster migration operation")
+		return migrationOp.State, nil
+	}
+
+	logger.Infof("Requested creation of cluster installation %s on target cluster %s", clusterInstallation.ID, cluster.ID)
+
+	return model.InstallationClusterMigrationStateTargetCreationInProgress, nil
+}
+
+// getTargetClusterInstallation returns the cluster installation of the
+// installation on the target cluster which is not being deleted, or nil if
+// there is none.
+func (s *InstallationClusterMigrationSupervisor) getTargetClusterInstallation(installationID, clusterID string) (*model.ClusterInstallation, error) {
+	clusterInstallations, err := s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
+		InstallationID: installationID,
+		ClusterID:      clusterID,
+		Paging:         model.AllPagesNotDeleted(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get cluster installations")
+	}
+
+	for _, clusterInstallation := range clusterInstallations {
+		switch clusterInstallation.State {
+		case model.ClusterInstallationStateDeletionRequested,
+			model.ClusterInstallationStateDeletionFailed,
+			model.ClusterInstallationStateDeleted:
+			continue
+		}
+		return clusterInstallation, nil
+	}
+
+	return nil, nil
+}
+
+func (s *InstallationClusterMigrationSupervisor) waitForTargetClusterInstallation(migrationOp *model.InstallationClusterMigrationOperation, logger log.FieldLogger) (model.InstallationClusterMigrationOperationState, error) {
+	clusterInstallation, err := s.store.GetClusterInstallation(migrationOp.TargetClusterInstallationID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get target cluster installation")
+		return migrationOp.State, nil
+	}
+	if clusterInstallation == nil {
+		logger.Error("Target cluster installation not found")
+		return model.InstallationClusterMigrationStateRollbackRequested, errors.Errorf("target cluster installation %s not found", migrationOp.TargetClusterInstallationID)
+	}
+
+	switch clusterInstallation.State {
+	case model.ClusterInstallationStateStable:
+		logger.Info("Target cluster installation is stable, switching DNS")
+		return model.InstallationClusterMigrationStateDNSSwitch, nil
+	case model.ClusterInstallationStateCreationFailed:
+		logger.Error("Target cluster installation creation failed")
+		return model.InstallationClusterMigrationStateRollbackRequested, errors.Errorf("target cluster installation %s creation failed", clusterInstallation.ID)
+	default:
+		logger.Debugf("Target cluster installation creation in progress, state is %q", clusterInstallation.State)
+		return migrationOp.State, nil
+	}
+}
+
+func (s *InstallationClusterMigrationSupervisor) switchDNS(migrationOp *model.InstallationClusterMigrationOperation, instanceID string, logger log.FieldLogger) (model.InstallationClusterMigrationOperationState, error) {
+	installation, err := s.store.GetInstallation(migrationOp.InstallationID, false, false)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get installation")
+		return migrationOp.State, nil
+	}
+	if installation == nil {
+		logger.Error("Installation not found")
+		return migrationOp.State, nil
+	}
+
+	cluster, err := s.store.GetCluster(migrationOp.TargetClusterID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get target cluster")
+		return migrationOp.State, nil
+	}
+	if cluster == nil {
+		logger.Error("Target cluster not found")
+		return migrationOp.State, nil
+	}
+
+	// Deleting the source cluster installation after the switch would lose
+	// the data stored on the source cluster.
+	err = model.EnsureInstallationDataOutsideCluster(installation)
+	if err != nil {
+		logger.WithError(err).Error("Installation data cannot be migrated to the target cluster")
+		return model.InstallationClusterMigrationStateRollbackRequested, errors.Wrap(err, "installation data cannot be migrated to the target cluster")
+	}
+
+	endpoint, err := s.provisioner.GetPublicLoadBalancerEndpoint(cluster, "nginx")
+	if err != nil {
+		logger.WithError(err).Error("Couldn't get the load balancer endpoint (nginx) for target cluster")
+		return migrationOp.State, nil
+	}
+
+	// The record ID must match the standard value for the CNAME upsert to
+	// replace the existing record instead of adding a second one.
+	err = s.cloud.UpdatePublicRecordIDForCNAME(installation.DNS, installation.DNS, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to update the installation route53 record to the standard ID value")
+		return migrationOp.State, nil
+	}
+
+	err = s.cloud.CreatePublicCNAME(installation.DNS, []string{endpoint}, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to switch DNS CNAME record to target cluster")
+		return migrationOp.State, nil
+	}
+
+	logger.Infof("Switched DNS %s to target cluster %s", installation.DNS, cluster.ID)
+
+	err = s.requestClusterInstallationDeletion(migrationOp.SourceClusterInstallationID, installation, instanceID, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to request deletion of source cluster installation")
+		return migrationOp.State, nil
+	}
+
+	return model.InstallationClusterMigrationStateSourceDeletionInProgress, nil
+}
+
+func (s *InstallationClusterMigrationSupervisor) waitForSourceDeletion(migrationOp *model.InstallationClusterMigrationOperation, instanceID string, logger log.FieldLogger) (model.InstallationClusterMigrationOperationState, error) {
+	clusterInstallation, err := s.store.GetClusterInstallation(migrationOp.SourceClusterInstallationID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get source cluster installation")
+		return migrationOp.State, nil
+	}
+
+	if clusterInstallation != nil && clusterInstallation.State != model.ClusterInstallationStateDeleted {
+		if clusterInstallation.State == model.ClusterInstallationStateDeletionFailed {
+			logger.Warn("Source cluster installation deletion failed, retrying")
+			err = s.requestClusterInstallationDeletion(clusterInstallation.ID, nil, instanceID, logger)
+			if err != nil {
+				logger.WithError(err).Error("Failed to request deletion of source cluster installation")
+			}
+		}
+		logger.Debugf("Source cluster installation deletion in progress, state is %q", clusterInstallation.State)
+		return migrationOp.State, nil
+	}
+
+	err = s.completeMigration(migrationOp, instanceID, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to complete cluster migration")
+		return migrationOp.State, nil
+	}
+
+	logger.Info("Installation migrated to target cluster")
+
+	return model.InstallationClusterMigrationStateSucceeded, nil
+}
+
+func (s *InstallationClusterMigrationSupervisor) rollbackMigration(migrationOp *model.InstallationClusterMigrationOperation, instanceID string, logger log.FieldLogger) (model.InstallationClusterMigrationOperationState, error) {
+	if migrationOp.TargetClusterInstallationID == "" {
+		return s.finishRollback(migrationOp, instanceID, logger)
+	}
+
+	err := s.requestClusterInstallationDeletion(migrationOp.TargetClusterInstallationID, nil, instanceID, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to request deletion of target cluster installation")
+		return migrationOp.State, nil
+	}
+
+	logger.Info("Requested deletion of target cluster installation")
+
+	return model.InstallationClusterMigrationStateRollbackInProgress, nil
+}
+
+func (s *InstallationClusterMigrationSupervisor) waitForRollback(migrationOp *model.InstallationClusterMigrationOperation, instanceID string, logger log.FieldLogger) (model.InstallationClusterMigrationOperationState, error) {
+	clusterInstallation, err := s.store.GetClusterInstallation(migrationOp.TargetClusterInstallationID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get target cluster installation")
+		return migrationOp.State, nil
+	}
+
+	if clusterInstallation != nil && clusterInstallation.State != model.ClusterInstallationStateDeleted {
+		if clusterInstallation.State == model.ClusterInstallationStateDeletionFailed {
+			logger.Warn("Target cluster installation deletion failed, retrying")
+			return model.InstallationClusterMigrationStateRollbackRequested, nil
+		}
+		logger.Debugf("Target cluster installation deletion in progress, state is %q", clusterInstallation.State)
+		return migrationOp.State, nil
+	}
+
+	return s.finishRollback(migrationOp, instanceID, logger)
+}
+
+func (s *InstallationClusterMigrationSupervisor) finishRollback(migrationOp *model.InstallationClusterMigrationOperation, instanceID string, logger log.FieldLogger) (model.InstallationClusterMigrationOperationState, error) {
+	err := s.completeMigration(migrationOp, instanceID, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to complete cluster migration rollback")
+		return migrationOp.State, nil
+	}
+
+	logger.Warn("Installation cluster migration rolled back")
+
+	return model.InstallationClusterMigrationStateFailed, nil
+}
+
+// requestClusterInstallationDeletion marks the given cluster installation for deletion
+// unless it is already being deleted.
+func (s *InstallationClusterMigrationSupervisor) requestClusterInstallationDeletion(clusterInstallationID string, installation *model.Installation, instanceID string, logger log.FieldLogger) error {
+	clusterInstallation, err := s.store.GetClusterInstallation(clusterInstallationID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get cluster installation")
+	}
+	if clusterInstallation == nil {
+		return errors.Errorf("cluster installation %s not found", clusterInstallationID)
+	}
+
+	switch clusterInstallation.State {
+	case model.ClusterInstallationStateDeletionRequested, model.ClusterInstallationStateDeleted:
+		return nil
+	}
+
+	lock := newClusterInstallationLock(clusterInstallation.ID, instanceID, s.store, logger)
+	if !lock.TryLock() {
+		return errors.Errorf("failed to lock cluster installation %s", clusterInstallation.ID)
+	}
+	defer lock.Unlock()
+
+	oldState := clusterInstallation.State
+	clusterInstallation.State = model.ClusterInstallationStateDeletionRequested
+	err = s.store.UpdateClusterInstallation(clusterInstallation)
+	if err != nil {
+		return errors.Wrap(err, "failed to mark cluster installation for deletion")
+	}
+
+	ownerID := installationOwnerID(s.store, clusterInstallation.InstallationID, logger)
+	if installation != nil {
+		ownerID = installation.OwnerID
+	}
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeClusterInstallation,
+		ID:        clusterInstallation.ID,
+		OwnerID:   ownerID,
+		NewState:  clusterInstallation.State,
+		OldState:  oldState,
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"Environment": s.cloud.GetCloudEnvironmentName()},
+	}
+	recordStateChangeEvent(s.store, webhookPayload, s.instanceID, nil, logger)
+	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	return nil
+}
+
+// completeMigration returns the installation to the stable state and marks
+// the cluster migration operation as completed.
+func (s *InstallationClusterMigrationSupervisor) completeMigration(migrationOp *model.InstallationClusterMigrationOperation, instanceID string, logger log.FieldLogger) error {
+	installation, lock, err := getAndLockInstallation(s.store, migrationOp.InstallationID, instanceID, logger)
+	if err != nil {
+		return errors.Wrap(err, "failed to get and lock installation")
+	}
+	defer lock.Unlock()
+
+	if installation.State == model.InstallationStateClusterMigrationInProgress {
+		oldState := installation.State
+		installation.State = model.InstallationStateStable
+		err = s.store.UpdateInstallationState(installation)
+		if err != nil {
+			return errors.Wrap(err, "failed to set installation state to stable")
+		}
+
+		webhookPayload := &model.WebhookPayload{
+			Type:      model.TypeInstallation,
+			ID:        installation.ID,
+			OwnerID:   installation.OwnerID,
+			NewState:  installation.State,
+			OldState:  oldState,
+			Timestamp: time.Now().UnixNano(),
+			ExtraData: map[string]string{"DNS": installation.DNS, "Environment": s.cloud.GetCloudEnvironmentName()},
+		}
+		recordStateChangeEvent(s.store, webhookPayload, s.instanceID, nil, logger)
+		err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+		if err != nil {
+			logger.WithError(err).Error("Unable to process and send webhooks")
+		}
+	}
+
+	migrationOp.CompleteAt = utils.GetMillis()
+	err = s.store.UpdateInstallationClusterMigrationOperation(migrationOp)
+	if err != nil {
+		return errors.Wrap(err, "failed to set complete at for cluster migration operation")
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import log "github.com/sirupsen/logrus"
+
+type installationClusterMigrationOperationLockStore interface {
+	LockInstallationClusterMigrationOperations(ids []string, lockerID string) (bool, error)
+	UnlockInstallationClusterMigrationOperations(ids []string, lockerID string, force bool) (bool, error)
+}
+
+type installationClusterMigrationOperationLock struct {
+	ids      []string
+	lockerID string
+	store    installationClusterMigrationOperationLockStore
+	logger   log.FieldLogger
+}
+
+func newInstallationClusterMigrationOperationLock(id, lockerID string, store installationClusterMigrationOperationLockStore, logger log.FieldLogger) *installationClusterMigrationOperationLock {
+	return &installationClusterMigrationOperationLock{
+		ids:      []string{id},
+		lockerID: lockerID,
+		store:    store,
+		logger:   logger,
+	}
+}
+
+func (l *installationClusterMigrationOperationLock) TryLock() bool {
+	locked, err := l.store.LockInstallationClusterMigrationOperations(l.ids, l.lockerID)
+	if err != nil {
+		l.logger.WithError(err).Error("failed to lock installationClusterMigrationOperations")
+		return false
+	}
+
+	return locked
+}
+
+func (l *installationClusterMigrationOperationLock) Unlock() {
+	unlocked, err := l.store.UnlockInstallationClusterMigrationOperations(l.ids, l.lockerID, false)
+	if err != nil {
+		l.logger.WithError(err).Error("failed to unlock installationClusterMigrationOperations")
+	} else if !unlocked {
+		l.logger.Error("failed to release lock for installationClusterMigrationOperations")
+	}
+}
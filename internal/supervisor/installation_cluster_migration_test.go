@@ -0,0 +1,300 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/internal/tools/cloud"
+	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallationClusterMigrationSupervisor_Supervise(t *testing.T) {
+	newSupervisor := func(sqlStore *store.SQLStore, t *testing.T) *supervisor.InstallationClusterMigrationSupervisor {
+		return supervisor.NewInstallationClusterMigrationSupervisor(
+			sqlStore,
+			&mockInstallationProvisioner{},
+			cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}),
+			"instanceID",
+			testlib.MakeLogger(t),
+		)
+	}
+
+	t.Run("create target cluster installation", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		migrationOp, installation, _ := setupClusterMigration(t, sqlStore, model.InstallationClusterMigrationStateRequested)
+
+		newSupervisor(sqlStore, t).Supervise(migrationOp)
+
+		migrationOp, err := sqlStore.GetInstallationClusterMigrationOperation(migrationOp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.InstallationClusterMigrationStateTargetCreationInProgress, migrationOp.State)
+		require.NotEmpty(t, migrationOp.TargetClusterInstallationID)
+
+		targetCI, err := sqlStore.GetClusterInstallation(migrationOp.TargetClusterInstallationID)
+		require.NoError(t, err)
+		assert.Equal(t, migrationOp.TargetClusterID, targetCI.ClusterID)
+		assert.Equal(t, installation.ID, targetCI.InstallationID)
+		assert.Equal(t, model.ClusterInstallationStateCreationRequested, targetCI.State)
+	})
+
+	t.Run("reuse existing target cluster installation", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		migrationOp, installation, _ := setupClusterMigration(t, sqlStore, model.InstallationClusterMigrationStateRequested)
+		existingCI := &model.ClusterInstallation{
+			InstallationID: installation.ID,
+			ClusterID:      migrationOp.TargetClusterID,
+			Namespace:      installation.ID,
+			State:          model.ClusterInstallationStateCreationRequested,
+		}
+		err := sqlStore.CreateClusterInstallation(existingCI)
+		require.NoError(t, err)
+
+		newSupervisor(sqlStore, t).Supervise(migrationOp)
+
+		migrationOp, err = sqlStore.GetInstallationClusterMigrationOperation(migrationOp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.InstallationClusterMigrationStateTargetCreationInProgress, migrationOp.State)
+		assert.Equal(t, existingCI.ID, migrationOp.TargetClusterInstallationID)
+
+		targetCIs, err := sqlStore.GetClusterInstallations(&model.ClusterInstallationFilter{
+			InstallationID: installation.ID,
+			ClusterID:      migrationOp.TargetClusterID,
+			Paging:         model.AllPagesNotDeleted(),
+		})
+		require.NoError(t, err)
+		assert.Len(t, targetCIs, 1)
+	})
+
+	t.Run("roll back when target cluster not ready", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		migrationOp, _, _ := setupClusterMigration(t, sqlStore, model.InstallationClusterMigrationStateRequested)
+		targetCluster, err := sqlStore.GetCluster(migrationOp.TargetClusterID)
+		require.NoError(t, err)
+		targetCluster.AllowInstallations = false
+		err = sqlStore.UpdateCluster(targetCluster)
+		require.NoError(t, err)
+
+		newSupervisor(sqlStore, t).Supervise(migrationOp)
+
+		migrationOp, err = sqlStore.GetInstallationClusterMigrationOperation(migrationOp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.InstallationClusterMigrationStateRollbackRequested, migrationOp.State)
+	})
+
+	t.Run("wait for target cluster installation", func(t *testing.T) {
+		for _, testCase := range []struct {
+			description   string
+			targetCIState string
+			expectedState model.InstallationClusterMigrationOperationState
+		}{
+			{"when reconciling", model.ClusterInstallationStateReconciling, model.InstallationClusterMigrationStateTargetCreationInProgress},
+			{"when stable", model.ClusterInstallationStateStable, model.InstallationClusterMigrationStateDNSSwitch},
+			{"when creation failed", model.ClusterInstallationStateCreationFailed, model.InstallationClusterMigrationStateRollbackRequested},
+		} {
+			t.Run(testCase.description, func(t *testing.T) {
+				logger := testlib.MakeLogger(t)
+				sqlStore := store.MakeTestSQLStore(t, logger)
+				defer store.CloseConnection(t, sqlStore)
+
+				migrationOp, _, _ := setupClusterMigration(t, sqlStore, model.InstallationClusterMigrationStateTargetCreationInProgress)
+				setupTargetClusterInstallation(t, sqlStore, migrationOp, testCase.targetCIState)
+
+				newSupervisor(sqlStore, t).Supervise(migrationOp)
+
+				migrationOp, err := sqlStore.GetInstallationClusterMigrationOperation(migrationOp.ID)
+				require.NoError(t, err)
+				assert.Equal(t, testCase.expectedState, migrationOp.State)
+			})
+		}
+	})
+
+	t.Run("switch DNS and delete source cluster installation", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		migrationOp, _, sourceCI := setupClusterMigration(t, sqlStore, model.InstallationClusterMigrationStateDNSSwitch)
+		setupTargetClusterInstallation(t, sqlStore, migrationOp, model.ClusterInstallationStateStable)
+
+		newSupervisor(sqlStore, t).Supervise(migrationOp)
+
+		migrationOp, err := sqlStore.GetInstallationClusterMigrationOperation(migrationOp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.InstallationClusterMigrationStateSourceDeletionInProgress, migrationOp.State)
+
+		sourceCI, err = sqlStore.GetClusterInstallation(sourceCI.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.ClusterInstallationStateDeletionRequested, sourceCI.State)
+	})
+
+	t.Run("roll back instead of switching DNS when data is stored on the cluster", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		migrationOp, installation, sourceCI := setupClusterMigration(t, sqlStore, model.InstallationClusterMigrationStateDNSSwitch)
+		setupTargetClusterInstallation(t, sqlStore, migrationOp, model.ClusterInstallationStateStable)
+		installation.Database = model.InstallationDatabaseMysqlOperator
+		err := sqlStore.UpdateInstallation(installation)
+		require.NoError(t, err)
+
+		newSupervisor(sqlStore, t).Supervise(migrationOp)
+
+		migrationOp, err = sqlStore.GetInstallationClusterMigrationOperation(migrationOp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.InstallationClusterMigrationStateRollbackRequested, migrationOp.State)
+
+		sourceCI, err = sqlStore.GetClusterInstallation(sourceCI.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.ClusterInstallationStateStable, sourceCI.State)
+	})
+
+	t.Run("wait for source deletion", func(t *testing.T) {
+		for _, testCase := range []struct {
+			description               string
+			sourceCIState             string
+			expectedState             model.InstallationClusterMigrationOperationState
+			expectedInstallationState string
+		}{
+			{"when deletion in progress", model.ClusterInstallationStateDeletionRequested, model.InstallationClusterMigrationStateSourceDeletionInProgress, model.InstallationStateClusterMigrationInProgress},
+			{"when deleted", model.ClusterInstallationStateDeleted, model.InstallationClusterMigrationStateSucceeded, model.InstallationStateStable},
+		} {
+			t.Run(testCase.description, func(t *testing.T) {
+				logger := testlib.MakeLogger(t)
+				sqlStore := store.MakeTestSQLStore(t, logger)
+				defer store.CloseConnection(t, sqlStore)
+
+				migrationOp, installation, sourceCI := setupClusterMigration(t, sqlStore, model.InstallationClusterMigrationStateSourceDeletionInProgress)
+				sourceCI.State = testCase.sourceCIState
+				err := sqlStore.UpdateClusterInstallation(sourceCI)
+				require.NoError(t, err)
+
+				newSupervisor(sqlStore, t).Supervise(migrationOp)
+
+				migrationOp, err = sqlStore.GetInstallationClusterMigrationOperation(migrationOp.ID)
+				require.NoError(t, err)
+				assert.Equal(t, testCase.expectedState, migrationOp.State)
+
+				installation, err = sqlStore.GetInstallation(installation.ID, false, false)
+				require.NoError(t, err)
+				assert.Equal(t, testCase.expectedInstallationState, installation.State)
+			})
+		}
+	})
+
+	t.Run("roll back", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		migrationOp, installation, sourceCI := setupClusterMigration(t, sqlStore, model.InstallationClusterMigrationStateRollbackRequested)
+		targetCI := setupTargetClusterInstallation(t, sqlStore, migrationOp, model.ClusterInstallationStateCreationFailed)
+
+		migrationSupervisor := newSupervisor(sqlStore, t)
+		migrationSupervisor.Supervise(migrationOp)
+
+		migrationOp, err := sqlStore.GetInstallationClusterMigrationOperation(migrationOp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.InstallationClusterMigrationStateRollbackInProgress, migrationOp.State)
+
+		targetCI, err = sqlStore.GetClusterInstallation(targetCI.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.ClusterInstallationStateDeletionRequested, targetCI.State)
+
+		targetCI.State = model.ClusterInstallationStateDeleted
+		err = sqlStore.UpdateClusterInstallation(targetCI)
+		require.NoError(t, err)
+
+		migrationSupervisor.Supervise(migrationOp)
+
+		migrationOp, err = sqlStore.GetInstallationClusterMigrationOperation(migrationOp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.InstallationClusterMigrationStateFailed, migrationOp.State)
+		assert.True(t, migrationOp.CompleteAt > 0)
+
+		installation, err = sqlStore.GetInstallation(installation.ID, false, false)
+		require.NoError(t, err)
+		assert.Equal(t, model.InstallationStateStable, installation.State)
+
+		sourceCI, err = sqlStore.GetClusterInstallation(sourceCI.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.ClusterInstallationStateStable, sourceCI.State)
+	})
+}
+
+func setupClusterMigration(t *testing.T, sqlStore *store.SQLStore, state model.InstallationClusterMigrationOperationState) (*model.InstallationClusterMigrationOperation, *model.Installation, *model.ClusterInstallation) {
+	installation := &model.Installation{
+		Database:  model.InstallationDatabaseMultiTenantRDSPostgres,
+		Filestore: model.InstallationFilestoreBifrost,
+		State:     model.InstallationStateStable,
+		DNS:       fmt.Sprintf("dns-%s", uuid.NewRandom().String()[:6]),
+	}
+	err := sqlStore.CreateInstallation(installation, nil)
+	require.NoError(t, err)
+
+	sourceCluster := &model.Cluster{State: model.ClusterStateStable, AllowInstallations: true}
+	err = sqlStore.CreateCluster(sourceCluster, nil)
+	require.NoError(t, err)
+	targetCluster := &model.Cluster{State: model.ClusterStateStable, AllowInstallations: true}
+	err = sqlStore.CreateCluster(targetCluster, nil)
+	require.NoError(t, err)
+
+	sourceCI := &model.ClusterInstallation{
+		InstallationID: installation.ID,
+		ClusterID:      sourceCluster.ID,
+		Namespace:      installation.ID,
+		State:          model.ClusterInstallationStateStable,
+	}
+	err = sqlStore.CreateClusterInstallation(sourceCI)
+	require.NoError(t, err)
+
+	migrationOp, err := sqlStore.TriggerInstallationClusterMigration(&model.InstallationClusterMigrationOperation{
+		SourceClusterID:             sourceCluster.ID,
+		TargetClusterID:             targetCluster.ID,
+		SourceClusterInstallationID: sourceCI.ID,
+	}, installation)
+	require.NoError(t, err)
+
+	migrationOp.State = state
+	err = sqlStore.UpdateInstallationClusterMigrationOperationState(migrationOp)
+	require.NoError(t, err)
+
+	return migrationOp, installation, sourceCI
+}
+
+func setupTargetClusterInstallation(t *testing.T, sqlStore *store.SQLStore, migrationOp *model.InstallationClusterMigrationOperation, state string) *model.ClusterInstallation {
+	targetCI := &model.ClusterInstallation{
+		InstallationID: migrationOp.InstallationID,
+		ClusterID:      migrationOp.TargetClusterID,
+		Namespace:      migrationOp.InstallationID,
+		State:          state,
+	}
+	err := sqlStore.CreateClusterInstallation(targetCI)
+	require.NoError(t, err)
+
+	migrationOp.TargetClusterInstallationID = targetCI.ID
+	err = sqlStore.UpdateInstallationClusterMigrationOperation(migrationOp)
+	require.NoError(t, err)
+
+	return targetCI
+}
@@ -9,8 +9,10 @@ import (
 
 	"github.com/mattermost/mattermost-cloud/internal/provisioner"
 	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
+	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
 	"github.com/mattermost/mattermost-cloud/internal/webhook"
 	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -35,14 +37,24 @@ type installationDBRestorationStore interface {
 
 	GetCluster(id string) (*model.Cluster, error)
 
+	model.InstallationDatabaseStoreInterface
+
 	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+	CreateEvent(event *model.Event) error
 }
 
 // restoreOperator abstracts different restoration operations required by the installation db restoration supervisor.
 type restoreOperator interface {
 	TriggerRestore(installation *model.Installation, backup *model.InstallationBackup, cluster *model.Cluster) error
-	CheckRestoreStatus(backupMeta *model.InstallationBackup, cluster *model.Cluster) (int64, error)
-	CleanupRestoreJob(backup *model.InstallationBackup, cluster *model.Cluster) error
+	CheckRestoreStatus(installation *model.Installation, backupMeta *model.InstallationBackup, cluster *model.Cluster) (int64, error)
+	CleanupRestoreJob(installation *model.Installation, backup *model.InstallationBackup, cluster *model.Cluster) error
+}
+
+// restorationDatabaseProvider provides the databases of installations restored to a point in time.
+type restorationDatabaseProvider interface {
+	GetDatabaseForInstallation(installation *model.Installation) model.Database
 }
 
 // InstallationDBRestorationSupervisor finds pending work and effects the required changes.
@@ -56,6 +68,7 @@ type InstallationDBRestorationSupervisor struct {
 	environment string
 	logger      log.FieldLogger
 
+	dbProvider      restorationDatabaseProvider
 	restoreOperator restoreOperator
 }
 
@@ -63,12 +76,14 @@ type InstallationDBRestorationSupervisor struct {
 func NewInstallationDBRestorationSupervisor(
 	store installationDBRestorationStore,
 	aws aws.AWS,
+	dbProvider restorationDatabaseProvider,
 	restoreOperator restoreOperator,
 	instanceID string,
 	logger log.FieldLogger) *InstallationDBRestorationSupervisor {
 	return &InstallationDBRestorationSupervisor{
 		store:           store,
 		aws:             aws,
+		dbProvider:      dbProvider,
 		restoreOperator: restoreOperator,
 		instanceID:      instanceID,
 		environment:     aws.GetCloudEnvironmentName(),
@@ -86,7 +101,7 @@ func (s *InstallationDBRestorationSupervisor) Do() error {
 	installationDBRestorations, err := s.store.GetUnlockedInstallationDBRestorationOperationsPendingWork()
 	if err != nil {
 		s.logger.WithError(err).Warn("Failed to query for pending work")
-		return nil
+		return errors.Wrap(err, "failed to query for pending work")
 	}
 
 	for _, restoration := range installationDBRestorations {
@@ -125,7 +140,7 @@ func (s *InstallationDBRestorationSupervisor) Supervise(restoration *model.Insta
 
 	logger.Debugf("Supervising restoration in state %s", restoration.State)
 
-	newState := s.transitionRestoration(restoration, s.instanceID, logger)
+	newState, stateErr := s.transitionRestoration(restoration, s.instanceID, logger)
 
 	restoration, err = s.store.GetInstallationDBRestorationOperation(restoration.ID)
 	if err != nil {
@@ -149,11 +164,13 @@ func (s *InstallationDBRestorationSupervisor) Supervise(restoration *model.Insta
 	webhookPayload := &model.WebhookPayload{
 		Type:      model.TypeInstallationDBRestoration,
 		ID:        restoration.ID,
+		OwnerID:   installationOwnerID(s.store, restoration.InstallationID, logger),
 		NewState:  string(restoration.State),
 		OldState:  string(oldState),
 		Timestamp: time.Now().UnixNano(),
 		ExtraData: map[string]string{"Environment": s.environment},
 	}
+	recordStateChangeEvent(s.store, webhookPayload, s.instanceID, stateErr, logger)
 	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
 	if err != nil {
 		logger.WithError(err).Error("Unable to process and send webhooks")
@@ -163,12 +180,18 @@ func (s *InstallationDBRestorationSupervisor) Supervise(restoration *model.Insta
 }
 
 // transitionRestoration works with the given restoration to transition it to a final state.
-func (s *InstallationDBRestorationSupervisor) transitionRestoration(restoration *model.InstallationDBRestorationOperation, instanceID string, logger log.FieldLogger) model.InstallationDBRestorationState {
+func (s *InstallationDBRestorationSupervisor) transitionRestoration(restoration *model.InstallationDBRestorationOperation, instanceID string, logger log.FieldLogger) (model.InstallationDBRestorationState, error) {
 	switch restoration.State {
 	case model.InstallationDBRestorationStateRequested:
+		if restoration.IsPointInTime() {
+			return s.restoreToPointInTime(restoration, instanceID, logger)
+		}
 		return s.triggerRestoration(restoration, instanceID, logger)
 
 	case model.InstallationDBRestorationStateInProgress:
+		if restoration.IsPointInTime() {
+			return s.restoreToPointInTime(restoration, instanceID, logger)
+		}
 		return s.checkRestorationStatus(restoration, instanceID, logger)
 
 	case model.InstallationDBRestorationStateFinalizing:
@@ -179,96 +202,145 @@ func (s *InstallationDBRestorationSupervisor) transitionRestoration(restoration
 
 	default:
 		logger.Warnf("Found restoration pending work in unexpected state %s", restoration.State)
-		return restoration.State
+		return restoration.State, nil
 	}
 }
 
-func (s *InstallationDBRestorationSupervisor) triggerRestoration(restoration *model.InstallationDBRestorationOperation, instanceID string, logger log.FieldLogger) model.InstallationDBRestorationState {
+func (s *InstallationDBRestorationSupervisor) triggerRestoration(restoration *model.InstallationDBRestorationOperation, instanceID string, logger log.FieldLogger) (model.InstallationDBRestorationState, error) {
 	installation, lock, err := getAndLockInstallation(s.store, restoration.InstallationID, instanceID, logger)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get and lock installation")
-		return restoration.State
+		return restoration.State, nil
 	}
 	defer lock.Unlock()
 
 	backup, err := s.store.GetInstallationBackup(restoration.BackupID)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get backup")
-		return restoration.State
+		return restoration.State, nil
 	}
 
 	if restoration.ClusterInstallationID == "" {
 		restoreCI, ciLock, err := claimClusterInstallation(s.store, installation, instanceID, logger)
 		if err != nil {
 			logger.WithError(err).Error("Failed to claim Cluster Installation for restoration")
-			return restoration.State
+			return restoration.State, nil
 		}
 		defer ciLock.Unlock()
 		restoration.ClusterInstallationID = restoreCI.ID
 		err = s.store.UpdateInstallationDBRestorationOperation(restoration)
 		if err != nil {
 			logger.WithError(err).Error("Failed to assign cluster installation to restoration")
-			return restoration.State
+			return restoration.State, nil
 		}
 	}
 
 	cluster, err := getClusterForClusterInstallation(s.store, restoration.ClusterInstallationID)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get cluster for restoration")
-		return restoration.State
+		return restoration.State, nil
 	}
 
 	err = s.restoreOperator.TriggerRestore(installation, backup, cluster)
 	if err != nil {
 		logger.WithError(err).Error("Failed to trigger restoration job")
-		return restoration.State
+		return restoration.State, nil
 	}
 
-	return model.InstallationDBRestorationStateInProgress
+	return model.InstallationDBRestorationStateInProgress, nil
 }
 
-func (s *InstallationDBRestorationSupervisor) checkRestorationStatus(restoration *model.InstallationDBRestorationOperation, instanceID string, logger log.FieldLogger) model.InstallationDBRestorationState {
+func (s *InstallationDBRestorationSupervisor) checkRestorationStatus(restoration *model.InstallationDBRestorationOperation, instanceID string, logger log.FieldLogger) (model.InstallationDBRestorationState, error) {
+	installation, err := s.store.GetInstallation(restoration.InstallationID, false, false)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get installation")
+		return restoration.State, nil
+	}
+	if installation == nil {
+		logger.Error("Installation not found")
+		return restoration.State, nil
+	}
+
 	backup, err := s.store.GetInstallationBackup(restoration.BackupID)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get backup")
-		return restoration.State
+		return restoration.State, nil
 	}
 
 	cluster, err := getClusterForClusterInstallation(s.store, restoration.ClusterInstallationID)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get cluster for restoration")
-		return restoration.State
+		return restoration.State, nil
 	}
 
-	completeAt, err := s.restoreOperator.CheckRestoreStatus(backup, cluster)
+	completeAt, err := s.restoreOperator.CheckRestoreStatus(installation, backup, cluster)
 	if err != nil {
 		if err == provisioner.ErrJobBackoffLimitReached {
 			logger.WithError(err).Error("Installation db restoration failed")
-			return model.InstallationDBRestorationStateFailing
+			return model.InstallationDBRestorationStateFailing, errors.Wrap(err, "installation db restoration failed")
 		}
 		logger.WithError(err).Error("Failed to check restoration status")
-		return restoration.State
+		return restoration.State, nil
 	}
 	if completeAt <= 0 {
 		logger.Info("Database restoration still in progress")
-		return restoration.State
+		return restoration.State, nil
 	}
 
 	restoration.CompleteAt = completeAt
 	err = s.store.UpdateInstallationDBRestorationOperation(restoration)
 	if err != nil {
 		logger.WithError(err).Error("Failed to update restoration")
-		return restoration.State
+		return restoration.State, nil
+	}
+
+	return model.InstallationDBRestorationStateFinalizing, nil
+}
+
+// restoreToPointInTime drives the point-in-time restoration of the installation
+// database, which is done natively by the database without a restoration job.
+func (s *InstallationDBRestorationSupervisor) restoreToPointInTime(restoration *model.InstallationDBRestorationOperation, instanceID string, logger log.FieldLogger) (model.InstallationDBRestorationState, error) {
+	installation, lock, err := getAndLockInstallation(s.store, restoration.InstallationID, instanceID, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get and lock installation")
+		return restoration.State, nil
+	}
+	defer lock.Unlock()
+
+	restorer, ok := s.dbProvider.GetDatabaseForInstallation(installation).(model.DatabasePointInTimeRestorer)
+	if !ok {
+		logger.Errorf("Point-in-time restoration is not supported for database type %s", installation.Database)
+		return model.InstallationDBRestorationStateFailing, errors.Errorf("point-in-time restoration is not supported for database type %s", installation.Database)
+	}
+
+	ready, err := restorer.RestoreToPointInTime(s.store, restoration.SourceInstallationID, restoration.RestoreTime, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to restore database to point in time")
+		if errors.Cause(err) == model.ErrPointInTimeRestoreNotPossible {
+			return model.InstallationDBRestorationStateFailing, errors.Wrap(err, "failed to restore database to point in time")
+		}
+		return restoration.State, nil
+	}
+	if !ready {
+		logger.Info("Point-in-time database restoration still in progress")
+		return model.InstallationDBRestorationStateInProgress, nil
+	}
+
+	restoration.CompleteAt = utils.GetMillis()
+	err = s.store.UpdateInstallationDBRestorationOperation(restoration)
+	if err != nil {
+		logger.WithError(err).Error("Failed to update restoration")
+		return restoration.State, nil
 	}
 
-	return model.InstallationDBRestorationStateFinalizing
+	return model.InstallationDBRestorationStateFinalizing, nil
 }
 
-func (s *InstallationDBRestorationSupervisor) finalizeRestoration(restoration *model.InstallationDBRestorationOperation, instanceID string, logger log.FieldLogger) model.InstallationDBRestorationState {
+func (s *InstallationDBRestorationSupervisor) finalizeRestoration(restoration *model.InstallationDBRestorationOperation, instanceID string, logger log.FieldLogger) (model.InstallationDBRestorationState, error) {
 	installation, lock, err := getAndLockInstallation(s.store, restoration.InstallationID, instanceID, logger)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get and lock installation")
-		return restoration.State
+		return restoration.State, nil
 	}
 	defer lock.Unlock()
 
@@ -277,31 +349,33 @@ func (s *InstallationDBRestorationSupervisor) finalizeRestoration(restoration *m
 	err = s.store.UpdateInstallation(installation)
 	if err != nil {
 		logger.WithError(err).Error("Failed to set installation to target state after restore")
-		return restoration.State
+		return restoration.State, nil
 	}
 
 	webhookPayload := &model.WebhookPayload{
 		Type:      model.TypeInstallation,
 		ID:        installation.ID,
+		OwnerID:   installation.OwnerID,
 		NewState:  installation.State,
 		OldState:  oldState,
 		Timestamp: time.Now().UnixNano(),
 		ExtraData: map[string]string{"DNS": installation.DNS, "Environment": s.environment},
 	}
 
+	recordStateChangeEvent(s.store, webhookPayload, s.instanceID, nil, logger)
 	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
 	if err != nil {
 		logger.WithError(err).Error("Unable to process and send webhooks")
 	}
 
-	return model.InstallationDBRestorationStateSucceeded
+	return model.InstallationDBRestorationStateSucceeded, nil
 }
 
-func (s *InstallationDBRestorationSupervisor) failRestoration(restoration *model.InstallationDBRestorationOperation, instanceID string, logger log.FieldLogger) model.InstallationDBRestorationState {
+func (s *InstallationDBRestorationSupervisor) failRestoration(restoration *model.InstallationDBRestorationOperation, instanceID string, logger log.FieldLogger) (model.InstallationDBRestorationState, error) {
 	installation, lock, err := getAndLockInstallation(s.store, restoration.InstallationID, instanceID, logger)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get and lock installation")
-		return restoration.State
+		return restoration.State, nil
 	}
 	defer lock.Unlock()
 
@@ -310,22 +384,24 @@ func (s *InstallationDBRestorationSupervisor) failRestoration(restoration *model
 	err = s.store.UpdateInstallation(installation)
 	if err != nil {
 		logger.WithError(err).Error("Failed to set installation to failed DB restoration state")
-		return restoration.State
+		return restoration.State, nil
 	}
 
 	webhookPayload := &model.WebhookPayload{
 		Type:      model.TypeInstallation,
 		ID:        installation.ID,
+		OwnerID:   installation.OwnerID,
 		NewState:  installation.State,
 		OldState:  oldState,
 		Timestamp: time.Now().UnixNano(),
 		ExtraData: map[string]string{"DNS": installation.DNS, "Environment": s.environment},
 	}
 
+	recordStateChangeEvent(s.store, webhookPayload, s.instanceID, nil, logger)
 	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
 	if err != nil {
 		logger.WithError(err).Error("Unable to process and send webhooks")
 	}
 
-	return model.InstallationDBRestorationStateFailed
+	return model.InstallationDBRestorationStateFailed, nil
 }
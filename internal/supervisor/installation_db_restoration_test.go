@@ -15,6 +15,7 @@ import (
 	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/pborman/uuid"
 	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -26,6 +27,8 @@ type mockRestorationStore struct {
 	UnlockChan                       chan interface{}
 
 	UpdateRestorationOperationCalls int
+
+	mockMultitenantDBStore
 }
 
 func (m *mockRestorationStore) GetUnlockedInstallationDBRestorationOperationsPendingWork() ([]*model.InstallationDBRestorationOperation, error) {
@@ -109,6 +112,18 @@ func (m *mockRestorationStore) GetWebhooks(filter *model.WebhookFilter) ([]*mode
 	return nil, nil
 }
 
+func (m *mockRestorationStore) CreateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	return nil
+}
+
+func (m *mockRestorationStore) UpdateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	return nil
+}
+
+func (m *mockRestorationStore) CreateEvent(event *model.Event) error {
+	return nil
+}
+
 type mockRestoreProvisioner struct {
 	RestoreCompleteTime int64
 	err                 error
@@ -118,20 +133,38 @@ func (p *mockRestoreProvisioner) TriggerRestore(installation *model.Installation
 	return p.err
 }
 
-func (p *mockRestoreProvisioner) CheckRestoreStatus(backupMeta *model.InstallationBackup, cluster *model.Cluster) (int64, error) {
+func (p *mockRestoreProvisioner) CheckRestoreStatus(installation *model.Installation, backupMeta *model.InstallationBackup, cluster *model.Cluster) (int64, error) {
 	return p.RestoreCompleteTime, p.err
 }
 
-func (p *mockRestoreProvisioner) CleanupRestoreJob(backup *model.InstallationBackup, cluster *model.Cluster) error {
+func (p *mockRestoreProvisioner) CleanupRestoreJob(installation *model.Installation, backup *model.InstallationBackup, cluster *model.Cluster) error {
 	return p.err
 }
 
+type mockPointInTimeDatabase struct {
+	mockDatabase
+	ready bool
+	err   error
+}
+
+func (d *mockPointInTimeDatabase) RestoreToPointInTime(store model.InstallationDatabaseStoreInterface, sourceInstallationID string, restoreTime int64, logger log.FieldLogger) (bool, error) {
+	return d.ready, d.err
+}
+
+type mockRestorationDatabaseProvider struct {
+	database model.Database
+}
+
+func (p *mockRestorationDatabaseProvider) GetDatabaseForInstallation(installation *model.Installation) model.Database {
+	return p.database
+}
+
 func TestInstallationDBRestorationSupervisor_Do(t *testing.T) {
 	t.Run("no installation restoration operations pending work", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		mockStore := &mockRestorationStore{}
 
-		restorationSupervisor := supervisor.NewInstallationDBRestorationSupervisor(mockStore, &mockAWS{}, &mockRestoreProvisioner{}, "instanceID", logger)
+		restorationSupervisor := supervisor.NewInstallationDBRestorationSupervisor(mockStore, &mockAWS{}, &mockResourceUtil{}, &mockRestoreProvisioner{}, "instanceID", logger)
 		err := restorationSupervisor.Do()
 		require.NoError(t, err)
 
@@ -156,7 +189,7 @@ func TestInstallationDBRestorationSupervisor_Do(t *testing.T) {
 			UnlockChan:                       make(chan interface{}),
 		}
 
-		restorationSupervisor := supervisor.NewInstallationDBRestorationSupervisor(mockStore, &mockAWS{}, &mockRestoreProvisioner{}, "instanceID", logger)
+		restorationSupervisor := supervisor.NewInstallationDBRestorationSupervisor(mockStore, &mockAWS{}, &mockResourceUtil{}, &mockRestoreProvisioner{}, "instanceID", logger)
 		err := restorationSupervisor.Do()
 		require.NoError(t, err)
 
@@ -185,7 +218,7 @@ func TestInstallationDBRestorationSupervisor_Supervise(t *testing.T) {
 		err := sqlStore.CreateInstallationDBRestorationOperation(restorationOp)
 		require.NoError(t, err)
 
-		restorationSupervisor := supervisor.NewInstallationDBRestorationSupervisor(sqlStore, &mockAWS{}, mockRestoreOp, "instanceID", logger)
+		restorationSupervisor := supervisor.NewInstallationDBRestorationSupervisor(sqlStore, &mockAWS{}, &mockResourceUtil{}, mockRestoreOp, "instanceID", logger)
 		restorationSupervisor.Supervise(restorationOp)
 
 		// Assert
@@ -242,7 +275,7 @@ func TestInstallationDBRestorationSupervisor_Supervise(t *testing.T) {
 				err := sqlStore.CreateInstallationDBRestorationOperation(restorationOp)
 				require.NoError(t, err)
 
-				restorationSupervisor := supervisor.NewInstallationDBRestorationSupervisor(sqlStore, &mockAWS{}, testCase.mockRestoreOp, "instanceID", logger)
+				restorationSupervisor := supervisor.NewInstallationDBRestorationSupervisor(sqlStore, &mockAWS{}, &mockResourceUtil{}, testCase.mockRestoreOp, "instanceID", logger)
 				restorationSupervisor.Supervise(restorationOp)
 
 				// Assert
@@ -254,6 +287,78 @@ func TestInstallationDBRestorationSupervisor_Supervise(t *testing.T) {
 		}
 	})
 
+	t.Run("point-in-time restoration", func(t *testing.T) {
+		for _, testCase := range []struct {
+			description   string
+			database      model.Database
+			state         model.InstallationDBRestorationState
+			expectedState model.InstallationDBRestorationState
+		}{
+			{
+				description:   "when restore started",
+				database:      &mockPointInTimeDatabase{},
+				state:         model.InstallationDBRestorationStateRequested,
+				expectedState: model.InstallationDBRestorationStateInProgress,
+			},
+			{
+				description:   "when restore finished",
+				database:      &mockPointInTimeDatabase{ready: true},
+				state:         model.InstallationDBRestorationStateInProgress,
+				expectedState: model.InstallationDBRestorationStateFinalizing,
+			},
+			{
+				description:   "when error",
+				database:      &mockPointInTimeDatabase{err: errors.New("some error")},
+				state:         model.InstallationDBRestorationStateRequested,
+				expectedState: model.InstallationDBRestorationStateRequested,
+			},
+			{
+				description:   "when restore not possible",
+				database:      &mockPointInTimeDatabase{err: errors.Wrap(model.ErrPointInTimeRestoreNotPossible, "restore time outside of backup window")},
+				state:         model.InstallationDBRestorationStateInProgress,
+				expectedState: model.InstallationDBRestorationStateFailing,
+			},
+			{
+				description:   "when not supported by database",
+				database:      &mockDatabase{},
+				state:         model.InstallationDBRestorationStateRequested,
+				expectedState: model.InstallationDBRestorationStateFailing,
+			},
+		} {
+			t.Run(testCase.description, func(t *testing.T) {
+				logger := testlib.MakeLogger(t)
+				sqlStore := store.MakeTestSQLStore(t, logger)
+				defer store.CloseConnection(t, sqlStore)
+
+				installation, _, _ := setupRestoreRequiredResources(t, sqlStore)
+
+				restorationOp := &model.InstallationDBRestorationOperation{
+					InstallationID:          installation.ID,
+					Mode:                    model.InstallationDBRestorationModePointInTime,
+					SourceInstallationID:    installation.ID,
+					RestoreTime:             100,
+					State:                   testCase.state,
+					TargetInstallationState: model.InstallationStateHibernating,
+				}
+				err := sqlStore.CreateInstallationDBRestorationOperation(restorationOp)
+				require.NoError(t, err)
+
+				dbProvider := &mockRestorationDatabaseProvider{database: testCase.database}
+				restorationSupervisor := supervisor.NewInstallationDBRestorationSupervisor(sqlStore, &mockAWS{}, dbProvider, &mockRestoreProvisioner{}, "instanceID", logger)
+				restorationSupervisor.Supervise(restorationOp)
+
+				// Assert
+				restorationOp, err = sqlStore.GetInstallationDBRestorationOperation(restorationOp.ID)
+				require.NoError(t, err)
+				assert.Equal(t, testCase.expectedState, restorationOp.State)
+				assert.Empty(t, restorationOp.ClusterInstallationID)
+				if testCase.expectedState == model.InstallationDBRestorationStateFinalizing {
+					assert.NotZero(t, restorationOp.CompleteAt)
+				}
+			})
+		}
+	})
+
 	t.Run("finalizing restoration", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
@@ -273,7 +378,7 @@ func TestInstallationDBRestorationSupervisor_Supervise(t *testing.T) {
 		err := sqlStore.CreateInstallationDBRestorationOperation(restorationOp)
 		require.NoError(t, err)
 
-		restorationSupervisor := supervisor.NewInstallationDBRestorationSupervisor(sqlStore, &mockAWS{}, mockRestoreOp, "instanceID", logger)
+		restorationSupervisor := supervisor.NewInstallationDBRestorationSupervisor(sqlStore, &mockAWS{}, &mockResourceUtil{}, mockRestoreOp, "instanceID", logger)
 		restorationSupervisor.Supervise(restorationOp)
 
 		// Assert
@@ -305,7 +410,7 @@ func TestInstallationDBRestorationSupervisor_Supervise(t *testing.T) {
 		err := sqlStore.CreateInstallationDBRestorationOperation(restorationOp)
 		require.NoError(t, err)
 
-		restorationSupervisor := supervisor.NewInstallationDBRestorationSupervisor(sqlStore, &mockAWS{}, mockRestoreOp, "instanceID", logger)
+		restorationSupervisor := supervisor.NewInstallationDBRestorationSupervisor(sqlStore, &mockAWS{}, &mockResourceUtil{}, mockRestoreOp, "instanceID", logger)
 		restorationSupervisor.Supervise(restorationOp)
 
 		// Assert
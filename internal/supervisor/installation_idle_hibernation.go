@@ -0,0 +1,332 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/tools/cloud"
+	"github.com/mattermost/mattermost-cloud/internal/webhook"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// idleHibernationStore abstracts the database operations required by the idle hibernation supervisor.
+type idleHibernationStore interface {
+	GetInstallation(installationID string, includeGroupConfig, includeGroupConfigOverrides bool) (*model.Installation, error)
+	GetInstallations(filter *model.InstallationFilter, includeGroupConfig, includeGroupConfigOverrides bool) ([]*model.Installation, error)
+	UpdateInstallationState(installation *model.Installation) error
+	UpdateInstallationHibernationNotice(installationID string, noticeAt int64) error
+	GetAnnotationsForInstallation(installationID string) ([]*model.Annotation, error)
+	installationLockStore
+
+	GetClusterInstallations(filter *model.ClusterInstallationFilter) ([]*model.ClusterInstallation, error)
+	GetCluster(id string) (*model.Cluster, error)
+	GetEvents(filter *model.EventFilter) ([]*model.Event, error)
+
+	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+	CreateEvent(event *model.Event) error
+}
+
+// idleHibernationProvisioner abstracts the provisioning operations required by the idle hibernation supervisor.
+type idleHibernationProvisioner interface {
+	ExecClusterInstallationCLI(cluster *model.Cluster, clusterInstallation *model.ClusterInstallation, args ...string) ([]byte, error)
+}
+
+// IdleHibernationOptions are the various options that control when idle
+// installations are hibernated.
+type IdleHibernationOptions struct {
+	idlePeriod    time.Duration
+	noticePeriod  time.Duration
+	checkInterval time.Duration
+}
+
+// NewIdleHibernationOptions creates a new IdleHibernationOptions.
+func NewIdleHibernationOptions(idlePeriod, noticePeriod, checkInterval time.Duration) IdleHibernationOptions {
+	return IdleHibernationOptions{
+		idlePeriod:    idlePeriod,
+		noticePeriod:  noticePeriod,
+		checkInterval: checkInterval,
+	}
+}
+
+// IdleHibernationSupervisor periodically checks the user activity of stable
+// installations and hibernates the installations which had no active users
+// for the idle period.
+//
+// A webhook notice is sent once an installation has been idle for the idle
+// period minus the notice period. The installation is only hibernated when
+// it is still idle after both the idle period and the notice period have
+// passed, giving the owner a chance to use the installation or to opt it out
+// with the disable-idle-hibernation annotation.
+type IdleHibernationSupervisor struct {
+	store       idleHibernationStore
+	provisioner idleHibernationProvisioner
+	cloud       cloud.Provider
+	instanceID  string
+	options     IdleHibernationOptions
+	logger      log.FieldLogger
+
+	nextCheckAt int64
+}
+
+// NewIdleHibernationSupervisor creates a new IdleHibernationSupervisor.
+func NewIdleHibernationSupervisor(store idleHibernationStore, provisioner idleHibernationProvisioner, cloud cloud.Provider, instanceID string, options IdleHibernationOptions, logger log.FieldLogger) *IdleHibernationSupervisor {
+	return &IdleHibernationSupervisor{
+		store:       store,
+		provisioner: provisioner,
+		cloud:       cloud,
+		instanceID:  instanceID,
+		options:     options,
+		logger:      logger,
+	}
+}
+
+// Shutdown performs graceful shutdown tasks for the idle hibernation supervisor.
+func (s *IdleHibernationSupervisor) Shutdown() {
+	s.logger.Debug("Shutting down idle hibernation supervisor")
+}
+
+// Do checks the activity of all stable installations once per check interval.
+func (s *IdleHibernationSupervisor) Do() error {
+	now := store.GetMillis()
+	if now < s.nextCheckAt {
+		return nil
+	}
+	s.nextCheckAt = now + s.options.checkInterval.Milliseconds()
+
+	installations, err := s.store.GetInstallations(&model.InstallationFilter{
+		State:  model.InstallationStateStable,
+		Paging: model.AllPagesNotDeleted(),
+	}, false, false)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to query for stable installations")
+		return errors.Wrap(err, "failed to query for stable installations")
+	}
+
+	for _, installation := range installations {
+		s.Supervise(installation)
+	}
+
+	return nil
+}
+
+// Supervise checks the activity of the given installation and notifies about
+// or requests its hibernation when it is idle.
+func (s *IdleHibernationSupervisor) Supervise(installation *model.Installation) {
+	logger := s.logger.WithFields(log.Fields{
+		"installation": installation.ID,
+	})
+
+	annotations, err := s.store.GetAnnotationsForInstallation(installation.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get installation annotations")
+		return
+	}
+	for _, annotation := range annotations {
+		if annotation.Name == model.AnnotationDisableIdleHibernation {
+			s.clearHibernationNotice(installation, logger)
+			return
+		}
+	}
+
+	lastActivityAt, err := s.getLastActivityAt(installation)
+	if err != nil {
+		logger.WithError(err).Error("Failed to determine installation activity")
+		return
+	}
+
+	now := store.GetMillis()
+	idleFor := now - lastActivityAt
+	idlePeriod := s.options.idlePeriod.Milliseconds()
+	noticePeriod := s.options.noticePeriod.Milliseconds()
+
+	if idleFor < idlePeriod-noticePeriod {
+		s.clearHibernationNotice(installation, logger)
+		return
+	}
+
+	if installation.HibernationNoticeAt == 0 {
+		hibernateAt := lastActivityAt + idlePeriod
+		if hibernateAt < now+noticePeriod {
+			hibernateAt = now + noticePeriod
+		}
+		s.sendHibernationNotice(installation, lastActivityAt, hibernateAt, logger)
+		return
+	}
+
+	if idleFor < idlePeriod || now-installation.HibernationNoticeAt < noticePeriod {
+		return
+	}
+
+	s.hibernateInstallation(installation, lastActivityAt, logger)
+}
+
+// mattermostUser is the subset of the user fields returned by mmctl which
+// describe the user activity.
+type mattermostUser struct {
+	LastActivityAt int64 `json:"last_activity_at"`
+	LastLogin      int64 `json:"last_login"`
+}
+
+// getLastActivityAt returns the last time in milliseconds the installation
+// was active. Changes of the installation state, such as a wake up, count as
+// activity too.
+func (s *IdleHibernationSupervisor) getLastActivityAt(installation *model.Installation) (int64, error) {
+	lastActivityAt := installation.CreateAt
+
+	events, err := s.store.GetEvents(&model.EventFilter{
+		Paging:       model.Paging{Page: 0, PerPage: 1},
+		ResourceType: model.TypeInstallation,
+		ResourceID:   installation.ID,
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get installation events")
+	}
+	if len(events) > 0 && events[0].Timestamp > lastActivityAt {
+		lastActivityAt = events[0].Timestamp
+	}
+
+	clusterInstallations, err := s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
+		InstallationID: installation.ID,
+		Paging:         model.AllPagesNotDeleted(),
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get cluster installations")
+	}
+	if len(clusterInstallations) == 0 {
+		return 0, errors.New("installation has no cluster installations")
+	}
+
+	clusterInstallation := clusterInstallations[0]
+	cluster, err := s.store.GetCluster(clusterInstallation.ClusterID)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get cluster")
+	}
+	if cluster == nil {
+		return 0, errors.Errorf("cluster %s not found", clusterInstallation.ClusterID)
+	}
+
+	output, err := s.provisioner.ExecClusterInstallationCLI(cluster, clusterInstallation, "mmctl", "--format", "json", "--local", "user", "list", "--all")
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to list installation users")
+	}
+
+	var users []mattermostUser
+	err = json.Unmarshal(output, &users)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to parse installation users")
+	}
+	for _, user := range users {
+		if user.LastActivityAt > lastActivityAt {
+			lastActivityAt = user.LastActivityAt
+		}
+		if user.LastLogin > lastActivityAt {
+			lastActivityAt = user.LastLogin
+		}
+	}
+
+	return lastActivityAt, nil
+}
+
+// sendHibernationNotice notifies the webhooks that the installation will be
+// hibernated at the given time unless it becomes active again.
+func (s *IdleHibernationSupervisor) sendHibernationNotice(installation *model.Installation, lastActivityAt, hibernateAt int64, logger log.FieldLogger) {
+	err := s.store.UpdateInstallationHibernationNotice(installation.ID, store.GetMillis())
+	if err != nil {
+		logger.WithError(err).Error("Failed to record hibernation notice")
+		return
+	}
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeInstallationHibernationNotice,
+		ID:        installation.ID,
+		OwnerID:   installation.OwnerID,
+		NewState:  model.InstallationStateHibernationRequested,
+		OldState:  installation.State,
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{
+			"DNS":            installation.DNS,
+			"Environment":    s.cloud.GetCloudEnvironmentName(),
+			"LastActivityAt": strconv.FormatInt(lastActivityAt, 10),
+			"HibernateAt":    strconv.FormatInt(hibernateAt, 10),
+		},
+	}
+	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.Type))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	logger.Infof("Installation is idle and will be hibernated at %s", time.Unix(0, hibernateAt*int64(time.Millisecond)).UTC())
+}
+
+// clearHibernationNotice cancels a previously sent hibernation notice.
+func (s *IdleHibernationSupervisor) clearHibernationNotice(installation *model.Installation, logger log.FieldLogger) {
+	if installation.HibernationNoticeAt == 0 {
+		return
+	}
+
+	err := s.store.UpdateInstallationHibernationNotice(installation.ID, 0)
+	if err != nil {
+		logger.WithError(err).Error("Failed to clear hibernation notice")
+		return
+	}
+
+	logger.Info("Installation is no longer subject to idle hibernation")
+}
+
+// hibernateInstallation requests the hibernation of the idle installation.
+func (s *IdleHibernationSupervisor) hibernateInstallation(installation *model.Installation, lastActivityAt int64, logger log.FieldLogger) {
+	originalNoticeAt := installation.HibernationNoticeAt
+	installation, lock, err := getAndLockInstallation(s.store, installation.ID, s.instanceID, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get and lock installation for hibernation")
+		return
+	}
+	defer lock.Unlock()
+
+	// Ensure that the installation was not changed by another provisioning
+	// server since its activity was checked.
+	if installation.State != model.InstallationStateStable || installation.HibernationNoticeAt != originalNoticeAt {
+		logger.Warn("Another provisioner has worked on this installation; skipping...")
+		return
+	}
+
+	oldState := installation.State
+	installation.State = model.InstallationStateHibernationRequested
+	err = s.store.UpdateInstallationState(installation)
+	if err != nil {
+		logger.WithError(err).Error("Failed to request installation hibernation")
+		return
+	}
+
+	err = s.store.UpdateInstallationHibernationNotice(installation.ID, 0)
+	if err != nil {
+		logger.WithError(err).Error("Failed to clear hibernation notice")
+	}
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeInstallation,
+		ID:        installation.ID,
+		OwnerID:   installation.OwnerID,
+		NewState:  installation.State,
+		OldState:  oldState,
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"DNS": installation.DNS, "Environment": s.cloud.GetCloudEnvironmentName()},
+	}
+	recordStateChangeEvent(s.store, webhookPayload, s.instanceID, nil, logger)
+	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	logger.Infof("Requested hibernation of installation idle since %s", time.Unix(0, lastActivityAt*int64(time.Millisecond)).UTC())
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/internal/tools/cloud"
+	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockIdleHibernationProvisioner struct {
+	active bool
+}
+
+func (p *mockIdleHibernationProvisioner) ExecClusterInstallationCLI(cluster *model.Cluster, clusterInstallation *model.ClusterInstallation, args ...string) ([]byte, error) {
+	if !p.active {
+		return []byte(`[{"last_activity_at": 0}]`), nil
+	}
+
+	return []byte(fmt.Sprintf(`[{"last_activity_at": %d}]`, store.GetMillis())), nil
+}
+
+func TestIdleHibernationSupervisor(t *testing.T) {
+	options := supervisor.NewIdleHibernationOptions(100*time.Millisecond, 50*time.Millisecond, time.Hour)
+
+	setupInstallation := func(t *testing.T, sqlStore *store.SQLStore, annotations []*model.Annotation) *model.Installation {
+		cluster := &model.Cluster{State: model.ClusterStateStable}
+		err := sqlStore.CreateCluster(cluster, nil)
+		require.NoError(t, err)
+
+		installation := &model.Installation{
+			OwnerID: model.NewID(),
+			DNS:     fmt.Sprintf("dns-%s.example.com", model.NewID()),
+			State:   model.InstallationStateStable,
+		}
+		err = sqlStore.CreateInstallation(installation, annotations)
+		require.NoError(t, err)
+
+		err = sqlStore.CreateClusterInstallation(&model.ClusterInstallation{
+			ClusterID:      cluster.ID,
+			InstallationID: installation.ID,
+			Namespace:      installation.ID,
+			State:          model.ClusterInstallationStateStable,
+		})
+		require.NoError(t, err)
+
+		return installation
+	}
+
+	getInstallation := func(t *testing.T, sqlStore *store.SQLStore, installationID string) *model.Installation {
+		installation, err := sqlStore.GetInstallation(installationID, false, false)
+		require.NoError(t, err)
+		return installation
+	}
+
+	t.Run("hibernate idle installation after notice", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		installation := setupInstallation(t, sqlStore, nil)
+		idleSupervisor := supervisor.NewIdleHibernationSupervisor(sqlStore, &mockIdleHibernationProvisioner{}, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", options, logger)
+
+		err := idleSupervisor.Do()
+		require.NoError(t, err)
+		installation = getInstallation(t, sqlStore, installation.ID)
+		assert.Equal(t, model.InstallationStateStable, installation.State)
+		assert.Equal(t, int64(0), installation.HibernationNoticeAt)
+
+		time.Sleep(60 * time.Millisecond)
+		idleSupervisor.Supervise(installation)
+		installation = getInstallation(t, sqlStore, installation.ID)
+		assert.Equal(t, model.InstallationStateStable, installation.State)
+		assert.NotEqual(t, int64(0), installation.HibernationNoticeAt)
+
+		idleSupervisor.Supervise(installation)
+		installation = getInstallation(t, sqlStore, installation.ID)
+		assert.Equal(t, model.InstallationStateStable, installation.State)
+
+		time.Sleep(60 * time.Millisecond)
+		idleSupervisor.Supervise(installation)
+		installation = getInstallation(t, sqlStore, installation.ID)
+		assert.Equal(t, model.InstallationStateHibernationRequested, installation.State)
+		assert.Equal(t, int64(0), installation.HibernationNoticeAt)
+
+		events, err := sqlStore.GetEvents(&model.EventFilter{
+			Paging:     model.AllPagesWithDeleted(),
+			ResourceID: installation.ID,
+			NewState:   model.InstallationStateHibernationRequested,
+		})
+		require.NoError(t, err)
+		assert.Len(t, events, 1)
+	})
+
+	t.Run("active installation", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		installation := setupInstallation(t, sqlStore, nil)
+		err := sqlStore.UpdateInstallationHibernationNotice(installation.ID, store.GetMillis())
+		require.NoError(t, err)
+		idleSupervisor := supervisor.NewIdleHibernationSupervisor(sqlStore, &mockIdleHibernationProvisioner{active: true}, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", options, logger)
+
+		time.Sleep(120 * time.Millisecond)
+		err = idleSupervisor.Do()
+		require.NoError(t, err)
+
+		installation = getInstallation(t, sqlStore, installation.ID)
+		assert.Equal(t, model.InstallationStateStable, installation.State)
+		assert.Equal(t, int64(0), installation.HibernationNoticeAt)
+	})
+
+	t.Run("opted out installation", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		installation := setupInstallation(t, sqlStore, []*model.Annotation{{Name: model.AnnotationDisableIdleHibernation}})
+		idleSupervisor := supervisor.NewIdleHibernationSupervisor(sqlStore, &mockIdleHibernationProvisioner{}, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", options, logger)
+
+		time.Sleep(120 * time.Millisecond)
+		idleSupervisor.Supervise(installation)
+		idleSupervisor.Supervise(getInstallation(t, sqlStore, installation.ID))
+
+		installation = getInstallation(t, sqlStore, installation.ID)
+		assert.Equal(t, model.InstallationStateStable, installation.State)
+		assert.Equal(t, int64(0), installation.HibernationNoticeAt)
+	})
+}
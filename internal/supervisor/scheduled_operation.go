@@ -0,0 +1,168 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/tools/cloud"
+	"github.com/mattermost/mattermost-cloud/internal/webhook"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// scheduledOperationStore abstracts the database operations required by the scheduled operation supervisor.
+type scheduledOperationStore interface {
+	GetUnlockedScheduledOperationsPendingWork() ([]*model.ScheduledOperation, error)
+	GetScheduledOperation(id string) (*model.ScheduledOperation, error)
+	UpdateScheduledOperationRun(operation *model.ScheduledOperation) error
+	scheduledOperationLockStore
+
+	installationStateChangeStore
+}
+
+// ScheduledOperationSupervisor requests the installation state changes of
+// scheduled operations when they are due.
+//
+// Operations on installations which are being worked on are retried on the
+// next pass. Operations which cannot be applied to the installation in its
+// current state, for example waking up an installation which is not
+// hibernating, fail. Recurring operations are scheduled again regardless of
+// the outcome of their run.
+type ScheduledOperationSupervisor struct {
+	store      scheduledOperationStore
+	cloud      cloud.Provider
+	instanceID string
+	logger     log.FieldLogger
+}
+
+// NewScheduledOperationSupervisor creates a new ScheduledOperationSupervisor.
+func NewScheduledOperationSupervisor(store scheduledOperationStore, cloud cloud.Provider, instanceID string, logger log.FieldLogger) *ScheduledOperationSupervisor {
+	return &ScheduledOperationSupervisor{
+		store:      store,
+		cloud:      cloud,
+		instanceID: instanceID,
+		logger:     logger,
+	}
+}
+
+// Shutdown performs graceful shutdown tasks for the scheduled operation supervisor.
+func (s *ScheduledOperationSupervisor) Shutdown() {
+	s.logger.Debug("Shutting down scheduled operation supervisor")
+}
+
+// Do looks for due scheduled operations and runs them.
+func (s *ScheduledOperationSupervisor) Do() error {
+	operations, err := s.store.GetUnlockedScheduledOperationsPendingWork()
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to query for scheduled operations pending work")
+		return errors.Wrap(err, "failed to query for scheduled operations pending work")
+	}
+
+	for _, operation := range operations {
+		s.Supervise(operation)
+	}
+
+	return nil
+}
+
+// Supervise runs the given scheduled operation if it is due.
+func (s *ScheduledOperationSupervisor) Supervise(operation *model.ScheduledOperation) {
+	logger := s.logger.WithFields(log.Fields{
+		"scheduledOperation": operation.ID,
+		"installation":       operation.InstallationID,
+	})
+
+	lock := newScheduledOperationLock(operation.ID, s.instanceID, s.store, logger)
+	if !lock.TryLock() {
+		return
+	}
+	defer lock.Unlock()
+
+	// Before working on the operation, it is crucial that we ensure that it
+	// was not already worked on by another provisioning server.
+	originalRunAt := operation.RunAt
+	operation, err := s.store.GetScheduledOperation(operation.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get refreshed scheduled operation")
+		return
+	}
+	if operation == nil || operation.IsDeleted() {
+		logger.Debug("Scheduled operation was cancelled; skipping...")
+		return
+	}
+	if operation.RunAt != originalRunAt || !operation.IsDue(store.GetMillis()) {
+		logger.Warn("Another provisioner has worked on this scheduled operation; skipping...")
+		return
+	}
+
+	retry, runErr := s.run(operation, logger)
+	if retry {
+		return
+	}
+
+	now := store.GetMillis()
+	oldState := operation.State
+	operation.LastRunAt = now
+	operation.Error = ""
+	if runErr != nil {
+		operation.Error = runErr.Error()
+		logger.WithError(runErr).Warn("Scheduled operation failed")
+	}
+	if operation.IsRecurring() {
+		operation.RunAt = operation.NextRunAt(now)
+	} else if runErr != nil {
+		operation.State = model.ScheduledOperationStateFailed
+	} else {
+		operation.State = model.ScheduledOperationStateSucceeded
+	}
+
+	err = s.store.UpdateScheduledOperationRun(operation)
+	if err != nil {
+		logger.WithError(err).Error("Failed to update scheduled operation run")
+		return
+	}
+
+	if operation.State == oldState {
+		return
+	}
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeScheduledOperation,
+		ID:        operation.ID,
+		OwnerID:   installationOwnerID(s.store, operation.InstallationID, logger),
+		NewState:  string(operation.State),
+		OldState:  string(oldState),
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"Installation": operation.InstallationID, "Environment": s.cloud.GetCloudEnvironmentName()},
+	}
+	recordStateChangeEvent(s.store, webhookPayload, s.instanceID, runErr, logger)
+	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+}
+
+// run requests the installation state change of the scheduled operation.
+// It returns true if the operation should be retried on the next pass.
+func (s *ScheduledOperationSupervisor) run(operation *model.ScheduledOperation, logger log.FieldLogger) (bool, error) {
+	installation, installationLock, retry, err := lockInstallationForStateChange(s.store, operation.InstallationID, s.instanceID, logger)
+	if retry || err != nil {
+		return retry, err
+	}
+	defer installationLock.Unlock()
+
+	newState := operation.Type.InstallationState()
+	requested, retry, err := requestInstallationStateChange(s.store, installation, newState, operation.Patch, s.instanceID, s.cloud.GetCloudEnvironmentName(), logger)
+	if !requested {
+		return retry, err
+	}
+
+	logger.Infof("Scheduled operation requested installation state %q", newState)
+
+	return false, nil
+}
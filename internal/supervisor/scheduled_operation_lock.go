@@ -0,0 +1,49 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	log "github.com/sirupsen/logrus"
+)
+
+type scheduledOperationLockStore interface {
+	LockScheduledOperation(operationID, lockerID string) (bool, error)
+	UnlockScheduledOperation(operationID, lockerID string, force bool) (bool, error)
+}
+
+type scheduledOperationLock struct {
+	operationID string
+	lockerID    string
+	store       scheduledOperationLockStore
+	logger      log.FieldLogger
+}
+
+func newScheduledOperationLock(operationID, lockerID string, store scheduledOperationLockStore, logger log.FieldLogger) *scheduledOperationLock {
+	return &scheduledOperationLock{
+		operationID: operationID,
+		lockerID:    lockerID,
+		store:       store,
+		logger:      logger,
+	}
+}
+
+func (l *scheduledOperationLock) TryLock() bool {
+	locked, err := l.store.LockScheduledOperation(l.operationID, l.lockerID)
+	if err != nil {
+		l.logger.WithError(err).Error("failed to lock scheduled operation")
+		return false
+	}
+
+	return locked
+}
+
+func (l *scheduledOperationLock) Unlock() {
+	unlocked, err := l.store.UnlockScheduledOperation(l.operationID, l.lockerID, false)
+	if err != nil {
+		l.logger.WithError(err).Error("failed to unlock scheduled operation")
+	} else if unlocked != true {
+		l.logger.Error("failed to release lock for scheduled operation")
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/internal/tools/cloud"
+	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduledOperationSupervisor(t *testing.T) {
+	setupInstallation := func(t *testing.T, sqlStore *store.SQLStore, state string) *model.Installation {
+		installation := &model.Installation{
+			OwnerID: model.NewID(),
+			Version: "5.38.0",
+			DNS:     model.NewID() + ".example.com",
+			State:   state,
+		}
+		err := sqlStore.CreateInstallation(installation, nil)
+		require.NoError(t, err)
+
+		return installation
+	}
+
+	setupOperation := func(t *testing.T, sqlStore *store.SQLStore, operation *model.ScheduledOperation) *model.ScheduledOperation {
+		if operation.RunAt == 0 {
+			operation.RunAt = store.GetMillis()
+		}
+		err := sqlStore.CreateScheduledOperation(operation)
+		require.NoError(t, err)
+
+		return operation
+	}
+
+	getOperation := func(t *testing.T, sqlStore *store.SQLStore, operationID string) *model.ScheduledOperation {
+		operation, err := sqlStore.GetScheduledOperation(operationID)
+		require.NoError(t, err)
+		return operation
+	}
+
+	getInstallation := func(t *testing.T, sqlStore *store.SQLStore, installationID string) *model.Installation {
+		installation, err := sqlStore.GetInstallation(installationID, false, false)
+		require.NoError(t, err)
+		return installation
+	}
+
+	t.Run("one-time hibernation", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		installation := setupInstallation(t, sqlStore, model.InstallationStateStable)
+		operation := setupOperation(t, sqlStore, &model.ScheduledOperation{
+			InstallationID: installation.ID,
+			Type:           model.ScheduledOperationTypeHibernate,
+		})
+
+		operationSupervisor := supervisor.NewScheduledOperationSupervisor(sqlStore, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
+		err := operationSupervisor.Do()
+		require.NoError(t, err)
+
+		assert.Equal(t, model.InstallationStateHibernationRequested, getInstallation(t, sqlStore, installation.ID).State)
+		operation = getOperation(t, sqlStore, operation.ID)
+		assert.Equal(t, model.ScheduledOperationStateSucceeded, operation.State)
+		assert.NotZero(t, operation.LastRunAt)
+		assert.Empty(t, operation.Error)
+	})
+
+	t.Run("recurring wake up", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		installation := setupInstallation(t, sqlStore, model.InstallationStateHibernating)
+		operation := setupOperation(t, sqlStore, &model.ScheduledOperation{
+			InstallationID:  installation.ID,
+			Type:            model.ScheduledOperationTypeWakeUp,
+			IntervalSeconds: 86400,
+		})
+		originalRunAt := operation.RunAt
+
+		operationSupervisor := supervisor.NewScheduledOperationSupervisor(sqlStore, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
+		err := operationSupervisor.Do()
+		require.NoError(t, err)
+
+		assert.Equal(t, model.InstallationStateWakeUpRequested, getInstallation(t, sqlStore, installation.ID).State)
+		operation = getOperation(t, sqlStore, operation.ID)
+		assert.Equal(t, model.ScheduledOperationStateScheduled, operation.State)
+		assert.Equal(t, originalRunAt+86400*1000, operation.RunAt)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		version := "5.39.0"
+		installation := setupInstallation(t, sqlStore, model.InstallationStateStable)
+		operation := setupOperation(t, sqlStore, &model.ScheduledOperation{
+			InstallationID: installation.ID,
+			Type:           model.ScheduledOperationTypeUpdate,
+			Patch:          &model.PatchInstallationRequest{Version: &version},
+		})
+
+		operationSupervisor := supervisor.NewScheduledOperationSupervisor(sqlStore, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
+		err := operationSupervisor.Do()
+		require.NoError(t, err)
+
+		installation = getInstallation(t, sqlStore, installation.ID)
+		assert.Equal(t, model.InstallationStateUpdateRequested, installation.State)
+		assert.Equal(t, version, installation.Version)
+		assert.Equal(t, model.ScheduledOperationStateSucceeded, getOperation(t, sqlStore, operation.ID).State)
+	})
+
+	t.Run("retry while installation is being worked on", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		installation := setupInstallation(t, sqlStore, model.InstallationStateUpdateInProgress)
+		operation := setupOperation(t, sqlStore, &model.ScheduledOperation{
+			InstallationID: installation.ID,
+			Type:           model.ScheduledOperationTypeHibernate,
+		})
+
+		operationSupervisor := supervisor.NewScheduledOperationSupervisor(sqlStore, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
+		err := operationSupervisor.Do()
+		require.NoError(t, err)
+
+		assert.Equal(t, model.InstallationStateUpdateInProgress, getInstallation(t, sqlStore, installation.ID).State)
+		operation = getOperation(t, sqlStore, operation.ID)
+		assert.Equal(t, model.ScheduledOperationStateScheduled, operation.State)
+		assert.Zero(t, operation.LastRunAt)
+	})
+
+	t.Run("invalid transition", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		installation := setupInstallation(t, sqlStore, model.InstallationStateStable)
+		operation := setupOperation(t, sqlStore, &model.ScheduledOperation{
+			InstallationID: installation.ID,
+			Type:           model.ScheduledOperationTypeWakeUp,
+		})
+
+		operationSupervisor := supervisor.NewScheduledOperationSupervisor(sqlStore, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
+		err := operationSupervisor.Do()
+		require.NoError(t, err)
+
+		assert.Equal(t, model.InstallationStateStable, getInstallation(t, sqlStore, installation.ID).State)
+		operation = getOperation(t, sqlStore, operation.ID)
+		assert.Equal(t, model.ScheduledOperationStateFailed, operation.State)
+		assert.NotEmpty(t, operation.Error)
+
+		events, err := sqlStore.GetEvents(&model.EventFilter{
+			Paging:       model.AllPagesWithDeleted(),
+			ResourceType: model.TypeScheduledOperation,
+			ResourceID:   operation.ID,
+		})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, operation.Error, events[0].Error)
+	})
+
+	t.Run("not due yet", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		defer store.CloseConnection(t, sqlStore)
+
+		installation := setupInstallation(t, sqlStore, model.InstallationStateStable)
+		operation := setupOperation(t, sqlStore, &model.ScheduledOperation{
+			InstallationID: installation.ID,
+			Type:           model.ScheduledOperationTypeDelete,
+			RunAt:          store.GetMillis() + 60*60*1000,
+		})
+
+		operationSupervisor := supervisor.NewScheduledOperationSupervisor(sqlStore, cloud.NewAWSProvider(&mockAWS{}, &utils.ResourceUtil{}), "instanceID", logger)
+		err := operationSupervisor.Do()
+		require.NoError(t, err)
+
+		assert.Equal(t, model.InstallationStateStable, getInstallation(t, sqlStore, installation.ID).State)
+		assert.Equal(t, model.ScheduledOperationStateScheduled, getOperation(t, sqlStore, operation.ID).State)
+	})
+}
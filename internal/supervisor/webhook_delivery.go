@@ -0,0 +1,146 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
+	"github.com/mattermost/mattermost-cloud/internal/webhook"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// webhookDeliveryStore abstracts the database operations required by the webhook delivery supervisor.
+type webhookDeliveryStore interface {
+	GetUnlockedWebhookDeliveriesPendingWork() ([]*model.WebhookDelivery, error)
+	GetWebhookDelivery(id string) (*model.WebhookDelivery, error)
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+	DeleteWebhookDeliveries(createdBefore int64) (int64, error)
+	webhookDeliveryLockStore
+
+	GetWebhook(id string) (*model.Webhook, error)
+}
+
+// WebhookDeliverySupervisor finds pending webhook deliveries and retries them
+// until they succeed or exceed the maximum delivery age. Finished deliveries
+// are deleted once they are older than the retention period.
+type WebhookDeliverySupervisor struct {
+	store      webhookDeliveryStore
+	maxAge     time.Duration
+	retention  time.Duration
+	instanceID string
+	logger     log.FieldLogger
+}
+
+// NewWebhookDeliverySupervisor creates a new WebhookDeliverySupervisor.
+func NewWebhookDeliverySupervisor(store webhookDeliveryStore, maxAge, retention time.Duration, instanceID string, logger log.FieldLogger) *WebhookDeliverySupervisor {
+	return &WebhookDeliverySupervisor{
+		store:      store,
+		maxAge:     maxAge,
+		retention:  retention,
+		instanceID: instanceID,
+		logger:     logger,
+	}
+}
+
+// Shutdown performs graceful shutdown tasks for the webhook delivery supervisor.
+func (s *WebhookDeliverySupervisor) Shutdown() {
+	s.logger.Debug("Shutting down webhook delivery supervisor")
+}
+
+// Do looks for webhook deliveries due for another attempt and retries them.
+func (s *WebhookDeliverySupervisor) Do() error {
+	deliveries, err := s.store.GetUnlockedWebhookDeliveriesPendingWork()
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to query for webhook deliveries pending work")
+		return errors.Wrap(err, "failed to query for webhook deliveries pending work")
+	}
+
+	for _, delivery := range deliveries {
+		s.Supervise(delivery)
+	}
+
+	s.pruneDeliveries()
+
+	return nil
+}
+
+// pruneDeliveries deletes delivered and failed webhook deliveries older than
+// the retention period.
+func (s *WebhookDeliverySupervisor) pruneDeliveries() {
+	deleted, err := s.store.DeleteWebhookDeliveries(utils.GetMillisAtTime(time.Now().Add(-s.retention)))
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to delete old webhook deliveries")
+		return
+	}
+	if deleted > 0 {
+		s.logger.Debugf("Deleted %d webhook deliveries older than %s", deleted, s.retention)
+	}
+}
+
+// Supervise makes another delivery attempt of the given webhook delivery.
+func (s *WebhookDeliverySupervisor) Supervise(delivery *model.WebhookDelivery) {
+	logger := s.logger.WithFields(log.Fields{
+		"webhookDelivery": delivery.ID,
+		"webhook":         delivery.WebhookID,
+	})
+
+	lock := newWebhookDeliveryLock(delivery.ID, s.instanceID, s.store, logger)
+	if !lock.TryLock() {
+		return
+	}
+	defer lock.Unlock()
+
+	// Before working on the delivery, it is crucial that we ensure that it
+	// was not already attempted by another provisioning server.
+	originalAttempts := delivery.Attempts
+	delivery, err := s.store.GetWebhookDelivery(delivery.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get refreshed webhook delivery")
+		return
+	}
+	if delivery.Attempts != originalAttempts || !delivery.IsPending() {
+		logger.Warn("Another provisioner has worked on this webhook delivery; skipping...")
+		return
+	}
+
+	s.attemptDelivery(delivery, logger)
+
+	err = s.store.UpdateWebhookDelivery(delivery)
+	if err != nil {
+		logger.WithError(err).Error("Failed to update webhook delivery")
+		return
+	}
+
+	logger.Debugf("Webhook delivery attempt %d finished in state %s", delivery.Attempts, delivery.State)
+}
+
+func (s *WebhookDeliverySupervisor) attemptDelivery(delivery *model.WebhookDelivery, logger log.FieldLogger) {
+	hook, err := s.store.GetWebhook(delivery.WebhookID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get webhook")
+		return
+	}
+	if hook == nil || hook.IsDeleted() {
+		logger.Warn("Webhook no longer exists; abandoning delivery")
+		delivery.State = model.WebhookDeliveryStateFailed
+		delivery.LastError = "webhook was deleted"
+		return
+	}
+
+	deadline := utils.TimeFromMillis(delivery.CreateAt).Add(s.maxAge)
+
+	err = webhook.Deliver(hook, delivery, logger)
+	if err == nil {
+		return
+	}
+
+	if utils.TimeFromMillis(delivery.NextAttemptAt).After(deadline) {
+		logger.WithError(err).Warnf("Webhook delivery failed after %d attempts and exceeded max age of %s; abandoning delivery", delivery.Attempts, s.maxAge)
+		delivery.State = model.WebhookDeliveryStateFailed
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	log "github.com/sirupsen/logrus"
+)
+
+type webhookDeliveryLockStore interface {
+	LockWebhookDelivery(deliveryID, lockerID string) (bool, error)
+	UnlockWebhookDelivery(deliveryID, lockerID string, force bool) (bool, error)
+}
+
+type webhookDeliveryLock struct {
+	deliveryID string
+	lockerID   string
+	store      webhookDeliveryLockStore
+	logger     log.FieldLogger
+}
+
+func newWebhookDeliveryLock(deliveryID, lockerID string, store webhookDeliveryLockStore, logger log.FieldLogger) *webhookDeliveryLock {
+	return &webhookDeliveryLock{
+		deliveryID: deliveryID,
+		lockerID:   lockerID,
+		store:      store,
+		logger:     logger,
+	}
+}
+
+func (l *webhookDeliveryLock) TryLock() bool {
+	locked, err := l.store.LockWebhookDelivery(l.deliveryID, l.lockerID)
+	if err != nil {
+		l.logger.WithError(err).Error("failed to lock webhook delivery")
+		return false
+	}
+
+	return locked
+}
+
+func (l *webhookDeliveryLock) Unlock() {
+	unlocked, err := l.store.UnlockWebhookDelivery(l.deliveryID, l.lockerID, false)
+	if err != nil {
+		l.logger.WithError(err).Error("failed to unlock webhook delivery")
+	} else if unlocked != true {
+		l.logger.Error("failed to release lock for webhook delivery")
+	}
+}
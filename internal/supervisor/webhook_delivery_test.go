@@ -0,0 +1,147 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDeliverySupervisor(t *testing.T) {
+	payload := &model.WebhookPayload{
+		Timestamp: time.Now().UnixNano(),
+		ID:        model.NewID(),
+		Type:      model.TypeCluster,
+		NewState:  model.ClusterStateStable,
+		OldState:  model.ClusterStateCreationRequested,
+	}
+
+	setup := func(t *testing.T, statusCode int) (*store.SQLStore, *model.Webhook) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(statusCode)
+		}))
+		t.Cleanup(ts.Close)
+
+		sqlStore := store.MakeTestSQLStore(t, testlib.MakeLogger(t))
+		webhook := &model.Webhook{OwnerID: "owner", URL: ts.URL}
+		err := sqlStore.CreateWebhook(webhook)
+		require.NoError(t, err)
+
+		return sqlStore, webhook
+	}
+
+	t.Run("successful retry", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore, webhook := setup(t, http.StatusOK)
+
+		delivery := &model.WebhookDelivery{WebhookID: webhook.ID, Payload: payload, Attempts: 1}
+		err := sqlStore.CreateWebhookDelivery(delivery)
+		require.NoError(t, err)
+
+		deliverySupervisor := supervisor.NewWebhookDeliverySupervisor(sqlStore, time.Hour, time.Hour, "instanceID", logger)
+		err = deliverySupervisor.Do()
+		require.NoError(t, err)
+
+		delivery, err = sqlStore.GetWebhookDelivery(delivery.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.WebhookDeliveryStateDelivered, delivery.State)
+		assert.Equal(t, int64(2), delivery.Attempts)
+		assert.Equal(t, int64(http.StatusOK), delivery.LastStatusCode)
+	})
+
+	t.Run("failed retry", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore, webhook := setup(t, http.StatusInternalServerError)
+
+		delivery := &model.WebhookDelivery{WebhookID: webhook.ID, Payload: payload}
+		err := sqlStore.CreateWebhookDelivery(delivery)
+		require.NoError(t, err)
+
+		deliverySupervisor := supervisor.NewWebhookDeliverySupervisor(sqlStore, time.Hour, time.Hour, "instanceID", logger)
+		deliverySupervisor.Supervise(delivery)
+
+		delivery, err = sqlStore.GetWebhookDelivery(delivery.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.WebhookDeliveryStatePending, delivery.State)
+		assert.Equal(t, int64(1), delivery.Attempts)
+		assert.Equal(t, int64(http.StatusInternalServerError), delivery.LastStatusCode)
+		assert.True(t, delivery.NextAttemptAt > store.GetMillis())
+
+		pending, err := sqlStore.GetUnlockedWebhookDeliveriesPendingWork()
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+	})
+
+	t.Run("exceeded max age", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore, webhook := setup(t, http.StatusInternalServerError)
+
+		delivery := &model.WebhookDelivery{WebhookID: webhook.ID, Payload: payload}
+		err := sqlStore.CreateWebhookDelivery(delivery)
+		require.NoError(t, err)
+
+		deliverySupervisor := supervisor.NewWebhookDeliverySupervisor(sqlStore, time.Second, time.Hour, "instanceID", logger)
+		deliverySupervisor.Supervise(delivery)
+
+		delivery, err = sqlStore.GetWebhookDelivery(delivery.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.WebhookDeliveryStateFailed, delivery.State)
+		assert.Equal(t, int64(1), delivery.Attempts)
+	})
+
+	t.Run("deleted webhook", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore, webhook := setup(t, http.StatusOK)
+
+		delivery := &model.WebhookDelivery{WebhookID: webhook.ID, Payload: payload}
+		err := sqlStore.CreateWebhookDelivery(delivery)
+		require.NoError(t, err)
+		err = sqlStore.DeleteWebhook(webhook.ID)
+		require.NoError(t, err)
+
+		deliverySupervisor := supervisor.NewWebhookDeliverySupervisor(sqlStore, time.Hour, time.Hour, "instanceID", logger)
+		deliverySupervisor.Supervise(delivery)
+
+		delivery, err = sqlStore.GetWebhookDelivery(delivery.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.WebhookDeliveryStateFailed, delivery.State)
+		assert.Equal(t, int64(0), delivery.Attempts)
+	})
+
+	t.Run("delete old deliveries", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore, webhook := setup(t, http.StatusOK)
+
+		delivered := &model.WebhookDelivery{WebhookID: webhook.ID, Payload: payload, State: model.WebhookDeliveryStateDelivered}
+		err := sqlStore.CreateWebhookDelivery(delivered)
+		require.NoError(t, err)
+		pending := &model.WebhookDelivery{WebhookID: webhook.ID, Payload: payload, NextAttemptAt: store.GetMillis() + 60*1000}
+		err = sqlStore.CreateWebhookDelivery(pending)
+		require.NoError(t, err)
+
+		time.Sleep(2 * time.Millisecond)
+
+		deliverySupervisor := supervisor.NewWebhookDeliverySupervisor(sqlStore, time.Hour, time.Millisecond, "instanceID", logger)
+		err = deliverySupervisor.Do()
+		require.NoError(t, err)
+
+		delivery, err := sqlStore.GetWebhookDelivery(delivered.ID)
+		require.NoError(t, err)
+		assert.Nil(t, delivery)
+
+		delivery, err = sqlStore.GetWebhookDelivery(pending.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, delivery)
+	})
+}
@@ -71,7 +71,9 @@ type AWS interface {
 	DynamoDBEnsureTableDeleted(tableName string, logger log.FieldLogger) error
 	S3EnsureBucketDeleted(bucketName string, logger log.FieldLogger) error
 	S3EnsureObjectDeleted(bucketName, path string) error
+	S3EnsureBucketDirectoryDeleted(bucketName, directory string, logger log.FieldLogger) error
 	S3LargeCopy(srcBucketName, srcKey, destBucketName, destKey *string) error
+	S3CopyBucketDirectory(srcBucketName, srcPrefix, destBucketName, destPrefix string, logger log.FieldLogger) error
 	GetMultitenantBucketNameForInstallation(installationID string, store model.InstallationDatabaseStoreInterface) (string, error)
 
 	GenerateBifrostUtilitySecret(clusterID string, logger log.FieldLogger) (*corev1.Secret, error)
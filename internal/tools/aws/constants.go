@@ -183,6 +183,10 @@ const (
 	// of a cluster installation.
 	DefaultClusterInstallationSnapshotTagKey = "tag:ClusterInstallationSnapshot"
 
+	// RDSPointInTimeRestoreTagKey is used for tagging RDS DB clusters
+	// restored to a point in time with the source and time of the restore.
+	RDSPointInTimeRestoreTagKey = "tag:PointInTimeRestore"
+
 	// DefaultAWSClientRetries supplies how many time the AWS client will
 	// retry a failed call.
 	DefaultAWSClientRetries = 3
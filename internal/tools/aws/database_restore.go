@@ -0,0 +1,321 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package aws
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// RestoreToPointInTime replaces the RDS DB cluster of the installation with
+// the DB cluster of the source installation as it was at the given time.
+//
+// RDS always restores to a new DB cluster, so the restoration is done in steps
+// which are resumed on every call until the restored DB cluster is ready:
+//  1. The source DB cluster is restored to a temporary DB cluster in the
+//     network of the installation DB cluster.
+//  2. The DB instances of the installation are created in the restored DB
+//     cluster under temporary names.
+//  3. Once the restored DB instances are available, the installation DB
+//     cluster is deleted, keeping a final snapshot.
+//  4. The restored DB cluster is renamed to the installation DB cluster and
+//     its master password is set to the one of the installation.
+//  5. The restored DB instances are renamed to the installation DB instances.
+//
+// Errors which cannot be resolved by retrying are caused by
+// model.ErrPointInTimeRestoreNotPossible.
+func (d *RDSDatabase) RestoreToPointInTime(store model.InstallationDatabaseStoreInterface, sourceInstallationID string, restoreTime int64, logger log.FieldLogger) (bool, error) {
+	awsID := CloudID(d.installationID)
+	restoredAWSID := fmt.Sprintf("%s-restore", awsID)
+	restoreTagValue := fmt.Sprintf("%s-%d", CloudID(sourceInstallationID), restoreTime)
+
+	logger = logger.WithFields(log.Fields{
+		"db-cluster-name":          awsID,
+		"restored-db-cluster-name": restoredAWSID,
+		"database-type":            d.databaseType,
+	})
+
+	installationCluster, err := d.client.rdsGetDBCluster(awsID)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get installation DB cluster")
+	}
+	restoredCluster, err := d.client.rdsGetDBCluster(restoredAWSID)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get restored DB cluster")
+	}
+
+	if restoredCluster == nil {
+		if installationCluster == nil {
+			return false, errors.Wrap(model.ErrPointInTimeRestoreNotPossible, "installation DB cluster not found")
+		}
+		if rdsHasTag(installationCluster.TagList, trimTagPrefix(RDSPointInTimeRestoreTagKey), restoreTagValue) {
+			if *installationCluster.Status != DefaultRDSStatusAvailable {
+				logger.Debugf("Restored DB cluster is in %s state", *installationCluster.Status)
+				return false, nil
+			}
+
+			ready, err := d.ensureDBClusterInstancesRenamed(store, restoredAWSID, awsID, logger)
+			if err != nil {
+				return false, errors.Wrap(err, "failed to rename restored DB instances")
+			}
+
+			return ready, nil
+		}
+
+		err = d.restoreDBClusterToPointInTime(installationCluster, CloudID(sourceInstallationID), restoredAWSID, restoreTime, restoreTagValue)
+		if err != nil {
+			if !isRetryableRestoreError(err) {
+				return false, errors.Wrap(model.ErrPointInTimeRestoreNotPossible, err.Error())
+			}
+			return false, errors.Wrap(err, "failed to restore DB cluster to point in time")
+		}
+		logger.Info("Restoring DB cluster to point in time")
+
+		return false, nil
+	}
+
+	if *restoredCluster.Status != DefaultRDSStatusAvailable {
+		logger.Debugf("Restored DB cluster is in %s state", *restoredCluster.Status)
+		return false, nil
+	}
+
+	// The installation DB cluster is only deleted once the restored DB
+	// cluster can serve the installation.
+	ready, err := d.ensureDBClusterInstancesCreated(store, restoredAWSID, restoredAWSID, logger)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to create restored DB instances")
+	}
+	if !ready {
+		logger.Debug("Restored DB instances are not available yet")
+		return false, nil
+	}
+
+	if installationCluster != nil {
+		if *installationCluster.Status == "deleting" {
+			logger.Debug("Installation DB cluster is being deleted")
+			return false, nil
+		}
+
+		err = d.deleteDBClusterWithSnapshot(installationCluster, logger)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to delete installation DB cluster")
+		}
+
+		return false, nil
+	}
+
+	installationSecret, err := d.client.secretsManagerGetRDSSecret(awsID, logger)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get installation RDS secret")
+	}
+
+	_, err = d.client.Service().rds.ModifyDBCluster(&rds.ModifyDBClusterInput{
+		DBClusterIdentifier:    aws.String(restoredAWSID),
+		NewDBClusterIdentifier: aws.String(awsID),
+		MasterUserPassword:     aws.String(installationSecret.MasterPassword),
+		ApplyImmediately:       aws.Bool(true),
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to rename restored DB cluster")
+	}
+	logger.Info("Renaming restored DB cluster to installation DB cluster")
+
+	return false, nil
+}
+
+// isRetryableRestoreError returns whether restoring a DB cluster to a point
+// in time may succeed when retried after failing with the given error.
+func isRetryableRestoreError(err error) bool {
+	aerr, ok := err.(awserr.Error)
+	if !ok {
+		return true
+	}
+
+	switch aerr.Code() {
+	case rds.ErrCodeInvalidRestoreFault,
+		rds.ErrCodeDBClusterNotFoundFault,
+		rds.ErrCodeDBSubnetGroupNotFoundFault,
+		rds.ErrCodeInvalidSubnet,
+		rds.ErrCodeInvalidVPCNetworkStateFault,
+		rds.ErrCodeKMSKeyNotAccessibleFault:
+		return false
+	}
+
+	return true
+}
+
+func (d *RDSDatabase) restoreDBClusterToPointInTime(installationCluster *rds.DBCluster, sourceAWSID, restoredAWSID string, restoreTime int64, restoreTagValue string) error {
+	var securityGroupIDs []*string
+	for _, securityGroup := range installationCluster.VpcSecurityGroups {
+		securityGroupIDs = append(securityGroupIDs, securityGroup.VpcSecurityGroupId)
+	}
+
+	_, err := d.client.Service().rds.RestoreDBClusterToPointInTime(&rds.RestoreDBClusterToPointInTimeInput{
+		SourceDBClusterIdentifier: aws.String(sourceAWSID),
+		DBClusterIdentifier:       aws.String(restoredAWSID),
+		RestoreToTime:             aws.Time(time.Unix(0, restoreTime*int64(time.Millisecond))),
+		DBSubnetGroupName:         installationCluster.DBSubnetGroup,
+		VpcSecurityGroupIds:       securityGroupIDs,
+		KmsKeyId:                  installationCluster.KmsKeyId,
+		Tags: []*rds.Tag{
+			{
+				Key:   aws.String(trimTagPrefix(RDSPointInTimeRestoreTagKey)),
+				Value: aws.String(restoreTagValue),
+			},
+		},
+	})
+
+	return err
+}
+
+func (d *RDSDatabase) deleteDBClusterWithSnapshot(dbCluster *rds.DBCluster, logger log.FieldLogger) error {
+	for _, instance := range dbCluster.DBClusterMembers {
+		_, err := d.client.Service().rds.DeleteDBInstance(&rds.DeleteDBInstanceInput{
+			DBInstanceIdentifier: instance.DBInstanceIdentifier,
+			SkipFinalSnapshot:    aws.Bool(true),
+		})
+		if err != nil {
+			if aerr, ok := err.(awserr.Error); ok && aerr.Code() == rds.ErrCodeInvalidDBInstanceStateFault {
+				continue
+			}
+			return errors.Wrap(err, "unable to delete DB cluster instance")
+		}
+		logger.WithField("db-instance-name", *instance.DBInstanceIdentifier).Debug("DB instance deleted")
+	}
+
+	snapshotID := fmt.Sprintf("%s-before-restore-%d", *dbCluster.DBClusterIdentifier, time.Now().Unix())
+	_, err := d.client.Service().rds.DeleteDBCluster(&rds.DeleteDBClusterInput{
+		DBClusterIdentifier:       dbCluster.DBClusterIdentifier,
+		FinalDBSnapshotIdentifier: aws.String(snapshotID),
+	})
+	if err != nil {
+		return errors.Wrap(err, "unable to delete DB cluster")
+	}
+
+	logger.WithField("db-snapshot-name", snapshotID).Info("Installation DB cluster deleted")
+
+	return nil
+}
+
+// dbClusterInstanceClasses returns the instance classes of the primary and
+// replica instances of the installation by their names with the given prefix.
+func (d *RDSDatabase) dbClusterInstanceClasses(store model.InstallationDatabaseStoreInterface, prefix string) (map[string]string, error) {
+	dbConfig, err := store.GetSingleTenantDatabaseConfigForInstallation(d.installationID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get single tenant database config for installation")
+	}
+	if dbConfig == nil {
+		return nil, errors.New("single tenant database not found for installation")
+	}
+
+	instances := map[string]string{fmt.Sprintf("%s-master", prefix): dbConfig.PrimaryInstanceType}
+	for i := 0; i < dbConfig.ReplicasCount; i++ {
+		instances[fmt.Sprintf("%s-replica-%d", prefix, i)] = dbConfig.ReplicaInstanceType
+	}
+
+	return instances, nil
+}
+
+// ensureDBClusterInstancesCreated creates the primary and replica instances
+// of the installation in the given DB cluster, named with the given prefix,
+// and returns whether all of them are available.
+func (d *RDSDatabase) ensureDBClusterInstancesCreated(store model.InstallationDatabaseStoreInterface, dbClusterID, prefix string, logger log.FieldLogger) (bool, error) {
+	instances, err := d.dbClusterInstanceClasses(store, prefix)
+	if err != nil {
+		return false, err
+	}
+
+	dbEngine, err := dbEngineFromType(d.databaseType)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to convert database type to database engine")
+	}
+
+	ready := true
+	for instanceName, instanceClass := range instances {
+		err = d.client.rdsEnsureDBClusterInstanceCreated(dbClusterID, instanceName, dbEngine, instanceClass, logger)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to ensure DB instance %s was created", instanceName)
+		}
+
+		instance, err := d.client.rdsGetDBInstance(instanceName)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to describe DB instance %s", instanceName)
+		}
+		if instance == nil || *instance.DBInstanceStatus != DefaultRDSStatusAvailable {
+			ready = false
+		}
+	}
+
+	return ready, nil
+}
+
+// ensureDBClusterInstancesRenamed renames the primary and replica instances
+// of the installation from the given prefix to the new prefix and returns
+// whether all renamed instances are available.
+func (d *RDSDatabase) ensureDBClusterInstancesRenamed(store model.InstallationDatabaseStoreInterface, prefix, newPrefix string, logger log.FieldLogger) (bool, error) {
+	instances, err := d.dbClusterInstanceClasses(store, prefix)
+	if err != nil {
+		return false, err
+	}
+
+	ready := true
+	for instanceName := range instances {
+		newInstanceName := newPrefix + strings.TrimPrefix(instanceName, prefix)
+
+		instance, err := d.client.rdsGetDBInstance(newInstanceName)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to describe DB instance %s", newInstanceName)
+		}
+		if instance != nil {
+			if *instance.DBInstanceStatus != DefaultRDSStatusAvailable {
+				ready = false
+			}
+			continue
+		}
+
+		ready = false
+		instance, err = d.client.rdsGetDBInstance(instanceName)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to describe DB instance %s", instanceName)
+		}
+		if instance == nil {
+			// The instance is being renamed.
+			continue
+		}
+		if *instance.DBInstanceStatus != DefaultRDSStatusAvailable {
+			continue
+		}
+
+		_, err = d.client.Service().rds.ModifyDBInstance(&rds.ModifyDBInstanceInput{
+			DBInstanceIdentifier:    aws.String(instanceName),
+			NewDBInstanceIdentifier: aws.String(newInstanceName),
+			ApplyImmediately:        aws.Bool(true),
+		})
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to rename DB instance %s", instanceName)
+		}
+		logger.WithField("db-instance-name", newInstanceName).Info("Renaming restored DB instance")
+	}
+
+	return ready, nil
+}
+
+func rdsHasTag(tags []*rds.Tag, key, value string) bool {
+	for _, tag := range tags {
+		if tag.Key != nil && tag.Value != nil && *tag.Key == key && *tag.Value == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,284 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package aws
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/golang/mock/gomock"
+	testlib "github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+func (a *AWSTestSuite) TestRestoreToPointInTimeStarted() {
+	database := RDSDatabase{
+		databaseType:   model.DatabaseEngineTypePostgres,
+		installationID: a.InstallationA.ID,
+		client:         a.Mocks.AWS,
+	}
+	awsID := CloudID(a.InstallationA.ID)
+	sourceInstallationID := model.NewID()
+	restoreTime := int64(1600000000000)
+
+	gomock.InOrder(
+		a.Mocks.Log.Logger.EXPECT().
+			WithFields(log.Fields{
+				"db-cluster-name":          awsID,
+				"restored-db-cluster-name": awsID + "-restore",
+				"database-type":            database.databaseType,
+			}).
+			Return(testlib.NewLoggerEntry()).
+			Times(1),
+
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClusters(&rds.DescribeDBClustersInput{DBClusterIdentifier: aws.String(awsID)}).
+			Return(&rds.DescribeDBClustersOutput{DBClusters: []*rds.DBCluster{{
+				DBClusterIdentifier: aws.String(awsID),
+				Status:              aws.String(DefaultRDSStatusAvailable),
+				DBSubnetGroup:       aws.String("subnet-group"),
+				KmsKeyId:            aws.String("kms-key"),
+				VpcSecurityGroups:   []*rds.VpcSecurityGroupMembership{{VpcSecurityGroupId: aws.String("sg-1")}},
+			}}}, nil).
+			Times(1),
+
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClusters(&rds.DescribeDBClustersInput{DBClusterIdentifier: aws.String(awsID + "-restore")}).
+			Return(nil, awserr.New(rds.ErrCodeDBClusterNotFoundFault, "not found", nil)).
+			Times(1),
+
+		a.Mocks.API.RDS.EXPECT().
+			RestoreDBClusterToPointInTime(gomock.Any()).
+			Do(func(input *rds.RestoreDBClusterToPointInTimeInput) {
+				a.Assert().Equal(CloudID(sourceInstallationID), *input.SourceDBClusterIdentifier)
+				a.Assert().Equal(awsID+"-restore", *input.DBClusterIdentifier)
+				a.Assert().Equal(restoreTime, input.RestoreToTime.UnixNano()/1000000)
+				a.Assert().Equal("subnet-group", *input.DBSubnetGroupName)
+				a.Assert().Equal("kms-key", *input.KmsKeyId)
+				a.Assert().Equal([]*string{aws.String("sg-1")}, input.VpcSecurityGroupIds)
+				a.Assert().Equal(fmt.Sprintf("%s-%d", CloudID(sourceInstallationID), restoreTime), *input.Tags[0].Value)
+			}).
+			Return(&rds.RestoreDBClusterToPointInTimeOutput{}, nil).
+			Times(1),
+	)
+
+	ready, err := database.RestoreToPointInTime(a.Mocks.AWS.store, sourceInstallationID, restoreTime, a.Mocks.Log.Logger)
+	a.Assert().NoError(err)
+	a.Assert().False(ready)
+}
+
+func (a *AWSTestSuite) TestRestoreToPointInTimeCompleted() {
+	database := RDSDatabase{
+		databaseType:   model.DatabaseEngineTypePostgres,
+		installationID: a.InstallationA.ID,
+		client:         a.Mocks.AWS,
+	}
+	awsID := CloudID(a.InstallationA.ID)
+	sourceInstallationID := model.NewID()
+	restoreTime := int64(1600000000000)
+
+	gomock.InOrder(
+		a.Mocks.Log.Logger.EXPECT().
+			WithFields(gomock.Any()).
+			Return(testlib.NewLoggerEntry()).
+			Times(1),
+
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClusters(&rds.DescribeDBClustersInput{DBClusterIdentifier: aws.String(awsID)}).
+			Return(&rds.DescribeDBClustersOutput{DBClusters: []*rds.DBCluster{{
+				DBClusterIdentifier: aws.String(awsID),
+				Status:              aws.String(DefaultRDSStatusAvailable),
+				TagList: []*rds.Tag{{
+					Key:   aws.String(trimTagPrefix(RDSPointInTimeRestoreTagKey)),
+					Value: aws.String(fmt.Sprintf("%s-%d", CloudID(sourceInstallationID), restoreTime)),
+				}},
+			}}}, nil).
+			Times(1),
+
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClusters(&rds.DescribeDBClustersInput{DBClusterIdentifier: aws.String(awsID + "-restore")}).
+			Return(nil, awserr.New(rds.ErrCodeDBClusterNotFoundFault, "not found", nil)).
+			Times(1),
+
+		a.Mocks.Model.DatabaseInstallationStore.EXPECT().GetSingleTenantDatabaseConfigForInstallation(a.InstallationA.ID).
+			Return(&model.SingleTenantDatabaseConfig{PrimaryInstanceType: "db.r5.large"}, nil).
+			Times(1),
+
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBInstances(&rds.DescribeDBInstancesInput{DBInstanceIdentifier: aws.String(awsID + "-master")}).
+			Return(&rds.DescribeDBInstancesOutput{DBInstances: []*rds.DBInstance{{
+				DBInstanceStatus: aws.String(DefaultRDSStatusAvailable),
+			}}}, nil).
+			Times(1),
+	)
+
+	ready, err := database.RestoreToPointInTime(a.Mocks.Model.DatabaseInstallationStore, sourceInstallationID, restoreTime, a.Mocks.Log.Logger)
+	a.Assert().NoError(err)
+	a.Assert().True(ready)
+}
+
+func (a *AWSTestSuite) TestRestoreToPointInTimeRenameInstances() {
+	database := RDSDatabase{
+		databaseType:   model.DatabaseEngineTypePostgres,
+		installationID: a.InstallationA.ID,
+		client:         a.Mocks.AWS,
+	}
+	awsID := CloudID(a.InstallationA.ID)
+	sourceInstallationID := model.NewID()
+	restoreTime := int64(1600000000000)
+
+	gomock.InOrder(
+		a.Mocks.Log.Logger.EXPECT().
+			WithFields(gomock.Any()).
+			Return(testlib.NewLoggerEntry()).
+			Times(1),
+
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClusters(&rds.DescribeDBClustersInput{DBClusterIdentifier: aws.String(awsID)}).
+			Return(&rds.DescribeDBClustersOutput{DBClusters: []*rds.DBCluster{{
+				DBClusterIdentifier: aws.String(awsID),
+				Status:              aws.String(DefaultRDSStatusAvailable),
+				TagList: []*rds.Tag{{
+					Key:   aws.String(trimTagPrefix(RDSPointInTimeRestoreTagKey)),
+					Value: aws.String(fmt.Sprintf("%s-%d", CloudID(sourceInstallationID), restoreTime)),
+				}},
+			}}}, nil).
+			Times(1),
+
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClusters(&rds.DescribeDBClustersInput{DBClusterIdentifier: aws.String(awsID + "-restore")}).
+			Return(nil, awserr.New(rds.ErrCodeDBClusterNotFoundFault, "not found", nil)).
+			Times(1),
+
+		a.Mocks.Model.DatabaseInstallationStore.EXPECT().GetSingleTenantDatabaseConfigForInstallation(a.InstallationA.ID).
+			Return(&model.SingleTenantDatabaseConfig{PrimaryInstanceType: "db.r5.large"}, nil).
+			Times(1),
+
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBInstances(&rds.DescribeDBInstancesInput{DBInstanceIdentifier: aws.String(awsID + "-master")}).
+			Return(nil, awserr.New(rds.ErrCodeDBInstanceNotFoundFault, "not found", nil)).
+			Times(1),
+
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBInstances(&rds.DescribeDBInstancesInput{DBInstanceIdentifier: aws.String(awsID + "-restore-master")}).
+			Return(&rds.DescribeDBInstancesOutput{DBInstances: []*rds.DBInstance{{
+				DBInstanceStatus: aws.String(DefaultRDSStatusAvailable),
+			}}}, nil).
+			Times(1),
+
+		a.Mocks.API.RDS.EXPECT().
+			ModifyDBInstance(&rds.ModifyDBInstanceInput{
+				DBInstanceIdentifier:    aws.String(awsID + "-restore-master"),
+				NewDBInstanceIdentifier: aws.String(awsID + "-master"),
+				ApplyImmediately:        aws.Bool(true),
+			}).
+			Return(&rds.ModifyDBInstanceOutput{}, nil).
+			Times(1),
+	)
+
+	ready, err := database.RestoreToPointInTime(a.Mocks.Model.DatabaseInstallationStore, sourceInstallationID, restoreTime, a.Mocks.Log.Logger)
+	a.Assert().NoError(err)
+	a.Assert().False(ready)
+}
+
+func (a *AWSTestSuite) TestRestoreToPointInTimeKeepsInstallationClusterUntilRestoredInstancesAvailable() {
+	database := RDSDatabase{
+		databaseType:   model.DatabaseEngineTypePostgres,
+		installationID: a.InstallationA.ID,
+		client:         a.Mocks.AWS,
+	}
+	awsID := CloudID(a.InstallationA.ID)
+
+	gomock.InOrder(
+		a.Mocks.Log.Logger.EXPECT().
+			WithFields(gomock.Any()).
+			Return(testlib.NewLoggerEntry()).
+			Times(1),
+
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClusters(&rds.DescribeDBClustersInput{DBClusterIdentifier: aws.String(awsID)}).
+			Return(&rds.DescribeDBClustersOutput{DBClusters: []*rds.DBCluster{{
+				DBClusterIdentifier: aws.String(awsID),
+				Status:              aws.String(DefaultRDSStatusAvailable),
+			}}}, nil).
+			Times(1),
+
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClusters(&rds.DescribeDBClustersInput{DBClusterIdentifier: aws.String(awsID + "-restore")}).
+			Return(&rds.DescribeDBClustersOutput{DBClusters: []*rds.DBCluster{{
+				DBClusterIdentifier: aws.String(awsID + "-restore"),
+				Status:              aws.String(DefaultRDSStatusAvailable),
+			}}}, nil).
+			Times(1),
+
+		a.Mocks.Model.DatabaseInstallationStore.EXPECT().GetSingleTenantDatabaseConfigForInstallation(a.InstallationA.ID).
+			Return(&model.SingleTenantDatabaseConfig{PrimaryInstanceType: "db.r5.large"}, nil).
+			Times(1),
+
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBInstances(&rds.DescribeDBInstancesInput{DBInstanceIdentifier: aws.String(awsID + "-restore-master")}).
+			Return(&rds.DescribeDBInstancesOutput{DBInstances: []*rds.DBInstance{{
+				DBInstanceStatus: aws.String("creating"),
+			}}}, nil).
+			Times(1),
+
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBInstances(&rds.DescribeDBInstancesInput{DBInstanceIdentifier: aws.String(awsID + "-restore-master")}).
+			Return(&rds.DescribeDBInstancesOutput{DBInstances: []*rds.DBInstance{{
+				DBInstanceStatus: aws.String("creating"),
+			}}}, nil).
+			Times(1),
+	)
+
+	// The installation DB cluster must not be deleted while the restored
+	// instances are not available.
+	a.Mocks.API.RDS.EXPECT().DeleteDBCluster(gomock.Any()).Times(0)
+
+	ready, err := database.RestoreToPointInTime(a.Mocks.Model.DatabaseInstallationStore, model.NewID(), 1600000000000, a.Mocks.Log.Logger)
+	a.Assert().NoError(err)
+	a.Assert().False(ready)
+}
+
+func (a *AWSTestSuite) TestRestoreToPointInTimeNotPossible() {
+	database := RDSDatabase{
+		databaseType:   model.DatabaseEngineTypePostgres,
+		installationID: a.InstallationA.ID,
+		client:         a.Mocks.AWS,
+	}
+	awsID := CloudID(a.InstallationA.ID)
+
+	gomock.InOrder(
+		a.Mocks.Log.Logger.EXPECT().
+			WithFields(gomock.Any()).
+			Return(testlib.NewLoggerEntry()).
+			Times(1),
+
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClusters(&rds.DescribeDBClustersInput{DBClusterIdentifier: aws.String(awsID)}).
+			Return(&rds.DescribeDBClustersOutput{DBClusters: []*rds.DBCluster{{
+				DBClusterIdentifier: aws.String(awsID),
+				Status:              aws.String(DefaultRDSStatusAvailable),
+			}}}, nil).
+			Times(1),
+
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClusters(&rds.DescribeDBClustersInput{DBClusterIdentifier: aws.String(awsID + "-restore")}).
+			Return(nil, awserr.New(rds.ErrCodeDBClusterNotFoundFault, "not found", nil)).
+			Times(1),
+
+		a.Mocks.API.RDS.EXPECT().
+			RestoreDBClusterToPointInTime(gomock.Any()).
+			Return(nil, awserr.New(rds.ErrCodeInvalidRestoreFault, "restore time is outside of the backup window", nil)).
+			Times(1),
+	)
+
+	_, err := database.RestoreToPointInTime(a.Mocks.Model.DatabaseInstallationStore, model.NewID(), 1600000000000, a.Mocks.Log.Logger)
+	a.Assert().Error(err)
+	a.Assert().Equal(model.ErrPointInTimeRestoreNotPossible, errors.Cause(err))
+}
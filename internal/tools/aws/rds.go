@@ -136,7 +136,7 @@ func (a *Client) rdsEnsureDBClusterCreated(
 
 	input := &rds.CreateDBClusterInput{
 		AvailabilityZones:     rdsAZs,
-		BackupRetentionPeriod: aws.Int64(7),
+		BackupRetentionPeriod: aws.Int64(model.SingleTenantDatabaseBackupRetentionDays),
 		DBClusterIdentifier:   aws.String(awsID),
 		DatabaseName:          aws.String("mattermost"),
 		EngineMode:            aws.String("provisioned"),
@@ -234,3 +234,41 @@ func (a *Client) rdsEnsureDBClusterDeleted(awsID string, logger log.FieldLogger)
 
 	return nil
 }
+
+// rdsGetDBCluster returns the DB cluster with the given ID or nil if it does
+// not exist.
+func (a *Client) rdsGetDBCluster(awsID string) (*rds.DBCluster, error) {
+	result, err := a.Service().rds.DescribeDBClusters(&rds.DescribeDBClustersInput{
+		DBClusterIdentifier: aws.String(awsID),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == rds.ErrCodeDBClusterNotFoundFault {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if len(result.DBClusters) != 1 {
+		return nil, fmt.Errorf("expected 1 DB cluster, but got %d", len(result.DBClusters))
+	}
+
+	return result.DBClusters[0], nil
+}
+
+func (a *Client) rdsGetDBInstance(instanceName string) (*rds.DBInstance, error) {
+	result, err := a.Service().rds.DescribeDBInstances(&rds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: aws.String(instanceName),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == rds.ErrCodeDBInstanceNotFoundFault {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if len(result.DBInstances) != 1 {
+		return nil, fmt.Errorf("expected 1 DB instance, but got %d", len(result.DBInstances))
+	}
+
+	return result.DBInstances[0], nil
+}
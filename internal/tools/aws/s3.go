@@ -7,6 +7,7 @@ package aws
 import (
 	"fmt"
 	"math"
+	"net/url"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -108,6 +109,55 @@ func (a *Client) S3EnsureBucketDirectoryDeleted(bucketName, directory string, lo
 	return nil
 }
 
+// s3MaxCopyObjectSize is the largest object which can be copied with a
+// single CopyObject request.
+const s3MaxCopyObjectSize = 5 * 1024 * 1024 * 1024
+
+// S3CopyBucketDirectory copies every object whose key starts with srcPrefix
+// to destBucketName, replacing srcPrefix with destPrefix in the keys.
+func (a *Client) S3CopyBucketDirectory(srcBucketName, srcPrefix, destBucketName, destPrefix string, logger log.FieldLogger) error {
+	var copyErr error
+	var copied int
+	err := a.Service().s3.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: aws.String(srcBucketName),
+		Prefix: aws.String(srcPrefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, object := range page.Contents {
+			destKey := destPrefix + strings.TrimPrefix(*object.Key, srcPrefix)
+			if aws.Int64Value(object.Size) > s3MaxCopyObjectSize {
+				copyErr = a.S3LargeCopy(aws.String(srcBucketName), object.Key, aws.String(destBucketName), aws.String(destKey))
+			} else {
+				_, copyErr = a.Service().s3.CopyObject(&s3.CopyObjectInput{
+					Bucket:     aws.String(destBucketName),
+					Key:        aws.String(destKey),
+					CopySource: aws.String(s3CopySource(srcBucketName, *object.Key)),
+				})
+			}
+			if copyErr != nil {
+				copyErr = errors.Wrapf(copyErr, "failed to copy object %s", *object.Key)
+				return false
+			}
+			copied++
+		}
+		return true
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to list bucket directory")
+	}
+	if copyErr != nil {
+		return copyErr
+	}
+
+	logger.WithField("s3-bucket-name", destBucketName).Debugf("Copied %d objects from %s/%s to %s", copied, srcBucketName, srcPrefix, destPrefix)
+
+	return nil
+}
+
+// s3CopySource returns the URL-encoded source of a copy request.
+func s3CopySource(bucketName, key string) string {
+	return (&url.URL{Path: bucketName + "/" + key}).EscapedPath()
+}
+
 // S3LargeCopy uses the "Upload Part - Copy API" from AWS to copy
 // srcBucketName/srcBucketKey to destBucketName/destBucketKey in the
 // case that the file being copied may be greater than 5GB in size
@@ -0,0 +1,77 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/pkg/errors"
+)
+
+func (a *AWSTestSuite) TestS3CopyBucketDirectory() {
+	a.Mocks.API.S3.EXPECT().
+		ListObjectsV2Pages(&s3.ListObjectsV2Input{
+			Bucket: aws.String("source-bucket"),
+			Prefix: aws.String("installation-1/backup-1"),
+		}, gomock.Any()).
+		DoAndReturn(func(input *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool) error {
+			fn(&s3.ListObjectsV2Output{
+				Contents: []*s3.Object{
+					{Key: aws.String("installation-1/backup-1"), Size: aws.Int64(10)},
+					{Key: aws.String("installation-1/backup-1-filestore/data/file name.txt"), Size: aws.Int64(0)},
+				},
+			}, true)
+			return nil
+		}).
+		Times(1)
+
+	a.Mocks.API.S3.EXPECT().
+		CopyObject(&s3.CopyObjectInput{
+			Bucket:     aws.String("destination-bucket"),
+			Key:        aws.String("installation-2/backup-1"),
+			CopySource: aws.String("source-bucket/installation-1/backup-1"),
+		}).
+		Return(&s3.CopyObjectOutput{}, nil).
+		Times(1)
+
+	a.Mocks.API.S3.EXPECT().
+		CopyObject(&s3.CopyObjectInput{
+			Bucket:     aws.String("destination-bucket"),
+			Key:        aws.String("installation-2/backup-1-filestore/data/file name.txt"),
+			CopySource: aws.String("source-bucket/installation-1/backup-1-filestore/data/file%20name.txt"),
+		}).
+		Return(&s3.CopyObjectOutput{}, nil).
+		Times(1)
+
+	a.Mocks.Log.Logger.EXPECT().
+		WithField("s3-bucket-name", "destination-bucket").
+		Return(testlib.NewLoggerEntry()).
+		Times(1)
+
+	err := a.Mocks.AWS.S3CopyBucketDirectory("source-bucket", "installation-1/backup-1", "destination-bucket", "installation-2/backup-1", a.Mocks.Log.Logger)
+	a.Assert().NoError(err)
+}
+
+func (a *AWSTestSuite) TestS3CopyBucketDirectoryCopyError() {
+	a.Mocks.API.S3.EXPECT().
+		ListObjectsV2Pages(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(input *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool) error {
+			fn(&s3.ListObjectsV2Output{
+				Contents: []*s3.Object{{Key: aws.String("installation-1/backup-1"), Size: aws.Int64(10)}},
+			}, true)
+			return nil
+		}).
+		Times(1)
+
+	a.Mocks.API.S3.EXPECT().
+		CopyObject(gomock.Any()).
+		Return(nil, errors.New("access denied")).
+		Times(1)
+
+	err := a.Mocks.AWS.S3CopyBucketDirectory("source-bucket", "installation-1/backup-1", "destination-bucket", "installation-2/backup-1", a.Mocks.Log.Logger)
+	a.Assert().EqualError(err, "failed to copy object installation-1/backup-1: access denied")
+}
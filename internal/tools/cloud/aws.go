@@ -0,0 +1,152 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package cloud
+
+import (
+	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
+	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// HibernatingInstallationResourceRecordIDPrefix is the prefix given to the
+// DNS record ID of hibernating installations.
+const HibernatingInstallationResourceRecordIDPrefix = aws.HibernatingInstallationResourceRecordIDPrefix
+
+// AWSProvider is the Provider backed by AWS.
+type AWSProvider struct {
+	aws          aws.AWS
+	resourceUtil *utils.ResourceUtil
+}
+
+// NewAWSProvider creates a new AWSProvider.
+func NewAWSProvider(awsClient aws.AWS, resourceUtil *utils.ResourceUtil) *AWSProvider {
+	return &AWSProvider{
+		aws:          awsClient,
+		resourceUtil: resourceUtil,
+	}
+}
+
+// Name returns the name of the provider.
+func (p *AWSProvider) Name() string {
+	return model.ProviderAWS
+}
+
+// GetCloudEnvironmentName returns the name of the AWS environment.
+func (p *AWSProvider) GetCloudEnvironmentName() string {
+	return p.aws.GetCloudEnvironmentName()
+}
+
+// CreatePublicCNAME creates a public Route53 CNAME record.
+func (p *AWSProvider) CreatePublicCNAME(dnsName string, dnsEndpoints []string, logger log.FieldLogger) error {
+	return p.aws.CreatePublicCNAME(dnsName, dnsEndpoints, logger)
+}
+
+// UpdatePublicRecordIDForCNAME updates the record ID of a public Route53 CNAME record.
+func (p *AWSProvider) UpdatePublicRecordIDForCNAME(dnsName, newID string, logger log.FieldLogger) error {
+	return p.aws.UpdatePublicRecordIDForCNAME(dnsName, newID, logger)
+}
+
+// DeletePublicCNAME deletes a public Route53 CNAME record.
+func (p *AWSProvider) DeletePublicCNAME(dnsName string, logger log.FieldLogger) error {
+	return p.aws.DeletePublicCNAME(dnsName, logger)
+}
+
+// ClaimClusterNetwork claims a VPC for the given cluster.
+func (p *AWSProvider) ClaimClusterNetwork(clusterID, owner string, logger log.FieldLogger) (*ClusterNetwork, error) {
+	resources, err := p.aws.GetAndClaimVpcResources(clusterID, owner, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return newClusterNetwork(resources), nil
+}
+
+// GetClusterNetwork returns the VPC claimed by the given cluster.
+func (p *AWSProvider) GetClusterNetwork(clusterID string, logger log.FieldLogger) (*ClusterNetwork, error) {
+	resources, err := p.aws.GetVpcResources(clusterID, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return newClusterNetwork(resources), nil
+}
+
+// GetNetwork returns the VPC with the given ID.
+func (p *AWSProvider) GetNetwork(networkID string, logger log.FieldLogger) (*ClusterNetwork, error) {
+	resources, err := p.aws.GetVpcResourcesByVpcID(networkID, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return newClusterNetwork(resources), nil
+}
+
+// GetNetworkCIDRByName returns the CIDR of the VPC with the given name tag.
+func (p *AWSProvider) GetNetworkCIDRByName(name string, logger log.FieldLogger) (string, error) {
+	return p.aws.GetCIDRByVPCTag(name, logger)
+}
+
+// TagClusterNetwork tags the given VPC and its subnets as used by the cluster.
+func (p *AWSProvider) TagClusterNetwork(network *ClusterNetwork, clusterID, owner string, logger log.FieldLogger) error {
+	return p.aws.TagResourcesByCluster(aws.ClusterResources{
+		VpcID:                  network.ID,
+		VpcCIDR:                network.CIDR,
+		PrivateSubnetIDs:       network.PrivateSubnetIDs,
+		PublicSubnetsIDs:       network.PublicSubnetIDs,
+		MasterSecurityGroupIDs: network.MasterSecurityGroupIDs,
+		WorkerSecurityGroupIDs: network.WorkerSecurityGroupIDs,
+	}, clusterID, owner, logger)
+}
+
+// ReleaseClusterNetwork releases the VPC claimed by the given cluster.
+func (p *AWSProvider) ReleaseClusterNetwork(clusterID string, logger log.FieldLogger) error {
+	return p.aws.ReleaseVpc(clusterID, logger)
+}
+
+// GetCertificateByTag returns the ACM certificate with the given tag.
+func (p *AWSProvider) GetCertificateByTag(key, value string, logger log.FieldLogger) (*Certificate, error) {
+	summary, err := p.aws.GetCertificateSummaryByTag(key, value, logger)
+	if err != nil {
+		return nil, err
+	}
+	if summary == nil || summary.CertificateArn == nil {
+		return nil, errors.Errorf("no certificate found with tag %s:%s", key, value)
+	}
+
+	certificate := &Certificate{ID: *summary.CertificateArn}
+	if summary.DomainName != nil {
+		certificate.Domain = *summary.DomainName
+	}
+
+	return certificate, nil
+}
+
+// GetDatabase returns the database of the given type for an installation.
+func (p *AWSProvider) GetDatabase(installationID, dbType string) model.Database {
+	return p.resourceUtil.GetDatabase(installationID, dbType)
+}
+
+// GetDatabaseForInstallation returns the database of the given installation.
+func (p *AWSProvider) GetDatabaseForInstallation(installation *model.Installation) model.Database {
+	return p.resourceUtil.GetDatabaseForInstallation(installation)
+}
+
+// GetFilestore returns the filestore of the given installation.
+func (p *AWSProvider) GetFilestore(installation *model.Installation) model.Filestore {
+	return p.resourceUtil.GetFilestore(installation)
+}
+
+func newClusterNetwork(resources aws.ClusterResources) *ClusterNetwork {
+	return &ClusterNetwork{
+		ID:                     resources.VpcID,
+		CIDR:                   resources.VpcCIDR,
+		PrivateSubnetIDs:       resources.PrivateSubnetIDs,
+		PublicSubnetIDs:        resources.PublicSubnetsIDs,
+		MasterSecurityGroupIDs: resources.MasterSecurityGroupIDs,
+		WorkerSecurityGroupIDs: resources.WorkerSecurityGroupIDs,
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package cloud
+
+import (
+	"testing"
+
+	sdkAWS "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/acm"
+	"github.com/golang/mock/gomock"
+	awsMocks "github.com/mattermost/mattermost-cloud/internal/mocks/aws-tools"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
+	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAWSProvider(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	gmctrl := gomock.NewController(t)
+	defer gmctrl.Finish()
+
+	awsClient := awsMocks.NewMockAWS(gmctrl)
+	provider := NewAWSProvider(awsClient, &utils.ResourceUtil{})
+
+	t.Run("name", func(t *testing.T) {
+		assert.Equal(t, model.ProviderAWS, provider.Name())
+	})
+
+	t.Run("environment name", func(t *testing.T) {
+		awsClient.EXPECT().GetCloudEnvironmentName().Return("test")
+
+		assert.Equal(t, "test", provider.GetCloudEnvironmentName())
+	})
+
+	t.Run("create public CNAME", func(t *testing.T) {
+		awsClient.EXPECT().
+			CreatePublicCNAME("installation.example.com", []string{"endpoint.example.com"}, logger).
+			Return(nil)
+
+		err := provider.CreatePublicCNAME("installation.example.com", []string{"endpoint.example.com"}, logger)
+		require.NoError(t, err)
+	})
+
+	t.Run("claim cluster network", func(t *testing.T) {
+		awsClient.EXPECT().
+			GetAndClaimVpcResources("cluster1", "owner1", logger).
+			Return(aws.ClusterResources{
+				VpcID:                  "vpc1",
+				VpcCIDR:                "10.0.0.0/16",
+				PrivateSubnetIDs:       []string{"private1"},
+				PublicSubnetsIDs:       []string{"public1"},
+				MasterSecurityGroupIDs: []string{"master1"},
+				WorkerSecurityGroupIDs: []string{"worker1"},
+			}, nil)
+
+		network, err := provider.ClaimClusterNetwork("cluster1", "owner1", logger)
+		require.NoError(t, err)
+		assert.Equal(t, &ClusterNetwork{
+			ID:                     "vpc1",
+			CIDR:                   "10.0.0.0/16",
+			PrivateSubnetIDs:       []string{"private1"},
+			PublicSubnetIDs:        []string{"public1"},
+			MasterSecurityGroupIDs: []string{"master1"},
+			WorkerSecurityGroupIDs: []string{"worker1"},
+		}, network)
+	})
+
+	t.Run("claim cluster network error", func(t *testing.T) {
+		awsClient.EXPECT().
+			GetAndClaimVpcResources("cluster1", "owner1", logger).
+			Return(aws.ClusterResources{}, errors.New("no VPCs available"))
+
+		network, err := provider.ClaimClusterNetwork("cluster1", "owner1", logger)
+		require.EqualError(t, err, "no VPCs available")
+		assert.Nil(t, network)
+	})
+
+	t.Run("tag cluster network", func(t *testing.T) {
+		awsClient.EXPECT().
+			TagResourcesByCluster(aws.ClusterResources{
+				VpcID:            "vpc1",
+				PrivateSubnetIDs: []string{"private1"},
+				PublicSubnetsIDs: []string{"public1"},
+			}, "cluster1", "owner1", logger).
+			Return(nil)
+
+		err := provider.TagClusterNetwork(&ClusterNetwork{
+			ID:               "vpc1",
+			PrivateSubnetIDs: []string{"private1"},
+			PublicSubnetIDs:  []string{"public1"},
+		}, "cluster1", "owner1", logger)
+		require.NoError(t, err)
+	})
+
+	t.Run("release cluster network", func(t *testing.T) {
+		awsClient.EXPECT().ReleaseVpc("cluster1", logger).Return(nil)
+
+		err := provider.ReleaseClusterNetwork("cluster1", logger)
+		require.NoError(t, err)
+	})
+
+	t.Run("get certificate", func(t *testing.T) {
+		awsClient.EXPECT().
+			GetCertificateSummaryByTag("key", "value", logger).
+			Return(&acm.CertificateSummary{
+				CertificateArn: sdkAWS.String("arn:certificate"),
+				DomainName:     sdkAWS.String("*.example.com"),
+			}, nil)
+
+		certificate, err := provider.GetCertificateByTag("key", "value", logger)
+		require.NoError(t, err)
+		assert.Equal(t, &Certificate{ID: "arn:certificate", Domain: "*.example.com"}, certificate)
+	})
+
+	t.Run("get missing certificate", func(t *testing.T) {
+		awsClient.EXPECT().
+			GetCertificateSummaryByTag("key", "value", logger).
+			Return(&acm.CertificateSummary{}, nil)
+
+		_, err := provider.GetCertificateByTag("key", "value", logger)
+		require.EqualError(t, err, "no certificate found with tag key:value")
+	})
+
+	t.Run("operator resources", func(t *testing.T) {
+		installation := &model.Installation{
+			ID:        model.NewID(),
+			Database:  model.InstallationDatabaseMysqlOperator,
+			Filestore: model.InstallationFilestoreMinioOperator,
+		}
+
+		assert.Equal(t, model.NewMysqlOperatorDatabase(), provider.GetDatabaseForInstallation(installation))
+		assert.Equal(t, model.NewMinioOperatorFilestore(), provider.GetFilestore(installation))
+	})
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+// Package cloud defines the provider-neutral interface to the cloud hosting
+// clusters and installations.
+package cloud
+
+import (
+	"github.com/mattermost/mattermost-cloud/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// Provider is the interface to a cloud provider. It covers the cloud
+// resources needed by the supervisors, so that they don't depend on a
+// specific provider.
+type Provider interface {
+	ResourceFactory
+
+	// Name returns the name of the provider, e.g. model.ProviderAWS.
+	Name() string
+	// GetCloudEnvironmentName returns the name of the environment the
+	// provisioner is running in.
+	GetCloudEnvironmentName() string
+
+	// DNS records
+	CreatePublicCNAME(dnsName string, dnsEndpoints []string, logger log.FieldLogger) error
+	UpdatePublicRecordIDForCNAME(dnsName, newID string, logger log.FieldLogger) error
+	DeletePublicCNAME(dnsName string, logger log.FieldLogger) error
+
+	// Networking
+	ClaimClusterNetwork(clusterID, owner string, logger log.FieldLogger) (*ClusterNetwork, error)
+	GetClusterNetwork(clusterID string, logger log.FieldLogger) (*ClusterNetwork, error)
+	GetNetwork(networkID string, logger log.FieldLogger) (*ClusterNetwork, error)
+	GetNetworkCIDRByName(name string, logger log.FieldLogger) (string, error)
+	TagClusterNetwork(network *ClusterNetwork, clusterID, owner string, logger log.FieldLogger) error
+	ReleaseClusterNetwork(clusterID string, logger log.FieldLogger) error
+
+	// Certificates
+	GetCertificateByTag(key, value string, logger log.FieldLogger) (*Certificate, error)
+}
+
+// ResourceFactory builds the database and filestore backing installations.
+type ResourceFactory interface {
+	GetDatabase(installationID, dbType string) model.Database
+	GetDatabaseForInstallation(installation *model.Installation) model.Database
+	GetFilestore(installation *model.Installation) model.Filestore
+}
+
+// ClusterNetwork is the network claimed by a cluster.
+type ClusterNetwork struct {
+	ID                     string
+	CIDR                   string
+	PrivateSubnetIDs       []string
+	PublicSubnetIDs        []string
+	MasterSecurityGroupIDs []string
+	WorkerSecurityGroupIDs []string
+}
+
+// Certificate is a TLS certificate managed by the provider.
+type Certificate struct {
+	ID     string
+	Domain string
+}
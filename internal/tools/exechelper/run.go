@@ -14,8 +14,11 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"sync"
+	"time"
 
+	"github.com/mattermost/mattermost-cloud/internal/metrics"
 	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -63,6 +66,7 @@ func bufferAndLog(reader io.Reader, buffer *bytes.Buffer, logger log.FieldLogger
 }
 
 func run(cmd *exec.Cmd, logger log.FieldLogger, outputLogger OutputLogger) ([]byte, []byte, error) {
+	start := time.Now()
 	stdout := new(bytes.Buffer)
 	stderr := new(bytes.Buffer)
 	rStdout, wStdout := io.Pipe()
@@ -100,6 +104,8 @@ func run(cmd *exec.Cmd, logger log.FieldLogger, outputLogger OutputLogger) ([]by
 
 	wg.Wait()
 
+	metrics.ObserveExternalCommand(filepath.Base(cmd.Path), time.Since(start), err)
+
 	if err != nil {
 		logger.WithError(err).Error("failed invocation")
 
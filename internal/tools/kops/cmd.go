@@ -47,6 +47,34 @@ func New(s3StateStore string, logger log.FieldLogger) (*Cmd, error) {
 	}, nil
 }
 
+// NewFromKubeconfig creates a new instance of Cmd holding a copy of an existing
+// kubeconfig. It is intended for clusters which are not managed by kops, so
+// the kops binary is not required.
+func NewFromKubeconfig(kubeconfigPath string, logger log.FieldLogger) (*Cmd, error) {
+	kubeconfig, err := ioutil.ReadFile(kubeconfigPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read kubeconfig")
+	}
+
+	tempDir, err := ioutil.TempDir("", "kops-")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temporary kops directory")
+	}
+
+	cmd := &Cmd{
+		tempDir: tempDir,
+		logger:  logger,
+	}
+
+	err = ioutil.WriteFile(cmd.GetKubeConfigPath(), kubeconfig, 0600)
+	if err != nil {
+		cmd.Close()
+		return nil, errors.Wrap(err, "failed to write kubeconfig")
+	}
+
+	return cmd, nil
+}
+
 // SetLogger sets a new logger for kops commands.
 func (c *Cmd) SetLogger(logger log.FieldLogger) {
 	c.logger = logger
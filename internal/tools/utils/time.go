@@ -15,3 +15,8 @@ func TimeFromMillis(millis int64) time.Time {
 func GetMillis() int64 {
 	return time.Now().UnixNano() / int64(time.Millisecond)
 }
+
+// GetMillisAtTime returns the given time as milliseconds since epoch.
+func GetMillisAtTime(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
@@ -14,11 +14,27 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+const (
+	// deliveryTimeout is the time a single delivery attempt may take. It must
+	// stay below InitialRetryBackoff so that an immediate delivery attempt
+	// never overlaps with a retry.
+	deliveryTimeout = 10 * time.Second
+
+	// InitialRetryBackoff is the time to wait before retrying a failed delivery.
+	InitialRetryBackoff = 30 * time.Second
+	// MaxRetryBackoff is the maximum time to wait between delivery attempts.
+	MaxRetryBackoff = time.Hour
+)
+
 type webhookStore interface {
 	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
 }
 
-// SendToAllWebhooks sends a given payload to all webhooks.
+// SendToAllWebhooks queues a given payload for delivery to all webhooks
+// subscribed to it and makes a first delivery attempt in the background.
+// Failed deliveries are retried by the webhook delivery supervisor.
 func SendToAllWebhooks(store webhookStore, payload *model.WebhookPayload, logger *log.Entry) error {
 	hooks, err := store.GetWebhooks(&model.WebhookFilter{
 		Paging: model.AllPagesNotDeleted(),
@@ -27,14 +43,26 @@ func SendToAllWebhooks(store webhookStore, payload *model.WebhookPayload, logger
 		return errors.Wrap(err, "Failed to find webhooks")
 	}
 
-	sendWebhooks(hooks, payload, logger)
+	sendWebhooks(store, subscribedWebhooks(hooks, payload), payload, logger)
 
 	return nil
 }
 
-// sendWebhooks sends webhooks via fire-and-forget goroutines. The send-webhook
-// failures are logged, but not handled.
-func sendWebhooks(hooks []*model.Webhook, payload *model.WebhookPayload, logger *log.Entry) {
+// subscribedWebhooks returns the webhooks whose subscription matches the payload.
+func subscribedWebhooks(hooks []*model.Webhook, payload *model.WebhookPayload) []*model.Webhook {
+	var subscribed []*model.Webhook
+	for _, hook := range hooks {
+		if hook.Subscription.Matches(payload) {
+			subscribed = append(subscribed, hook)
+		}
+	}
+
+	return subscribed
+}
+
+// sendWebhooks persists a delivery for every webhook and attempts to send
+// them via goroutines.
+func sendWebhooks(store webhookStore, hooks []*model.Webhook, payload *model.WebhookPayload, logger *log.Entry) {
 	if len(hooks) == 0 {
 		return
 	}
@@ -42,26 +70,99 @@ func sendWebhooks(hooks []*model.Webhook, payload *model.WebhookPayload, logger
 	logger.Debugf("Sending %d webhook(s)", len(hooks))
 
 	for _, hook := range hooks {
-		go sendWebhook(hook, payload, logger)
+		delivery := &model.WebhookDelivery{
+			WebhookID:     hook.ID,
+			Payload:       payload,
+			State:         model.WebhookDeliveryStatePending,
+			NextAttemptAt: asMillis(time.Now().Add(InitialRetryBackoff)),
+		}
+		err := store.CreateWebhookDelivery(delivery)
+		if err != nil {
+			logger.WithField("webhookURL", hook.URL).WithError(err).Error("Unable to queue webhook delivery")
+			continue
+		}
+
+		go func(hook *model.Webhook, delivery *model.WebhookDelivery) {
+			_ = Deliver(hook, delivery, logger)
+
+			err := store.UpdateWebhookDelivery(delivery)
+			if err != nil {
+				logger.WithField("webhookDelivery", delivery.ID).WithError(err).Error("Unable to record webhook delivery attempt")
+			}
+		}(hook, delivery)
+	}
+}
+
+// Deliver makes a delivery attempt of the given delivery to the webhook and
+// records the outcome on the delivery. It is up to the caller to persist it.
+func Deliver(hook *model.Webhook, delivery *model.WebhookDelivery, logger log.FieldLogger) error {
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = asMillis(now)
+
+	statusCode, err := sendWebhook(hook, delivery, logger)
+	delivery.LastStatusCode = int64(statusCode)
+	if err != nil {
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = asMillis(now.Add(RetryBackoff(delivery.Attempts)))
+		return err
 	}
+
+	delivery.State = model.WebhookDeliveryStateDelivered
+	delivery.LastError = ""
+	delivery.NextAttemptAt = 0
+
+	return nil
+}
+
+// RetryBackoff returns the time to wait before the next delivery attempt
+// after the given number of failed attempts.
+func RetryBackoff(attempts int64) time.Duration {
+	backoff := InitialRetryBackoff
+	for i := int64(1); i < attempts; i++ {
+		backoff *= 2
+		if backoff >= MaxRetryBackoff {
+			return MaxRetryBackoff
+		}
+	}
+
+	return backoff
 }
 
-func sendWebhook(hook *model.Webhook, payload *model.WebhookPayload, logger *log.Entry) error {
-	payloadStr, err := payload.ToJSON()
+func sendWebhook(hook *model.Webhook, delivery *model.WebhookDelivery, logger log.FieldLogger) (int, error) {
+	payloadStr, err := delivery.Payload.ToJSON()
 	if err != nil {
 		logger.WithField("webhookURL", hook.URL).WithError(err).Error("Unable to create payload string to send to webhook")
-		return errors.Wrap(err, "unable to create payload string to send to webhook")
+		return 0, errors.Wrap(err, "unable to create payload string to send to webhook")
 	}
 
 	req, err := http.NewRequest("POST", hook.URL, bytes.NewBuffer([]byte(payloadStr)))
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to create webhook request")
+	}
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(model.WebhookDeliveryHeader, delivery.ID)
+	if hook.Secret != "" {
+		req.Header.Set(model.WebhookSignatureHeader, model.SignWebhookPayload(hook.Secret, []byte(payloadStr)))
+	}
 
-	client := &http.Client{Timeout: 5 * time.Second}
-	_, err = client.Do(req)
+	client := &http.Client{Timeout: deliveryTimeout}
+	resp, err := client.Do(req)
 	if err != nil {
 		logger.WithField("webhookURL", hook.URL).WithError(err).Error("Unable to send webhook")
-		return errors.Wrap(err, "unable to send webhook")
+		return 0, errors.Wrap(err, "unable to send webhook")
 	}
+	defer resp.Body.Close()
 
-	return nil
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		logger.WithField("webhookURL", hook.URL).Errorf("Webhook responded with status code %d", resp.StatusCode)
+		return resp.StatusCode, errors.Errorf("webhook responded with status code %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// asMillis returns the given time as milliseconds since epoch.
+func asMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
 }
@@ -5,6 +5,11 @@
 package webhook
 
 import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
@@ -15,15 +20,37 @@ import (
 )
 
 type mockWebhookStore struct {
-	Webhooks []*model.Webhook
+	Webhooks   []*model.Webhook
+	Deliveries []*model.WebhookDelivery
+
+	mux sync.Mutex
+	// pending tracks background delivery attempts which have not been recorded yet.
+	pending sync.WaitGroup
 }
 
 func (s *mockWebhookStore) GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error) {
 	return s.Webhooks, nil
 }
 
+func (s *mockWebhookStore) CreateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	delivery.ID = model.NewID()
+	s.Deliveries = append(s.Deliveries, delivery)
+	s.pending.Add(1)
+
+	return nil
+}
+
+func (s *mockWebhookStore) UpdateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	s.pending.Done()
+	return nil
+}
+
 func TestGetAndSendWebhooks(t *testing.T) {
 	mockStore := &mockWebhookStore{}
+	defer mockStore.pending.Wait()
 	logger := testlib.MakeLogger(t).WithFields(log.Fields{
 		"webhooks-tests": true,
 	})
@@ -60,18 +87,10 @@ func TestGetAndSendWebhooks(t *testing.T) {
 	})
 }
 
-// TODO: add happy-path test.
 func TestSendWebhooks(t *testing.T) {
 	logger := testlib.MakeLogger(t).WithFields(log.Fields{
 		"webhooks-tests": true,
 	})
-	hook := &model.Webhook{
-		ID:       model.NewID(),
-		OwnerID:  model.NewID(),
-		URL:      "https://not-a-real-host",
-		CreateAt: 10,
-		DeleteAt: 0,
-	}
 	payload := &model.WebhookPayload{
 		Type:      "type",
 		ID:        model.NewID(),
@@ -81,6 +100,124 @@ func TestSendWebhooks(t *testing.T) {
 		ExtraData: map[string]string{"ClusterID": model.NewID()},
 	}
 
-	err := sendWebhook(hook, payload, logger)
-	require.Contains(t, err.Error(), "unable to send webhook")
+	t.Run("unreachable host", func(t *testing.T) {
+		hook := &model.Webhook{
+			ID:      model.NewID(),
+			OwnerID: model.NewID(),
+			URL:     "https://not-a-real-host",
+		}
+		delivery := &model.WebhookDelivery{ID: model.NewID(), Payload: payload, State: model.WebhookDeliveryStatePending}
+
+		err := Deliver(hook, delivery, logger)
+		require.Contains(t, err.Error(), "unable to send webhook")
+		require.Equal(t, model.WebhookDeliveryStatePending, delivery.State)
+		require.Equal(t, int64(1), delivery.Attempts)
+		require.NotEmpty(t, delivery.LastError)
+		require.True(t, delivery.NextAttemptAt > delivery.LastAttemptAt)
+	})
+
+	t.Run("error status code", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer ts.Close()
+
+		hook := &model.Webhook{ID: model.NewID(), URL: ts.URL}
+		delivery := &model.WebhookDelivery{ID: model.NewID(), Payload: payload, State: model.WebhookDeliveryStatePending}
+
+		err := Deliver(hook, delivery, logger)
+		require.EqualError(t, err, "webhook responded with status code 503")
+		require.Equal(t, model.WebhookDeliveryStatePending, delivery.State)
+		require.Equal(t, int64(http.StatusServiceUnavailable), delivery.LastStatusCode)
+	})
+
+	t.Run("signed delivery", func(t *testing.T) {
+		var body []byte
+		var header http.Header
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ = ioutil.ReadAll(r.Body)
+			header = r.Header
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer ts.Close()
+
+		hook := &model.Webhook{ID: model.NewID(), URL: ts.URL, Secret: "secret"}
+		delivery := &model.WebhookDelivery{ID: model.NewID(), Payload: payload, State: model.WebhookDeliveryStatePending}
+
+		err := Deliver(hook, delivery, logger)
+		require.NoError(t, err)
+		require.Equal(t, model.WebhookDeliveryStateDelivered, delivery.State)
+		require.Equal(t, int64(http.StatusOK), delivery.LastStatusCode)
+		require.Equal(t, delivery.ID, header.Get(model.WebhookDeliveryHeader))
+		require.Equal(t, model.SignWebhookPayload("secret", body), header.Get(model.WebhookSignatureHeader))
+
+		received, err := model.WebhookPayloadFromReader(bytes.NewReader(body))
+		require.NoError(t, err)
+		require.Equal(t, payload, received)
+	})
+
+	t.Run("queued deliveries", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer ts.Close()
+
+		mockStore := &mockWebhookStore{
+			Webhooks: []*model.Webhook{
+				{ID: model.NewID(), URL: ts.URL},
+				{ID: model.NewID(), URL: ts.URL},
+			},
+		}
+
+		err := SendToAllWebhooks(mockStore, payload, logger)
+		require.NoError(t, err)
+		mockStore.pending.Wait()
+		require.Len(t, mockStore.Deliveries, 2)
+		for i, delivery := range mockStore.Deliveries {
+			require.Equal(t, mockStore.Webhooks[i].ID, delivery.WebhookID)
+			require.Equal(t, payload, delivery.Payload)
+			require.Equal(t, model.WebhookDeliveryStateDelivered, delivery.State)
+		}
+	})
+
+	t.Run("subscribed deliveries", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer ts.Close()
+
+		subscribed := &model.Webhook{
+			ID:           model.NewID(),
+			URL:          ts.URL,
+			Subscription: &model.WebhookSubscription{Types: []string{payload.Type}},
+		}
+		mockStore := &mockWebhookStore{
+			Webhooks: []*model.Webhook{
+				{
+					ID:           model.NewID(),
+					URL:          ts.URL,
+					Subscription: &model.WebhookSubscription{Types: []string{model.TypeCluster}},
+				},
+				subscribed,
+				{
+					ID:           model.NewID(),
+					URL:          ts.URL,
+					Subscription: &model.WebhookSubscription{ResourceIDs: []string{model.NewID()}},
+				},
+			},
+		}
+
+		err := SendToAllWebhooks(mockStore, payload, logger)
+		require.NoError(t, err)
+		mockStore.pending.Wait()
+		require.Len(t, mockStore.Deliveries, 1)
+		require.Equal(t, subscribed.ID, mockStore.Deliveries[0].WebhookID)
+	})
+}
+
+func TestRetryBackoff(t *testing.T) {
+	require.Equal(t, InitialRetryBackoff, RetryBackoff(1))
+	require.Equal(t, 2*InitialRetryBackoff, RetryBackoff(2))
+	require.Equal(t, 4*InitialRetryBackoff, RetryBackoff(3))
+	require.Equal(t, MaxRetryBackoff, RetryBackoff(100))
 }
@@ -13,6 +13,8 @@ type ClusterResources struct {
 	MilliUsedCPU     int64
 	MilliTotalMemory int64
 	MilliUsedMemory  int64
+	// NodeCount is the number of nodes whose resources are included.
+	NodeCount int64
 }
 
 // CalculateCPUPercentUsed calculates the CPU usage percentage of a cluster with
@@ -20,6 +20,10 @@ const (
 	annotationAllowedFormat = "annotations must start with a letter and can contain only lowercase letters, numbers or '_', '-' characters"
 )
 
+// AnnotationDisableIdleHibernation is the installation annotation which opts
+// the installation out of being hibernated automatically when idle.
+const AnnotationDisableIdleHibernation = "disable-idle-hibernation"
+
 var annotationRegex = regexp.MustCompile("^[a-z]+[a-z0-9_-]*$")
 
 // Annotation represents an annotation.
@@ -0,0 +1,114 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// APIKeyScopeAdmin grants unrestricted access to the provisioning server API.
+	APIKeyScopeAdmin = "admin"
+	// APIKeyScopeReadOnly grants access only to API endpoints that do not modify any resources.
+	APIKeyScopeReadOnly = "read-only"
+	// APIKeyScopeInstallations grants full access to installation endpoints only.
+	APIKeyScopeInstallations = "installations"
+
+	// AuthorizationHeader is the HTTP header used to pass API credentials.
+	AuthorizationHeader = "Authorization"
+	// AuthorizationBearerPrefix is the prefix of the Authorization header value followed by the API key.
+	AuthorizationBearerPrefix = "Bearer "
+
+	apiKeySecretBytes = 32
+)
+
+// APIKey represents a credential used to authenticate with the provisioning server API.
+type APIKey struct {
+	ID   string
+	Name string
+	// Key is the plain text API key. It is only returned once when the key is created.
+	Key   string `json:"Key,omitempty"`
+	Scope string
+	// OwnerID binds the API key to installations of a single owner when set.
+	OwnerID  string
+	KeyHash  string `json:"-"`
+	CreateAt int64
+	DeleteAt int64
+}
+
+// APIKeyFilter describes the parameters used to constrain a set of API keys.
+type APIKeyFilter struct {
+	Paging
+	OwnerID string
+}
+
+// IsDeleted returns whether the API key was marked as deleted or not.
+func (k *APIKey) IsDeleted() bool {
+	return k.DeleteAt != 0
+}
+
+// IsOwnerBound returns whether the API key is restricted to installations of a single owner.
+func (k *APIKey) IsOwnerBound() bool {
+	return k.OwnerID != ""
+}
+
+// IsSupportedAPIKeyScope returns true if the given scope is supported.
+func IsSupportedAPIKeyScope(scope string) bool {
+	switch scope {
+	case APIKeyScopeAdmin:
+	case APIKeyScopeReadOnly:
+	case APIKeyScopeInstallations:
+	default:
+		return false
+	}
+
+	return true
+}
+
+// NewAPIKeySecret generates a new random plain text API key.
+func NewAPIKeySecret() (string, error) {
+	key, err := newSecret(apiKeySecretBytes)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to generate random API key")
+	}
+
+	return key, nil
+}
+
+// HashAPIKey returns the hash of the plain text API key that is persisted in the store.
+func HashAPIKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+// APIKeyFromReader decodes a json-encoded API key from the given io.Reader.
+func APIKeyFromReader(reader io.Reader) (*APIKey, error) {
+	apiKey := APIKey{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&apiKey)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return &apiKey, nil
+}
+
+// APIKeysFromReader decodes a json-encoded list of API keys from the given io.Reader.
+func APIKeysFromReader(reader io.Reader) ([]*APIKey, error) {
+	apiKeys := []*APIKey{}
+	decoder := json.NewDecoder(reader)
+
+	err := decoder.Decode(&apiKeys)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return apiKeys, nil
+}
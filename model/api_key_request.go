@@ -0,0 +1,66 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// CreateAPIKeyRequest specifies the parameters for a new API key.
+type CreateAPIKeyRequest struct {
+	Name    string
+	Scope   string
+	OwnerID string
+}
+
+// Validate validates the values of an API key create request.
+func (request *CreateAPIKeyRequest) Validate() error {
+	if request.Name == "" {
+		return errors.New("must specify name")
+	}
+	if !IsSupportedAPIKeyScope(request.Scope) {
+		return errors.Errorf("unsupported scope %q", request.Scope)
+	}
+	if request.OwnerID != "" && request.Scope == APIKeyScopeAdmin {
+		return errors.New("admin API keys cannot be bound to an owner")
+	}
+
+	return nil
+}
+
+// NewCreateAPIKeyRequestFromReader will create a CreateAPIKeyRequest from an io.Reader with JSON data.
+func NewCreateAPIKeyRequestFromReader(reader io.Reader) (*CreateAPIKeyRequest, error) {
+	var createAPIKeyRequest CreateAPIKeyRequest
+	err := json.NewDecoder(reader).Decode(&createAPIKeyRequest)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode create API key request")
+	}
+
+	err = createAPIKeyRequest.Validate()
+	if err != nil {
+		return nil, errors.Wrap(err, "create API key request failed validation")
+	}
+
+	return &createAPIKeyRequest, nil
+}
+
+// GetAPIKeysRequest describes the parameters to request a list of API keys.
+type GetAPIKeysRequest struct {
+	Paging
+	OwnerID string
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *GetAPIKeysRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	q.Add("owner", request.OwnerID)
+	request.Paging.AddToQuery(q)
+
+	u.RawQuery = q.Encode()
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyHelpers(t *testing.T) {
+	apiKey := &APIKey{}
+	require.False(t, apiKey.IsDeleted())
+	require.False(t, apiKey.IsOwnerBound())
+
+	apiKey.DeleteAt = 1
+	apiKey.OwnerID = "owner"
+	require.True(t, apiKey.IsDeleted())
+	require.True(t, apiKey.IsOwnerBound())
+}
+
+func TestNewAPIKeySecret(t *testing.T) {
+	key1, err := NewAPIKeySecret()
+	require.NoError(t, err)
+	key2, err := NewAPIKeySecret()
+	require.NoError(t, err)
+
+	require.Len(t, key1, 2*apiKeySecretBytes)
+	require.NotEqual(t, key1, key2)
+	require.Equal(t, HashAPIKey(key1), HashAPIKey(key1))
+	require.NotEqual(t, HashAPIKey(key1), HashAPIKey(key2))
+}
+
+func TestCreateAPIKeyRequestValidate(t *testing.T) {
+	for _, testCase := range []struct {
+		description string
+		request     *CreateAPIKeyRequest
+		expectError bool
+	}{
+		{"valid", &CreateAPIKeyRequest{Name: "key", Scope: APIKeyScopeAdmin}, false},
+		{"valid owner bound", &CreateAPIKeyRequest{Name: "key", Scope: APIKeyScopeInstallations, OwnerID: "owner"}, false},
+		{"missing name", &CreateAPIKeyRequest{Scope: APIKeyScopeReadOnly}, true},
+		{"invalid scope", &CreateAPIKeyRequest{Name: "key", Scope: "unknown"}, true},
+		{"owner bound admin", &CreateAPIKeyRequest{Name: "key", Scope: APIKeyScopeAdmin, OwnerID: "owner"}, true},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			err := testCase.request.Validate()
+			if testCase.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAPIKeyFromReader(t *testing.T) {
+	apiKey, err := APIKeyFromReader(strings.NewReader(`{"ID":"id","Scope":"admin","KeyHash":"hash"}`))
+	require.NoError(t, err)
+	require.Equal(t, &APIKey{ID: "id", Scope: APIKeyScopeAdmin}, apiKey)
+
+	_, err = APIKeyFromReader(strings.NewReader(`{test`))
+	require.Error(t, err)
+}
@@ -0,0 +1,223 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// BulkOperation applies the same operation to every installation matching a
+// filter, for example updating the version of all installations of an owner.
+// The matching installations are resolved when the operation is created and
+// the outcome for each of them is recorded as a BulkOperationItem.
+type BulkOperation struct {
+	ID     string
+	Type   BulkOperationType
+	Filter BulkOperationInstallationFilter
+	// Patch is applied to the installations by update operations.
+	Patch *PatchInstallationRequest `json:"Patch,omitempty"`
+	// Annotations are added to the installations by add-annotations operations.
+	Annotations []string `json:"Annotations,omitempty"`
+	// Concurrency is the maximum number of installations for which a state
+	// change was requested and which did not settle yet.
+	Concurrency    int64
+	State          BulkOperationState
+	CreateAt       int64
+	CompleteAt     int64
+	DeleteAt       int64
+	LockAcquiredBy *string
+	LockAcquiredAt int64
+}
+
+// BulkOperationInstallationFilter describes the installations targeted by a
+// bulk operation. Deleted installations are never targeted.
+type BulkOperationInstallationFilter struct {
+	OwnerID string
+	GroupID string
+	State   string
+	// Annotations are the names of the annotations which must all be set on
+	// an installation for it to be targeted.
+	Annotations []string `json:"Annotations,omitempty"`
+	// DNSPattern is matched against the installation DNS, where * matches any
+	// sequence of characters.
+	DNSPattern string
+}
+
+// BulkOperationType is the operation applied to each installation by a bulk
+// operation.
+type BulkOperationType string
+
+const (
+	// BulkOperationTypeUpdate applies the operation patch to the installations.
+	BulkOperationTypeUpdate BulkOperationType = "update"
+	// BulkOperationTypeHibernate hibernates the installations.
+	BulkOperationTypeHibernate BulkOperationType = "hibernate"
+	// BulkOperationTypeWakeUp wakes up the hibernated installations.
+	BulkOperationTypeWakeUp BulkOperationType = "wake-up"
+	// BulkOperationTypeDelete deletes the installations.
+	BulkOperationTypeDelete BulkOperationType = "delete"
+	// BulkOperationTypeAddAnnotations adds the operation annotations to the
+	// installations.
+	BulkOperationTypeAddAnnotations BulkOperationType = "add-annotations"
+)
+
+// AllBulkOperationTypes is a list of all supported bulk operation types.
+var AllBulkOperationTypes = []BulkOperationType{
+	BulkOperationTypeUpdate,
+	BulkOperationTypeHibernate,
+	BulkOperationTypeWakeUp,
+	BulkOperationTypeDelete,
+	BulkOperationTypeAddAnnotations,
+}
+
+// IsSupported returns whether the bulk operation type is supported.
+func (t BulkOperationType) IsSupported() bool {
+	for _, supported := range AllBulkOperationTypes {
+		if t == supported {
+			return true
+		}
+	}
+
+	return false
+}
+
+// InstallationState returns the installation state requested by the
+// operation type, or an empty string for operations which do not change the
+// installation state.
+func (t BulkOperationType) InstallationState() string {
+	switch t {
+	case BulkOperationTypeUpdate:
+		return InstallationStateUpdateRequested
+	case BulkOperationTypeHibernate:
+		return InstallationStateHibernationRequested
+	case BulkOperationTypeWakeUp:
+		return InstallationStateWakeUpRequested
+	case BulkOperationTypeDelete:
+		return InstallationStateDeletionRequested
+	}
+
+	return ""
+}
+
+// SettledInstallationState returns the installation state reached once the
+// installation state change requested by the operation type is done.
+func (t BulkOperationType) SettledInstallationState() string {
+	switch t {
+	case BulkOperationTypeUpdate, BulkOperationTypeWakeUp:
+		return InstallationStateStable
+	case BulkOperationTypeHibernate:
+		return InstallationStateHibernating
+	case BulkOperationTypeDelete:
+		return InstallationStateDeleted
+	}
+
+	return ""
+}
+
+// BulkOperationState represents the state of a bulk operation.
+type BulkOperationState string
+
+const (
+	// BulkOperationStateInProgress is a bulk operation with installations
+	// still being worked on.
+	BulkOperationStateInProgress BulkOperationState = "in-progress"
+	// BulkOperationStateCompleted is a bulk operation for which all
+	// installations were worked on. Some of them may have failed.
+	BulkOperationStateCompleted BulkOperationState = "completed"
+	// BulkOperationStateCancelled is a bulk operation which was cancelled
+	// before all installations were worked on.
+	BulkOperationStateCancelled BulkOperationState = "cancelled"
+)
+
+// BulkOperationItem is the outcome of a bulk operation for one installation.
+type BulkOperationItem struct {
+	BulkOperationID string
+	InstallationID  string
+	State           BulkOperationItemState
+	// Error describes why the operation failed for the installation.
+	Error    string `json:"Error,omitempty"`
+	UpdateAt int64
+}
+
+// BulkOperationItemState represents the state of a bulk operation item.
+type BulkOperationItemState string
+
+const (
+	// BulkOperationItemStatePending is an installation which was not worked on yet.
+	BulkOperationItemStatePending BulkOperationItemState = "pending"
+	// BulkOperationItemStateRequested is an installation for which a state
+	// change was requested and which did not settle yet.
+	BulkOperationItemStateRequested BulkOperationItemState = "requested"
+	// BulkOperationItemStateSucceeded is an installation the operation was
+	// applied to.
+	BulkOperationItemStateSucceeded BulkOperationItemState = "succeeded"
+	// BulkOperationItemStateFailed is an installation the operation could not
+	// be applied to.
+	BulkOperationItemStateFailed BulkOperationItemState = "failed"
+	// BulkOperationItemStateCancelled is an installation which was not worked
+	// on because the bulk operation was cancelled.
+	BulkOperationItemStateCancelled BulkOperationItemState = "cancelled"
+)
+
+// IsDone returns whether the installation will not be worked on anymore.
+func (i *BulkOperationItem) IsDone() bool {
+	return i.State != BulkOperationItemStatePending && i.State != BulkOperationItemStateRequested
+}
+
+// BulkOperationFilter describes the parameters used to constrain a set of bulk operations.
+type BulkOperationFilter struct {
+	Paging
+	States []BulkOperationState
+}
+
+// IsDeleted returns whether the bulk operation was marked as deleted or not.
+func (o *BulkOperation) IsDeleted() bool {
+	return o.DeleteAt != 0
+}
+
+// IsInProgress returns whether the bulk operation still has installations to
+// work on.
+func (o *BulkOperation) IsInProgress() bool {
+	return o.State == BulkOperationStateInProgress
+}
+
+// NewBulkOperationFromReader will create a BulkOperation from an io.Reader
+// with JSON data.
+func NewBulkOperationFromReader(reader io.Reader) (*BulkOperation, error) {
+	var operation BulkOperation
+	err := json.NewDecoder(reader).Decode(&operation)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode bulk operation")
+	}
+
+	return &operation, nil
+}
+
+// NewBulkOperationsFromReader will create a slice of BulkOperation from an
+// io.Reader with JSON data.
+func NewBulkOperationsFromReader(reader io.Reader) ([]*BulkOperation, error) {
+	operations := []*BulkOperation{}
+	err := json.NewDecoder(reader).Decode(&operations)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode bulk operations")
+	}
+
+	return operations, nil
+}
+
+// NewBulkOperationItemsFromReader will create a slice of BulkOperationItem
+// from an io.Reader with JSON data.
+func NewBulkOperationItemsFromReader(reader io.Reader) ([]*BulkOperationItem, error) {
+	items := []*BulkOperationItem{}
+	err := json.NewDecoder(reader).Decode(&items)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode bulk operation items")
+	}
+
+	return items, nil
+}
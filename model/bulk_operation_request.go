@@ -0,0 +1,121 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// BulkOperationDefaultConcurrency is the default number of installations
+	// worked on at the same time by a bulk operation.
+	BulkOperationDefaultConcurrency = 5
+	// BulkOperationConcurrencyLimit is the upper bound of installations worked
+	// on at the same time by a bulk operation.
+	BulkOperationConcurrencyLimit = 50
+)
+
+// CreateBulkOperationRequest specifies the parameters for a new bulk
+// operation.
+type CreateBulkOperationRequest struct {
+	Type   BulkOperationType
+	Filter BulkOperationInstallationFilter
+	// Patch is required for update operations.
+	Patch *PatchInstallationRequest `json:"Patch,omitempty"`
+	// Annotations are required for add-annotations operations.
+	Annotations []string `json:"Annotations,omitempty"`
+	Concurrency int64
+}
+
+// SetDefaults sets the default values for a bulk operation create request.
+func (request *CreateBulkOperationRequest) SetDefaults() {
+	if request.Concurrency == 0 {
+		request.Concurrency = BulkOperationDefaultConcurrency
+	}
+}
+
+// Validate validates the values of a bulk operation create request.
+func (request *CreateBulkOperationRequest) Validate() error {
+	if !request.Type.IsSupported() {
+		return errors.Errorf("unsupported bulk operation type %q", request.Type)
+	}
+	if request.Concurrency < 1 || request.Concurrency > BulkOperationConcurrencyLimit {
+		return errors.Errorf("concurrency must be between 1 and %d", BulkOperationConcurrencyLimit)
+	}
+
+	filter := request.Filter
+	if filter.OwnerID == "" && filter.GroupID == "" && filter.State == "" &&
+		len(filter.Annotations) == 0 && filter.DNSPattern == "" {
+		return errors.New("must specify at least one installation filter")
+	}
+	_, err := AnnotationsFromStringSlice(filter.Annotations)
+	if err != nil {
+		return errors.Wrap(err, "invalid annotation filter")
+	}
+
+	if request.Type == BulkOperationTypeUpdate {
+		if request.Patch == nil {
+			return errors.New("must specify installation patch for update operation")
+		}
+		err = request.Patch.Validate()
+		if err != nil {
+			return errors.Wrap(err, "invalid installation patch")
+		}
+	} else if request.Patch != nil {
+		return errors.New("installation patch can only be specified for update operation")
+	}
+
+	if request.Type == BulkOperationTypeAddAnnotations {
+		if len(request.Annotations) == 0 {
+			return errors.New("must specify annotations for add-annotations operation")
+		}
+		_, err = AnnotationsFromStringSlice(request.Annotations)
+		if err != nil {
+			return errors.Wrap(err, "invalid annotations")
+		}
+	} else if len(request.Annotations) != 0 {
+		return errors.New("annotations can only be specified for add-annotations operation")
+	}
+
+	return nil
+}
+
+// NewCreateBulkOperationRequestFromReader will create a
+// CreateBulkOperationRequest from an io.Reader with JSON data.
+func NewCreateBulkOperationRequestFromReader(reader io.Reader) (*CreateBulkOperationRequest, error) {
+	var operationRequest CreateBulkOperationRequest
+	err := json.NewDecoder(reader).Decode(&operationRequest)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode bulk operation request")
+	}
+
+	operationRequest.SetDefaults()
+	err = operationRequest.Validate()
+	if err != nil {
+		return nil, errors.Wrap(err, "bulk operation request failed validation")
+	}
+
+	return &operationRequest, nil
+}
+
+// GetBulkOperationsRequest describes the parameters to request a list of
+// bulk operations.
+type GetBulkOperationsRequest struct {
+	Paging
+	State string
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *GetBulkOperationsRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	q.Add("state", request.State)
+	request.Paging.AddToQuery(q)
+
+	u.RawQuery = q.Encode()
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCreateBulkOperationRequestFromReader(t *testing.T) {
+	t.Run("invalid", func(t *testing.T) {
+		request, err := NewCreateBulkOperationRequestFromReader(bytes.NewReader([]byte(
+			"{test",
+		)))
+		require.Error(t, err)
+		require.Nil(t, request)
+	})
+
+	t.Run("valid with defaults", func(t *testing.T) {
+		request, err := NewCreateBulkOperationRequestFromReader(bytes.NewReader([]byte(
+			`{"Type": "hibernate", "Filter": {"OwnerID": "owner", "DNSPattern": "*.example.com"}}`,
+		)))
+		require.NoError(t, err)
+		require.Equal(t, &CreateBulkOperationRequest{
+			Type:        BulkOperationTypeHibernate,
+			Filter:      BulkOperationInstallationFilter{OwnerID: "owner", DNSPattern: "*.example.com"},
+			Concurrency: BulkOperationDefaultConcurrency,
+		}, request)
+	})
+}
+
+func TestCreateBulkOperationRequestValidate(t *testing.T) {
+	version := "5.39.0"
+	blank := ""
+	filter := BulkOperationInstallationFilter{GroupID: "group"}
+
+	for _, testCase := range []struct {
+		description string
+		request     *CreateBulkOperationRequest
+		valid       bool
+	}{
+		{
+			description: "valid",
+			request:     &CreateBulkOperationRequest{Type: BulkOperationTypeWakeUp, Filter: filter, Concurrency: 1},
+			valid:       true,
+		},
+		{
+			description: "valid update",
+			request:     &CreateBulkOperationRequest{Type: BulkOperationTypeUpdate, Filter: filter, Concurrency: 1, Patch: &PatchInstallationRequest{Version: &version}},
+			valid:       true,
+		},
+		{
+			description: "valid add annotations",
+			request:     &CreateBulkOperationRequest{Type: BulkOperationTypeAddAnnotations, Filter: filter, Concurrency: 1, Annotations: []string{"annotation1"}},
+			valid:       true,
+		},
+		{
+			description: "valid annotation filter",
+			request:     &CreateBulkOperationRequest{Type: BulkOperationTypeDelete, Filter: BulkOperationInstallationFilter{Annotations: []string{"annotation1"}}, Concurrency: 1},
+			valid:       true,
+		},
+		{
+			description: "unsupported type",
+			request:     &CreateBulkOperationRequest{Type: "unknown", Filter: filter, Concurrency: 1},
+		},
+		{
+			description: "no filter",
+			request:     &CreateBulkOperationRequest{Type: BulkOperationTypeHibernate, Concurrency: 1},
+		},
+		{
+			description: "invalid annotation filter",
+			request:     &CreateBulkOperationRequest{Type: BulkOperationTypeHibernate, Filter: BulkOperationInstallationFilter{Annotations: []string{"Invalid Annotation"}}, Concurrency: 1},
+		},
+		{
+			description: "no concurrency",
+			request:     &CreateBulkOperationRequest{Type: BulkOperationTypeHibernate, Filter: filter},
+		},
+		{
+			description: "concurrency too high",
+			request:     &CreateBulkOperationRequest{Type: BulkOperationTypeHibernate, Filter: filter, Concurrency: BulkOperationConcurrencyLimit + 1},
+		},
+		{
+			description: "update without patch",
+			request:     &CreateBulkOperationRequest{Type: BulkOperationTypeUpdate, Filter: filter, Concurrency: 1},
+		},
+		{
+			description: "update with invalid patch",
+			request:     &CreateBulkOperationRequest{Type: BulkOperationTypeUpdate, Filter: filter, Concurrency: 1, Patch: &PatchInstallationRequest{Version: &blank}},
+		},
+		{
+			description: "patch without update",
+			request:     &CreateBulkOperationRequest{Type: BulkOperationTypeWakeUp, Filter: filter, Concurrency: 1, Patch: &PatchInstallationRequest{Version: &version}},
+		},
+		{
+			description: "add annotations without annotations",
+			request:     &CreateBulkOperationRequest{Type: BulkOperationTypeAddAnnotations, Filter: filter, Concurrency: 1},
+		},
+		{
+			description: "add invalid annotations",
+			request:     &CreateBulkOperationRequest{Type: BulkOperationTypeAddAnnotations, Filter: filter, Concurrency: 1, Annotations: []string{"Invalid Annotation"}},
+		},
+		{
+			description: "annotations without add annotations",
+			request:     &CreateBulkOperationRequest{Type: BulkOperationTypeHibernate, Filter: filter, Concurrency: 1, Annotations: []string{"annotation1"}},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			if testCase.valid {
+				require.NoError(t, testCase.request.Validate())
+			} else {
+				require.Error(t, testCase.request.Validate())
+			}
+		})
+	}
+}
@@ -41,6 +41,14 @@ func NewClientWithHeaders(address string, headers map[string]string) *Client {
 	}
 }
 
+// NewClientWithAPIKey creates a client to the provisioning server at the given
+// address which authenticates all requests with the provided API key.
+func NewClientWithAPIKey(address, apiKey string) *Client {
+	return NewClientWithHeaders(address, map[string]string{
+		AuthorizationHeader: AuthorizationBearerPrefix + apiKey,
+	})
+}
+
 // closeBody ensures the Body of an http.Response is properly closed.
 func closeBody(r *http.Response) {
 	if r.Body != nil {
@@ -276,6 +284,40 @@ func (c *Client) ResizeCluster(clusterID string, request *PatchClusterSizeReques
 	}
 }
 
+// DrainCluster requests migration of all multitenant installations off the given cluster.
+func (c *Client) DrainCluster(clusterID string, request *ClusterDrainRequest) (*ClusterDrainOperation, error) {
+	resp, err := c.doPost(c.buildURL("/api/cluster/%s/drain", clusterID), request)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		return NewClusterDrainOperationFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetClusterDrainStatus fetches the progress of the most recent drain of the given cluster.
+func (c *Client) GetClusterDrainStatus(clusterID string) (*ClusterDrainStatus, error) {
+	resp, err := c.doGet(c.buildURL("/api/cluster/%s/drain", clusterID))
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return NewClusterDrainStatusFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
 // DeleteCluster deletes the given cluster and all resources contained therein.
 func (c *Client) DeleteCluster(clusterID string) error {
 	resp, err := c.doDelete(c.buildURL("/api/cluster/%s", clusterID))
@@ -553,9 +595,12 @@ func (c *Client) DeleteInstallation(installationID string) error {
 
 // RestoreInstallationDatabase requests installation db restoration from the configured provisioning server.
 func (c *Client) RestoreInstallationDatabase(installationID, backupID string) (*InstallationDBRestorationOperation, error) {
-	resp, err := c.doPost(c.buildURL("/api/installations/operations/database/restorations"),
-		InstallationDBRestorationRequest{BackupID: backupID, InstallationID: installationID},
-	)
+	return c.RequestInstallationDBRestoration(&InstallationDBRestorationRequest{BackupID: backupID, InstallationID: installationID})
+}
+
+// RequestInstallationDBRestoration requests installation db restoration of any mode from the configured provisioning server.
+func (c *Client) RequestInstallationDBRestoration(request *InstallationDBRestorationRequest) (*InstallationDBRestorationOperation, error) {
+	resp, err := c.doPost(c.buildURL("/api/installations/operations/database/restorations"), request)
 	if err != nil {
 		return nil, err
 	}
@@ -610,9 +655,32 @@ func (c *Client) GetInstallationDBRestoration(id string) (*InstallationDBRestora
 	}
 }
 
-// AddInstallationAnnotations adds annotations to the given installation.
-func (c *Client) AddInstallationAnnotations(installationID string, annotationsRequest *AddAnnotationsRequest) (*InstallationDTO, error) {
-	resp, err := c.doPost(c.buildURL("/api/installation/%s/annotations", installationID), annotationsRequest)
+// MigrateInstallationDatabase requests installation database migration from the configured provisioning server.
+func (c *Client) MigrateInstallationDatabase(request *InstallationDBMigrationRequest) (*InstallationDBMigrationOperation, error) {
+	resp, err := c.doPost(c.buildURL("/api/installations/operations/database/migrations"), request)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		return NewDBMigrationOperationFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetInstallationDBMigrationOperations fetches the list of installation db migration operations from the configured provisioning server.
+func (c *Client) GetInstallationDBMigrationOperations(request *GetInstallationDBMigrationOperationsRequest) ([]*InstallationDBMigrationOperation, error) {
+	u, err := url.Parse(c.buildURL("/api/installations/operations/database/migrations"))
+	if err != nil {
+		return nil, err
+	}
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
 	if err != nil {
 		return nil, err
 	}
@@ -620,33 +688,33 @@ func (c *Client) AddInstallationAnnotations(installationID string, annotationsRe
 
 	switch resp.StatusCode {
 	case http.StatusOK:
-		return InstallationDTOFromReader(resp.Body)
+		return NewDBMigrationOperationsFromReader(resp.Body)
 
 	default:
 		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
 	}
 }
 
-// DeleteInstallationAnnotation deletes annotation from the given installation.
-func (c *Client) DeleteInstallationAnnotation(installationID string, annotationName string) error {
-	resp, err := c.doDelete(c.buildURL("/api/installation/%s/annotation/%s", installationID, annotationName))
+// GetInstallationDBMigrationOperation fetches the specified installation db migration operation from the configured provisioning server.
+func (c *Client) GetInstallationDBMigrationOperation(id string) (*InstallationDBMigrationOperation, error) {
+	resp, err := c.doGet(c.buildURL("/api/installations/operations/database/migration/%s", id))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer closeBody(resp)
 
 	switch resp.StatusCode {
-	case http.StatusNoContent:
-		return nil
+	case http.StatusOK:
+		return NewDBMigrationOperationFromReader(resp.Body)
 
 	default:
-		return errors.Errorf("failed with status code %d", resp.StatusCode)
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
 	}
 }
 
-// CreateInstallationBackup triggers backup for the given installation.
-func (c *Client) CreateInstallationBackup(installationID string) (*InstallationBackup, error) {
-	resp, err := c.doPost(c.buildURL("/api/installations/backups"), &InstallationBackupRequest{InstallationID: installationID})
+// CancelInstallationDBMigration cancels the specified installation db migration operation if it has not started yet.
+func (c *Client) CancelInstallationDBMigration(id string) (*InstallationDBMigrationOperation, error) {
+	resp, err := c.doPost(c.buildURL("/api/installations/operations/database/migration/%s/cancel", id), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -654,21 +722,43 @@ func (c *Client) CreateInstallationBackup(installationID string) (*InstallationB
 
 	switch resp.StatusCode {
 	case http.StatusOK:
-		return NewInstallationBackupFromReader(resp.Body)
+		return NewDBMigrationOperationFromReader(resp.Body)
 
 	default:
 		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
 	}
 }
 
-// GetInstallationBackups returns list of installation backups.
-func (c *Client) GetInstallationBackups(request *GetInstallationBackupsRequest) ([]*InstallationBackup, error) {
-	u, err := url.Parse(c.buildURL("/api/installations/backups"))
+// CloneInstallation requests creation of a clone of the given installation from the configured provisioning server.
+// Only hibernated installations can be cloned.
+func (c *Client) CloneInstallation(installationID string, request *InstallationCloneRequest) (*InstallationCloneOperation, error) {
+	resp, err := c.doPost(c.buildURL("/api/installation/%s/clone", installationID), request)
 	if err != nil {
 		return nil, err
 	}
+	defer closeBody(resp)
 
-	request.ApplyToURL(u)
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		return NewInstallationCloneOperationFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// DryRunInstallationScheduling evaluates on which cluster the installation would be scheduled
+// with the given policy. The installation scheduling policy is used if policy is empty.
+func (c *Client) DryRunInstallationScheduling(installationID, policy string) (*InstallationSchedulingResult, error) {
+	u, err := url.Parse(c.buildURL("/api/installation/%s/scheduling/dry-run", installationID))
+	if err != nil {
+		return nil, err
+	}
+	if policy != "" {
+		q := u.Query()
+		q.Add("policy", policy)
+		u.RawQuery = q.Encode()
+	}
 
 	resp, err := c.doGet(u.String())
 	if err != nil {
@@ -678,16 +768,22 @@ func (c *Client) GetInstallationBackups(request *GetInstallationBackupsRequest)
 
 	switch resp.StatusCode {
 	case http.StatusOK:
-		return NewInstallationBackupsFromReader(resp.Body)
+		return NewInstallationSchedulingResultFromReader(resp.Body)
 
 	default:
 		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
 	}
 }
 
-// GetInstallationBackup returns given installation backup.
-func (c *Client) GetInstallationBackup(backupID string) (*InstallationBackup, error) {
-	resp, err := c.doGet(c.buildURL("/api/installations/backup/%s", backupID))
+// GetInstallationCloneOperations fetches the list of installation clone operations from the configured provisioning server.
+func (c *Client) GetInstallationCloneOperations(request *GetInstallationCloneOperationsRequest) ([]*InstallationCloneOperation, error) {
+	u, err := url.Parse(c.buildURL("/api/installations/operations/clones"))
+	if err != nil {
+		return nil, err
+	}
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
 	if err != nil {
 		return nil, err
 	}
@@ -695,57 +791,53 @@ func (c *Client) GetInstallationBackup(backupID string) (*InstallationBackup, er
 
 	switch resp.StatusCode {
 	case http.StatusOK:
-		return NewInstallationBackupFromReader(resp.Body)
+		return NewInstallationCloneOperationsFromReader(resp.Body)
 
 	default:
 		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
 	}
 }
 
-// DeleteInstallationBackup deletes given installation backup.
-func (c *Client) DeleteInstallationBackup(backupID string) error {
-	resp, err := c.doDelete(c.buildURL("/api/installations/backup/%s", backupID))
+// GetInstallationCloneOperation fetches the specified installation clone operation from the configured provisioning server.
+func (c *Client) GetInstallationCloneOperation(id string) (*InstallationCloneOperation, error) {
+	resp, err := c.doGet(c.buildURL("/api/installations/operations/clone/%s", id))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer closeBody(resp)
 
 	switch resp.StatusCode {
-	case http.StatusAccepted:
-		return nil
+	case http.StatusOK:
+		return NewInstallationCloneOperationFromReader(resp.Body)
 
 	default:
-		return errors.Errorf("failed with status code %d", resp.StatusCode)
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
 	}
 }
 
-// GetClusterInstallation fetches the specified cluster installation from the configured provisioning server.
-func (c *Client) GetClusterInstallation(clusterInstallationID string) (*ClusterInstallation, error) {
-	resp, err := c.doGet(c.buildURL("/api/cluster_installation/%s", clusterInstallationID))
+// MigrateInstallationCluster requests installation migration to another cluster from the configured provisioning server.
+func (c *Client) MigrateInstallationCluster(request *InstallationClusterMigrationRequest) (*InstallationClusterMigrationOperation, error) {
+	resp, err := c.doPost(c.buildURL("/api/installations/operations/cluster/migrations"), request)
 	if err != nil {
 		return nil, err
 	}
 	defer closeBody(resp)
 
 	switch resp.StatusCode {
-	case http.StatusOK:
-		return ClusterInstallationFromReader(resp.Body)
-
-	case http.StatusNotFound:
-		return nil, nil
+	case http.StatusAccepted:
+		return NewInstallationClusterMigrationOperationFromReader(resp.Body)
 
 	default:
 		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
 	}
 }
 
-// GetClusterInstallations fetches the list of cluster installations from the configured provisioning server.
-func (c *Client) GetClusterInstallations(request *GetClusterInstallationsRequest) ([]*ClusterInstallation, error) {
-	u, err := url.Parse(c.buildURL("/api/cluster_installations"))
+// GetInstallationClusterMigrationOperations fetches the list of installation cluster migration operations from the configured provisioning server.
+func (c *Client) GetInstallationClusterMigrationOperations(request *GetInstallationClusterMigrationOperationsRequest) ([]*InstallationClusterMigrationOperation, error) {
+	u, err := url.Parse(c.buildURL("/api/installations/operations/cluster/migrations"))
 	if err != nil {
 		return nil, err
 	}
-
 	request.ApplyToURL(u)
 
 	resp, err := c.doGet(u.String())
@@ -756,16 +848,16 @@ func (c *Client) GetClusterInstallations(request *GetClusterInstallationsRequest
 
 	switch resp.StatusCode {
 	case http.StatusOK:
-		return ClusterInstallationsFromReader(resp.Body)
+		return NewInstallationClusterMigrationOperationsFromReader(resp.Body)
 
 	default:
 		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
 	}
 }
 
-// GetClusterInstallationConfig fetches the specified cluster installation's Mattermost config.
-func (c *Client) GetClusterInstallationConfig(clusterInstallationID string) (map[string]interface{}, error) {
-	resp, err := c.doGet(c.buildURL("/api/cluster_installation/%s/config", clusterInstallationID))
+// GetInstallationClusterMigrationOperation fetches the specified installation cluster migration operation from the configured provisioning server.
+func (c *Client) GetInstallationClusterMigrationOperation(id string) (*InstallationClusterMigrationOperation, error) {
+	resp, err := c.doGet(c.buildURL("/api/installations/operations/cluster/migration/%s", id))
 	if err != nil {
 		return nil, err
 	}
@@ -773,76 +865,74 @@ func (c *Client) GetClusterInstallationConfig(clusterInstallationID string) (map
 
 	switch resp.StatusCode {
 	case http.StatusOK:
-		return ClusterInstallationConfigFromReader(resp.Body)
-
-	case http.StatusNotFound:
-		return nil, nil
+		return NewInstallationClusterMigrationOperationFromReader(resp.Body)
 
 	default:
 		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
 	}
 }
 
-// SetClusterInstallationConfig modifies an cluster installation's Mattermost configuration.
-//
-// The operation is applied as a patch, preserving existing values if they are not specified.
-func (c *Client) SetClusterInstallationConfig(clusterInstallationID string, config map[string]interface{}) error {
-	resp, err := c.doPut(c.buildURL("/api/cluster_installation/%s/config", clusterInstallationID), config)
+// AddInstallationAnnotations adds annotations to the given installation.
+func (c *Client) AddInstallationAnnotations(installationID string, annotationsRequest *AddAnnotationsRequest) (*InstallationDTO, error) {
+	resp, err := c.doPost(c.buildURL("/api/installation/%s/annotations", installationID), annotationsRequest)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer closeBody(resp)
 
 	switch resp.StatusCode {
 	case http.StatusOK:
-		return nil
+		return InstallationDTOFromReader(resp.Body)
 
 	default:
-		return errors.Errorf("failed with status code %d", resp.StatusCode)
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
 	}
 }
 
-// RunMattermostCLICommandOnClusterInstallation runs a Mattermost CLI command against a cluster installation.
-func (c *Client) RunMattermostCLICommandOnClusterInstallation(clusterInstallationID string, subcommand []string) ([]byte, error) {
-	resp, err := c.doPost(c.buildURL("/api/cluster_installation/%s/mattermost_cli", clusterInstallationID), subcommand)
+// DeleteInstallationAnnotation deletes annotation from the given installation.
+func (c *Client) DeleteInstallationAnnotation(installationID string, annotationName string) error {
+	resp, err := c.doDelete(c.buildURL("/api/installation/%s/annotation/%s", installationID, annotationName))
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer closeBody(resp)
 
-	bytes, _ := ioutil.ReadAll(resp.Body)
-
 	switch resp.StatusCode {
-	case http.StatusOK:
-		return bytes, nil
+	case http.StatusNoContent:
+		return nil
 
 	default:
-		return bytes, errors.Errorf("failed with status code %d", resp.StatusCode)
+		return errors.Errorf("failed with status code %d", resp.StatusCode)
 	}
 }
 
-// ExecClusterInstallationCLI runs a valid exec command against a cluster installation.
-func (c *Client) ExecClusterInstallationCLI(clusterInstallationID, command string, subcommand []string) ([]byte, error) {
-	resp, err := c.doPost(c.buildURL("/api/cluster_installation/%s/exec/%s", clusterInstallationID, command), subcommand)
+// CreateInstallationBackup triggers backup for the given installation.
+func (c *Client) CreateInstallationBackup(installationID string) (*InstallationBackup, error) {
+	resp, err := c.doPost(c.buildURL("/api/installations/backups"), &InstallationBackupRequest{InstallationID: installationID})
 	if err != nil {
 		return nil, err
 	}
 	defer closeBody(resp)
 
-	bytes, _ := ioutil.ReadAll(resp.Body)
-
 	switch resp.StatusCode {
 	case http.StatusOK:
-		return bytes, nil
+		return NewInstallationBackupFromReader(resp.Body)
 
 	default:
-		return bytes, errors.Errorf("failed with status code %d", resp.StatusCode)
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
 	}
 }
 
-// CreateGroup requests the creation of a group from the configured provisioning server.
-func (c *Client) CreateGroup(request *CreateGroupRequest) (*Group, error) {
-	resp, err := c.doPost(c.buildURL("/api/groups"), request)
+// GetInstallationBackups returns list of installation backups.
+func (c *Client) GetInstallationBackups(request *GetInstallationBackupsRequest) ([]*InstallationBackup, error) {
+	u, err := url.Parse(c.buildURL("/api/installations/backups"))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
 	if err != nil {
 		return nil, err
 	}
@@ -850,16 +940,16 @@ func (c *Client) CreateGroup(request *CreateGroupRequest) (*Group, error) {
 
 	switch resp.StatusCode {
 	case http.StatusOK:
-		return GroupFromReader(resp.Body)
+		return NewInstallationBackupsFromReader(resp.Body)
 
 	default:
 		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
 	}
 }
 
-// UpdateGroup updates the installation group.
-func (c *Client) UpdateGroup(request *PatchGroupRequest) (*Group, error) {
-	resp, err := c.doPut(c.buildURL("/api/group/%s", request.ID), request)
+// GetInstallationBackup returns given installation backup.
+func (c *Client) GetInstallationBackup(backupID string) (*InstallationBackup, error) {
+	resp, err := c.doGet(c.buildURL("/api/installations/backup/%s", backupID))
 	if err != nil {
 		return nil, err
 	}
@@ -867,23 +957,23 @@ func (c *Client) UpdateGroup(request *PatchGroupRequest) (*Group, error) {
 
 	switch resp.StatusCode {
 	case http.StatusOK:
-		return GroupFromReader(resp.Body)
+		return NewInstallationBackupFromReader(resp.Body)
 
 	default:
 		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
 	}
 }
 
-// DeleteGroup deletes the given group and all resources contained therein.
-func (c *Client) DeleteGroup(groupID string) error {
-	resp, err := c.doDelete(c.buildURL("/api/group/%s", groupID))
+// DeleteInstallationBackup deletes given installation backup.
+func (c *Client) DeleteInstallationBackup(backupID string) error {
+	resp, err := c.doDelete(c.buildURL("/api/installations/backup/%s", backupID))
 	if err != nil {
 		return err
 	}
 	defer closeBody(resp)
 
 	switch resp.StatusCode {
-	case http.StatusOK:
+	case http.StatusAccepted:
 		return nil
 
 	default:
@@ -891,9 +981,9 @@ func (c *Client) DeleteGroup(groupID string) error {
 	}
 }
 
-// GetGroup fetches the specified group from the configured provisioning server.
-func (c *Client) GetGroup(groupID string) (*Group, error) {
-	resp, err := c.doGet(c.buildURL("/api/group/%s", groupID))
+// CreateInstallationBackupSchedule creates a backup schedule for an installation or a group.
+func (c *Client) CreateInstallationBackupSchedule(request *CreateInstallationBackupScheduleRequest) (*InstallationBackupSchedule, error) {
+	resp, err := c.doPost(c.buildURL("/api/installations/backups/schedules"), request)
 	if err != nil {
 		return nil, err
 	}
@@ -901,19 +991,16 @@ func (c *Client) GetGroup(groupID string) (*Group, error) {
 
 	switch resp.StatusCode {
 	case http.StatusOK:
-		return GroupFromReader(resp.Body)
-
-	case http.StatusNotFound:
-		return nil, nil
+		return NewInstallationBackupScheduleFromReader(resp.Body)
 
 	default:
 		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
 	}
 }
 
-// GetGroups fetches the list of groups from the configured provisioning server.
-func (c *Client) GetGroups(request *GetGroupsRequest) ([]*Group, error) {
-	u, err := url.Parse(c.buildURL("/api/groups"))
+// GetInstallationBackupSchedules returns list of installation backup schedules.
+func (c *Client) GetInstallationBackupSchedules(request *GetInstallationBackupSchedulesRequest) ([]*InstallationBackupSchedule, error) {
+	u, err := url.Parse(c.buildURL("/api/installations/backups/schedules"))
 	if err != nil {
 		return nil, err
 	}
@@ -928,16 +1015,16 @@ func (c *Client) GetGroups(request *GetGroupsRequest) ([]*Group, error) {
 
 	switch resp.StatusCode {
 	case http.StatusOK:
-		return GroupsFromReader(resp.Body)
+		return NewInstallationBackupSchedulesFromReader(resp.Body)
 
 	default:
 		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
 	}
 }
 
-// GetGroupStatus fetches the status for specified group from the configured provisioning server.
-func (c *Client) GetGroupStatus(groupID string) (*GroupStatus, error) {
-	resp, err := c.doGet(c.buildURL("/api/group/%s/status", groupID))
+// GetInstallationBackupSchedule returns given installation backup schedule.
+func (c *Client) GetInstallationBackupSchedule(scheduleID string) (*InstallationBackupSchedule, error) {
+	resp, err := c.doGet(c.buildURL("/api/installations/backups/schedule/%s", scheduleID))
 	if err != nil {
 		return nil, err
 	}
@@ -945,7 +1032,7 @@ func (c *Client) GetGroupStatus(groupID string) (*GroupStatus, error) {
 
 	switch resp.StatusCode {
 	case http.StatusOK:
-		return GroupStatusFromReader(resp.Body)
+		return NewInstallationBackupScheduleFromReader(resp.Body)
 
 	case http.StatusNotFound:
 		return nil, nil
@@ -955,75 +1042,574 @@ func (c *Client) GetGroupStatus(groupID string) (*GroupStatus, error) {
 	}
 }
 
-// GetGroupsStatus fetches the status for all groups.
-func (c *Client) GetGroupsStatus() ([]*GroupsStatus, error) {
-	resp, err := c.doGet(c.buildURL("/api/groups/status"))
+// DeleteInstallationBackupSchedule deletes given installation backup schedule.
+func (c *Client) DeleteInstallationBackupSchedule(scheduleID string) error {
+	resp, err := c.doDelete(c.buildURL("/api/installations/backups/schedule/%s", scheduleID))
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer closeBody(resp)
 
 	switch resp.StatusCode {
-	case http.StatusOK:
-		return GroupsStatusFromReader(resp.Body)
-
-	case http.StatusNotFound:
-		return nil, nil
+	case http.StatusNoContent:
+		return nil
 
 	default:
-		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+		return errors.Errorf("failed with status code %d", resp.StatusCode)
 	}
 }
 
-// JoinGroup joins an installation to the given group, leaving any existing group.
-func (c *Client) JoinGroup(groupID, installationID string) error {
-	resp, err := c.doPut(c.buildURL("/api/installation/%s/group/%s", installationID, groupID), nil)
+// CreateScheduledOperation schedules an installation state change.
+func (c *Client) CreateScheduledOperation(request *CreateScheduledOperationRequest) (*ScheduledOperation, error) {
+	resp, err := c.doPost(c.buildURL("/api/installations/scheduled_operations"), request)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer closeBody(resp)
 
 	switch resp.StatusCode {
 	case http.StatusOK:
-		return nil
+		return NewScheduledOperationFromReader(resp.Body)
 
 	default:
-		return errors.Errorf("failed with status code %d", resp.StatusCode)
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
 	}
 }
 
-// LeaveGroup removes an installation from its group, if any.
-func (c *Client) LeaveGroup(installationID string, request *LeaveGroupRequest) error {
-	u, err := url.Parse(c.buildURL("/api/installation/%s/group", installationID))
+// GetScheduledOperations returns list of scheduled operations.
+func (c *Client) GetScheduledOperations(request *GetScheduledOperationsRequest) ([]*ScheduledOperation, error) {
+	u, err := url.Parse(c.buildURL("/api/installations/scheduled_operations"))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	request.ApplyToURL(u)
 
-	resp, err := c.doDelete(u.String())
+	resp, err := c.doGet(u.String())
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer closeBody(resp)
 
 	switch resp.StatusCode {
 	case http.StatusOK:
-		return nil
+		return NewScheduledOperationsFromReader(resp.Body)
 
 	default:
-		return errors.Errorf("failed with status code %d", resp.StatusCode)
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
 	}
 }
 
-// GetMultitenantDatabases fetches the list of multitenant databases from the configured provisioning server.
-func (c *Client) GetMultitenantDatabases(request *GetDatabasesRequest) ([]*MultitenantDatabase, error) {
-	u, err := url.Parse(c.buildURL("/api/databases"))
+// GetScheduledOperation returns given scheduled operation.
+func (c *Client) GetScheduledOperation(operationID string) (*ScheduledOperation, error) {
+	resp, err := c.doGet(c.buildURL("/api/installations/scheduled_operation/%s", operationID))
 	if err != nil {
 		return nil, err
 	}
+	defer closeBody(resp)
 
-	request.ApplyToURL(u)
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return NewScheduledOperationFromReader(resp.Body)
+
+	case http.StatusNotFound:
+		return nil, nil
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// CancelScheduledOperation cancels given scheduled operation.
+func (c *Client) CancelScheduledOperation(operationID string) error {
+	resp, err := c.doDelete(c.buildURL("/api/installations/scheduled_operation/%s", operationID))
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusNoContent:
+		return nil
+
+	default:
+		return errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// CreateBulkOperation starts applying an operation to all installations
+// matching a filter.
+func (c *Client) CreateBulkOperation(request *CreateBulkOperationRequest) (*BulkOperation, error) {
+	resp, err := c.doPost(c.buildURL("/api/installations/bulk_operations"), request)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return NewBulkOperationFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetBulkOperations returns list of bulk operations.
+func (c *Client) GetBulkOperations(request *GetBulkOperationsRequest) ([]*BulkOperation, error) {
+	u, err := url.Parse(c.buildURL("/api/installations/bulk_operations"))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return NewBulkOperationsFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetBulkOperation returns given bulk operation.
+func (c *Client) GetBulkOperation(operationID string) (*BulkOperation, error) {
+	resp, err := c.doGet(c.buildURL("/api/installations/bulk_operation/%s", operationID))
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return NewBulkOperationFromReader(resp.Body)
+
+	case http.StatusNotFound:
+		return nil, nil
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetBulkOperationItems returns the outcome of given bulk operation for each
+// installation.
+func (c *Client) GetBulkOperationItems(operationID string) ([]*BulkOperationItem, error) {
+	resp, err := c.doGet(c.buildURL("/api/installations/bulk_operation/%s/items", operationID))
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return NewBulkOperationItemsFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// CancelBulkOperation cancels given bulk operation.
+func (c *Client) CancelBulkOperation(operationID string) error {
+	resp, err := c.doDelete(c.buildURL("/api/installations/bulk_operation/%s", operationID))
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusNoContent:
+		return nil
+
+	default:
+		return errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetClusterInstallation fetches the specified cluster installation from the configured provisioning server.
+func (c *Client) GetClusterInstallation(clusterInstallationID string) (*ClusterInstallation, error) {
+	resp, err := c.doGet(c.buildURL("/api/cluster_installation/%s", clusterInstallationID))
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return ClusterInstallationFromReader(resp.Body)
+
+	case http.StatusNotFound:
+		return nil, nil
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetClusterInstallations fetches the list of cluster installations from the configured provisioning server.
+func (c *Client) GetClusterInstallations(request *GetClusterInstallationsRequest) ([]*ClusterInstallation, error) {
+	u, err := url.Parse(c.buildURL("/api/cluster_installations"))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return ClusterInstallationsFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetClusterInstallationConfig fetches the specified cluster installation's Mattermost config.
+func (c *Client) GetClusterInstallationConfig(clusterInstallationID string) (map[string]interface{}, error) {
+	resp, err := c.doGet(c.buildURL("/api/cluster_installation/%s/config", clusterInstallationID))
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return ClusterInstallationConfigFromReader(resp.Body)
+
+	case http.StatusNotFound:
+		return nil, nil
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// SetClusterInstallationConfig modifies an cluster installation's Mattermost configuration.
+//
+// The operation is applied as a patch, preserving existing values if they are not specified.
+func (c *Client) SetClusterInstallationConfig(clusterInstallationID string, config map[string]interface{}) error {
+	resp, err := c.doPut(c.buildURL("/api/cluster_installation/%s/config", clusterInstallationID), config)
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+
+	default:
+		return errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// RunMattermostCLICommandOnClusterInstallation runs a Mattermost CLI command against a cluster installation.
+func (c *Client) RunMattermostCLICommandOnClusterInstallation(clusterInstallationID string, subcommand []string) ([]byte, error) {
+	resp, err := c.doPost(c.buildURL("/api/cluster_installation/%s/mattermost_cli", clusterInstallationID), subcommand)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	bytes, _ := ioutil.ReadAll(resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return bytes, nil
+
+	default:
+		return bytes, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// ExecClusterInstallationCLI runs a valid exec command against a cluster installation.
+func (c *Client) ExecClusterInstallationCLI(clusterInstallationID, command string, subcommand []string) ([]byte, error) {
+	resp, err := c.doPost(c.buildURL("/api/cluster_installation/%s/exec/%s", clusterInstallationID, command), subcommand)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	bytes, _ := ioutil.ReadAll(resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return bytes, nil
+
+	default:
+		return bytes, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// CreateGroup requests the creation of a group from the configured provisioning server.
+func (c *Client) CreateGroup(request *CreateGroupRequest) (*Group, error) {
+	resp, err := c.doPost(c.buildURL("/api/groups"), request)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return GroupFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// UpdateGroup updates the installation group.
+func (c *Client) UpdateGroup(request *PatchGroupRequest) (*Group, error) {
+	resp, err := c.doPut(c.buildURL("/api/group/%s", request.ID), request)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return GroupFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// DeleteGroup deletes the given group and all resources contained therein.
+func (c *Client) DeleteGroup(groupID string) error {
+	resp, err := c.doDelete(c.buildURL("/api/group/%s", groupID))
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+
+	default:
+		return errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetGroup fetches the specified group from the configured provisioning server.
+func (c *Client) GetGroup(groupID string) (*Group, error) {
+	resp, err := c.doGet(c.buildURL("/api/group/%s", groupID))
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return GroupFromReader(resp.Body)
+
+	case http.StatusNotFound:
+		return nil, nil
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetGroups fetches the list of groups from the configured provisioning server.
+func (c *Client) GetGroups(request *GetGroupsRequest) ([]*Group, error) {
+	u, err := url.Parse(c.buildURL("/api/groups"))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return GroupsFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetGroupStatus fetches the status for specified group from the configured provisioning server.
+func (c *Client) GetGroupStatus(groupID string) (*GroupStatus, error) {
+	resp, err := c.doGet(c.buildURL("/api/group/%s/status", groupID))
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return GroupStatusFromReader(resp.Body)
+
+	case http.StatusNotFound:
+		return nil, nil
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetGroupHistory fetches the configuration history of the given group, most
+// recent first.
+func (c *Client) GetGroupHistory(groupID string) ([]*GroupConfig, error) {
+	resp, err := c.doGet(c.buildURL("/api/group/%s/history", groupID))
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return GroupConfigsFromReader(resp.Body)
+
+	case http.StatusNotFound:
+		return nil, nil
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// RollbackGroup applies the configuration of a previous sequence to the group.
+func (c *Client) RollbackGroup(groupID string, request *RollbackGroupRequest) (*Group, error) {
+	u, err := url.Parse(c.buildURL("/api/group/%s/rollback", groupID))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doPost(u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return GroupFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// ResumeGroupRollout resumes the paused rollout of the group configuration.
+func (c *Client) ResumeGroupRollout(groupID string) (*Group, error) {
+	return c.updateGroupRollout(groupID, "resume")
+}
+
+// AbortGroupRollout aborts the rollout of the group configuration.
+func (c *Client) AbortGroupRollout(groupID string) (*Group, error) {
+	return c.updateGroupRollout(groupID, "abort")
+}
+
+func (c *Client) updateGroupRollout(groupID, action string) (*Group, error) {
+	resp, err := c.doPost(c.buildURL("/api/group/%s/rollout/%s", groupID, action), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return GroupFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetGroupsStatus fetches the status for all groups.
+func (c *Client) GetGroupsStatus() ([]*GroupsStatus, error) {
+	resp, err := c.doGet(c.buildURL("/api/groups/status"))
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return GroupsStatusFromReader(resp.Body)
+
+	case http.StatusNotFound:
+		return nil, nil
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// JoinGroup joins an installation to the given group, leaving any existing group.
+func (c *Client) JoinGroup(groupID, installationID string) error {
+	resp, err := c.doPut(c.buildURL("/api/installation/%s/group/%s", installationID, groupID), nil)
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+
+	default:
+		return errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// LeaveGroup removes an installation from its group, if any.
+func (c *Client) LeaveGroup(installationID string, request *LeaveGroupRequest) error {
+	u, err := url.Parse(c.buildURL("/api/installation/%s/group", installationID))
+	if err != nil {
+		return err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doDelete(u.String())
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+
+	default:
+		return errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetMultitenantDatabases fetches the list of multitenant databases from the configured provisioning server.
+func (c *Client) GetMultitenantDatabases(request *GetDatabasesRequest) ([]*MultitenantDatabase, error) {
+	u, err := url.Parse(c.buildURL("/api/databases"))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
 
 	resp, err := c.doGet(u.String())
 	if err != nil {
@@ -1101,6 +1687,30 @@ func (c *Client) GetWebhooks(request *GetWebhooksRequest) ([]*Webhook, error) {
 	}
 }
 
+// GetWebhookDeliveries fetches the list of deliveries to the given webhook from the configured provisioning server.
+func (c *Client) GetWebhookDeliveries(webhookID string, request *GetWebhookDeliveriesRequest) ([]*WebhookDelivery, error) {
+	u, err := url.Parse(c.buildURL("/api/webhook/%s/deliveries", webhookID))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return WebhookDeliveriesFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
 // DeleteWebhook deletes the given webhook.
 func (c *Client) DeleteWebhook(webhookID string) error {
 	resp, err := c.doDelete(c.buildURL("/api/webhook/%s", webhookID))
@@ -1118,6 +1728,109 @@ func (c *Client) DeleteWebhook(webhookID string) error {
 	}
 }
 
+// GetEvents fetches the list of resource state change events from the configured provisioning server.
+func (c *Client) GetEvents(request *GetEventsRequest) ([]*Event, error) {
+	u, err := url.Parse(c.buildURL("/api/events"))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return EventsFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// CreateAPIKey requests the creation of an API key from the configured provisioning server.
+// The returned API key contains the plain text key which cannot be retrieved later.
+func (c *Client) CreateAPIKey(request *CreateAPIKeyRequest) (*APIKey, error) {
+	resp, err := c.doPost(c.buildURL("/api/apikeys"), request)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return APIKeyFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetAPIKey fetches the API key from the configured provisioning server.
+func (c *Client) GetAPIKey(apiKeyID string) (*APIKey, error) {
+	resp, err := c.doGet(c.buildURL("/api/apikey/%s", apiKeyID))
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return APIKeyFromReader(resp.Body)
+
+	case http.StatusNotFound:
+		return nil, nil
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetAPIKeys fetches the list of API keys from the configured provisioning server.
+func (c *Client) GetAPIKeys(request *GetAPIKeysRequest) ([]*APIKey, error) {
+	u, err := url.Parse(c.buildURL("/api/apikeys"))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return APIKeysFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// DeleteAPIKey revokes the given API key.
+func (c *Client) DeleteAPIKey(apiKeyID string) error {
+	resp, err := c.doDelete(c.buildURL("/api/apikey/%s", apiKeyID))
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+
+	default:
+		return errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
 // LockAPIForCluster locks API changes for a given cluster.
 func (c *Client) LockAPIForCluster(clusterID string) error {
 	return c.makeSecurityCall("cluster", clusterID, "api", "lock")
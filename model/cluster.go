@@ -12,20 +12,21 @@ import (
 
 // Cluster represents a Kubernetes cluster.
 type Cluster struct {
-	ID                      string
-	State                   string
-	Provider                string
-	ProviderMetadataAWS     *AWSMetadata
-	Provisioner             string
-	ProvisionerMetadataKops *KopsMetadata
-	UtilityMetadata         *UtilityMetadata
-	AllowInstallations      bool
-	CreateAt                int64
-	DeleteAt                int64
-	APISecurityLock         bool
-	LockAcquiredBy          *string
-	LockAcquiredAt          int64
-	Networking              string
+	ID                       string
+	State                    string
+	Provider                 string
+	ProviderMetadataAWS      *AWSMetadata
+	Provisioner              string
+	ProvisionerMetadataKops  *KopsMetadata
+	ProvisionerMetadataLocal *LocalMetadata `json:"ProvisionerMetadataLocal,omitempty"`
+	UtilityMetadata          *UtilityMetadata
+	AllowInstallations       bool
+	CreateAt                 int64
+	DeleteAt                 int64
+	APISecurityLock          bool
+	LockAcquiredBy           *string
+	LockAcquiredAt           int64
+	Networking               string
 }
 
 // Clone returns a deep copy the cluster.
@@ -78,8 +79,10 @@ type ClusterFilter struct {
 
 // AnnotationsFilter describes filter based on Annotations.
 type AnnotationsFilter struct {
-	// MatchAllIDs contains all Annotation IDs which need to be set on a Cluster for it to be included in the result.
+	// MatchAllIDs contains all Annotation IDs which need to be set on a Cluster or Installation for it to be included in the result.
 	MatchAllIDs []string
+	// MatchAnyIDs contains Annotation IDs of which at least one needs to be set on a Cluster or Installation for it to be included in the result.
+	MatchAnyIDs []string
 }
 
 var clusterVersionMatcher = regexp.MustCompile(`^(([0-9]{1,3}.[0-9]{1,3}.[0-9]{1,3})|(latest))$`)
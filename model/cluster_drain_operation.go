@@ -0,0 +1,108 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// ClusterDrainOperation contains information about cluster drain operation.
+// Draining a cluster disallows new installations on it and migrates all
+// multitenant installations to other clusters.
+type ClusterDrainOperation struct {
+	ID        string
+	ClusterID string
+	// MaxConcurrentMigrations is the maximum number of installation cluster
+	// migrations running at the same time for the drain.
+	MaxConcurrentMigrations int64
+	RequestAt               int64
+	State                   ClusterDrainOperationState
+	CompleteAt              int64
+	DeleteAt                int64
+	LockAcquiredBy          *string
+	LockAcquiredAt          int64
+}
+
+// ClusterDrainOperationState represents the state of cluster drain operation.
+type ClusterDrainOperationState string
+
+const (
+	// ClusterDrainStateRequested is requested cluster drain operation.
+	ClusterDrainStateRequested ClusterDrainOperationState = "cluster-drain-requested"
+	// ClusterDrainStateInProgress is cluster drain operation migrating installations off the cluster.
+	ClusterDrainStateInProgress ClusterDrainOperationState = "cluster-drain-in-progress"
+	// ClusterDrainStateSucceeded is cluster drain operation that moved all multitenant installations off the cluster.
+	ClusterDrainStateSucceeded ClusterDrainOperationState = "cluster-drain-succeeded"
+	// ClusterDrainStateFailed is cluster drain operation that finished with some multitenant installations left on the cluster.
+	ClusterDrainStateFailed ClusterDrainOperationState = "cluster-drain-failed"
+)
+
+// AllClusterDrainOperationsStatesPendingWork is a list of all cluster drain operations states
+// that the supervisor will attempt to transition towards stable on the next "tick".
+var AllClusterDrainOperationsStatesPendingWork = []ClusterDrainOperationState{
+	ClusterDrainStateRequested,
+	ClusterDrainStateInProgress,
+}
+
+// ClusterDrainFilter describes the parameters used to constrain a set of cluster drain operations.
+type ClusterDrainFilter struct {
+	Paging
+	IDs       []string
+	ClusterID string
+	States    []ClusterDrainOperationState
+}
+
+// ClusterDrainStatus represents the progress of a cluster drain operation.
+type ClusterDrainStatus struct {
+	Operation *ClusterDrainOperation
+	// RemainingInstallations is the number of installations still on the
+	// cluster that are not being migrated at the moment.
+	RemainingInstallations int
+	// MigratingInstallations is the number of installation cluster migrations in progress.
+	MigratingInstallations int
+	// MigratedInstallations is the number of installations successfully moved off the cluster.
+	MigratedInstallations int
+	// FailedMigrations is the number of installation cluster migrations that failed.
+	FailedMigrations int
+}
+
+// NewClusterDrainOperationFromReader will create a ClusterDrainOperation from an
+// io.Reader with JSON data.
+func NewClusterDrainOperationFromReader(reader io.Reader) (*ClusterDrainOperation, error) {
+	var drainOperation ClusterDrainOperation
+	err := json.NewDecoder(reader).Decode(&drainOperation)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode ClusterDrainOperation")
+	}
+
+	return &drainOperation, nil
+}
+
+// NewClusterDrainOperationsFromReader will create a slice of ClusterDrainOperations from an
+// io.Reader with JSON data.
+func NewClusterDrainOperationsFromReader(reader io.Reader) ([]*ClusterDrainOperation, error) {
+	drainOperations := []*ClusterDrainOperation{}
+	err := json.NewDecoder(reader).Decode(&drainOperations)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode ClusterDrainOperations")
+	}
+
+	return drainOperations, nil
+}
+
+// NewClusterDrainStatusFromReader will create a ClusterDrainStatus from an
+// io.Reader with JSON data.
+func NewClusterDrainStatusFromReader(reader io.Reader) (*ClusterDrainStatus, error) {
+	var drainStatus ClusterDrainStatus
+	err := json.NewDecoder(reader).Decode(&drainStatus)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode ClusterDrainStatus")
+	}
+
+	return &drainStatus, nil
+}
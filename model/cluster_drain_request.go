@@ -0,0 +1,60 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ClusterDrainDefaultMaxConcurrentMigrations is the default number of
+	// installations migrated at the same time when draining a cluster.
+	ClusterDrainDefaultMaxConcurrentMigrations = 5
+	// ClusterDrainMaxConcurrentMigrationsLimit is the upper bound of
+	// installations migrated at the same time when draining a cluster.
+	ClusterDrainMaxConcurrentMigrationsLimit = 50
+)
+
+// ClusterDrainRequest represents request for draining a cluster.
+type ClusterDrainRequest struct {
+	MaxConcurrentMigrations int64
+}
+
+// SetDefaults sets the default values for a cluster drain request.
+func (request *ClusterDrainRequest) SetDefaults() {
+	if request.MaxConcurrentMigrations == 0 {
+		request.MaxConcurrentMigrations = ClusterDrainDefaultMaxConcurrentMigrations
+	}
+}
+
+// Validate validates the values of a cluster drain request.
+func (request *ClusterDrainRequest) Validate() error {
+	if request.MaxConcurrentMigrations < 1 || request.MaxConcurrentMigrations > ClusterDrainMaxConcurrentMigrationsLimit {
+		return errors.Errorf("max concurrent migrations must be between 1 and %d", ClusterDrainMaxConcurrentMigrationsLimit)
+	}
+
+	return nil
+}
+
+// NewClusterDrainRequestFromReader will create a ClusterDrainRequest from an
+// io.Reader with JSON data.
+func NewClusterDrainRequestFromReader(reader io.Reader) (*ClusterDrainRequest, error) {
+	var clusterDrainRequest ClusterDrainRequest
+	err := json.NewDecoder(reader).Decode(&clusterDrainRequest)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode cluster drain request")
+	}
+
+	clusterDrainRequest.SetDefaults()
+	err = clusterDrainRequest.Validate()
+	if err != nil {
+		return nil, errors.Wrap(err, "cluster drain request failed validation")
+	}
+
+	return &clusterDrainRequest, nil
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClusterDrainRequestFromReader(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		request, err := NewClusterDrainRequestFromReader(bytes.NewReader([]byte(
+			"",
+		)))
+		require.NoError(t, err)
+		require.Equal(t, &ClusterDrainRequest{MaxConcurrentMigrations: ClusterDrainDefaultMaxConcurrentMigrations}, request)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		request, err := NewClusterDrainRequestFromReader(bytes.NewReader([]byte(
+			"{test",
+		)))
+		require.Error(t, err)
+		require.Nil(t, request)
+	})
+
+	t.Run("invalid concurrency", func(t *testing.T) {
+		request, err := NewClusterDrainRequestFromReader(bytes.NewReader([]byte(
+			`{"MaxConcurrentMigrations": -1}`,
+		)))
+		require.Error(t, err)
+		require.Nil(t, request)
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		request, err := NewClusterDrainRequestFromReader(bytes.NewReader([]byte(
+			`{"MaxConcurrentMigrations": 10}`,
+		)))
+		require.NoError(t, err)
+		require.Equal(t, &ClusterDrainRequest{MaxConcurrentMigrations: 10}, request)
+	})
+}
+
+func TestClusterDrainRequestValidate(t *testing.T) {
+	for _, testCase := range []struct {
+		description string
+		request     *ClusterDrainRequest
+		valid       bool
+	}{
+		{
+			description: "valid",
+			request:     &ClusterDrainRequest{MaxConcurrentMigrations: 1},
+			valid:       true,
+		},
+		{
+			description: "max limit",
+			request:     &ClusterDrainRequest{MaxConcurrentMigrations: ClusterDrainMaxConcurrentMigrationsLimit},
+			valid:       true,
+		},
+		{
+			description: "zero concurrency",
+			request:     &ClusterDrainRequest{},
+			valid:       false,
+		},
+		{
+			description: "concurrency over limit",
+			request:     &ClusterDrainRequest{MaxConcurrentMigrations: ClusterDrainMaxConcurrentMigrationsLimit + 1},
+			valid:       false,
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			err := testCase.request.Validate()
+			if testCase.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
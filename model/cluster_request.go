@@ -15,6 +15,8 @@ import (
 // CreateClusterRequest specifies the parameters for a new cluster.
 type CreateClusterRequest struct {
 	Provider               string                         `json:"provider,omitempty"`
+	Provisioner            string                         `json:"provisioner,omitempty"`
+	KubeconfigPath         string                         `json:"kubeconfig-path,omitempty"`
 	Zones                  []string                       `json:"zones,omitempty"`
 	Version                string                         `json:"version,omitempty"`
 	KopsAMI                string                         `json:"kops-ami,omitempty"`
@@ -36,6 +38,9 @@ func (request *CreateClusterRequest) SetDefaults() {
 	if len(request.Provider) == 0 {
 		request.Provider = ProviderAWS
 	}
+	if len(request.Provisioner) == 0 {
+		request.Provisioner = ProvisionerKops
+	}
 	if len(request.Version) == 0 {
 		request.Version = "latest"
 	}
@@ -88,9 +93,15 @@ func (request *CreateClusterRequest) SetDefaults() {
 
 // Validate validates the values of a cluster create request.
 func (request *CreateClusterRequest) Validate() error {
-	if request.Provider != ProviderAWS {
+	if provider, err := CheckProvider(request.Provider); err != nil || provider != request.Provider {
 		return errors.Errorf("unsupported provider %s", request.Provider)
 	}
+	if request.Provisioner != ProvisionerKops && request.Provisioner != ProvisionerLocal {
+		return errors.Errorf("unsupported provisioner %s", request.Provisioner)
+	}
+	if request.Provisioner == ProvisionerLocal && len(request.KubeconfigPath) == 0 {
+		return errors.New("kubeconfig path must be provided for local clusters")
+	}
 	if !ValidClusterVersion(request.Version) {
 		return errors.Errorf("unsupported cluster version %s", request.Version)
 	}
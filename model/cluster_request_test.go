@@ -19,6 +19,9 @@ func TestCreateClusterRequestValid(t *testing.T) {
 	}{
 		{"defaults", &model.CreateClusterRequest{}, false},
 		{"invalid provider", &model.CreateClusterRequest{Provider: "blah"}, true},
+		{"invalid provisioner", &model.CreateClusterRequest{Provisioner: "blah"}, true},
+		{"local without kubeconfig", &model.CreateClusterRequest{Provisioner: model.ProvisionerLocal}, true},
+		{"local", &model.CreateClusterRequest{Provisioner: model.ProvisionerLocal, KubeconfigPath: "/tmp/kubeconfig"}, false},
 		{"invalid version", &model.CreateClusterRequest{Version: "blah"}, true},
 		{"negative node counts", &model.CreateClusterRequest{NodeMinCount: -1, NodeMaxCount: -1}, true},
 		{"negative master count", &model.CreateClusterRequest{MasterCount: -1}, true},
@@ -0,0 +1,63 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Event is a record of a state change of a resource.
+type Event struct {
+	ID           string
+	ResourceType string
+	ResourceID   string
+	OldState     string
+	NewState     string
+	// Timestamp is the time of the state change in milliseconds since epoch.
+	Timestamp int64
+	// InstanceID is the ID of the provisioner instance which made the change.
+	InstanceID string
+	// Error describes why the state change happened, if it was caused by a failure.
+	Error string `json:"Error,omitempty"`
+}
+
+// EventFilter describes the parameters used to constrain a set of events.
+type EventFilter struct {
+	Paging
+	ResourceType string
+	ResourceID   string
+	OldState     string
+	NewState     string
+	// Since and Until limit the events to the given time range in
+	// milliseconds since epoch. They are ignored when 0.
+	Since int64
+	Until int64
+}
+
+// NewStateChangeEvent creates an event recording the state change described
+// by the given webhook payload.
+func NewStateChangeEvent(payload *WebhookPayload, instanceID string) *Event {
+	return &Event{
+		ResourceType: payload.Type,
+		ResourceID:   payload.ID,
+		OldState:     payload.OldState,
+		NewState:     payload.NewState,
+		InstanceID:   instanceID,
+	}
+}
+
+// EventsFromReader decodes a json-encoded list of events from the given io.Reader.
+func EventsFromReader(reader io.Reader) ([]*Event, error) {
+	events := []*Event{}
+	decoder := json.NewDecoder(reader)
+
+	err := decoder.Decode(&events)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return events, nil
+}
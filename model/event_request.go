@@ -0,0 +1,35 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// GetEventsRequest describes the parameters to request a list of events.
+type GetEventsRequest struct {
+	Paging
+	ResourceType string
+	ResourceID   string
+	Since        int64
+	Until        int64
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *GetEventsRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	q.Add("resource_type", request.ResourceType)
+	q.Add("resource_id", request.ResourceID)
+	if request.Since != 0 {
+		q.Add("since", strconv.FormatInt(request.Since, 10))
+	}
+	if request.Until != 0 {
+		q.Add("until", strconv.FormatInt(request.Until, 10))
+	}
+	request.Paging.AddToQuery(q)
+
+	u.RawQuery = q.Encode()
+}
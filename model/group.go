@@ -9,6 +9,18 @@ import (
 	"io"
 )
 
+const (
+	// GroupRolloutStateActive is a group rollout which is progressing.
+	GroupRolloutStateActive = "active"
+	// GroupRolloutStatePaused is a group rollout which was paused by a
+	// failed health gate and waits to be resumed.
+	GroupRolloutStatePaused = "paused"
+	// GroupRolloutStateAborted is a group rollout which was aborted. The
+	// remaining installations are not updated until the group configuration
+	// changes again.
+	GroupRolloutStateAborted = "aborted"
+)
+
 // Group represents a group of Mattermost installations.
 type Group struct {
 	ID              string
@@ -24,6 +36,24 @@ type Group struct {
 	APISecurityLock bool
 	LockAcquiredBy  *string
 	LockAcquiredAt  int64
+	// CanarySize is the number of installations which are updated first when
+	// rolling out a new group configuration. The rest of the installations
+	// are only updated once all canaries have finished updating, and the
+	// rollout is paused if any of them fails.
+	CanarySize int64
+	// FailureThresholdPercent is the share of updated installations ending
+	// in update-failed at which the rollout is paused. 0 disables the check.
+	FailureThresholdPercent int64
+	RolloutState            string
+	// Size and License are merged into the configuration of the group
+	// installations, so changes to them are rolled out like Version changes.
+	Size    string
+	License string
+	// Affinity, Database and Filestore are inherited by installations when
+	// they are created in the group.
+	Affinity  string
+	Database  string
+	Filestore string
 }
 
 // GroupFilter describes the parameters used to constrain a set of groups.
@@ -45,6 +75,34 @@ func (g *Group) IsDeleted() bool {
 	return g.DeleteAt != 0
 }
 
+// IsRolloutActive returns whether the rollout of the group configuration
+// is neither paused nor aborted.
+func (g *Group) IsRolloutActive() bool {
+	return g.RolloutState == GroupRolloutStateActive
+}
+
+// RolloutPhase returns the phase of the rollout of the group configuration
+// given its progress.
+func (g *Group) RolloutPhase(progress GroupRolloutProgress) string {
+	switch g.RolloutState {
+	case GroupRolloutStatePaused:
+		return GroupRolloutPhasePaused
+	case GroupRolloutStateAborted:
+		return GroupRolloutPhaseAborted
+	}
+	if progress.IsComplete() {
+		return GroupRolloutPhaseComplete
+	}
+	if g.MaxRolling == 0 {
+		return GroupRolloutPhasePaused
+	}
+	if progress.IsCanaryInProgress(g.CanarySize) {
+		return GroupRolloutPhaseCanary
+	}
+
+	return GroupRolloutPhaseRolling
+}
+
 // GroupFromReader decodes a json-encoded group from the given io.Reader.
 func GroupFromReader(reader io.Reader) (*Group, error) {
 	group := Group{}
@@ -0,0 +1,125 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+// GroupConfig is the configuration applied to the installations of a group
+// at a given group sequence.
+type GroupConfig struct {
+	GroupID       string
+	Sequence      int64
+	Version       string
+	Image         string
+	Size          string
+	License       string
+	MattermostEnv EnvVarMap
+	CreateAt      int64
+}
+
+// GroupConfigChange describes a configuration value which differs between two
+// group configurations.
+type GroupConfigChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// NewGroupConfig returns the current configuration of the given group.
+func NewGroupConfig(group *Group) *GroupConfig {
+	return &GroupConfig{
+		GroupID:       group.ID,
+		Sequence:      group.Sequence,
+		Version:       group.Version,
+		Image:         group.Image,
+		Size:          group.Size,
+		License:       group.License,
+		MattermostEnv: group.Clone().MattermostEnv,
+	}
+}
+
+// ApplyTo sets the configuration values of the given group to the ones of the
+// group configuration.
+func (c *GroupConfig) ApplyTo(group *Group) {
+	group.Version = c.Version
+	group.Image = c.Image
+	group.Size = c.Size
+	group.License = c.License
+	group.MattermostEnv = EnvVarMap{}
+	for name, envVar := range c.MattermostEnv {
+		group.MattermostEnv[name] = envVar
+	}
+}
+
+// Diff returns the configuration values which changed from the given previous
+// group configuration. Environment variables are sorted by name.
+func (c *GroupConfig) Diff(previous *GroupConfig) []GroupConfigChange {
+	changes := []GroupConfigChange{}
+	if previous.Version != c.Version {
+		changes = append(changes, GroupConfigChange{Field: "Version", Old: previous.Version, New: c.Version})
+	}
+	if previous.Image != c.Image {
+		changes = append(changes, GroupConfigChange{Field: "Image", Old: previous.Image, New: c.Image})
+	}
+	if previous.Size != c.Size {
+		changes = append(changes, GroupConfigChange{Field: "Size", Old: previous.Size, New: c.Size})
+	}
+	if previous.License != c.License {
+		changes = append(changes, GroupConfigChange{Field: "License", Old: previous.License, New: c.License})
+	}
+
+	var names []string
+	for name := range previous.MattermostEnv {
+		names = append(names, name)
+	}
+	for name := range c.MattermostEnv {
+		if _, ok := previous.MattermostEnv[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		oldValue := envVarDiffValue(previous.MattermostEnv, name)
+		newValue := envVarDiffValue(c.MattermostEnv, name)
+		if oldValue != newValue {
+			changes = append(changes, GroupConfigChange{Field: "MattermostEnv." + name, Old: oldValue, New: newValue})
+		}
+	}
+
+	return changes
+}
+
+// envVarDiffValue returns a printable value of the given env var.
+func envVarDiffValue(envVarMap EnvVarMap, name string) string {
+	envVar, ok := envVarMap[name]
+	if !ok {
+		return ""
+	}
+	if envVar.ValueFrom == nil {
+		return envVar.Value
+	}
+
+	data, _ := json.Marshal(envVar)
+	return string(data)
+}
+
+// GroupConfigsFromReader decodes a json-encoded list of group configurations
+// from the given io.Reader.
+func GroupConfigsFromReader(reader io.Reader) ([]*GroupConfig, error) {
+	groupConfigs := []*GroupConfig{}
+	decoder := json.NewDecoder(reader)
+
+	err := decoder.Decode(&groupConfigs)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return groupConfigs, nil
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestGroupConfigDiff(t *testing.T) {
+	previous := &model.GroupConfig{
+		Version: "5.30.0",
+		Image:   "mattermost/mattermost-enterprise-edition",
+		MattermostEnv: model.EnvVarMap{
+			"KEY1": {Value: "value1"},
+			"KEY2": {Value: "value2"},
+		},
+	}
+
+	t.Run("no changes", func(t *testing.T) {
+		require.Empty(t, previous.Diff(previous))
+	})
+
+	t.Run("changes", func(t *testing.T) {
+		config := &model.GroupConfig{
+			Version: "5.31.0",
+			Image:   "mattermost/mattermost-enterprise-edition",
+			MattermostEnv: model.EnvVarMap{
+				"KEY0": {ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{Key: "key"}}},
+				"KEY2": {Value: "value3"},
+			},
+		}
+
+		expected := []model.GroupConfigChange{
+			{Field: "Version", Old: "5.30.0", New: "5.31.0"},
+			{Field: "MattermostEnv.KEY0", Old: "", New: `{"valueFrom":{"secretKeyRef":{"key":"key"}}}`},
+			{Field: "MattermostEnv.KEY1", Old: "value1", New: ""},
+			{Field: "MattermostEnv.KEY2", Old: "value2", New: "value3"},
+		}
+		require.Equal(t, expected, config.Diff(previous))
+	})
+}
+
+func TestGroupConfigApplyTo(t *testing.T) {
+	group := &model.Group{
+		ID:       model.NewID(),
+		Sequence: 2,
+		Version:  "5.31.0",
+		Image:    "image2",
+		MattermostEnv: model.EnvVarMap{
+			"KEY1": {Value: "value2"},
+			"KEY2": {Value: "value2"},
+		},
+	}
+	config := &model.GroupConfig{
+		GroupID:       group.ID,
+		Sequence:      1,
+		Version:       "5.30.0",
+		Image:         "image1",
+		MattermostEnv: model.EnvVarMap{"KEY1": {Value: "value1"}},
+	}
+
+	config.ApplyTo(group)
+	require.Equal(t, "5.30.0", group.Version)
+	require.Equal(t, "image1", group.Image)
+	require.Equal(t, model.EnvVarMap{"KEY1": {Value: "value1"}}, group.MattermostEnv)
+	require.Equal(t, int64(2), group.Sequence)
+
+	t.Run("the configuration is not modified through the group", func(t *testing.T) {
+		group.MattermostEnv["KEY3"] = model.EnvVar{Value: "value3"}
+		require.Len(t, config.MattermostEnv, 1)
+	})
+}
+
+func TestGroupConfigsFromReader(t *testing.T) {
+	t.Run("empty request", func(t *testing.T) {
+		groupConfigs, err := model.GroupConfigsFromReader(bytes.NewReader([]byte(``)))
+		require.NoError(t, err)
+		require.Equal(t, []*model.GroupConfig{}, groupConfigs)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		groupConfigs, err := model.GroupConfigsFromReader(bytes.NewReader([]byte(`{test`)))
+		require.Error(t, err)
+		require.Nil(t, groupConfigs)
+	})
+
+	t.Run("request", func(t *testing.T) {
+		groupConfigs, err := model.GroupConfigsFromReader(bytes.NewReader([]byte(`[{"GroupID":"group1","Sequence":1,"Version":"5.30.0"}]`)))
+		require.NoError(t, err)
+		require.Equal(t, []*model.GroupConfig{{GroupID: "group1", Sequence: 1, Version: "5.30.0"}}, groupConfigs)
+	})
+}
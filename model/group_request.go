@@ -8,7 +8,9 @@ import (
 	"encoding/json"
 	"io"
 	"net/url"
+	"strconv"
 
+	mmv1alpha1 "github.com/mattermost/mattermost-operator/apis/mattermost/v1alpha1"
 	"github.com/pkg/errors"
 )
 
@@ -21,6 +23,15 @@ type CreateGroupRequest struct {
 	MaxRolling      int64
 	APISecurityLock bool
 	MattermostEnv   EnvVarMap
+
+	CanarySize              int64
+	FailureThresholdPercent int64
+
+	Size      string
+	License   string
+	Affinity  string
+	Database  string
+	Filestore string
 }
 
 // Validate validates the values of a group create request.
@@ -31,7 +42,15 @@ func (request *CreateGroupRequest) Validate() error {
 	if request.MaxRolling < 0 {
 		return errors.New("max rolling must be 0 or greater")
 	}
-	err := request.MattermostEnv.Validate()
+	err := validateGroupRolloutStrategy(request.CanarySize, request.FailureThresholdPercent)
+	if err != nil {
+		return err
+	}
+	err = validateGroupInstallationDefaults(&request.Size, &request.Affinity, &request.Database, &request.Filestore)
+	if err != nil {
+		return err
+	}
+	err = request.MattermostEnv.Validate()
 	if err != nil {
 		return errors.Wrapf(err, "bad environment variable map in create group request")
 	}
@@ -39,6 +58,41 @@ func (request *CreateGroupRequest) Validate() error {
 	return nil
 }
 
+// validateGroupRolloutStrategy validates the values controlling how the group
+// configuration is rolled out.
+func validateGroupRolloutStrategy(canarySize, failureThresholdPercent int64) error {
+	if canarySize < 0 {
+		return errors.New("canary size must be 0 or greater")
+	}
+	if failureThresholdPercent < 0 || failureThresholdPercent > 100 {
+		return errors.New("failure threshold percent must be between 0 and 100")
+	}
+
+	return nil
+}
+
+// validateGroupInstallationDefaults validates the installation values dictated
+// by the group. Values which are nil or empty are not set by the group.
+func validateGroupInstallationDefaults(size, affinity, database, filestore *string) error {
+	if size != nil && len(*size) != 0 {
+		_, err := mmv1alpha1.GetClusterSize(*size)
+		if err != nil {
+			return errors.Wrap(err, "invalid size")
+		}
+	}
+	if affinity != nil && len(*affinity) != 0 && !IsSupportedAffinity(*affinity) {
+		return errors.Errorf("unsupported affinity %s", *affinity)
+	}
+	if database != nil && len(*database) != 0 && !IsSupportedDatabase(*database) {
+		return errors.Errorf("unsupported database %s", *database)
+	}
+	if filestore != nil && len(*filestore) != 0 && !IsSupportedFilestore(*filestore) {
+		return errors.Errorf("unsupported filestore %s", *filestore)
+	}
+
+	return nil
+}
+
 // NewCreateGroupRequestFromReader will create a CreateGroupRequest from an io.Reader with JSON data.
 func NewCreateGroupRequestFromReader(reader io.Reader) (*CreateGroupRequest, error) {
 	var createGroupRequest CreateGroupRequest
@@ -65,6 +119,15 @@ type PatchGroupRequest struct {
 	Image         *string
 	MattermostEnv EnvVarMap
 
+	CanarySize              *int64
+	FailureThresholdPercent *int64
+
+	Size      *string
+	License   *string
+	Affinity  *string
+	Database  *string
+	Filestore *string
+
 	ForceSequenceUpdate bool
 }
 
@@ -92,6 +155,34 @@ func (p *PatchGroupRequest) Apply(group *Group) bool {
 		applied = true
 		group.MaxRolling = *p.MaxRolling
 	}
+	if p.CanarySize != nil && *p.CanarySize != group.CanarySize {
+		applied = true
+		group.CanarySize = *p.CanarySize
+	}
+	if p.FailureThresholdPercent != nil && *p.FailureThresholdPercent != group.FailureThresholdPercent {
+		applied = true
+		group.FailureThresholdPercent = *p.FailureThresholdPercent
+	}
+	if p.Size != nil && *p.Size != group.Size {
+		applied = true
+		group.Size = *p.Size
+	}
+	if p.License != nil && *p.License != group.License {
+		applied = true
+		group.License = *p.License
+	}
+	if p.Affinity != nil && *p.Affinity != group.Affinity {
+		applied = true
+		group.Affinity = *p.Affinity
+	}
+	if p.Database != nil && *p.Database != group.Database {
+		applied = true
+		group.Database = *p.Database
+	}
+	if p.Filestore != nil && *p.Filestore != group.Filestore {
+		applied = true
+		group.Filestore = *p.Filestore
+	}
 	if p.MattermostEnv != nil {
 		if group.MattermostEnv.ClearOrPatch(&p.MattermostEnv) {
 			applied = true
@@ -115,6 +206,16 @@ func (p *PatchGroupRequest) Validate() error {
 	if p.MaxRolling != nil && *p.MaxRolling < 0 {
 		return errors.New("max rolling must be 0 or greater")
 	}
+	if p.CanarySize != nil && *p.CanarySize < 0 {
+		return errors.New("canary size must be 0 or greater")
+	}
+	if p.FailureThresholdPercent != nil && (*p.FailureThresholdPercent < 0 || *p.FailureThresholdPercent > 100) {
+		return errors.New("failure threshold percent must be between 0 and 100")
+	}
+	err := validateGroupInstallationDefaults(p.Size, p.Affinity, p.Database, p.Filestore)
+	if err != nil {
+		return err
+	}
 	// EnvVarMap validation is skipped as all configurations of this now imply
 	// a specific patch action should be taken.
 
@@ -150,6 +251,19 @@ func (request *GetGroupsRequest) ApplyToURL(u *url.URL) {
 	u.RawQuery = q.Encode()
 }
 
+// RollbackGroupRequest describes the parameters to roll a group back to a
+// previous configuration.
+type RollbackGroupRequest struct {
+	Sequence int64
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *RollbackGroupRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	q.Add("sequence", strconv.FormatInt(request.Sequence, 10))
+	u.RawQuery = q.Encode()
+}
+
 // LeaveGroupRequest describes the parameters to leave a group.
 type LeaveGroupRequest struct {
 	RetainConfig bool
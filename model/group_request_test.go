@@ -40,6 +40,61 @@ func TestCreateGroupRequestValid(t *testing.T) {
 				MaxRolling: -1,
 			},
 		},
+		{
+			"rollout strategy",
+			false,
+			&model.CreateGroupRequest{
+				Name:                    "group1",
+				MaxRolling:              1,
+				CanarySize:              2,
+				FailureThresholdPercent: 20,
+			},
+		},
+		{
+			"installation defaults",
+			false,
+			&model.CreateGroupRequest{
+				Name:      "group1",
+				Size:      "1000users",
+				Affinity:  model.InstallationAffinityMultiTenant,
+				Database:  model.InstallationDatabaseMultiTenantRDSPostgres,
+				Filestore: model.InstallationFilestoreBifrost,
+			},
+		},
+		{
+			"invalid size",
+			true,
+			&model.CreateGroupRequest{
+				Name: "group1",
+				Size: "unknown",
+			},
+		},
+		{
+			"invalid database",
+			true,
+			&model.CreateGroupRequest{
+				Name:     "group1",
+				Database: "unknown",
+			},
+		},
+		{
+			"negative canary size",
+			true,
+			&model.CreateGroupRequest{
+				Name:       "group1",
+				MaxRolling: 1,
+				CanarySize: -1,
+			},
+		},
+		{
+			"failure threshold above 100",
+			true,
+			&model.CreateGroupRequest{
+				Name:                    "group1",
+				MaxRolling:              1,
+				FailureThresholdPercent: 101,
+			},
+		},
 		{
 			"invalid mattermost env",
 			true,
@@ -103,6 +158,52 @@ func TestPatchGroupRequestValid(t *testing.T) {
 				MaxRolling: i64oP(-1),
 			},
 		},
+		{
+			"rollout strategy only",
+			false,
+			&model.PatchGroupRequest{
+				CanarySize:              i64oP(1),
+				FailureThresholdPercent: i64oP(100),
+			},
+		},
+		{
+			"installation defaults only",
+			false,
+			&model.PatchGroupRequest{
+				Size:      sToP("1000users"),
+				License:   sToP(""),
+				Affinity:  sToP(""),
+				Filestore: sToP(model.InstallationFilestoreMultiTenantAwsS3),
+			},
+		},
+		{
+			"invalid affinity only",
+			true,
+			&model.PatchGroupRequest{
+				Affinity: sToP("unknown"),
+			},
+		},
+		{
+			"invalid filestore only",
+			true,
+			&model.PatchGroupRequest{
+				Filestore: sToP("unknown"),
+			},
+		},
+		{
+			"invalid canary size only",
+			true,
+			&model.PatchGroupRequest{
+				CanarySize: i64oP(-1),
+			},
+		},
+		{
+			"invalid failure threshold only",
+			true,
+			&model.PatchGroupRequest{
+				FailureThresholdPercent: i64oP(-1),
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -141,6 +242,39 @@ func TestPatchGroupRequestApply(t *testing.T) {
 			&model.Group{},
 			&model.Group{},
 		},
+		{
+			"installation defaults only",
+			true,
+			&model.PatchGroupRequest{
+				Size:     sToP("1000users"),
+				Database: sToP(model.InstallationDatabaseMysqlOperator),
+			},
+			&model.Group{
+				Size:    "100users",
+				License: "license1",
+			},
+			&model.Group{
+				Size:     "1000users",
+				License:  "license1",
+				Database: model.InstallationDatabaseMysqlOperator,
+			},
+		},
+		{
+			"rollout strategy only",
+			true,
+			&model.PatchGroupRequest{
+				CanarySize:              i64oP(2),
+				FailureThresholdPercent: i64oP(20),
+			},
+			&model.Group{
+				CanarySize:              1,
+				FailureThresholdPercent: 20,
+			},
+			&model.Group{
+				CanarySize:              2,
+				FailureThresholdPercent: 20,
+			},
+		},
 		{
 			"name only",
 			true,
@@ -9,6 +9,23 @@ import (
 	"io"
 )
 
+const (
+	// GroupRolloutPhaseComplete is a group whose installations all run the
+	// current group configuration.
+	GroupRolloutPhaseComplete = "complete"
+	// GroupRolloutPhaseCanary is a group rolling out its configuration to
+	// the canary installations.
+	GroupRolloutPhaseCanary = "canary"
+	// GroupRolloutPhaseRolling is a group rolling out its configuration to
+	// the remaining installations.
+	GroupRolloutPhaseRolling = "rolling"
+	// GroupRolloutPhasePaused is a group whose rollout is paused, either by
+	// a failed health gate or by a MaxRolling of 0.
+	GroupRolloutPhasePaused = "paused"
+	// GroupRolloutPhaseAborted is a group whose rollout was aborted.
+	GroupRolloutPhaseAborted = "aborted"
+)
+
 // GroupStatus represents the status of a group.
 type GroupStatus struct {
 	InstallationsTotal          int64
@@ -16,6 +33,69 @@ type GroupStatus struct {
 	InstallationsUpdating       int64
 	InstallationsHibernating    int64
 	InstallationsAwaitingUpdate int64
+	InstallationsFailed         int64
+	RolloutPhase                string
+}
+
+// GroupRolloutProgress describes how far the current group configuration
+// has been rolled out.
+type GroupRolloutProgress struct {
+	// InstallationsAwaitingUpdate is the number of stable installations not
+	// running the current group configuration yet.
+	InstallationsAwaitingUpdate int64
+	// InstallationsRolledOut is the number of installations which started
+	// updating to the current group configuration.
+	InstallationsRolledOut int64
+	// InstallationsSucceeded is the number of rolled out installations which
+	// are stable or hibernating.
+	InstallationsSucceeded int64
+	// InstallationsFailed is the number of rolled out installations which
+	// ended in update-failed.
+	InstallationsFailed int64
+}
+
+// InstallationsFinished returns the number of rolled out installations which
+// are done updating.
+func (p GroupRolloutProgress) InstallationsFinished() int64 {
+	return p.InstallationsSucceeded + p.InstallationsFailed
+}
+
+// IsComplete returns whether no installations are awaiting or undergoing an
+// update to the current group configuration.
+func (p GroupRolloutProgress) IsComplete() bool {
+	return p.InstallationsAwaitingUpdate == 0 && p.InstallationsFinished() == p.InstallationsRolledOut
+}
+
+// CanaryTarget returns the number of canary installations for the given
+// canary size, which is capped by the number of installations to update.
+func (p GroupRolloutProgress) CanaryTarget(canarySize int64) int64 {
+	target := p.InstallationsRolledOut + p.InstallationsAwaitingUpdate
+	if canarySize < target {
+		return canarySize
+	}
+	return target
+}
+
+// IsCanaryInProgress returns whether the canary installations have not all
+// finished updating yet.
+func (p GroupRolloutProgress) IsCanaryInProgress(canarySize int64) bool {
+	return canarySize > 0 && p.InstallationsFinished() < p.CanaryTarget(canarySize)
+}
+
+// IsCanaryFailed returns whether an installation failed to update before the
+// canary phase was over.
+func (p GroupRolloutProgress) IsCanaryFailed(canarySize int64) bool {
+	return canarySize > 0 && p.InstallationsFailed > 0 && p.InstallationsFinished() <= canarySize
+}
+
+// IsFailureThresholdReached returns whether the share of finished
+// installations which failed to update reached the given percentage. A
+// threshold of 0 is never reached.
+func (p GroupRolloutProgress) IsFailureThresholdReached(thresholdPercent int64) bool {
+	if thresholdPercent <= 0 || p.InstallationsFailed == 0 {
+		return false
+	}
+	return p.InstallationsFailed*100 >= thresholdPercent*p.InstallationsFinished()
 }
 
 // GroupsStatus represents the status of a groups.
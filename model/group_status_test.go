@@ -11,6 +11,28 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func TestGroupRolloutProgressHealthGates(t *testing.T) {
+	t.Run("canary failed", func(t *testing.T) {
+		progress := GroupRolloutProgress{InstallationsAwaitingUpdate: 5, InstallationsRolledOut: 2, InstallationsSucceeded: 1, InstallationsFailed: 1}
+		require.True(t, progress.IsCanaryFailed(2))
+		require.False(t, progress.IsCanaryFailed(0))
+		require.False(t, progress.IsCanaryFailed(1))
+	})
+
+	t.Run("canary target capped by installations", func(t *testing.T) {
+		progress := GroupRolloutProgress{InstallationsRolledOut: 1, InstallationsSucceeded: 1}
+		require.Equal(t, int64(1), progress.CanaryTarget(3))
+		require.False(t, progress.IsCanaryInProgress(3))
+	})
+
+	t.Run("failure threshold", func(t *testing.T) {
+		progress := GroupRolloutProgress{InstallationsRolledOut: 5, InstallationsSucceeded: 3, InstallationsFailed: 1}
+		require.False(t, progress.IsFailureThresholdReached(0))
+		require.True(t, progress.IsFailureThresholdReached(25))
+		require.False(t, progress.IsFailureThresholdReached(26))
+	})
+}
+
 func TestGroupStatusFromReader(t *testing.T) {
 	t.Run("empty json", func(t *testing.T) {
 		groupStatus, err := GroupStatusFromReader(bytes.NewReader([]byte(
@@ -44,6 +44,64 @@ func TestGroupIsDeleted(t *testing.T) {
 	})
 }
 
+func TestGroupRolloutPhase(t *testing.T) {
+	var testCases = []struct {
+		testName      string
+		group         *Group
+		progress      GroupRolloutProgress
+		expectedPhase string
+	}{
+		{
+			"complete",
+			&Group{MaxRolling: 1, RolloutState: GroupRolloutStateActive},
+			GroupRolloutProgress{InstallationsRolledOut: 2, InstallationsSucceeded: 2},
+			GroupRolloutPhaseComplete,
+		},
+		{
+			"rolling",
+			&Group{MaxRolling: 1, RolloutState: GroupRolloutStateActive},
+			GroupRolloutProgress{InstallationsAwaitingUpdate: 1, InstallationsRolledOut: 1},
+			GroupRolloutPhaseRolling,
+		},
+		{
+			"canary",
+			&Group{MaxRolling: 1, CanarySize: 2, RolloutState: GroupRolloutStateActive},
+			GroupRolloutProgress{InstallationsAwaitingUpdate: 3, InstallationsRolledOut: 2, InstallationsSucceeded: 1},
+			GroupRolloutPhaseCanary,
+		},
+		{
+			"canary finished",
+			&Group{MaxRolling: 1, CanarySize: 2, RolloutState: GroupRolloutStateActive},
+			GroupRolloutProgress{InstallationsAwaitingUpdate: 3, InstallationsRolledOut: 2, InstallationsSucceeded: 2},
+			GroupRolloutPhaseRolling,
+		},
+		{
+			"max rolling 0",
+			&Group{MaxRolling: 0, RolloutState: GroupRolloutStateActive},
+			GroupRolloutProgress{InstallationsAwaitingUpdate: 1},
+			GroupRolloutPhasePaused,
+		},
+		{
+			"paused",
+			&Group{MaxRolling: 1, RolloutState: GroupRolloutStatePaused},
+			GroupRolloutProgress{InstallationsAwaitingUpdate: 1},
+			GroupRolloutPhasePaused,
+		},
+		{
+			"aborted",
+			&Group{MaxRolling: 1, RolloutState: GroupRolloutStateAborted},
+			GroupRolloutProgress{InstallationsAwaitingUpdate: 1},
+			GroupRolloutPhaseAborted,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.testName, func(t *testing.T) {
+			require.Equal(t, tc.expectedPhase, tc.group.RolloutPhase(tc.progress))
+		})
+	}
+}
+
 func TestGroupFromReader(t *testing.T) {
 	t.Run("empty request", func(t *testing.T) {
 		group, err := GroupFromReader(bytes.NewReader([]byte(
@@ -6,9 +6,12 @@ package model
 
 import (
 	"bytes"
+	"crypto/rand"
 	"encoding/base32"
+	"encoding/hex"
 
 	"github.com/pborman/uuid"
+	"github.com/pkg/errors"
 )
 
 var encoding = base32.NewEncoding("ybndrfg8ejkmcpqxot1uwisza345h769")
@@ -24,3 +27,14 @@ func NewID() string {
 	b.Truncate(26) // removes the '==' padding
 	return b.String()
 }
+
+// newSecret generates a hex-encoded secret from the given number of random bytes.
+func newSecret(size int) (string, error) {
+	b := make([]byte, size)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read random bytes")
+	}
+
+	return hex.EncodeToString(b), nil
+}
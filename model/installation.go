@@ -39,6 +39,7 @@ type Installation struct {
 	MattermostEnv              EnvVarMap
 	Size                       string
 	Affinity                   string
+	SchedulingPolicy           string
 	State                      string
 	CRVersion                  string
 	CreateAt                   int64
@@ -46,6 +47,7 @@ type Installation struct {
 	APISecurityLock            bool
 	LockAcquiredBy             *string
 	LockAcquiredAt             int64
+	HibernationNoticeAt        int64                       `json:"HibernationNoticeAt,omitempty"`
 	GroupOverrides             map[string]string           `json:"GroupOverrides,omitempty"`
 	SingleTenantDatabaseConfig *SingleTenantDatabaseConfig `json:"SingleTenantDatabaseConfig,omitempty"`
 
@@ -73,6 +75,71 @@ type InstallationFilter struct {
 	GroupID         string
 	State           string
 	DNS             string
+	// DNSPattern is matched against the installation DNS, where * matches any
+	// sequence of characters.
+	DNSPattern string
+	// DNSContains is matched as a substring of the installation DNS.
+	DNSContains string
+	Version     string
+	Image       string
+	Database    string
+	Filestore   string
+	Size        string
+	Affinity    string
+	// ClusterID limits the result to installations with a cluster
+	// installation on the given cluster.
+	ClusterID string
+	// CreatedAfter and CreatedBefore limit the result to installations
+	// created strictly after or before the given time in milliseconds.
+	CreatedAfter  int64
+	CreatedBefore int64
+	Annotations   *AnnotationsFilter
+	// SortBy is the field the result is sorted by. Defaults to create_at.
+	SortBy string
+	// SortOrder is either asc or desc. Defaults to asc.
+	SortOrder string
+	// After is the ID of the last installation of the previous page. When
+	// set, only installations sorted after it are returned. It must refer to
+	// an existing installation and cannot be combined with a page other
+	// than 0.
+	After string
+}
+
+const (
+	// InstallationSortByCreateAt sorts installations by creation time.
+	InstallationSortByCreateAt = "create_at"
+	// InstallationSortByDNS sorts installations by DNS.
+	InstallationSortByDNS = "dns"
+	// InstallationSortByVersion sorts installations by Mattermost version.
+	InstallationSortByVersion = "version"
+	// InstallationSortByState sorts installations by state.
+	InstallationSortByState = "state"
+	// InstallationSortByOwner sorts installations by owner ID.
+	InstallationSortByOwner = "owner"
+)
+
+// SortOrderAsc and SortOrderDesc are the supported sort orders.
+const (
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+// IsValidInstallationSortBy returns true if installations can be sorted by
+// the given field. An empty value selects the default.
+func IsValidInstallationSortBy(sortBy string) bool {
+	switch sortBy {
+	case "", InstallationSortByCreateAt, InstallationSortByDNS,
+		InstallationSortByVersion, InstallationSortByState, InstallationSortByOwner:
+		return true
+	}
+
+	return false
+}
+
+// IsValidSortOrder returns true if the given sort order is supported. An
+// empty value selects the default.
+func IsValidSortOrder(sortOrder string) bool {
+	return sortOrder == "" || sortOrder == SortOrderAsc || sortOrder == SortOrderDesc
 }
 
 // Clone returns a deep copy the installation.
@@ -169,6 +236,20 @@ func (i *Installation) MergeWithGroup(group *Group, includeOverrides bool) {
 		}
 		i.Image = group.Image
 	}
+	if len(group.Size) != 0 && i.Size != group.Size {
+		if includeOverrides {
+			i.GroupOverrides["Installation Size"] = i.Size
+			i.GroupOverrides["Group Size"] = group.Size
+		}
+		i.Size = group.Size
+	}
+	if len(group.License) != 0 && i.License != group.License {
+		if includeOverrides {
+			i.GroupOverrides["Installation License"] = i.License
+			i.GroupOverrides["Group License"] = group.License
+		}
+		i.License = group.License
+	}
 	for key, value := range group.MattermostEnv {
 		if includeOverrides {
 			if _, ok := i.MattermostEnv[key]; ok {
@@ -20,9 +20,11 @@ type InstallationBackup struct {
 	InstallationID string
 	// ClusterInstallationID is set when backup is scheduled.
 	ClusterInstallationID string
-	DataResidence         *S3DataResidence
-	State                 InstallationBackupState
-	RequestAt             int64
+	// ScheduleID is set when backup was requested by a backup schedule.
+	ScheduleID    string
+	DataResidence *S3DataResidence
+	State         InstallationBackupState
+	RequestAt     int64
 	// StartAt is a start time of job that successfully completed backup.
 	StartAt         int64
 	DeleteAt        int64
@@ -38,6 +40,12 @@ type S3DataResidence struct {
 	Bucket     string
 	PathPrefix string
 	ObjectKey  string
+	// FilestorePrefix is a directory, relative to PathPrefix, containing
+	// the copy of installation files. Empty if files were not backed up.
+	FilestorePrefix string `json:"FilestorePrefix,omitempty"`
+	// FilestoreManifestKey is a key, relative to PathPrefix, of the object
+	// listing all files copied to FilestorePrefix.
+	FilestoreManifestKey string `json:"FilestoreManifestKey,omitempty"`
 }
 
 // FullPath returns joined path of object in the file store.
@@ -45,6 +53,23 @@ func (dr S3DataResidence) FullPath() string {
 	return filepath.Join(dr.PathPrefix, dr.ObjectKey)
 }
 
+// HasFilestoreSnapshot returns true if installation files are part of the backup.
+func (dr S3DataResidence) HasFilestoreSnapshot() bool {
+	return dr.FilestorePrefix != ""
+}
+
+// FilestoreFullPath returns joined path of the directory containing
+// backed up files in the file store.
+func (dr S3DataResidence) FilestoreFullPath() string {
+	return filepath.Join(dr.PathPrefix, dr.FilestorePrefix) + "/"
+}
+
+// FilestoreManifestFullPath returns joined path of the manifest of
+// backed up files in the file store.
+func (dr S3DataResidence) FilestoreManifestFullPath() string {
+	return filepath.Join(dr.PathPrefix, dr.FilestoreManifestKey)
+}
+
 // InstallationBackupState represents the state of backup.
 type InstallationBackupState string
 
@@ -86,6 +111,7 @@ type InstallationBackupFilter struct {
 	IDs                   []string
 	InstallationID        string
 	ClusterInstallationID string
+	ScheduleID            string
 	States                []InstallationBackupState
 }
 
@@ -34,6 +34,7 @@ type GetInstallationBackupsRequest struct {
 	Paging
 	InstallationID        string
 	ClusterInstallationID string
+	ScheduleID            string
 	State                 string
 }
 
@@ -42,8 +43,80 @@ func (request *GetInstallationBackupsRequest) ApplyToURL(u *url.URL) {
 	q := u.Query()
 	q.Add("installation", request.InstallationID)
 	q.Add("cluster_installation", request.ClusterInstallationID)
+	q.Add("schedule", request.ScheduleID)
 	q.Add("state", request.State)
 	request.Paging.AddToQuery(q)
 
 	u.RawQuery = q.Encode()
 }
+
+// CreateInstallationBackupScheduleRequest specifies the parameters for a new
+// installation backup schedule.
+type CreateInstallationBackupScheduleRequest struct {
+	// Only one of InstallationID and GroupID may be set.
+	InstallationID         string
+	GroupID                string
+	IntervalSeconds        int64
+	RetentionKeepLast      int64
+	RetentionKeepDailyDays int64
+}
+
+// MinimumBackupScheduleIntervalSeconds is the shortest allowed interval
+// between two scheduled backups.
+const MinimumBackupScheduleIntervalSeconds = 60 * 60
+
+// Validate validates the values of a backup schedule create request.
+func (request *CreateInstallationBackupScheduleRequest) Validate() error {
+	if request.InstallationID == "" && request.GroupID == "" {
+		return errors.New("must specify installation or group")
+	}
+	if request.InstallationID != "" && request.GroupID != "" {
+		return errors.New("installation and group cannot be specified together")
+	}
+	if request.IntervalSeconds < MinimumBackupScheduleIntervalSeconds {
+		return errors.Errorf("interval must be at least %d seconds", MinimumBackupScheduleIntervalSeconds)
+	}
+	if request.RetentionKeepLast < 0 {
+		return errors.New("number of backups to keep cannot be negative")
+	}
+	if request.RetentionKeepDailyDays < 0 {
+		return errors.New("number of days to keep daily backups cannot be negative")
+	}
+
+	return nil
+}
+
+// NewCreateInstallationBackupScheduleRequestFromReader will create a
+// CreateInstallationBackupScheduleRequest from an io.Reader with JSON data.
+func NewCreateInstallationBackupScheduleRequestFromReader(reader io.Reader) (*CreateInstallationBackupScheduleRequest, error) {
+	var scheduleRequest CreateInstallationBackupScheduleRequest
+	err := json.NewDecoder(reader).Decode(&scheduleRequest)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode backup schedule request")
+	}
+
+	err = scheduleRequest.Validate()
+	if err != nil {
+		return nil, errors.Wrap(err, "backup schedule request failed validation")
+	}
+
+	return &scheduleRequest, nil
+}
+
+// GetInstallationBackupSchedulesRequest describes the parameters to request a
+// list of installation backup schedules.
+type GetInstallationBackupSchedulesRequest struct {
+	Paging
+	InstallationID string
+	GroupID        string
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *GetInstallationBackupSchedulesRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	q.Add("installation", request.InstallationID)
+	q.Add("group", request.GroupID)
+	request.Paging.AddToQuery(q)
+
+	u.RawQuery = q.Encode()
+}
@@ -43,6 +43,7 @@ func TestGetInstallationBackupsRequest_ApplyToURL(t *testing.T) {
 	req := &GetInstallationBackupsRequest{
 		InstallationID:        "my-installation",
 		ClusterInstallationID: "my-ci",
+		ScheduleID:            "my-schedule",
 		State:                 "failed",
 		Paging: Paging{
 			Page:           1,
@@ -58,8 +59,34 @@ func TestGetInstallationBackupsRequest_ApplyToURL(t *testing.T) {
 
 	assert.Equal(t, req.InstallationID, u.Query().Get("installation"))
 	assert.Equal(t, req.ClusterInstallationID, u.Query().Get("cluster_installation"))
+	assert.Equal(t, req.ScheduleID, u.Query().Get("schedule"))
 	assert.Equal(t, req.State, u.Query().Get("state"))
 	assert.Equal(t, "1", u.Query().Get("page"))
 	assert.Equal(t, "5", u.Query().Get("per_page"))
 	assert.Equal(t, "true", u.Query().Get("include_deleted"))
 }
+
+func TestCreateInstallationBackupScheduleRequestValidate(t *testing.T) {
+	for _, testCase := range []struct {
+		description string
+		request     *CreateInstallationBackupScheduleRequest
+		valid       bool
+	}{
+		{"installation", &CreateInstallationBackupScheduleRequest{InstallationID: "installation", IntervalSeconds: 3600, RetentionKeepLast: 3}, true},
+		{"group", &CreateInstallationBackupScheduleRequest{GroupID: "group", IntervalSeconds: 86400, RetentionKeepDailyDays: 7}, true},
+		{"no target", &CreateInstallationBackupScheduleRequest{IntervalSeconds: 3600}, false},
+		{"installation and group", &CreateInstallationBackupScheduleRequest{InstallationID: "installation", GroupID: "group", IntervalSeconds: 3600}, false},
+		{"interval too short", &CreateInstallationBackupScheduleRequest{InstallationID: "installation", IntervalSeconds: 60}, false},
+		{"negative keep last", &CreateInstallationBackupScheduleRequest{InstallationID: "installation", IntervalSeconds: 3600, RetentionKeepLast: -1}, false},
+		{"negative keep daily", &CreateInstallationBackupScheduleRequest{InstallationID: "installation", IntervalSeconds: 3600, RetentionKeepDailyDays: -1}, false},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			err := testCase.request.Validate()
+			if testCase.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
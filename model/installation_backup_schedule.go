@@ -0,0 +1,140 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// InstallationBackupSchedule periodically requests backups of an installation,
+// or of every installation in a group, and expires the backups it created
+// according to its retention rules.
+type InstallationBackupSchedule struct {
+	ID string
+	// Only one of InstallationID and GroupID is set.
+	InstallationID string
+	GroupID        string
+	// IntervalSeconds is the time between two scheduled backups.
+	IntervalSeconds int64
+	// RetentionKeepLast is the number of the most recent backups of each
+	// installation which are always kept.
+	RetentionKeepLast int64
+	// RetentionKeepDailyDays is the number of days for which the newest
+	// backup of each day is kept.
+	RetentionKeepDailyDays int64
+	LastBackupAt           int64
+	NextBackupAt           int64
+	// LastError describes the installations which were skipped by the last
+	// scheduled run, e.g. because they were not hibernated. It is empty when
+	// every installation was backed up.
+	LastError      string
+	CreateAt       int64
+	DeleteAt       int64
+	LockAcquiredBy *string
+	LockAcquiredAt int64
+}
+
+// InstallationBackupScheduleFilter describes the parameters used to constrain a set of backup schedules.
+type InstallationBackupScheduleFilter struct {
+	Paging
+	InstallationID string
+	GroupID        string
+}
+
+// IsDeleted returns whether the backup schedule was marked as deleted or not.
+func (s *InstallationBackupSchedule) IsDeleted() bool {
+	return s.DeleteAt != 0
+}
+
+// Interval returns the time between two scheduled backups.
+func (s *InstallationBackupSchedule) Interval() time.Duration {
+	return time.Duration(s.IntervalSeconds) * time.Second
+}
+
+// IsDue returns whether the next backup of the schedule should be requested
+// at the given time in milliseconds.
+func (s *InstallationBackupSchedule) IsDue(now int64) bool {
+	return s.NextBackupAt <= now
+}
+
+// HasRetention returns whether the schedule expires any backups.
+func (s *InstallationBackupSchedule) HasRetention() bool {
+	return s.RetentionKeepLast > 0 || s.RetentionKeepDailyDays > 0
+}
+
+// ExpiredBackups returns the backups which are no longer retained by the
+// schedule at the given time. Retention rules are evaluated separately for
+// each installation and only successful backups are considered; a backup is
+// retained if it is one of the RetentionKeepLast most recent backups or the
+// newest backup of a day within the last RetentionKeepDailyDays days.
+func (s *InstallationBackupSchedule) ExpiredBackups(backups []*InstallationBackup, now time.Time) []*InstallationBackup {
+	if !s.HasRetention() {
+		return nil
+	}
+
+	byInstallation := map[string][]*InstallationBackup{}
+	for _, backup := range backups {
+		if backup.State != InstallationBackupStateBackupSucceeded {
+			continue
+		}
+		byInstallation[backup.InstallationID] = append(byInstallation[backup.InstallationID], backup)
+	}
+
+	dailyCutoff := now.UTC().Truncate(24*time.Hour).AddDate(0, 0, -int(s.RetentionKeepDailyDays)+1)
+
+	var expired []*InstallationBackup
+	for _, installationBackups := range byInstallation {
+		sort.SliceStable(installationBackups, func(i, j int) bool {
+			return installationBackups[i].RequestAt > installationBackups[j].RequestAt
+		})
+
+		keptDays := map[string]bool{}
+		for i, backup := range installationBackups {
+			keep := int64(i) < s.RetentionKeepLast
+
+			requestAt := time.Unix(0, backup.RequestAt*int64(time.Millisecond)).UTC()
+			day := requestAt.Format("2006-01-02")
+			if s.RetentionKeepDailyDays > 0 && !requestAt.Before(dailyCutoff) && !keptDays[day] {
+				keptDays[day] = true
+				keep = true
+			}
+
+			if !keep {
+				expired = append(expired, backup)
+			}
+		}
+	}
+
+	return expired
+}
+
+// NewInstallationBackupScheduleFromReader will create an InstallationBackupSchedule
+// from an io.Reader with JSON data.
+func NewInstallationBackupScheduleFromReader(reader io.Reader) (*InstallationBackupSchedule, error) {
+	var schedule InstallationBackupSchedule
+	err := json.NewDecoder(reader).Decode(&schedule)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode backup schedule")
+	}
+
+	return &schedule, nil
+}
+
+// NewInstallationBackupSchedulesFromReader will create a slice of
+// InstallationBackupSchedule from an io.Reader with JSON data.
+func NewInstallationBackupSchedulesFromReader(reader io.Reader) ([]*InstallationBackupSchedule, error) {
+	schedules := []*InstallationBackupSchedule{}
+	err := json.NewDecoder(reader).Decode(&schedules)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode backup schedules")
+	}
+
+	return schedules, nil
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstallationBackupSchedule_IsDue(t *testing.T) {
+	schedule := &InstallationBackupSchedule{NextBackupAt: 100}
+
+	assert.False(t, schedule.IsDue(99))
+	assert.True(t, schedule.IsDue(100))
+	assert.True(t, schedule.IsDue(101))
+}
+
+func TestInstallationBackupSchedule_ExpiredBackups(t *testing.T) {
+	now := time.Date(2021, 6, 10, 12, 0, 0, 0, time.UTC)
+	millis := func(t time.Time) int64 {
+		return t.UnixNano() / int64(time.Millisecond)
+	}
+
+	newBackup := func(id, installationID string, requestAt time.Time) *InstallationBackup {
+		return &InstallationBackup{
+			ID:             id,
+			InstallationID: installationID,
+			State:          InstallationBackupStateBackupSucceeded,
+			RequestAt:      millis(requestAt),
+		}
+	}
+
+	backups := []*InstallationBackup{
+		newBackup("today-2", "installation1", now.Add(-1*time.Hour)),
+		newBackup("today-1", "installation1", now.Add(-2*time.Hour)),
+		newBackup("yesterday-2", "installation1", now.Add(-23*time.Hour)),
+		newBackup("yesterday-1", "installation1", now.Add(-25*time.Hour)),
+		newBackup("last-week", "installation1", now.Add(-7*24*time.Hour)),
+		newBackup("other", "installation2", now.Add(-7*24*time.Hour)),
+	}
+	failed := newBackup("failed", "installation1", now.Add(-30*24*time.Hour))
+	failed.State = InstallationBackupStateBackupFailed
+	backups = append(backups, failed)
+
+	ids := func(backups []*InstallationBackup) []string {
+		var ids []string
+		for _, backup := range backups {
+			ids = append(ids, backup.ID)
+		}
+		return ids
+	}
+
+	t.Run("no retention", func(t *testing.T) {
+		schedule := &InstallationBackupSchedule{}
+		assert.Empty(t, schedule.ExpiredBackups(backups, now))
+	})
+
+	t.Run("keep last", func(t *testing.T) {
+		schedule := &InstallationBackupSchedule{RetentionKeepLast: 2}
+		assert.ElementsMatch(t, []string{"yesterday-2", "yesterday-1", "last-week"}, ids(schedule.ExpiredBackups(backups, now)))
+	})
+
+	t.Run("keep daily", func(t *testing.T) {
+		schedule := &InstallationBackupSchedule{RetentionKeepDailyDays: 2}
+		assert.ElementsMatch(t, []string{"today-1", "yesterday-1", "last-week", "other"}, ids(schedule.ExpiredBackups(backups, now)))
+	})
+
+	t.Run("keep last and daily", func(t *testing.T) {
+		schedule := &InstallationBackupSchedule{RetentionKeepLast: 1, RetentionKeepDailyDays: 2}
+		assert.ElementsMatch(t, []string{"today-1", "yesterday-1", "last-week"}, ids(schedule.ExpiredBackups(backups, now)))
+	})
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// InstallationCloneOperation contains information about installation clone operation.
+type InstallationCloneOperation struct {
+	ID string
+	// InstallationID is an ID of the Installation being cloned.
+	InstallationID string
+	// CloneInstallationID is an ID of the Installation created as a clone.
+	CloneInstallationID                  string
+	RequestAt                            int64
+	State                                InstallationCloneOperationState
+	BackupID                             string
+	InstallationDBRestorationOperationID string
+	CompleteAt                           int64
+	DeleteAt                             int64
+	LockAcquiredBy                       *string
+	LockAcquiredAt                       int64
+}
+
+// InstallationCloneOperationState represents the state of installation clone operation.
+type InstallationCloneOperationState string
+
+const (
+	// InstallationCloneStateRequested is requested clone operation.
+	InstallationCloneStateRequested InstallationCloneOperationState = "installation-clone-requested"
+	// InstallationCloneStateBackupInProgress is clone operation waiting for backup of the source installation to complete.
+	InstallationCloneStateBackupInProgress InstallationCloneOperationState = "installation-clone-backup-in-progress"
+	// InstallationCloneStateCreationInProgress is clone operation waiting for the clone installation to be created.
+	InstallationCloneStateCreationInProgress InstallationCloneOperationState = "installation-clone-creation-in-progress"
+	// InstallationCloneStateHibernationInProgress is clone operation waiting for the clone installation to hibernate before restoration.
+	InstallationCloneStateHibernationInProgress InstallationCloneOperationState = "installation-clone-hibernation-in-progress"
+	// InstallationCloneStateRestorationInProgress is clone operation waiting for restoration of the backup to the clone installation to complete.
+	InstallationCloneStateRestorationInProgress InstallationCloneOperationState = "installation-clone-restoration-in-progress"
+	// InstallationCloneStateWakeUpInProgress is clone operation waiting for the clone installation to wake up.
+	InstallationCloneStateWakeUpInProgress InstallationCloneOperationState = "installation-clone-wake-up-in-progress"
+	// InstallationCloneStateFailing is clone operation that is failing.
+	InstallationCloneStateFailing InstallationCloneOperationState = "installation-clone-failing"
+	// InstallationCloneStateSucceeded is clone operation that finished with success.
+	InstallationCloneStateSucceeded InstallationCloneOperationState = "installation-clone-succeeded"
+	// InstallationCloneStateFailed is clone operation that failed.
+	InstallationCloneStateFailed InstallationCloneOperationState = "installation-clone-failed"
+)
+
+// AllInstallationCloneOperationsStatesPendingWork is a list of all clone operations states
+// that the supervisor will attempt to transition towards stable on the next "tick".
+var AllInstallationCloneOperationsStatesPendingWork = []InstallationCloneOperationState{
+	InstallationCloneStateRequested,
+	InstallationCloneStateBackupInProgress,
+	InstallationCloneStateCreationInProgress,
+	InstallationCloneStateHibernationInProgress,
+	InstallationCloneStateRestorationInProgress,
+	InstallationCloneStateWakeUpInProgress,
+	InstallationCloneStateFailing,
+}
+
+// InstallationCloneFilter describes the parameters used to constrain a set of installation clone operations.
+type InstallationCloneFilter struct {
+	Paging
+	IDs                 []string
+	InstallationID      string
+	CloneInstallationID string
+	States              []InstallationCloneOperationState
+}
+
+// EnsureInstallationReadyForClone ensures that installation can be cloned.
+// Clone is created from a fresh backup, therefore the same requirements apply.
+func EnsureInstallationReadyForClone(installation *Installation) error {
+	return EnsureInstallationReadyForBackup(installation)
+}
+
+// NewInstallationCloneFromSource creates new Installation with the same configuration
+// as the source installation, which can be used as a clone.
+func NewInstallationCloneFromSource(source *Installation, request *InstallationCloneRequest) *Installation {
+	return &Installation{
+		OwnerID:                    request.OwnerID,
+		Version:                    source.Version,
+		Image:                      source.Image,
+		DNS:                        request.DNS,
+		Database:                   source.Database,
+		Filestore:                  source.Filestore,
+		License:                    source.License,
+		Size:                       source.Size,
+		Affinity:                   source.Affinity,
+		SchedulingPolicy:           source.SchedulingPolicy,
+		MattermostEnv:              source.MattermostEnv,
+		SingleTenantDatabaseConfig: source.SingleTenantDatabaseConfig,
+		CRVersion:                  source.CRVersion,
+		State:                      InstallationStateCreationRequested,
+	}
+}
+
+// NewInstallationCloneOperationFromReader will create a InstallationCloneOperation from an
+// io.Reader with JSON data.
+func NewInstallationCloneOperationFromReader(reader io.Reader) (*InstallationCloneOperation, error) {
+	var cloneOperation InstallationCloneOperation
+	err := json.NewDecoder(reader).Decode(&cloneOperation)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode InstallationCloneOperation")
+	}
+
+	return &cloneOperation, nil
+}
+
+// NewInstallationCloneOperationsFromReader will create a slice of InstallationCloneOperations from an
+// io.Reader with JSON data.
+func NewInstallationCloneOperationsFromReader(reader io.Reader) ([]*InstallationCloneOperation, error) {
+	cloneOperations := []*InstallationCloneOperation{}
+	err := json.NewDecoder(reader).Decode(&cloneOperations)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode InstallationCloneOperations")
+	}
+
+	return cloneOperations, nil
+}
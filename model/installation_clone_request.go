@@ -0,0 +1,77 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// InstallationCloneRequest represents request for installation clone.
+type InstallationCloneRequest struct {
+	// DNS is the DNS of the clone installation.
+	DNS string
+	// OwnerID is the owner of the clone installation.
+	// Defaults to the owner of the source installation.
+	OwnerID string
+}
+
+// NewInstallationCloneRequestFromReader will create a InstallationCloneRequest from an
+// io.Reader with JSON data.
+func NewInstallationCloneRequestFromReader(reader io.Reader) (*InstallationCloneRequest, error) {
+	var installationCloneRequest InstallationCloneRequest
+	err := json.NewDecoder(reader).Decode(&installationCloneRequest)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode InstallationCloneRequest")
+	}
+
+	return &installationCloneRequest, nil
+}
+
+// SetDefaults sets the default values for an installation clone request
+// based on the installation being cloned.
+func (request *InstallationCloneRequest) SetDefaults(source *Installation) {
+	if request.OwnerID == "" {
+		request.OwnerID = source.OwnerID
+	}
+}
+
+// Validate validates the values of an installation clone request.
+func (request *InstallationCloneRequest) Validate() error {
+	if err := isValidDNS(request.DNS); err != nil {
+		return err
+	}
+	if hasWhiteSpace(request.DNS) != -1 {
+		return errors.Errorf("cannot have spaces in dns field. DNS=%s", request.DNS)
+	}
+	if request.OwnerID == "" {
+		return errors.New("must specify owner")
+	}
+
+	return nil
+}
+
+// GetInstallationCloneOperationsRequest describes the parameters to request
+// a list of installation clone operations.
+type GetInstallationCloneOperationsRequest struct {
+	Paging
+	InstallationID      string
+	CloneInstallationID string
+	State               string
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *GetInstallationCloneOperationsRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	q.Add("installation", request.InstallationID)
+	q.Add("clone_installation", request.CloneInstallationID)
+	q.Add("state", request.State)
+	request.Paging.AddToQuery(q)
+
+	u.RawQuery = q.Encode()
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// InstallationClusterMigrationOperation contains information about installation's cluster migration operation.
+type InstallationClusterMigrationOperation struct {
+	ID             string
+	InstallationID string
+	// SourceClusterID is the cluster the installation is migrated from.
+	SourceClusterID string
+	// TargetClusterID is the cluster the installation is migrated to.
+	TargetClusterID string
+	// SourceClusterInstallationID is the cluster installation removed after the migration.
+	SourceClusterInstallationID string
+	// TargetClusterInstallationID is the cluster installation created on the target cluster.
+	TargetClusterInstallationID string
+	RequestAt                   int64
+	State                       InstallationClusterMigrationOperationState
+	CompleteAt                  int64
+	DeleteAt                    int64
+	LockAcquiredBy              *string
+	LockAcquiredAt              int64
+}
+
+// InstallationClusterMigrationOperationState represents the state of cluster migration operation.
+type InstallationClusterMigrationOperationState string
+
+const (
+	// InstallationClusterMigrationStateRequested is requested cluster migration operation.
+	InstallationClusterMigrationStateRequested InstallationClusterMigrationOperationState = "installation-cluster-migration-requested"
+	// InstallationClusterMigrationStateTargetCreationInProgress is cluster migration operation waiting for the cluster installation on the target cluster to become stable.
+	InstallationClusterMigrationStateTargetCreationInProgress InstallationClusterMigrationOperationState = "installation-cluster-migration-target-creation-in-progress"
+	// InstallationClusterMigrationStateDNSSwitch is cluster migration operation that is switching the installation DNS to the target cluster.
+	InstallationClusterMigrationStateDNSSwitch InstallationClusterMigrationOperationState = "installation-cluster-migration-dns-switch"
+	// InstallationClusterMigrationStateSourceDeletionInProgress is cluster migration operation waiting for the cluster installation on the source cluster to be deleted.
+	InstallationClusterMigrationStateSourceDeletionInProgress InstallationClusterMigrationOperationState = "installation-cluster-migration-source-deletion-in-progress"
+	// InstallationClusterMigrationStateRollbackRequested is cluster migration operation that needs to remove the cluster installation from the target cluster.
+	InstallationClusterMigrationStateRollbackRequested InstallationClusterMigrationOperationState = "installation-cluster-migration-rollback-requested"
+	// InstallationClusterMigrationStateRollbackInProgress is cluster migration operation waiting for the cluster installation on the target cluster to be deleted.
+	InstallationClusterMigrationStateRollbackInProgress InstallationClusterMigrationOperationState = "installation-cluster-migration-rollback-in-progress"
+	// InstallationClusterMigrationStateSucceeded is cluster migration operation that finished with success.
+	InstallationClusterMigrationStateSucceeded InstallationClusterMigrationOperationState = "installation-cluster-migration-succeeded"
+	// InstallationClusterMigrationStateFailed is cluster migration operation that failed and was rolled back.
+	InstallationClusterMigrationStateFailed InstallationClusterMigrationOperationState = "installation-cluster-migration-failed"
+)
+
+// AllInstallationClusterMigrationOperationsStatesPendingWork is a list of all cluster migration operations states
+// that the supervisor will attempt to transition towards stable on the next "tick".
+var AllInstallationClusterMigrationOperationsStatesPendingWork = []InstallationClusterMigrationOperationState{
+	InstallationClusterMigrationStateRequested,
+	InstallationClusterMigrationStateTargetCreationInProgress,
+	InstallationClusterMigrationStateDNSSwitch,
+	InstallationClusterMigrationStateSourceDeletionInProgress,
+	InstallationClusterMigrationStateRollbackRequested,
+	InstallationClusterMigrationStateRollbackInProgress,
+}
+
+// InstallationClusterMigrationFilter describes the parameters used to constrain a set of installation cluster migration operations.
+type InstallationClusterMigrationFilter struct {
+	Paging
+	IDs             []string
+	InstallationID  string
+	SourceClusterID string
+	TargetClusterID string
+	States          []InstallationClusterMigrationOperationState
+}
+
+// IsInProgress returns true if the cluster migration operation has not reached a final state yet.
+func (o *InstallationClusterMigrationOperation) IsInProgress() bool {
+	for _, state := range AllInstallationClusterMigrationOperationsStatesPendingWork {
+		if o.State == state {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EnsureInstallationReadyForClusterMigration ensures that installation can be migrated to a different cluster.
+func EnsureInstallationReadyForClusterMigration(installation *Installation) error {
+	if installation.State != InstallationStateStable {
+		return errors.Errorf("invalid installation state, only stable installations can be migrated to another cluster, state is %q", installation.State)
+	}
+
+	return EnsureInstallationDataOutsideCluster(installation)
+}
+
+// EnsureInstallationDataOutsideCluster ensures that the database and the
+// filestore of the installation are not running on its cluster, as their data
+// would be lost when the installation is moved to another cluster.
+func EnsureInstallationDataOutsideCluster(installation *Installation) error {
+	if installation.InternalDatabase() || installation.InternalFilestore() {
+		return errors.New("installations with operator database or filestore cannot be migrated to another cluster, their data is stored on the cluster")
+	}
+
+	return nil
+}
+
+// EnsureClusterReadyForInstallationMigration ensures that the installation can be
+// migrated to the given cluster.
+func EnsureClusterReadyForInstallationMigration(cluster *Cluster, installation *Installation) error {
+	if cluster.State != ClusterStateStable {
+		return errors.Errorf("target cluster must be stable, state is %q", cluster.State)
+	}
+	if !cluster.AllowInstallations {
+		return errors.New("target cluster does not allow installations")
+	}
+	if cluster.Provisioner == ProvisionerLocal {
+		return errors.New("local clusters support only installations with operator database and filestore, which cannot be migrated")
+	}
+
+	return EnsureInstallationDataOutsideCluster(installation)
+}
+
+// NewInstallationClusterMigrationOperationFromReader will create a InstallationClusterMigrationOperation from an
+// io.Reader with JSON data.
+func NewInstallationClusterMigrationOperationFromReader(reader io.Reader) (*InstallationClusterMigrationOperation, error) {
+	var migrationOperation InstallationClusterMigrationOperation
+	err := json.NewDecoder(reader).Decode(&migrationOperation)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode InstallationClusterMigrationOperation")
+	}
+
+	return &migrationOperation, nil
+}
+
+// NewInstallationClusterMigrationOperationsFromReader will create a slice of InstallationClusterMigrationOperations from an
+// io.Reader with JSON data.
+func NewInstallationClusterMigrationOperationsFromReader(reader io.Reader) ([]*InstallationClusterMigrationOperation, error) {
+	migrationOperations := []*InstallationClusterMigrationOperation{}
+	err := json.NewDecoder(reader).Decode(&migrationOperations)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode InstallationClusterMigrationOperations")
+	}
+
+	return migrationOperations, nil
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// InstallationClusterMigrationRequest represents request for installation migration to another cluster.
+type InstallationClusterMigrationRequest struct {
+	InstallationID  string
+	TargetClusterID string
+}
+
+// NewInstallationClusterMigrationRequestFromReader will create a InstallationClusterMigrationRequest from an
+// io.Reader with JSON data.
+func NewInstallationClusterMigrationRequestFromReader(reader io.Reader) (*InstallationClusterMigrationRequest, error) {
+	var installationClusterMigrationRequest InstallationClusterMigrationRequest
+	err := json.NewDecoder(reader).Decode(&installationClusterMigrationRequest)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode InstallationClusterMigrationRequest")
+	}
+
+	return &installationClusterMigrationRequest, nil
+}
+
+// Validate validates the values of an installation cluster migration request.
+func (request *InstallationClusterMigrationRequest) Validate() error {
+	if request.InstallationID == "" {
+		return errors.New("installation ID must not be empty")
+	}
+	if request.TargetClusterID == "" {
+		return errors.New("target cluster ID must not be empty")
+	}
+
+	return nil
+}
+
+// GetInstallationClusterMigrationOperationsRequest describes the parameters to request
+// a list of installation cluster migration operations.
+type GetInstallationClusterMigrationOperationsRequest struct {
+	Paging
+	InstallationID  string
+	SourceClusterID string
+	TargetClusterID string
+	State           string
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *GetInstallationClusterMigrationOperationsRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	q.Add("installation", request.InstallationID)
+	q.Add("source_cluster", request.SourceClusterID)
+	q.Add("target_cluster", request.TargetClusterID)
+	q.Add("state", request.State)
+	request.Paging.AddToQuery(q)
+
+	u.RawQuery = q.Encode()
+}
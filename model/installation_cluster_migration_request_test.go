@@ -0,0 +1,74 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInstallationClusterMigrationRequestFromReader(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		request, err := NewInstallationClusterMigrationRequestFromReader(bytes.NewReader([]byte(
+			"",
+		)))
+		require.NoError(t, err)
+		require.Equal(t, &InstallationClusterMigrationRequest{}, request)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		request, err := NewInstallationClusterMigrationRequestFromReader(bytes.NewReader([]byte(
+			"{test",
+		)))
+		require.Error(t, err)
+		require.Nil(t, request)
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		request, err := NewInstallationClusterMigrationRequestFromReader(bytes.NewReader([]byte(
+			`{"InstallationID": "installation", "TargetClusterID": "cluster"}`,
+		)))
+		require.NoError(t, err)
+		require.Equal(t, &InstallationClusterMigrationRequest{
+			InstallationID:  "installation",
+			TargetClusterID: "cluster",
+		}, request)
+	})
+}
+
+func TestInstallationClusterMigrationRequestValidate(t *testing.T) {
+	for _, testCase := range []struct {
+		description string
+		request     *InstallationClusterMigrationRequest
+		valid       bool
+	}{
+		{
+			description: "valid",
+			request:     &InstallationClusterMigrationRequest{InstallationID: "installation", TargetClusterID: "cluster"},
+			valid:       true,
+		},
+		{
+			description: "missing installation ID",
+			request:     &InstallationClusterMigrationRequest{TargetClusterID: "cluster"},
+			valid:       false,
+		},
+		{
+			description: "missing target cluster ID",
+			request:     &InstallationClusterMigrationRequest{InstallationID: "installation"},
+			valid:       false,
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			err := testCase.request.Validate()
+			if testCase.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
@@ -32,8 +32,16 @@ const (
 	DatabaseEngineTypeMySQL = "mysql"
 	// DatabaseEngineTypePostgres is a PostgreSQL database.
 	DatabaseEngineTypePostgres = "postgres"
+
+	// SingleTenantDatabaseBackupRetentionDays is the number of days for which
+	// single tenant databases can be restored to a point in time.
+	SingleTenantDatabaseBackupRetentionDays = 7
 )
 
+// ErrPointInTimeRestoreNotPossible is returned by RestoreToPointInTime when
+// the restoration can never succeed, so that it is not retried.
+var ErrPointInTimeRestoreNotPossible = errors.New("point-in-time restoration is not possible")
+
 // Database is the interface for managing Mattermost databases.
 type Database interface {
 	Provision(store InstallationDatabaseStoreInterface, logger log.FieldLogger) error
@@ -47,6 +55,17 @@ type Database interface {
 	RollbackMigration(store InstallationDatabaseStoreInterface, dbMigration *InstallationDBMigrationOperation, logger log.FieldLogger) error
 }
 
+// DatabasePointInTimeRestorer is implemented by databases which can be
+// restored to a point in time natively.
+type DatabasePointInTimeRestorer interface {
+	// RestoreToPointInTime replaces the database with the database of the
+	// source installation as it was at the given time in milliseconds. It is
+	// called repeatedly until the restored database is ready, which is
+	// reported by returning true. Errors caused by
+	// ErrPointInTimeRestoreNotPossible are not retried.
+	RestoreToPointInTime(store InstallationDatabaseStoreInterface, sourceInstallationID string, restoreTime int64, logger log.FieldLogger) (bool, error)
+}
+
 // InstallationDatabaseStoreInterface is the interface necessary for SQLStore
 // functionality to correlate an installation to a cluster for database creation.
 // TODO(gsagula): Consider renaming this interface to InstallationDatabaseInterface. For reference,
@@ -63,6 +63,8 @@ const (
 	InstallationDBMigrationStateSucceeded InstallationDBMigrationOperationState = "installation-db-migration-succeeded"
 	// InstallationDBMigrationStateFailed is DB migration operation that failed.
 	InstallationDBMigrationStateFailed InstallationDBMigrationOperationState = "installation-db-migration-failed"
+	// InstallationDBMigrationStateCanceled is DB migration operation that was canceled before any work was done.
+	InstallationDBMigrationStateCanceled InstallationDBMigrationOperationState = "installation-db-migration-canceled"
 )
 
 // AllInstallationDBMigrationOperationsStatesPendingWork is a list of all db migration operations states
@@ -87,6 +89,23 @@ type InstallationDBMigrationFilter struct {
 	States         []InstallationDBMigrationOperationState
 }
 
+// EnsureInstallationReadyForDBMigration ensures that installation can be migrated to a different database.
+func EnsureInstallationReadyForDBMigration(installation *Installation) error {
+	if installation.State != InstallationStateHibernating {
+		return errors.Errorf("invalid installation state, only hibernated installations can be migrated, state is %q", installation.State)
+	}
+	if installation.Database != InstallationDatabaseMultiTenantRDSPostgres {
+		return errors.Errorf("db migration is supported only from %q database, the database type is %q", InstallationDatabaseMultiTenantRDSPostgres, installation.Database)
+	}
+
+	return EnsureBackupRestoreCompatible(installation)
+}
+
+// IsCancelable returns true if the db migration operation can still be canceled.
+func (o *InstallationDBMigrationOperation) IsCancelable() bool {
+	return o.State == InstallationDBMigrationStateRequested
+}
+
 // NewDBMigrationOperationFromReader will create a InstallationDBMigrationOperation from an
 // io.Reader with JSON data.
 func NewDBMigrationOperationFromReader(reader io.Reader) (*InstallationDBMigrationOperation, error) {
@@ -83,3 +83,54 @@ func TestNewDBMigrationOperationsFromReader(t *testing.T) {
 		}, dBMigrationOperations)
 	})
 }
+
+func TestEnsureInstallationReadyForDBMigration(t *testing.T) {
+	for _, testCase := range []struct {
+		description  string
+		installation *Installation
+		valid        bool
+	}{
+		{
+			description: "valid",
+			installation: &Installation{
+				State:     InstallationStateHibernating,
+				Database:  InstallationDatabaseMultiTenantRDSPostgres,
+				Filestore: InstallationFilestoreBifrost,
+			},
+			valid: true,
+		},
+		{
+			description: "not hibernating",
+			installation: &Installation{
+				State:     InstallationStateStable,
+				Database:  InstallationDatabaseMultiTenantRDSPostgres,
+				Filestore: InstallationFilestoreBifrost,
+			},
+		},
+		{
+			description: "unsupported source database",
+			installation: &Installation{
+				State:     InstallationStateHibernating,
+				Database:  InstallationDatabaseSingleTenantRDSPostgres,
+				Filestore: InstallationFilestoreBifrost,
+			},
+		},
+		{
+			description: "unsupported filestore",
+			installation: &Installation{
+				State:     InstallationStateHibernating,
+				Database:  InstallationDatabaseMultiTenantRDSPostgres,
+				Filestore: InstallationFilestoreMinioOperator,
+			},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			err := EnsureInstallationReadyForDBMigration(testCase.installation)
+			if testCase.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
@@ -33,6 +33,21 @@ func NewInstallationDBMigrationRequestFromReader(reader io.Reader) (*Installatio
 	return &installationDBMigrationRequest, nil
 }
 
+// Validate validates the values of an installation db migration request.
+func (request *InstallationDBMigrationRequest) Validate() error {
+	if request.InstallationID == "" {
+		return errors.New("installation ID must not be empty")
+	}
+	if request.DestinationDatabase != InstallationDatabaseMultiTenantRDSPostgres {
+		return errors.Errorf("db migration is supported only to %q database, requested %q", InstallationDatabaseMultiTenantRDSPostgres, request.DestinationDatabase)
+	}
+	if request.DestinationMultiTenant == nil || request.DestinationMultiTenant.DatabaseID == "" {
+		return errors.New("destination multi-tenant database ID must be specified")
+	}
+
+	return nil
+}
+
 // GetInstallationDBMigrationOperationsRequest describes the parameters to request
 // a list of installation db migration operations.
 type GetInstallationDBMigrationOperationsRequest struct {
@@ -40,3 +40,52 @@ func TestNewInstallationDBMigrationRequestFromReader(t *testing.T) {
 		}, installationDBMigrationRequest)
 	})
 }
+
+func TestInstallationDBMigrationRequestValidate(t *testing.T) {
+	for _, testCase := range []struct {
+		description string
+		request     *InstallationDBMigrationRequest
+		valid       bool
+	}{
+		{
+			description: "valid",
+			request: &InstallationDBMigrationRequest{
+				InstallationID:         "installation",
+				DestinationDatabase:    InstallationDatabaseMultiTenantRDSPostgres,
+				DestinationMultiTenant: &MultiTenantDBMigrationData{DatabaseID: "db"},
+			},
+			valid: true,
+		},
+		{
+			description: "missing installation ID",
+			request: &InstallationDBMigrationRequest{
+				DestinationDatabase:    InstallationDatabaseMultiTenantRDSPostgres,
+				DestinationMultiTenant: &MultiTenantDBMigrationData{DatabaseID: "db"},
+			},
+		},
+		{
+			description: "unsupported destination database",
+			request: &InstallationDBMigrationRequest{
+				InstallationID:         "installation",
+				DestinationDatabase:    InstallationDatabaseMysqlOperator,
+				DestinationMultiTenant: &MultiTenantDBMigrationData{DatabaseID: "db"},
+			},
+		},
+		{
+			description: "missing destination multi-tenant database",
+			request: &InstallationDBMigrationRequest{
+				InstallationID:      "installation",
+				DestinationDatabase: InstallationDatabaseMultiTenantRDSPostgres,
+			},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			err := testCase.request.Validate()
+			if testCase.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
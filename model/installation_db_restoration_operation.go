@@ -7,6 +7,7 @@ package model
 import (
 	"encoding/json"
 	"io"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -15,9 +16,19 @@ import (
 type InstallationDBRestorationOperation struct {
 	ID             string
 	InstallationID string
-	BackupID       string
-	RequestAt      int64
-	State          InstallationDBRestorationState
+	// Mode determines what is restored. Backup restorations restore BackupID,
+	// point-in-time restorations restore the database of SourceInstallationID
+	// as it was at RestoreTime.
+	Mode InstallationDBRestorationMode
+	// SourceInstallationID is the installation from which the data is
+	// restored. It differs from InstallationID for cross-installation restorations.
+	SourceInstallationID string
+	BackupID             string
+	// RestoreTime is the time in milliseconds to which point-in-time
+	// restorations restore the database.
+	RestoreTime int64
+	RequestAt   int64
+	State       InstallationDBRestorationState
 	// TargetInstallationState is an installation State to which installation
 	// will be transitioned when the restoration finishes successfully.
 	TargetInstallationState string
@@ -28,6 +39,22 @@ type InstallationDBRestorationOperation struct {
 	LockAcquiredAt          int64
 }
 
+// InstallationDBRestorationMode represents the kind of db restoration operation.
+type InstallationDBRestorationMode string
+
+const (
+	// InstallationDBRestorationModeBackup is a restoration of an installation backup.
+	InstallationDBRestorationModeBackup InstallationDBRestorationMode = "backup"
+	// InstallationDBRestorationModePointInTime is a restoration of an installation database
+	// to a point in time using the native restore of the database provider.
+	InstallationDBRestorationModePointInTime InstallationDBRestorationMode = "point-in-time"
+)
+
+// IsPointInTime returns whether the restoration is a point-in-time restoration.
+func (o *InstallationDBRestorationOperation) IsPointInTime() bool {
+	return o.Mode == InstallationDBRestorationModePointInTime
+}
+
 // InstallationDBRestorationState represents the state of db restoration operation.
 type InstallationDBRestorationState string
 
@@ -67,10 +94,9 @@ type InstallationDBRestorationFilter struct {
 }
 
 // EnsureInstallationReadyForDBRestoration ensures that installation can be restored.
+// The backup may belong to a different installation, in which case its data
+// is restored into the given installation.
 func EnsureInstallationReadyForDBRestoration(installation *Installation, backup *InstallationBackup) error {
-	if installation.ID != backup.InstallationID {
-		return errors.New("Backup belongs to different installation")
-	}
 	if backup.State != InstallationBackupStateBackupSucceeded {
 		return errors.Errorf("Only backups in succeeded state can be restored, the state is %q", backup.State)
 	}
@@ -85,6 +111,43 @@ func EnsureInstallationReadyForDBRestoration(installation *Installation, backup
 	return EnsureBackupRestoreCompatible(installation)
 }
 
+// EnsureInstallationReadyForDBPointInTimeRestoration ensures that the installation
+// database can be restored to the database of the source installation as it
+// was at the given time in milliseconds.
+func EnsureInstallationReadyForDBPointInTimeRestoration(installation, source *Installation, restoreTime, now int64) error {
+	if !SupportsPointInTimeRestoration(installation.Database) {
+		return errors.Errorf("point-in-time restoration is not supported for database type %q", installation.Database)
+	}
+	if source.Database != installation.Database {
+		return errors.Errorf("source installation database type %q does not match installation database type %q", source.Database, installation.Database)
+	}
+	if source.State == InstallationStateDeleted {
+		return errors.New("source installation is deleted")
+	}
+	if restoreTime <= 0 || restoreTime > now {
+		return errors.New("restore time must be in the past")
+	}
+	if restoreTime < source.CreateAt {
+		return errors.New("restore time must be after the source installation was created")
+	}
+	if restoreTime < now-int64(SingleTenantDatabaseBackupRetentionDays*24*time.Hour/time.Millisecond) {
+		return errors.Errorf("restore time must be within the backup retention period of %d days", SingleTenantDatabaseBackupRetentionDays)
+	}
+
+	if installation.State != InstallationStateHibernating && installation.State != InstallationStateDBMigrationInProgress {
+		return errors.Errorf("invalid installation state, only hibernated installations can be restored, state is %q", installation.State)
+	}
+
+	return nil
+}
+
+// SupportsPointInTimeRestoration returns whether databases of the given type
+// can be restored to a point in time.
+func SupportsPointInTimeRestoration(database string) bool {
+	return database == InstallationDatabaseSingleTenantRDSPostgres ||
+		database == InstallationDatabaseSingleTenantRDSMySQL
+}
+
 // DetermineAfterRestorationState returns installation state that should be set after successful restoration.
 func DetermineAfterRestorationState(installation *Installation) (string, error) {
 	switch installation.State {
@@ -7,6 +7,7 @@ package model
 import (
 	"bytes"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -121,18 +122,17 @@ func TestEnsureInstallationReadyForDBRestoration(t *testing.T) {
 			errorContains: "Only backups in succeeded state can be restored",
 		},
 		{
-			description: "backup not matching installation",
+			description: "backup of different installation",
 			installation: &Installation{
 				ID:        "abcd",
 				State:     InstallationStateHibernating,
-				Database:  InstallationDatabaseMultiTenantRDSPostgres,
+				Database:  InstallationDatabaseSingleTenantRDSPostgres,
 				Filestore: InstallationFilestoreBifrost,
 			},
 			backup: &InstallationBackup{
 				InstallationID: "efgh",
 				State:          InstallationBackupStateBackupSucceeded,
 			},
-			errorContains: "Backup belongs to different installation",
 		},
 		{
 			description: "backup deleted",
@@ -190,6 +190,96 @@ func TestEnsureInstallationReadyForDBRestoration(t *testing.T) {
 	}
 }
 
+func TestEnsureInstallationReadyForDBPointInTimeRestoration(t *testing.T) {
+	now := int64(10000)
+
+	for _, testCase := range []struct {
+		description   string
+		installation  *Installation
+		source        *Installation
+		restoreTime   int64
+		errorContains string
+	}{
+		{
+			description:  "valid",
+			installation: &Installation{ID: "abcd", State: InstallationStateHibernating, Database: InstallationDatabaseSingleTenantRDSPostgres},
+			source:       &Installation{ID: "efgh", State: InstallationStateStable, Database: InstallationDatabaseSingleTenantRDSPostgres, CreateAt: 1000},
+			restoreTime:  5000,
+		},
+		{
+			description:  "valid same installation",
+			installation: &Installation{ID: "abcd", State: InstallationStateHibernating, Database: InstallationDatabaseSingleTenantRDSMySQL},
+			source:       &Installation{ID: "abcd", State: InstallationStateHibernating, Database: InstallationDatabaseSingleTenantRDSMySQL},
+			restoreTime:  5000,
+		},
+		{
+			description:   "unsupported database",
+			installation:  &Installation{ID: "abcd", State: InstallationStateHibernating, Database: InstallationDatabaseMultiTenantRDSPostgres},
+			source:        &Installation{ID: "abcd", State: InstallationStateHibernating, Database: InstallationDatabaseMultiTenantRDSPostgres},
+			restoreTime:   5000,
+			errorContains: "point-in-time restoration is not supported",
+		},
+		{
+			description:   "database type mismatch",
+			installation:  &Installation{ID: "abcd", State: InstallationStateHibernating, Database: InstallationDatabaseSingleTenantRDSPostgres},
+			source:        &Installation{ID: "efgh", State: InstallationStateStable, Database: InstallationDatabaseSingleTenantRDSMySQL},
+			restoreTime:   5000,
+			errorContains: "does not match",
+		},
+		{
+			description:   "source deleted",
+			installation:  &Installation{ID: "abcd", State: InstallationStateHibernating, Database: InstallationDatabaseSingleTenantRDSPostgres},
+			source:        &Installation{ID: "efgh", State: InstallationStateDeleted, Database: InstallationDatabaseSingleTenantRDSPostgres},
+			restoreTime:   5000,
+			errorContains: "source installation is deleted",
+		},
+		{
+			description:   "restore time in future",
+			installation:  &Installation{ID: "abcd", State: InstallationStateHibernating, Database: InstallationDatabaseSingleTenantRDSPostgres},
+			source:        &Installation{ID: "efgh", State: InstallationStateStable, Database: InstallationDatabaseSingleTenantRDSPostgres},
+			restoreTime:   20000,
+			errorContains: "restore time must be in the past",
+		},
+		{
+			description:   "restore time before source creation",
+			installation:  &Installation{ID: "abcd", State: InstallationStateHibernating, Database: InstallationDatabaseSingleTenantRDSPostgres},
+			source:        &Installation{ID: "efgh", State: InstallationStateStable, Database: InstallationDatabaseSingleTenantRDSPostgres, CreateAt: 6000},
+			restoreTime:   5000,
+			errorContains: "after the source installation was created",
+		},
+		{
+			description:   "installation not hibernated",
+			installation:  &Installation{ID: "abcd", State: InstallationStateStable, Database: InstallationDatabaseSingleTenantRDSPostgres},
+			source:        &Installation{ID: "abcd", State: InstallationStateStable, Database: InstallationDatabaseSingleTenantRDSPostgres},
+			restoreTime:   5000,
+			errorContains: "invalid installation state",
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			err := EnsureInstallationReadyForDBPointInTimeRestoration(testCase.installation, testCase.source, testCase.restoreTime, now)
+			if testCase.errorContains == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), testCase.errorContains)
+			}
+		})
+	}
+}
+
+func TestEnsureInstallationReadyForDBPointInTimeRestorationRetention(t *testing.T) {
+	installation := &Installation{ID: "abcd", State: InstallationStateHibernating, Database: InstallationDatabaseSingleTenantRDSPostgres}
+	day := int64(24 * time.Hour / time.Millisecond)
+	now := 30 * day
+
+	err := EnsureInstallationReadyForDBPointInTimeRestoration(installation, installation, now-6*day, now)
+	assert.NoError(t, err)
+
+	err = EnsureInstallationReadyForDBPointInTimeRestoration(installation, installation, now-8*day, now)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "backup retention period")
+}
+
 func TestDetermineAfterRestorationState(t *testing.T) {
 
 	for _, testCase := range []struct {
@@ -15,7 +15,55 @@ import (
 // InstallationDBRestorationRequest represents request for installation restoration.
 type InstallationDBRestorationRequest struct {
 	InstallationID string
-	BackupID       string
+	// Mode defaults to InstallationDBRestorationModeBackup.
+	Mode InstallationDBRestorationMode
+	// BackupID is the backup to restore in backup mode. The backup may
+	// belong to a different installation.
+	BackupID string
+	// SourceInstallationID is the installation whose database is restored
+	// in point-in-time mode. It defaults to InstallationID.
+	SourceInstallationID string
+	// RestoreTime is the time in milliseconds to which the database is
+	// restored in point-in-time mode.
+	RestoreTime int64
+}
+
+// SetDefaults sets the default values for a restoration request.
+func (request *InstallationDBRestorationRequest) SetDefaults() {
+	if request.Mode == "" {
+		request.Mode = InstallationDBRestorationModeBackup
+	}
+	if request.Mode == InstallationDBRestorationModePointInTime && request.SourceInstallationID == "" {
+		request.SourceInstallationID = request.InstallationID
+	}
+}
+
+// Validate validates the values of a restoration request.
+func (request *InstallationDBRestorationRequest) Validate() error {
+	if request.InstallationID == "" {
+		return errors.New("installation ID must be specified")
+	}
+
+	switch request.Mode {
+	case InstallationDBRestorationModeBackup:
+		if request.BackupID == "" {
+			return errors.New("backup ID must be specified for backup restoration")
+		}
+		if request.SourceInstallationID != "" || request.RestoreTime != 0 {
+			return errors.New("source installation and restore time can only be specified for point-in-time restoration")
+		}
+	case InstallationDBRestorationModePointInTime:
+		if request.BackupID != "" {
+			return errors.New("backup ID cannot be specified for point-in-time restoration")
+		}
+		if request.RestoreTime <= 0 {
+			return errors.New("restore time must be specified for point-in-time restoration")
+		}
+	default:
+		return errors.Errorf("unsupported restoration mode %q", request.Mode)
+	}
+
+	return nil
 }
 
 // NewInstallationDBRestorationRequestFromReader will create a InstallationDBRestorationRequest from an
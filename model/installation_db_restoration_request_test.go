@@ -39,6 +39,65 @@ func TestNewInstallationDBRestorationRequestFromReader(t *testing.T) {
 	})
 }
 
+func TestInstallationDBRestorationRequest_Validate(t *testing.T) {
+	for _, testCase := range []struct {
+		description string
+		request     *InstallationDBRestorationRequest
+		valid       bool
+	}{
+		{
+			description: "backup",
+			request:     &InstallationDBRestorationRequest{InstallationID: "installation", BackupID: "backup"},
+			valid:       true,
+		},
+		{
+			description: "backup without backup ID",
+			request:     &InstallationDBRestorationRequest{InstallationID: "installation"},
+		},
+		{
+			description: "backup with restore time",
+			request:     &InstallationDBRestorationRequest{InstallationID: "installation", BackupID: "backup", RestoreTime: 100},
+		},
+		{
+			description: "point in time",
+			request:     &InstallationDBRestorationRequest{InstallationID: "installation", Mode: InstallationDBRestorationModePointInTime, RestoreTime: 100},
+			valid:       true,
+		},
+		{
+			description: "point in time without restore time",
+			request:     &InstallationDBRestorationRequest{InstallationID: "installation", Mode: InstallationDBRestorationModePointInTime},
+		},
+		{
+			description: "point in time with backup ID",
+			request:     &InstallationDBRestorationRequest{InstallationID: "installation", Mode: InstallationDBRestorationModePointInTime, BackupID: "backup", RestoreTime: 100},
+		},
+		{
+			description: "unknown mode",
+			request:     &InstallationDBRestorationRequest{InstallationID: "installation", Mode: "unknown", BackupID: "backup"},
+		},
+		{
+			description: "no installation",
+			request:     &InstallationDBRestorationRequest{BackupID: "backup"},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			testCase.request.SetDefaults()
+			err := testCase.request.Validate()
+			if testCase.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+
+	t.Run("point in time defaults to same installation", func(t *testing.T) {
+		request := &InstallationDBRestorationRequest{InstallationID: "installation", Mode: InstallationDBRestorationModePointInTime, RestoreTime: 100}
+		request.SetDefaults()
+		assert.Equal(t, "installation", request.SourceInstallationID)
+	})
+}
+
 func TestGetInstallationDBRestorationOperationsRequest_ApplyToURL(t *testing.T) {
 	req := &GetInstallationDBRestorationOperationsRequest{
 		InstallationID:        "my-installation",
@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -26,19 +27,20 @@ func SetRequireAnnotatedInstallations(val bool) {
 
 // CreateInstallationRequest specifies the parameters for a new installation.
 type CreateInstallationRequest struct {
-	OwnerID         string
-	GroupID         string
-	Version         string
-	Image           string
-	DNS             string
-	License         string
-	Size            string
-	Affinity        string
-	Database        string
-	Filestore       string
-	APISecurityLock bool
-	MattermostEnv   EnvVarMap
-	Annotations     []string
+	OwnerID          string
+	GroupID          string
+	Version          string
+	Image            string
+	DNS              string
+	License          string
+	Size             string
+	Affinity         string
+	SchedulingPolicy string
+	Database         string
+	Filestore        string
+	APISecurityLock  bool
+	MattermostEnv    EnvVarMap
+	Annotations      []string
 	// SingleTenantDatabaseConfig is ignored if Database is not single tenant mysql or postgres.
 	SingleTenantDatabaseConfig SingleTenantDatabaseRequest
 }
@@ -71,6 +73,29 @@ func (request *CreateInstallationRequest) SetDefaults() {
 	}
 }
 
+// ApplyGroupDefaults sets the values dictated by the given group on the
+// request, overriding the requested ones.
+func (request *CreateInstallationRequest) ApplyGroupDefaults(group *Group) {
+	if len(group.Size) != 0 {
+		request.Size = group.Size
+	}
+	if len(group.License) != 0 {
+		request.License = group.License
+	}
+	if len(group.Affinity) != 0 {
+		request.Affinity = group.Affinity
+	}
+	if len(group.Database) != 0 {
+		request.Database = group.Database
+	}
+	if len(group.Filestore) != 0 {
+		request.Filestore = group.Filestore
+	}
+	if IsSingleTenantRDS(request.Database) {
+		request.SingleTenantDatabaseConfig.SetDefaults()
+	}
+}
+
 // Validate validates the values of an installation create request.
 func (request *CreateInstallationRequest) Validate() error {
 	if request.OwnerID == "" {
@@ -90,6 +115,9 @@ func (request *CreateInstallationRequest) Validate() error {
 	if !IsSupportedAffinity(request.Affinity) {
 		return errors.Errorf("unsupported affinity %s", request.Affinity)
 	}
+	if request.SchedulingPolicy != "" && !IsSupportedSchedulingPolicy(request.SchedulingPolicy) {
+		return errors.Errorf("unsupported scheduling policy %s", request.SchedulingPolicy)
+	}
 	if !IsSupportedDatabase(request.Database) {
 		return errors.Errorf("unsupported database %s", request.Database)
 	}
@@ -195,10 +223,29 @@ func (request *GetInstallationRequest) ApplyToURL(u *url.URL) {
 // GetInstallationsRequest describes the parameters to request a list of installations.
 type GetInstallationsRequest struct {
 	Paging
-	OwnerID                     string
-	GroupID                     string
-	State                       string
-	DNS                         string
+	OwnerID       string
+	GroupID       string
+	State         string
+	DNS           string
+	DNSContains   string
+	Version       string
+	Image         string
+	Database      string
+	Filestore     string
+	Size          string
+	Affinity      string
+	ClusterID     string
+	CreatedAfter  int64
+	CreatedBefore int64
+	// Annotations are the names of annotations all of which need to be set
+	// on returned installations.
+	Annotations []string
+	// AnyAnnotations are the names of annotations at least one of which needs
+	// to be set on returned installations.
+	AnyAnnotations              []string
+	SortBy                      string
+	SortOrder                   string
+	After                       string
 	IncludeGroupConfig          bool
 	IncludeGroupConfigOverrides bool
 }
@@ -210,6 +257,35 @@ func (request *GetInstallationsRequest) ApplyToURL(u *url.URL) {
 	q.Add("group", request.GroupID)
 	q.Add("state", request.State)
 	q.Add("dns_name", request.DNS)
+	for name, value := range map[string]string{
+		"dns_contains": request.DNSContains,
+		"version":      request.Version,
+		"image":        request.Image,
+		"database":     request.Database,
+		"filestore":    request.Filestore,
+		"size":         request.Size,
+		"affinity":     request.Affinity,
+		"cluster":      request.ClusterID,
+		"sort_by":      request.SortBy,
+		"sort_order":   request.SortOrder,
+		"after":        request.After,
+	} {
+		if value != "" {
+			q.Add(name, value)
+		}
+	}
+	if request.CreatedAfter > 0 {
+		q.Add("created_after", strconv.FormatInt(request.CreatedAfter, 10))
+	}
+	if request.CreatedBefore > 0 {
+		q.Add("created_before", strconv.FormatInt(request.CreatedBefore, 10))
+	}
+	for _, annotation := range request.Annotations {
+		q.Add("annotation", annotation)
+	}
+	for _, annotation := range request.AnyAnnotations {
+		q.Add("annotation_any", annotation)
+	}
 	if !request.IncludeGroupConfig {
 		q.Add("include_group_config", "false")
 	}
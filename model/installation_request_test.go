@@ -6,6 +6,7 @@ package model_test
 
 import (
 	"bytes"
+	"net/url"
 	"testing"
 
 	"github.com/mattermost/mattermost-cloud/model"
@@ -99,6 +100,15 @@ func TestCreateInstallationRequestValid(t *testing.T) {
 				Affinity: "solo",
 			},
 		},
+		{
+			"invalid scheduling policy",
+			true,
+			&model.CreateInstallationRequest{
+				OwnerID:          "owner1",
+				DNS:              "domain4321.com",
+				SchedulingPolicy: "random",
+			},
+		},
 		{
 			"invalid database",
 			true,
@@ -203,6 +213,37 @@ func TestCreateInstallationRequestValid(t *testing.T) {
 	})
 }
 
+func TestCreateInstallationRequestApplyGroupDefaults(t *testing.T) {
+	t.Run("group without defaults", func(t *testing.T) {
+		request := &model.CreateInstallationRequest{OwnerID: "owner1", DNS: "domain4321.com"}
+		request.SetDefaults()
+		expected := *request
+
+		request.ApplyGroupDefaults(&model.Group{})
+		assert.Equal(t, &expected, request)
+	})
+
+	t.Run("group with defaults", func(t *testing.T) {
+		request := &model.CreateInstallationRequest{OwnerID: "owner1", DNS: "domain4321.com", License: "license1"}
+		request.SetDefaults()
+
+		request.ApplyGroupDefaults(&model.Group{
+			Size:      "1000users",
+			License:   "license2",
+			Affinity:  model.InstallationAffinityMultiTenant,
+			Database:  model.InstallationDatabaseSingleTenantRDSPostgres,
+			Filestore: model.InstallationFilestoreMultiTenantAwsS3,
+		})
+		assert.Equal(t, "1000users", request.Size)
+		assert.Equal(t, "license2", request.License)
+		assert.Equal(t, model.InstallationAffinityMultiTenant, request.Affinity)
+		assert.Equal(t, model.InstallationDatabaseSingleTenantRDSPostgres, request.Database)
+		assert.Equal(t, model.InstallationFilestoreMultiTenantAwsS3, request.Filestore)
+		assert.NotEmpty(t, request.SingleTenantDatabaseConfig.PrimaryInstanceType)
+		require.NoError(t, request.Validate())
+	})
+}
+
 func TestCreateInstallationRequestFromReader(t *testing.T) {
 	t.Run("empty request", func(t *testing.T) {
 		request, err := model.NewCreateInstallationRequestFromReader(bytes.NewReader([]byte(
@@ -525,3 +566,39 @@ func TestNewPatchInstallationRequestFromReader(t *testing.T) {
 func sToP(s string) *string {
 	return &s
 }
+
+func TestGetInstallationsRequest_ApplyToURL(t *testing.T) {
+	req := &model.GetInstallationsRequest{
+		OwnerID:        "owner",
+		DNSContains:    "example",
+		Version:        "5.39.0",
+		Database:       model.InstallationDatabaseMysqlOperator,
+		ClusterID:      "cluster",
+		CreatedAfter:   1000,
+		Annotations:    []string{"annotation1", "annotation2"},
+		AnyAnnotations: []string{"annotation3"},
+		SortBy:         model.InstallationSortByDNS,
+		SortOrder:      model.SortOrderDesc,
+		After:          "installation",
+		Paging:         model.AllPagesNotDeleted(),
+	}
+
+	u, err := url.Parse("https://provisioner/installations")
+	require.NoError(t, err)
+
+	req.ApplyToURL(u)
+
+	assert.Equal(t, req.OwnerID, u.Query().Get("owner"))
+	assert.Equal(t, req.DNSContains, u.Query().Get("dns_contains"))
+	assert.Equal(t, req.Version, u.Query().Get("version"))
+	assert.Equal(t, req.Database, u.Query().Get("database"))
+	assert.Equal(t, req.ClusterID, u.Query().Get("cluster"))
+	assert.Equal(t, "1000", u.Query().Get("created_after"))
+	assert.Empty(t, u.Query()["created_before"])
+	assert.Empty(t, u.Query()["image"])
+	assert.Equal(t, req.Annotations, u.Query()["annotation"])
+	assert.Equal(t, req.AnyAnnotations, u.Query()["annotation_any"])
+	assert.Equal(t, req.SortBy, u.Query().Get("sort_by"))
+	assert.Equal(t, req.SortOrder, u.Query().Get("sort_order"))
+	assert.Equal(t, req.After, u.Query().Get("after"))
+}
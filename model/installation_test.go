@@ -274,6 +274,38 @@ func TestMergeWithGroup(t *testing.T) {
 		assert.NotEmpty(t, installation.GroupOverrides)
 	})
 
+	t.Run("with overrides, size and license", func(t *testing.T) {
+		installation := &Installation{
+			ID:       NewID(),
+			OwnerID:  "owner",
+			Version:  "iversion",
+			Image:    "iImage",
+			DNS:      "test.example.com",
+			License:  "this_is_my_license",
+			Size:     "100users",
+			Affinity: InstallationAffinityIsolated,
+			GroupID:  sToP("group_id"),
+			State:    InstallationStateStable,
+		}
+
+		group := &Group{
+			ID:       NewID(),
+			Version:  "gversion",
+			Image:    "gImage",
+			Size:     "1000users",
+			License:  "group_license",
+			Affinity: InstallationAffinityMultiTenant,
+		}
+
+		installation.MergeWithGroup(group, true)
+		checkMergeValues(t, installation, group)
+		assert.Equal(t, "1000users", installation.Size)
+		assert.Equal(t, "group_license", installation.License)
+		assert.Equal(t, InstallationAffinityIsolated, installation.Affinity)
+		assert.Equal(t, "100users", installation.GroupOverrides["Installation Size"])
+		assert.Equal(t, "1000users", installation.GroupOverrides["Group Size"])
+	})
+
 	t.Run("without overrides, group sequence matches", func(t *testing.T) {
 		installation := &Installation{
 			ID:            NewID(),
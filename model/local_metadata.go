@@ -0,0 +1,30 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import "encoding/json"
+
+// LocalMetadata is the provisioner metadata stored in a model.Cluster
+// registered with the local provisioner.
+type LocalMetadata struct {
+	KubeconfigPath string
+	Version        string
+}
+
+// NewLocalMetadata creates an instance of LocalMetadata given the raw provisioner metadata.
+func NewLocalMetadata(metadataBytes []byte) (*LocalMetadata, error) {
+	if metadataBytes == nil || string(metadataBytes) == "null" {
+		// TODO: remove "null" check after sqlite is gone.
+		return nil, nil
+	}
+
+	var localMetadata LocalMetadata
+	err := json.Unmarshal(metadataBytes, &localMetadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return &localMetadata, nil
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model_test
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLocalMetadata(t *testing.T) {
+	t.Run("nil payload", func(t *testing.T) {
+		localMetadata, err := model.NewLocalMetadata(nil)
+		require.NoError(t, err)
+		require.Nil(t, localMetadata)
+	})
+
+	t.Run("invalid payload", func(t *testing.T) {
+		_, err := model.NewLocalMetadata([]byte(`{`))
+		require.Error(t, err)
+	})
+
+	t.Run("valid payload", func(t *testing.T) {
+		localMetadata, err := model.NewLocalMetadata([]byte(`{"KubeconfigPath": "/home/dev/.kube/config"}`))
+		require.NoError(t, err)
+		require.Equal(t, "/home/dev/.kube/config", localMetadata.KubeconfigPath)
+	})
+}
@@ -7,6 +7,7 @@ package model
 import (
 	"fmt"
 	"strings"
+	"sync"
 )
 
 const (
@@ -14,10 +15,29 @@ const (
 	ProviderAWS = "aws"
 )
 
+var (
+	supportedProvidersLock sync.RWMutex
+	supportedProviders     = map[string]bool{ProviderAWS: true}
+)
+
+// RegisterProvider adds the given cloud provider to the providers accepted by
+// CheckProvider. It is intended to be called once the cloud provider
+// implementation used by the server is known.
+func RegisterProvider(provider string) {
+	supportedProvidersLock.Lock()
+	defer supportedProvidersLock.Unlock()
+
+	supportedProviders[strings.ToLower(provider)] = true
+}
+
 // CheckProvider normalizes the given provider, returning an error if invalid.
 func CheckProvider(provider string) (string, error) {
 	provider = strings.ToLower(provider)
-	if provider == ProviderAWS {
+
+	supportedProvidersLock.RLock()
+	defer supportedProvidersLock.RUnlock()
+
+	if supportedProviders[provider] {
 		return provider, nil
 	}
 
@@ -40,3 +40,14 @@ func TestCheckProvider(t *testing.T) {
 		})
 	}
 }
+
+func TestRegisterProvider(t *testing.T) {
+	_, err := model.CheckProvider("test-provider")
+	assert.Error(t, err)
+
+	model.RegisterProvider("Test-Provider")
+
+	provider, err := model.CheckProvider("test-provider")
+	assert.NoError(t, err)
+	assert.Equal(t, "test-provider", provider)
+}
@@ -0,0 +1,13 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+const (
+	// ProvisionerKops is the provisioner creating clusters with kops and terraform.
+	ProvisionerKops = "kops"
+	// ProvisionerLocal is the provisioner of already-existing clusters, such
+	// as a local kind cluster, which are accessed through a kubeconfig.
+	ProvisionerLocal = "local"
+)
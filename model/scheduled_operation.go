@@ -0,0 +1,163 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ScheduledOperation requests an installation state change at a given time,
+// for example waking up an installation every morning or deleting it when a
+// trial expires.
+type ScheduledOperation struct {
+	ID             string
+	InstallationID string
+	Type           ScheduledOperationType
+	// Patch is applied to the installation by update operations.
+	Patch *PatchInstallationRequest `json:"Patch,omitempty"`
+	// RunAt is the time in milliseconds at which the operation is due next.
+	RunAt int64
+	// IntervalSeconds is the time between two runs of a recurring operation.
+	// Operations with an interval of 0 run only once.
+	IntervalSeconds int64
+	State           ScheduledOperationState
+	LastRunAt       int64
+	// Error describes why the last run of the operation failed.
+	Error          string `json:"Error,omitempty"`
+	CreateAt       int64
+	DeleteAt       int64
+	LockAcquiredBy *string
+	LockAcquiredAt int64
+}
+
+// ScheduledOperationType is the installation state change requested by a
+// scheduled operation.
+type ScheduledOperationType string
+
+const (
+	// ScheduledOperationTypeWakeUp wakes up a hibernated installation.
+	ScheduledOperationTypeWakeUp ScheduledOperationType = "wake-up"
+	// ScheduledOperationTypeHibernate hibernates an installation.
+	ScheduledOperationTypeHibernate ScheduledOperationType = "hibernate"
+	// ScheduledOperationTypeUpdate applies the operation patch to an installation.
+	ScheduledOperationTypeUpdate ScheduledOperationType = "update"
+	// ScheduledOperationTypeDelete deletes an installation.
+	ScheduledOperationTypeDelete ScheduledOperationType = "delete"
+)
+
+// AllScheduledOperationTypes is a list of all supported scheduled operation types.
+var AllScheduledOperationTypes = []ScheduledOperationType{
+	ScheduledOperationTypeWakeUp,
+	ScheduledOperationTypeHibernate,
+	ScheduledOperationTypeUpdate,
+	ScheduledOperationTypeDelete,
+}
+
+// InstallationState returns the installation state requested by the
+// operation type.
+func (t ScheduledOperationType) InstallationState() string {
+	switch t {
+	case ScheduledOperationTypeWakeUp:
+		return InstallationStateWakeUpRequested
+	case ScheduledOperationTypeHibernate:
+		return InstallationStateHibernationRequested
+	case ScheduledOperationTypeUpdate:
+		return InstallationStateUpdateRequested
+	case ScheduledOperationTypeDelete:
+		return InstallationStateDeletionRequested
+	}
+
+	return ""
+}
+
+// ScheduledOperationState represents the state of a scheduled operation.
+type ScheduledOperationState string
+
+const (
+	// ScheduledOperationStateScheduled is a scheduled operation waiting to be run.
+	ScheduledOperationStateScheduled ScheduledOperationState = "scheduled"
+	// ScheduledOperationStateSucceeded is a one-time scheduled operation that
+	// requested the installation state change.
+	ScheduledOperationStateSucceeded ScheduledOperationState = "succeeded"
+	// ScheduledOperationStateFailed is a one-time scheduled operation that
+	// could not request the installation state change.
+	ScheduledOperationStateFailed ScheduledOperationState = "failed"
+	// ScheduledOperationStateCancelled is a scheduled operation that was
+	// cancelled before it was run.
+	ScheduledOperationStateCancelled ScheduledOperationState = "cancelled"
+)
+
+// ScheduledOperationFilter describes the parameters used to constrain a set of scheduled operations.
+type ScheduledOperationFilter struct {
+	Paging
+	InstallationID string
+	States         []ScheduledOperationState
+}
+
+// IsDeleted returns whether the scheduled operation was marked as deleted or not.
+func (o *ScheduledOperation) IsDeleted() bool {
+	return o.DeleteAt != 0
+}
+
+// IsRecurring returns whether the operation runs more than once.
+func (o *ScheduledOperation) IsRecurring() bool {
+	return o.IntervalSeconds > 0
+}
+
+// Interval returns the time between two runs of a recurring operation.
+func (o *ScheduledOperation) Interval() time.Duration {
+	return time.Duration(o.IntervalSeconds) * time.Second
+}
+
+// IsDue returns whether the operation should be run at the given time in
+// milliseconds.
+func (o *ScheduledOperation) IsDue(now int64) bool {
+	return o.State == ScheduledOperationStateScheduled && o.RunAt <= now
+}
+
+// NextRunAt returns the first time after the given time in milliseconds at
+// which a recurring operation is due again. Runs missed while the operation
+// could not be processed are skipped.
+func (o *ScheduledOperation) NextRunAt(now int64) int64 {
+	interval := o.Interval().Milliseconds()
+	if interval <= 0 {
+		return o.RunAt
+	}
+
+	runAt := o.RunAt
+	for runAt <= now {
+		runAt += interval
+	}
+
+	return runAt
+}
+
+// NewScheduledOperationFromReader will create a ScheduledOperation from an
+// io.Reader with JSON data.
+func NewScheduledOperationFromReader(reader io.Reader) (*ScheduledOperation, error) {
+	var operation ScheduledOperation
+	err := json.NewDecoder(reader).Decode(&operation)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode scheduled operation")
+	}
+
+	return &operation, nil
+}
+
+// NewScheduledOperationsFromReader will create a slice of ScheduledOperation
+// from an io.Reader with JSON data.
+func NewScheduledOperationsFromReader(reader io.Reader) ([]*ScheduledOperation, error) {
+	operations := []*ScheduledOperation{}
+	err := json.NewDecoder(reader).Decode(&operations)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode scheduled operations")
+	}
+
+	return operations, nil
+}
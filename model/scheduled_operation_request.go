@@ -0,0 +1,98 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// MinimumScheduledOperationIntervalSeconds is the shortest allowed interval
+// between two runs of a recurring scheduled operation.
+const MinimumScheduledOperationIntervalSeconds = 60 * 60
+
+// CreateScheduledOperationRequest specifies the parameters for a new
+// scheduled operation.
+type CreateScheduledOperationRequest struct {
+	InstallationID string
+	Type           ScheduledOperationType
+	// Patch is required for update operations.
+	Patch *PatchInstallationRequest `json:"Patch,omitempty"`
+	// RunAt is the time in milliseconds at which the operation is first due.
+	RunAt           int64
+	IntervalSeconds int64
+}
+
+// Validate validates the values of a scheduled operation create request.
+func (request *CreateScheduledOperationRequest) Validate() error {
+	if request.InstallationID == "" {
+		return errors.New("must specify installation")
+	}
+	if request.Type.InstallationState() == "" {
+		return errors.Errorf("unsupported scheduled operation type %q", request.Type)
+	}
+	if request.RunAt <= 0 {
+		return errors.New("must specify the time at which the operation runs")
+	}
+	if request.Type == ScheduledOperationTypeUpdate {
+		if request.Patch == nil {
+			return errors.New("must specify installation patch for update operation")
+		}
+		err := request.Patch.Validate()
+		if err != nil {
+			return errors.Wrap(err, "invalid installation patch")
+		}
+	} else if request.Patch != nil {
+		return errors.New("installation patch can only be specified for update operation")
+	}
+	if request.IntervalSeconds != 0 {
+		if request.Type == ScheduledOperationTypeDelete {
+			return errors.New("delete operation cannot be recurring")
+		}
+		if request.IntervalSeconds < MinimumScheduledOperationIntervalSeconds {
+			return errors.Errorf("interval must be at least %d seconds", MinimumScheduledOperationIntervalSeconds)
+		}
+	}
+
+	return nil
+}
+
+// NewCreateScheduledOperationRequestFromReader will create a
+// CreateScheduledOperationRequest from an io.Reader with JSON data.
+func NewCreateScheduledOperationRequestFromReader(reader io.Reader) (*CreateScheduledOperationRequest, error) {
+	var operationRequest CreateScheduledOperationRequest
+	err := json.NewDecoder(reader).Decode(&operationRequest)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode scheduled operation request")
+	}
+
+	err = operationRequest.Validate()
+	if err != nil {
+		return nil, errors.Wrap(err, "scheduled operation request failed validation")
+	}
+
+	return &operationRequest, nil
+}
+
+// GetScheduledOperationsRequest describes the parameters to request a list of
+// scheduled operations.
+type GetScheduledOperationsRequest struct {
+	Paging
+	InstallationID string
+	State          string
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *GetScheduledOperationsRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	q.Add("installation", request.InstallationID)
+	q.Add("state", request.State)
+	request.Paging.AddToQuery(q)
+
+	u.RawQuery = q.Encode()
+}
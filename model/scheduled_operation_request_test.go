@@ -0,0 +1,97 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCreateScheduledOperationRequestFromReader(t *testing.T) {
+	t.Run("invalid", func(t *testing.T) {
+		request, err := NewCreateScheduledOperationRequestFromReader(bytes.NewReader([]byte(
+			"{test",
+		)))
+		require.Error(t, err)
+		require.Nil(t, request)
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		request, err := NewCreateScheduledOperationRequestFromReader(bytes.NewReader([]byte(
+			`{"InstallationID": "installation", "Type": "wake-up", "RunAt": 1000, "IntervalSeconds": 86400}`,
+		)))
+		require.NoError(t, err)
+		require.Equal(t, &CreateScheduledOperationRequest{
+			InstallationID:  "installation",
+			Type:            ScheduledOperationTypeWakeUp,
+			RunAt:           1000,
+			IntervalSeconds: 86400,
+		}, request)
+	})
+}
+
+func TestCreateScheduledOperationRequestValidate(t *testing.T) {
+	version := "5.39.0"
+	blank := ""
+
+	for _, testCase := range []struct {
+		description string
+		request     *CreateScheduledOperationRequest
+		valid       bool
+	}{
+		{
+			description: "valid",
+			request:     &CreateScheduledOperationRequest{InstallationID: "installation", Type: ScheduledOperationTypeHibernate, RunAt: 1},
+			valid:       true,
+		},
+		{
+			description: "valid update",
+			request:     &CreateScheduledOperationRequest{InstallationID: "installation", Type: ScheduledOperationTypeUpdate, RunAt: 1, Patch: &PatchInstallationRequest{Version: &version}},
+			valid:       true,
+		},
+		{
+			description: "no installation",
+			request:     &CreateScheduledOperationRequest{Type: ScheduledOperationTypeHibernate, RunAt: 1},
+		},
+		{
+			description: "unsupported type",
+			request:     &CreateScheduledOperationRequest{InstallationID: "installation", Type: "unknown", RunAt: 1},
+		},
+		{
+			description: "no run time",
+			request:     &CreateScheduledOperationRequest{InstallationID: "installation", Type: ScheduledOperationTypeHibernate},
+		},
+		{
+			description: "update without patch",
+			request:     &CreateScheduledOperationRequest{InstallationID: "installation", Type: ScheduledOperationTypeUpdate, RunAt: 1},
+		},
+		{
+			description: "update with invalid patch",
+			request:     &CreateScheduledOperationRequest{InstallationID: "installation", Type: ScheduledOperationTypeUpdate, RunAt: 1, Patch: &PatchInstallationRequest{Version: &blank}},
+		},
+		{
+			description: "patch without update",
+			request:     &CreateScheduledOperationRequest{InstallationID: "installation", Type: ScheduledOperationTypeWakeUp, RunAt: 1, Patch: &PatchInstallationRequest{Version: &version}},
+		},
+		{
+			description: "interval too short",
+			request:     &CreateScheduledOperationRequest{InstallationID: "installation", Type: ScheduledOperationTypeWakeUp, RunAt: 1, IntervalSeconds: 60},
+		},
+		{
+			description: "recurring delete",
+			request:     &CreateScheduledOperationRequest{InstallationID: "installation", Type: ScheduledOperationTypeDelete, RunAt: 1, IntervalSeconds: 86400},
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			if testCase.valid {
+				require.NoError(t, testCase.request.Validate())
+			} else {
+				require.Error(t, testCase.request.Validate())
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduledOperationNextRunAt(t *testing.T) {
+	hour := int64(60 * 60 * 1000)
+
+	t.Run("one-time", func(t *testing.T) {
+		operation := &ScheduledOperation{RunAt: 1000}
+		assert.Equal(t, int64(1000), operation.NextRunAt(5000))
+	})
+
+	t.Run("recurring", func(t *testing.T) {
+		operation := &ScheduledOperation{RunAt: 1000, IntervalSeconds: 60 * 60}
+		assert.Equal(t, 1000+hour, operation.NextRunAt(1000))
+	})
+
+	t.Run("skip missed runs", func(t *testing.T) {
+		operation := &ScheduledOperation{RunAt: 1000, IntervalSeconds: 60 * 60}
+		assert.Equal(t, 1000+3*hour, operation.NextRunAt(1000+2*hour+1))
+	})
+}
+
+func TestScheduledOperationTypeInstallationState(t *testing.T) {
+	for _, operationType := range AllScheduledOperationTypes {
+		assert.NotEmpty(t, operationType.InstallationState())
+	}
+	assert.Empty(t, ScheduledOperationType("unknown").InstallationState())
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// SchedulingPolicyFirstFit schedules installations on the first cluster
+	// that can fit them.
+	SchedulingPolicyFirstFit = "first-fit"
+	// SchedulingPolicyBinPacking schedules installations on the most utilized
+	// cluster that can fit them.
+	SchedulingPolicyBinPacking = "bin-packing"
+	// SchedulingPolicySpread schedules installations on the least utilized
+	// cluster.
+	SchedulingPolicySpread = "spread"
+	// SchedulingPolicyAnnotationAffinity schedules installations only on
+	// clusters which do not have annotations missing on the installation.
+	SchedulingPolicyAnnotationAffinity = "annotation-affinity"
+	// SchedulingPolicyOwnerAntiAffinity schedules installations on clusters
+	// with the lowest number of installations of the same owner.
+	SchedulingPolicyOwnerAntiAffinity = "owner-anti-affinity"
+)
+
+// AllSchedulingPolicies is a list of all supported installation scheduling policies.
+var AllSchedulingPolicies = []string{
+	SchedulingPolicyFirstFit,
+	SchedulingPolicyBinPacking,
+	SchedulingPolicySpread,
+	SchedulingPolicyAnnotationAffinity,
+	SchedulingPolicyOwnerAntiAffinity,
+}
+
+// IsSupportedSchedulingPolicy returns true if the given scheduling policy is supported.
+func IsSupportedSchedulingPolicy(policy string) bool {
+	return contains(AllSchedulingPolicies, policy)
+}
+
+// InstallationSchedulingResult describes which cluster an installation would
+// be scheduled on and why.
+type InstallationSchedulingResult struct {
+	InstallationID string
+	Policy         string
+	// ClusterID is the cluster selected for the installation. It is empty if
+	// no cluster can host the installation.
+	ClusterID string
+	// Clusters contains the evaluated clusters ordered by preference with
+	// eligible clusters first.
+	Clusters []*ClusterSchedulingDecision
+}
+
+// ClusterSchedulingDecision describes the evaluation of a single cluster
+// during installation scheduling.
+type ClusterSchedulingDecision struct {
+	ClusterID string
+	Eligible  bool
+	// Reason explains why the cluster is not eligible.
+	Reason string `json:"Reason,omitempty"`
+	// CPUPercent and MemoryPercent are the expected cluster resource usage
+	// after scheduling the installation.
+	CPUPercent           int
+	MemoryPercent        int
+	ClusterInstallations int
+	OwnerInstallations   int
+}
+
+// NewInstallationSchedulingResultFromReader will create an InstallationSchedulingResult
+// from an io.Reader with JSON data.
+func NewInstallationSchedulingResultFromReader(reader io.Reader) (*InstallationSchedulingResult, error) {
+	var result InstallationSchedulingResult
+	err := json.NewDecoder(reader).Decode(&result)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode installation scheduling result")
+	}
+
+	return &result, nil
+}
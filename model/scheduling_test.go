@@ -0,0 +1,33 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model_test
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSupportedSchedulingPolicy(t *testing.T) {
+	var testCases = []struct {
+		policy          string
+		expectSupported bool
+	}{
+		{"", false},
+		{"unknown", false},
+		{model.SchedulingPolicyFirstFit, true},
+		{model.SchedulingPolicyBinPacking, true},
+		{model.SchedulingPolicySpread, true},
+		{model.SchedulingPolicyAnnotationAffinity, true},
+		{model.SchedulingPolicyOwnerAntiAffinity, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.policy, func(t *testing.T) {
+			assert.Equal(t, tc.expectSupported, model.IsSupportedSchedulingPolicy(tc.policy))
+		})
+	}
+}
@@ -5,8 +5,14 @@
 package model
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"io"
+	"strings"
+
+	"github.com/pkg/errors"
 )
 
 const (
@@ -23,15 +29,79 @@ const (
 	TypeInstallationDBRestoration = "installation_db_restoration_operation"
 	// TypeInstallationDBMigration is the string value that represents an installation db migration operation.
 	TypeInstallationDBMigration = "installation_db_migration_operation"
+	// TypeInstallationClone is the string value that represents an installation clone operation.
+	TypeInstallationClone = "installation_clone_operation"
+	// TypeInstallationClusterMigration is the string value that represents an installation cluster migration operation.
+	TypeInstallationClusterMigration = "installation_cluster_migration_operation"
+	// TypeClusterDrain is the string value that represents a cluster drain operation.
+	TypeClusterDrain = "cluster_drain_operation"
+	// TypeInstallationHibernationNotice is the string value that represents a
+	// notice that an idle installation is about to be hibernated.
+	TypeInstallationHibernationNotice = "installation_hibernation_notice"
+	// TypeScheduledOperation is the string value that represents a scheduled operation.
+	TypeScheduledOperation = "scheduled_operation"
+	// TypeGroupRollout is the string value that represents the rollout of a
+	// group configuration.
+	TypeGroupRollout = "group_rollout"
+	// TypeBulkOperation is the string value that represents a bulk installation operation.
+	TypeBulkOperation = "bulk_operation"
+)
+
+// AllWebhookPayloadTypes is a list of all resource types sent in webhook payloads.
+var AllWebhookPayloadTypes = []string{
+	TypeCluster,
+	TypeInstallation,
+	TypeClusterInstallation,
+	TypeInstallationBackup,
+	TypeInstallationDBRestoration,
+	TypeInstallationDBMigration,
+	TypeInstallationClone,
+	TypeInstallationClusterMigration,
+	TypeClusterDrain,
+	TypeInstallationHibernationNotice,
+	TypeScheduledOperation,
+	TypeGroupRollout,
+	TypeBulkOperation,
+}
+
+const (
+	// WebhookSignatureHeader is the HTTP header containing the HMAC-SHA256
+	// signature of the webhook payload computed with the webhook secret.
+	WebhookSignatureHeader = "X-Mattermost-Cloud-Signature"
+	// WebhookDeliveryHeader is the HTTP header containing the ID of the
+	// webhook delivery. It is the same for all attempts of a given delivery.
+	WebhookDeliveryHeader = "X-Mattermost-Cloud-Delivery"
+
+	webhookSignaturePrefix = "sha256="
+	webhookSecretBytes     = 32
 )
 
 // Webhook is
 type Webhook struct {
-	ID       string
-	OwnerID  string
-	URL      string
-	CreateAt int64
-	DeleteAt int64
+	ID      string
+	OwnerID string
+	URL     string
+	// Secret is used to sign webhook payloads. It is only returned once when
+	// the webhook is created.
+	Secret string `json:"Secret,omitempty"`
+	// Subscription limits the payloads sent to the webhook. All payloads are
+	// sent when it is nil.
+	Subscription *WebhookSubscription `json:"Subscription,omitempty"`
+	CreateAt     int64
+	DeleteAt     int64
+}
+
+// WebhookSubscription describes which payloads are sent to a webhook. Empty
+// fields do not constrain the payloads.
+type WebhookSubscription struct {
+	// Types are the resource types of the payloads.
+	Types []string `json:"Types,omitempty"`
+	// States are the new states of the resources.
+	States []string `json:"States,omitempty"`
+	// ResourceIDs are the IDs of the resources.
+	ResourceIDs []string `json:"ResourceIDs,omitempty"`
+	// OwnerID is the owner of the installations the resources belong to.
+	OwnerID string `json:"OwnerID,omitempty"`
 }
 
 // WebhookFilter describes the parameters used to constrain a set of webhooks.
@@ -42,11 +112,13 @@ type WebhookFilter struct {
 
 // WebhookPayload is the payload sent in every webhook.
 type WebhookPayload struct {
-	Timestamp int64             `json:"timestamp"`
-	ID        string            `json:"id"`
-	Type      string            `json:"type"`
-	NewState  string            `json:"new_state"`
-	OldState  string            `json:"old_state"`
+	Timestamp int64  `json:"timestamp"`
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	NewState  string `json:"new_state"`
+	OldState  string `json:"old_state"`
+	// OwnerID is the owner of the installation the resource belongs to, if any.
+	OwnerID   string            `json:"owner_id,omitempty"`
 	ExtraData map[string]string `json:"extra_data,omitempty"`
 }
 
@@ -55,6 +127,88 @@ func (w *Webhook) IsDeleted() bool {
 	return w.DeleteAt != 0
 }
 
+// Validate validates the webhook subscription.
+func (s *WebhookSubscription) Validate() error {
+	for _, subscribedType := range s.Types {
+		if !contains(AllWebhookPayloadTypes, subscribedType) {
+			return errors.Errorf("unsupported resource type %q", subscribedType)
+		}
+	}
+
+	return nil
+}
+
+// Matches returns true if the payload should be sent to webhooks with the
+// subscription.
+func (s *WebhookSubscription) Matches(payload *WebhookPayload) bool {
+	if s == nil {
+		return true
+	}
+	if payload == nil {
+		return false
+	}
+	if len(s.Types) > 0 && !contains(s.Types, payload.Type) {
+		return false
+	}
+	if len(s.States) > 0 && !contains(s.States, payload.NewState) {
+		return false
+	}
+	if len(s.ResourceIDs) > 0 && !contains(s.ResourceIDs, payload.ID) {
+		return false
+	}
+	if s.OwnerID != "" && s.OwnerID != payload.OwnerID {
+		return false
+	}
+
+	return true
+}
+
+// String returns a short human readable description of the subscription.
+func (s *WebhookSubscription) String() string {
+	if s == nil {
+		return "all"
+	}
+
+	var filters []string
+	if len(s.Types) > 0 {
+		filters = append(filters, "types="+strings.Join(s.Types, ","))
+	}
+	if len(s.States) > 0 {
+		filters = append(filters, "states="+strings.Join(s.States, ","))
+	}
+	if len(s.ResourceIDs) > 0 {
+		filters = append(filters, "resources="+strings.Join(s.ResourceIDs, ","))
+	}
+	if s.OwnerID != "" {
+		filters = append(filters, "owner="+s.OwnerID)
+	}
+	if len(filters) == 0 {
+		return "all"
+	}
+
+	return strings.Join(filters, " ")
+}
+
+// NewWebhookSecret generates a new random secret used to sign webhook payloads.
+func NewWebhookSecret() (string, error) {
+	secret, err := newSecret(webhookSecretBytes)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to generate random webhook secret")
+	}
+
+	return secret, nil
+}
+
+// SignWebhookPayload returns the value of the signature header for the given
+// payload. Receivers can verify payloads by computing the same value with the
+// webhook secret.
+func SignWebhookPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+
+	return webhookSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
 // ToJSON returns a JSON string representation of the webhook payload.
 func (p *WebhookPayload) ToJSON() (string, error) {
 	b, err := json.Marshal(p)
@@ -0,0 +1,61 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+)
+
+const (
+	// WebhookDeliveryStatePending is a webhook delivery waiting to be sent or retried.
+	WebhookDeliveryStatePending = "pending"
+	// WebhookDeliveryStateDelivered is a webhook delivery accepted by the receiver.
+	WebhookDeliveryStateDelivered = "delivered"
+	// WebhookDeliveryStateFailed is a webhook delivery that was abandoned.
+	WebhookDeliveryStateFailed = "failed"
+)
+
+// WebhookDelivery represents a webhook payload queued for delivery to a
+// single webhook, along with the outcome of the latest delivery attempt.
+type WebhookDelivery struct {
+	ID             string
+	WebhookID      string
+	Payload        *WebhookPayload
+	State          string
+	Attempts       int64
+	LastAttemptAt  int64
+	NextAttemptAt  int64
+	LastStatusCode int64
+	LastError      string
+	CreateAt       int64
+	LockAcquiredBy *string
+	LockAcquiredAt int64
+}
+
+// WebhookDeliveryFilter describes the parameters used to constrain a set of webhook deliveries.
+type WebhookDeliveryFilter struct {
+	Paging
+	WebhookID string
+	States    []string
+}
+
+// IsPending returns whether the webhook delivery still has to be sent.
+func (d *WebhookDelivery) IsPending() bool {
+	return d.State == WebhookDeliveryStatePending
+}
+
+// WebhookDeliveriesFromReader decodes a json-encoded list of webhook deliveries from the given io.Reader.
+func WebhookDeliveriesFromReader(reader io.Reader) ([]*WebhookDelivery, error) {
+	deliveries := []*WebhookDelivery{}
+	decoder := json.NewDecoder(reader)
+
+	err := decoder.Decode(&deliveries)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
@@ -17,6 +17,11 @@ import (
 type CreateWebhookRequest struct {
 	OwnerID string
 	URL     string
+	// Secret is used to sign webhook payloads. A random secret is generated
+	// when none is provided.
+	Secret string
+	// Subscription limits the payloads sent to the webhook.
+	Subscription *WebhookSubscription
 }
 
 // NewCreateWebhookRequestFromReader will create a CreateWebhookRequest from an io.Reader with JSON data.
@@ -45,6 +50,12 @@ func NewCreateWebhookRequestFromReader(reader io.Reader) (*CreateWebhookRequest,
 	if uri.Host == "" {
 		return nil, errors.New("must specify host")
 	}
+	if createWebhookRequest.Subscription != nil {
+		err = createWebhookRequest.Subscription.Validate()
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid subscription")
+		}
+	}
 
 	return &createWebhookRequest, nil
 }
@@ -63,3 +74,18 @@ func (request *GetWebhooksRequest) ApplyToURL(u *url.URL) {
 
 	u.RawQuery = q.Encode()
 }
+
+// GetWebhookDeliveriesRequest describes the parameters to request a list of webhook deliveries.
+type GetWebhookDeliveriesRequest struct {
+	Paging
+	State string
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *GetWebhookDeliveriesRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	q.Add("state", request.State)
+	request.Paging.AddToQuery(q)
+
+	u.RawQuery = q.Encode()
+}
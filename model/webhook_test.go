@@ -168,3 +168,89 @@ func TestWebhookPayloadFromReader(t *testing.T) {
 		}, payload)
 	})
 }
+
+func TestSignWebhookPayload(t *testing.T) {
+	payload := []byte(`{"id":"id"}`)
+
+	signature := SignWebhookPayload("secret", payload)
+	require.True(t, strings.HasPrefix(signature, "sha256="))
+	require.Equal(t, signature, SignWebhookPayload("secret", payload))
+	require.NotEqual(t, signature, SignWebhookPayload("other", payload))
+	require.NotEqual(t, signature, SignWebhookPayload("secret", []byte(`{"id":"other"}`)))
+
+	secret, err := NewWebhookSecret()
+	require.NoError(t, err)
+	require.Len(t, secret, 64)
+}
+
+func TestWebhookSubscriptionValidate(t *testing.T) {
+	require.NoError(t, (&WebhookSubscription{}).Validate())
+	require.NoError(t, (&WebhookSubscription{Types: []string{TypeInstallation, TypeCluster}}).Validate())
+	require.Error(t, (&WebhookSubscription{Types: []string{TypeInstallation, "unknown"}}).Validate())
+}
+
+func TestWebhookSubscriptionMatches(t *testing.T) {
+	payload := &WebhookPayload{
+		Type:     TypeInstallation,
+		ID:       "installation1",
+		NewState: InstallationStateStable,
+		OwnerID:  "owner1",
+	}
+
+	var testCases = []struct {
+		Description  string
+		Subscription *WebhookSubscription
+		Payload      *WebhookPayload
+		Expected     bool
+	}{
+		{"nil subscription", nil, payload, true},
+		{"empty subscription", &WebhookSubscription{}, payload, true},
+		{"nil payload", &WebhookSubscription{}, nil, false},
+		{"matching type", &WebhookSubscription{Types: []string{TypeCluster, TypeInstallation}}, payload, true},
+		{"other type", &WebhookSubscription{Types: []string{TypeCluster}}, payload, false},
+		{"matching state", &WebhookSubscription{States: []string{InstallationStateStable}}, payload, true},
+		{"other state", &WebhookSubscription{States: []string{InstallationStateDeleted}}, payload, false},
+		{"matching resource", &WebhookSubscription{ResourceIDs: []string{"installation1"}}, payload, true},
+		{"other resource", &WebhookSubscription{ResourceIDs: []string{"installation2"}}, payload, false},
+		{"matching owner", &WebhookSubscription{OwnerID: "owner1"}, payload, true},
+		{"other owner", &WebhookSubscription{OwnerID: "owner2"}, payload, false},
+		{
+			"all matching",
+			&WebhookSubscription{
+				Types:       []string{TypeInstallation},
+				States:      []string{InstallationStateStable},
+				ResourceIDs: []string{"installation1"},
+				OwnerID:     "owner1",
+			},
+			payload,
+			true,
+		},
+		{
+			"one not matching",
+			&WebhookSubscription{
+				Types:       []string{TypeInstallation},
+				States:      []string{InstallationStateStable},
+				ResourceIDs: []string{"installation1"},
+				OwnerID:     "owner2",
+			},
+			payload,
+			false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Description, func(t *testing.T) {
+			require.Equal(t, tc.Expected, tc.Subscription.Matches(tc.Payload))
+		})
+	}
+}
+
+func TestWebhookSubscriptionString(t *testing.T) {
+	var subscription *WebhookSubscription
+	require.Equal(t, "all", subscription.String())
+	require.Equal(t, "all", (&WebhookSubscription{}).String())
+	require.Equal(t, "types=installation,cluster owner=owner1", (&WebhookSubscription{
+		Types:   []string{TypeInstallation, TypeCluster},
+		OwnerID: "owner1",
+	}).String())
+}